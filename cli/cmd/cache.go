@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+)
+
+// CacheCmd represents the scan cache command group
+type CacheCmd struct {
+	Clear CacheClearCmd `cmd:"" help:"Remove every cached scan result"`
+}
+
+// CacheClearCmd represents the command removing cached scan results
+type CacheClearCmd struct {
+	CacheDir string `help:"Directory holding the cached scan results, ~/.aws-taggy/cache when empty"`
+}
+
+// Run implements the logic for clearing the scan cache
+func (c *CacheClearCmd) Run() error {
+	logger := o11y.DefaultLogger()
+
+	dir := c.CacheDir
+	if dir == "" {
+		defaultDir, err := inspector.DefaultScanCacheDir()
+		if err != nil {
+			return err
+		}
+		dir = defaultDir
+	}
+
+	cache, err := inspector.NewScanCache(dir, inspector.DefaultScanCacheTTL)
+	if err != nil {
+		return err
+	}
+
+	removed, err := cache.Clear()
+	if err != nil {
+		return err
+	}
+
+	logger.Info(fmt.Sprintf("🧹 Removed %d cached scan result(s) from %s", removed, cache.Dir()))
+	return nil
+}
+
+// newScanCache returns the scan cache selected by the cache flags of a command, nil when
+// caching is disabled
+func newScanCache(dir string, ttl time.Duration, noCache bool) (*inspector.ScanCache, error) {
+	if dir == "" || noCache {
+		return nil, nil
+	}
+
+	cache, err := inspector.NewScanCache(dir, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scan cache settings: %w", err)
+	}
+	return cache, nil
+}
@@ -2,7 +2,11 @@
 
 // ComplianceCmd represents the compliance command group
 type ComplianceCmd struct {
-	Check CheckCmd `cmd:"" help:"Check AWS resource tag compliance"`
+	Check     CheckCmd     `cmd:"" help:"Check AWS resource tag compliance"`
+	CheckPlan CheckPlanCmd `cmd:"" name:"check-plan" help:"Check the tag compliance of the resources of a Terraform plan or state, without AWS credentials"`
+	Diff      DiffCmd      `cmd:"" help:"Report tag drift between two compliance check outputs"`
+	Baseline  BaselineCmd  `cmd:"" help:"Accept the current violations in a suppressions file, so only new ones fail checks"`
+	Watch     WatchCmd     `cmd:"" help:"Rescan compliance on a timer and show the summary, trend and changes between runs"`
 }
 
 // Run is a no-op method to satisfy the Kong command interface
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/Excoriate/aws-taggy/pkg/runner"
+)
+
+// BaselineCmd represents the command accepting the current violations in a suppressions file
+type BaselineCmd struct {
+	Config  string        `help:"Path to the tag compliance configuration file" required:"true"`
+	Write   string        `help:"Suppressions file to write, for compliance check --suppressions" required:"true" type:"path"`
+	Reason  string        `help:"Reason recorded on every suppression" default:"Accepted when adopting aws-taggy"`
+	Expires string        `help:"Last day (YYYY-MM-DD) the suppressions apply, they never expire when omitted"`
+	Set     []string      `help:"Override a configuration setting, e.g. --set aws.regions.mode=specific (repeatable, lists are comma-separated)" placeholder:"PATH=VALUE" sep:"none"`
+	Timeout time.Duration `help:"Abort the scan after this duration (e.g. 5m), unbounded when 0" default:"0"`
+}
+
+// Run scans the resources and writes one suppression per violation type of every
+// non-compliant resource, so only new violations fail later checks
+func (b *BaselineCmd) Run(ctx context.Context) error {
+	logger := o11y.DefaultLogger()
+
+	if b.Expires != "" {
+		if _, err := time.Parse(compliance.SuppressionDateLayout, b.Expires); err != nil {
+			return fmt.Errorf("invalid --expires date %q, expected YYYY-MM-DD", b.Expires)
+		}
+	}
+
+	overrides, err := configuration.ParseOverrides(b.Set)
+	if err != nil {
+		return err
+	}
+
+	loader := configuration.NewTaggyScanConfigLoader()
+	loader.SetOverrides(overrides)
+
+	cfg, err := loader.LoadConfig(b.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration from file %s: %w", b.Config, err)
+	}
+
+	configValidator, err := configuration.NewContentValidator(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize configuration validator for file %s: %w", b.Config, err)
+	}
+	if err := configValidator.ValidateContent(); err != nil {
+		return fmt.Errorf("configuration validation failed for file %s: %w", b.Config, err)
+	}
+
+	baselineRunner, err := runner.New(cfg, runner.Options{})
+	if err != nil {
+		return err
+	}
+
+	scanCtx, cancel := withTimeout(ctx, b.Timeout)
+	defer cancel()
+	scan, err := baselineRunner.Scan(scanCtx)
+	if err != nil {
+		return err
+	}
+
+	// Every current violation is accepted, existing suppressions are not taken into account
+	validator := compliance.NewTagValidator(cfg)
+
+	var suppressions []compliance.Suppression
+	nonCompliant := 0
+	for _, result := range scan.Results {
+		for _, resource := range result.Resources {
+			// Violations of resources whose tags could not be read are unknown, not accepted
+			if resource.TagFetchError != "" {
+				logger.Warn(fmt.Sprintf("Skipping %s, its tags could not be read: %s", resource.ID, resource.TagFetchError))
+				continue
+			}
+
+			ref := compliance.ResourceRef{ID: resource.ID, ARN: resource.Details.ARN, Type: resource.Type}
+			validationResult := validator.ValidateResource(ref, resource.Tags)
+			if validationResult.IsCompliant {
+				continue
+			}
+			nonCompliant++
+			suppressions = append(suppressions, compliance.BaselineSuppressions(ref, validationResult, b.Reason, b.Expires)...)
+		}
+	}
+
+	if err := compliance.WriteSuppressions(b.Write, suppressions); err != nil {
+		return err
+	}
+
+	logger.Info(fmt.Sprintf("✅ Wrote %d suppressions for %d non-compliant resources to %s", len(suppressions), nonCompliant, b.Write))
+	return nil
+}
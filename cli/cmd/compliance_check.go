@@ -4,58 +4,236 @@
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/Excoriate/aws-taggy/cli/internal/output"
 	"github.com/Excoriate/aws-taggy/cli/internal/tui"
 	"github.com/Excoriate/aws-taggy/pkg/compliance"
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/history"
 	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/Excoriate/aws-taggy/pkg/manifest"
 	"github.com/Excoriate/aws-taggy/pkg/o11y"
-	"github.com/Excoriate/aws-taggy/pkg/taggy"
+	"github.com/Excoriate/aws-taggy/pkg/runner"
+	"github.com/Excoriate/aws-taggy/pkg/version"
 )
 
+// defaultJUnitFile is the file of the JUnit report of --output junit without --junit-file
+const defaultJUnitFile = "junit.xml"
+
 // CheckCmd represents the compliance check command
 type CheckCmd struct {
-	Config     string `help:"Path to the tag compliance configuration file" required:"true"`
-	Output     string `help:"Output format (table|json|yaml)" default:"table" enum:"table,json,yaml,TABLE,JSON,YAML"`
-	Table      bool   `help:"Display detailed information in tables" default:"false"`
-	Detailed   bool   `help:"Show detailed compliance results for each resource" default:"false"`
-	Clipboard  bool   `help:"Copy output to clipboard" default:"false"`
-	OutputFile string `help:"Write detailed JSON output to specified file" type:"path"`
-	Resource   string `help:"Filter compliance check for a specific resource (name or ARN)" optional:"true"`
-}
+	Config       string        `help:"Path to the tag compliance configuration file, required unless --replay is set"`
+	Output       string        `help:"Output format (${output_formats}|junit), junit writes a JUnit XML report to --junit-file and prints the summary" default:"table"`
+	Table        bool          `help:"Display detailed information in tables" default:"false"`
+	Detailed     bool          `help:"Show detailed compliance results for each resource" default:"false"`
+	Clipboard    bool          `help:"Copy output to clipboard" default:"false"`
+	OutputFile   string        `help:"Write detailed JSON output to specified file" type:"path"`
+	Resource     string        `help:"Filter compliance check for a specific resource (name or ARN)" optional:"true"`
+	GroupBy      string        `help:"Group the compliance summary by account, region, type or the value of a tag (tag:<key>)" placeholder:"DIMENSION" optional:"true"`
+	Stream       bool          `help:"Stream one JSON line per resource result to the output file instead of keeping all results in memory (implied by a .ndjson output file)" default:"false"`
+	Timeout      time.Duration `help:"Abort the scan after this duration (e.g. 5m), unbounded when 0" default:"0"`
+	CacheDir     string        `help:"Cache scan results in this directory (e.g. ~/.aws-taggy/cache) and validate cached results on later runs"`
+	CacheTTL     time.Duration `help:"How long cached scan results are reused" default:"30m"`
+	NoCache      bool          `help:"Ignore the scan cache and always scan AWS" default:"false"`
+	MinScore     float64       `help:"Fail when the weighted compliance score (0-100) is below this value" default:"0"`
+	MinLevel     string        `help:"Fail when a resource meets neither this compliance level nor a stricter one (high, standard, medium or low)" placeholder:"LEVEL"`
+	Set          []string      `help:"Override a configuration setting, e.g. --set aws.regions.mode=specific (repeatable, lists are comma-separated)" placeholder:"PATH=VALUE" sep:"none"`
+	FilterTag    []string      `help:"Only check resources whose tags match: key (present), key=value or key!=value (repeatable, all must match)" placeholder:"SELECTOR" sep:"none"`
+	CreatedAfter string        `help:"Only check resources created after this date (YYYY-MM-DD or RFC 3339)" placeholder:"DATE"`
+	MinAge       string        `help:"Only check resources at least this old, in days (e.g. 30d) or as a duration (e.g. 720h)" placeholder:"AGE"`
+	Match        string        `help:"Only check resources whose ID, name or ARN matches this regular expression (e.g. '^prod-')" placeholder:"REGEX"`
+	Exclude      string        `help:"Leave out resources whose ID, name or ARN matches this regular expression" placeholder:"REGEX"`
+	Suppressions string        `help:"YAML file of accepted violations, left out of the compliance status and score (see compliance baseline)" type:"path"`
+	IncludeRaw   bool          `help:"Add the raw AWS API response of every resource to the detailed results, always scanning AWS as raw responses are not cached" default:"false"`
+	StateDB      string        `help:"Record the tags and compliance status of every resource in this state file (e.g. ~/.aws-taggy/state.db), see history show"`
+	Incremental  bool          `help:"Reuse the tags recorded in --state-db for resources unchanged since the last run, and report the resources no longer discovered as deleted" default:"false"`
+	Sort         string        `help:"Order the resources of the --table and --detailed output by violations, id, type or region, scan order when unset" placeholder:"KEY" optional:"true"`
+	Desc         bool          `help:"Sort in descending order, e.g. the most violations first with --sort violations" default:"false"`
+
+	OnlyNoncompliant bool `help:"Leave compliant resources out of the --table and --detailed output, the summary still counts them" default:"false"`
+
+	ShowCoverage bool `help:"Print the share of resources of every type carrying each required tag key, which the JSON and YAML outputs always hold under summary.coverage" default:"false"`
+
+	Interactive bool `help:"Browse the results in an interactive dashboard after the scan, printing the summary instead when not run in a terminal" default:"false"`
+
+	JUnitFile string `help:"Write the results as a JUnit XML report to this file, for CI test reporters (default with --output junit: junit.xml)" type:"path" name:"junit-file"`
+
+	ValidationWorkers int `help:"Number of goroutines validating the tags of the scanned resources, one per CPU when 0" default:"0" placeholder:"N"`
+
+	Concurrency int `help:"Cap the concurrent AWS operations and the workers of every service to N for this run, overriding max_concurrency and workers of the configuration; the scan still backs off from AWS throttling below it" default:"0" placeholder:"N"`
+
+	RuleExamples int `help:"Number of resources failing each rule listed as examples in the rule results, none when 0" default:"5" placeholder:"N"`
+
+	Sample     string `help:"Only validate a random sample of the resources discovered for every service, a share (e.g. 10%) or a number of resources (e.g. 500), the summary adding estimates scaled to every discovered resource" placeholder:"SIZE"`
+	SampleSeed int64  `help:"Seed of the --sample selection, the same seed sampling the same resources out of the same discovery, random when 0" default:"0" placeholder:"SEED"`
+
+	ExcludeUnknownAge bool `help:"Leave out resources whose creation time is unknown, which --created-after and --min-age keep otherwise" default:"false"`
+
+	TreatUnreadableAsNoncompliant bool `help:"Report resources whose tags could not be read, e.g. for lack of permissions, as non-compliant untagged resources instead of unknown" default:"false"`
+
+	WriteFixes string `help:"Write the tag changes fixing the auto-fixable violations, such as a value breaking a case rule, to this JSON file, one entry per resource ARN with the tags to set and remove, to review and apply with your own tooling" type:"path" placeholder:"FILE"`
+
+	BadgeFile string `help:"Write the compliance score as a shields.io endpoint badge to this JSON file, colored by reporting.badge_thresholds" type:"path" placeholder:"FILE"`
+
+	Redact []string `help:"Mask the values of this tag key in every output, keeping the key visible (repeatable, added to reporting.redact_tags of the configuration)" placeholder:"KEY" sep:"none"`
+
+	StrictScan bool `help:"Fail when any service cannot be scanned, e.g. for lack of permissions, instead of only when every service fails" default:"false"`
+
+	FailOnWarnings bool `help:"Fail when any resource has a warning, such as a deprecated allowed value, which otherwise leaves its compliance untouched" default:"false"`
+
+	DryRunEstimate bool `help:"Only discover the resources, printing an estimate of the resources and AWS API calls of the check per service and region, without reading or validating tags" default:"false"`
+
+	Export         string `help:"Index a document per resource result into a search cluster, keyed by ARN so runs replace their previous documents (opensearch, also for Elasticsearch)" placeholder:"TARGET"`
+	ExportURL      string `help:"URL of the OpenSearch or Elasticsearch cluster of --export" name:"export-url" placeholder:"URL"`
+	ExportIndex    string `help:"Index --export writes the resource results to" default:"aws-taggy"`
+	ExportUsername string `help:"Username authenticating --export with basic auth"`
+	ExportPassword string `help:"Password authenticating --export with basic auth" env:"OPENSEARCH_PASSWORD"`
+	ExportSigV4    bool   `help:"Sign the --export requests with the AWS credentials, for Amazon OpenSearch Service" name:"export-sigv4" default:"false"`
+	ExportRegion   string `help:"Region of the Amazon OpenSearch Service domain of --export-sigv4, AWS_REGION when unset"`
+	ExportService  string `help:"Signing name of --export-sigv4: es for domains, aoss for serverless collections" default:"es" enum:"es,aoss"`
 
-// DetailedComplianceResult represents a detailed view of compliance results
-type DetailedComplianceResult struct {
-	Summary         output.ComplianceSummary      `json:"summary"`
-	ResourceResults []*output.ComplianceResult    `json:"resource_results"`
-	ValidationRules map[string]*output.RuleResult `json:"validation_rules"`
+	ManifestFile string `help:"Also write the run manifest, which the JSON and YAML outputs embed under manifest, to this JSON file, never overwriting an existing one" type:"path" placeholder:"FILE"`
+	Replay       string `help:"Re-run the check with the effective configuration recorded in this run manifest, a --manifest-file or the JSON output of an earlier run, instead of --config" type:"existingfile" placeholder:"MANIFEST"`
 }
 
 // Run validates the configuration file and performs compliance checks
-func (c *CheckCmd) Run() error {
+func (c *CheckCmd) Run(ctx context.Context) error {
 	logger := o11y.DefaultLogger()
-	logger.Info(fmt.Sprintf("🔍 Checking compliance configuration file: %s", c.Config))
 
-	// Initialize configuration loader and validator
-	loader := configuration.NewTaggyScanConfigLoader()
+	if (c.Config == "") == (c.Replay == "") {
+		return fmt.Errorf("exactly one of --config or --replay must be set")
+	}
+	if c.Replay != "" && len(c.Set) > 0 {
+		return fmt.Errorf("--replay re-runs the recorded effective configuration, which cannot be combined with --set")
+	}
+	source := c.Config
+	if c.Replay != "" {
+		source = c.Replay
+	}
+	logger.Info(fmt.Sprintf("🔍 Checking compliance configuration file: %s", source))
+
+	if c.ManifestFile != "" {
+		if _, err := os.Stat(c.ManifestFile); err == nil {
+			return fmt.Errorf("run manifest %s already exists, manifests are never overwritten", c.ManifestFile)
+		}
+	}
+
+	if c.GroupBy != "" {
+		if err := runner.ValidateGroupBy(c.GroupBy); err != nil {
+			return err
+		}
+	}
+
+	if formatter, err := c.formatter(); err != nil {
+		return err
+	} else if c.Interactive && formatter.IsStructured() {
+		return fmt.Errorf("--interactive cannot be combined with --output %s", c.Output)
+	}
+
+	if c.Sort != "" {
+		if err := output.ValidateSortKey(c.Sort); err != nil {
+			return err
+		}
+	} else if c.Desc {
+		return fmt.Errorf("--desc requires --sort")
+	}
+
+	if c.Incremental && c.StateDB == "" {
+		return fmt.Errorf("--incremental requires --state-db, which holds the resources of the previous run")
+	}
+
+	if err := validateConcurrency(c.Concurrency); err != nil {
+		return err
+	}
 
-	// Load configuration
-	cfg, err := loader.LoadConfig(c.Config)
+	sample, err := c.sample()
 	if err != nil {
-		return fmt.Errorf("failed to load configuration from file %s: %w. Please check the configuration file path and its contents", c.Config, err)
+		return err
+	}
+
+	if err := validateThresholds(c.MinScore, c.MinLevel); err != nil {
+		return err
+	}
+
+	if err := c.validateExport(); err != nil {
+		return err
+	}
+
+	if c.streaming() {
+		if c.junitFile() != "" {
+			return fmt.Errorf("streaming output cannot be combined with a JUnit report, which needs every result")
+		}
+		if c.Export != "" {
+			return fmt.Errorf("streaming output cannot be combined with --export, which needs every result")
+		}
+		if c.OutputFile == "" {
+			return fmt.Errorf("--stream requires --output-file to write the resource results to")
+		}
+		if c.Table || c.Detailed || c.Clipboard || c.Interactive {
+			return fmt.Errorf("streaming output cannot be combined with --table, --detailed, --clipboard or --interactive")
+		}
+	}
+
+	overrides, err := configuration.ParseOverrides(c.Set)
+	if err != nil {
+		return err
+	}
+
+	tagSelectors, err := inspector.ParseTagSelectors(c.FilterTag)
+	if err != nil {
+		return err
+	}
+
+	ageFilter, err := parseAgeFilter(c.CreatedAfter, c.MinAge, c.ExcludeUnknownAge)
+	if err != nil {
+		return err
+	}
+
+	nameFilter, err := parseNameFilter(c.Match, c.Exclude)
+	if err != nil {
+		return err
+	}
+
+	var suppressions *compliance.Suppressions
+	if c.Suppressions != "" {
+		suppressions, err = compliance.LoadSuppressions(c.Suppressions)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Load configuration, starting the manifest of the run
+	cfg, runManifest, err := c.loadConfig(overrides)
+	if err != nil {
+		return err
 	}
 
 	// Initialize config validator
 	configValidator, err := configuration.NewContentValidator(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to initialize configuration validator for file %s: %w. Ensure the configuration is valid and follows the expected schema", c.Config, err)
+		return fmt.Errorf("failed to initialize configuration validator for file %s: %w. Ensure the configuration is valid and follows the expected schema", source, err)
 	}
 
 	// Perform configuration validation
 	if err := configValidator.ValidateContent(); err != nil {
-		return fmt.Errorf("configuration validation failed for file %s: %w. Review the configuration and ensure all required fields are correctly specified", c.Config, err)
+		return fmt.Errorf("configuration validation failed for file %s: %w. Review the configuration and ensure all required fields are correctly specified", source, err)
+	}
+
+	if _, defined := cfg.ComplianceLevels[c.MinLevel]; c.MinLevel != "" && !defined {
+		return fmt.Errorf("--min-level %s is not defined in the compliance_levels of configuration file %s", c.MinLevel, source)
+	}
+
+	// Values of redacted tags are masked in the outputs only, compliance is evaluated
+	// against the real values
+	redactor := output.NewRedactor(append(slices.Clone(cfg.Reporting.RedactTags), c.Redact...))
+	if key, ok := strings.CutPrefix(c.GroupBy, runner.GroupByTagPrefix); ok && redactor.IsRedacted(key) {
+		return fmt.Errorf("--group-by %s would reveal the values of redacted tag %s", c.GroupBy, key)
 	}
 
 	// Print configuration validation success
@@ -85,191 +263,127 @@ func (c *CheckCmd) Run() error {
 
 	output.PrintPlannedChecks(plannedChecks)
 
-	// Initialize taggy client
-	client, err := taggy.New(c.Config)
+	// Raw responses are not cached, results including them always come from AWS
+	cache, err := newScanCache(c.CacheDir, c.CacheTTL, c.NoCache || c.IncludeRaw)
 	if err != nil {
-		return fmt.Errorf("failed to initialize taggy client with configuration %s: %w. Check the configuration and ensure all required parameters are set", c.Config, err)
+		return err
 	}
 
-	// Initialize inspector manager
-	inspectorMgr, err := inspector.NewInspectorManagerFromConfig(*client.Config())
-	if err != nil {
-		return fmt.Errorf("failed to create scanner manager from configuration: %w. Verify the AWS configuration and region settings", err)
+	var previous *inspector.PreviousScan
+	if c.Incremental {
+		previous, err = loadPreviousScan(c.StateDB)
+		if err != nil {
+			return err
+		}
 	}
 
-	// Scan resources
-	logger.Info("🔍 Scanning AWS resources...")
-	ctx := context.Background()
-	if err := inspectorMgr.Inspect(ctx); err != nil {
-		return fmt.Errorf("failed to scan AWS resources: %w. Check AWS credentials, permissions, and network connectivity", err)
+	// The runner lists runner.DefaultRuleExamples when the option is 0, none when negative
+	ruleExamples := c.RuleExamples
+	if ruleExamples <= 0 {
+		ruleExamples = -1
 	}
 
-	// Get scan results
-	inspectResults := inspectorMgr.GetResults()
-
-	// Filter resources if Resource flag is provided
-	if c.Resource != "" {
-		logger.Info(fmt.Sprintf("🔍 Filtering resources matching: %s", c.Resource))
-		filteredResults := make(map[string]*inspector.InspectResult)
-
-		for resourceType, result := range inspectResults {
-			filteredResources := make([]inspector.ResourceMetadata, 0)
-			for _, resource := range result.Resources {
-				// Check if resource matches by ID or ARN
-				if resource.ID == c.Resource ||
-					resource.Details.ARN == c.Resource ||
-					resource.Details.Name == c.Resource {
-					filteredResources = append(filteredResources, resource)
-				}
-			}
+	complianceRunner, err := runner.New(cfg, runner.Options{
+		Cache:        cache,
+		Resource:     c.Resource,
+		TagSelectors: tagSelectors,
+		AgeFilter:    ageFilter,
+		NameFilter:   nameFilter,
+		Suppressions: suppressions,
+		GroupBy:      c.GroupBy,
+		IncludeRaw:   c.IncludeRaw,
+		Previous:     previous,
 
-			if len(filteredResources) > 0 {
-				filteredResult := &inspector.InspectResult{
-					Resources:      filteredResources,
-					StartTime:      result.StartTime,
-					EndTime:        result.EndTime,
-					Duration:       result.Duration,
-					Region:         result.Region,
-					TotalResources: len(filteredResources),
-					Errors:         result.Errors,
-				}
-				filteredResults[resourceType] = filteredResult
-			}
-		}
+		TreatUnreadableAsNonCompliant: c.TreatUnreadableAsNoncompliant,
+		ValidationWorkers:             c.ValidationWorkers,
+		Concurrency:                   c.Concurrency,
+		StrictScan:                    c.StrictScan,
+		RuleExamples:                  ruleExamples,
+		Sample:                        sample,
+	})
+	if err != nil {
+		return err
+	}
 
-		// If no resources match the filter, return an error
-		if len(filteredResults) == 0 {
-			return fmt.Errorf("no resources found matching the resource filter: %s", c.Resource)
-		}
+	startedAt := time.Now()
+	runManifest.StartedAt = startedAt.UTC()
+	scanCtx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
 
-		// Safely get the number of filtered resources
-		var totalFilteredResources int
-		for _, result := range filteredResults {
-			totalFilteredResources += len(result.Resources)
+	if c.DryRunEstimate {
+		estimates, estimateErrors, err := complianceRunner.Estimate(scanCtx)
+		if err != nil {
+			return err
 		}
-		logger.Info(fmt.Sprintf("✅ Found %d resources matching the filter", totalFilteredResources))
-
-		// Update inspectResults with filtered results
-		inspectResults = filteredResults
+		return printScanEstimate(newScanEstimateReport(estimates, estimateErrors), c.outputFormat())
 	}
 
-	// Create compliance validator
-	complianceValidator := compliance.NewTagValidator(cfg)
-
-	// Validate tags and collect results
-	var complianceResults []*output.ComplianceResult
-	ruleResults := make(map[string]*output.RuleResult)
-
-	// Initialize rule results
-	ruleResults["required_tags"] = &output.RuleResult{
-		Name:        "Required Tags",
-		Description: "Validates that all required tags are present",
-		Passed:      true,
-	}
-	ruleResults["tag_format"] = &output.RuleResult{
-		Name:        "Tag Value Format",
-		Description: "Ensures tag values match specified formats and patterns",
-		Passed:      true,
-	}
-	ruleResults["allowed_values"] = &output.RuleResult{
-		Name:        "Allowed Values",
-		Description: "Verifies tag values are within allowed sets",
-		Passed:      true,
+	// Results are validated and written as each inspector completes when streaming, keeping
+	// memory usage flat
+	if c.streaming() {
+		return c.streamResults(scanCtx, complianceRunner, redactor, cfg.Reporting.Badge(), runManifest)
 	}
-	ruleResults["case_sensitivity"] = &output.RuleResult{
-		Name:        "Case Sensitivity",
-		Description: "Checks if tag keys and values follow case requirements",
-		Passed:      true,
+
+	scan, err := complianceRunner.Scan(scanCtx)
+	if err != nil {
+		return err
 	}
 
-	for _, result := range inspectResults {
-		for _, resource := range result.Resources {
-			validationResult := complianceValidator.ValidateTags(resource.Tags)
+	report, err := complianceRunner.Report(ctx, scan)
+	if err != nil {
+		return err
+	}
+	complianceResults := report.ResourceResults
+	finalSummary := report.Summary
 
-			// Convert compliance.ComplianceResult to output.ComplianceResult
-			outputResult := &output.ComplianceResult{
-				IsCompliant:     validationResult.IsCompliant,
-				ResourceTags:    validationResult.ResourceTags,
-				ComplianceLevel: string(validationResult.ComplianceLevel),
-				ResourceID:      resource.ID,
-				ResourceType:    resource.Type,
-			}
+	completeManifest(runManifest, scan)
+	report.Manifest = runManifest
+	if err := c.writeManifest(runManifest, logger); err != nil {
+		return err
+	}
 
-			// Convert violations and update rule results
-			for _, v := range validationResult.Violations {
-				outputResult.Violations = append(outputResult.Violations, output.Violation{
-					Type:    string(v.Type),
-					Message: v.Message,
-				})
-
-				// Update rule results based on violation types
-				switch v.Type {
-				case "missing_required_tag":
-					ruleResults["required_tags"].Passed = false
-					ruleResults["required_tags"].Failures++
-				case "invalid_format":
-					ruleResults["tag_format"].Passed = false
-					ruleResults["tag_format"].Failures++
-				case "invalid_value":
-					ruleResults["allowed_values"].Passed = false
-					ruleResults["allowed_values"].Failures++
-				case "case_mismatch":
-					ruleResults["case_sensitivity"].Passed = false
-					ruleResults["case_sensitivity"].Failures++
-				}
-			}
+	// The fix plan and the tag history keep the real values, every other output is redacted
+	redactedReport := redactor.Report(report)
 
-			complianceResults = append(complianceResults, outputResult)
+	if junitFile := c.junitFile(); junitFile != "" {
+		if err := writeJUnitReport(junitFile, redactedReport, startedAt, time.Since(startedAt)); err != nil {
+			return err
 		}
+		logger.Info(fmt.Sprintf("✅ JUnit report written to %s", junitFile))
 	}
 
-	// Convert output results back to compliance results for summary generation
-	var internalResults []*compliance.ComplianceResult
-	for _, result := range complianceResults {
-		internalResult := &compliance.ComplianceResult{
-			IsCompliant:     result.IsCompliant,
-			ResourceTags:    result.ResourceTags,
-			ComplianceLevel: compliance.ComplianceLevel(result.ComplianceLevel),
-		}
-
-		// Convert violations
-		for _, v := range result.Violations {
-			internalResult.Violations = append(internalResult.Violations, compliance.Violation{
-				Type:    compliance.ViolationType(v.Type),
-				Message: v.Message,
-			})
+	if c.Export != "" {
+		if err := c.exportResults(ctx, redactedReport.ResourceResults, startedAt, logger); err != nil {
+			return err
 		}
-
-		internalResults = append(internalResults, internalResult)
 	}
 
-	// Generate compliance summary
-	summary := compliance.GenerateSummary(internalResults)
-
-	// Create final summary with rule results
-	finalSummary := output.ComplianceSummary{
-		TotalResources:        summary.TotalResources,
-		CompliantResources:    summary.CompliantResources,
-		NonCompliantResources: summary.NonCompliantResources,
-		GlobalViolations:      make(map[string]int),
-		RuleResults:           ruleResults,
+	if c.WriteFixes != "" {
+		if err := writeFixPlan(c.WriteFixes, runner.FixPlan(complianceResults), logger); err != nil {
+			return err
+		}
 	}
 
-	// Convert global violations
-	for vType, count := range summary.GlobalViolations {
-		finalSummary.GlobalViolations[string(vType)] = count
+	if c.BadgeFile != "" {
+		if err := writeBadge(c.BadgeFile, finalSummary, cfg.Reporting.Badge(), logger); err != nil {
+			return err
+		}
 	}
 
-	// Create detailed compliance result
-	detailedResult := &DetailedComplianceResult{
-		ResourceResults: complianceResults,
-		ValidationRules: ruleResults,
-		Summary:         finalSummary,
+	if c.StateDB != "" {
+		snapshots := make([]history.Snapshot, 0, len(complianceResults))
+		for _, result := range complianceResults {
+			if snapshot, ok := complianceSnapshot(result); ok {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+		snapshots = append(snapshots, deletionSnapshots(scan.Deleted)...)
+		recordHistory(c.StateDB, snapshots, logger)
 	}
 
 	// Handle JSON output to file if specified
 	if c.OutputFile != "" {
-		jsonData, err := json.MarshalIndent(detailedResult, "", "  ")
+		jsonData, err := json.MarshalIndent(redactedReport, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to marshal JSON data: %w", err)
 		}
@@ -281,34 +395,81 @@ func (c *CheckCmd) Run() error {
 
 	// Handle clipboard if requested
 	if c.Clipboard {
-		if err := output.WriteToClipboard(detailedResult); err != nil {
+		if err := output.WriteToClipboard(redactedReport); err != nil {
 			return fmt.Errorf("failed to copy to clipboard: %w", err)
 		}
-		fmt.Println("✅ Compliance check result copied to clipboard!")
-		return nil
+		logger.Info("✅ Compliance check result copied to clipboard!")
+		return c.checkThresholds(finalSummary)
 	}
 
 	// Create output formatter
-	formatter := output.NewFormatter(c.Output)
+	formatter, err := c.formatter()
+	if err != nil {
+		return err
+	}
 
 	if formatter.IsStructured() {
-		return formatter.Output(detailedResult)
+		if err := formatter.Output(redactedReport); err != nil {
+			return err
+		}
+		return c.checkThresholds(finalSummary)
+	}
+
+	// Structured outputs hold every result, the table and detailed outputs only the selected ones
+	listedResults := c.selection().Apply(redactedReport.ResourceResults)
+
+	if c.Interactive {
+		if tui.IsInteractiveTerminal() {
+			if err := runComplianceDashboard(source, listedResults); err != nil {
+				return err
+			}
+			return c.checkThresholds(finalSummary)
+		}
+		logger.Warn("--interactive requires a terminal, printing the results instead")
 	}
 
 	// If table view is requested
 	if c.Table {
-		return renderDetailedTable(complianceResults, finalSummary)
+		if err := renderDetailedTable(listedResults, finalSummary); err != nil {
+			return err
+		}
+		if err := renderRuleTable(finalSummary); err != nil {
+			return err
+		}
+		if len(finalSummary.Groups) > 0 {
+			if err := renderGroupTable(finalSummary); err != nil {
+				return err
+			}
+		}
+		if c.ShowCoverage {
+			if err := renderCoverageTable(finalSummary.Coverage); err != nil {
+				return err
+			}
+		}
+		if len(redactedReport.Errors) > 0 {
+			if err := renderServiceErrors(redactedReport.Errors); err != nil {
+				return err
+			}
+		}
+		return c.checkThresholds(finalSummary)
 	}
 
 	// Print the compliance summary
 	output.PrintComplianceSummary(finalSummary)
+	if c.ShowCoverage {
+		if err := renderCoverageTable(finalSummary.Coverage); err != nil {
+			return err
+		}
+	}
 
 	// If detailed output is requested, print resource-specific results
 	if c.Detailed {
 		fmt.Printf("\n🔍 Detailed Resource Results:\n\n")
-		for _, result := range complianceResults {
+		for _, result := range listedResults {
 			status := "✅"
-			if !result.IsCompliant {
+			if result.IsUnknown {
+				status = "❔"
+			} else if !result.IsCompliant {
 				status = "❌"
 			}
 			fmt.Printf("%s Resource: %s (%s)\n", status, result.ResourceID, result.ResourceType)
@@ -319,16 +480,349 @@ func (c *CheckCmd) Run() error {
 			if !result.IsCompliant {
 				fmt.Printf("   Violations:\n")
 				for _, v := range result.Violations {
-					fmt.Printf("      • %s: %s\n", v.Type, v.Message)
+					fmt.Printf("      • [%s] %s: %s\n", v.Severity, v.Type, v.Describe())
+					if v.Note != "" {
+						fmt.Printf("        Note: %s\n", v.Note)
+					}
+					if v.DocURL != "" {
+						fmt.Printf("        Docs: %s\n", v.DocURL)
+					}
+				}
+			}
+			if len(result.Warnings) > 0 {
+				fmt.Printf("   Warnings:\n")
+				for _, v := range result.Warnings {
+					fmt.Printf("      • [%s] %s: %s\n", v.Severity, v.Type, v.Message)
+				}
+			}
+			if len(result.SuppressedViolations) > 0 {
+				fmt.Printf("   Suppressed Violations:\n")
+				for _, v := range result.SuppressedViolations {
+					fmt.Printf("      • %s: %s (%s)\n", v.Type, v.Message, v.Note)
 				}
 			}
+			fmt.Printf("   Score: %.1f\n", result.Score)
 			fmt.Printf("\n")
 		}
 	}
 
+	return c.checkThresholds(finalSummary)
+}
+
+// checkThresholds fails the check when the compliance score is below --min-score, resources
+// are below --min-level or have warnings with --fail-on-warnings, and with ExitCodeTruncated
+// when a scan limit cut the results short
+func (c *CheckCmd) checkThresholds(summary output.ComplianceSummary) error {
+	if err := thresholdsError(summary, c.MinScore, c.MinLevel, c.FailOnWarnings); err != nil {
+		return err
+	}
+	return truncatedError(summary.TruncatedResults)
+}
+
+// validateThresholds checks the values of the --min-score and --min-level flags
+func validateThresholds(minScore float64, minLevel string) error {
+	if minScore < 0 || minScore > compliance.MaxComplianceScore {
+		return fmt.Errorf("--min-score must be between 0 and %.0f, got %g", compliance.MaxComplianceScore, minScore)
+	}
+	if minLevel != "" && !slices.Contains(compliance.ComplianceLevels(), compliance.ComplianceLevel(minLevel)) {
+		return fmt.Errorf("--min-level must be one of high, standard, medium or low, got %s", minLevel)
+	}
+	return nil
+}
+
+// thresholdsError returns the error failing a check whose compliance score is below
+// minScore, whose resources are below minLevel or, with failOnWarnings, have warnings
+func thresholdsError(summary output.ComplianceSummary, minScore float64, minLevel string, failOnWarnings bool) error {
+	if summary.ComplianceScore < minScore {
+		return fmt.Errorf("compliance score %.1f is below the minimum score %.1f", summary.ComplianceScore, minScore)
+	}
+	if minLevel != "" {
+		if below := summary.BelowComplianceLevel(compliance.ComplianceLevel(minLevel)); below > 0 {
+			return fmt.Errorf("%d resource(s) are below the minimum compliance level %s", below, minLevel)
+		}
+	}
+	if failOnWarnings && summary.Warnings > 0 {
+		return fmt.Errorf("%d warning(s) found and --fail-on-warnings is set", summary.Warnings)
+	}
+	return nil
+}
+
+// streamResults scans the resources and writes the result of every resource to the output
+// file as a JSON line as soon as its inspector completes, printing the summary built from
+// the streamed counters at the end
+func (c *CheckCmd) streamResults(ctx context.Context, complianceRunner *runner.Runner, redactor *output.Redactor, badgeThresholds configuration.BadgeThresholds, runManifest *manifest.Manifest) error {
+	logger := o11y.DefaultLogger()
+
+	file, err := os.Create(c.OutputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file %s: %w", c.OutputFile, err)
+	}
+	defer file.Close()
+
+	// Snapshots are small next to the results, they are kept to be recorded in one write
+	var snapshots []history.Snapshot
+	var fixes []compliance.FixPlanEntry
+	stream := output.NewResultStream(file)
+	scan, finalSummary, err := complianceRunner.StreamScan(ctx, func(result *output.ComplianceResult) error {
+		if c.StateDB != "" {
+			if snapshot, ok := complianceSnapshot(result); ok {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+		if c.WriteFixes != "" {
+			if entry, ok := result.FixPlanEntry(); ok {
+				fixes = append(fixes, entry)
+			}
+		}
+		return stream.Write(redactor.Result(result))
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Flush(); err != nil {
+		return fmt.Errorf("failed to write results to %s: %w", c.OutputFile, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close output file %s: %w", c.OutputFile, err)
+	}
+	logger.Info(fmt.Sprintf("✅ Compliance results streamed to %s", c.OutputFile))
+
+	completeManifest(runManifest, scan)
+	if err := c.writeManifest(runManifest, logger); err != nil {
+		return err
+	}
+
+	if c.WriteFixes != "" {
+		if err := writeFixPlan(c.WriteFixes, fixes, logger); err != nil {
+			return err
+		}
+	}
+
+	if c.BadgeFile != "" {
+		if err := writeBadge(c.BadgeFile, finalSummary, badgeThresholds, logger); err != nil {
+			return err
+		}
+	}
+
+	if c.StateDB != "" {
+		snapshots = append(snapshots, deletionSnapshots(scan.Deleted)...)
+		recordHistory(c.StateDB, snapshots, logger)
+	}
+
+	formatter, err := c.formatter()
+	if err != nil {
+		return err
+	}
+	if formatter.IsStructured() {
+		if err := formatter.Output(finalSummary); err != nil {
+			return err
+		}
+		return c.checkThresholds(finalSummary)
+	}
+
+	output.PrintComplianceSummary(finalSummary)
+	if c.ShowCoverage {
+		if err := renderCoverageTable(finalSummary.Coverage); err != nil {
+			return err
+		}
+	}
+	return c.checkThresholds(finalSummary)
+}
+
+// loadConfig loads the configuration of the check, the file of --config with the overrides
+// applied or the effective configuration recorded in the manifest of --replay, and returns
+// it with the manifest of the run, completed once the scan is done
+func (c *CheckCmd) loadConfig(overrides []configuration.Override) (*configuration.TaggyScanConfig, *manifest.Manifest, error) {
+	runManifest := &manifest.Manifest{
+		Tool: version.Get(),
+		Args: manifest.MaskArgs(os.Args[1:], "--export-password"),
+	}
+
+	loader := configuration.NewTaggyScanConfigLoader()
+	var cfg *configuration.TaggyScanConfig
+	if c.Replay != "" {
+		replayed, err := manifest.Load(c.Replay)
+		if err != nil {
+			return nil, nil, err
+		}
+		if replayed.Tool.Version != runManifest.Tool.Version {
+			o11y.DefaultLogger().Warn(fmt.Sprintf("Run manifest %s was recorded by aws-taggy %s, replaying it with %s",
+				c.Replay, replayed.Tool.Version, runManifest.Tool.Version))
+		}
+
+		cfg, err = loader.LoadEffectiveConfig(replayed.Config)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load the effective configuration of run manifest %s: %w", c.Replay, err)
+		}
+		runManifest.ConfigFile = replayed.ConfigFile
+		runManifest.ConfigSHA256 = replayed.ConfigSHA256
+		runManifest.ReplayOf = c.Replay
+	} else {
+		loader.SetOverrides(overrides)
+
+		var err error
+		cfg, err = loader.LoadConfig(c.Config)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load configuration from file %s: %w. Please check the configuration file path and its contents", c.Config, err)
+		}
+		runManifest.ConfigFile = c.Config
+		runManifest.ConfigSHA256, err = manifest.HashFile(c.Config)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to hash configuration file %s: %w", c.Config, err)
+		}
+	}
+
+	runManifest.Config = cfg
+	if regions, err := inspector.GetEffectiveRegions(*cfg); err == nil {
+		runManifest.Regions = slices.Sorted(slices.Values(regions))
+	}
+	return cfg, runManifest, nil
+}
+
+// completeManifest records the identity of the scan and the end of the run in the manifest
+func completeManifest(runManifest *manifest.Manifest, scan *runner.ScanResult) {
+	if scan.Identity != nil {
+		runManifest.AccountID = scan.Identity.AccountID
+		runManifest.CallerARN = scan.Identity.ARN
+	}
+	runManifest.FinishedAt = time.Now().UTC()
+}
+
+// writeManifest writes the run manifest to --manifest-file, when set
+func (c *CheckCmd) writeManifest(runManifest *manifest.Manifest, logger *o11y.Logger) error {
+	if c.ManifestFile == "" {
+		return nil
+	}
+	if err := runManifest.WriteFile(c.ManifestFile); err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("✅ Run manifest written to %s", c.ManifestFile))
+	return nil
+}
+
+// selection returns the resource results listed by the table and detailed outputs
+func (c *CheckCmd) selection() output.ResultSelection {
+	return output.ResultSelection{
+		OnlyNonCompliant: c.OnlyNoncompliant,
+		SortBy:           c.Sort,
+		Descending:       c.Desc,
+	}
+}
+
+// outputFormat returns the format of --output, the JUnit output printing the summary as a table
+func (c *CheckCmd) outputFormat() string {
+	if strings.EqualFold(c.Output, string(output.FormatJUnit)) {
+		return string(output.FormatTable)
+	}
+	return c.Output
+}
+
+// formatter returns the formatter of the output format
+func (c *CheckCmd) formatter() (*output.Formatter, error) {
+	return output.NewFormatter(c.outputFormat())
+}
+
+// junitFile returns the file of the JUnit report, empty when no report is requested
+func (c *CheckCmd) junitFile() string {
+	if c.JUnitFile != "" {
+		return c.JUnitFile
+	}
+	if strings.EqualFold(c.Output, string(output.FormatJUnit)) {
+		return defaultJUnitFile
+	}
+	return ""
+}
+
+// sample returns the sample of --sample and --sample-seed, zero when every resource is
+// validated. A random seed is drawn when --sample-seed is unset, and logged so the sample
+// can be drawn again.
+func (c *CheckCmd) sample() (inspector.Sample, error) {
+	if c.Sample == "" {
+		if c.SampleSeed != 0 {
+			return inspector.Sample{}, fmt.Errorf("--sample-seed requires --sample")
+		}
+		return inspector.Sample{}, nil
+	}
+	if c.Incremental {
+		return inspector.Sample{}, fmt.Errorf("--sample cannot be combined with --incremental, the resources left out of the sample would be reported as deleted")
+	}
+
+	seed := c.SampleSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	sample, err := inspector.ParseSample(c.Sample, seed)
+	if err != nil {
+		return inspector.Sample{}, err
+	}
+
+	o11y.DefaultLogger().Info(fmt.Sprintf("🎲 Validating a sample of %s of the resources of every service, seed %d (--sample-seed %d draws it again)",
+		sample, sample.Seed, sample.Seed))
+	return sample, nil
+}
+
+// streaming reports whether resource results are streamed instead of accumulated
+func (c *CheckCmd) streaming() bool {
+	return c.Stream || strings.EqualFold(filepath.Ext(c.OutputFile), ".ndjson")
+}
+
+// writeFixPlan writes the fix plan of the auto-fixable violations to path
+func writeFixPlan(path string, entries []compliance.FixPlanEntry, logger *o11y.Logger) error {
+	if err := compliance.WriteFixPlan(path, entries); err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("✅ Fixes for %d resources written to %s, review them before applying the tag changes", len(entries), path))
+	return nil
+}
+
+// writeBadge writes the compliance badge of a run to path
+func writeBadge(path string, summary output.ComplianceSummary, thresholds configuration.BadgeThresholds, logger *o11y.Logger) error {
+	badge := summary.Badge(thresholds)
+	if err := compliance.WriteBadge(path, badge); err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("✅ Compliance badge (%s, %s) written to %s", badge.Message, badge.Color, path))
+	return nil
+}
+
+// writeJUnitReport writes the results of a compliance run as a JUnit XML report to path
+func writeJUnitReport(path string, report *runner.ComplianceReport, startedAt time.Time, duration time.Duration) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create JUnit report %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := output.WriteJUnit(file, report.ResourceResults, report.Summary, startedAt, duration); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close JUnit report %s: %w", path, err)
+	}
 	return nil
 }
 
+// detailedTableColumns are the columns of the detailed table, its summary rows filling them
+// by title so that every count lines up with the column it belongs to
+var detailedTableColumns = []tui.Column{
+	{Title: "Resource", Width: 30, Flexible: true},
+	{Title: "Tags", Width: 40, Flexible: true},
+	{Title: "Status", Width: 20},
+	{Title: "Level", Width: 10, Flexible: true},
+	{Title: "Violations", Width: 40, Flexible: true},
+}
+
+// detailedSummaryRow returns a row of the detailed table holding the given cells, keyed by
+// column title, the other columns being left empty
+func detailedSummaryRow(cells map[string]string) []string {
+	row := make([]string, len(detailedTableColumns))
+	for i, column := range detailedTableColumns {
+		row[i] = cells[column.Title]
+	}
+	return row
+}
+
 func renderDetailedTable(results []*output.ComplianceResult, summary output.ComplianceSummary) error {
 	// Prepare table data
 	tableData := [][]string{}
@@ -336,30 +830,142 @@ func renderDetailedTable(results []*output.ComplianceResult, summary output.Comp
 		resourceInfo := fmt.Sprintf("%s (%s)", compResult.ResourceID, compResult.ResourceType)
 		tagsStr := formatTags(compResult.ResourceTags)
 		complianceStatus := "✅ Compliant"
-		if !compResult.IsCompliant {
+		if compResult.IsUnknown {
+			complianceStatus = "❔ Unknown"
+		} else if !compResult.IsCompliant {
 			complianceStatus = "❌ Non-Compliant"
 		}
 
+		level := compResult.ComplianceLevel
+		if compResult.IsUnknown {
+			level = "-"
+		} else if level == "" {
+			level = string(compliance.ComplianceLevelNone)
+		}
+
 		violationsStr := formatViolations(compResult.Violations)
-		tableData = append(tableData, []string{resourceInfo, tagsStr, complianceStatus, violationsStr})
+		tableData = append(tableData, []string{resourceInfo, tagsStr, complianceStatus, level, violationsStr})
 	}
 
-	// Add summary row
-	tableData = append(tableData, []string{
-		"Summary",
-		fmt.Sprintf("Total: %d", summary.TotalResources),
-		fmt.Sprintf("Compliant: %d", summary.CompliantResources),
-		fmt.Sprintf("Non-Compliant: %d", summary.NonCompliantResources),
-	})
+	// Add summary rows
+	tableData = append(tableData, detailedSummaryRow(map[string]string{
+		"Resource":   "Summary",
+		"Tags":       fmt.Sprintf("Total: %d", summary.TotalResources),
+		"Status":     fmt.Sprintf("Compliant: %d", summary.CompliantResources),
+		"Violations": fmt.Sprintf("Non-Compliant: %d", summary.NonCompliantResources),
+	}))
+
+	if summary.ExcludedResources > 0 || summary.UnknownResources > 0 {
+		tableData = append(tableData, detailedSummaryRow(map[string]string{
+			"Tags":   fmt.Sprintf("Excluded: %d", summary.ExcludedResources),
+			"Status": fmt.Sprintf("Unknown: %d", summary.UnknownResources),
+		}))
+	}
+
+	// One row per compliance level, as the breakdown does not fit the width of the level column
+	for _, level := range append(compliance.ComplianceLevels(), compliance.ComplianceLevelNone) {
+		if count, ok := summary.ComplianceLevels[string(level)]; ok {
+			tableData = append(tableData, detailedSummaryRow(map[string]string{
+				"Status": fmt.Sprintf("%d resource(s)", count),
+				"Level":  string(level),
+			}))
+		}
+	}
 
 	// Render table
 	tableOpts := tui.TableOptions{
-		Title: "Compliance Check Results",
+		Title:     "Compliance Check Results",
+		Columns:   detailedTableColumns,
+		AutoWidth: true,
+	}
+
+	return tui.RenderTable(tableOpts, tableData)
+}
+
+// renderServiceErrors renders one row per service and region that could not be scanned,
+// whose resources are missing from the results
+func renderServiceErrors(serviceErrors []inspector.ServiceError) error {
+	tableData := make([][]string, 0, len(serviceErrors))
+	for _, serviceErr := range serviceErrors {
+		region := serviceErr.Region
+		if region == "" {
+			region = "all"
+		}
+		tableData = append(tableData, []string{serviceErr.Service, serviceErr.AccountID, region, serviceErr.Message})
+	}
+
+	return tui.RenderTable(tui.TableOptions{
+		Title: fmt.Sprintf("⚠️  %d service scan(s) failed, their resources are missing from the results", len(serviceErrors)),
+		Columns: []tui.Column{
+			{Title: "Service", Width: 20},
+			{Title: "Account", Width: 14},
+			{Title: "Region", Width: 16},
+			{Title: "Error", Width: 60, Flexible: true},
+		},
+		AutoWidth: true,
+	}, tableData)
+}
+
+// renderRuleTable renders one row per failing compliance rule, with the resource type failing
+// it the most and examples of the resources failing it
+func renderRuleTable(summary output.ComplianceSummary) error {
+	var tableData [][]string
+	for _, key := range slices.Sorted(maps.Keys(summary.RuleResults)) {
+		rule := summary.RuleResults[key]
+		if rule.Passed || rule.IsSkipped() {
+			continue
+		}
+
+		topService, topFailures := rule.TopResourceType()
+		examples := "-"
+		if len(rule.ExampleResources) > 0 {
+			examples = strings.Join(rule.ExampleResources, ", ")
+		}
+		tableData = append(tableData, []string{
+			rule.Name,
+			fmt.Sprintf("%d", rule.Failures),
+			fmt.Sprintf("%s (%d)", topService, topFailures),
+			examples,
+		})
+	}
+	if len(tableData) == 0 {
+		return nil
+	}
+
+	return tui.RenderTable(tui.TableOptions{
+		Title: "Failing Rules",
+		Columns: []tui.Column{
+			{Title: "Rule", Width: 30, Flexible: true},
+			{Title: "Failures", Width: 10},
+			{Title: "Top Service", Width: 20},
+			{Title: "Examples", Width: 60, Flexible: true},
+		},
+		AutoWidth: true,
+	}, tableData)
+}
+
+// renderGroupTable renders one row per group of the compliance summary
+func renderGroupTable(summary output.ComplianceSummary) error {
+	tableData := make([][]string, 0, len(summary.Groups))
+	for _, key := range runner.SortedGroupKeys(summary.Groups) {
+		group := summary.Groups[key]
+		tableData = append(tableData, []string{
+			key,
+			fmt.Sprintf("%d", group.TotalResources),
+			fmt.Sprintf("%d", group.CompliantResources),
+			fmt.Sprintf("%d", group.NonCompliantResources),
+			group.FormatTopViolations(runner.TopViolationsLimit),
+		})
+	}
+
+	tableOpts := tui.TableOptions{
+		Title: fmt.Sprintf("Compliance by %s", summary.GroupBy),
 		Columns: []tui.Column{
-			{Title: "Resource", Width: 30, Flexible: true},
-			{Title: "Tags", Width: 40, Flexible: true},
-			{Title: "Status", Width: 20},
-			{Title: "Violations", Width: 40, Flexible: true},
+			{Title: "Group", Width: 30, Flexible: true},
+			{Title: "Total", Width: 8},
+			{Title: "Compliant", Width: 10},
+			{Title: "Non-Compliant", Width: 14},
+			{Title: "Top Violations", Width: 50, Flexible: true},
 		},
 		AutoWidth: true,
 	}
@@ -367,6 +973,38 @@ func renderDetailedTable(results []*output.ComplianceResult, summary output.Comp
 	return tui.RenderTable(tableOpts, tableData)
 }
 
+// renderCoverageTable renders one row per required tag key and resource type with the number
+// of resources carrying and missing the key
+func renderCoverageTable(coverage []runner.TagCoverage) error {
+	if len(coverage) == 0 {
+		fmt.Println("\nNo tag coverage to report: the configuration requires no tag or no resource was checked")
+		return nil
+	}
+
+	tableData := make([][]string, 0, len(coverage))
+	for _, entry := range coverage {
+		tableData = append(tableData, []string{
+			entry.TagKey,
+			entry.ResourceType,
+			fmt.Sprintf("%d", entry.Present),
+			fmt.Sprintf("%d", entry.Missing),
+			fmt.Sprintf("%.1f%%", entry.Percentage),
+		})
+	}
+
+	return tui.RenderTable(tui.TableOptions{
+		Title: "Required Tag Coverage",
+		Columns: []tui.Column{
+			{Title: "Tag", Width: 25, Flexible: true},
+			{Title: "Service", Width: 15},
+			{Title: "Present", Width: 10},
+			{Title: "Missing", Width: 10},
+			{Title: "%", Width: 8},
+		},
+		AutoWidth: true,
+	}, tableData)
+}
+
 // Helper functions
 func formatTags(tags map[string]string) string {
 	if len(tags) == 0 {
@@ -393,7 +1031,7 @@ func formatViolations(violations []output.Violation) string {
 		if result != "" {
 			result += "\n"
 		}
-		result += fmt.Sprintf("%s: %s", v.Type, v.Message)
+		result += fmt.Sprintf("[%s] %s: %s", v.Severity, v.Type, v.Describe())
 	}
 	return result
 }
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/cli/internal/output"
+	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/Excoriate/aws-taggy/pkg/runner"
+	"github.com/Excoriate/aws-taggy/pkg/tfplan"
+)
+
+// CheckPlanCmd represents the command checking the tag compliance of the resources of a
+// Terraform plan or state, before they are applied and without AWS credentials
+type CheckPlanCmd struct {
+	Config         string   `help:"Path to the tag compliance configuration file" required:"true" type:"path"`
+	Plan           string   `help:"Terraform plan or state to check: the output of terraform show -json for a saved plan or for the state, or a terraform.tfstate file" required:"true" type:"existingfile"`
+	Output         string   `help:"Output format (${output_formats})" default:"table"`
+	Table          bool     `help:"Display detailed information in tables" default:"false"`
+	OutputFile     string   `help:"Write detailed JSON output to specified file" type:"path"`
+	Set            []string `help:"Override a configuration setting, e.g. --set resources.s3.enabled=false (repeatable, lists are comma-separated)" placeholder:"PATH=VALUE" sep:"none"`
+	Suppressions   string   `help:"YAML file of accepted violations, left out of the compliance status and score (see compliance baseline)" type:"path"`
+	MinScore       float64  `help:"Fail when the weighted compliance score (0-100) is below this value" default:"0"`
+	MinLevel       string   `help:"Fail when a resource meets neither this compliance level nor a stricter one (high, standard, medium or low)" placeholder:"LEVEL"`
+	FailOnWarnings bool     `help:"Fail when any resource has a warning, such as a deprecated allowed value, which otherwise leaves its compliance untouched" default:"false"`
+	RuleExamples   int      `help:"Number of resources failing each rule listed as examples in the rule results, none when 0" default:"5" placeholder:"N"`
+	Redact         []string `help:"Mask the values of this tag key in every output, keeping the key visible (repeatable, added to reporting.redact_tags of the configuration)" placeholder:"KEY" sep:"none"`
+}
+
+// Run validates the tags of the resources of the plan or state against the configuration
+func (c *CheckPlanCmd) Run(ctx context.Context) error {
+	logger := o11y.DefaultLogger()
+
+	formatter, err := output.NewFormatter(c.Output)
+	if err != nil {
+		return err
+	}
+
+	if err := validateThresholds(c.MinScore, c.MinLevel); err != nil {
+		return err
+	}
+
+	overrides, err := configuration.ParseOverrides(c.Set)
+	if err != nil {
+		return err
+	}
+
+	var suppressions *compliance.Suppressions
+	if c.Suppressions != "" {
+		suppressions, err = compliance.LoadSuppressions(c.Suppressions)
+		if err != nil {
+			return err
+		}
+	}
+
+	plan, err := tfplan.Load(c.Plan)
+	if err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("🔍 Checking the tags of the resources of Terraform %s %s", plan.Kind, c.Plan))
+
+	loader := configuration.NewTaggyScanConfigLoader()
+	loader.SetOverrides(overrides)
+	cfg, err := loader.LoadConfig(c.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration from file %s: %w. Please check the configuration file path and its contents", c.Config, err)
+	}
+
+	configValidator, err := configuration.NewContentValidator(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize configuration validator for file %s: %w. Ensure the configuration is valid and follows the expected schema", c.Config, err)
+	}
+	if err := configValidator.ValidateContent(); err != nil {
+		return fmt.Errorf("configuration validation failed for file %s: %w. Review the configuration and ensure all required fields are correctly specified", c.Config, err)
+	}
+
+	if _, defined := cfg.ComplianceLevels[c.MinLevel]; c.MinLevel != "" && !defined {
+		return fmt.Errorf("--min-level %s is not defined in the compliance_levels of configuration file %s", c.MinLevel, c.Config)
+	}
+
+	redactor := output.NewRedactor(append(slices.Clone(cfg.Reporting.RedactTags), c.Redact...))
+
+	// The runner lists runner.DefaultRuleExamples when the option is 0, none when negative
+	ruleExamples := c.RuleExamples
+	if ruleExamples <= 0 {
+		ruleExamples = -1
+	}
+
+	complianceRunner, err := runner.New(cfg, runner.Options{
+		Suppressions: suppressions,
+		RuleExamples: ruleExamples,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Tags computed during the apply have no value yet, they are validated as missing
+	for _, resource := range plan.Resources {
+		if len(resource.UnknownTags) > 0 {
+			logger.Warn(fmt.Sprintf("Tags %s of %s are known after apply, they are checked as missing",
+				strings.Join(resource.UnknownTags, ", "), resource.Address))
+		}
+	}
+
+	report, err := complianceRunner.Report(ctx, complianceRunner.PlanScan(plan))
+	if err != nil {
+		return err
+	}
+	summary := report.Summary
+	redactedReport := redactor.Report(report)
+
+	if c.OutputFile != "" {
+		jsonData, err := json.MarshalIndent(redactedReport, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON data: %w", err)
+		}
+		if err := os.WriteFile(c.OutputFile, jsonData, 0o644); err != nil {
+			return fmt.Errorf("failed to write JSON to file: %w", err)
+		}
+		logger.Info(fmt.Sprintf("✅ Detailed compliance results written to %s", c.OutputFile))
+	}
+
+	if formatter.IsStructured() {
+		if err := formatter.Output(redactedReport); err != nil {
+			return err
+		}
+		return thresholdsError(summary, c.MinScore, c.MinLevel, c.FailOnWarnings)
+	}
+
+	output.PrintConfigValidation()
+	if c.Table {
+		if err := renderDetailedTable(redactedReport.ResourceResults, summary); err != nil {
+			return err
+		}
+		if err := renderRuleTable(summary); err != nil {
+			return err
+		}
+	} else {
+		output.PrintComplianceSummary(summary)
+	}
+
+	if len(report.Skipped) > 0 {
+		if err := renderSkippedResources(report.Skipped); err != nil {
+			return err
+		}
+	}
+
+	return thresholdsError(summary, c.MinScore, c.MinLevel, c.FailOnWarnings)
+}
+
+// renderSkippedResources renders one row per resource of the plan whose tags were not checked
+func renderSkippedResources(skipped []tfplan.SkippedResource) error {
+	tableData := make([][]string, 0, len(skipped))
+	for _, resource := range skipped {
+		tableData = append(tableData, []string{resource.Address, resource.TerraformType, resource.Reason})
+	}
+
+	return tui.RenderTable(tui.TableOptions{
+		Title: fmt.Sprintf("⏭️  %d resource(s) skipped, their tags were not checked", len(skipped)),
+		Columns: []tui.Column{
+			{Title: "Address", Width: 40, Flexible: true},
+			{Title: "Terraform Type", Width: 30},
+			{Title: "Reason", Width: 50},
+		},
+		AutoWidth: true,
+	}, tableData)
+}
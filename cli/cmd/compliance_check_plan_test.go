@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/tfplan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exitCode returns the exit status the binary ends with on the error of a command
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
+
+func TestCheckPlanCmdExitCode(t *testing.T) {
+	// The queue of the plan has no tag and the instance misses Environment
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name: "Violations Without Threshold",
+		},
+		{
+			name: "Score Threshold Met",
+			args: []string{"--min-score", "0"},
+		},
+		{
+			name:         "Score Below Threshold",
+			args:         []string{"--min-score", "100"},
+			expectedCode: 1,
+			expectedErr:  "is below the minimum score 100.0",
+		},
+		{
+			name:         "Score Below Threshold With JSON Output",
+			args:         []string{"--min-score", "100", "--output", "json"},
+			expectedCode: 1,
+			expectedErr:  "is below the minimum score 100.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"compliance", "check-plan", "--config", writeConfig(t), "--plan", planFile}, tt.args...)
+			stdout, _, err := runCommand(t, args...)
+
+			assert.Equal(t, tt.expectedCode, exitCode(err))
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+			}
+			assert.NotEmpty(t, stdout, "the report is printed whether the thresholds are met or not")
+		})
+	}
+}
+
+func TestCheckPlanCmdListsSkippedResources(t *testing.T) {
+	t.Run("Table", func(t *testing.T) {
+		stdout, _, err := runCommand(t, "compliance", "check-plan", "--config", writeConfig(t), "--plan", planFile)
+		require.NoError(t, err)
+
+		assert.Contains(t, stdout, "2 resource(s) skipped, their tags were not checked")
+		for _, expected := range []string{
+			"aws_iam_role.deploy", tfplan.SkipReasonUnmapped,
+			"aws_sns_topic.alerts", tfplan.SkipReasonTagsUnknown,
+		} {
+			assert.Contains(t, stdout, expected)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		stdout, _, err := runCommand(t, "compliance", "check-plan", "--config", writeConfig(t), "--plan", planFile,
+			"--output", "json")
+		require.NoError(t, err)
+
+		var report struct {
+			Skipped []tfplan.SkippedResource `json:"skipped_resources"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(stdout), &report), stdout)
+		assert.Equal(t, []tfplan.SkippedResource{
+			{Address: "aws_iam_role.deploy", TerraformType: "aws_iam_role", Reason: tfplan.SkipReasonUnmapped},
+			{Address: "aws_sns_topic.alerts", TerraformType: "aws_sns_topic", Reason: tfplan.SkipReasonTagsUnknown},
+		}, report.Skipped)
+	})
+}
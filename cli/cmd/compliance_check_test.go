@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetailedSummaryRow(t *testing.T) {
+	t.Parallel()
+
+	row := detailedSummaryRow(map[string]string{
+		"Resource":   "Summary",
+		"Status":     "Compliant: 2",
+		"Level":      "high",
+		"Violations": "Non-Compliant: 1",
+	})
+
+	// Every cell lands in the column of its title, whatever their order in the table
+	assert.Len(t, row, len(detailedTableColumns))
+	assert.Equal(t, []string{"Summary", "", "Compliant: 2", "high", "Non-Compliant: 1"}, row)
+}
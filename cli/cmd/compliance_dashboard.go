@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Excoriate/aws-taggy/cli/internal/output"
+	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+)
+
+// runComplianceDashboard opens the interactive dashboard of the results of a compliance check
+func runComplianceDashboard(config string, results []*output.ComplianceResult) error {
+	return tui.RunDashboard(tui.DashboardOptions{
+		Title: fmt.Sprintf("🏷️  Compliance of %s", config),
+	}, dashboardResources(results))
+}
+
+// dashboardResources converts the results of a compliance check into the resources listed by
+// the dashboard, exported as their results
+func dashboardResources(results []*output.ComplianceResult) []tui.DashboardResource {
+	resources := make([]tui.DashboardResource, 0, len(results))
+	for _, result := range results {
+		status := tui.StatusCompliant
+		if result.IsUnknown {
+			status = tui.StatusUnknown
+		} else if !result.IsCompliant {
+			status = tui.StatusNonCompliant
+		}
+
+		var violations []string
+		for _, v := range result.Violations {
+			violations = append(violations, fmt.Sprintf("[%s] %s: %s", v.Severity, v.Type, v.Describe()))
+		}
+		if result.TagFetchError != "" {
+			violations = append(violations, fmt.Sprintf("tags unreadable: %s", result.TagFetchError))
+		}
+
+		resources = append(resources, tui.DashboardResource{
+			ID:         result.ResourceID,
+			Service:    result.ResourceType,
+			Region:     result.Region,
+			Status:     status,
+			Score:      result.Score,
+			Tags:       result.ResourceTags,
+			Violations: violations,
+			Result:     result,
+		})
+	}
+	return resources
+}
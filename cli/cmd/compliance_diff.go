@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/cli/internal/output"
+	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+)
+
+// DiffCmd represents the command comparing two compliance check outputs
+type DiffCmd struct {
+	Baseline         string `help:"Compliance results of the earlier scan, as written by --output-file" required:"true" type:"path"`
+	Current          string `help:"Compliance results of the later scan, as written by --output-file" required:"true" type:"path"`
+	Output           string `help:"Output format (${output_formats})" default:"table"`
+	FailOnRegression bool   `help:"Exit with an error if any resource went from compliant to non-compliant" default:"false"`
+}
+
+// Run implements the logic for reporting tag drift between two scans
+func (d *DiffCmd) Run() error {
+	formatter, err := output.NewFormatter(d.Output)
+	if err != nil {
+		return err
+	}
+
+	baseline, err := output.ReadComplianceResults(d.Baseline)
+	if err != nil {
+		return fmt.Errorf("failed to read baseline results: %w", err)
+	}
+
+	current, err := output.ReadComplianceResults(d.Current)
+	if err != nil {
+		return fmt.Errorf("failed to read current results: %w", err)
+	}
+
+	diff := output.DiffComplianceResults(baseline, current)
+
+	if formatter.IsStructured() {
+		err = formatter.Output(diff)
+	} else {
+		err = renderDiffTable(diff)
+	}
+	if err != nil {
+		return err
+	}
+
+	if d.FailOnRegression && diff.HasRegressions() {
+		return fmt.Errorf("%d resource(s) went from compliant to non-compliant", len(diff.Regressions))
+	}
+
+	return nil
+}
+
+// renderDiffTable prints one row per changed, new or deleted resource, followed by the counts
+func renderDiffTable(diff *output.ComplianceDiff) error {
+	tableData := [][]string{}
+
+	regressed := make(map[*output.ResourceDiff]bool, len(diff.Regressions))
+	for _, change := range diff.Regressions {
+		regressed[change] = true
+		tableData = append(tableData, []string{
+			diffResourceLabel(change.ResourceID, change.ResourceType),
+			"❌ Regressed",
+			joinNonEmpty(formatTagChanges(change.TagChanges), formatViolations(change.Violations)),
+		})
+	}
+
+	fixed := make(map[*output.ResourceDiff]bool, len(diff.Fixed))
+	for _, change := range diff.Fixed {
+		fixed[change] = true
+		tableData = append(tableData, []string{
+			diffResourceLabel(change.ResourceID, change.ResourceType),
+			"✅ Fixed",
+			formatTagChanges(change.TagChanges),
+		})
+	}
+
+	for _, change := range diff.TagChanges {
+		if regressed[change] || fixed[change] {
+			continue
+		}
+		tableData = append(tableData, []string{
+			diffResourceLabel(change.ResourceID, change.ResourceType),
+			"🏷️ Tags changed",
+			formatTagChanges(change.TagChanges),
+		})
+	}
+
+	for _, result := range diff.NewResources {
+		status := "compliant"
+		if !result.IsCompliant {
+			status = "non-compliant"
+		}
+		tableData = append(tableData, []string{diffResourceLabel(result.ResourceID, result.ResourceType), "🆕 New", status})
+	}
+
+	for _, result := range diff.DeletedResources {
+		tableData = append(tableData, []string{diffResourceLabel(result.ResourceID, result.ResourceType), "🗑️ Deleted", ""})
+	}
+
+	summary := diff.Summary
+	fmt.Printf("\n📊 Compliance Drift: %d regressed, %d fixed, %d with tag changes, %d new, %d deleted (%d baseline, %d current resources)\n\n",
+		summary.Regressions, summary.Fixed, summary.TagChanges, summary.NewResources, summary.DeletedResources,
+		summary.BaselineResources, summary.CurrentResources)
+
+	if len(tableData) == 0 {
+		fmt.Println("✅ No drift between the two scans")
+		return nil
+	}
+
+	tableOpts := tui.TableOptions{
+		Title: "Compliance Drift",
+		Columns: []tui.Column{
+			{Title: "Resource", Width: 30, Flexible: true},
+			{Title: "Change", Width: 18},
+			{Title: "Details", Width: 50, Flexible: true},
+		},
+		AutoWidth: true,
+	}
+
+	return tui.RenderTable(tableOpts, tableData)
+}
+
+// diffResourceLabel formats a resource as "<id> (<type>)"
+func diffResourceLabel(id, resourceType string) string {
+	return fmt.Sprintf("%s (%s)", id, resourceType)
+}
+
+// formatTagChanges renders tag changes one per line: +key=value, -key=value or ~key: old → new
+func formatTagChanges(changes []output.TagChange) string {
+	lines := make([]string, 0, len(changes))
+	for _, change := range changes {
+		switch change.Change {
+		case output.TagAdded:
+			lines = append(lines, fmt.Sprintf("+%s=%s", change.Key, change.NewValue))
+		case output.TagRemoved:
+			lines = append(lines, fmt.Sprintf("-%s=%s", change.Key, change.OldValue))
+		default:
+			lines = append(lines, fmt.Sprintf("~%s: %s → %s", change.Key, change.OldValue, change.NewValue))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// joinNonEmpty joins the non-empty parts with new lines
+func joinNonEmpty(parts ...string) string {
+	kept := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
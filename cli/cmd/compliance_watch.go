@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/cli/internal/output"
+	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/Excoriate/aws-taggy/pkg/runner"
+)
+
+// minWatchInterval keeps watches from hammering the AWS APIs
+const minWatchInterval = time.Minute
+
+// clearScreen moves the cursor home and clears the terminal before each refresh
+const clearScreen = "\033[H\033[2J"
+
+// WatchCmd represents the command rescanning compliance on a timer
+type WatchCmd struct {
+	Config       string        `help:"Path to the tag compliance configuration file" required:"true"`
+	Interval     time.Duration `help:"Time between the start of two scans (e.g. 10m), at least 1m" default:"10m"`
+	History      int           `help:"Number of past runs shown in the compliance trend" default:"10"`
+	Changes      bool          `help:"Show the resources that became non-compliant or were fixed since the previous run" default:"false"`
+	Resource     string        `help:"Filter compliance check for a specific resource (name or ARN)" optional:"true"`
+	Set          []string      `help:"Override a configuration setting, e.g. --set aws.regions.mode=specific (repeatable, lists are comma-separated)" placeholder:"PATH=VALUE" sep:"none"`
+	FilterTag    []string      `help:"Only check resources whose tags match: key (present), key=value or key!=value (repeatable, all must match)" placeholder:"SELECTOR" sep:"none"`
+	Suppressions string        `help:"YAML file of accepted violations, left out of the compliance status and score (see compliance baseline)" type:"path"`
+	Redact       []string      `help:"Mask the values of this tag key in the dashboard, keeping the key visible (repeatable, added to reporting.redact_tags of the configuration)" placeholder:"KEY" sep:"none"`
+}
+
+// Run rescans the resources every interval and refreshes the compliance summary until
+// interrupted
+func (w *WatchCmd) Run(ctx context.Context) error {
+	logger := o11y.DefaultLogger()
+
+	if w.Interval < minWatchInterval {
+		return fmt.Errorf("--interval must be at least %s to avoid hammering the AWS APIs, got %s", minWatchInterval, w.Interval)
+	}
+	if w.History < 1 {
+		return fmt.Errorf("--history must be at least 1, got %d", w.History)
+	}
+
+	overrides, err := configuration.ParseOverrides(w.Set)
+	if err != nil {
+		return err
+	}
+
+	tagSelectors, err := inspector.ParseTagSelectors(w.FilterTag)
+	if err != nil {
+		return err
+	}
+
+	var suppressions *compliance.Suppressions
+	if w.Suppressions != "" {
+		suppressions, err = compliance.LoadSuppressions(w.Suppressions)
+		if err != nil {
+			return err
+		}
+	}
+
+	loader := configuration.NewTaggyScanConfigLoader()
+	loader.SetOverrides(overrides)
+
+	cfg, err := loader.LoadConfig(w.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration from file %s: %w", w.Config, err)
+	}
+
+	configValidator, err := configuration.NewContentValidator(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize configuration validator for file %s: %w", w.Config, err)
+	}
+	if err := configValidator.ValidateContent(); err != nil {
+		return fmt.Errorf("configuration validation failed for file %s: %w", w.Config, err)
+	}
+
+	// Every run scans AWS, cached results would hide the remediation progress
+	watchRunner, err := runner.New(cfg, runner.Options{
+		Resource:     w.Resource,
+		TagSelectors: tagSelectors,
+		Suppressions: suppressions,
+	})
+	if err != nil {
+		return err
+	}
+
+	redactor := output.NewRedactor(append(slices.Clone(cfg.Reporting.RedactTags), w.Redact...))
+	history := output.NewSummaryHistory(w.History)
+	var previous []*output.ComplianceResult
+
+	for {
+		startedAt := time.Now()
+		logger.Info(fmt.Sprintf("🔍 Scanning resources (run started at %s)", startedAt.Format(time.TimeOnly)))
+
+		scan, err := watchRunner.Scan(ctx)
+		var report *runner.ComplianceReport
+		if err == nil {
+			report, err = watchRunner.Report(ctx, scan)
+		}
+		switch {
+		case ctx.Err() != nil:
+			logger.Info("Watch stopped")
+			return nil
+		case err != nil:
+			// A failed run is retried on the next tick, the dashboard keeps the last results
+			logger.Error(fmt.Sprintf("Scan failed, retrying in %s: %v", w.Interval, err))
+		default:
+			report = redactor.Report(report)
+			history.Add(startedAt, report.Summary)
+			if err := w.render(startedAt, report, previous, history); err != nil {
+				return err
+			}
+			previous = report.ResourceResults
+		}
+
+		wait := time.Until(startedAt.Add(w.Interval))
+		fmt.Printf("Next scan at %s, press Ctrl+C to stop\n", time.Now().Add(wait).Format(time.TimeOnly))
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Watch stopped")
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// render redraws the dashboard: the summary, the compliance trend and, with --changes, the
+// resources whose compliance changed since the previous run
+func (w *WatchCmd) render(at time.Time, report *runner.ComplianceReport, previous []*output.ComplianceResult, history *output.SummaryHistory) error {
+	fmt.Print(clearScreen)
+	fmt.Printf("👀 Compliance watch of %s, every %s (last run %s)\n", w.Config, w.Interval, at.Format(time.TimeOnly))
+
+	output.PrintComplianceSummary(report.Summary)
+	fmt.Printf("📈 Trend: %s\n\n", history.Trend())
+
+	if !w.Changes || previous == nil {
+		return nil
+	}
+
+	return renderWatchChanges(output.DiffComplianceResults(previous, report.ResourceResults))
+}
+
+// renderWatchChanges prints the resources that became non-compliant or were fixed, including
+// new resources that are not compliant
+func renderWatchChanges(diff *output.ComplianceDiff) error {
+	tableData := [][]string{}
+
+	for _, change := range diff.Regressions {
+		tableData = append(tableData, []string{
+			diffResourceLabel(change.ResourceID, change.ResourceType),
+			"❌ Now non-compliant",
+			formatViolations(change.Violations),
+		})
+	}
+
+	for _, result := range diff.NewResources {
+		if result.IsCompliant || result.IsUnknown {
+			continue
+		}
+		tableData = append(tableData, []string{
+			diffResourceLabel(result.ResourceID, result.ResourceType),
+			"🆕 New, non-compliant",
+			formatViolations(result.Violations),
+		})
+	}
+
+	for _, change := range diff.Fixed {
+		tableData = append(tableData, []string{
+			diffResourceLabel(change.ResourceID, change.ResourceType),
+			"✅ Fixed",
+			formatTagChanges(change.TagChanges),
+		})
+	}
+
+	if len(tableData) == 0 {
+		fmt.Println("No compliance changes since the previous run")
+		return nil
+	}
+
+	return tui.RenderTable(tui.TableOptions{
+		Title: "Changes Since The Previous Run",
+		Columns: []tui.Column{
+			{Title: "Resource", Width: 30, Flexible: true},
+			{Title: "Change", Width: 22},
+			{Title: "Details", Width: 50, Flexible: true},
+		},
+		AutoWidth: true,
+	}, tableData)
+}
@@ -2,10 +2,12 @@
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/Excoriate/aws-taggy/cli/internal/output"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
 	"github.com/Excoriate/aws-taggy/pkg/o11y"
 	"github.com/alecthomas/kong"
 )
@@ -14,6 +16,12 @@
 type ConfigCmd struct {
 	Validate ValidateCmd `cmd:"" help:"Validate the tag compliance configuration file"`
 	Generate GenerateCmd `cmd:"" help:"Generate a sample configuration file"`
+	Init     InitCmd     `cmd:"" help:"Scaffold a commented starter configuration file"`
+	Show     ShowCmd     `cmd:"" help:"Print the configuration, optionally with resolved compliance levels"`
+	Lint     LintCmd     `cmd:"" help:"Report unused and unreachable sections of the configuration file"`
+	TestRule TestRuleCmd `cmd:"" help:"Test candidate values of a tag against the rules of the configuration file"`
+	Assert   AssertCmd   `cmd:"" help:"Assert the compliance outcome of fixture test cases against the configuration file"`
+	Migrate  MigrateCmd  `cmd:"" help:"Upgrade a configuration file written for an older version to the supported version"`
 }
 
 // BeforeApply is a Kong hook to perform any pre-processing before the command is run
@@ -63,3 +71,45 @@ func (c *GenerateCmd) Run() error {
 
 	return nil
 }
+
+// InitCmd represents the command scaffolding a starter configuration file
+type InitCmd struct {
+	Output    string   `short:"o" help:"Output file path for the starter configuration" default:"tag-compliance.yaml"`
+	Force     bool     `short:"f" help:"Force overwrite if the configuration file already exists"`
+	Minimal   bool     `help:"Write a bare-bones configuration without comments and examples"`
+	Resources []string `help:"Resource types to pre-populate, comma separated (e.g. s3,ec2,rds)" sep:","`
+}
+
+// Run implements the logic for scaffolding a starter configuration file
+func (c *InitCmd) Run() error {
+	logger := o11y.DefaultLogger()
+
+	if ext := filepath.Ext(c.Output); ext != ".yaml" {
+		return fmt.Errorf("configuration file %s must have a .yaml extension", c.Output)
+	}
+
+	content, err := configuration.Scaffold(configuration.ScaffoldOptions{
+		Minimal:   c.Minimal,
+		Resources: c.Resources,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scaffold configuration: %w", err)
+	}
+
+	if !c.Force {
+		if _, err := os.Stat(c.Output); err == nil {
+			return fmt.Errorf("configuration file already exists at %s. Use the --force flag to overwrite", c.Output)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.Output), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for configuration file: %w", err)
+	}
+
+	if err := os.WriteFile(c.Output, content, 0o600); err != nil {
+		return fmt.Errorf("failed to write configuration file: %w", err)
+	}
+
+	logger.Info(fmt.Sprintf("✅ Starter configuration written to %s", c.Output))
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/cli/internal/output"
+	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+)
+
+// AssertCmd represents the command evaluating the test cases of a fixtures file against a
+// configuration file, so changes relaxing the rules are caught
+type AssertCmd struct {
+	Config   string `help:"Path to the tag compliance configuration file" required:"true"`
+	Fixtures string `help:"Path to the fixtures file declaring the test cases" required:"true" type:"path"`
+	Output   string `help:"Output format (${output_formats})" default:"table"`
+}
+
+// assertReport is the machine-readable outcome of assert
+type assertReport struct {
+	File     string                       `json:"file" yaml:"file"`
+	Fixtures string                       `json:"fixtures" yaml:"fixtures"`
+	Passed   int                          `json:"passed" yaml:"passed"`
+	Failed   int                          `json:"failed" yaml:"failed"`
+	Results  []compliance.AssertionResult `json:"results" yaml:"results"`
+}
+
+// Run loads the configuration and the fixtures, and prints whether each case gets the
+// expected compliance outcome
+func (a *AssertCmd) Run() error {
+	formatter, err := output.NewFormatter(a.Output)
+	if err != nil {
+		return err
+	}
+
+	fixtures, err := compliance.LoadFixtures(a.Fixtures)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := configuration.NewTaggyScanConfigLoader().LoadConfig(a.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration file %s: %w", a.Config, err)
+	}
+
+	configValidator, err := configuration.NewContentValidator(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize configuration validator for file %s: %w", a.Config, err)
+	}
+	if err := configValidator.ValidateContent(); err != nil {
+		return fmt.Errorf("configuration validation failed for file %s: %w", a.Config, err)
+	}
+
+	validator := compliance.NewTagValidator(cfg)
+
+	report := assertReport{File: a.Config, Fixtures: a.Fixtures}
+	for _, fixture := range fixtures {
+		result := validator.Assert(fixture)
+		if result.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	if formatter.IsStructured() {
+		err = formatter.Output(report)
+	} else {
+		err = renderAssertions(report)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to output assertions of fixtures file %s: %w", a.Fixtures, err)
+	}
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d fixture(s) of %s fail against configuration file %s",
+			report.Failed, len(report.Results), a.Fixtures, a.Config)
+	}
+	return nil
+}
+
+// renderAssertions prints a row per fixture with its outcome and, for failed ones, how it
+// differs from the expected outcome
+func renderAssertions(report assertReport) error {
+	tableData := make([][]string, 0, len(report.Results))
+	for _, result := range report.Results {
+		violations := strings.Join(result.Violations, ", ")
+		if violations == "" {
+			violations = "-"
+		}
+		tableData = append(tableData, []string{
+			result.Name,
+			result.ResourceType,
+			fmt.Sprintf("%t", result.Compliant),
+			violations,
+			passMark(result.Passed),
+			strings.Join(result.Failures, "; "),
+		})
+	}
+
+	return tui.RenderTable(tui.TableOptions{
+		Title: fmt.Sprintf("🧪 Fixtures of %s (%d of %d pass)", report.Fixtures, report.Passed, len(report.Results)),
+		Columns: []tui.Column{
+			{Title: "Fixture", Width: 30, Flexible: true},
+			{Title: "Resource Type", Width: 15},
+			{Title: "Compliant", Width: 10},
+			{Title: "Violations", Width: 30, Flexible: true},
+			{Title: "Result", Width: 8},
+			{Title: "Failures", Width: 50, Flexible: true},
+		},
+		AutoWidth: true,
+	}, tableData)
+}
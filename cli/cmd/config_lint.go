@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Excoriate/aws-taggy/cli/internal/output"
+	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+)
+
+// LintCmd represents the command reporting the dead sections of a configuration file
+type LintCmd struct {
+	Config string `help:"Path to the tag compliance configuration file" required:"true"`
+	Output string `help:"Output format (${output_formats})" default:"table"`
+	Strict bool   `help:"Fail when any warning is found" default:"false"`
+}
+
+// lintReport is the machine-readable list of warnings found in a configuration file
+type lintReport struct {
+	File     string                      `json:"file" yaml:"file"`
+	Warnings []configuration.LintFinding `json:"warnings" yaml:"warnings"`
+}
+
+// Run loads the configuration and prints its unused and unreachable sections
+func (l *LintCmd) Run() error {
+	formatter, err := output.NewFormatter(l.Output)
+	if err != nil {
+		return err
+	}
+
+	loader := configuration.NewTaggyScanConfigLoader()
+	cfg, err := loader.LoadConfig(l.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration file %s: %w", l.Config, err)
+	}
+
+	report := lintReport{
+		File:     l.Config,
+		Warnings: configuration.Lint(cfg),
+	}
+	if report.Warnings == nil {
+		report.Warnings = []configuration.LintFinding{}
+	}
+
+	if formatter.IsStructured() {
+		if err := formatter.Output(report); err != nil {
+			return fmt.Errorf("failed to output lint report for file %s: %w", l.Config, err)
+		}
+		return l.strictError(report.Warnings)
+	}
+
+	if len(report.Warnings) == 0 {
+		o11y.DefaultLogger().Info(fmt.Sprintf("✅ No unused or unreachable settings found in %s", l.Config))
+		return nil
+	}
+
+	tableData := make([][]string, 0, len(report.Warnings))
+	for _, warning := range report.Warnings {
+		tableData = append(tableData, []string{warning.Path, warning.Rule, warning.Message})
+	}
+
+	if err := tui.RenderTable(tui.TableOptions{
+		Title: fmt.Sprintf("⚠️  Configuration Lint Warnings (%d)", len(report.Warnings)),
+		Columns: []tui.Column{
+			{Title: "Location", Width: 40, Flexible: true},
+			{Title: "Rule", Width: 25},
+			{Title: "Message", Width: 60, Flexible: true},
+		},
+		AutoWidth: true,
+	}, tableData); err != nil {
+		return fmt.Errorf("failed to render lint warnings for file %s: %w", l.Config, err)
+	}
+
+	return l.strictError(report.Warnings)
+}
+
+// strictError fails the command on warnings when --strict is set
+func (l *LintCmd) strictError(warnings []configuration.LintFinding) error {
+	if !l.Strict || len(warnings) == 0 {
+		return nil
+	}
+	return fmt.Errorf("configuration file %s has %d lint warning(s)", l.Config, len(warnings))
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+)
+
+// MigrateCmd represents the command upgrading a configuration file to the supported version
+type MigrateCmd struct {
+	Config string `help:"Path to the tag compliance configuration file to upgrade" required:"true"`
+	Output string `short:"o" help:"Output file path for the upgraded configuration, YAML" required:"true"`
+	Force  bool   `short:"f" help:"Force overwrite if the output file already exists"`
+}
+
+// Run implements the logic for upgrading the configuration file
+func (m *MigrateCmd) Run() error {
+	logger := o11y.DefaultLogger()
+
+	if ext := filepath.Ext(m.Output); ext != ".yaml" && ext != ".yml" {
+		return fmt.Errorf("output file %s must have a .yaml or .yml extension", m.Output)
+	}
+
+	format, err := configuration.DetectConfigFormat(m.Config)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(m.Config)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file %s: %w", m.Config, err)
+	}
+
+	result, err := configuration.MigrateConfig(content, format)
+	if err != nil {
+		return fmt.Errorf("failed to migrate configuration file %s: %w", m.Config, err)
+	}
+
+	if !m.Force {
+		if _, err := os.Stat(m.Output); err == nil {
+			return fmt.Errorf("output file already exists at %s. Use the --force flag to overwrite", m.Output)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(m.Output), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for output file: %w", err)
+	}
+	if err := os.WriteFile(m.Output, result.Content, 0o600); err != nil {
+		return fmt.Errorf("failed to write upgraded configuration: %w", err)
+	}
+
+	if !result.Migrated() {
+		logger.Info(fmt.Sprintf("✅ Configuration %s is already at version %s, copied to %s", m.Config, result.ToVersion, m.Output))
+		return nil
+	}
+
+	for _, change := range result.Changes {
+		logger.Info(fmt.Sprintf("Migrated %s", change))
+	}
+	logger.Info(fmt.Sprintf("✅ Configuration migrated from version %s to %s and written to %s",
+		result.FromVersion, result.ToVersion, m.Output))
+	return nil
+}
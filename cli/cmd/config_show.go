@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"gopkg.in/yaml.v3"
+)
+
+// ShowCmd represents the command printing a configuration file as aws-taggy reads it
+type ShowCmd struct {
+	Config  string `help:"Path to the tag compliance configuration file" required:"true"`
+	Resolve bool   `help:"Show every resource with its tag criteria merged onto their template and the whole tag validation rule set it is checked against when it overrides the global one, and every compliance level with the tags it inherits through extends"`
+}
+
+// Run implements the logic for printing the configuration
+func (s *ShowCmd) Run() error {
+	// Parse the configuration as written, LoadConfig would merge the tag criteria templates
+	loader := configuration.NewTaggyScanConfigLoader()
+	cfg, err := loader.ParseConfig(s.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration file %s: %w", s.Config, err)
+	}
+
+	validator, err := configuration.NewContentValidator(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize configuration validator for file %s: %w", s.Config, err)
+	}
+	if err := validator.ValidateContent(); err != nil {
+		return fmt.Errorf("failed to load configuration file %s: configuration validation failed: %w", s.Config, err)
+	}
+
+	if s.Resolve {
+		if err := cfg.ResolveTagCriteriaTemplates(); err != nil {
+			return fmt.Errorf("failed to resolve tag criteria templates: %w", err)
+		}
+		cfg.ResolveTagValidationOverrides()
+
+		levels, err := cfg.ResolvedComplianceLevels()
+		if err != nil {
+			return fmt.Errorf("failed to resolve compliance levels: %w", err)
+		}
+		cfg.ComplianceLevels = levels
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to render configuration: %w", err)
+	}
+
+	fmt.Print(string(data))
+	return nil
+}
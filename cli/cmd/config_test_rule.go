@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/cli/internal/output"
+	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+)
+
+// TestRuleCmd represents the command running candidate values of a tag through the rules of
+// a configuration file
+type TestRuleCmd struct {
+	Config     string `help:"Path to the tag compliance configuration file" required:"true"`
+	Tag        string `help:"Tag whose rules the values are tested against" required:"true"`
+	Value      string `help:"Value to test"`
+	ValuesFile string `help:"File of values to test, one per line, printed as a pass/fail matrix" type:"path"`
+	Output     string `help:"Output format (${output_formats})" default:"table"`
+}
+
+// ruleTestResult is the outcome of every rule of the tag for a tested value
+type ruleTestResult struct {
+	Value  string                 `json:"value" yaml:"value"`
+	Passed bool                   `json:"passed" yaml:"passed"`
+	Checks []compliance.RuleCheck `json:"checks" yaml:"checks"`
+}
+
+// ruleTestReport is the machine-readable outcome of test-rule
+type ruleTestReport struct {
+	File    string           `json:"file" yaml:"file"`
+	Tag     string           `json:"tag" yaml:"tag"`
+	Results []ruleTestResult `json:"results" yaml:"results"`
+}
+
+// Run loads the configuration and prints which rules of the tag the values pass or fail
+func (t *TestRuleCmd) Run() error {
+	formatter, err := output.NewFormatter(t.Output)
+	if err != nil {
+		return err
+	}
+
+	values, err := t.values()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := configuration.NewTaggyScanConfigLoader().LoadConfig(t.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration file %s: %w", t.Config, err)
+	}
+	validator := compliance.NewTagValidator(cfg)
+
+	report := ruleTestReport{File: t.Config, Tag: t.Tag}
+	failed := 0
+	for _, value := range values {
+		result := ruleTestResult{Value: value, Passed: true, Checks: validator.TestValue(t.Tag, value)}
+		for _, check := range result.Checks {
+			result.Passed = result.Passed && check.Passed
+		}
+		if !result.Passed {
+			failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	if len(report.Results[0].Checks) == 0 {
+		return fmt.Errorf("no rule of configuration file %s applies to the values of tag %s", t.Config, t.Tag)
+	}
+
+	if formatter.IsStructured() {
+		if err := formatter.Output(report); err != nil {
+			return fmt.Errorf("failed to output rule test report for tag %s: %w", t.Tag, err)
+		}
+	} else if t.ValuesFile != "" {
+		err = renderRuleMatrix(report)
+	} else {
+		err = renderRuleChecks(report.Results[0])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to render rule test results for tag %s: %w", t.Tag, err)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d value(s) fail the rules of tag %s", failed, len(values), t.Tag)
+	}
+	return nil
+}
+
+// values returns the value of --value or the non-empty lines of --values-file
+func (t *TestRuleCmd) values() ([]string, error) {
+	if (t.Value == "") == (t.ValuesFile == "") {
+		return nil, fmt.Errorf("either --value or --values-file is required")
+	}
+	if t.Value != "" {
+		return []string{t.Value}, nil
+	}
+
+	content, err := os.ReadFile(t.ValuesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read values file %s: %w", t.ValuesFile, err)
+	}
+
+	var values []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if value := strings.TrimRight(line, "\r"); strings.TrimSpace(value) != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("values file %s holds no value", t.ValuesFile)
+	}
+	return values, nil
+}
+
+// renderRuleChecks prints a row per rule of the tag for a single value
+func renderRuleChecks(result ruleTestResult) error {
+	tableData := make([][]string, 0, len(result.Checks))
+	for _, check := range result.Checks {
+		status, message := "✅ pass", ""
+		if !check.Passed {
+			status, message = "❌ fail", check.Message
+		}
+		tableData = append(tableData, []string{check.Rule, check.Constraint, status, message})
+	}
+
+	return tui.RenderTable(tui.TableOptions{
+		Title: fmt.Sprintf("🧪 Rules for value '%s'", result.Value),
+		Columns: []tui.Column{
+			{Title: "Rule", Width: 20},
+			{Title: "Constraint", Width: 30, Flexible: true},
+			{Title: "Result", Width: 10},
+			{Title: "Message", Width: 50, Flexible: true},
+		},
+		AutoWidth: true,
+	}, tableData)
+}
+
+// renderRuleMatrix prints a row per value and a column per rule of the tag
+func renderRuleMatrix(report ruleTestReport) error {
+	columns := []tui.Column{{Title: "Value", Width: 30, Flexible: true}}
+	for _, check := range report.Results[0].Checks {
+		columns = append(columns, tui.Column{Title: check.Rule, Width: len(check.Rule) + 2})
+	}
+	columns = append(columns, tui.Column{Title: "Result", Width: 10})
+
+	passed := 0
+	tableData := make([][]string, 0, len(report.Results))
+	for _, result := range report.Results {
+		row := []string{result.Value}
+		for _, check := range result.Checks {
+			row = append(row, passMark(check.Passed))
+		}
+		row = append(row, passMark(result.Passed))
+		tableData = append(tableData, row)
+		if result.Passed {
+			passed++
+		}
+	}
+
+	return tui.RenderTable(tui.TableOptions{
+		Title:     fmt.Sprintf("🧪 Rules of tag %s (%d of %d values pass)", report.Tag, passed, len(report.Results)),
+		Columns:   columns,
+		AutoWidth: true,
+	}, tableData)
+}
+
+// passMark renders the outcome of a rule in a matrix cell
+func passMark(passed bool) string {
+	if passed {
+		return "✅"
+	}
+	return "❌"
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/Excoriate/aws-taggy/cli/internal/output"
@@ -13,23 +15,34 @@
 // ValidateCmd represents the validate subcommand
 type ValidateCmd struct {
 	Config    string `help:"Path to the tag validation configuration file" required:"true"`
-	Output    string `help:"Output format (table|json|yaml)" default:"table" enum:"table,json,yaml,TABLE,JSON,YAML"`
+	Output    string `help:"Output format (${output_formats})" default:"table"`
 	Table     bool   `help:"Display detailed information in tables" default:"false"`
 	Clipboard bool   `help:"Copy output to clipboard" default:"false"`
+	Format    string `help:"Format of the validation errors report, json lists every error with the path of the offending setting (text|json)" default:"text" enum:"text,json"`
+}
+
+// validationReport is the machine-readable list of problems found in a configuration file
+type validationReport struct {
+	File   string                          `json:"file"`
+	Valid  bool                            `json:"valid"`
+	Errors []configuration.ValidationIssue `json:"errors"`
 }
 
 // Run method for ValidateCmd implements the configuration validation logic
 func (v *ValidateCmd) Run() error {
+	formatter, err := output.NewFormatter(v.Output)
+	if err != nil {
+		return err
+	}
+
 	logger := o11y.DefaultLogger()
 	logger.Info(fmt.Sprintf("🔍 Validating configuration file: %s", v.Config))
 
-	// Initialize configuration loader and validator
+	// Parse the configuration without validating it, so every content problem is reported
 	loader := configuration.NewTaggyScanConfigLoader()
-
-	// Load configuration
-	cfg, err := loader.LoadConfig(v.Config)
+	cfg, err := loader.ParseConfig(v.Config)
 	if err != nil {
-		return fmt.Errorf("failed to load configuration file %s: %w", v.Config, err)
+		return v.reportInvalid(configuration.Issues(err))
 	}
 
 	// Initialize config validator
@@ -38,19 +51,24 @@ func (v *ValidateCmd) Run() error {
 		return fmt.Errorf("failed to initialize configuration validator for file %s: %w", v.Config, err)
 	}
 
+	// Collect every validation problem instead of stopping at the first one
+	issues := configuration.Issues(validator.ValidateContent())
+	if v.Format == "json" {
+		return v.outputReport(issues)
+	}
+
 	// Prepare validation result
 	result := output.ValidationResult{
 		File:    v.Config,
-		Valid:   true,
+		Valid:   len(issues) == 0,
 		Status:  "valid",
 		Version: cfg.Version,
 	}
-
-	// Perform validation
-	if err := validator.ValidateContent(); err != nil {
-		result.Valid = false
+	if !result.Valid {
 		result.Status = "invalid"
-		result.Errors = append(result.Errors, err.Error())
+		for _, issue := range issues {
+			result.Errors = append(result.Errors, issue.Error())
+		}
 	}
 
 	// Collect resource statistics and global config
@@ -93,18 +111,15 @@ func (v *ValidateCmd) Run() error {
 		if err := output.WriteToClipboard(result); err != nil {
 			return fmt.Errorf("failed to copy validation result to clipboard for file %s: %w", v.Config, err)
 		}
-		fmt.Println("✅ Validation result copied to clipboard!")
-		return nil
+		logger.Info("✅ Validation result copied to clipboard!")
+		return invalidConfigError(v.Config, issues)
 	}
 
-	// Create output formatter
-	formatter := output.NewFormatter(v.Output)
-
 	if formatter.IsStructured() {
 		if err := formatter.Output(result); err != nil {
 			return fmt.Errorf("failed to output structured validation result for file %s: %w", v.Config, err)
 		}
-		return nil
+		return invalidConfigError(v.Config, issues)
 	}
 
 	// If table view is requested
@@ -132,6 +147,17 @@ func (v *ValidateCmd) Run() error {
 			[]string{"Batch Size", fmt.Sprintf("%d", result.GlobalConfig.BatchSize)},
 		)
 
+		// Add errors if any, numbered so they can be fixed one by one
+		if len(result.Errors) > 0 {
+			numbered := make([]string, len(result.Errors))
+			for i, validationErr := range result.Errors {
+				numbered[i] = fmt.Sprintf("%d. %s", i+1, validationErr)
+			}
+			tableData = append(tableData,
+				[]string{"Errors", strings.Join(numbered, "\n")},
+			)
+		}
+
 		// Add warnings if any
 		if len(result.Warnings) > 0 {
 			tableData = append(tableData,
@@ -152,7 +178,7 @@ func (v *ValidateCmd) Run() error {
 		if err := tui.RenderTable(tableOpts, tableData); err != nil {
 			return fmt.Errorf("failed to render validation results table for file %s: %w", v.Config, err)
 		}
-		return nil
+		return invalidConfigError(v.Config, issues)
 	}
 
 	// Default console output
@@ -162,3 +188,50 @@ func (v *ValidateCmd) Run() error {
 
 	return nil
 }
+
+// reportInvalid reports problems found before the content could be validated, such as a
+// missing file or malformed YAML or JSON
+func (v *ValidateCmd) reportInvalid(issues []configuration.ValidationIssue) error {
+	if v.Format == "json" {
+		return v.outputReport(issues)
+	}
+
+	result := output.ValidationResult{
+		File:   v.Config,
+		Status: "invalid",
+	}
+	for _, issue := range issues {
+		result.Errors = append(result.Errors, issue.Error())
+	}
+
+	return output.RenderDefaultOutput(&result)
+}
+
+// outputReport prints the JSON validation report, failing when any problem was found
+func (v *ValidateCmd) outputReport(issues []configuration.ValidationIssue) error {
+	report := validationReport{
+		File:   v.Config,
+		Valid:  len(issues) == 0,
+		Errors: issues,
+	}
+	if report.Errors == nil {
+		report.Errors = []configuration.ValidationIssue{}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to output validation report for file %s: %w", v.Config, err)
+	}
+
+	return invalidConfigError(v.Config, issues)
+}
+
+// invalidConfigError returns an error when the configuration has problems, so the command
+// exits with a non-zero status
+func invalidConfigError(file string, issues []configuration.ValidationIssue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	return fmt.Errorf("configuration file %s is invalid: %d problem(s) found", file, len(issues))
+}
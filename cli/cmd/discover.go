@@ -3,12 +3,16 @@
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/Excoriate/aws-taggy/cli/internal/normaliser"
 	"github.com/Excoriate/aws-taggy/cli/internal/tui"
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
 	"github.com/Excoriate/aws-taggy/pkg/inspector"
 	"github.com/Excoriate/aws-taggy/pkg/o11y"
 	"github.com/Excoriate/aws-taggy/pkg/output"
@@ -17,210 +21,355 @@
 
 // DiscoverCmd represents the discover subcommand
 type DiscoverCmd struct {
-	Service   string `help:"AWS service to discover (e.g., s3, ec2)" required:"true"`
-	Region    string `help:"AWS region to discover resources in" default:"us-east-1"`
-	WithARN   bool   `help:"Include ARN in the output"`
-	Output    string `help:"Output format (table|json|yaml|yml)" default:"table" enum:"table,json,yaml,yml,TABLE,JSON,YAML,YML"`
-	Untagged  bool   `help:"Only show resources without tags"`
-	Clipboard bool   `help:"Copy the output to the clipboard"`
+	Service        string        `help:"AWS service to discover (e.g., s3, ec2), required unless --all-services is set"`
+	AllServices    bool          `help:"Discover every resource type enabled in the configuration given with --config, in its regions"`
+	Region         []string      `help:"AWS regions to discover resources in, comma-separated or repeated (e.g. us-east-1,eu-west-1)" default:"us-east-1"`
+	AllRegions     bool          `help:"Discover resources in every AWS region, instead of the regions of --region"`
+	WithARN        bool          `help:"Include ARN in the output"`
+	Output         string        `help:"Output format (${output_formats})" default:"table"`
+	Untagged       bool          `help:"Only show resources without tags"`
+	Orphaned       bool          `help:"Only show resources nothing uses, such as EBS volumes attached to no instance"`
+	Clipboard      bool          `help:"Copy the output to the clipboard"`
+	Config         string        `help:"Optional tag compliance configuration file whose exclusion patterns are applied" type:"path"`
+	ShowExcluded   bool          `help:"Also list resources skipped by exclusion patterns, with the reason"`
+	Timeout        time.Duration `help:"Abort the discovery after this duration (e.g. 5m), unbounded when 0" default:"0"`
+	CacheDir       string        `help:"Cache discovered resources in this directory (e.g. ~/.aws-taggy/cache) and reuse them on later runs"`
+	CacheTTL       time.Duration `help:"How long cached discovery results are reused" default:"30m"`
+	NoCache        bool          `help:"Ignore the scan cache and always call AWS"`
+	Set            []string      `help:"Override a setting of the discovery configuration, e.g. --set aws.batch_size=50 (repeatable, lists are comma-separated)" placeholder:"PATH=VALUE" sep:"none"`
+	FilterTag      []string      `help:"Only list resources whose tags match: key (present), key=value or key!=value (repeatable, all must match)" placeholder:"SELECTOR" sep:"none"`
+	InstanceStates []string      `help:"Only list EC2 instances in these states (e.g. running,stopped,pending), running and stopped ones when unset" placeholder:"STATE"`
+	IncludeRaw     bool          `help:"Add the raw AWS API response of every resource to the JSON and YAML output, always calling AWS as raw responses are not cached"`
+	StateDB        string        `help:"Record the tags of every discovered resource in this state file (e.g. ~/.aws-taggy/state.db), see history show"`
+	CreatedAfter   string        `help:"Only list resources created after this date (YYYY-MM-DD or RFC 3339)" placeholder:"DATE"`
+	MinAge         string        `help:"Only list resources at least this old, in days (e.g. 30d) or as a duration (e.g. 720h)" placeholder:"AGE"`
+	DryRunEstimate bool          `help:"Only count the resources with the cheap list and describe calls, printing an estimate of the resources and AWS API calls of the discovery per region, without reading tags"`
+	Match          string        `help:"Only list resources whose ID, name or ARN matches this regular expression (e.g. '^prod-')" placeholder:"REGEX"`
+	Exclude        string        `help:"Leave out resources whose ID, name or ARN matches this regular expression" placeholder:"REGEX"`
+	Limit          int           `help:"Only list the first N resources (of each service with --all-services), noting how many were discovered; structured output is then marked truncated" placeholder:"N"`
+	SummaryOnly    bool          `help:"Only print the number of resources per region, tagged and untagged, without listing them"`
+	Concurrency    int           `help:"Cap the concurrent AWS operations and the workers of every service to N for this run, overriding max_concurrency and workers of the configuration; the discovery still backs off from AWS throttling below it" default:"0" placeholder:"N"`
+
+	ExcludeUnknownAge bool `help:"Leave out resources whose creation time is unknown, which --created-after and --min-age keep otherwise"`
+}
+
+// ResourceRow is a discovered resource, as listed by discover
+type ResourceRow struct {
+	ID              string `json:"id" yaml:"id"`
+	Name            string `json:"name,omitempty" yaml:"name,omitempty"`
+	Region          string `json:"region" yaml:"region"`
+	HasTags         bool   `json:"has_tags" yaml:"has_tags"`
+	TagCount        int    `json:"tag_count" yaml:"tag_count"`
+	ARN             string `json:"arn,omitempty" yaml:"arn,omitempty"`
+	Excluded        bool   `json:"excluded,omitempty" yaml:"excluded,omitempty"`
+	ExclusionReason string `json:"exclusion_reason,omitempty" yaml:"exclusion_reason,omitempty"`
+
+	RawResponse map[string]interface{} `json:"raw_response,omitempty" yaml:"raw_response,omitempty"`
+}
+
+// DiscoveryResult holds the resources discovered for a service, with their counts
+type DiscoveryResult struct {
+	Service           string        `json:"service" yaml:"service"`
+	Region            string        `json:"region,omitempty" yaml:"region,omitempty"`
+	TotalResources    int           `json:"total_resources" yaml:"total_resources"`
+	TaggedResources   int           `json:"tagged_resources" yaml:"tagged_resources"`
+	UntaggedResources int           `json:"untagged_resources" yaml:"untagged_resources"`
+	ExcludedResources int           `json:"excluded_resources" yaml:"excluded_resources"`
+	Truncated         bool          `json:"truncated,omitempty" yaml:"truncated,omitempty"`
+	Resources         []ResourceRow `json:"resources,omitempty" yaml:"resources,omitempty"`
+
+	// OutOfRegionResources counts the S3 buckets skipped as located outside the regions
+	OutOfRegionResources int `json:"out_of_region_resources,omitempty" yaml:"out_of_region_resources,omitempty"`
+
+	// Limit is the --limit the resources were cut to, the counts covering every resource.
+	// RegionCounts replace the resources with --summary-only.
+	Limit        int           `json:"limit,omitempty" yaml:"limit,omitempty"`
+	RegionCounts []RegionCount `json:"region_counts,omitempty" yaml:"region_counts,omitempty"`
+
+	// Regions are the regions of a single service discovery, Region being set when there is
+	// only one. EmptyRegions are those where no resource is listed, and Errors hold the
+	// regions that could not be discovered.
+	Regions      []string                 `json:"regions,omitempty" yaml:"regions,omitempty"`
+	EmptyRegions []string                 `json:"empty_regions,omitempty" yaml:"empty_regions,omitempty"`
+	Errors       []inspector.ServiceError `json:"errors,omitempty" yaml:"errors,omitempty"`
+
+	// NameFilters are the --match and --exclude expressions the resources were filtered by
+	NameFilters []string `json:"name_filters,omitempty" yaml:"name_filters,omitempty"`
+
+	// regionCounts are the counts per region, kept for --summary-only
+	regionCounts map[string]*RegionCount
 }
 
 // Run method for DiscoverCmd implements the resource discovery logic
-func (d *DiscoverCmd) Run() error {
+func (d *DiscoverCmd) Run(ctx context.Context) error {
 	// Initialize logger
 	logger := o11y.DefaultLogger()
 
+	if d.AllServices {
+		if d.Service != "" {
+			return fmt.Errorf("--service and --all-services cannot be used together")
+		}
+		if d.Config == "" {
+			return fmt.Errorf("--all-services requires --config, whose enabled resource types are discovered")
+		}
+		if d.AllRegions {
+			return fmt.Errorf("--all-regions cannot be used with --all-services, which discovers the regions of --config")
+		}
+	} else if d.Service == "" {
+		return fmt.Errorf("a service is required, set --service or use --all-services with --config")
+	}
+
+	if err := validateConcurrency(d.Concurrency); err != nil {
+		return err
+	}
+
+	if err := d.validateListing(); err != nil {
+		return err
+	}
+
+	// Unknown output formats fail before any AWS call
+	if _, err := structuredFormatter(d.Output); err != nil {
+		return err
+	}
+
+	tagSelectors, err := inspector.ParseTagSelectors(d.FilterTag)
+	if err != nil {
+		return err
+	}
+
+	ageFilter, err := parseAgeFilter(d.CreatedAfter, d.MinAge, d.ExcludeUnknownAge)
+	if err != nil {
+		return err
+	}
+
+	nameFilter, err := parseNameFilter(d.Match, d.Exclude)
+	if err != nil {
+		return err
+	}
+
+	if d.AllServices {
+		scanCtx, cancel := withTimeout(ctx, d.Timeout)
+		defer cancel()
+		return d.discoverAllServices(scanCtx, tagSelectors, ageFilter, nameFilter, logger)
+	}
+
 	// Normalize service name
 	d.Service = normaliser.NormalizeServiceName(d.Service)
 
-	// Normalize output format to lowercase
-	d.Output = normaliser.NormalizeOutputFormat(d.Output)
+	if len(d.InstanceStates) > 0 && d.Service != constants.ResourceTypeEC2 {
+		return fmt.Errorf("--instance-states only applies to the %s service", constants.ResourceTypeEC2)
+	}
+	if d.Orphaned && d.Service != constants.ResourceTypeEBS {
+		return fmt.Errorf("--orphaned only applies to the %s service", constants.ResourceTypeEBS)
+	}
 
 	// Validate service
 	if err := configuration.IsSupportedAWSResource(d.Service); err != nil {
 		return fmt.Errorf("service %s is not supported: %w", d.Service, err)
 	}
 
-	// Create a custom configuration for the specific service and region
+	regions, err := d.discoveryRegions()
+	if err != nil {
+		return err
+	}
+
+	// Create a custom configuration for the specific service and regions
 	customConfig := configuration.TaggyScanConfig{
 		AWS: configuration.AWSConfig{
 			Regions: configuration.RegionsConfig{
 				Mode: "specific",
-				List: []string{d.Region},
 			},
 		},
 		Resources: map[string]configuration.ResourceConfig{
 			d.Service: {
 				Enabled: true,
-				Regions: []string{d.Region},
 			},
 		},
 	}
+	setDiscoveryRegions(&customConfig, d.Service, d.scannedRegions(regions))
+	d.restrictBucketRegions(&customConfig, regions)
+
+	// Apply the exclusion patterns and the role of the service when a configuration file is given
+	if d.Config != "" {
+		loader := configuration.NewTaggyScanConfigLoader()
+		fileConfig, err := loader.LoadConfig(d.Config)
+		if err != nil {
+			return fmt.Errorf("failed to load configuration from file %s: %w", d.Config, err)
+		}
+
+		if resourceConfig, ok := fileConfig.Resources[d.Service]; ok {
+			serviceConfig := customConfig.Resources[d.Service]
+			serviceConfig.ExcludedResources = resourceConfig.ExcludedResources
+			serviceConfig.IncludeInstanceStates = resourceConfig.IncludeInstanceStates
+			serviceConfig.IncludeSnapshots = resourceConfig.IncludeSnapshots
+			serviceConfig.RoleARN = resourceConfig.RoleARN
+			serviceConfig.ExternalID = resourceConfig.ExternalID
+			customConfig.Resources[d.Service] = serviceConfig
+		}
+	} else if d.ShowExcluded {
+		logger.Warn("--show-excluded has no effect without --config, no exclusion patterns are defined")
+	}
+
+	// Settings given through the environment or --set apply to the discovery configuration
+	if err := d.applyOverrides(&customConfig); err != nil {
+		return err
+	}
+	if err := d.applyInstanceStates(&customConfig); err != nil {
+		return err
+	}
 
 	// Create Taggy client with empty config since we'll use our custom config
 	client, err := taggy.NewWithConfig(&customConfig)
 	if err != nil {
-		return fmt.Errorf("failed to create Taggy client with custom configuration for service %s in region %s: %w", d.Service, d.Region, err)
+		return fmt.Errorf("failed to create Taggy client with custom configuration for service %s in %s: %w", d.Service, describeRegions(regions), err)
 	}
 
 	// Perform resource discovery
-	return d.discoverResources(client, logger)
+	scanCtx, cancel := withTimeout(ctx, d.Timeout)
+	defer cancel()
+	return d.discoverResources(scanCtx, client, regions, tagSelectors, ageFilter, nameFilter, logger)
 }
 
-// discoverResources performs resource discovery for a specific service and region
-func (d *DiscoverCmd) discoverResources(client *taggy.TaggyClient, logger *o11y.Logger) error {
-	ctx := context.Background()
+// applyOverrides applies the settings given through the environment or --set to a
+// discovery configuration
+func (d *DiscoverCmd) applyOverrides(cfg *configuration.TaggyScanConfig) error {
+	overrides, err := configuration.ParseOverrides(d.Set)
+	if err != nil {
+		return err
+	}
+	envOverrides, err := configuration.EnvOverrides(os.Environ())
+	if err != nil {
+		return err
+	}
+	return configuration.ApplyOverrides(cfg, append(envOverrides, overrides...))
+}
 
-	logger.Info(fmt.Sprintf("🔍 Discovering %s resources in region %s", d.Service, d.Region))
+// applyInstanceStates restricts the EC2 instances of a discovery configuration to the states
+// given with --instance-states, when set
+func (d *DiscoverCmd) applyInstanceStates(cfg *configuration.TaggyScanConfig) error {
+	if len(d.InstanceStates) == 0 {
+		return nil
+	}
+
+	for _, state := range d.InstanceStates {
+		if !slices.Contains(configuration.ValidEC2InstanceStates(), state) {
+			return fmt.Errorf("invalid instance state %s, valid states are: %s",
+				state, strings.Join(configuration.ValidEC2InstanceStates(), ", "))
+		}
+	}
+
+	ec2Config := cfg.Resources[constants.ResourceTypeEC2]
+	ec2Config.IncludeInstanceStates = d.InstanceStates
+	cfg.Resources[constants.ResourceTypeEC2] = ec2Config
+	return nil
+}
+
+// discoverResources performs resource discovery for a specific service in the given regions
+func (d *DiscoverCmd) discoverResources(ctx context.Context, client *taggy.TaggyClient, regions []string, tagSelectors []inspector.TagSelector, ageFilter inspector.AgeFilter, nameFilter inspector.NameFilter, logger *o11y.Logger) error {
+	where := describeRegions(regions)
+	logger.Info(fmt.Sprintf("🔍 Discovering %s resources in %s", d.Service, where))
 
 	// Create a inspector manager
 	inspectorManager, err := inspector.NewInspectorManagerFromConfig(*client.Config())
 	if err != nil {
-		return fmt.Errorf("failed to create inspector manager for service %s in region %s: %w", d.Service, d.Region, err)
+		return fmt.Errorf("failed to create inspector manager for service %s in %s: %w", d.Service, where, err)
 	}
+	inspectorManager.SetConcurrency(d.Concurrency)
+	logCallerIdentity(ctx, inspectorManager, logger)
 
-	// Perform the scan
-	if err := inspectorManager.Inspect(ctx); err != nil {
-		return fmt.Errorf("resource discovery failed for service %s in region %s: %w", d.Service, d.Region, err)
+	if d.DryRunEstimate {
+		return d.estimate(ctx, inspectorManager)
 	}
 
-	// Process discovery results
-	inspectResults := inspectorManager.GetResults()
-
-	// Prepare table data
-	type ResourceRow struct {
-		ID       string `json:"id" yaml:"id"`
-		Region   string `json:"region" yaml:"region"`
-		HasTags  bool   `json:"has_tags" yaml:"has_tags"`
-		TagCount int    `json:"tag_count" yaml:"tag_count"`
-		ARN      string `json:"arn,omitempty" yaml:"arn,omitempty"`
+	// Raw responses are not cached, discoveries including them always call AWS
+	cache, err := newScanCache(d.CacheDir, d.CacheTTL, d.NoCache || d.IncludeRaw)
+	if err != nil {
+		return err
 	}
 
-	var totalResources, resourcesWithTags int
-	var resourceRows []ResourceRow
+	// Perform the scan, regions that fail being reported with the results of the others
+	cfg := *client.Config()
+	scanned, regionErrors, err := d.inspectRegions(ctx, cfg.AWS.Regions.List, d.regionScanner(inspectorManager, cfg, cache), logger)
+	if err != nil {
+		return fmt.Errorf("resource discovery failed for service %s in %s: %w", d.Service, where, err)
+	}
 
-	// Process all resources regardless of region for S3 buckets
-	if d.Service == "s3" {
-		for _, result := range inspectResults {
-			for _, resource := range result.Resources {
-				hasTags := len(resource.Tags) > 0
+	// Process discovery results, keeping the resources selected by their tags, creation time
+	// and name
+	inspectResults := selectDiscovered(scanned, tagSelectors, ageFilter, nameFilter)
+	if d.StateDB != "" {
+		recordHistory(d.StateDB, discoverySnapshots(inspectResults), logger)
+	}
 
-				// Skip if we're only looking for untagged resources and this one has tags
-				if d.Untagged && hasTags {
-					continue
-				}
+	discovery := DiscoveryResult{
+		Service:     d.Service,
+		Errors:      regionErrors,
+		NameFilters: nameFilter.Filters(),
+	}
 
-				resourceRows = append(resourceRows, ResourceRow{
-					ID:       resource.ID,
-					Region:   resource.Region,
-					HasTags:  hasTags,
-					TagCount: len(resource.Tags),
-					ARN:      resource.Details.ARN,
-				})
+	// Results only hold the service scanned, under the account of its role when it has one,
+	// S3 buckets being listed whatever their region
+	if result, exists := inspectResults[inspector.ServiceResultKey(*client.Config(), d.Service)]; exists {
+		d.addResult(&discovery, result)
+	}
+	d.summarizeRegions(&discovery, regions)
 
-				if hasTags {
-					resourcesWithTags++
-				}
-				totalResources++
-			}
-		}
-	} else {
-		// For non-S3 resources, filter by specified region
-		result, exists := inspectResults[d.Region]
-		if !exists {
-			logger.Info(fmt.Sprintf("No %s resources found in region %s", d.Service, d.Region))
-			return nil
-		}
+	truncated := truncatedError(inspector.TruncatedResults(inspectResults))
+	if truncated != nil {
+		discovery.Truncated = true
+		logger.Warn(fmt.Sprintf("⚠️  Only part of the %s resources were discovered: the max_resources_per_service or max_api_calls limit was hit", d.Service))
+	}
 
-		for _, resource := range result.Resources {
-			hasTags := len(resource.Tags) > 0
-
-			// Skip if we're only looking for untagged resources and this one has tags
-			if d.Untagged && hasTags {
-				continue
-			}
-
-			resourceRows = append(resourceRows, ResourceRow{
-				ID:       resource.ID,
-				Region:   d.Region,
-				HasTags:  hasTags,
-				TagCount: len(resource.Tags),
-				ARN:      resource.Details.ARN,
-			})
-
-			if hasTags {
-				resourcesWithTags++
-			}
-			totalResources++
-		}
+	// Structured output, such as JSON or YAML, is rendered by the registered formatter
+	formatter, err := structuredFormatter(d.Output)
+	if err != nil {
+		return err
 	}
 
-	// Check if we found any resources after filtering
-	if len(resourceRows) == 0 {
+	// Check if we found any resources after filtering, structured output still printing an
+	// empty document so that it can always be parsed
+	if len(discovery.Resources) == 0 {
 		if d.Untagged {
-			logger.Info(fmt.Sprintf("No untagged %s resources found in region %s", d.Service, d.Region))
+			logger.Info(fmt.Sprintf("No untagged %s resources found in %s", d.Service, where))
+		} else if d.Orphaned {
+			logger.Info(fmt.Sprintf("No orphaned %s resources found in %s", d.Service, where))
 		} else {
-			logger.Info(fmt.Sprintf("No %s resources found in region %s", d.Service, d.Region))
+			logger.Info(fmt.Sprintf("No %s resources found in %s", d.Service, where))
 		}
-		return nil
-	}
-
-	// Prepare clipboard output (always in YAML)
-	type DiscoveryResult struct {
-		Service           string        `json:"service" yaml:"service"`
-		Region            string        `json:"region" yaml:"region"`
-		TotalResources    int           `json:"total_resources" yaml:"total_resources"`
-		TaggedResources   int           `json:"tagged_resources" yaml:"tagged_resources"`
-		UntaggedResources int           `json:"untagged_resources" yaml:"untagged_resources"`
-		Resources         []ResourceRow `json:"resources" yaml:"resources"`
+		if formatter == nil {
+			printRegionErrors(discovery.Errors)
+			return truncated
+		}
+	} else if len(discovery.EmptyRegions) > 0 {
+		logger.Info(fmt.Sprintf("No %s resources found in %s", d.Service, describeRegions(discovery.EmptyRegions)))
 	}
 
-	clipboardOutput := DiscoveryResult{
-		Service:           d.Service,
-		Region:            d.Region,
-		TotalResources:    totalResources,
-		TaggedResources:   resourcesWithTags,
-		UntaggedResources: totalResources - resourcesWithTags,
-		Resources:         resourceRows,
+	// Only the counts are printed with --summary-only, and at most --limit resources otherwise
+	if d.SummaryOnly {
+		discovery.summarize()
 	}
+	listed := discovery.applyLimit(d.Limit)
 
 	// If clipboard flag is set, copy to clipboard in YAML
 	if d.Clipboard {
-		yamlFormatter := output.NewYAMLFormatter(false)
-		clipboardContent, err := yamlFormatter.Format(clipboardOutput)
-		if err != nil {
-			return fmt.Errorf("failed to format clipboard output: %w", err)
-		}
-
-		// Use system clipboard
-		cmd := exec.Command("pbcopy")
-		cmd.Stdin = strings.NewReader(clipboardContent)
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("failed to copy resource discovery results to clipboard: %w", err)
+		if err := copyDiscoveryToClipboard(discovery, logger); err != nil {
+			return err
 		}
-
-		logger.Info("✅ Resource discovery results copied to clipboard!")
 	}
 
-	// Create output formatter
-	var formatter output.Formatter
-	switch d.Output {
-	case "json":
-		formatter = output.NewJSONFormatter(false)
-	case "yaml", "yml":
-		formatter = output.NewYAMLFormatter(false)
-	default:
-		formatter = output.NewTableFormatter([]string{"Resource", "Region", "Has Tags", "Tag Count"})
+	if formatter != nil {
+		if err := printFormatted(formatter, discovery); err != nil {
+			return err
+		}
+		return truncated
 	}
 
-	// If using structured output (JSON/YAML), prepare the data structure
-	if d.Output == "json" || d.Output == "yaml" || d.Output == "yml" {
-		formattedOutput, err := formatter.Format(clipboardOutput)
-		if err != nil {
-			return fmt.Errorf("failed to format output: %w", err)
+	if d.SummaryOnly {
+		if err := d.renderRegionSummary(discovery); err != nil {
+			return err
 		}
-		fmt.Println(formattedOutput)
-		return nil
+		printRegionErrors(discovery.Errors)
+		return truncated
 	}
 
 	// Default table output
@@ -241,12 +390,31 @@ type DiscoveryResult struct {
 		})
 	}
 
+	if d.ShowExcluded {
+		columns = append(columns, tui.Column{
+			Title:    "Excluded",
+			Key:      "ExclusionReason",
+			Width:    40,
+			Flexible: true,
+			Align:    "left",
+		})
+	}
+
 	title := fmt.Sprintf("🏷️  %s Resource Discovery", d.Service)
 	if d.Untagged {
 		title = fmt.Sprintf("🏷️  Untagged %s Resources", d.Service)
 	}
-	title = fmt.Sprintf("%s (Total: %d, Tagged: %d, Untagged: %d)",
-		title, totalResources, resourcesWithTags, totalResources-resourcesWithTags)
+	if d.Orphaned {
+		title += " (orphaned only)"
+	}
+	if len(discovery.Regions) > 1 {
+		title = fmt.Sprintf("%s across %d regions", title, len(discovery.Regions))
+	}
+	title = fmt.Sprintf("%s (Total: %d, Tagged: %d, Untagged: %d, Excluded: %d)",
+		title, discovery.TotalResources, discovery.TaggedResources, discovery.UntaggedResources, discovery.ExcludedResources)
+	if discovery.OutOfRegionResources > 0 {
+		title = fmt.Sprintf("%s, %d buckets in other regions skipped", title, discovery.OutOfRegionResources)
+	}
 
 	tableOpts := tui.TableOptions{
 		Title:           title,
@@ -255,20 +423,142 @@ type DiscoveryResult struct {
 		AutoWidth:       true,
 	}
 
-	// Convert resourceRows to [][]string for RenderTable
-	tableData := make([][]string, len(resourceRows))
-	for i, row := range resourceRows {
-		rowData := []string{
-			row.ID,
-			row.Region,
-			fmt.Sprintf("%v", row.HasTags),
-			fmt.Sprintf("%d", row.TagCount),
+	// Convert the resource rows to [][]string for RenderTable
+	tableData := make([][]string, len(discovery.Resources))
+	for i, row := range discovery.Resources {
+		tableData[i] = d.tableRow(row)
+	}
+
+	if err := tui.RenderTable(tableOpts, tableData); err != nil {
+		return err
+	}
+	printLimitNote(len(discovery.Resources), listed)
+	printRegionErrors(discovery.Errors)
+	return truncated
+}
+
+// estimate prints the estimate of the discovery instead of discovering the resources
+func (d *DiscoverCmd) estimate(ctx context.Context, inspectorManager *inspector.InspectorManager) error {
+	estimates, err := inspectorManager.Estimate(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to estimate the discovery: %w", err)
+	}
+	return printScanEstimate(newScanEstimateReport(estimates, inspectorManager.GetErrors()), d.Output)
+}
+
+// addResult records the resources of an inspection result in a discovery, leaving out the
+// tagged ones when only untagged resources are listed, and the used ones when only orphaned
+// resources are. Resources are listed in their own region.
+func (d *DiscoverCmd) addResult(discovery *DiscoveryResult, result *inspector.InspectResult) {
+	for _, resource := range result.Resources {
+		hasTags := len(resource.Tags) > 0
+
+		// Skip if we're only looking for untagged resources and this one has tags
+		if d.Untagged && hasTags {
+			continue
 		}
-		if d.WithARN {
-			rowData = append(rowData, row.ARN)
+		if d.Orphaned && !resource.IsOrphaned() {
+			continue
 		}
-		tableData[i] = rowData
+
+		discovery.Resources = append(discovery.Resources, ResourceRow{
+			ID:          resource.ID,
+			Name:        resource.Details.Name,
+			Region:      resource.Region,
+			HasTags:     hasTags,
+			TagCount:    len(resource.Tags),
+			ARN:         resource.Details.ARN,
+			RawResponse: d.rawResponse(resource),
+		})
+
+		if hasTags {
+			discovery.TaggedResources++
+		} else {
+			discovery.UntaggedResources++
+		}
+		discovery.TotalResources++
+		discovery.countRegion(resource.Region, hasTags, false)
+	}
+
+	discovery.OutOfRegionResources += result.OutOfRegionResources
+
+	// Skipped resources are listed when --show-excluded is set
+	discovery.ExcludedResources += len(result.ExcludedResources)
+	for _, excluded := range result.ExcludedResources {
+		discovery.countRegion(excluded.Resource.Region, false, true)
+	}
+	if !d.ShowExcluded {
+		return
+	}
+
+	for _, excluded := range result.ExcludedResources {
+		reason := excluded.Reason
+		if reason == "" {
+			reason = fmt.Sprintf("matches pattern %s", excluded.Pattern)
+		}
+		discovery.Resources = append(discovery.Resources, ResourceRow{
+			ID:              excluded.Resource.ID,
+			Name:            excluded.Resource.Details.Name,
+			Region:          excluded.Resource.Region,
+			HasTags:         len(excluded.Resource.Tags) > 0,
+			TagCount:        len(excluded.Resource.Tags),
+			ARN:             excluded.Resource.Details.ARN,
+			Excluded:        true,
+			ExclusionReason: reason,
+			RawResponse:     d.rawResponse(excluded.Resource),
+		})
+	}
+}
+
+// rawResponse returns the raw API response of a resource when --include-raw is set
+func (d *DiscoverCmd) rawResponse(resource inspector.ResourceMetadata) map[string]interface{} {
+	if !d.IncludeRaw {
+		return nil
+	}
+	return resource.RawResponseMap()
+}
+
+// label returns the ID of the resource followed by its name, such as the name of a security
+// group, when the name is not the ID itself
+func (r ResourceRow) label() string {
+	if r.Name == "" || r.Name == r.ID || strings.HasSuffix(r.ID, r.Name) {
+		return r.ID
+	}
+	return fmt.Sprintf("%s (%s)", r.ID, r.Name)
+}
+
+// tableRow renders a resource row with the columns selected by the flags
+func (d *DiscoverCmd) tableRow(row ResourceRow) []string {
+	rowData := []string{
+		row.label(),
+		row.Region,
+		fmt.Sprintf("%v", row.HasTags),
+		fmt.Sprintf("%d", row.TagCount),
+	}
+	if d.WithARN {
+		rowData = append(rowData, row.ARN)
+	}
+	if d.ShowExcluded {
+		rowData = append(rowData, row.ExclusionReason)
+	}
+	return rowData
+}
+
+// copyDiscoveryToClipboard copies discovery results to the clipboard, always in YAML
+func copyDiscoveryToClipboard(discovery interface{}, logger *o11y.Logger) error {
+	yamlFormatter := output.NewYAMLFormatter(false)
+	clipboardContent, err := yamlFormatter.Format(discovery)
+	if err != nil {
+		return fmt.Errorf("failed to format clipboard output: %w", err)
+	}
+
+	// Use system clipboard
+	cmd := exec.Command("pbcopy")
+	cmd.Stdin = strings.NewReader(clipboardContent)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to copy resource discovery results to clipboard: %w", err)
 	}
 
-	return tui.RenderTable(tableOpts, tableData)
+	logger.Info("✅ Resource discovery results copied to clipboard!")
+	return nil
 }
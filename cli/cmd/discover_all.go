@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+)
+
+// AllServicesDiscovery holds the resources discovered for every enabled service, along with
+// the errors of the services that could not be discovered
+type AllServicesDiscovery struct {
+	TotalResources    int                         `json:"total_resources" yaml:"total_resources"`
+	TaggedResources   int                         `json:"tagged_resources" yaml:"tagged_resources"`
+	UntaggedResources int                         `json:"untagged_resources" yaml:"untagged_resources"`
+	ExcludedResources int                         `json:"excluded_resources" yaml:"excluded_resources"`
+	Services          map[string]*DiscoveryResult `json:"services" yaml:"services"`
+	Errors            []string                    `json:"errors,omitempty" yaml:"errors,omitempty"`
+	Truncated         []string                    `json:"truncated,omitempty" yaml:"truncated,omitempty"`
+	NameFilters       []string                    `json:"name_filters,omitempty" yaml:"name_filters,omitempty"`
+}
+
+// discoverAllServices discovers every resource type enabled in the configuration file, in the
+// regions it declares. Services failing to be discovered are reported in the errors of the
+// results instead of failing the command.
+func (d *DiscoverCmd) discoverAllServices(ctx context.Context, tagSelectors []inspector.TagSelector, ageFilter inspector.AgeFilter, nameFilter inspector.NameFilter, logger *o11y.Logger) error {
+	overrides, err := configuration.ParseOverrides(d.Set)
+	if err != nil {
+		return err
+	}
+
+	loader := configuration.NewTaggyScanConfigLoader()
+	loader.SetOverrides(overrides)
+	cfg, err := loader.LoadConfig(d.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration from file %s: %w", d.Config, err)
+	}
+
+	if len(d.InstanceStates) > 0 {
+		if !cfg.Resources[constants.ResourceTypeEC2].Enabled {
+			return fmt.Errorf("--instance-states requires the %s resource type to be enabled in %s", constants.ResourceTypeEC2, d.Config)
+		}
+		if err := d.applyInstanceStates(cfg); err != nil {
+			return err
+		}
+	}
+
+	if d.Orphaned && !cfg.Resources[constants.ResourceTypeEBS].Enabled {
+		return fmt.Errorf("--orphaned requires the %s resource type to be enabled in %s", constants.ResourceTypeEBS, d.Config)
+	}
+
+	var services []string
+	for resourceType, resourceConfig := range cfg.Resources {
+		if resourceConfig.Enabled {
+			services = append(services, resourceType)
+		}
+	}
+	if len(services) == 0 {
+		return fmt.Errorf("no resource type is enabled in the configuration file %s", d.Config)
+	}
+	sort.Strings(services)
+
+	logger.Info(fmt.Sprintf("🔍 Discovering resources of %d services: %s", len(services), strings.Join(services, ", ")))
+
+	inspectorManager, err := inspector.NewInspectorManagerFromConfig(*cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create inspector manager: %w", err)
+	}
+	inspectorManager.SetConcurrency(d.Concurrency)
+	logCallerIdentity(ctx, inspectorManager, logger)
+
+	if d.DryRunEstimate {
+		return d.estimate(ctx, inspectorManager)
+	}
+
+	cache, err := newScanCache(d.CacheDir, d.CacheTTL, d.NoCache || d.IncludeRaw)
+	if err != nil {
+		return err
+	}
+	inspectorManager.SetCache(cache)
+
+	// Failures of single services are reported with the results, only a cancelled scan fails
+	if err := inspectorManager.Inspect(ctx); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("resource discovery failed: %w", err)
+		}
+		logger.Warn(fmt.Sprintf("Some services could not be discovered: %v", err))
+	}
+
+	discovery := AllServicesDiscovery{
+		Services:    make(map[string]*DiscoveryResult),
+		Errors:      inspectorManager.GetErrors(),
+		NameFilters: nameFilter.Filters(),
+	}
+
+	// Results are keyed by service, or by account and service when scanning several accounts
+	inspectResults := selectDiscovered(inspectorManager.Results().ByService, tagSelectors, ageFilter, nameFilter)
+	if d.StateDB != "" {
+		recordHistory(d.StateDB, discoverySnapshots(inspectResults), logger)
+	}
+	for key, result := range inspectResults {
+		service := key[strings.LastIndex(key, "/")+1:]
+		serviceDiscovery, exists := discovery.Services[service]
+		if !exists {
+			serviceDiscovery = &DiscoveryResult{Service: service}
+			discovery.Services[service] = serviceDiscovery
+		}
+		d.addResult(serviceDiscovery, result)
+		serviceDiscovery.Truncated = serviceDiscovery.Truncated || result.Truncated
+	}
+	discovery.Truncated = inspector.TruncatedResults(inspectResults)
+
+	// Each service lists at most --limit resources, or only its counts with --summary-only
+	shown, listed := 0, 0
+	for _, serviceDiscovery := range discovery.Services {
+		discovery.TotalResources += serviceDiscovery.TotalResources
+		discovery.TaggedResources += serviceDiscovery.TaggedResources
+		discovery.UntaggedResources += serviceDiscovery.UntaggedResources
+		discovery.ExcludedResources += serviceDiscovery.ExcludedResources
+
+		if d.SummaryOnly {
+			serviceDiscovery.summarize()
+		}
+		listed += serviceDiscovery.applyLimit(d.Limit)
+		shown += len(serviceDiscovery.Resources)
+	}
+
+	if d.Clipboard {
+		if err := copyDiscoveryToClipboard(discovery, logger); err != nil {
+			return err
+		}
+	}
+
+	formatter, err := structuredFormatter(d.Output)
+	if err != nil {
+		return err
+	}
+	if formatter != nil {
+		if err := printFormatted(formatter, discovery); err != nil {
+			return err
+		}
+		return truncatedError(discovery.Truncated)
+	}
+
+	if d.SummaryOnly {
+		if err := d.renderAllServicesSummary(discovery); err != nil {
+			return err
+		}
+	} else {
+		if err := d.renderAllServicesTable(discovery); err != nil {
+			return err
+		}
+		printLimitNote(shown, listed)
+	}
+
+	if len(discovery.Errors) > 0 {
+		fmt.Println("\n⚠️  Errors:")
+		for _, discoveryErr := range discovery.Errors {
+			fmt.Printf("  • %s\n", discoveryErr)
+		}
+	}
+
+	return truncatedError(discovery.Truncated)
+}
+
+// renderAllServicesTable renders the discovered resources in a single table, grouped by
+// service, each service closed by a subtotal row
+func (d *DiscoverCmd) renderAllServicesTable(discovery AllServicesDiscovery) error {
+	columns := []tui.Column{
+		{Title: "Service", Key: "Service", Width: 12, Align: "left"},
+		{Title: "Resource", Key: "ID", Width: 60, Flexible: true, Align: "left"},
+		{Title: "Region", Key: "Region", Width: 15, Align: "center"},
+		{Title: "Has Tags", Key: "HasTags", Width: 12, Align: "center"},
+		{Title: "Tag Count", Key: "TagCount", Width: 12, Align: "center"},
+	}
+	if d.WithARN {
+		columns = append(columns, tui.Column{Title: "ARN", Key: "ARN", Width: 100, Align: "left"})
+	}
+	if d.ShowExcluded {
+		columns = append(columns, tui.Column{Title: "Excluded", Key: "ExclusionReason", Width: 40, Flexible: true, Align: "left"})
+	}
+
+	services := make([]string, 0, len(discovery.Services))
+	for service := range discovery.Services {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	var tableData [][]string
+	for _, service := range services {
+		serviceDiscovery := discovery.Services[service]
+		for _, row := range serviceDiscovery.Resources {
+			tableData = append(tableData, append([]string{service}, d.tableRow(row)...))
+		}
+
+		subtotal := make([]string, len(columns))
+		subtotal[0] = service
+		subtotal[1] = fmt.Sprintf("Subtotal: %d (Tagged: %d, Untagged: %d, Excluded: %d)",
+			serviceDiscovery.TotalResources, serviceDiscovery.TaggedResources,
+			serviceDiscovery.UntaggedResources, serviceDiscovery.ExcludedResources)
+		tableData = append(tableData, subtotal)
+	}
+
+	title := "🏷️  Resource Discovery"
+	if d.Untagged {
+		title = "🏷️  Untagged Resources"
+	}
+	title = fmt.Sprintf("%s across %d services (Total: %d, Tagged: %d, Untagged: %d, Excluded: %d)",
+		title, len(services), discovery.TotalResources, discovery.TaggedResources,
+		discovery.UntaggedResources, discovery.ExcludedResources)
+
+	return tui.RenderTable(tui.TableOptions{
+		Title:           title,
+		Columns:         columns,
+		FlexibleColumns: true,
+		AutoWidth:       true,
+	}, tableData)
+}
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+)
+
+// discoveryRegions returns the regions to discover: every AWS region with --all-regions,
+// otherwise the regions of --region, validated and without duplicates
+func (d *DiscoverCmd) discoveryRegions() ([]string, error) {
+	if d.AllRegions {
+		return configuration.ValidAWSRegions(), nil
+	}
+
+	var regions []string
+	for _, region := range d.Region {
+		region = strings.ToLower(strings.TrimSpace(region))
+		if region == "" || slices.Contains(regions, region) {
+			continue
+		}
+		if !configuration.IsValidRegion(region) {
+			return nil, fmt.Errorf("invalid region %s, valid regions are: %s",
+				region, strings.Join(configuration.ValidAWSRegions(), ", "))
+		}
+		regions = append(regions, region)
+	}
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("at least one region is required, set --region or --all-regions")
+	}
+	return regions, nil
+}
+
+// scannedRegions returns the regions the inspector of the service scans. S3 buckets are
+// listed whatever their region and global services belong to no region, so those are
+// scanned once, through the first region.
+func (d *DiscoverCmd) scannedRegions(regions []string) []string {
+	if d.Service == constants.ResourceTypeS3 || inspector.IsGlobalResourceType(d.Service) {
+		return regions[:1]
+	}
+	return regions
+}
+
+// restrictBucketRegions keeps the S3 buckets located in the discovered regions: buckets are
+// listed account-wide, so every bucket is kept only with --all-regions
+func (d *DiscoverCmd) restrictBucketRegions(cfg *configuration.TaggyScanConfig, regions []string) {
+	if d.Service != constants.ResourceTypeS3 || d.AllRegions {
+		return
+	}
+
+	resourceConfig := cfg.Resources[d.Service]
+	resourceConfig.BucketRegions = regions
+	cfg.Resources[d.Service] = resourceConfig
+}
+
+// setDiscoveryRegions sets the regions of a discovery configuration and of its service
+func setDiscoveryRegions(cfg *configuration.TaggyScanConfig, service string, regions []string) {
+	cfg.AWS.Regions.List = regions
+
+	resourceConfig := cfg.Resources[service]
+	resourceConfig.Regions = regions
+	cfg.Resources[service] = resourceConfig
+}
+
+// regionScan is the outcome of the discovery of the service in a region: the results keyed
+// as by InspectorManager.Results, or the error of the scan with the failures it recorded
+type regionScan struct {
+	results map[string]*inspector.InspectResult
+	errors  []inspector.ServiceError
+	err     error
+}
+
+// regionScanner discovers the resources of the service in a region
+type regionScanner func(ctx context.Context, region string) regionScan
+
+// regionScanner returns the scanner discovering the resources of the service in a region
+// with a manager of its own, sharing the concurrency limiter and the API call budget of the
+// primary manager so that the regions count as a single run
+func (d *DiscoverCmd) regionScanner(primary *inspector.InspectorManager, cfg configuration.TaggyScanConfig, cache *inspector.ScanCache) regionScanner {
+	return func(ctx context.Context, region string) regionScan {
+		// The configuration is a copy, but shares its resources with the caller
+		regionConfig := cfg
+		regionConfig.Resources = maps.Clone(cfg.Resources)
+		setDiscoveryRegions(&regionConfig, d.Service, []string{region})
+
+		manager, err := inspector.NewInspectorManagerFromConfig(regionConfig)
+		if err != nil {
+			return regionScan{err: fmt.Errorf("failed to create inspector manager: %w", err)}
+		}
+		manager.SetConcurrency(d.Concurrency)
+		manager.ShareLimits(primary)
+		manager.SetCache(cache)
+
+		if err := manager.Inspect(ctx); err != nil {
+			return regionScan{err: err, errors: manager.GetServiceErrors()}
+		}
+		return regionScan{results: manager.Results().ByService}
+	}
+}
+
+// inspectRegions scans the resources of the discovery in each region concurrently, so that
+// an unreachable region does not hide the resources of the others. Regions failing to be
+// scanned are scanned once more on their own, those failing again being returned as errors
+// with the merged results of the other regions. The discovery only fails when no region
+// can be scanned.
+func (d *DiscoverCmd) inspectRegions(ctx context.Context, regions []string, scan regionScanner, logger *o11y.Logger) (map[string]*inspector.InspectResult, []inspector.ServiceError, error) {
+	scans := scanRegions(ctx, regions, scan)
+
+	var failed []string
+	for _, region := range regions {
+		if scans[region].err != nil {
+			failed = append(failed, region)
+		}
+	}
+	if len(failed) > 0 && ctx.Err() == nil {
+		for _, region := range failed {
+			logger.Warn(fmt.Sprintf("Failed to discover %s resources in region %s: %v", d.Service, region, scans[region].err))
+		}
+		logger.Info(fmt.Sprintf("Discovering %s resources again in %s", d.Service, describeRegions(failed)))
+		maps.Copy(scans, scanRegions(ctx, failed, scan))
+	}
+
+	results := make(map[string]*inspector.InspectResult)
+	var regionErrors []inspector.ServiceError
+	var firstErr error
+	for _, region := range regions {
+		outcome := scans[region]
+		if outcome.err != nil {
+			if firstErr == nil {
+				firstErr = outcome.err
+			}
+			regionErrors = append(regionErrors, regionErrorsOf(d.Service, region, outcome)...)
+			continue
+		}
+		mergeResults(results, outcome.results)
+	}
+
+	if firstErr != nil && (ctx.Err() != nil || allFailed(regions, scans)) {
+		return nil, nil, firstErr
+	}
+	return results, regionErrors, nil
+}
+
+// scanRegions scans every region concurrently, returning the scan of each region
+func scanRegions(ctx context.Context, regions []string, scan regionScanner) map[string]regionScan {
+	scans := make([]regionScan, len(regions))
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scans[i] = scan(ctx, region)
+		}()
+	}
+	wg.Wait()
+
+	byRegion := make(map[string]regionScan, len(regions))
+	for i, region := range regions {
+		byRegion[region] = scans[i]
+	}
+	return byRegion
+}
+
+// allFailed reports whether the scan of every region failed
+func allFailed(regions []string, scans map[string]regionScan) bool {
+	for _, region := range regions {
+		if scans[region].err == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// regionErrorsOf returns the failures of the scan of a region, the error of the scan when
+// none was recorded, failures not tied to a region being attributed to the scanned one
+func regionErrorsOf(service, region string, scan regionScan) []inspector.ServiceError {
+	if len(scan.errors) == 0 {
+		return []inspector.ServiceError{{Service: service, Region: region, Message: scan.err.Error()}}
+	}
+
+	regionErrors := slices.Clone(scan.errors)
+	for i := range regionErrors {
+		if regionErrors[i].Region == "" {
+			regionErrors[i].Region = region
+		}
+	}
+	return regionErrors
+}
+
+// mergeResults adds the results of a region to those of the other regions, under the same keys
+func mergeResults(merged, results map[string]*inspector.InspectResult) {
+	for key, result := range results {
+		existing, exists := merged[key]
+		if !exists {
+			// Later regions append to the copy, leaving the result of the region untouched
+			copied := *result
+			copied.Resources = slices.Clone(result.Resources)
+			copied.ExcludedResources = slices.Clone(result.ExcludedResources)
+			copied.Errors = slices.Clone(result.Errors)
+			merged[key] = &copied
+			continue
+		}
+
+		existing.Resources = append(existing.Resources, result.Resources...)
+		existing.ExcludedResources = append(existing.ExcludedResources, result.ExcludedResources...)
+		existing.Errors = append(existing.Errors, result.Errors...)
+		existing.TotalResources += result.TotalResources
+		existing.OutOfRegionResources += result.OutOfRegionResources
+		existing.Truncated = existing.Truncated || result.Truncated
+		existing.Metadata.APICallsMade += result.Metadata.APICallsMade
+		existing.Metadata.RetriesPerformed += result.Metadata.RetriesPerformed
+		existing.Metadata.Throttles += result.Metadata.Throttles
+		if result.StartTime.Before(existing.StartTime) {
+			existing.StartTime = result.StartTime
+		}
+		if result.EndTime.After(existing.EndTime) {
+			existing.EndTime = result.EndTime
+		}
+		existing.Duration = existing.EndTime.Sub(existing.StartTime)
+	}
+}
+
+// summarizeRegions records the regions of a discovery, and those where no resource is listed.
+// Resources of global services are reported in the global region instead of the requested
+// ones.
+func (d *DiscoverCmd) summarizeRegions(discovery *DiscoveryResult, regions []string) {
+	if inspector.IsGlobalResourceType(d.Service) {
+		regions = []string{constants.GlobalRegion}
+	}
+	discovery.Regions = regions
+	if len(regions) == 1 {
+		discovery.Region = regions[0]
+	}
+
+	listed := make(map[string]bool)
+	for _, row := range discovery.Resources {
+		listed[row.Region] = true
+	}
+	for _, serviceErr := range discovery.Errors {
+		listed[serviceErr.Region] = true
+	}
+
+	for _, region := range regions {
+		if !listed[region] {
+			discovery.EmptyRegions = append(discovery.EmptyRegions, region)
+		}
+	}
+}
+
+// describeRegions names the regions in log and error messages
+func describeRegions(regions []string) string {
+	if len(regions) == 1 {
+		return fmt.Sprintf("region %s", regions[0])
+	}
+	return fmt.Sprintf("regions %s", strings.Join(regions, ", "))
+}
+
+// printRegionErrors prints the errors of the regions that could not be discovered to stderr,
+// leaving stdout to the output of the command
+func printRegionErrors(regionErrors []inspector.ServiceError) {
+	if len(regionErrors) == 0 {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "\n⚠️  Errors:")
+	for _, regionErr := range regionErrors {
+		fmt.Fprintf(os.Stderr, "  • %s: %s\n", regionErr.Region, regionErr.Message)
+	}
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoveryRegions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cmd     DiscoverCmd
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "regions are normalized and deduplicated",
+			cmd:  DiscoverCmd{Region: []string{"us-east-1", " EU-West-1 ", "us-east-1", ""}},
+			want: []string{"us-east-1", "eu-west-1"},
+		},
+		{
+			name: "all regions",
+			cmd:  DiscoverCmd{AllRegions: true, Region: []string{"us-east-1"}},
+			want: configuration.ValidAWSRegions(),
+		},
+		{
+			name:    "invalid region",
+			cmd:     DiscoverCmd{Region: []string{"us-east-1", "moon-1"}},
+			wantErr: "invalid region moon-1",
+		},
+		{
+			name:    "no region",
+			cmd:     DiscoverCmd{Region: []string{" "}},
+			wantErr: "at least one region is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			regions, err := tt.cmd.discoveryRegions()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, regions)
+		})
+	}
+}
+
+func TestSummarizeRegions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		service     string
+		regions     []string
+		discovery   DiscoveryResult
+		wantRegion  string
+		wantRegions []string
+		wantEmpty   []string
+	}{
+		{
+			name:        "single region with resources",
+			service:     constants.ResourceTypeSQS,
+			regions:     []string{"us-east-1"},
+			discovery:   DiscoveryResult{Resources: []ResourceRow{{ID: "jobs", Region: "us-east-1"}}},
+			wantRegion:  "us-east-1",
+			wantRegions: []string{"us-east-1"},
+		},
+		{
+			name:    "regions without resources or errors are empty",
+			service: constants.ResourceTypeSQS,
+			regions: []string{"us-east-1", "eu-west-1", "ap-southeast-2"},
+			discovery: DiscoveryResult{
+				Resources: []ResourceRow{{ID: "jobs", Region: "us-east-1"}},
+				Errors:    []inspector.ServiceError{{Service: constants.ResourceTypeSQS, Region: "ap-southeast-2", Message: "AccessDenied"}},
+			},
+			wantRegions: []string{"us-east-1", "eu-west-1", "ap-southeast-2"},
+			wantEmpty:   []string{"eu-west-1"},
+		},
+		{
+			name:        "no resources",
+			service:     constants.ResourceTypeSQS,
+			regions:     []string{"us-east-1", "eu-west-1"},
+			wantRegions: []string{"us-east-1", "eu-west-1"},
+			wantEmpty:   []string{"us-east-1", "eu-west-1"},
+		},
+		{
+			name:        "global services belong to the global region",
+			service:     constants.ResourceTypeRoute53,
+			regions:     []string{"us-east-1", "eu-west-1"},
+			discovery:   DiscoveryResult{Resources: []ResourceRow{{ID: "example.com", Region: constants.GlobalRegion}}},
+			wantRegion:  constants.GlobalRegion,
+			wantRegions: []string{constants.GlobalRegion},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &DiscoverCmd{Service: tt.service}
+			discovery := tt.discovery
+			d.summarizeRegions(&discovery, tt.regions)
+
+			assert.Equal(t, tt.wantRegion, discovery.Region)
+			assert.Equal(t, tt.wantRegions, discovery.Regions)
+			assert.Equal(t, tt.wantEmpty, discovery.EmptyRegions)
+		})
+	}
+}
+
+// scriptedScanner scans regions from a script of outcomes: the first scan of a region returns
+// its first outcome, a retry its second. It records the regions it scanned.
+type scriptedScanner struct {
+	mu      sync.Mutex
+	script  map[string][]regionScan
+	scanned []string
+}
+
+func (s *scriptedScanner) scan(_ context.Context, region string) regionScan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt := 0
+	for _, scanned := range s.scanned {
+		if scanned == region {
+			attempt++
+		}
+	}
+	s.scanned = append(s.scanned, region)
+	return s.script[region][min(attempt, len(s.script[region])-1)]
+}
+
+// scanOf returns the successful scan of a region holding queues with the given names
+func scanOf(region string, names ...string) regionScan {
+	result := &inspector.InspectResult{Region: region, TotalResources: len(names)}
+	for _, name := range names {
+		result.Resources = append(result.Resources, inspector.ResourceMetadata{ID: name, Region: region})
+	}
+	result.Metadata.APICallsMade = int64(len(names))
+	return regionScan{results: map[string]*inspector.InspectResult{constants.ResourceTypeSQS: result}}
+}
+
+// failedScan returns the scan of a region failing with AccessDenied
+func failedScan(region string) regionScan {
+	return regionScan{
+		err:    errors.New("AccessDenied"),
+		errors: []inspector.ServiceError{{Service: constants.ResourceTypeSQS, Region: region, Message: "AccessDenied"}},
+	}
+}
+
+func TestInspectRegions(t *testing.T) {
+	t.Parallel()
+
+	regions := []string{"us-east-1", "eu-west-1"}
+
+	tests := []struct {
+		name        string
+		script      map[string][]regionScan
+		wantErr     bool
+		wantIDs     []string
+		wantErrors  []inspector.ServiceError
+		wantScanned []string
+	}{
+		{
+			name: "every region succeeds",
+			script: map[string][]regionScan{
+				"us-east-1": {scanOf("us-east-1", "jobs")},
+				"eu-west-1": {scanOf("eu-west-1", "events", "mails")},
+			},
+			wantIDs:     []string{"jobs", "events", "mails"},
+			wantScanned: []string{"us-east-1", "eu-west-1"},
+		},
+		{
+			name: "only the failed region is scanned again",
+			script: map[string][]regionScan{
+				"us-east-1": {scanOf("us-east-1", "jobs")},
+				"eu-west-1": {failedScan("eu-west-1"), scanOf("eu-west-1", "events")},
+			},
+			wantIDs:     []string{"jobs", "events"},
+			wantScanned: []string{"us-east-1", "eu-west-1", "eu-west-1"},
+		},
+		{
+			name: "a region failing again is reported with the results of the others",
+			script: map[string][]regionScan{
+				"us-east-1": {scanOf("us-east-1", "jobs")},
+				"eu-west-1": {failedScan("eu-west-1")},
+			},
+			wantIDs:     []string{"jobs"},
+			wantErrors:  []inspector.ServiceError{{Service: constants.ResourceTypeSQS, Region: "eu-west-1", Message: "AccessDenied"}},
+			wantScanned: []string{"us-east-1", "eu-west-1", "eu-west-1"},
+		},
+		{
+			name: "failures without region are attributed to the scanned one",
+			script: map[string][]regionScan{
+				"us-east-1": {scanOf("us-east-1", "jobs")},
+				"eu-west-1": {{err: errors.New("expired token"), errors: []inspector.ServiceError{{Service: constants.ResourceTypeSQS, Message: "expired token"}}}},
+			},
+			wantIDs:     []string{"jobs"},
+			wantErrors:  []inspector.ServiceError{{Service: constants.ResourceTypeSQS, Region: "eu-west-1", Message: "expired token"}},
+			wantScanned: []string{"us-east-1", "eu-west-1", "eu-west-1"},
+		},
+		{
+			name: "every region failing fails the discovery",
+			script: map[string][]regionScan{
+				"us-east-1": {failedScan("us-east-1")},
+				"eu-west-1": {failedScan("eu-west-1")},
+			},
+			wantErr:     true,
+			wantScanned: []string{"us-east-1", "eu-west-1", "us-east-1", "eu-west-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &DiscoverCmd{Service: constants.ResourceTypeSQS}
+			scanner := &scriptedScanner{script: tt.script}
+			results, regionErrors, err := d.inspectRegions(context.Background(), regions, scanner.scan,
+				o11y.NewLogger(io.Discard, o11y.LogLevelError))
+
+			assert.ElementsMatch(t, tt.wantScanned, scanner.scanned)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantErrors, regionErrors)
+
+			require.Contains(t, results, constants.ResourceTypeSQS)
+			result := results[constants.ResourceTypeSQS]
+			var ids []string
+			for _, resource := range result.Resources {
+				ids = append(ids, resource.ID)
+			}
+			assert.ElementsMatch(t, tt.wantIDs, ids)
+			assert.Equal(t, len(tt.wantIDs), result.TotalResources)
+		})
+	}
+}
+
+func TestMergeResults(t *testing.T) {
+	t.Parallel()
+
+	first := scanOf("us-east-1", "jobs").results
+	second := scanOf("eu-west-1", "events", "mails").results
+	second[constants.ResourceTypeSQS].Truncated = true
+	second[constants.ResourceTypeSQS].OutOfRegionResources = 1
+
+	merged := make(map[string]*inspector.InspectResult)
+	mergeResults(merged, first)
+	mergeResults(merged, second)
+
+	result := merged[constants.ResourceTypeSQS]
+	assert.Equal(t, 3, result.TotalResources)
+	assert.Len(t, result.Resources, 3)
+	assert.Equal(t, int64(3), result.Metadata.APICallsMade)
+	assert.Equal(t, 1, result.OutOfRegionResources)
+	assert.True(t, result.Truncated)
+
+	// The results of the regions are left untouched
+	assert.Len(t, first[constants.ResourceTypeSQS].Resources, 1)
+	assert.True(t, slices.ContainsFunc(result.Resources, func(r inspector.ResourceMetadata) bool { return r.ID == "mails" }))
+}
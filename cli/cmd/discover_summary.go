@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+)
+
+// RegionCount holds the number of resources of a service discovered in a region, as printed
+// by --summary-only
+type RegionCount struct {
+	Region            string `json:"region" yaml:"region"`
+	TotalResources    int    `json:"total_resources" yaml:"total_resources"`
+	TaggedResources   int    `json:"tagged_resources" yaml:"tagged_resources"`
+	UntaggedResources int    `json:"untagged_resources" yaml:"untagged_resources"`
+	ExcludedResources int    `json:"excluded_resources" yaml:"excluded_resources"`
+}
+
+// countRegion counts a resource of the discovery in its region
+func (r *DiscoveryResult) countRegion(region string, hasTags, excluded bool) {
+	if r.regionCounts == nil {
+		r.regionCounts = make(map[string]*RegionCount)
+	}
+	count, exists := r.regionCounts[region]
+	if !exists {
+		count = &RegionCount{Region: region}
+		r.regionCounts[region] = count
+	}
+
+	switch {
+	case excluded:
+		count.ExcludedResources++
+	case hasTags:
+		count.TaggedResources++
+		count.TotalResources++
+	default:
+		count.UntaggedResources++
+		count.TotalResources++
+	}
+}
+
+// summarize replaces the resources of the discovery with their counts per region
+func (r *DiscoveryResult) summarize() {
+	r.RegionCounts = make([]RegionCount, 0, len(r.regionCounts))
+	for _, count := range r.regionCounts {
+		r.RegionCounts = append(r.RegionCounts, *count)
+	}
+	sort.Slice(r.RegionCounts, func(i, j int) bool {
+		return r.RegionCounts[i].Region < r.RegionCounts[j].Region
+	})
+	r.Resources = nil
+}
+
+// applyLimit keeps the first limit resources of the discovery, marking it truncated when some
+// are left out. The counts still cover every discovered resource. It returns the number of
+// resources listed before the limit.
+func (r *DiscoveryResult) applyLimit(limit int) int {
+	listed := len(r.Resources)
+	if limit <= 0 || listed <= limit {
+		return listed
+	}
+
+	r.Resources = r.Resources[:limit]
+	r.Truncated = true
+	r.Limit = limit
+	return listed
+}
+
+// validateListing ensures --limit and --summary-only are used consistently
+func (d *DiscoverCmd) validateListing() error {
+	if d.Limit < 0 {
+		return fmt.Errorf("--limit must be positive, got %d", d.Limit)
+	}
+	if d.Limit > 0 && d.SummaryOnly {
+		return fmt.Errorf("--limit cannot be used with --summary-only, which lists no resources")
+	}
+	return nil
+}
+
+// printLimitNote tells how many of the listed resources were shown when --limit left some out
+func printLimitNote(shown, listed int) {
+	if shown < listed {
+		fmt.Printf("\nShowing %d of %d resources, raise --limit to list more\n", shown, listed)
+	}
+}
+
+// renderRegionSummary renders the counts per region of a single service discovery
+func (d *DiscoverCmd) renderRegionSummary(discovery DiscoveryResult) error {
+	columns := []tui.Column{
+		{Title: "Region", Key: "Region", Width: 15, Align: "left"},
+		{Title: "Total", Key: "TotalResources", Width: 10, Align: "center"},
+		{Title: "Tagged", Key: "TaggedResources", Width: 10, Align: "center"},
+		{Title: "Untagged", Key: "UntaggedResources", Width: 10, Align: "center"},
+		{Title: "Excluded", Key: "ExcludedResources", Width: 10, Align: "center"},
+	}
+
+	tableData := make([][]string, 0, len(discovery.RegionCounts))
+	for _, count := range discovery.RegionCounts {
+		tableData = append(tableData, regionCountRow(count))
+	}
+
+	return tui.RenderTable(tui.TableOptions{
+		Title: fmt.Sprintf("🏷️  %s Resource Summary (Total: %d, Tagged: %d, Untagged: %d, Excluded: %d)",
+			discovery.Service, discovery.TotalResources, discovery.TaggedResources,
+			discovery.UntaggedResources, discovery.ExcludedResources),
+		Columns:         columns,
+		FlexibleColumns: true,
+		AutoWidth:       true,
+	}, tableData)
+}
+
+// renderAllServicesSummary renders the counts per service and region of a discovery of every
+// enabled service
+func (d *DiscoverCmd) renderAllServicesSummary(discovery AllServicesDiscovery) error {
+	columns := []tui.Column{
+		{Title: "Service", Key: "Service", Width: 12, Align: "left"},
+		{Title: "Region", Key: "Region", Width: 15, Align: "left"},
+		{Title: "Total", Key: "TotalResources", Width: 10, Align: "center"},
+		{Title: "Tagged", Key: "TaggedResources", Width: 10, Align: "center"},
+		{Title: "Untagged", Key: "UntaggedResources", Width: 10, Align: "center"},
+		{Title: "Excluded", Key: "ExcludedResources", Width: 10, Align: "center"},
+	}
+
+	services := make([]string, 0, len(discovery.Services))
+	for service := range discovery.Services {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	var tableData [][]string
+	for _, service := range services {
+		for _, count := range discovery.Services[service].RegionCounts {
+			tableData = append(tableData, append([]string{service}, regionCountRow(count)...))
+		}
+	}
+
+	return tui.RenderTable(tui.TableOptions{
+		Title: fmt.Sprintf("🏷️  Resource Summary across %d services (Total: %d, Tagged: %d, Untagged: %d, Excluded: %d)",
+			len(services), discovery.TotalResources, discovery.TaggedResources,
+			discovery.UntaggedResources, discovery.ExcludedResources),
+		Columns:         columns,
+		FlexibleColumns: true,
+		AutoWidth:       true,
+	}, tableData)
+}
+
+// regionCountRow renders the counts of a region as table cells
+func regionCountRow(count RegionCount) []string {
+	return []string{
+		count.Region,
+		fmt.Sprintf("%d", count.TotalResources),
+		fmt.Sprintf("%d", count.TaggedResources),
+		fmt.Sprintf("%d", count.UntaggedResources),
+		fmt.Sprintf("%d", count.ExcludedResources),
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/cloud"
+	"github.com/Excoriate/aws-taggy/pkg/export"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/Excoriate/aws-taggy/pkg/runner"
+)
+
+// exportOpenSearch is the --export target indexing the results into OpenSearch or Elasticsearch
+const exportOpenSearch = "opensearch"
+
+// maxRejectionReasons is the number of distinct rejection reasons an export reports
+const maxRejectionReasons = 5
+
+// resultDocument is the document of a resource result indexed by --export
+type resultDocument struct {
+	*runner.ResourceResult
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+// validateExport checks the --export flags before the scan starts
+func (c *CheckCmd) validateExport() error {
+	if c.Export == "" {
+		switch {
+		case c.ExportURL != "":
+			return fmt.Errorf("--export-url requires --export %s", exportOpenSearch)
+		case c.ExportUsername != "":
+			return fmt.Errorf("--export-username requires --export %s", exportOpenSearch)
+		case c.ExportSigV4:
+			return fmt.Errorf("--export-sigv4 requires --export %s", exportOpenSearch)
+		}
+		return nil
+	}
+	if !strings.EqualFold(c.Export, exportOpenSearch) {
+		return fmt.Errorf("unsupported export target %s, supported targets are: %s", c.Export, exportOpenSearch)
+	}
+	if c.ExportURL == "" {
+		return fmt.Errorf("--export %s requires --export-url", exportOpenSearch)
+	}
+	if c.ExportSigV4 && c.ExportUsername != "" {
+		return fmt.Errorf("--export-sigv4 cannot be combined with --export-username")
+	}
+	return nil
+}
+
+// exportResults indexes a document per resource result into the cluster of --export-url,
+// under the ARN of the resource so a new run replaces the documents of the previous one.
+// Rejected documents are reported without failing the check.
+func (c *CheckCmd) exportResults(ctx context.Context, results []*runner.ResourceResult, scannedAt time.Time, logger *o11y.Logger) error {
+	options := export.OpenSearchOptions{
+		URL:      c.ExportURL,
+		Index:    c.ExportIndex,
+		Username: c.ExportUsername,
+		Password: c.ExportPassword,
+	}
+	if c.ExportSigV4 {
+		awsConfig, err := (&cloud.AWSClientConfigOptions{Region: c.ExportRegion}).LoadConfig(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load the AWS credentials signing the export: %w", err)
+		}
+		options.SigV4 = &export.SigV4Options{
+			Credentials: awsConfig.Credentials,
+			Region:      awsConfig.Region,
+			Service:     c.ExportService,
+		}
+	}
+
+	exporter, err := export.NewOpenSearchExporter(options)
+	if err != nil {
+		return err
+	}
+
+	documents := make([]export.Document, 0, len(results))
+	for _, result := range results {
+		documents = append(documents, export.Document{
+			ID:     documentID(result),
+			Source: resultDocument{ResourceResult: result, ScannedAt: scannedAt},
+		})
+	}
+
+	bulkResult, err := exporter.Export(ctx, documents)
+	if err != nil {
+		return fmt.Errorf("failed to export results to index %s (%d documents indexed): %w", c.ExportIndex, bulkResult.Indexed, err)
+	}
+
+	logger.Info(fmt.Sprintf("✅ %d results exported to index %s", bulkResult.Indexed, c.ExportIndex))
+	if len(bulkResult.Rejected) > 0 {
+		logger.Warn(fmt.Sprintf("%d results were rejected by index %s: %s", len(bulkResult.Rejected), c.ExportIndex,
+			rejectionReasons(bulkResult.Rejected)))
+	}
+	return nil
+}
+
+// documentID returns the ARN of the resource, or its account, region, type and ID when it
+// has none
+func documentID(result *runner.ResourceResult) string {
+	if result.ResourceARN != "" {
+		return result.ResourceARN
+	}
+	return strings.Join([]string{result.AccountID, result.Region, result.ResourceType, result.ResourceID}, "/")
+}
+
+// rejectionReasons summarizes the reasons documents were rejected for, the most frequent first
+func rejectionReasons(rejected []export.RejectedDocument) string {
+	counts := make(map[string]int)
+	for _, document := range rejected {
+		reason := document.Reason
+		if reason == "" {
+			reason = fmt.Sprintf("status %d", document.Status)
+		}
+		counts[reason]++
+	}
+
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if counts[reasons[i]] != counts[reasons[j]] {
+			return counts[reasons[i]] > counts[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	summary := make([]string, 0, maxRejectionReasons)
+	for _, reason := range reasons[:min(len(reasons), maxRejectionReasons)] {
+		summary = append(summary, fmt.Sprintf("%d × %s", counts[reason], reason))
+	}
+	if len(reasons) > maxRejectionReasons {
+		summary = append(summary, fmt.Sprintf("%d other reasons", len(reasons)-maxRejectionReasons))
+	}
+	return strings.Join(summary, "; ")
+}
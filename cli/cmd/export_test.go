@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateExport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cmd     CheckCmd
+		wantErr string
+	}{
+		{
+			name: "no export",
+			cmd:  CheckCmd{},
+		},
+		{
+			name: "basic auth",
+			cmd:  CheckCmd{Export: "opensearch", ExportURL: "https://search.example.com", ExportUsername: "admin"},
+		},
+		{
+			name: "sigv4",
+			cmd:  CheckCmd{Export: "OpenSearch", ExportURL: "https://search.example.com", ExportSigV4: true},
+		},
+		{
+			name:    "url without export",
+			cmd:     CheckCmd{ExportURL: "https://search.example.com"},
+			wantErr: "--export-url requires --export opensearch",
+		},
+		{
+			name:    "username without export",
+			cmd:     CheckCmd{ExportUsername: "admin"},
+			wantErr: "--export-username requires --export opensearch",
+		},
+		{
+			name:    "sigv4 without export",
+			cmd:     CheckCmd{ExportSigV4: true},
+			wantErr: "--export-sigv4 requires --export opensearch",
+		},
+		{
+			name:    "unsupported target",
+			cmd:     CheckCmd{Export: "splunk", ExportURL: "https://search.example.com"},
+			wantErr: "unsupported export target splunk",
+		},
+		{
+			name:    "export without url",
+			cmd:     CheckCmd{Export: "opensearch"},
+			wantErr: "--export opensearch requires --export-url",
+		},
+		{
+			name:    "sigv4 with username",
+			cmd:     CheckCmd{Export: "opensearch", ExportURL: "https://search.example.com", ExportSigV4: true, ExportUsername: "admin"},
+			wantErr: "--export-sigv4 cannot be combined with --export-username",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.cmd.validateExport()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
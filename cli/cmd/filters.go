@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/history"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+)
+
+// parseAgeFilter builds the creation time filter of --created-after, --min-age and
+// --exclude-unknown-age, keeping every resource when none is set
+func parseAgeFilter(createdAfter, minAge string, excludeUnknown bool) (inspector.AgeFilter, error) {
+	filter := inspector.AgeFilter{ExcludeUnknown: excludeUnknown}
+
+	if createdAfter != "" {
+		date, err := inspector.ParseCreationDate(createdAfter)
+		if err != nil {
+			return inspector.AgeFilter{}, fmt.Errorf("--created-after: %w", err)
+		}
+		filter.CreatedAfter = date
+	}
+
+	if minAge != "" {
+		age, err := history.ParseAge(minAge)
+		if err != nil {
+			return inspector.AgeFilter{}, fmt.Errorf("--min-age: %w", err)
+		}
+		filter.CreatedBefore = time.Now().Add(-age)
+	}
+
+	return filter, nil
+}
+
+// parseNameFilter builds the filter of --match and --exclude, keeping every resource when
+// neither is set
+func parseNameFilter(match, exclude string) (inspector.NameFilter, error) {
+	filter, err := inspector.NewNameFilter(match, exclude)
+	if err != nil {
+		return inspector.NameFilter{}, fmt.Errorf("--match/--exclude: %w", err)
+	}
+	return filter, nil
+}
+
+// selectDiscovered keeps the discovered resources selected by their tags, creation time and
+// ID, name or ARN
+func selectDiscovered(results map[string]*inspector.InspectResult, tagSelectors []inspector.TagSelector, ageFilter inspector.AgeFilter, nameFilter inspector.NameFilter) map[string]*inspector.InspectResult {
+	results = inspector.FilterByTagSelectors(results, tagSelectors)
+	results = inspector.FilterByAge(results, ageFilter)
+	return inspector.FilterByName(results, nameFilter)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/pkg/output"
+)
+
+// tableFormat is the output format every command renders itself, as a table
+const tableFormat = "table"
+
+// outputFormatsVar is the Kong variable listing the registered output formats in the help of
+// the --output flags, which accept any format registered in pkg/output
+const outputFormatsVar = "output_formats"
+
+// outputFormats lists the registered output formats for the help of the --output flags
+func outputFormats() string {
+	return strings.Join(output.Formats(), "|")
+}
+
+// structuredFormatter returns the formatter registered in pkg/output for an output format,
+// nil for table output. Unknown formats fail with the list of the registered ones, so the
+// --output flags are validated before any AWS call.
+func structuredFormatter(format string) (output.Formatter, error) {
+	if format == "" || strings.EqualFold(strings.TrimSpace(format), tableFormat) {
+		return nil, nil
+	}
+	return output.New(format)
+}
+
+// printFormatted prints the data rendered by a formatter
+func printFormatted(formatter output.Formatter, data interface{}) error {
+	formattedOutput, err := formatter.Format(data)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	fmt.Println(strings.TrimSuffix(formattedOutput, "\n"))
+	return nil
+}
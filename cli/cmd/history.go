@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/cli/internal/output"
+	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+	"github.com/Excoriate/aws-taggy/pkg/history"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+)
+
+// HistoryCmd represents the tag history command group
+type HistoryCmd struct {
+	Show  HistoryShowCmd  `cmd:"" help:"Show how the tags and compliance status of a resource changed over time"`
+	Prune HistoryPruneCmd `cmd:"" help:"Remove old snapshots from the state file"`
+}
+
+// HistoryShowCmd represents the command showing the timeline of a resource
+type HistoryShowCmd struct {
+	ARN     string `help:"ARN of the resource, or its ID when it has no ARN" required:"true"`
+	StateDB string `help:"State file recorded with --state-db, ~/.aws-taggy/state.db when empty"`
+	Output  string `help:"Output format (${output_formats})" default:"table"`
+}
+
+// Run implements the logic for showing the timeline of a resource
+func (h *HistoryShowCmd) Run() error {
+	formatter, err := output.NewFormatter(h.Output)
+	if err != nil {
+		return err
+	}
+
+	store, err := openHistoryStore(h.StateDB)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := store.Snapshots(h.ARN)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return fmt.Errorf("no history recorded for %s in %s", h.ARN, store.Path())
+	}
+
+	events := history.Timeline(snapshots)
+
+	if formatter.IsStructured() {
+		return formatter.Output(events)
+	}
+
+	tableData := make([][]string, 0, len(events))
+	for _, event := range events {
+		tableData = append(tableData, []string{
+			event.Time.Local().Format(time.DateTime),
+			historyEventLabel(event.Kind),
+			event.TagKey,
+			historyEventValues(event),
+		})
+	}
+
+	return tui.RenderTable(tui.TableOptions{
+		Title: fmt.Sprintf("🕰️  History of %s", h.ARN),
+		Columns: []tui.Column{
+			{Title: "Time", Width: 20},
+			{Title: "Change", Width: 22},
+			{Title: "Tag", Width: 20, Flexible: true},
+			{Title: "Before → After", Width: 40, Flexible: true},
+		},
+		AutoWidth: true,
+	}, tableData)
+}
+
+// HistoryPruneCmd represents the command removing old snapshots
+type HistoryPruneCmd struct {
+	OlderThan string `help:"Remove snapshots older than this age, in days (e.g. 90d) or as a duration (e.g. 720h)" default:"90d"`
+	StateDB   string `help:"State file recorded with --state-db, ~/.aws-taggy/state.db when empty"`
+}
+
+// Run implements the logic for pruning the state file
+func (h *HistoryPruneCmd) Run() error {
+	logger := o11y.DefaultLogger()
+
+	age, err := history.ParseAge(h.OlderThan)
+	if err != nil {
+		return err
+	}
+
+	store, err := openHistoryStore(h.StateDB)
+	if err != nil {
+		return err
+	}
+
+	removed, err := store.Prune(time.Now().Add(-age))
+	if err != nil {
+		return err
+	}
+
+	logger.Info(fmt.Sprintf("🧹 Removed %d snapshot(s) older than %s from %s", removed, h.OlderThan, store.Path()))
+	return nil
+}
+
+// openHistoryStore opens the state file at path, or at its default location when empty
+func openHistoryStore(path string) (*history.Store, error) {
+	if path == "" {
+		defaultPath, err := history.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+	return history.Open(path)
+}
+
+// recordHistory records the snapshots of a run in the state file given with --state-db. A
+// failure is logged rather than returned, the run itself having succeeded.
+func recordHistory(path string, snapshots []history.Snapshot, logger *o11y.Logger) {
+	store, err := history.Open(path)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Tag history not recorded: %v", err))
+		return
+	}
+
+	written, err := store.Record(snapshots)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Tag history not recorded: %v", err))
+		return
+	}
+	logger.Info(fmt.Sprintf("🕰️  Recorded %d changed resource(s) of %d in %s", written, len(snapshots), store.Path()))
+}
+
+// complianceSnapshot returns the snapshot of a compliance result, false when its tags could
+// not be read and its state is unknown
+func complianceSnapshot(result *output.ComplianceResult) (history.Snapshot, bool) {
+	if result.IsUnknown {
+		return history.Snapshot{}, false
+	}
+
+	arn := result.ResourceARN
+	if arn == "" {
+		arn = result.ResourceID
+	}
+	compliant := result.IsCompliant
+	return history.Snapshot{
+		ARN:          arn,
+		ResourceID:   result.ResourceID,
+		ResourceType: result.ResourceType,
+		AccountID:    result.AccountID,
+		Region:       result.Region,
+		Tags:         result.ResourceTags,
+		Compliant:    &compliant,
+		ChangeMarker: result.ChangeMarker,
+	}, true
+}
+
+// deletionSnapshots returns the snapshots recording the resources an incremental scan no
+// longer discovered as deleted
+func deletionSnapshots(deleted []output.DeletedResource) []history.Snapshot {
+	snapshots := make([]history.Snapshot, 0, len(deleted))
+	for _, resource := range deleted {
+		arn := resource.ResourceARN
+		if arn == "" {
+			arn = resource.ResourceID
+		}
+		snapshots = append(snapshots, history.Snapshot{
+			ARN:          arn,
+			ResourceID:   resource.ResourceID,
+			ResourceType: resource.ResourceType,
+			AccountID:    resource.AccountID,
+			Region:       resource.Region,
+			Deleted:      true,
+		})
+	}
+	return snapshots
+}
+
+// loadPreviousScan returns the resources recorded in the state file at path for an
+// incremental scan, leaving out those already recorded as deleted
+func loadPreviousScan(path string) (*inspector.PreviousScan, error) {
+	store, err := history.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := store.Latest()
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]inspector.PreviousResource, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		if snapshot.Deleted {
+			continue
+		}
+
+		// Snapshots of resources without an ARN are keyed by their ID
+		resource := inspector.PreviousResource{
+			ResourceID:   snapshot.ResourceID,
+			ResourceType: snapshot.ResourceType,
+			AccountID:    snapshot.AccountID,
+			Region:       snapshot.Region,
+			Tags:         snapshot.Tags,
+			ChangeMarker: snapshot.ChangeMarker,
+		}
+		if snapshot.ARN != snapshot.ResourceID {
+			resource.ARN = snapshot.ARN
+		}
+		resources = append(resources, resource)
+	}
+	return inspector.NewPreviousScan(resources), nil
+}
+
+// discoverySnapshots returns the snapshots of the discovered resources whose tags were read,
+// without a compliance status as discover does not validate tags
+func discoverySnapshots(results map[string]*inspector.InspectResult) []history.Snapshot {
+	var snapshots []history.Snapshot
+	for _, result := range results {
+		for _, resource := range result.Resources {
+			if resource.TagFetchError != "" {
+				continue
+			}
+
+			arn := resource.Details.ARN
+			if arn == "" {
+				arn = resource.ID
+			}
+			snapshots = append(snapshots, history.Snapshot{
+				ARN:          arn,
+				ResourceID:   resource.ID,
+				ResourceType: resource.Type,
+				AccountID:    resource.AccountID,
+				Region:       resource.Region,
+				Tags:         resource.Tags,
+				ChangeMarker: resource.ChangeMarker,
+			})
+		}
+	}
+	return snapshots
+}
+
+// historyEventLabel formats the kind of a timeline event
+func historyEventLabel(kind string) string {
+	switch kind {
+	case history.EventFirstSeen:
+		return "🆕 First seen"
+	case history.EventTagAdded:
+		return "➕ Tag added"
+	case history.EventTagRemoved:
+		return "➖ Tag removed"
+	case history.EventTagChanged:
+		return "✏️ Tag changed"
+	case history.EventBecameCompliant:
+		return "✅ Became compliant"
+	case history.EventBecameNonCompliant:
+		return "❌ Became non-compliant"
+	case history.EventDeleted:
+		return "🗑️ Deleted"
+	default:
+		return kind
+	}
+}
+
+// historyEventValues formats the tag values before and after a tag event
+func historyEventValues(event history.Event) string {
+	switch event.Kind {
+	case history.EventTagAdded:
+		return fmt.Sprintf("→ %s", event.NewValue)
+	case history.EventTagRemoved:
+		return fmt.Sprintf("%s →", event.OldValue)
+	case history.EventTagChanged:
+		return fmt.Sprintf("%s → %s", event.OldValue, event.NewValue)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+)
+
+// ExitCodeTruncated is the exit status of a command whose results are partial because the
+// max_resources_per_service or max_api_calls limit of the configuration was hit
+const ExitCodeTruncated = 3
+
+// ExitError is an error ending the command with a specific exit status
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error returns the message of the wrapped error
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// truncatedError reports the results cut short by a scan limit with ExitCodeTruncated, nil
+// when no result was truncated
+func truncatedError(truncated []string) error {
+	if len(truncated) == 0 {
+		return nil
+	}
+	return &ExitError{
+		Code: ExitCodeTruncated,
+		Err: fmt.Errorf("results are truncated by the max_resources_per_service or max_api_calls limit: %s",
+			strings.Join(truncated, ", ")),
+	}
+}
+
+// validateConcurrency rejects a --concurrency that is negative, zero keeping the
+// max_concurrency and workers settings of the configuration
+func validateConcurrency(concurrency int) error {
+	if concurrency < 0 {
+		return fmt.Errorf("--concurrency must be a positive number of concurrent operations, got %d", concurrency)
+	}
+	return nil
+}
+
+// ScanEstimateReport is the output of --dry-run-estimate: the resources a scan would
+// discover and the AWS API calls it would make, per service
+type ScanEstimateReport struct {
+	TotalResources    int                                `json:"total_resources" yaml:"total_resources"`
+	EstimatedAPICalls int64                              `json:"estimated_api_calls" yaml:"estimated_api_calls"`
+	Services          map[string]*inspector.ScanEstimate `json:"services" yaml:"services"`
+	Errors            []string                           `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// newScanEstimateReport totals the estimates of a run, keyed by inspector.ResultKey
+func newScanEstimateReport(estimates map[string]*inspector.ScanEstimate, errors []string) ScanEstimateReport {
+	report := ScanEstimateReport{
+		Services: estimates,
+		Errors:   errors,
+	}
+	for _, estimate := range estimates {
+		report.TotalResources += estimate.Resources
+		report.EstimatedAPICalls += estimate.EstimatedAPICalls
+	}
+	return report
+}
+
+// printScanEstimate prints the estimate of a scan with the formatter registered for the
+// format, such as JSON or YAML, or as a table with a row per service and region
+func printScanEstimate(report ScanEstimateReport, format string) error {
+	formatter, err := structuredFormatter(format)
+	if err != nil {
+		return err
+	}
+	if formatter != nil {
+		return printFormatted(formatter, report)
+	}
+
+	services := make([]string, 0, len(report.Services))
+	for service := range report.Services {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	var tableData [][]string
+	for _, service := range services {
+		estimate := report.Services[service]
+
+		regions := make([]string, 0, len(estimate.ResourcesByRegion))
+		for region := range estimate.ResourcesByRegion {
+			regions = append(regions, region)
+		}
+		sort.Strings(regions)
+
+		for _, region := range regions {
+			tableData = append(tableData, []string{service, region, fmt.Sprintf("%d", estimate.ResourcesByRegion[region]), ""})
+		}
+		tableData = append(tableData, []string{
+			service,
+			"Subtotal",
+			fmt.Sprintf("%d", estimate.Resources),
+			fmt.Sprintf("%d (%d for discovery)", estimate.EstimatedAPICalls, estimate.DiscoveryAPICalls),
+		})
+	}
+
+	err := tui.RenderTable(tui.TableOptions{
+		Title: fmt.Sprintf("📏 Scan Estimate (Resources: %d, API Calls: ~%d)", report.TotalResources, report.EstimatedAPICalls),
+		Columns: []tui.Column{
+			{Title: "Service", Width: 20, Flexible: true},
+			{Title: "Region", Width: 15},
+			{Title: "Resources", Width: 12},
+			{Title: "API Calls", Width: 30, Flexible: true},
+		},
+		AutoWidth: true,
+	}, tableData)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Errors) > 0 {
+		fmt.Println("\n⚠️  Errors:")
+		for _, estimateErr := range report.Errors {
+			fmt.Printf("  • %s\n", estimateErr)
+		}
+	}
+
+	return nil
+}
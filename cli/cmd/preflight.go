@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/Excoriate/aws-taggy/pkg/runner"
+)
+
+// PreflightCmd represents the command checking the permissions of a scan before running it
+type PreflightCmd struct {
+	Config  string        `help:"Path to the tag compliance configuration file whose resource types and regions are probed" required:"true"`
+	Output  string        `help:"Output format (${output_formats})" default:"table"`
+	Set     []string      `help:"Override a configuration setting, e.g. --set aws.regions.mode=specific (repeatable, lists are comma-separated)" placeholder:"PATH=VALUE" sep:"none"`
+	Timeout time.Duration `help:"Abort the probes after this duration (e.g. 2m), unbounded when 0" default:"0"`
+}
+
+// PreflightReport is the output of preflight: the outcome of the probe of every resource
+// type in each of its regions
+type PreflightReport struct {
+	Passed  bool                    `json:"passed" yaml:"passed"`
+	Failed  int                     `json:"failed" yaml:"failed"`
+	Results []inspector.ProbeResult `json:"results" yaml:"results"`
+}
+
+// Run probes every enabled resource type in each of its regions with the cheapest of the read
+// calls of a scan, then the tag read on at most one resource, and reports the IAM action of
+// every failed call. No resource is modified. It fails when a probe fails.
+func (p *PreflightCmd) Run(ctx context.Context) error {
+	overrides, err := configuration.ParseOverrides(p.Set)
+	if err != nil {
+		return err
+	}
+
+	loader := configuration.NewTaggyScanConfigLoader()
+	loader.SetOverrides(overrides)
+
+	cfg, err := loader.LoadConfig(p.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration from file %s: %w", p.Config, err)
+	}
+
+	configValidator, err := configuration.NewContentValidator(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize configuration validator for file %s: %w", p.Config, err)
+	}
+	if err := configValidator.ValidateContent(); err != nil {
+		return fmt.Errorf("configuration validation failed for file %s: %w", p.Config, err)
+	}
+
+	preflightRunner, err := runner.New(cfg, runner.Options{})
+	if err != nil {
+		return err
+	}
+
+	probeCtx, cancel := withTimeout(ctx, p.Timeout)
+	defer cancel()
+	results, err := preflightRunner.Preflight(probeCtx)
+	if err != nil {
+		return err
+	}
+
+	report := newPreflightReport(results)
+	if err := printPreflightReport(report, p.Output); err != nil {
+		return err
+	}
+
+	if !report.Passed {
+		return fmt.Errorf("%d of %d probes failed, the scan lacks permissions or cannot reach AWS", report.Failed, len(report.Results))
+	}
+	return nil
+}
+
+// newPreflightReport counts the failed probes of a preflight. Resource types whose inspector
+// cannot be probed are not failures.
+func newPreflightReport(results []inspector.ProbeResult) PreflightReport {
+	report := PreflightReport{Results: results}
+	for _, result := range results {
+		if result.Status == inspector.ProbeStatusAccessDenied || result.Status == inspector.ProbeStatusError {
+			report.Failed++
+		}
+	}
+	report.Passed = report.Failed == 0
+	return report
+}
+
+// printPreflightReport prints a preflight with the formatter registered for the format, such
+// as JSON or YAML, or as a table with a row per service, account and region
+func printPreflightReport(report PreflightReport, format string) error {
+	formatter, err := structuredFormatter(format)
+	if err != nil {
+		return err
+	}
+	if formatter != nil {
+		return printFormatted(formatter, report)
+	}
+
+	tableData := make([][]string, len(report.Results))
+	for i, result := range report.Results {
+		service := result.Service
+		if result.AccountID != "" {
+			service = fmt.Sprintf("%s (%s)", service, result.AccountID)
+		}
+		tableData[i] = []string{service, result.Region, preflightStatus(result.Status), result.Action, result.Message}
+	}
+
+	return tui.RenderTable(tui.TableOptions{
+		Title: fmt.Sprintf("🩺 Preflight (Probes: %d, Failed: %d)", len(report.Results), report.Failed),
+		Columns: []tui.Column{
+			{Title: "Service", Width: 20, Flexible: true},
+			{Title: "Region", Width: 15},
+			{Title: "Status", Width: 16},
+			{Title: "Action", Width: 32},
+			{Title: "Message", Width: 50, Flexible: true},
+		},
+		AutoWidth: true,
+	}, tableData)
+}
+
+// preflightStatus returns the label of the status of a probe in the table
+func preflightStatus(status inspector.ProbeStatus) string {
+	switch status {
+	case inspector.ProbeStatusOK:
+		return "✅ OK"
+	case inspector.ProbeStatusAccessDenied:
+		return "❌ AccessDenied"
+	case inspector.ProbeStatusUnsupported:
+		return "➖ Unsupported"
+	default:
+		return "⚠️ Error"
+	}
+}
@@ -3,10 +3,12 @@
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 
 	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+	"github.com/Excoriate/aws-taggy/pkg/arn"
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
 	"github.com/Excoriate/aws-taggy/pkg/inspector"
 	"github.com/Excoriate/aws-taggy/pkg/o11y"
@@ -21,18 +23,28 @@ type QueryCmd struct {
 
 // TagsCmd represents the query tags subcommand
 type TagsCmd struct {
-	ARN       string `help:"ARN of the resource to query tags for" required:"true"`
-	Service   string `help:"AWS service type (e.g., s3, ec2)" required:"true"`
-	Output    string `help:"Output format (table|json|yaml)" default:"table" enum:"table,json,yaml,TABLE,JSON,YAML"`
-	Clipboard bool   `help:"Copy output to clipboard" default:"false"`
+	ARN        string `help:"ARN of the resource to query tags for" required:"true"`
+	Service    string `help:"AWS service type (e.g., s3, ec2), inferred from the ARN when omitted"`
+	Region     string `help:"AWS region to query the resource from, inferred from the ARN, AWS_REGION or AWS_DEFAULT_REGION when omitted"`
+	Output     string `help:"Output format (${output_formats})" default:"table"`
+	Clipboard  bool   `help:"Copy output to clipboard" default:"false"`
+	IncludeRaw bool   `help:"Add the raw AWS API response of the resource to the JSON and YAML output" default:"false"`
+
+	// Write mode, changing the tags of the resource instead of printing them
+	Set    []string `help:"Add or update a tag of the resource (repeatable)" placeholder:"KEY=VALUE" sep:"none"`
+	Unset  []string `help:"Remove a tag from the resource (repeatable)" placeholder:"KEY" sep:"none"`
+	Yes    bool     `help:"Apply the tag changes without asking for confirmation" default:"false"`
+	Config string   `help:"Tag compliance configuration file the new tag values are validated against before being written" type:"path"`
 }
 
 // InfoCmd represents the query info subcommand
 type InfoCmd struct {
-	ARN       string `help:"ARN of the resource to query information for" required:"true"`
-	Service   string `help:"AWS service type (e.g., s3, ec2)" required:"true"`
-	Output    string `help:"Output format (table|json|yaml)" default:"table" enum:"table,json,yaml,TABLE,JSON,YAML"`
-	Clipboard bool   `help:"Copy output to clipboard" default:"false"`
+	ARN        string `help:"ARN of the resource to query information for" required:"true"`
+	Service    string `help:"AWS service type (e.g., s3, ec2), inferred from the ARN when omitted"`
+	Region     string `help:"AWS region to query the resource from, inferred from the ARN, AWS_REGION or AWS_DEFAULT_REGION when omitted"`
+	Output     string `help:"Output format (${output_formats})" default:"table"`
+	Clipboard  bool   `help:"Copy output to clipboard" default:"false"`
+	IncludeRaw bool   `help:"Add the raw AWS API response of the resource to the JSON and YAML output" default:"false"`
 }
 
 // Run is a no-op method to satisfy the Kong command interface
@@ -41,45 +53,61 @@ func (q *QueryCmd) Run() error {
 }
 
 // Run implements the tags query logic
-func (t *TagsCmd) Run() error {
+func (t *TagsCmd) Run(ctx context.Context) error {
 	logger := o11y.DefaultLogger()
 	logger.Info(fmt.Sprintf("🔍 Querying tags for resource: %s", t.ARN))
 
-	regionOnARN := inspector.ExtractRegionFromARNOrDefault(t.ARN)
+	formatter, err := structuredFormatter(t.Output)
+	if err != nil {
+		return err
+	}
+
+	service, err := resolveService(t.ARN, t.Service)
+	if err != nil {
+		return err
+	}
+
+	// Regions are tried in order by inspectors locating resources whose ARN has no region
+	regions := inspector.QueryRegions(t.ARN, t.Region)
 
 	// Create minimal config for the specific service
 	config := configuration.TaggyScanConfig{
 		AWS: configuration.AWSConfig{
 			Regions: configuration.RegionsConfig{
 				Mode: "specific",
-				List: []string{regionOnARN},
+				List: regions,
 			},
+			EndpointURL: os.Getenv(configuration.EndpointURLEnvVar),
 		},
 		Resources: map[string]configuration.ResourceConfig{
-			t.Service: {
+			service: {
 				Enabled: true,
 			},
 		},
 	}
 
 	// Create inspector for the specific service
-	inspectorClient, err := inspector.New(t.Service, config)
+	inspectorClient, err := inspector.New(service, config)
 	if err != nil {
-		return fmt.Errorf("failed to create inspector for service %s: %w", t.Service, err)
+		return fmt.Errorf("failed to create inspector for service %s: %w", service, err)
 	}
 
 	// Fetch resource details
-	ctx := context.Background()
 	resource, err := inspectorClient.Fetch(ctx, t.ARN, config)
 	if err != nil {
-		return fmt.Errorf("failed to fetch resource details for ARN %s in service %s: %w", t.ARN, t.Service, err)
+		return fmt.Errorf("failed to fetch resource details for ARN %s in service %s: %w", t.ARN, service, err)
+	}
+
+	if len(t.Set) > 0 || len(t.Unset) > 0 {
+		return t.writeTags(ctx, service, regions, resource, logger)
 	}
 
 	// Prepare output
 	type TagsResult struct {
-		Resource string            `json:"resource" yaml:"resource"`
-		ARN      string            `json:"arn" yaml:"arn"`
-		Tags     map[string]string `json:"tags" yaml:"tags"`
+		Resource    string                 `json:"resource" yaml:"resource"`
+		ARN         string                 `json:"arn" yaml:"arn"`
+		Tags        map[string]string      `json:"tags" yaml:"tags"`
+		RawResponse map[string]interface{} `json:"raw_response,omitempty" yaml:"raw_response,omitempty"`
 	}
 
 	result := TagsResult{
@@ -87,19 +115,8 @@ type TagsResult struct {
 		ARN:      t.ARN,
 		Tags:     resource.Tags,
 	}
-
-	// Normalize output format
-	outputFormat := strings.ToLower(t.Output)
-
-	// Create output formatter
-	var formatter output.Formatter
-	switch outputFormat {
-	case "json":
-		formatter = output.NewJSONFormatter(false)
-	case "yaml", "yml":
-		formatter = output.NewYAMLFormatter(false)
-	default:
-		formatter = output.NewTableFormatter([]string{"Key", "Value"})
+	if t.IncludeRaw {
+		result.RawResponse = resource.RawResponseMap()
 	}
 
 	// Prepare clipboard output
@@ -124,13 +141,8 @@ type TagsResult struct {
 	}
 
 	// Check if output should be structured
-	if outputFormat == "json" || outputFormat == "yaml" || outputFormat == "yml" {
-		formattedOutput, err := formatter.Format(result)
-		if err != nil {
-			return fmt.Errorf("failed to format output: %w", err)
-		}
-		fmt.Println(formattedOutput)
-		return nil
+	if formatter != nil {
+		return printFormatted(formatter, result)
 	}
 
 	// Default table output
@@ -154,54 +166,65 @@ type TagsResult struct {
 }
 
 // Run implements the info query logic
-func (i *InfoCmd) Run() error {
+func (i *InfoCmd) Run(ctx context.Context) error {
 	logger := o11y.DefaultLogger()
 	logger.Info(fmt.Sprintf("🔍 Querying information for resource: %s", i.ARN))
 
-	regionOnARN := inspector.ExtractRegionFromARNOrDefault(i.ARN)
+	formatter, err := structuredFormatter(i.Output)
+	if err != nil {
+		return err
+	}
+
+	service, err := resolveService(i.ARN, i.Service)
+	if err != nil {
+		return err
+	}
+
+	// Regions are tried in order by inspectors locating resources whose ARN has no region
+	regions := inspector.QueryRegions(i.ARN, i.Region)
 
 	// Similar initialization as TagsCmd
 	config := configuration.TaggyScanConfig{
 		AWS: configuration.AWSConfig{
 			Regions: configuration.RegionsConfig{
 				Mode: "specific",
-				List: []string{regionOnARN},
+				List: regions,
 			},
+			EndpointURL: os.Getenv(configuration.EndpointURLEnvVar),
 		},
 		Resources: map[string]configuration.ResourceConfig{
-			i.Service: {
+			service: {
 				Enabled: true,
 			},
 		},
 	}
 
-	inspectorClient, err := inspector.New(i.Service, config)
+	inspectorClient, err := inspector.New(service, config)
 	if err != nil {
-		return fmt.Errorf("failed to create inspector for service %s: %w", i.Service, err)
+		return fmt.Errorf("failed to create inspector for service %s: %w", service, err)
 	}
 
-	ctx := context.Background()
 	resource, err := inspectorClient.Fetch(ctx, i.ARN, config)
 	if err != nil {
-		return fmt.Errorf("failed to fetch resource details for ARN %s in service %s: %w", i.ARN, i.Service, err)
+		return fmt.Errorf("failed to fetch resource details for ARN %s in service %s: %w", i.ARN, service, err)
 	}
 
-	// Normalize output format
-	outputFormat := strings.ToLower(i.Output)
-
 	// Prepare clipboard output
 	clipboardOutput := struct {
 		Service           string                 `json:"service" yaml:"service"`
 		Region            string                 `json:"region" yaml:"region"`
+		AccountID         string                 `json:"account_id,omitempty" yaml:"account_id,omitempty"`
 		ResourceID        string                 `json:"resource_id" yaml:"resource_id"`
 		ResourceType      string                 `json:"resource_type" yaml:"resource_type"`
 		ARN               string                 `json:"arn" yaml:"arn"`
 		TagCount          int                    `json:"tag_count" yaml:"tag_count"`
 		Tags              map[string]string      `json:"tags" yaml:"tags"`
 		AdditionalDetails map[string]interface{} `json:"additional_details,omitempty" yaml:"additional_details,omitempty"`
+		RawResponse       map[string]interface{} `json:"raw_response,omitempty" yaml:"raw_response,omitempty"`
 	}{
-		Service:           i.Service,
+		Service:           service,
 		Region:            resource.Region,
+		AccountID:         resource.AccountID,
 		ResourceID:        resource.ID,
 		ResourceType:      resource.Type,
 		ARN:               resource.Details.ARN,
@@ -209,16 +232,8 @@ func (i *InfoCmd) Run() error {
 		Tags:              resource.Tags,
 		AdditionalDetails: resource.Details.Properties,
 	}
-
-	// Create output formatter
-	var formatter output.Formatter
-	switch outputFormat {
-	case "json":
-		formatter = output.NewJSONFormatter(false)
-	case "yaml", "yml":
-		formatter = output.NewYAMLFormatter(false)
-	default:
-		formatter = output.NewTableFormatter([]string{"Property", "Value"})
+	if i.IncludeRaw {
+		clipboardOutput.RawResponse = resource.RawResponseMap()
 	}
 
 	// If clipboard flag is set, copy to clipboard in YAML
@@ -240,13 +255,8 @@ func (i *InfoCmd) Run() error {
 	}
 
 	// Check if output should be structured
-	if outputFormat == "json" || outputFormat == "yaml" || outputFormat == "yml" {
-		formattedOutput, err := formatter.Format(clipboardOutput)
-		if err != nil {
-			return fmt.Errorf("failed to format output: %w", err)
-		}
-		fmt.Println(formattedOutput)
-		return nil
+	if formatter != nil {
+		return printFormatted(formatter, clipboardOutput)
 	}
 
 	// Prepare table data for resource details
@@ -254,6 +264,7 @@ func (i *InfoCmd) Run() error {
 		{"ID", resource.ID},
 		{"Type", resource.Type},
 		{"Region", resource.Region},
+		{"Account ID", resource.AccountID},
 		{"Provider", resource.Provider},
 		{"Tag Count", fmt.Sprintf("%d", len(resource.Tags))},
 		{"ARN", resource.Details.ARN},
@@ -279,18 +290,29 @@ func (i *InfoCmd) Run() error {
 }
 
 // Helper functions
-func extractRegionFromARN(arn string) string {
-	parts := strings.Split(arn, ":")
-	if len(parts) >= 4 {
-		return parts[3]
+
+// resolveService returns the service given with --service, or the one inferred from the ARN
+func resolveService(resourceARN, service string) (string, error) {
+	if service != "" {
+		return service, nil
+	}
+
+	inferred, err := inspector.ResourceTypeFromARN(resourceARN)
+	if err != nil {
+		return "", fmt.Errorf("%w. Use --service to choose one", err)
 	}
-	return "us-east-1" // Default region if ARN parsing fails
+	return inferred, nil
 }
 
-func shortenARN(arn string) string {
-	parts := strings.Split(arn, "/")
-	if len(parts) > 1 {
-		return "..." + parts[len(parts)-1]
+// shortenARN returns the name of the resource of an ARN, such as the ID of an EC2 instance,
+// or the ARN unchanged when it cannot be parsed
+func shortenARN(resourceARN string) string {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return resourceARN
+	}
+	if _, name := parsed.ResourceTypeAndName(); name != "" {
+		return "..." + name
 	}
-	return arn
+	return resourceARN
 }
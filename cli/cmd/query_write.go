@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+)
+
+// writeTags applies the --set and --unset tag changes to the fetched resource, after showing
+// them and getting them confirmed
+func (t *TagsCmd) writeTags(ctx context.Context, service string, regions []string, resource *inspector.ResourceMetadata, logger *o11y.Logger) error {
+	change, err := inspector.ParseTagChange(t.Set, t.Unset)
+	if err != nil {
+		return err
+	}
+
+	// Refuse the tags AWS would reject before writing any of them
+	if violations := compliance.CheckAWSTagLimits(change.Set); len(violations) > 0 {
+		messages := make([]string, 0, len(violations))
+		for _, violation := range violations {
+			messages = append(messages, violation.Message)
+		}
+		return fmt.Errorf("refusing to write tags AWS would reject:\n  • %s", strings.Join(messages, "\n  • "))
+	}
+
+	writer, err := inspector.NewTagWriter(service, regions, os.Getenv(configuration.EndpointURLEnvVar))
+	if err != nil {
+		return err
+	}
+
+	after := change.Apply(resource.Tags)
+	diff := tagDiff(resource.Tags, after)
+	if len(diff) == 0 {
+		logger.Info(fmt.Sprintf("✅ Tags of %s already match, nothing to change", shortenARN(t.ARN)))
+		return nil
+	}
+
+	if t.Config != "" {
+		if err := t.validateTagChange(service, resource, change, after); err != nil {
+			return err
+		}
+	}
+
+	if err := tui.RenderTable(tui.TableOptions{
+		Title: fmt.Sprintf("🏷️  Tag changes for %s", shortenARN(t.ARN)),
+		Columns: []tui.Column{
+			{Title: "Key", Width: 30, Flexible: true},
+			{Title: "Before", Width: 40, Flexible: true},
+			{Title: "After", Width: 40, Flexible: true},
+		},
+		FlexibleColumns: true,
+		AutoWidth:       true,
+	}, diff); err != nil {
+		return err
+	}
+
+	if !t.Yes {
+		confirmed, err := confirm(fmt.Sprintf("Apply these tag changes to %s?", t.ARN))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			logger.Info("Tag changes cancelled")
+			return nil
+		}
+	}
+
+	if err := writer.WriteTags(ctx, t.ARN, change); err != nil {
+		return fmt.Errorf("failed to write tags of %s: %w", t.ARN, err)
+	}
+
+	logger.Info(fmt.Sprintf("✅ Tags of %s updated", shortenARN(t.ARN)))
+	return nil
+}
+
+// validateTagChange refuses tag changes whose new values violate the compliance configuration.
+// Violations of tags the change leaves untouched, such as missing required tags, are not
+// blocking as the change does not cause them.
+func (t *TagsCmd) validateTagChange(service string, resource *inspector.ResourceMetadata, change inspector.TagChange, after map[string]string) error {
+	cfg, err := configuration.NewTaggyScanConfigLoader().LoadConfig(t.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration from file %s: %w", t.Config, err)
+	}
+
+	result := compliance.NewTagValidator(cfg).ValidateResource(compliance.ResourceRef{
+		ID:   resource.ID,
+		ARN:  t.ARN,
+		Type: service,
+	}, after)
+
+	var violations []string
+	for _, violation := range result.Violations {
+		for key := range change.Set {
+			if strings.EqualFold(violation.TagKey, key) {
+				violations = append(violations, violation.Message)
+				break
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("refusing to write tags violating the policy of %s:\n  • %s", t.Config, strings.Join(violations, "\n  • "))
+	}
+	return nil
+}
+
+// tagDiff returns the key, previous value and new value of every tag that changes, sorted by
+// key. Missing tags are shown as "-".
+func tagDiff(before, after map[string]string) [][]string {
+	keys := make(map[string]bool)
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+
+	var diff [][]string
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		oldValue, hadTag := before[key]
+		newValue, hasTag := after[key]
+		if hadTag == hasTag && oldValue == newValue {
+			continue
+		}
+		if !hadTag {
+			oldValue = "-"
+		}
+		if !hasTag {
+			newValue = "-"
+		}
+		diff = append(diff, []string{key, oldValue, newValue})
+	}
+	return diff
+}
+
+// confirm asks a yes/no question on the terminal, defaulting to no
+func confirm(question string) (bool, error) {
+	fmt.Printf("%s [y/N]: ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, fmt.Errorf("failed to read confirmation, use --yes to apply the changes without it: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -1,33 +1,93 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/Excoriate/aws-taggy/cli/internal/tui"
+	"github.com/Excoriate/aws-taggy/pkg/cloud"
 	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/Excoriate/aws-taggy/pkg/version"
 	"github.com/alecthomas/kong"
 )
 
-// Version will be populated during build
-var version = "dev"
-
 // RootCmd represents the base command structure for aws-taggy
 type RootCmd struct {
-	Version bool `short:"v" help:"Display version information"`
-	Debug   bool `help:"Enable debug mode"`
+	Version   bool   `short:"v" help:"Display version information"`
+	Debug     bool   `help:"Enable debug mode (same as --log-level debug)"`
+	LogFormat string `help:"Log format: text (human readable) or json (one object per line)" enum:"text,json" default:"text"`
+	LogLevel  string `help:"Minimum level of the logged entries: debug, info, warn or error" enum:"debug,info,warn,error" default:"info"`
+
+	// Logs and progress go to stderr, stdout only carries the output of the commands
+	Quiet        bool `short:"q" help:"Suppress informational logging, only errors are logged to stderr"`
+	ProgressJSON bool `name:"progress-json" help:"Emit newline-delimited JSON progress events on stderr, e.g. to render a progress bar around aws-taggy"`
+
+	// AWS identity of every command, the default credential chain when unset
+	AWSProfile    string `name:"aws-profile" help:"AWS shared configuration profile to use instead of AWS_PROFILE" placeholder:"PROFILE"`
+	AWSRoleARN    string `name:"aws-role-arn" help:"IAM role to assume with the credentials of the profile, e.g. a read-only audit role" placeholder:"ARN"`
+	AWSExternalID string `name:"aws-external-id" help:"External ID passed when assuming --aws-role-arn" placeholder:"ID"`
 
 	// Subcommands
 	Discover   DiscoverCmd   `cmd:"" help:"Discover AWS resources"`
 	Config     ConfigCmd     `cmd:"" help:"Configuration management commands"`
 	Query      QueryCmd      `cmd:"" help:"Query AWS resource details"`
 	Compliance ComplianceCmd `cmd:"" help:"AWS resource tag compliance commands"`
+	Terraform  TerraformCmd  `cmd:"" help:"Terraform code generation commands"`
+	Cache      CacheCmd      `cmd:"" help:"Scan result cache commands"`
+	History    HistoryCmd    `cmd:"" help:"Tag history commands"`
+	Preflight  PreflightCmd  `cmd:"" help:"Check the AWS permissions of a scan before running it, without modifying any resource"`
+}
+
+// AfterApply configures the logger and progress reporter shared by every command and
+// inspector from the global logging flags, before the selected command runs. Both write to
+// stderr, so the JSON or YAML output of a command can be piped from stdout.
+func (r *RootCmd) AfterApply() error {
+	level, err := o11y.ParseLogLevel(r.LogLevel)
+	if err != nil {
+		return err
+	}
+	if r.Debug {
+		level = o11y.LogLevelDebug
+	}
+	if r.Quiet {
+		level = o11y.LogLevelError
+	}
+
+	format, err := o11y.ParseLogFormat(r.LogFormat)
+	if err != nil {
+		return err
+	}
+
+	o11y.SetDefaultLogger(o11y.NewLoggerWithFormat(os.Stderr, level, format))
+	if r.ProgressJSON {
+		o11y.SetDefaultProgress(o11y.NewProgressReporter(os.Stderr))
+	}
+	if !r.Quiet && !r.ProgressJSON {
+		fmt.Fprintln(os.Stderr, tui.GetBanner())
+	}
+
+	identity := cloud.Identity{
+		Profile:    r.AWSProfile,
+		RoleARN:    r.AWSRoleARN,
+		ExternalID: r.AWSExternalID,
+	}
+	if err := identity.Validate(); err != nil {
+		return fmt.Errorf("--aws-external-id requires --aws-role-arn")
+	}
+	cloud.SetDefaultIdentity(identity)
+	return nil
 }
 
 // Run implements the main logic for the root command
 func (r *RootCmd) Run() error {
 	if r.Version {
-		fmt.Printf("aws-taggy version %s\n", version)
+		fmt.Printf("aws-taggy version %s\n", version.Get())
 		return nil
 	}
 
@@ -46,9 +106,6 @@ func (r *RootCmd) Run() error {
 func NewRootCommand() *kong.Kong {
 	cli := &RootCmd{}
 
-	banner := tui.GetBanner()
-	fmt.Println(banner)
-
 	kongOptions := []kong.Option{
 		kong.Name(constants.AppName),
 		kong.Description(constants.AppDescription),
@@ -57,8 +114,13 @@ func NewRootCommand() *kong.Kong {
 			Compact: true,
 			Summary: true,
 		}),
+		kong.Help(func(options kong.HelpOptions, ctx *kong.Context) error {
+			fmt.Fprintln(ctx.Stdout, tui.GetBanner())
+			return kong.DefaultHelpPrinter(options, ctx)
+		}),
 		kong.Vars{
-			"version": version,
+			"version":        version,
+			outputFormatsVar: outputFormats(),
 		},
 	}
 
@@ -75,7 +137,7 @@ func NewRootCommand() *kong.Kong {
 func Execute() error {
 	parser := NewRootCommand()
 
-	ctx, err := parser.Parse(os.Args[1:])
+	kongCtx, err := parser.Parse(os.Args[1:])
 	if err != nil {
 		// If no arguments are provided, show help and exit successfully
 		if len(os.Args) == 1 {
@@ -86,5 +148,30 @@ func Execute() error {
 		parser.FatalIfErrorf(err)
 	}
 
-	return ctx.Run()
+	// Cancel the running command on SIGINT/SIGTERM, so scans unwind instead of waiting for
+	// every in-flight AWS call to finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	kongCtx.BindTo(ctx, (*context.Context)(nil))
+	return kongCtx.Run()
+}
+
+// logCallerIdentity logs the AWS account and principal a scan runs with, a warning when they
+// cannot be resolved
+func logCallerIdentity(ctx context.Context, manager *inspector.InspectorManager, logger *o11y.Logger) {
+	identity, err := manager.CallerIdentity(ctx)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Failed to resolve the AWS identity of the scan: %v", err))
+		return
+	}
+	logger.Info(fmt.Sprintf("🔐 Scanning as %s (account %s)", identity.ARN, identity.AccountID))
+}
+
+// withTimeout bounds ctx by the --timeout of a command, leaving it unbounded when zero
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, timeout, fmt.Errorf("timed out after %s", timeout))
 }
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// planFile is a Terraform plan with resources to validate and resources skipped by check-plan
+var planFile = filepath.Join("..", "..", "pkg", "tfplan", "testdata", "plan.json")
+
+// runCommand runs args through the root command as the binary does, returning what was
+// written to stdout and stderr along with the error of the command
+func runCommand(t *testing.T, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+
+	previousStdout, previousStderr := os.Stdout, os.Stderr
+	previousLogger, previousProgress := o11y.DefaultLogger(), o11y.DefaultProgress()
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = previousStdout, previousStderr
+		o11y.SetDefaultLogger(previousLogger)
+		o11y.SetDefaultProgress(previousProgress)
+	})
+
+	stdoutReader, stdoutWriter, pipeErr := os.Pipe()
+	require.NoError(t, pipeErr)
+	stderrReader, stderrWriter, pipeErr := os.Pipe()
+	require.NoError(t, pipeErr)
+	os.Stdout, os.Stderr = stdoutWriter, stderrWriter
+
+	stdoutData, stderrData := make(chan []byte), make(chan []byte)
+	go func() { data, _ := io.ReadAll(stdoutReader); stdoutData <- data }()
+	go func() { data, _ := io.ReadAll(stderrReader); stderrData <- data }()
+
+	parser := NewRootCommand()
+	kongCtx, err := parser.Parse(args)
+	if err == nil {
+		kongCtx.BindTo(context.Background(), (*context.Context)(nil))
+		err = kongCtx.Run()
+	}
+
+	require.NoError(t, stdoutWriter.Close())
+	require.NoError(t, stderrWriter.Close())
+	os.Stdout, os.Stderr = previousStdout, previousStderr
+	return string(<-stdoutData), string(<-stderrData), err
+}
+
+// writeConfig writes a configuration requiring the Environment and Owner tags on the
+// resources of the test plan, returning its path
+func writeConfig(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "tag-compliance.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`version: "1.1"
+global:
+  enabled: true
+  tag_criteria:
+    minimum_required_tags: 2
+    required_tags: [Environment, Owner]
+aws:
+  regions:
+    mode: specific
+    list: [us-east-1]
+resources:
+  s3:
+    enabled: true
+  ec2:
+    enabled: true
+  sqs:
+    enabled: true
+tag_validation:
+  key_validation:
+    max_length: 128
+`), 0o644))
+	return path
+}
+
+func TestRootCommandJSONOutputKeepsLogsOffStdout(t *testing.T) {
+	stdout, stderr, err := runCommand(t, "compliance", "check-plan",
+		"--config", writeConfig(t), "--plan", planFile, "--output", "json")
+	require.NoError(t, err)
+
+	// stdout only holds the report, the banner and the logs going to stderr
+	var report struct {
+		Summary struct {
+			TotalResources int `json:"total_resources"`
+		} `json:"summary"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &report), stdout)
+	assert.Equal(t, 3, report.Summary.TotalResources)
+	assert.Contains(t, stderr, "Checking the tags of the resources of Terraform")
+}
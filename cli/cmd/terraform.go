@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/Excoriate/aws-taggy/pkg/tfgen"
+)
+
+// TerraformCmd represents the terraform command with subcommands
+type TerraformCmd struct {
+	Tags TerraformTagsCmd `cmd:"" help:"Generate compliant Terraform tags for a resource type"`
+}
+
+// TerraformTagsCmd represents the command generating Terraform tags from the configuration
+type TerraformTagsCmd struct {
+	Config          string `help:"Path to the tag compliance configuration file" required:"true"`
+	Resource        string `help:"Resource type to generate tags for (e.g. s3, ec2)" required:"true"`
+	Style           string `help:"Terraform construct holding the tags (locals|resource|variable)" default:"locals" enum:"locals,resource,variable"`
+	MergeExpression bool   `help:"Emit resource-specific tags as merge(local.common_tags, { ... })"`
+	Output          string `short:"o" help:"Path of the Terraform file to write, printed to stdout when empty"`
+	CreateDirs      bool   `help:"Create the output directory if it does not exist"`
+	Overwrite       bool   `short:"f" help:"Force overwrite if the output file already exists"`
+}
+
+// Run implements the logic for generating Terraform tags
+func (t *TerraformTagsCmd) Run() error {
+	logger := o11y.DefaultLogger()
+
+	loader := configuration.NewTaggyScanConfigLoader()
+	cfg, err := loader.LoadConfig(t.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration file %s: %w", t.Config, err)
+	}
+
+	style, err := tfgen.ParseStyle(t.Style)
+	if err != nil {
+		return err
+	}
+
+	generator, err := tfgen.NewTagGenerator(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create tag generator: %w", err)
+	}
+
+	file, err := generator.GenerateTagsWithOptions(t.Resource, tfgen.GenerateOptions{
+		Style:           style,
+		MergeExpression: t.MergeExpression,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate Terraform tags: %w", err)
+	}
+
+	if t.Output == "" {
+		fmt.Print(string(file.Bytes()))
+		return nil
+	}
+
+	if err := t.prepareOutput(); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(t.Output, file.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write Terraform file %s: %w", t.Output, err)
+	}
+
+	logger.Info(fmt.Sprintf("✅ Terraform tags written to %s", t.Output))
+	return nil
+}
+
+// prepareOutput ensures the output file can be written, creating its directory when allowed
+func (t *TerraformTagsCmd) prepareOutput() error {
+	dir := filepath.Dir(t.Output)
+
+	info, err := os.Stat(dir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if !t.CreateDirs {
+			return fmt.Errorf("output directory %s does not exist, use --create-dirs to create it", dir)
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory %s: %w", dir, err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to access output directory %s: %w", dir, err)
+	case !info.IsDir():
+		return fmt.Errorf("output path parent %s is not a directory", dir)
+	}
+
+	if _, err := os.Stat(t.Output); err == nil && !t.Overwrite {
+		return fmt.Errorf("file %s already exists, use --overwrite to replace it", t.Output)
+	}
+
+	return nil
+}
@@ -1,27 +1,11 @@
 package normaliser
 
 import (
-	"strings"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
 )
 
-// NormalizeServiceName converts service names to a consistent lowercase format
-// Handles variations like "S3", "s3", "EC2", "ec2"
+// NormalizeServiceName converts service names to the resource type used in configuration
+// files. Handles variations like "S3", "s3", "EC2", "ec2" and aliases like "cloudwatch_alarms"
 func NormalizeServiceName(serviceName string) string {
-	return strings.ToLower(strings.TrimSpace(serviceName))
-}
-
-// NormalizeOutputFormat converts output format to a consistent lowercase format
-// Handles variations like "JSON", "json", "YAML", "yml"
-func NormalizeOutputFormat(format string) string {
-	format = strings.ToLower(strings.TrimSpace(format))
-	switch format {
-	case "json":
-		return "json"
-	case "yaml", "yml":
-		return "yaml"
-	case "table":
-		return "table"
-	default:
-		return "table" // Default to table if unrecognized
-	}
+	return configuration.NormalizeResourceType(serviceName)
 }
@@ -16,6 +16,9 @@ func TestNormalizeServiceName(t *testing.T) {
 		{"EC2", "ec2"},
 		{"ec2", "ec2"},
 		{" S3 ", "s3"},
+		{"cloudwatch", "cloudwatch"},
+		{"CloudWatch_Alarms", "cloudwatch"},
+		{"cloudwatch-logs", "cloudwatchlogs"},
 		{"", ""},
 	}
 
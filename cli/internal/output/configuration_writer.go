@@ -6,6 +6,7 @@
 	"path/filepath"
 
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
 	"gopkg.in/yaml.v3"
 )
 
@@ -70,7 +71,7 @@ func (w *ConfigurationWriter) WriteConfiguration(file string, overwrite bool) er
 	// Marshal the configuration to YAML
 	// Ensure version is set
 	if w.Config.Version == "" {
-		w.Config.Version = "1.0"
+		w.Config.Version = constants.SupportedConfigVersion
 	}
 	yamlData, err := yaml.Marshal(w.Config)
 	if err != nil {
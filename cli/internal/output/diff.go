@@ -0,0 +1,235 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Tag change kinds reported by a compliance diff
+const (
+	TagAdded   = "added"
+	TagRemoved = "removed"
+	TagChanged = "changed"
+)
+
+// TagChange describes how a single tag of a resource changed between two scans
+type TagChange struct {
+	Key      string `json:"key" yaml:"key"`
+	Change   string `json:"change" yaml:"change"`
+	OldValue string `json:"old_value,omitempty" yaml:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty" yaml:"new_value,omitempty"`
+}
+
+// ResourceDiff describes how a resource present in both scans changed
+type ResourceDiff struct {
+	ResourceID   string      `json:"resource_id" yaml:"resource_id"`
+	ResourceType string      `json:"resource_type" yaml:"resource_type"`
+	ResourceARN  string      `json:"resource_arn,omitempty" yaml:"resource_arn,omitempty"`
+	AccountID    string      `json:"account_id,omitempty" yaml:"account_id,omitempty"`
+	WasCompliant bool        `json:"was_compliant" yaml:"was_compliant"`
+	IsCompliant  bool        `json:"is_compliant" yaml:"is_compliant"`
+	TagChanges   []TagChange `json:"tag_changes,omitempty" yaml:"tag_changes,omitempty"`
+	Violations   []Violation `json:"violations,omitempty" yaml:"violations,omitempty"`
+}
+
+// DiffSummary counts the changes found between two scans
+type DiffSummary struct {
+	BaselineResources int `json:"baseline_resources" yaml:"baseline_resources"`
+	CurrentResources  int `json:"current_resources" yaml:"current_resources"`
+	Regressions       int `json:"regressions" yaml:"regressions"`
+	Fixed             int `json:"fixed" yaml:"fixed"`
+	TagChanges        int `json:"tag_changes" yaml:"tag_changes"`
+	NewResources      int `json:"new_resources" yaml:"new_resources"`
+	DeletedResources  int `json:"deleted_resources" yaml:"deleted_resources"`
+}
+
+// ComplianceDiff reports the drift between a baseline scan and a current scan.
+// A resource whose compliance flipped and whose tags changed is listed both in
+// Regressions or Fixed and in TagChanges.
+type ComplianceDiff struct {
+	Summary          DiffSummary         `json:"summary" yaml:"summary"`
+	Regressions      []*ResourceDiff     `json:"regressions" yaml:"regressions"`
+	Fixed            []*ResourceDiff     `json:"fixed" yaml:"fixed"`
+	TagChanges       []*ResourceDiff     `json:"tag_changes" yaml:"tag_changes"`
+	NewResources     []*ComplianceResult `json:"new_resources" yaml:"new_resources"`
+	DeletedResources []*ComplianceResult `json:"deleted_resources" yaml:"deleted_resources"`
+}
+
+// HasRegressions reports whether any resource went from compliant to non-compliant
+func (d *ComplianceDiff) HasRegressions() bool {
+	return len(d.Regressions) > 0
+}
+
+// DiffComplianceResults compares the results of two scans. Resources are matched by ARN,
+// falling back to their ID and type when either side has no ARN.
+func DiffComplianceResults(baseline, current []*ComplianceResult) *ComplianceDiff {
+	byARN := make(map[string]*ComplianceResult)
+	byID := make(map[string]*ComplianceResult)
+	for _, result := range baseline {
+		if result.ResourceARN != "" {
+			byARN[result.ResourceARN] = result
+		}
+		byID[resourceIdentity(result)] = result
+	}
+
+	diff := &ComplianceDiff{
+		Regressions:      []*ResourceDiff{},
+		Fixed:            []*ResourceDiff{},
+		TagChanges:       []*ResourceDiff{},
+		NewResources:     []*ComplianceResult{},
+		DeletedResources: []*ComplianceResult{},
+	}
+	matched := make(map[*ComplianceResult]bool, len(baseline))
+
+	for _, result := range sortedResults(current) {
+		previous, found := byARN[result.ResourceARN]
+		if result.ResourceARN == "" || !found {
+			// Resources whose ARNs are both known but differ are never the same resource
+			previous, found = byID[resourceIdentity(result)]
+			found = found && (previous.ResourceARN == "" || result.ResourceARN == "")
+		}
+		if !found || matched[previous] {
+			diff.NewResources = append(diff.NewResources, result)
+			continue
+		}
+		matched[previous] = true
+
+		change := &ResourceDiff{
+			ResourceID:   result.ResourceID,
+			ResourceType: result.ResourceType,
+			ResourceARN:  result.ResourceARN,
+			AccountID:    result.AccountID,
+			WasCompliant: previous.IsCompliant,
+			IsCompliant:  result.IsCompliant,
+			TagChanges:   diffTags(previous.ResourceTags, result.ResourceTags),
+		}
+
+		switch {
+		case previous.IsCompliant && !result.IsCompliant:
+			change.Violations = result.Violations
+			diff.Regressions = append(diff.Regressions, change)
+		case !previous.IsCompliant && result.IsCompliant:
+			diff.Fixed = append(diff.Fixed, change)
+		}
+
+		if len(change.TagChanges) > 0 {
+			diff.TagChanges = append(diff.TagChanges, change)
+		}
+	}
+
+	for _, result := range sortedResults(baseline) {
+		if !matched[result] {
+			diff.DeletedResources = append(diff.DeletedResources, result)
+		}
+	}
+
+	diff.Summary = DiffSummary{
+		BaselineResources: len(baseline),
+		CurrentResources:  len(current),
+		Regressions:       len(diff.Regressions),
+		Fixed:             len(diff.Fixed),
+		TagChanges:        len(diff.TagChanges),
+		NewResources:      len(diff.NewResources),
+		DeletedResources:  len(diff.DeletedResources),
+	}
+
+	return diff
+}
+
+// diffTags lists the tags added, removed or changed between two tag sets, sorted by key
+func diffTags(before, after map[string]string) []TagChange {
+	var changes []TagChange
+
+	for key, newValue := range after {
+		oldValue, existed := before[key]
+		switch {
+		case !existed:
+			changes = append(changes, TagChange{Key: key, Change: TagAdded, NewValue: newValue})
+		case oldValue != newValue:
+			changes = append(changes, TagChange{Key: key, Change: TagChanged, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+
+	for key, oldValue := range before {
+		if _, exists := after[key]; !exists {
+			changes = append(changes, TagChange{Key: key, Change: TagRemoved, OldValue: oldValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+// resourceIdentity identifies a resource by its type and ID
+func resourceIdentity(result *ComplianceResult) string {
+	return result.ResourceType + "/" + result.ResourceID
+}
+
+// sortedResults returns the results ordered by type and ID, so diffs are stable
+func sortedResults(results []*ComplianceResult) []*ComplianceResult {
+	sorted := make([]*ComplianceResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return resourceIdentity(sorted[i]) < resourceIdentity(sorted[j])
+	})
+	return sorted
+}
+
+// ReadComplianceResults reads the resource results of a compliance check output file:
+// the detailed JSON document written by --output-file, or newline-delimited JSON when the
+// file has the .ndjson extension.
+func ReadComplianceResults(path string) ([]*ComplianceResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open compliance results %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".ndjson") {
+		return readStreamedResults(path, file)
+	}
+
+	var document struct {
+		ResourceResults *[]*ComplianceResult `json:"resource_results"`
+	}
+	if err := json.NewDecoder(file).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to parse compliance results %s: %w", path, err)
+	}
+	if document.ResourceResults == nil {
+		return nil, fmt.Errorf("%s is not a compliance check output: no resource_results found", path)
+	}
+
+	return *document.ResourceResults, nil
+}
+
+// readStreamedResults decodes one result per line of a streamed compliance check output
+func readStreamedResults(path string, file *os.File) ([]*ComplianceResult, error) {
+	var results []*ComplianceResult
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var result ComplianceResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			return nil, fmt.Errorf("failed to parse line %d of %s: %w", line, path, err)
+		}
+		results = append(results, &result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read compliance results %s: %w", path, err)
+	}
+
+	return results, nil
+}
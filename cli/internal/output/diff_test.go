@@ -0,0 +1,168 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diffResult(id, arn string, compliant bool, tags map[string]string) *ComplianceResult {
+	return &ComplianceResult{
+		ResourceID:   id,
+		ResourceType: "s3",
+		ResourceARN:  arn,
+		IsCompliant:  compliant,
+		ResourceTags: tags,
+	}
+}
+
+func TestDiffComplianceResults(t *testing.T) {
+	baseline := []*ComplianceResult{
+		diffResult("logs", "arn:aws:s3:::logs", true, map[string]string{"Owner": "platform", "Environment": "production"}),
+		diffResult("assets", "arn:aws:s3:::assets", false, map[string]string{"Environment": "staging"}),
+		diffResult("backups", "arn:aws:s3:::backups", true, map[string]string{"Owner": "platform"}),
+		diffResult("legacy", "", true, map[string]string{"Owner": "data"}),
+	}
+
+	regressed := diffResult("logs", "arn:aws:s3:::logs", false, map[string]string{"Environment": "prod"})
+	regressed.Violations = []Violation{{Type: "missing_tags", Message: "Missing required tags: [Owner]"}}
+
+	current := []*ComplianceResult{
+		regressed,
+		diffResult("assets", "arn:aws:s3:::assets", true, map[string]string{"Environment": "staging", "Owner": "web"}),
+		// Matched by ID and type since the current scan has no ARN for it
+		diffResult("legacy", "arn:aws:s3:::legacy", true, map[string]string{"Owner": "data"}),
+		diffResult("reports", "arn:aws:s3:::reports", true, map[string]string{"Owner": "finance"}),
+	}
+
+	diff := DiffComplianceResults(baseline, current)
+
+	assert.Equal(t, DiffSummary{
+		BaselineResources: 4,
+		CurrentResources:  4,
+		Regressions:       1,
+		Fixed:             1,
+		TagChanges:        2,
+		NewResources:      1,
+		DeletedResources:  1,
+	}, diff.Summary)
+	assert.True(t, diff.HasRegressions())
+
+	require.Len(t, diff.Regressions, 1)
+	assert.Equal(t, "logs", diff.Regressions[0].ResourceID)
+	assert.True(t, diff.Regressions[0].WasCompliant)
+	assert.Equal(t, regressed.Violations, diff.Regressions[0].Violations)
+	assert.Equal(t, []TagChange{
+		{Key: "Environment", Change: TagChanged, OldValue: "production", NewValue: "prod"},
+		{Key: "Owner", Change: TagRemoved, OldValue: "platform"},
+	}, diff.Regressions[0].TagChanges)
+
+	require.Len(t, diff.Fixed, 1)
+	assert.Equal(t, "assets", diff.Fixed[0].ResourceID)
+	assert.Equal(t, []TagChange{{Key: "Owner", Change: TagAdded, NewValue: "web"}}, diff.Fixed[0].TagChanges)
+
+	require.Len(t, diff.NewResources, 1)
+	assert.Equal(t, "reports", diff.NewResources[0].ResourceID)
+	require.Len(t, diff.DeletedResources, 1)
+	assert.Equal(t, "backups", diff.DeletedResources[0].ResourceID)
+}
+
+func TestDiffComplianceResultsMatching(t *testing.T) {
+	testCases := []struct {
+		name         string
+		baseline     *ComplianceResult
+		current      *ComplianceResult
+		sameResource bool
+	}{
+		{
+			name:         "Same ARN with a different ID",
+			baseline:     diffResult("old-name", "arn:aws:s3:::bucket", true, nil),
+			current:      diffResult("new-name", "arn:aws:s3:::bucket", true, nil),
+			sameResource: true,
+		},
+		{
+			name:         "Same ID and type without ARNs",
+			baseline:     diffResult("bucket", "", true, nil),
+			current:      diffResult("bucket", "", true, nil),
+			sameResource: true,
+		},
+		{
+			name:         "Same ID and type with different ARNs",
+			baseline:     diffResult("queue", "arn:aws:sqs:us-east-1:111111111111:queue", true, nil),
+			current:      diffResult("queue", "arn:aws:sqs:us-east-1:222222222222:queue", true, nil),
+			sameResource: false,
+		},
+		{
+			name:         "Same ID with another type",
+			baseline:     diffResult("shared", "", true, nil),
+			current:      &ComplianceResult{ResourceID: "shared", ResourceType: "sqs", IsCompliant: true},
+			sameResource: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff := DiffComplianceResults([]*ComplianceResult{tc.baseline}, []*ComplianceResult{tc.current})
+
+			if tc.sameResource {
+				assert.Empty(t, diff.NewResources)
+				assert.Empty(t, diff.DeletedResources)
+			} else {
+				assert.Len(t, diff.NewResources, 1)
+				assert.Len(t, diff.DeletedResources, 1)
+			}
+		})
+	}
+}
+
+func TestReadComplianceResults(t *testing.T) {
+	dir := t.TempDir()
+	results := []*ComplianceResult{
+		diffResult("logs", "arn:aws:s3:::logs", true, map[string]string{"Owner": "platform"}),
+		diffResult("assets", "arn:aws:s3:::assets", false, nil),
+	}
+
+	t.Run("Detailed JSON output", func(t *testing.T) {
+		data, err := json.Marshal(map[string]interface{}{
+			"summary":          ComplianceSummary{TotalResources: 2},
+			"resource_results": results,
+		})
+		require.NoError(t, err)
+
+		path := filepath.Join(dir, "results.json")
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+
+		read, err := ReadComplianceResults(path)
+		require.NoError(t, err)
+		assert.Equal(t, results, read)
+	})
+
+	t.Run("Streamed NDJSON output", func(t *testing.T) {
+		path := filepath.Join(dir, "results.ndjson")
+		file, err := os.Create(path)
+		require.NoError(t, err)
+
+		stream := NewResultStream(file)
+		for _, result := range results {
+			require.NoError(t, stream.Write(result))
+		}
+		require.NoError(t, stream.Flush())
+		require.NoError(t, file.Close())
+
+		read, err := ReadComplianceResults(path)
+		require.NoError(t, err)
+		assert.Equal(t, results, read)
+	})
+
+	t.Run("Not a compliance output", func(t *testing.T) {
+		path := filepath.Join(dir, "other.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"version": "1.0"}`), 0o600))
+
+		_, err := ReadComplianceResults(path)
+		assert.ErrorContains(t, err, "no resource_results found")
+	})
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// trendLevels are the bar characters of a trend line, from 0% to 100% compliant
+var trendLevels = []rune("▁▂▃▄▅▆▇█")
+
+// SummaryRecord is a compliance summary kept in the history of a watch
+type SummaryRecord struct {
+	Time    time.Time
+	Summary ComplianceSummary
+}
+
+// CompliantPercentage returns the share of compliant resources, 100 when nothing was checked.
+// Resources with unknown status are not counted.
+func (r SummaryRecord) CompliantPercentage() float64 {
+	checked := r.Summary.CompliantResources + r.Summary.NonCompliantResources
+	if checked == 0 {
+		return 100
+	}
+	return float64(r.Summary.CompliantResources) / float64(checked) * 100
+}
+
+// SummaryHistory is a fixed size ring buffer of the most recent compliance summaries
+type SummaryHistory struct {
+	records []SummaryRecord
+	next    int
+	full    bool
+}
+
+// NewSummaryHistory creates a SummaryHistory keeping the last size summaries, at least one
+func NewSummaryHistory(size int) *SummaryHistory {
+	if size < 1 {
+		size = 1
+	}
+	return &SummaryHistory{records: make([]SummaryRecord, size)}
+}
+
+// Add records a summary, dropping the oldest one when the history is full
+func (h *SummaryHistory) Add(at time.Time, summary ComplianceSummary) {
+	h.records[h.next] = SummaryRecord{Time: at, Summary: summary}
+	h.next = (h.next + 1) % len(h.records)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Records returns the recorded summaries, oldest first
+func (h *SummaryHistory) Records() []SummaryRecord {
+	if !h.full {
+		return append([]SummaryRecord(nil), h.records[:h.next]...)
+	}
+	return append(append([]SummaryRecord(nil), h.records[h.next:]...), h.records[:h.next]...)
+}
+
+// Trend renders the compliant percentage of the recorded summaries as a line of bars, oldest
+// first, followed by the first and last percentage
+func (h *SummaryHistory) Trend() string {
+	records := h.Records()
+	if len(records) == 0 {
+		return ""
+	}
+
+	var bars strings.Builder
+	for _, record := range records {
+		level := int(record.CompliantPercentage() / 100 * float64(len(trendLevels)-1))
+		bars.WriteRune(trendLevels[level])
+	}
+
+	first, last := records[0].CompliantPercentage(), records[len(records)-1].CompliantPercentage()
+	return fmt.Sprintf("%s  %.1f%% → %.1f%% compliant over %d run(s)", bars.String(), first, last, len(records))
+}
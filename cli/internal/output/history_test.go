@@ -0,0 +1,44 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummaryHistory(t *testing.T) {
+	t.Parallel()
+
+	history := NewSummaryHistory(3)
+	assert.Empty(t, history.Records())
+	assert.Empty(t, history.Trend())
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, compliant := range []int{0, 2, 3, 4} {
+		history.Add(start.Add(time.Duration(i)*time.Minute), ComplianceSummary{
+			TotalResources:        4,
+			CompliantResources:    compliant,
+			NonCompliantResources: 4 - compliant,
+		})
+	}
+
+	records := history.Records()
+	require.Len(t, records, 3)
+	assert.Equal(t, start.Add(time.Minute), records[0].Time)
+	assert.Equal(t, []float64{50, 75, 100}, []float64{
+		records[0].CompliantPercentage(),
+		records[1].CompliantPercentage(),
+		records[2].CompliantPercentage(),
+	})
+	assert.Equal(t, "▄▆█  50.0% → 100.0% compliant over 3 run(s)", history.Trend())
+}
+
+func TestSummaryRecordCompliantPercentageIgnoresUnknown(t *testing.T) {
+	t.Parallel()
+
+	record := SummaryRecord{Summary: ComplianceSummary{TotalResources: 3, CompliantResources: 1, NonCompliantResources: 1, UnknownResources: 1}}
+	assert.Equal(t, 50.0, record.CompliantPercentage())
+	assert.Equal(t, 100.0, SummaryRecord{}.CompliantPercentage())
+}
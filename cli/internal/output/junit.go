@@ -0,0 +1,169 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FormatJUnit writes the compliance results as a JUnit XML report, rendered by CI test reporters
+const FormatJUnit Format = "junit"
+
+// junitTestSuites is the root element of a JUnit report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite holds the test case of every checked resource, along with the totals of the run
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+// junitProperty is a name and value recorded on a test suite
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// junitTestCase is the compliance check of a resource
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitFailure lists the violations of a non-compliant resource
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// junitSkipped tells why the compliance of a resource is unknown
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit writes the compliance results as a JUnit XML report. Every resource is a test
+// case named after its ARN, or its ID, in the class of its resource type: non-compliant
+// resources fail with their violations, resources whose tags could not be read are skipped.
+// The test suite carries the totals of the summary and the duration of the run. Tags and
+// violations are escaped, so values holding XML markup keep the report well-formed.
+func WriteJUnit(w io.Writer, results []*ComplianceResult, summary ComplianceSummary, startedAt time.Time, duration time.Duration) error {
+	suite := junitTestSuite{
+		Name:      "aws-taggy compliance",
+		Tests:     len(results),
+		Time:      junitSeconds(duration),
+		Timestamp: startedAt.UTC().Format(time.RFC3339),
+		Properties: []junitProperty{
+			{Name: "compliance_score", Value: fmt.Sprintf("%.1f", summary.ComplianceScore)},
+			{Name: "excluded_resources", Value: fmt.Sprintf("%d", summary.ExcludedResources)},
+		},
+		TestCases: make([]junitTestCase, 0, len(results)),
+	}
+
+	for _, result := range results {
+		testCase := junitTestCase{
+			ClassName: result.ResourceType,
+			Name:      result.ResourceID,
+			Time:      junitSeconds(0),
+			SystemOut: junitTags(result.ResourceTags),
+		}
+		if result.ResourceARN != "" {
+			testCase.Name = result.ResourceARN
+		}
+
+		switch {
+		case result.IsUnknown:
+			suite.Skipped++
+			testCase.Skipped = &junitSkipped{Message: fmt.Sprintf("Tags could not be read: %s", result.TagFetchError)}
+		case !result.IsCompliant:
+			suite.Failures++
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d tag compliance violation(s), score %.1f", len(result.Violations), result.Score),
+				Type:    "TagComplianceViolation",
+				Body:    junitViolations(result.Violations),
+			}
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	report := junitTestSuites{
+		Name:     suite.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	return nil
+}
+
+// junitViolations lists the violations of a resource, one per line
+func junitViolations(violations []Violation) string {
+	lines := make([]string, 0, len(violations))
+	for _, v := range violations {
+		line := fmt.Sprintf("%s: %s", v.Type, v.Describe())
+		if v.Severity != "" {
+			line = fmt.Sprintf("[%s] %s", v.Severity, line)
+		}
+		if v.DocURL != "" {
+			line += fmt.Sprintf(" - %s", v.DocURL)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// junitTags lists the tags of a resource in key order, one per line
+func junitTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("%s=%s", key, tags[key]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// junitSeconds formats a duration as the seconds of a JUnit time attribute
+func junitSeconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	t.Parallel()
+
+	results := []*ComplianceResult{
+		{
+			IsCompliant:  true,
+			Score:        100,
+			ResourceID:   "payments",
+			ResourceType: "s3",
+			ResourceARN:  "arn:aws:s3:::payments",
+			ResourceTags: map[string]string{"Owner": "payments"},
+		},
+		{
+			IsCompliant:  false,
+			Score:        60,
+			ResourceID:   "i-0abc",
+			ResourceType: "ec2",
+			ResourceTags: map[string]string{"Owner": `<script>alert("x")</script> & co`},
+			Violations: []Violation{
+				{Type: "pattern_violation", Message: "Tag value for 'Owner' does not match required pattern", TagKey: "Owner", Value: `<script>alert("x")</script> & co`, Severity: "high"},
+				{Type: "missing_tags", Message: "Missing required tags: [Environment]", Severity: "medium"},
+			},
+		},
+		{
+			IsUnknown:     true,
+			ResourceID:    "locked",
+			ResourceType:  "s3",
+			ResourceARN:   "arn:aws:s3:::locked",
+			TagFetchError: "AccessDenied <s3:GetBucketTagging>",
+		},
+	}
+	summary := ComplianceSummary{TotalResources: 3, CompliantResources: 1, NonCompliantResources: 1, UnknownResources: 1, ComplianceScore: 80}
+
+	var buf bytes.Buffer
+	startedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, WriteJUnit(&buf, results, summary, startedAt, 1500*time.Millisecond))
+
+	assert.NotContains(t, buf.String(), "<script>", "tag values must be escaped")
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, 3, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.Skipped)
+	require.Len(t, report.Suites, 1)
+
+	suite := report.Suites[0]
+	assert.Equal(t, "1.500", suite.Time)
+	assert.Equal(t, "2025-01-02T03:04:05Z", suite.Timestamp)
+	require.Len(t, suite.TestCases, 3)
+
+	compliant := suite.TestCases[0]
+	assert.Equal(t, "s3", compliant.ClassName)
+	assert.Equal(t, "arn:aws:s3:::payments", compliant.Name)
+	assert.Nil(t, compliant.Failure)
+	assert.Nil(t, compliant.Skipped)
+
+	failing := suite.TestCases[1]
+	assert.Equal(t, "ec2", failing.ClassName)
+	assert.Equal(t, "i-0abc", failing.Name, "resources without an ARN are named after their ID")
+	require.NotNil(t, failing.Failure)
+	assert.Contains(t, failing.Failure.Body, `[high] pattern_violation: Tag value for 'Owner' does not match required pattern, got '<script>alert("x")</script> & co'`)
+	assert.Contains(t, failing.Failure.Body, "[medium] missing_tags: Missing required tags: [Environment]")
+	assert.Equal(t, `Owner=<script>alert("x")</script> & co`, failing.SystemOut)
+
+	skipped := suite.TestCases[2]
+	require.NotNil(t, skipped.Skipped)
+	assert.Equal(t, "Tags could not be read: AccessDenied <s3:GetBucketTagging>", skipped.Skipped.Message)
+}
@@ -1,45 +1,46 @@
 package output
 
 import (
-	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 
-	"gopkg.in/yaml.v3"
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	pkgoutput "github.com/Excoriate/aws-taggy/pkg/output"
+	"github.com/Excoriate/aws-taggy/pkg/runner"
 )
 
-// ComplianceResult represents a single tag compliance validation result
-type ComplianceResult struct {
-	IsCompliant     bool              `json:"is_compliant" yaml:"is_compliant"`
-	ResourceTags    map[string]string `json:"resource_tags" yaml:"resource_tags"`
-	Violations      []Violation       `json:"violations,omitempty" yaml:"violations,omitempty"`
-	ComplianceLevel string            `json:"compliance_level,omitempty" yaml:"compliance_level,omitempty"`
-	ResourceID      string            `json:"resource_id" yaml:"resource_id"`
-	ResourceType    string            `json:"resource_type" yaml:"resource_type"`
-}
+// The compliance report types are produced by the runner package, the CLI only renders them
+type (
+	// ComplianceResult represents a single tag compliance validation result
+	ComplianceResult = runner.ResourceResult
 
-// Violation represents a specific tag compliance violation
-type Violation struct {
-	Type    string `json:"type" yaml:"type"`
-	Message string `json:"message" yaml:"message"`
-}
+	// Violation represents a specific tag compliance violation
+	Violation = runner.Violation
 
-// ComplianceSummary provides an overview of compliance results
-type ComplianceSummary struct {
-	TotalResources        int                    `json:"total_resources" yaml:"total_resources"`
-	CompliantResources    int                    `json:"compliant_resources" yaml:"compliant_resources"`
-	NonCompliantResources int                    `json:"non_compliant_resources" yaml:"non_compliant_resources"`
-	GlobalViolations      map[string]int         `json:"global_violations,omitempty" yaml:"global_violations,omitempty"`
-	RuleResults           map[string]*RuleResult `json:"rule_results,omitempty" yaml:"rule_results,omitempty"`
-}
+	// ComplianceSummary provides an overview of compliance results
+	ComplianceSummary = runner.Summary
 
-// RuleResult represents the result of a specific compliance rule
-type RuleResult struct {
-	Name        string `json:"name" yaml:"name"`
-	Description string `json:"description" yaml:"description"`
-	Passed      bool   `json:"passed" yaml:"passed"`
-	Failures    int    `json:"failures" yaml:"failures"`
-}
+	// ScanMetadata records how the checked resources were selected
+	ScanMetadata = runner.ScanMetadata
+
+	// GroupSummary provides compliance counts for the resources sharing a grouping key
+	GroupSummary = runner.GroupSummary
+
+	// ExcludedResource represents a resource skipped by a configured exclusion pattern
+	ExcludedResource = runner.ExcludedResource
+
+	// RuleResult represents the result of a specific compliance rule
+	RuleResult = runner.RuleResult
+
+	// IncrementalSummary counts how the resources of an incremental scan were inspected
+	IncrementalSummary = runner.IncrementalSummary
+
+	// DeletedResource represents a resource of the previous run an incremental scan no longer discovered
+	DeletedResource = runner.DeletedResource
+)
 
 // PlannedChecks represents the compliance checks that will be executed
 type PlannedChecks struct {
@@ -64,38 +65,50 @@ type ComplianceRule struct {
 	FormatTable Format = "table"
 )
 
-// Formatter handles the output formatting for different formats
+// Formatter handles the output formatting for different formats. Table output is rendered by
+// each command, the other formats by the formatter registered for them in pkg/output.
 type Formatter struct {
 	Format Format
+
+	formatter pkgoutput.Formatter
 }
 
-// NewFormatter creates a new Formatter instance
-func NewFormatter(format string) *Formatter {
-	switch format {
-	case string(FormatJSON):
-		return &Formatter{Format: FormatJSON}
-	case string(FormatYAML):
-		return &Formatter{Format: FormatYAML}
-	default:
-		return &Formatter{Format: FormatTable}
+// NewFormatter creates the formatter of a format, table when empty. Unknown formats fail
+// with the list of the registered ones.
+func NewFormatter(format string) (*Formatter, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" || format == string(FormatTable) {
+		return &Formatter{Format: FormatTable}, nil
+	}
+
+	formatter, err := pkgoutput.New(format)
+	if err != nil {
+		return nil, err
 	}
+	return &Formatter{Format: Format(format), formatter: formatter}, nil
 }
 
-// IsStructured returns true if the format is JSON or YAML
+// IsStructured returns true if the format is rendered by a registered formatter, such as JSON
+// or YAML, rather than as a table
 func (f *Formatter) IsStructured() bool {
-	return f.Format == FormatJSON || f.Format == FormatYAML
+	return f.formatter != nil
 }
 
 // Output formats and prints the data according to the specified format
 func (f *Formatter) Output(data interface{}) error {
-	switch f.Format {
-	case FormatJSON:
-		return outputJSON(data)
-	case FormatYAML:
-		return outputYAML(data)
-	default:
+	if f.formatter == nil {
 		return fmt.Errorf("unsupported output format: %s", f.Format)
 	}
+
+	rendered, err := f.formatter.Format(data)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(rendered, "\n") {
+		rendered += "\n"
+	}
+	_, err = fmt.Fprint(os.Stdout, rendered)
+	return err
 }
 
 // PrintConfigValidation prints a success message for configuration validation
@@ -117,24 +130,142 @@ func PrintComplianceSummary(summary ComplianceSummary) {
 	fmt.Printf("\n📊 Compliance Summary:\n\n")
 	fmt.Printf("Total Resources: %d\n", summary.TotalResources)
 	fmt.Printf("Compliant: %d\n", summary.CompliantResources)
-	fmt.Printf("Non-Compliant: %d\n\n", summary.NonCompliantResources)
+	fmt.Printf("Non-Compliant: %d\n", summary.NonCompliantResources)
+	if summary.UnknownResources > 0 {
+		fmt.Printf("Unknown (tags unreadable): %d\n", summary.UnknownResources)
+	}
+	fmt.Printf("Excluded: %d\n", summary.ExcludedResources)
+	if summary.SuppressedViolations > 0 {
+		fmt.Printf("Suppressed: %d\n", summary.SuppressedViolations)
+	}
+	if summary.Warnings > 0 {
+		fmt.Printf("Warnings: %d\n", summary.Warnings)
+	}
+	if summary.AutoFixableViolations > 0 || summary.ManualViolations > 0 {
+		fmt.Printf("Violations: %d auto-fixable, %d manual\n", summary.AutoFixableViolations, summary.ManualViolations)
+	}
+	fmt.Printf("Compliance Score: %.1f/100\n", summary.ComplianceScore)
+	if len(summary.ComplianceLevels) > 0 {
+		fmt.Printf("Compliance Levels: %s\n", FormatComplianceLevels(summary.ComplianceLevels))
+	}
+	fmt.Printf("\n")
+
+	if summary.Truncated() {
+		fmt.Printf("⚠️  Truncated: the max_resources_per_service or max_api_calls limit was hit, the results of %s are partial\n\n",
+			strings.Join(summary.TruncatedResults, ", "))
+	}
+
+	if sample := summary.Sample; sample != nil {
+		fmt.Printf("🎲 Sampled: %d of %d discovered resources (sample %s, seed %d), the counters above only cover the sample\n",
+			sample.SampledResources, sample.DiscoveredResources, sample.Sample, sample.Seed)
+		fmt.Printf("Estimated Total Resources: %d\n", sample.EstimatedTotalResources)
+		fmt.Printf("Estimated Compliant: %d\n", sample.EstimatedCompliantResources)
+		fmt.Printf("Estimated Non-Compliant: %d\n", sample.EstimatedNonCompliantResources)
+		if sample.EstimatedUnknownResources > 0 {
+			fmt.Printf("Estimated Unknown: %d\n", sample.EstimatedUnknownResources)
+		}
+		fmt.Printf("\n")
+	}
+
+	if summary.ScanMetadata != nil && summary.ScanMetadata.CallerARN != "" {
+		fmt.Printf("AWS Identity: %s (account %s)\n\n", summary.ScanMetadata.CallerARN, summary.ScanMetadata.AccountID)
+	}
+
+	if summary.ScanMetadata != nil && len(summary.ScanMetadata.Identities) > 0 {
+		fmt.Printf("Scanned As:\n")
+		for _, key := range slices.Sorted(maps.Keys(summary.ScanMetadata.Identities)) {
+			fmt.Printf("  🔐 %s: %s\n", key, summary.ScanMetadata.Identities[key])
+		}
+		fmt.Printf("\n")
+	}
+
+	if summary.ScanMetadata != nil && len(summary.ScanMetadata.TagFilters) > 0 {
+		fmt.Printf("Tag Filters: %s\n\n", strings.Join(summary.ScanMetadata.TagFilters, ", "))
+	}
+
+	if summary.ScanMetadata != nil && len(summary.ScanMetadata.NameFilters) > 0 {
+		fmt.Printf("Name Filters: %s\n\n", strings.Join(summary.ScanMetadata.NameFilters, ", "))
+	}
+
+	if summary.ScanMetadata != nil && summary.ScanMetadata.Throttles > 0 {
+		fmt.Printf("⏳ Throttled API Calls: %d (ended with a concurrency of %d)\n\n",
+			summary.ScanMetadata.Throttles, summary.ScanMetadata.EffectiveConcurrency)
+	}
+
+	if len(summary.Exclusions) > 0 {
+		fmt.Printf("Excluded Resources:\n")
+		for _, excluded := range summary.Exclusions {
+			reason := excluded.Reason
+			if reason == "" {
+				reason = fmt.Sprintf("matches pattern %s", excluded.Pattern)
+			}
+			fmt.Printf("  ⏭️  %s (%s): %s\n", excluded.ResourceID, excluded.ResourceType, reason)
+		}
+		fmt.Printf("\n")
+	}
+
+	if summary.Incremental != nil {
+		fmt.Printf("Incremental Scan: %d from the previous snapshot, %d freshly inspected, %d deleted\n\n",
+			summary.Incremental.FromSnapshot, summary.Incremental.Inspected, summary.Incremental.Deleted)
+	}
+
+	if len(summary.DeletedResources) > 0 {
+		fmt.Printf("Deleted Resources:\n")
+		for _, deleted := range summary.DeletedResources {
+			fmt.Printf("  🗑️  %s (%s)\n", deleted.ResourceID, deleted.ResourceType)
+		}
+		fmt.Printf("\n")
+	}
 
 	if len(summary.RuleResults) > 0 {
 		fmt.Printf("Rule Results:\n")
 		for _, result := range summary.RuleResults {
 			status := "✅"
-			if !result.Passed {
+			switch {
+			case result.IsSkipped():
+				status = "⏭️"
+			case !result.Passed:
 				status = "❌"
 			}
 			fmt.Printf("%s %s\n", status, result.Name)
 			fmt.Printf("   Description: %s\n", result.Description)
-			if !result.Passed {
+			if result.Skipped > 0 {
+				fmt.Printf("   Skipped: %d (disabled for %s)\n", result.Skipped, strings.Join(slices.Sorted(maps.Keys(result.SkippedByResourceType)), ", "))
+			}
+			if !result.Passed && !result.IsSkipped() {
 				fmt.Printf("   Failures: %d\n", result.Failures)
+				if resourceType, failures := result.TopResourceType(); resourceType != "" {
+					fmt.Printf("   Top Service: %s (%d failures)\n", resourceType, failures)
+				}
+				if len(result.ExampleResources) > 0 {
+					fmt.Printf("   Examples: %s\n", strings.Join(result.ExampleResources, ", "))
+				}
 			}
 			fmt.Printf("\n")
 		}
 	}
 
+	if len(summary.Groups) > 0 {
+		fmt.Printf("Compliance by %s:\n", summary.GroupBy)
+		for _, key := range runner.SortedGroupKeys(summary.Groups) {
+			group := summary.Groups[key]
+			fmt.Printf("  📁 %s: %d total, %d compliant, %d non-compliant\n",
+				key, group.TotalResources, group.CompliantResources, group.NonCompliantResources)
+			if len(group.ViolationTypes) > 0 {
+				fmt.Printf("     Top violations: %s\n", group.FormatTopViolations(runner.TopViolationsLimit))
+			}
+		}
+		fmt.Printf("\n")
+	}
+
+	if len(summary.ScanErrors) > 0 {
+		fmt.Printf("Scan Errors:\n")
+		for _, scanErr := range summary.ScanErrors {
+			fmt.Printf("  ⚠️  %s\n", scanErr)
+		}
+		fmt.Printf("\n")
+	}
+
 	if len(summary.GlobalViolations) > 0 {
 		fmt.Printf("Violation Types:\n")
 		for vType, count := range summary.GlobalViolations {
@@ -143,14 +274,14 @@ func PrintComplianceSummary(summary ComplianceSummary) {
 	}
 }
 
-func outputJSON(data interface{}) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(data)
-}
-
-func outputYAML(data interface{}) error {
-	encoder := yaml.NewEncoder(os.Stdout)
-	encoder.SetIndent(2)
-	return encoder.Encode(data)
+// FormatComplianceLevels lists the number of resources at each compliance level, from the
+// strictest level down to the resources meeting none
+func FormatComplianceLevels(levels map[string]int) string {
+	parts := make([]string, 0, len(levels))
+	for _, level := range append(compliance.ComplianceLevels(), compliance.ComplianceLevelNone) {
+		if count, ok := levels[string(level)]; ok {
+			parts = append(parts, fmt.Sprintf("%d at %s", count, level))
+		}
+	}
+	return strings.Join(parts, ", ")
 }
@@ -0,0 +1,57 @@
+package output
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureOutput runs fn with stdout and stderr redirected, returning what was written to each
+func captureOutput(t *testing.T, fn func()) (stdout, stderr string) {
+	t.Helper()
+
+	previousStdout, previousStderr := os.Stdout, os.Stderr
+	t.Cleanup(func() { os.Stdout, os.Stderr = previousStdout, previousStderr })
+
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	require.NoError(t, err)
+	stderrReader, stderrWriter, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout, os.Stderr = stdoutWriter, stderrWriter
+
+	// Pipes are drained while fn writes, so large outputs do not block it
+	stdoutData, stderrData := make(chan []byte), make(chan []byte)
+	go func() { data, _ := io.ReadAll(stdoutReader); stdoutData <- data }()
+	go func() { data, _ := io.ReadAll(stderrReader); stderrData <- data }()
+
+	fn()
+
+	require.NoError(t, stdoutWriter.Close())
+	require.NoError(t, stderrWriter.Close())
+	return string(<-stdoutData), string(<-stderrData)
+}
+
+func TestFormatterOutputQuietLogsOnlyErrors(t *testing.T) {
+	previousLogger := o11y.DefaultLogger()
+	t.Cleanup(func() { o11y.SetDefaultLogger(previousLogger) })
+
+	formatter, err := NewFormatter("yaml")
+	require.NoError(t, err)
+
+	stdout, stderr := captureOutput(t, func() {
+		// As configured by the root command with --quiet
+		o11y.SetDefaultLogger(o11y.NewLogger(os.Stderr, o11y.LogLevelError))
+
+		o11y.DefaultLogger().Info("🔍 Scanning AWS resources...")
+		require.NoError(t, formatter.Output(PlannedChecks{Rules: []ComplianceRule{{Name: "required_tags"}}}))
+		o11y.DefaultLogger().Error("Scanning rds failed")
+	})
+
+	assert.Equal(t, "rules:\n  - name: required_tags\n    description: \"\"\n", stdout)
+	assert.NotContains(t, stderr, "Scanning AWS resources")
+	assert.Contains(t, stderr, "Scanning rds failed")
+}
@@ -0,0 +1,155 @@
+package output
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	"github.com/Excoriate/aws-taggy/pkg/runner"
+)
+
+// RedactedValue replaces the values of redacted tags in the outputs
+const RedactedValue = "***"
+
+// Redactor masks the values of sensitive tags in the results rendered by the outputs. Tag
+// keys stay visible, so missing tags are still reported, and the results it is given are
+// left untouched, keeping the real values for the fix plan and the tag history.
+type Redactor struct {
+	keys map[string]bool
+}
+
+// NewRedactor creates a Redactor masking the values of the tag keys, matched
+// case-insensitively. It is nil when no key is given, and a nil Redactor masks nothing.
+func NewRedactor(keys []string) *Redactor {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	redactor := &Redactor{keys: make(map[string]bool, len(keys))}
+	for _, key := range keys {
+		redactor.keys[strings.ToLower(key)] = true
+	}
+	return redactor
+}
+
+// IsRedacted reports whether the values of the tag key are masked
+func (r *Redactor) IsRedacted(key string) bool {
+	return r != nil && r.keys[strings.ToLower(key)]
+}
+
+// Report returns a copy of the report whose resource results are redacted
+func (r *Redactor) Report(report *runner.ComplianceReport) *runner.ComplianceReport {
+	if r == nil || report == nil {
+		return report
+	}
+
+	redacted := *report
+	redacted.ResourceResults = make([]*ComplianceResult, 0, len(report.ResourceResults))
+	for _, result := range report.ResourceResults {
+		redacted.ResourceResults = append(redacted.ResourceResults, r.Result(result))
+	}
+	return &redacted
+}
+
+// Result returns a copy of the result where the values of the redacted tags are replaced
+// with RedactedValue, in the tags, the violations, the fix and the raw response, and
+// wherever else they appear in the messages of the result
+func (r *Redactor) Result(result *ComplianceResult) *ComplianceResult {
+	if r == nil || result == nil {
+		return result
+	}
+
+	var values []string
+	redacted := *result
+	redacted.ResourceTags = maps.Clone(result.ResourceTags)
+	for key, value := range result.ResourceTags {
+		if r.IsRedacted(key) {
+			redacted.ResourceTags[key] = RedactedValue
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return &redacted
+	}
+
+	// Longer values first, so a value containing another one is masked as a whole
+	slices.SortFunc(values, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	mask := func(s string) string {
+		for _, value := range values {
+			s = strings.ReplaceAll(s, value, RedactedValue)
+		}
+		return s
+	}
+
+	redacted.Violations = r.violations(result.Violations, mask)
+	redacted.SuppressedViolations = r.violations(result.SuppressedViolations, mask)
+	redacted.Warnings = r.violations(result.Warnings, mask)
+
+	if result.Fix != nil {
+		redacted.Fix = &compliance.TagFix{Set: make(map[string]string, len(result.Fix.Set)), Unset: result.Fix.Unset}
+		for key, value := range result.Fix.Set {
+			if r.IsRedacted(key) || mask(value) != value {
+				value = RedactedValue
+			}
+			redacted.Fix.Set[key] = value
+		}
+	}
+
+	if result.RawResponse != nil {
+		redacted.RawResponse, _ = redactRaw(result.RawResponse, mask).(map[string]interface{})
+	}
+
+	return &redacted
+}
+
+// violations returns copies of the violations masking the values of the redacted tags
+func (r *Redactor) violations(violations []Violation, mask func(string) string) []Violation {
+	if violations == nil {
+		return nil
+	}
+
+	redacted := make([]Violation, 0, len(violations))
+	for _, violation := range violations {
+		if r.IsRedacted(violation.TagKey) {
+			if violation.Value != "" {
+				violation.Value = RedactedValue
+			}
+			if violation.SuggestedValue != "" {
+				violation.SuggestedValue = RedactedValue
+			}
+		}
+		violation.Message = mask(violation.Message)
+		violation.Note = mask(violation.Note)
+		violation.Value = mask(violation.Value)
+		violation.Expected = mask(violation.Expected)
+		violation.SuggestedValue = mask(violation.SuggestedValue)
+		redacted = append(redacted, violation)
+	}
+	return redacted
+}
+
+// redactRaw returns a copy of a decoded JSON value whose strings are masked
+func redactRaw(value interface{}, mask func(string) string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, field := range v {
+			redacted[key] = redactRaw(field, mask)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			redacted = append(redacted, redactRaw(item, mask))
+		}
+		return redacted
+	case string:
+		return mask(v)
+	default:
+		return value
+	}
+}
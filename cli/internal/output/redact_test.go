@@ -0,0 +1,127 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	"github.com/Excoriate/aws-taggy/pkg/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// sensitiveValues are the values of the redacted tags of newRedactionReport
+var sensitiveValues = []string{"jane.doe@example.com", "PRJ-4711"}
+
+func newRedactionReport() *runner.ComplianceReport {
+	result := &ComplianceResult{
+		ResourceID:   "payments",
+		ResourceType: "s3",
+		ResourceARN:  "arn:aws:s3:::payments",
+		ResourceTags: map[string]string{
+			"Owner":       "jane.doe@example.com",
+			"project":     "PRJ-4711",
+			"Environment": "Production",
+		},
+		Violations: []Violation{
+			{
+				Type:           "pattern_violation",
+				Message:        "Tag value 'jane.doe@example.com' for 'Owner' does not match required pattern",
+				TagKey:         "Owner",
+				Value:          "jane.doe@example.com",
+				SuggestedValue: "jane.doe@example.org",
+			},
+			{Type: "case_violation", Message: "Tag value for 'Environment' must be lowercase", TagKey: "Environment", Value: "Production"},
+		},
+		SuppressedViolations: []Violation{
+			{Type: "allowed_values", Message: "Value PRJ-4711 is not allowed", TagKey: "project", Value: "PRJ-4711"},
+		},
+		Fix: &compliance.TagFix{Set: map[string]string{"Environment": "production", "owner": "jane.doe@example.com"}},
+		RawResponse: map[string]interface{}{
+			"Name": "payments",
+			"TagSet": []interface{}{
+				map[string]interface{}{"Key": "Owner", "Value": "jane.doe@example.com"},
+				map[string]interface{}{"Key": "project", "Value": "PRJ-4711"},
+			},
+		},
+	}
+
+	return &runner.ComplianceReport{
+		Summary:         ComplianceSummary{TotalResources: 1, NonCompliantResources: 1},
+		ResourceResults: []*ComplianceResult{result},
+	}
+}
+
+func assertRedacted(t *testing.T, output string) {
+	t.Helper()
+
+	for _, value := range sensitiveValues {
+		assert.NotContains(t, output, value)
+	}
+	assert.Contains(t, output, RedactedValue)
+}
+
+func TestRedactor_SerializedOutputs(t *testing.T) {
+	t.Parallel()
+
+	report := newRedactionReport()
+	redacted := NewRedactor([]string{"owner", "Project"}).Report(report)
+
+	jsonData, err := json.MarshalIndent(redacted, "", "  ")
+	require.NoError(t, err)
+	assertRedacted(t, string(jsonData))
+
+	yamlData, err := yaml.Marshal(redacted)
+	require.NoError(t, err)
+	assertRedacted(t, string(yamlData))
+
+	var junit bytes.Buffer
+	require.NoError(t, WriteJUnit(&junit, redacted.ResourceResults, redacted.Summary, time.Now(), time.Second))
+	assertRedacted(t, junit.String())
+
+	var stream bytes.Buffer
+	resultStream := NewResultStream(&stream)
+	for _, result := range redacted.ResourceResults {
+		require.NoError(t, resultStream.Write(result))
+	}
+	require.NoError(t, resultStream.Flush())
+	assertRedacted(t, stream.String())
+
+	// Keys stay visible and other values are untouched
+	result := redacted.ResourceResults[0]
+	assert.Equal(t, RedactedValue, result.ResourceTags["Owner"])
+	assert.Equal(t, "Production", result.ResourceTags["Environment"])
+	assert.Equal(t, RedactedValue, result.Violations[0].Value)
+	assert.Equal(t, RedactedValue, result.Violations[0].SuggestedValue)
+	assert.Equal(t, "Production", result.Violations[1].Value)
+	assert.Equal(t, "production", result.Fix.Set["Environment"])
+	assert.Equal(t, RedactedValue, result.Fix.Set["owner"])
+}
+
+func TestRedactor_KeepsOriginalResults(t *testing.T) {
+	t.Parallel()
+
+	report := newRedactionReport()
+	NewRedactor([]string{"Owner", "project"}).Report(report)
+
+	original := report.ResourceResults[0]
+	assert.Equal(t, "jane.doe@example.com", original.ResourceTags["Owner"])
+	assert.Equal(t, "jane.doe@example.com", original.Violations[0].Value)
+	assert.Equal(t, "jane.doe@example.com", original.Fix.Set["owner"])
+	assert.Equal(t, "PRJ-4711", original.RawResponse["TagSet"].([]interface{})[1].(map[string]interface{})["Value"])
+}
+
+func TestRedactor_Nil(t *testing.T) {
+	t.Parallel()
+
+	redactor := NewRedactor(nil)
+	assert.Nil(t, redactor)
+	assert.False(t, redactor.IsRedacted("Owner"))
+
+	report := newRedactionReport()
+	assert.Same(t, report, redactor.Report(report))
+	assert.Same(t, report.ResourceResults[0], redactor.Result(report.ResourceResults[0]))
+}
@@ -21,8 +21,8 @@ func RenderDefaultOutput(result *ValidationResult) error {
 	if !result.Valid {
 		fmt.Printf("❌ Configuration validation failed for %s\n\n", result.File)
 		fmt.Println("Errors:")
-		for _, err := range result.Errors {
-			fmt.Printf("  - %s\n", err)
+		for i, err := range result.Errors {
+			fmt.Printf("  %d. %s\n", i+1, err)
 		}
 		return fmt.Errorf("configuration is invalid")
 	}
@@ -0,0 +1,85 @@
+package output
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// Keys the resource results of the table and detailed outputs can be sorted by
+const (
+	SortByViolations = "violations"
+	SortByID         = "id"
+	SortByType       = "type"
+	SortByRegion     = "region"
+)
+
+// ResultSelection picks and orders the resource results listed by the table and detailed
+// outputs. Structured outputs always hold every result.
+type ResultSelection struct {
+	// OnlyNonCompliant leaves compliant resources out, resources of unknown compliance are kept
+	OnlyNonCompliant bool
+
+	// SortBy is the key results are sorted by, the order of the scan is kept when empty
+	SortBy string
+
+	// Descending sorts by the key in descending order
+	Descending bool
+}
+
+// ValidateSortKey checks that results can be sorted by the key
+func ValidateSortKey(key string) error {
+	switch key {
+	case SortByViolations, SortByID, SortByType, SortByRegion:
+		return nil
+	}
+	return fmt.Errorf("unsupported sort key %q, expected one of: %s, %s, %s or %s",
+		key, SortByViolations, SortByID, SortByType, SortByRegion)
+}
+
+// Apply returns the selected results in their order, leaving the given slice untouched.
+// Results equal under the sort key are ordered by ID, type, region and ARN, so repeated runs
+// list them in the same order whatever the order of the scan.
+func (s ResultSelection) Apply(results []*ComplianceResult) []*ComplianceResult {
+	selected := make([]*ComplianceResult, 0, len(results))
+	for _, result := range results {
+		if s.OnlyNonCompliant && result.IsCompliant {
+			continue
+		}
+		selected = append(selected, result)
+	}
+
+	if s.SortBy == "" {
+		return selected
+	}
+
+	slices.SortStableFunc(selected, func(a, b *ComplianceResult) int {
+		if order := compareBy(a, b, s.SortBy); order != 0 {
+			if s.Descending {
+				return -order
+			}
+			return order
+		}
+		return cmp.Or(
+			cmp.Compare(a.ResourceID, b.ResourceID),
+			cmp.Compare(a.ResourceType, b.ResourceType),
+			cmp.Compare(a.Region, b.Region),
+			cmp.Compare(a.ResourceARN, b.ResourceARN),
+		)
+	})
+	return selected
+}
+
+// compareBy compares two results by a sort key
+func compareBy(a, b *ComplianceResult, key string) int {
+	switch key {
+	case SortByViolations:
+		return cmp.Compare(len(a.Violations), len(b.Violations))
+	case SortByType:
+		return cmp.Compare(a.ResourceType, b.ResourceType)
+	case SortByRegion:
+		return cmp.Compare(a.Region, b.Region)
+	default:
+		return cmp.Compare(a.ResourceID, b.ResourceID)
+	}
+}
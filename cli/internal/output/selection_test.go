@@ -0,0 +1,97 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func selectionResult(id, resourceType, region string, violations int) *ComplianceResult {
+	result := &ComplianceResult{
+		ResourceID:   id,
+		ResourceType: resourceType,
+		Region:       region,
+		IsCompliant:  violations == 0,
+	}
+	for range violations {
+		result.Violations = append(result.Violations, Violation{Type: "missing_tags"})
+	}
+	return result
+}
+
+func resultIDs(results []*ComplianceResult) []string {
+	ids := make([]string, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.ResourceID)
+	}
+	return ids
+}
+
+func TestResultSelection(t *testing.T) {
+	t.Parallel()
+
+	results := []*ComplianceResult{
+		selectionResult("logs", "s3", "us-east-1", 2),
+		selectionResult("web", "ec2", "eu-west-1", 0),
+		selectionResult("assets", "s3", "us-east-1", 2),
+		selectionResult("queue", "sqs", "eu-west-1", 1),
+		selectionResult("db", "rds", "us-east-1", 3),
+	}
+	unknown := selectionResult("secret", "s3", "us-east-1", 1)
+	unknown.IsUnknown = true
+	results = append(results, unknown)
+
+	tests := []struct {
+		name      string
+		selection ResultSelection
+		expected  []string
+	}{
+		{
+			name:     "Scan order",
+			expected: []string{"logs", "web", "assets", "queue", "db", "secret"},
+		},
+		{
+			name:      "Only non-compliant, unknown included",
+			selection: ResultSelection{OnlyNonCompliant: true},
+			expected:  []string{"logs", "assets", "queue", "db", "secret"},
+		},
+		{
+			name:      "Most violations first, ties by ID",
+			selection: ResultSelection{OnlyNonCompliant: true, SortBy: SortByViolations, Descending: true},
+			expected:  []string{"db", "assets", "logs", "queue", "secret"},
+		},
+		{
+			name:      "By region",
+			selection: ResultSelection{SortBy: SortByRegion},
+			expected:  []string{"queue", "web", "assets", "db", "logs", "secret"},
+		},
+		{
+			name:      "By type descending",
+			selection: ResultSelection{SortBy: SortByType, Descending: true},
+			expected:  []string{"queue", "assets", "logs", "secret", "db", "web"},
+		},
+		{
+			name:      "By ID",
+			selection: ResultSelection{SortBy: SortByID},
+			expected:  []string{"assets", "db", "logs", "queue", "secret", "web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, resultIDs(tt.selection.Apply(results)))
+		})
+	}
+
+	assert.Equal(t, "logs", results[0].ResourceID, "the given results keep their order")
+}
+
+func TestValidateSortKey(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []string{SortByViolations, SortByID, SortByType, SortByRegion} {
+		assert.NoError(t, ValidateSortKey(key))
+	}
+	assert.Error(t, ValidateSortKey("score"))
+}
@@ -0,0 +1,75 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ResultStream writes compliance results as newline-delimited JSON as soon as they are
+// produced. Results are not retained: only the counters needed by the summary are kept,
+// so memory usage does not grow with the number of resources.
+type ResultStream struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	summary ComplianceSummary
+
+	// scoreTotal is the sum of the scores written so far, averaged by Summary
+	scoreTotal float64
+}
+
+// NewResultStream creates a ResultStream writing to w
+func NewResultStream(w io.Writer) *ResultStream {
+	writer := bufio.NewWriter(w)
+	return &ResultStream{
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+		summary: ComplianceSummary{
+			GlobalViolations: make(map[string]int),
+		},
+	}
+}
+
+// Write encodes a result as a single JSON line and records it in the summary counters
+func (s *ResultStream) Write(result *ComplianceResult) error {
+	if err := s.encoder.Encode(result); err != nil {
+		return fmt.Errorf("failed to write result of resource %s: %w", result.ResourceID, err)
+	}
+
+	s.summary.TotalResources++
+	s.summary.SuppressedViolations += len(result.SuppressedViolations)
+	s.summary.Warnings += len(result.Warnings)
+	if result.IsUnknown {
+		s.summary.UnknownResources++
+		return nil
+	}
+
+	s.scoreTotal += result.Score
+	if result.IsCompliant {
+		s.summary.CompliantResources++
+		return nil
+	}
+
+	s.summary.NonCompliantResources++
+	for _, violation := range result.Violations {
+		s.summary.GlobalViolations[violation.Type]++
+	}
+
+	return nil
+}
+
+// Flush writes any buffered results to the underlying writer
+func (s *ResultStream) Flush() error {
+	return s.writer.Flush()
+}
+
+// Summary returns the counters of the results written so far
+func (s *ResultStream) Summary() ComplianceSummary {
+	summary := s.summary
+	summary.ComplianceScore = 100
+	if scored := summary.TotalResources - summary.UnknownResources; scored > 0 {
+		summary.ComplianceScore = s.scoreTotal / float64(scored)
+	}
+	return summary
+}
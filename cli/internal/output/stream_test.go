@@ -0,0 +1,108 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syntheticResult builds the i-th result of a synthetic scan, every third one non-compliant
+func syntheticResult(i int) *ComplianceResult {
+	result := &ComplianceResult{
+		IsCompliant:  i%3 != 0,
+		Score:        100,
+		ResourceID:   fmt.Sprintf("resource-%06d", i),
+		ResourceType: "s3",
+		ResourceARN:  fmt.Sprintf("arn:aws:s3:::resource-%06d", i),
+		AccountID:    "123456789012",
+		ResourceTags: map[string]string{
+			"Environment": "production",
+			"Owner":       "platform@company.com",
+			"Project":     fmt.Sprintf("project-%d", i%100),
+		},
+	}
+	if !result.IsCompliant {
+		result.Score = 60
+		result.Violations = []Violation{
+			{Type: "missing_tags", Message: "Missing required tags: [CostCenter]"},
+		}
+	}
+	return result
+}
+
+func TestResultStream(t *testing.T) {
+	var buf bytes.Buffer
+	stream := NewResultStream(&buf)
+
+	for i := 0; i < 9; i++ {
+		require.NoError(t, stream.Write(syntheticResult(i)))
+	}
+	require.NoError(t, stream.Flush())
+
+	scanner := bufio.NewScanner(&buf)
+	lines := 0
+	for scanner.Scan() {
+		var result ComplianceResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &result))
+		assert.Equal(t, fmt.Sprintf("resource-%06d", lines), result.ResourceID)
+		lines++
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, 9, lines)
+
+	summary := stream.Summary()
+	assert.Equal(t, 9, summary.TotalResources)
+	assert.Equal(t, 6, summary.CompliantResources)
+	assert.Equal(t, 3, summary.NonCompliantResources)
+	assert.Equal(t, map[string]int{"missing_tags": 3}, summary.GlobalViolations)
+	assert.InDelta(t, (6*100.0+3*60.0)/9, summary.ComplianceScore, 0.001)
+}
+
+func TestResultStreamBoundedMemory(t *testing.T) {
+	const resources = 50000
+
+	heapInUse := func() uint64 {
+		runtime.GC()
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		return stats.HeapAlloc
+	}
+
+	stream := NewResultStream(io.Discard)
+	before := heapInUse()
+	for i := 0; i < resources; i++ {
+		require.NoError(t, stream.Write(syntheticResult(i)))
+	}
+	require.NoError(t, stream.Flush())
+	after := heapInUse()
+
+	// Retaining the results alone would take tens of megabytes
+	var growth uint64
+	if after > before {
+		growth = after - before
+	}
+	assert.Less(t, growth, uint64(4<<20), "heap grew by %d bytes while streaming %d results", growth, resources)
+	assert.Equal(t, resources, stream.Summary().TotalResources)
+}
+
+func BenchmarkResultStream(b *testing.B) {
+	stream := NewResultStream(io.Discard)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := stream.Write(syntheticResult(i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := stream.Flush(); err != nil {
+		b.Fatal(err)
+	}
+}
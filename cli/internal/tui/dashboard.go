@@ -0,0 +1,454 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ResourceStatus is the compliance status of a resource listed by the dashboard
+type ResourceStatus string
+
+const (
+	StatusCompliant    ResourceStatus = "compliant"
+	StatusNonCompliant ResourceStatus = "non-compliant"
+	StatusUnknown      ResourceStatus = "unknown"
+)
+
+// statusFilters is the order the f key cycles through the status filters, all statuses first
+var statusFilters = []ResourceStatus{"", StatusNonCompliant, StatusCompliant, StatusUnknown}
+
+// DashboardResource is a resource listed by the dashboard. Result is what the resource is
+// exported as, e.g. its compliance result.
+type DashboardResource struct {
+	ID         string
+	Service    string
+	Region     string
+	Status     ResourceStatus
+	Score      float64
+	Tags       map[string]string
+	Violations []string
+	Result     interface{}
+}
+
+// DashboardOptions contains configuration options for the dashboard
+type DashboardOptions struct {
+	Title string
+
+	// ExportDir is the directory the e key exports the listed resources to, the current
+	// directory when empty
+	ExportDir string
+}
+
+// IsInteractiveTerminal reports whether stdin and stdout are terminals, so an interactive
+// view can be drawn and driven by the keyboard
+func IsInteractiveTerminal() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	for _, file := range []*os.File{os.Stdin, os.Stdout} {
+		info, err := file.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// RunDashboard opens the dashboard of the resources in the alternate screen, until it is quit
+func RunDashboard(opts DashboardOptions, resources []DashboardResource) error {
+	if _, err := tea.NewProgram(newDashboardModel(opts, resources), tea.WithAltScreen()).Run(); err != nil {
+		return fmt.Errorf("failed to run the dashboard: %w", err)
+	}
+	return nil
+}
+
+// dashboardPane is the pane of the dashboard the keys apply to
+type dashboardPane int
+
+const (
+	servicesPane dashboardPane = iota
+	resourcesPane
+	detailPane
+)
+
+// dashboardModel is the bubbletea model of the dashboard: the services on the left, the
+// resources of the selected service matching the status filter and the search on the right,
+// and the detail of a resource in place of both
+type dashboardModel struct {
+	opts      DashboardOptions
+	resources []DashboardResource
+
+	// services lists the services of the resources, the first entry, empty, standing for all
+	services []string
+	counts   map[string]int
+
+	pane    dashboardPane
+	service int
+	cursor  int
+	offset  int
+
+	status    int
+	query     string
+	searching bool
+
+	// visible holds the indexes of the resources listed in the resources pane
+	visible []int
+
+	message       string
+	width, height int
+	now           func() time.Time
+}
+
+func newDashboardModel(opts DashboardOptions, resources []DashboardResource) *dashboardModel {
+	m := &dashboardModel{
+		opts:      opts,
+		resources: resources,
+		services:  []string{""},
+		counts:    map[string]int{"": len(resources)},
+		pane:      resourcesPane,
+		now:       time.Now,
+	}
+
+	for _, resource := range resources {
+		if m.counts[resource.Service] == 0 {
+			m.services = append(m.services, resource.Service)
+		}
+		m.counts[resource.Service]++
+	}
+	slices.Sort(m.services[1:])
+
+	m.filter()
+	return m
+}
+
+// filter lists the resources of the selected service matching the status filter and the
+// search, keeping the cursor within them
+func (m *dashboardModel) filter() {
+	service := m.services[m.service]
+	status := statusFilters[m.status]
+	query := strings.ToLower(m.query)
+
+	m.visible = m.visible[:0]
+	for i, resource := range m.resources {
+		if service != "" && resource.Service != service {
+			continue
+		}
+		if status != "" && resource.Status != status {
+			continue
+		}
+		if query != "" && !resource.matches(query) {
+			continue
+		}
+		m.visible = append(m.visible, i)
+	}
+
+	m.cursor = min(m.cursor, max(len(m.visible)-1, 0))
+	m.offset = min(m.offset, m.cursor)
+}
+
+// matches reports whether the ID, region, tags or violations of the resource contain the
+// lowercase query
+func (r DashboardResource) matches(query string) bool {
+	fields := []string{r.ID, r.Region}
+	for key, value := range r.Tags {
+		fields = append(fields, key+"="+value)
+	}
+	fields = append(fields, r.Violations...)
+
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// Init implements tea.Model
+func (m *dashboardModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+		return m.updateKey(msg)
+	}
+	return m, nil
+}
+
+// updateSearch edits the search query, filtering the resources as it is typed
+func (m *dashboardModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEnter:
+		m.searching = false
+	case tea.KeyEsc:
+		m.searching = false
+		m.query = ""
+	case tea.KeyBackspace:
+		if runes := []rune(m.query); len(runes) > 0 {
+			m.query = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.query += " "
+	case tea.KeyRunes:
+		m.query += string(msg.Runes)
+	}
+	m.filter()
+	return m, nil
+}
+
+// updateKey applies a key to the focused pane
+func (m *dashboardModel) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.message = ""
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		if m.pane == detailPane {
+			m.pane = resourcesPane
+		}
+	case "tab", "left", "right", "h", "l":
+		switch m.pane {
+		case servicesPane:
+			m.pane = resourcesPane
+		case resourcesPane:
+			m.pane = servicesPane
+		}
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "enter":
+		switch {
+		case m.pane == servicesPane:
+			m.pane = resourcesPane
+		case m.pane == resourcesPane && len(m.visible) > 0:
+			m.pane = detailPane
+		}
+	case "f":
+		if m.pane != detailPane {
+			m.status = (m.status + 1) % len(statusFilters)
+			m.filter()
+		}
+	case "/":
+		if m.pane != detailPane {
+			m.searching = true
+		}
+	case "e":
+		path, err := m.export()
+		if err != nil {
+			m.message = fmt.Sprintf("❌ %v", err)
+		} else {
+			m.message = fmt.Sprintf("✅ Exported %d resources to %s", len(m.visible), path)
+		}
+	}
+	return m, nil
+}
+
+// move moves the cursor of the focused pane
+func (m *dashboardModel) move(delta int) {
+	switch m.pane {
+	case servicesPane:
+		m.service = min(max(m.service+delta, 0), len(m.services)-1)
+		m.cursor, m.offset = 0, 0
+		m.filter()
+	case resourcesPane:
+		m.cursor = min(max(m.cursor+delta, 0), max(len(m.visible)-1, 0))
+		rows := m.listHeight()
+		if m.cursor < m.offset {
+			m.offset = m.cursor
+		} else if m.cursor >= m.offset+rows {
+			m.offset = m.cursor - rows + 1
+		}
+	}
+}
+
+// export writes the results of the listed resources to a JSON file of the export directory
+func (m *dashboardModel) export() (string, error) {
+	results := make([]interface{}, 0, len(m.visible))
+	for _, i := range m.visible {
+		results = append(results, m.resources[i].Result)
+	}
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the listed resources: %w", err)
+	}
+
+	path := filepath.Join(m.opts.ExportDir, fmt.Sprintf("aws-taggy-dashboard-%s.json", m.now().Format("20060102-150405")))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to export the listed resources to %s: %w", path, err)
+	}
+	return path, nil
+}
+
+var (
+	dashboardTitleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("252"))
+	dashboardSelectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57"))
+	dashboardHelpStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// paneStyle returns the style of a pane, its border highlighted when it is focused
+func (m *dashboardModel) paneStyle(pane dashboardPane, width int) lipgloss.Style {
+	color := lipgloss.Color("240")
+	if m.pane == pane {
+		color = lipgloss.Color("62")
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(color).
+		Width(width).
+		Height(m.listHeight())
+}
+
+// listHeight is the number of rows of the panes, leaving room for the header and the help
+func (m *dashboardModel) listHeight() int {
+	if m.height == 0 {
+		return 20
+	}
+	return max(m.height-6, 3)
+}
+
+// View implements tea.Model
+func (m *dashboardModel) View() string {
+	if m.pane == detailPane {
+		return m.detailView()
+	}
+
+	width := m.width
+	if width == 0 {
+		width = 120
+	}
+	servicesWidth := min(30, width/3)
+	resourcesWidth := max(width-servicesWidth-4, 20)
+
+	status := "all statuses"
+	if filter := statusFilters[m.status]; filter != "" {
+		status = string(filter)
+	}
+	header := dashboardTitleStyle.Render(fmt.Sprintf("%s — %d of %d resources (%s)", m.opts.Title, len(m.visible), len(m.resources), status))
+
+	panes := lipgloss.JoinHorizontal(lipgloss.Top,
+		m.paneStyle(servicesPane, servicesWidth).Render(m.servicesView(servicesWidth)),
+		m.paneStyle(resourcesPane, resourcesWidth).Render(m.resourcesView(resourcesWidth)),
+	)
+
+	footer := dashboardHelpStyle.Render("↑/↓ move • tab switch pane • enter details • f status filter • / search • e export • q quit")
+	switch {
+	case m.searching:
+		footer = fmt.Sprintf("/%s█ (enter to apply, esc to clear)", m.query)
+	case m.message != "":
+		footer = m.message
+	case m.query != "":
+		footer = fmt.Sprintf("search: %s • %s", m.query, footer)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, panes, footer)
+}
+
+// servicesView lists the services with their number of resources
+func (m *dashboardModel) servicesView(width int) string {
+	lines := make([]string, 0, len(m.services))
+	for i, service := range m.services {
+		name := service
+		if name == "" {
+			name = "All services"
+		}
+		line := truncate(fmt.Sprintf("%s (%d)", name, m.counts[service]), width)
+		if i == m.service {
+			line = dashboardSelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// resourcesView lists the rows of the visible resources that fit the pane
+func (m *dashboardModel) resourcesView(width int) string {
+	if len(m.visible) == 0 {
+		return "No resources match the filters"
+	}
+
+	end := min(m.offset+m.listHeight(), len(m.visible))
+	lines := make([]string, 0, end-m.offset)
+	for row := m.offset; row < end; row++ {
+		resource := m.resources[m.visible[row]]
+		line := truncate(fmt.Sprintf("%s %-40s %-10s %-15s %5.1f", statusMark(resource.Status), resource.ID, resource.Service, resource.Region, resource.Score), width)
+		if row == m.cursor && m.pane == resourcesPane {
+			line = dashboardSelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// detailView shows the tags and violations of the selected resource
+func (m *dashboardModel) detailView() string {
+	resource := m.resources[m.visible[m.cursor]]
+
+	var b strings.Builder
+	b.WriteString(dashboardTitleStyle.Render(fmt.Sprintf("%s %s", statusMark(resource.Status), resource.ID)))
+	fmt.Fprintf(&b, "\n\nService: %s\nRegion: %s\nStatus: %s\nScore: %.1f\n", resource.Service, resource.Region, resource.Status, resource.Score)
+
+	b.WriteString("\nTags:\n")
+	if len(resource.Tags) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	keys := make([]string, 0, len(resource.Tags))
+	for key := range resource.Tags {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "  %s: %s\n", key, resource.Tags[key])
+	}
+
+	b.WriteString("\nViolations:\n")
+	if len(resource.Violations) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for _, violation := range resource.Violations {
+		fmt.Fprintf(&b, "  • %s\n", violation)
+	}
+
+	b.WriteString("\n" + dashboardHelpStyle.Render("esc back • q quit"))
+	return b.String()
+}
+
+// statusMark returns the mark of a compliance status
+func statusMark(status ResourceStatus) string {
+	switch status {
+	case StatusCompliant:
+		return "✅"
+	case StatusNonCompliant:
+		return "❌"
+	default:
+		return "❔"
+	}
+}
+
+// truncate cuts a line to the width of a pane
+func truncate(line string, width int) string {
+	runes := []rune(line)
+	if width <= 1 || len(runes) <= width {
+		return line
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDashboard(t *testing.T) *dashboardModel {
+	t.Helper()
+
+	m := newDashboardModel(DashboardOptions{Title: "test", ExportDir: t.TempDir()}, []DashboardResource{
+		{ID: "payments", Service: "s3", Region: "eu-west-1", Status: StatusCompliant, Tags: map[string]string{"Owner": "payments"}, Result: "payments"},
+		{ID: "scratch", Service: "s3", Region: "eu-west-1", Status: StatusNonCompliant, Violations: []string{"missing required tag Owner"}, Result: "scratch"},
+		{ID: "i-0abc", Service: "ec2", Region: "us-east-1", Status: StatusNonCompliant, Tags: map[string]string{"Owner": "web"}, Result: "i-0abc"},
+		{ID: "legacy", Service: "rds", Region: "us-east-1", Status: StatusUnknown, Result: "legacy"},
+	})
+	m.now = func() time.Time { return time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC) }
+	return m
+}
+
+// press sends keys to the dashboard, runes being typed one key each
+func press(m *dashboardModel, keys ...tea.KeyMsg) tea.Cmd {
+	var cmd tea.Cmd
+	for _, key := range keys {
+		_, cmd = m.Update(key)
+	}
+	return cmd
+}
+
+func typed(text string) []tea.KeyMsg {
+	keys := make([]tea.KeyMsg, 0, len(text))
+	for _, r := range text {
+		keys = append(keys, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return keys
+}
+
+// visibleIDs returns the IDs of the resources listed in the resources pane
+func visibleIDs(m *dashboardModel) []string {
+	ids := make([]string, 0, len(m.visible))
+	for _, i := range m.visible {
+		ids = append(ids, m.resources[i].ID)
+	}
+	return ids
+}
+
+func TestDashboardServices(t *testing.T) {
+	t.Parallel()
+
+	m := newTestDashboard(t)
+	assert.Equal(t, []string{"", "ec2", "rds", "s3"}, m.services)
+	assert.Equal(t, 4, m.counts[""])
+	assert.Equal(t, 2, m.counts["s3"])
+	assert.Len(t, m.visible, 4)
+
+	// Selecting s3 in the services pane lists its resources only
+	press(m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, servicesPane, m.pane)
+	assert.Equal(t, []string{"payments", "scratch"}, visibleIDs(m))
+	assert.Contains(t, m.View(), "s3 (2)")
+}
+
+func TestDashboardStatusFilterAndSearch(t *testing.T) {
+	t.Parallel()
+
+	m := newTestDashboard(t)
+
+	press(m, typed("f")...)
+	assert.Equal(t, []string{"scratch", "i-0abc"}, visibleIDs(m))
+	assert.Contains(t, m.View(), "non-compliant")
+
+	press(m, typed("/web")...)
+	assert.True(t, m.searching)
+	assert.Equal(t, []string{"i-0abc"}, visibleIDs(m), "tags are searched as key=value")
+
+	press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, m.searching)
+	assert.Empty(t, m.query)
+	assert.Equal(t, []string{"scratch", "i-0abc"}, visibleIDs(m))
+
+	press(m, typed("fff")...)
+	assert.Len(t, m.visible, 4, "the status filter cycles back to every status")
+}
+
+func TestDashboardDetail(t *testing.T) {
+	t.Parallel()
+
+	m := newTestDashboard(t)
+
+	press(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, detailPane, m.pane)
+	view := m.View()
+	assert.Contains(t, view, "scratch")
+	assert.Contains(t, view, "missing required tag Owner")
+
+	press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, resourcesPane, m.pane)
+
+	cmd := press(m, typed("q")...)
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+}
+
+func TestDashboardExport(t *testing.T) {
+	t.Parallel()
+
+	m := newTestDashboard(t)
+	press(m, typed("/us-east")...)
+	press(m, tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+
+	path := filepath.Join(m.opts.ExportDir, "aws-taggy-dashboard-20261016-093000.json")
+	assert.Contains(t, m.message, "Exported 2 resources to "+path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var exported []string
+	require.NoError(t, json.Unmarshal(data, &exported))
+	assert.Equal(t, []string{"i-0abc", "legacy"}, exported)
+}
@@ -209,7 +209,6 @@ func RenderTable(opts TableOptions, data [][]string) error {
 		table.WithColumns(columns),
 		table.WithRows(rows),
 		table.WithFocused(true),
-		table.WithHeight(len(rows)),
 	)
 
 	s := table.DefaultStyles()
@@ -223,6 +222,9 @@ func RenderTable(opts TableOptions, data [][]string) error {
 
 	t.SetStyles(s)
 
+	// The height of the table includes its header, sized once its style is set
+	t.SetHeight(len(rows) + lipgloss.Height(s.Header.Render("")))
+
 	// Print title if provided
 	if opts.Title != "" {
 		fmt.Println(opts.Title)
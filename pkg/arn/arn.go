@@ -0,0 +1,118 @@
+// Package arn parses and builds Amazon Resource Names,
+// arn:partition:service:region:account-id:resource, in every partition (aws, aws-us-gov,
+// aws-cn). The inspectors read the identifiers of the resources they fetch from ARNs with it.
+package arn
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	prefix = "arn"
+
+	// sections is the number of colon separated sections of an ARN, the resource being the
+	// last one and possibly holding colons itself
+	sections = 6
+)
+
+// ARN is an Amazon Resource Name
+type ARN struct {
+	// Partition is the partition of the resource, aws, aws-us-gov or aws-cn
+	Partition string
+
+	// Service is the namespace of the AWS service of the resource, such as ec2 or s3
+	Service string
+
+	// Region is the region of the resource, empty for global resources such as S3 buckets
+	Region string
+
+	// AccountID is the ID of the account owning the resource, empty for some resources such
+	// as S3 buckets
+	AccountID string
+
+	// Resource identifies the resource within the service, such as instance/i-0abc, a bucket
+	// name or log-group:/app/web:*. Its format depends on the service.
+	Resource string
+}
+
+// Parse parses an ARN. The partition, service and resource of the ARN must not be empty,
+// the region and account ID may be. Everything after the fifth colon is the resource, so
+// resources holding colons, like log groups and RDS databases, are kept whole.
+func Parse(s string) (ARN, error) {
+	parts := strings.SplitN(s, ":", sections)
+	if parts[0] != prefix {
+		return ARN{}, fmt.Errorf("invalid ARN %s: it does not start with %s:", s, prefix)
+	}
+	if len(parts) != sections {
+		return ARN{}, fmt.Errorf("invalid ARN %s: expected arn:partition:service:region:account-id:resource", s)
+	}
+
+	parsed := ARN{
+		Partition: parts[1],
+		Service:   parts[2],
+		Region:    parts[3],
+		AccountID: parts[4],
+		Resource:  parts[5],
+	}
+	switch {
+	case parsed.Partition == "":
+		return ARN{}, fmt.Errorf("invalid ARN %s: empty partition", s)
+	case parsed.Service == "":
+		return ARN{}, fmt.Errorf("invalid ARN %s: empty service", s)
+	case parsed.Resource == "":
+		return ARN{}, fmt.Errorf("invalid ARN %s: empty resource", s)
+	}
+	return parsed, nil
+}
+
+// ParseService parses the ARN of a resource of a service, such as ec2 or logs
+func ParseService(s, service string) (ARN, error) {
+	parsed, err := Parse(s)
+	if err != nil {
+		return ARN{}, err
+	}
+	if parsed.Service != service {
+		return ARN{}, fmt.Errorf("invalid ARN %s: expected a %s ARN, got a %s one", s, service, parsed.Service)
+	}
+	return parsed, nil
+}
+
+// ParseRegionalService parses the ARN of a regional resource of a service, whose region
+// must not be empty
+func ParseRegionalService(s, service string) (ARN, error) {
+	parsed, err := ParseService(s, service)
+	if err != nil {
+		return ARN{}, err
+	}
+	if parsed.Region == "" {
+		return ARN{}, fmt.Errorf("invalid ARN %s: empty region, %s resources are regional", s, service)
+	}
+	return parsed, nil
+}
+
+// String returns the ARN, arn:partition:service:region:account-id:resource
+func (a ARN) String() string {
+	return strings.Join([]string{prefix, a.Partition, a.Service, a.Region, a.AccountID, a.Resource}, ":")
+}
+
+// ResourceTypeAndName splits the resource of the ARN, such as "stream/orders" or
+// "stateMachine:orders", into its resource type and name at its first slash or colon.
+// Resources without a type, such as S3 buckets, only have a name.
+func (a ARN) ResourceTypeAndName() (string, string) {
+	if i := strings.IndexAny(a.Resource, "/:"); i >= 0 {
+		return a.Resource[:i], a.Resource[i+1:]
+	}
+	return "", a.Resource
+}
+
+// ResourceName returns the name of the resource of the ARN when it is of the given type,
+// such as the instance ID of instance/i-0abc or the database of db:orders, and false when
+// the resource is of another type or its name is empty
+func (a ARN) ResourceName(resourceType string) (string, bool) {
+	actualType, name := a.ResourceTypeAndName()
+	if actualType != resourceType || name == "" {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,225 @@
+package arn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seeds are valid and malformed ARNs of the services aws-taggy inspects
+var seeds = []string{
+	"arn:aws:s3:::my-bucket",
+	"arn:aws:s3:::my:bucket",
+	"arn:aws:ec2:us-east-1:123456789012:instance/i-0abc",
+	"arn:aws-us-gov:ec2:us-gov-west-1:123456789012:vpc/vpc-0abc",
+	"arn:aws-cn:rds:cn-north-1:123456789012:db:orders",
+	"arn:aws:logs:eu-west-1:123456789012:log-group:/app/web:*",
+	"arn:aws:cloudwatch:us-east-1:123456789012:alarm:TargetTracking-table/orders:AlarmHigh",
+	"arn:aws:apigateway:us-east-1::/restapis/a1b2c3",
+	"arn:aws:route53:::hostedzone/Z123",
+	"arn:aws:sqs:eu-west-1:123456789012:orders",
+	"arn:aws:s3:::",
+	"arn::s3:::my-bucket",
+	"arn:aws:s3::",
+	"arn:aws",
+	"my-bucket",
+	"",
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		arn      string
+		expected ARN
+		errMsg   string
+	}{
+		{
+			name:     "S3 Bucket",
+			arn:      "arn:aws:s3:::my-bucket",
+			expected: ARN{Partition: "aws", Service: "s3", Resource: "my-bucket"},
+		},
+		{
+			name:     "EC2 Instance In GovCloud",
+			arn:      "arn:aws-us-gov:ec2:us-gov-west-1:123456789012:instance/i-0abc",
+			expected: ARN{Partition: "aws-us-gov", Service: "ec2", Region: "us-gov-west-1", AccountID: "123456789012", Resource: "instance/i-0abc"},
+		},
+		{
+			name:     "Resource With Colons",
+			arn:      "arn:aws:logs:eu-west-1:123456789012:log-group:/app/web:*",
+			expected: ARN{Partition: "aws", Service: "logs", Region: "eu-west-1", AccountID: "123456789012", Resource: "log-group:/app/web:*"},
+		},
+		{
+			name:   "Not An ARN",
+			arn:    "my-bucket",
+			errMsg: "invalid ARN my-bucket: it does not start with arn:",
+		},
+		{
+			name:   "Missing Sections",
+			arn:    "arn:aws:s3::my-bucket",
+			errMsg: "expected arn:partition:service:region:account-id:resource",
+		},
+		{
+			name:   "Empty Partition",
+			arn:    "arn::s3:::my-bucket",
+			errMsg: "empty partition",
+		},
+		{
+			name:   "Empty Service",
+			arn:    "arn:aws::us-east-1:123456789012:orders",
+			errMsg: "empty service",
+		},
+		{
+			name:   "Empty Resource",
+			arn:    "arn:aws:s3:::",
+			errMsg: "empty resource",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := Parse(tt.arn)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, parsed)
+			assert.Equal(t, tt.arn, parsed.String())
+		})
+	}
+}
+
+func TestParseService(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := ParseService("arn:aws:sqs:eu-west-1:123456789012:orders", "sqs")
+	require.NoError(t, err)
+	assert.Equal(t, "orders", parsed.Resource)
+
+	_, err = ParseService("arn:aws:sns:eu-west-1:123456789012:orders", "sqs")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected a sqs ARN, got a sns one")
+
+	_, err = ParseService("orders", "sqs")
+	assert.Error(t, err)
+}
+
+func TestParseRegionalService(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := ParseRegionalService("arn:aws-cn:ec2:cn-north-1:123456789012:instance/i-0abc", "ec2")
+	require.NoError(t, err)
+	assert.Equal(t, "cn-north-1", parsed.Region)
+
+	_, err = ParseRegionalService("arn:aws:ec2::123456789012:instance/i-0abc", "ec2")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "empty region, ec2 resources are regional")
+
+	_, err = ParseRegionalService("arn:aws:s3:::my-bucket", "ec2")
+	assert.Error(t, err)
+}
+
+func TestARN_ResourceName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		resource     string
+		resourceType string
+		typeOf       string
+		name         string
+		found        bool
+	}{
+		{resource: "instance/i-0abc", resourceType: "instance", typeOf: "instance", name: "i-0abc", found: true},
+		{resource: "db:orders", resourceType: "db", typeOf: "db", name: "orders", found: true},
+		{resource: "log-group:/app/web:*", resourceType: "log-group", typeOf: "log-group", name: "/app/web:*", found: true},
+		{resource: "alarm:table/orders:AlarmHigh", resourceType: "alarm", typeOf: "alarm", name: "table/orders:AlarmHigh", found: true},
+		{resource: "vpc/vpc-0abc", resourceType: "instance", typeOf: "vpc", name: "vpc-0abc"},
+		{resource: "instance/", resourceType: "instance", typeOf: "instance"},
+		{resource: "my-bucket", resourceType: "", typeOf: "", name: "my-bucket", found: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.resource, func(t *testing.T) {
+			t.Parallel()
+
+			parsed := ARN{Partition: "aws", Service: "ec2", Resource: tt.resource}
+			resourceType, name := parsed.ResourceTypeAndName()
+			assert.Equal(t, tt.typeOf, resourceType)
+
+			resourceName, found := parsed.ResourceName(tt.resourceType)
+			assert.Equal(t, tt.found, found)
+			if tt.found {
+				assert.Equal(t, tt.name, resourceName)
+				assert.Equal(t, tt.name, name)
+			}
+		})
+	}
+}
+
+// FuzzParse checks Parse never panics, and that the ARNs it accepts are returned unchanged
+// by String and parse back to the same ARN
+func FuzzParse(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		parsed, err := Parse(s)
+		if err != nil {
+			return
+		}
+
+		if parsed.String() != s {
+			t.Fatalf("Parse(%q).String() = %q", s, parsed.String())
+		}
+		reparsed, err := Parse(parsed.String())
+		if err != nil {
+			t.Fatalf("Parse(%q) of a parsed ARN failed: %v", parsed.String(), err)
+		}
+		if reparsed != parsed {
+			t.Fatalf("Parse(%q) = %+v, expected %+v", s, reparsed, parsed)
+		}
+		if !strings.HasPrefix(s, "arn:") || parsed.Partition == "" || parsed.Service == "" || parsed.Resource == "" {
+			t.Fatalf("Parse(%q) accepted an invalid ARN: %+v", s, parsed)
+		}
+
+		resourceType, name := parsed.ResourceTypeAndName()
+		if resourceType != "" && len(resourceType)+1+len(name) != len(parsed.Resource) {
+			t.Fatalf("ResourceTypeAndName of %q = %q, %q", parsed.Resource, resourceType, name)
+		}
+		if resourceName, found := parsed.ResourceName(resourceType); found && resourceName != name {
+			t.Fatalf("ResourceName(%q) of %q = %q, expected %q", resourceType, parsed.Resource, resourceName, name)
+		}
+	})
+}
+
+// FuzzString checks ARNs built from their fields parse back to the same ARN
+func FuzzString(f *testing.F) {
+	f.Add("aws", "s3", "", "", "my-bucket")
+	f.Add("aws-cn", "logs", "cn-north-1", "123456789012", "log-group:/app/web:*")
+	f.Add("aws", "ec2", "us-east-1", "123456789012", "instance/i-0abc")
+
+	f.Fuzz(func(t *testing.T, partition, service, region, accountID, resource string) {
+		built := ARN{Partition: partition, Service: service, Region: region, AccountID: accountID, Resource: resource}
+
+		parsed, err := Parse(built.String())
+		// Fields holding colons shift the sections of the ARN, only its resource may hold some
+		if partition == "" || service == "" || resource == "" || strings.ContainsRune(partition+service+region+accountID, ':') {
+			return
+		}
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", built.String(), err)
+		}
+		if parsed != built {
+			t.Fatalf("Parse(%q) = %+v, expected %+v", built.String(), parsed, built)
+		}
+	})
+}
@@ -8,6 +8,9 @@
 	"github.com/Excoriate/aws-taggy/pkg/util"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 // AWSClientConfig defines the configuration interface for AWS client creation
@@ -20,6 +23,12 @@ type AWSClientConfig interface {
 // AWSClientConfigOptions implements AWSClientConfig
 type AWSClientConfigOptions struct {
 	Region string
+
+	// RoleARN, when set, is assumed through STS on top of the default credentials
+	RoleARN string
+
+	// ExternalID is passed to AssumeRole when RoleARN is set
+	ExternalID string
 }
 
 func (c *AWSClientConfigOptions) GetRegion() string {
@@ -36,21 +45,74 @@ func (c *AWSClientConfigOptions) Validate() error {
 	return nil
 }
 
+// LoadConfig loads the configuration of the region with the credentials of the default
+// identity, assuming the role of the options on top of them when one is set
 func (c *AWSClientConfigOptions) LoadConfig(ctx context.Context) (*aws.Config, error) {
 	if err := c.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid AWS configuration: %w", err)
 	}
 
-	cfg, err := awscfg.LoadDefaultConfig(ctx,
-		awscfg.WithRegion(c.Region),
-	)
+	identity := DefaultIdentity()
+	if err := identity.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid AWS identity: %w", err)
+	}
+
+	loadOptions := []func(*awscfg.LoadOptions) error{awscfg.WithRegion(c.Region)}
+	if identity.Profile != "" {
+		loadOptions = append(loadOptions, awscfg.WithSharedConfigProfile(identity.Profile))
+	}
+
+	cfg, err := awscfg.LoadDefaultConfig(ctx, loadOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
 	}
 
+	// The role of the identity is assumed first, the role of the account is then assumed
+	// with its credentials
+	assumeRole(&cfg, identity.RoleARN, identity.ExternalID)
+	assumeRole(&cfg, c.RoleARN, c.ExternalID)
+
 	return &cfg, nil
 }
 
+// assumeRole replaces the credentials of cfg with those of the role, obtained through STS
+// with the current credentials of cfg. Nothing changes when roleARN is empty.
+func assumeRole(cfg *aws.Config, roleARN, externalID string) {
+	if roleARN == "" {
+		return
+	}
+
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(*cfg), roleARN,
+		func(o *stscreds.AssumeRoleOptions) {
+			if externalID != "" {
+				o.ExternalID = aws.String(externalID)
+			}
+		})
+	cfg.Credentials = aws.NewCredentialsCache(provider)
+}
+
+// Static credentials used against a custom endpoint when no credentials are configured,
+// accepted by LocalStack and moto
+const (
+	EndpointTestAccessKeyID     = "test"
+	EndpointTestSecretAccessKey = "test"
+)
+
+// UseEndpoint points the clients built from cfg at a custom endpoint, such as LocalStack.
+// When no credentials can be resolved, static test credentials are used instead, as the
+// emulators accept any.
+func UseEndpoint(ctx context.Context, cfg *aws.Config, endpointURL string) {
+	cfg.BaseEndpoint = aws.String(endpointURL)
+
+	if cfg.Credentials != nil {
+		if _, err := cfg.Credentials.Retrieve(ctx); err == nil {
+			return
+		}
+	}
+	cfg.Credentials = aws.NewCredentialsCache(
+		credentials.NewStaticCredentialsProvider(EndpointTestAccessKeyID, EndpointTestSecretAccessKey, ""))
+}
+
 // NewAWSClientConfig creates a new AWS client configuration
 func NewAWSClientConfig(region string) AWSClientConfig {
 	if region == "" {
@@ -61,3 +123,17 @@ func NewAWSClientConfig(region string) AWSClientConfig {
 		Region: region,
 	}
 }
+
+// NewAWSAssumeRoleClientConfig creates an AWS client configuration whose credentials
+// are obtained by assuming the given role
+func NewAWSAssumeRoleClientConfig(region, roleARN, externalID string) AWSClientConfig {
+	if region == "" {
+		region = constants.DefaultAWSRegion
+	}
+
+	return &AWSClientConfigOptions{
+		Region:     region,
+		RoleARN:    roleARN,
+		ExternalID: externalID,
+	}
+}
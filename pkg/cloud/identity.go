@@ -0,0 +1,49 @@
+package cloud
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Identity selects the AWS credentials of the clients: a profile of the shared configuration
+// files, and a role assumed on top of its credentials. The zero value uses the default
+// credential chain.
+type Identity struct {
+	// Profile is the shared configuration profile to load, AWS_PROFILE or default when empty
+	Profile string
+
+	// RoleARN, when set, is assumed through STS with the credentials of the profile
+	RoleARN string
+
+	// ExternalID is passed to AssumeRole when RoleARN is set
+	ExternalID string
+}
+
+// Validate checks that the identity can be used to load credentials
+func (i Identity) Validate() error {
+	if i.ExternalID != "" && i.RoleARN == "" {
+		return fmt.Errorf("an external ID requires a role ARN to assume")
+	}
+	return nil
+}
+
+var (
+	defaultIdentityMu sync.RWMutex
+	defaultIdentity   Identity
+)
+
+// SetDefaultIdentity sets the identity of every client configuration loaded afterwards, such
+// as the one chosen with the global flags of the CLI. Roles of the accounts of a multi-account
+// scan are assumed with the credentials of this identity.
+func SetDefaultIdentity(identity Identity) {
+	defaultIdentityMu.Lock()
+	defer defaultIdentityMu.Unlock()
+	defaultIdentity = identity
+}
+
+// DefaultIdentity returns the identity set with SetDefaultIdentity
+func DefaultIdentity() Identity {
+	defaultIdentityMu.RLock()
+	defer defaultIdentityMu.RUnlock()
+	return defaultIdentity
+}
@@ -0,0 +1,64 @@
+package cloud
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentity_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		identity    Identity
+		expectError bool
+	}{
+		{name: "Default credentials", identity: Identity{}},
+		{name: "Profile", identity: Identity{Profile: "audit"}},
+		{name: "Role with external ID", identity: Identity{RoleARN: "arn:aws:iam::111111111111:role/audit", ExternalID: "secret"}},
+		{name: "External ID without role", identity: Identity{ExternalID: "secret"}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.identity.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestLoadConfigWithDefaultIdentity changes the default identity and the environment, so it
+// cannot run in parallel with the other tests loading configurations
+func TestLoadConfigWithDefaultIdentity(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(configFile, []byte("[profile audit]\nregion = eu-west-1\n"), 0o600))
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	t.Cleanup(func() { SetDefaultIdentity(Identity{}) })
+
+	SetDefaultIdentity(Identity{Profile: "audit"})
+	assert.Equal(t, "audit", DefaultIdentity().Profile)
+	cfg, err := NewAWSClientConfig("us-east-1").LoadConfig(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1", cfg.Region, "the region of the client wins over the one of the profile")
+
+	SetDefaultIdentity(Identity{Profile: "missing"})
+	_, err = NewAWSClientConfig("us-east-1").LoadConfig(context.Background())
+	assert.Error(t, err)
+
+	SetDefaultIdentity(Identity{ExternalID: "secret"})
+	_, err = NewAWSClientConfig("us-east-1").LoadConfig(context.Background())
+	assert.ErrorContains(t, err, "invalid AWS identity")
+}
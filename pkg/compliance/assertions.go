@@ -0,0 +1,179 @@
+package compliance
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is a test case of a fixtures file: the tags of a resource and the compliance
+// outcome the configuration is expected to give them
+type Fixture struct {
+	// Name of the case, unique in the fixtures file
+	Name string `yaml:"name"`
+
+	// Resource type the tags belong to, whose tag criteria apply along with the global ones
+	ResourceType string `yaml:"resource_type"`
+
+	// Tags of the resource
+	Tags map[string]string `yaml:"tags"`
+
+	// Whether the tags are expected to be compliant
+	Compliant *bool `yaml:"compliant"`
+
+	// Violation types the tags are expected to break, exactly. Only the non-compliance is
+	// asserted when empty.
+	Violations []string `yaml:"violations,omitempty"`
+}
+
+// FixturesFile is the content of a fixtures file
+type FixturesFile struct {
+	Fixtures []Fixture `yaml:"fixtures"`
+}
+
+// AssertionResult is the outcome of a fixture evaluated against a configuration
+type AssertionResult struct {
+	Name         string `json:"name" yaml:"name"`
+	ResourceType string `json:"resource_type" yaml:"resource_type"`
+	Passed       bool   `json:"passed" yaml:"passed"`
+
+	// Compliance status and violation types the configuration gives the tags
+	Compliant  bool     `json:"compliant" yaml:"compliant"`
+	Violations []string `json:"violations,omitempty" yaml:"violations,omitempty"`
+
+	// Failures describe how the outcome differs from the expected one
+	Failures []string `json:"failures,omitempty" yaml:"failures,omitempty"`
+}
+
+// LoadFixtures reads and validates a fixtures file. Errors name the failing case, or its
+// position when the case has no name.
+func LoadFixtures(path string) ([]Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures file %s: %w", path, err)
+	}
+
+	// Cases are decoded one by one, so a malformed case can be named
+	var file struct {
+		Fixtures []yaml.Node `yaml:"fixtures"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures file %s: %w", path, err)
+	}
+	if len(file.Fixtures) == 0 {
+		return nil, fmt.Errorf("fixtures file %s holds no fixture", path)
+	}
+
+	fixtures := make([]Fixture, 0, len(file.Fixtures))
+	names := make(map[string]bool, len(file.Fixtures))
+	for i := range file.Fixtures {
+		node := &file.Fixtures[i]
+
+		var fixture Fixture
+		if err := node.Decode(&fixture); err != nil {
+			return nil, fmt.Errorf("invalid fixtures file %s: %s: %w", path, fixtureRef(node, i), err)
+		}
+		if err := validateFixture(&fixture); err != nil {
+			return nil, fmt.Errorf("invalid fixtures file %s: %s: %w", path, fixtureRef(node, i), err)
+		}
+		if names[fixture.Name] {
+			return nil, fmt.Errorf("invalid fixtures file %s: %s: duplicate fixture name", path, fixtureRef(node, i))
+		}
+		names[fixture.Name] = true
+
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, nil
+}
+
+// validateFixture checks the fields of a fixture, normalizing its resource type and the
+// former names of its violation types
+func validateFixture(fixture *Fixture) error {
+	if strings.TrimSpace(fixture.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if fixture.Compliant == nil {
+		return fmt.Errorf("compliant is required")
+	}
+
+	if fixture.ResourceType == "" {
+		return fmt.Errorf("resource_type is required")
+	}
+	if err := configuration.IsSupportedAWSResource(fixture.ResourceType); err != nil {
+		return err
+	}
+	fixture.ResourceType = configuration.NormalizeResourceType(fixture.ResourceType)
+
+	if *fixture.Compliant && len(fixture.Violations) > 0 {
+		return fmt.Errorf("a compliant fixture cannot expect violations")
+	}
+	for i, violationType := range fixture.Violations {
+		if renamed, exists := renamedViolationTypes[violationType]; exists {
+			violationType = string(renamed)
+			fixture.Violations[i] = violationType
+		}
+		if !knownViolationTypes[ViolationType(violationType)] {
+			return fmt.Errorf("unknown violation type %q, expected one of: %s",
+				violationType, strings.Join(sortedViolationTypes(), ", "))
+		}
+	}
+	return nil
+}
+
+// fixtureRef names a fixture in errors by its name when it has one, by its position and
+// line otherwise
+func fixtureRef(node *yaml.Node, index int) string {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "name" && node.Content[i+1].Value != "" {
+				return fmt.Sprintf("fixture %q", node.Content[i+1].Value)
+			}
+		}
+	}
+	return fmt.Sprintf("fixture %d (line %d)", index+1, node.Line)
+}
+
+// Assert evaluates a fixture through ValidateResource and compares the outcome with the
+// expected one. Suppressions set on the validator apply, the fixture name standing for the
+// resource ID.
+func (v *TagValidator) Assert(fixture Fixture) AssertionResult {
+	result := v.ValidateResource(ResourceRef{ID: fixture.Name, Type: fixture.ResourceType}, fixture.Tags)
+
+	assertion := AssertionResult{
+		Name:         fixture.Name,
+		ResourceType: fixture.ResourceType,
+		Compliant:    result.IsCompliant,
+	}
+	for _, violation := range result.Violations {
+		if !slices.Contains(assertion.Violations, string(violation.Type)) {
+			assertion.Violations = append(assertion.Violations, string(violation.Type))
+		}
+	}
+	slices.Sort(assertion.Violations)
+
+	expectCompliant := fixture.Compliant != nil && *fixture.Compliant
+	if result.IsCompliant != expectCompliant {
+		assertion.Failures = append(assertion.Failures,
+			fmt.Sprintf("expected compliant: %t, got compliant: %t", expectCompliant, result.IsCompliant))
+	}
+
+	if len(fixture.Violations) > 0 {
+		for _, violationType := range fixture.Violations {
+			if !slices.Contains(assertion.Violations, violationType) {
+				assertion.Failures = append(assertion.Failures, fmt.Sprintf("expected violation %s is not raised", violationType))
+			}
+		}
+		for _, violationType := range assertion.Violations {
+			if !slices.Contains(fixture.Violations, violationType) {
+				assertion.Failures = append(assertion.Failures, fmt.Sprintf("unexpected violation %s", violationType))
+			}
+		}
+	}
+
+	assertion.Passed = len(assertion.Failures) == 0
+	return assertion
+}
@@ -0,0 +1,219 @@
+package compliance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFixtures(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fixtures.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadFixtures(t *testing.T) {
+	path := writeFixtures(t, `
+fixtures:
+  - name: compliant bucket
+    resource_type: S3
+    tags:
+      environment: production
+      owner: team@company.com
+    compliant: true
+  - name: missing owner
+    resource_type: security-group
+    tags:
+      environment: production
+    compliant: false
+    violations: [missing_tags, excess_tags]
+`)
+
+	fixtures, err := LoadFixtures(path)
+	require.NoError(t, err)
+	require.Len(t, fixtures, 2)
+
+	assert.Equal(t, "s3", fixtures[0].ResourceType)
+	assert.True(t, *fixtures[0].Compliant)
+	assert.Equal(t, "securitygroup", fixtures[1].ResourceType)
+	assert.Equal(t, []string{"missing_tags", "too_many_tags"}, fixtures[1].Violations)
+}
+
+func TestLoadFixtures_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "No fixture",
+			content:  "fixtures: []\n",
+			expected: "holds no fixture",
+		},
+		{
+			name: "Malformed case named in the error",
+			content: `
+fixtures:
+  - name: broken tags
+    resource_type: s3
+    tags: [environment]
+    compliant: true
+`,
+			expected: `fixture "broken tags"`,
+		},
+		{
+			name: "Missing compliance expectation",
+			content: `
+fixtures:
+  - name: undecided
+    resource_type: s3
+`,
+			expected: `fixture "undecided": compliant is required`,
+		},
+		{
+			name: "Unknown violation type",
+			content: `
+fixtures:
+  - name: typo
+    resource_type: s3
+    compliant: false
+    violations: [missing_tag]
+`,
+			expected: `fixture "typo": unknown violation type "missing_tag"`,
+		},
+		{
+			name: "Unsupported resource type",
+			content: `
+fixtures:
+  - name: unknown resource
+    resource_type: mainframe
+    compliant: true
+`,
+			expected: `fixture "unknown resource": unsupported resource type: mainframe`,
+		},
+		{
+			name: "Compliant fixture expecting violations",
+			content: `
+fixtures:
+  - name: contradiction
+    resource_type: s3
+    compliant: true
+    violations: [missing_tags]
+`,
+			expected: `fixture "contradiction": a compliant fixture cannot expect violations`,
+		},
+		{
+			name: "Duplicate names",
+			content: `
+fixtures:
+  - name: twice
+    resource_type: s3
+    compliant: true
+  - name: twice
+    resource_type: s3
+    compliant: true
+`,
+			expected: `fixture "twice": duplicate fixture name`,
+		},
+		{
+			name: "Unnamed case referenced by position",
+			content: `
+fixtures:
+  - name: first
+    resource_type: s3
+    compliant: true
+  - resource_type: s3
+    compliant: true
+`,
+			expected: "fixture 2 (line 6): name is required",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadFixtures(writeFixtures(t, tc.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestTagValidator_Assert(t *testing.T) {
+	validator := NewTagValidator(createTestConfig())
+	compliant, nonCompliant := true, false
+
+	testCases := []struct {
+		name             string
+		fixture          Fixture
+		expectedPassed   bool
+		expectedFailures []string
+	}{
+		{
+			name: "Compliant tags expected compliant",
+			fixture: Fixture{
+				Name:      "compliant",
+				Tags:      map[string]string{"environment": "production", "owner": "team@company.com"},
+				Compliant: &compliant,
+			},
+			expectedPassed: true,
+		},
+		{
+			name: "Expected violations raised exactly",
+			fixture: Fixture{
+				Name:       "bad environment",
+				Tags:       map[string]string{"environment": "Prod", "owner": "team@company.com"},
+				Compliant:  &nonCompliant,
+				Violations: []string{"case_violation", "invalid_value"},
+			},
+			expectedPassed: true,
+		},
+		{
+			name: "Non-compliance asserted without violation types",
+			fixture: Fixture{
+				Name:      "no owner",
+				Tags:      map[string]string{"environment": "production"},
+				Compliant: &nonCompliant,
+			},
+			expectedPassed: true,
+		},
+		{
+			name: "Relaxed rule passing tags expected non-compliant",
+			fixture: Fixture{
+				Name:       "should fail",
+				Tags:       map[string]string{"environment": "production", "owner": "team@company.com"},
+				Compliant:  &nonCompliant,
+				Violations: []string{"pattern_violation"},
+			},
+			expectedFailures: []string{
+				"expected compliant: false, got compliant: true",
+				"expected violation pattern_violation is not raised",
+			},
+		},
+		{
+			name: "Unexpected violation raised",
+			fixture: Fixture{
+				Name:       "extra violation",
+				Tags:       map[string]string{"environment": "production", "owner": "team@company.com", "temp": "yes"},
+				Compliant:  &nonCompliant,
+				Violations: []string{"missing_tags"},
+			},
+			expectedFailures: []string{
+				"expected violation missing_tags is not raised",
+				"unexpected violation prohibited_tag",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := validator.Assert(tc.fixture)
+			assert.Equal(t, tc.fixture.Name, result.Name)
+			assert.Equal(t, tc.expectedPassed, result.Passed)
+			assert.Equal(t, tc.expectedFailures, result.Failures)
+		})
+	}
+}
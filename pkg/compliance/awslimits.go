@@ -0,0 +1,105 @@
+package compliance
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits AWS enforces on the tags of every resource, whatever the service
+const (
+	// AWSTagKeyMaxLength is the maximum number of characters of a tag key
+	AWSTagKeyMaxLength = 128
+
+	// AWSTagValueMaxLength is the maximum number of characters of a tag value
+	AWSTagValueMaxLength = 256
+
+	// AWSReservedTagPrefix starts the keys of the tags AWS creates, which cannot be written
+	AWSReservedTagPrefix = "aws:"
+)
+
+// awsTagSymbols are the characters besides letters, numbers and spaces AWS accepts in tags
+const awsTagSymbols = "_.:/=+-@"
+
+// awsTagLimitsExpectation describes the AWS tag limits in the violations of tags breaking them
+var awsTagLimitsExpectation = fmt.Sprintf("key of at most %d characters without the %s prefix, value of at most %d characters, only letters, numbers, spaces and %s",
+	AWSTagKeyMaxLength, AWSReservedTagPrefix, AWSTagValueMaxLength, awsTagSymbols)
+
+// CheckAWSTagLimits reports the tags AWS would reject when writing them: keys over 128
+// characters, values over 256, keys with the reserved aws: prefix and characters outside the
+// tag character set. Tags are reported in key order, one violation listing every limit the
+// tag breaks.
+func CheckAWSTagLimits(tags map[string]string) []Violation {
+	return awsTagLimitViolations(tags, false)
+}
+
+// awsTagLimitViolations checks tags against the AWS tag limits. Scanned resources skip the
+// tags with the reserved prefix, which AWS creates and which cannot be changed anyway.
+func awsTagLimitViolations(tags map[string]string, skipReserved bool) []Violation {
+	var violations []Violation
+	for _, key := range sortedKeys(tags) {
+		if skipReserved && hasAWSReservedPrefix(key) {
+			continue
+		}
+
+		value := tags[key]
+		problems := awsTagLimitProblems(key, value)
+		if len(problems) == 0 {
+			continue
+		}
+
+		violations = append(violations, Violation{
+			Type:     ViolationTypeAWSTagLimit,
+			Message:  fmt.Sprintf("Tag '%s' would be rejected by AWS: %s", key, strings.Join(problems, ", ")),
+			TagKey:   key,
+			Value:    value,
+			Expected: awsTagLimitsExpectation,
+			Severity: SeverityHigh,
+			DocURL:   violationDocURL(ViolationTypeAWSTagLimit),
+		})
+	}
+	return violations
+}
+
+// awsTagLimitProblems describes the AWS tag limits a tag breaks
+func awsTagLimitProblems(key, value string) []string {
+	var problems []string
+	if length := utf8.RuneCountInString(key); length > AWSTagKeyMaxLength {
+		problems = append(problems, fmt.Sprintf("key is %d characters long, the limit is %d", length, AWSTagKeyMaxLength))
+	}
+	if length := utf8.RuneCountInString(value); length > AWSTagValueMaxLength {
+		problems = append(problems, fmt.Sprintf("value is %d characters long, the limit is %d", length, AWSTagValueMaxLength))
+	}
+	if hasAWSReservedPrefix(key) {
+		problems = append(problems, fmt.Sprintf("key starts with the reserved %s prefix", AWSReservedTagPrefix))
+	}
+	if invalid := invalidAWSTagCharacters(key); invalid != "" {
+		problems = append(problems, fmt.Sprintf("key contains characters AWS does not allow: %q", invalid))
+	}
+	if invalid := invalidAWSTagCharacters(value); invalid != "" {
+		problems = append(problems, fmt.Sprintf("value contains characters AWS does not allow: %q", invalid))
+	}
+	return problems
+}
+
+// hasAWSReservedPrefix tells whether a tag key starts with the aws: prefix, in any case
+func hasAWSReservedPrefix(key string) bool {
+	return len(key) >= len(AWSReservedTagPrefix) && strings.EqualFold(key[:len(AWSReservedTagPrefix)], AWSReservedTagPrefix)
+}
+
+// invalidAWSTagCharacters returns the distinct characters of s that AWS does not accept in
+// tags, in order of appearance. Letters, numbers and spaces of any language are accepted,
+// along with _ . : / = + - @.
+func invalidAWSTagCharacters(s string) string {
+	var invalid []rune
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Z, r) || strings.ContainsRune(awsTagSymbols, r) {
+			continue
+		}
+		if !strings.ContainsRune(string(invalid), r) {
+			invalid = append(invalid, r)
+		}
+	}
+	return string(invalid)
+}
@@ -0,0 +1,70 @@
+package compliance
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+)
+
+// Colors of the compliance badge
+const (
+	BadgeColorGreen   = "green"
+	BadgeColorYellow  = "yellow"
+	BadgeColorRed     = "red"
+	BadgeColorUnknown = "lightgrey"
+)
+
+// BadgeLabel is the label of the compliance badge
+const BadgeLabel = "tag compliance"
+
+// Badge is a shields.io endpoint badge of the compliance of a run, see
+// https://shields.io/badges/endpoint-badge
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// NewBadge creates the badge of a compliance percentage computed over scored resources. A
+// run without resources of known compliance has an unknown grey badge rather than 100%.
+func NewBadge(percentage float64, scored int, thresholds configuration.BadgeThresholds) Badge {
+	badge := Badge{SchemaVersion: 1, Label: BadgeLabel, Message: "unknown", Color: BadgeColorUnknown}
+	if scored <= 0 {
+		return badge
+	}
+
+	// The percentage is rounded down, so a badge never shows a threshold its color misses
+	percentage = math.Floor(math.Max(0, math.Min(MaxComplianceScore, percentage)))
+	badge.Message = fmt.Sprintf("%.0f%%", percentage)
+	badge.Color = BadgeColor(percentage, thresholds)
+	return badge
+}
+
+// BadgeColor returns the color of the badge of a compliance percentage: green from the green
+// threshold, yellow from the yellow threshold and red below
+func BadgeColor(percentage float64, thresholds configuration.BadgeThresholds) string {
+	switch {
+	case percentage >= thresholds.Green:
+		return BadgeColorGreen
+	case percentage >= thresholds.Yellow:
+		return BadgeColorYellow
+	default:
+		return BadgeColorRed
+	}
+}
+
+// WriteBadge writes a badge to a JSON file served to the shields.io endpoint badge
+func WriteBadge(path string, badge Badge) error {
+	data, err := json.Marshal(badge)
+	if err != nil {
+		return fmt.Errorf("failed to encode compliance badge: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write compliance badge %s: %w", path, err)
+	}
+	return nil
+}
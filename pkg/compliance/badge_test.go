@@ -0,0 +1,85 @@
+package compliance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgeColor(t *testing.T) {
+	thresholds := configuration.BadgeThresholds{Green: 95, Yellow: 80}
+
+	testCases := []struct {
+		percentage float64
+		want       string
+	}{
+		{percentage: 100, want: BadgeColorGreen},
+		{percentage: 95, want: BadgeColorGreen},
+		{percentage: 94.9, want: BadgeColorYellow},
+		{percentage: 80, want: BadgeColorYellow},
+		{percentage: 79.9, want: BadgeColorRed},
+		{percentage: 0, want: BadgeColorRed},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, BadgeColor(tc.percentage, thresholds), "percentage %g", tc.percentage)
+	}
+}
+
+func TestNewBadge(t *testing.T) {
+	testCases := []struct {
+		name       string
+		percentage float64
+		scored     int
+		thresholds configuration.BadgeThresholds
+		want       Badge
+	}{
+		{
+			name:       "Yellow",
+			percentage: 87.4,
+			scored:     12,
+			thresholds: configuration.DefaultBadgeThresholds,
+			want:       Badge{SchemaVersion: 1, Label: BadgeLabel, Message: "87%", Color: BadgeColorYellow},
+		},
+		{
+			name:       "Rounded Down Below Green",
+			percentage: 94.6,
+			scored:     3,
+			thresholds: configuration.DefaultBadgeThresholds,
+			want:       Badge{SchemaVersion: 1, Label: BadgeLabel, Message: "94%", Color: BadgeColorYellow},
+		},
+		{
+			name:       "Custom Thresholds",
+			percentage: 75,
+			scored:     4,
+			thresholds: configuration.BadgeThresholds{Green: 70, Yellow: 50},
+			want:       Badge{SchemaVersion: 1, Label: BadgeLabel, Message: "75%", Color: BadgeColorGreen},
+		},
+		{
+			name:       "No Resources",
+			percentage: MaxComplianceScore,
+			scored:     0,
+			thresholds: configuration.DefaultBadgeThresholds,
+			want:       Badge{SchemaVersion: 1, Label: BadgeLabel, Message: "unknown", Color: BadgeColorUnknown},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, NewBadge(tc.percentage, tc.scored, tc.thresholds))
+		})
+	}
+}
+
+func TestWriteBadge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "badge.json")
+	require.NoError(t, WriteBadge(path, NewBadge(87, 10, configuration.DefaultBadgeThresholds)))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"schemaVersion":1,"label":"tag compliance","message":"87%","color":"yellow"}`+"\n", string(data))
+}
@@ -0,0 +1,79 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ResourceTags are the tags of a resource validated by ValidateResourcesBatch
+type ResourceTags struct {
+	Resource ResourceRef
+	Tags     map[string]string
+
+	// UnreadableReason tells why the tags could not be read, the resource is then validated
+	// as by ValidateUnreadable
+	UnreadableReason string
+}
+
+// SetBatchWorkers sets the number of goroutines validating the resources of
+// ValidateResourcesBatch, GOMAXPROCS when zero or negative
+func (v *TagValidator) SetBatchWorkers(workers int) {
+	v.batchWorkers = workers
+}
+
+// ValidateResourcesBatch validates the tags of many resources across a pool of workers,
+// returning the result of each resource at its index in the input. Every worker shares the
+// patterns compiled once by NewTagValidator. The validation stops with the error of the
+// context when it is cancelled, returning no results.
+func (v *TagValidator) ValidateResourcesBatch(ctx context.Context, resources []ResourceTags) ([]*ComplianceResult, error) {
+	results := make([]*ComplianceResult, len(resources))
+
+	workers := v.batchWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(resources))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = v.validateResourceTags(resources[i])
+			}
+		}()
+	}
+
+	var err error
+dispatch:
+	for i := range resources {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err != nil {
+		return nil, fmt.Errorf("tag validation cancelled: %w", err)
+	}
+	return results, nil
+}
+
+// validateResourceTags validates a resource of a batch
+func (v *TagValidator) validateResourceTags(resource ResourceTags) *ComplianceResult {
+	if resource.UnreadableReason != "" {
+		return v.ValidateUnreadable(resource.Resource, resource.UnreadableReason)
+	}
+	return v.ValidateResource(resource.Resource, resource.Tags)
+}
@@ -0,0 +1,161 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBatchTestConfig() *configuration.TaggyScanConfig {
+	return &configuration.TaggyScanConfig{
+		Global: configuration.GlobalConfig{
+			Enabled: true,
+			TagCriteria: configuration.TagCriteria{
+				RequiredTags: []string{"Environment", "Owner"},
+			},
+		},
+		TagValidation: configuration.TagValidation{
+			PatternRules: map[string]string{
+				"Owner": `^[a-z]+@example\.com$`,
+			},
+		},
+	}
+}
+
+// newBatchTestResources returns n resources, every third one missing its Owner tag and
+// every fifth one with unreadable tags
+func newBatchTestResources(n int) []ResourceTags {
+	resources := make([]ResourceTags, n)
+	for i := range resources {
+		resources[i] = ResourceTags{
+			Resource: ResourceRef{ID: fmt.Sprintf("bucket-%05d", i), Type: "s3"},
+			Tags:     map[string]string{"Environment": "prod", "Owner": "team@example.com"},
+		}
+		if i%3 == 0 {
+			delete(resources[i].Tags, "Owner")
+		}
+		if i%5 == 0 {
+			resources[i].UnreadableReason = "access denied"
+		}
+	}
+	return resources
+}
+
+func TestValidateResourcesBatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		workers   int
+		resources int
+	}{
+		{name: "Default Workers", resources: 100},
+		{name: "Single Worker", workers: 1, resources: 50},
+		{name: "More Workers Than Resources", workers: 16, resources: 3},
+		{name: "No Resources", workers: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			validator := NewTagValidator(newBatchTestConfig())
+			validator.SetBatchWorkers(tt.workers)
+			resources := newBatchTestResources(tt.resources)
+
+			results, err := validator.ValidateResourcesBatch(context.Background(), resources)
+			require.NoError(t, err)
+			require.Len(t, results, len(resources))
+
+			// Results are in input order, and the same as validating each resource on its own
+			for i, resource := range resources {
+				expected := validator.validateResourceTags(resource)
+				assert.Equal(t, expected, results[i], resource.Resource.ID)
+			}
+		})
+	}
+}
+
+func TestValidateResourcesBatchCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := NewTagValidator(newBatchTestConfig()).ValidateResourcesBatch(ctx, newBatchTestResources(10))
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, results)
+}
+
+// newBenchmarkConfig returns a configuration of 20 rules, most of them regular expressions
+func newBenchmarkConfig() *configuration.TaggyScanConfig {
+	config := &configuration.TaggyScanConfig{
+		Global: configuration.GlobalConfig{
+			Enabled: true,
+			TagCriteria: configuration.TagCriteria{
+				RequiredTags:  []string{"environment", "owner", "project"},
+				ForbiddenTags: []string{"temporary"},
+			},
+		},
+		TagValidation: configuration.TagValidation{
+			ProhibitedTags: []string{"aws:", "internal:"},
+			KeyFormatRules: []configuration.KeyFormatRule{
+				{Pattern: `^[a-z][a-z0-9_-]*$`, Message: "Tag keys must be lowercase"},
+				{Pattern: `^.{1,64}$`, Message: "Tag keys must not exceed 64 characters"},
+			},
+			PatternRules: map[string]string{},
+			AllowedValues: map[string][]string{
+				"environment": {"production", "staging", "development"},
+			},
+		},
+	}
+	for i := range 14 {
+		config.TagValidation.PatternRules[fmt.Sprintf("key-%02d", i)] = `^[a-z]+-[0-9]{2,4}(-[a-z]+)?$`
+	}
+	return config
+}
+
+// newBenchmarkResources returns n resources of 20 tags, the keys of the pattern rules included
+func newBenchmarkResources(n int) []ResourceTags {
+	resources := make([]ResourceTags, n)
+	for i := range resources {
+		tags := map[string]string{
+			"environment": "production",
+			"owner":       "platform",
+			"project":     "taggy",
+		}
+		for j := 0; len(tags) < 20; j++ {
+			tags[fmt.Sprintf("key-%02d", j)] = fmt.Sprintf("value-%d", i%1000)
+		}
+		resources[i] = ResourceTags{
+			Resource: ResourceRef{ID: fmt.Sprintf("resource-%05d", i), Type: "s3"},
+			Tags:     tags,
+		}
+	}
+	return resources
+}
+
+func BenchmarkValidateResources(b *testing.B) {
+	validator := NewTagValidator(newBenchmarkConfig())
+	resources := newBenchmarkResources(10000)
+
+	b.Run("Serial", func(b *testing.B) {
+		for range b.N {
+			for _, resource := range resources {
+				validator.validateResourceTags(resource)
+			}
+		}
+	})
+
+	b.Run("Batch", func(b *testing.B) {
+		for range b.N {
+			if _, err := validator.ValidateResourcesBatch(context.Background(), resources); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package compliance
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+)
+
+// ScopedResource is a resource checked by the cross-resource rules, along with the
+// dimensions the scope of a rule is told from
+type ScopedResource struct {
+	Resource ResourceRef
+	Tags     map[string]string
+
+	AccountID string
+	Region    string
+	VPC       string
+}
+
+// identifier returns the ARN of the resource, or its ID when it has none
+func (r ScopedResource) identifier() string {
+	if r.Resource.ARN != "" {
+		return r.Resource.ARN
+	}
+	return r.Resource.ID
+}
+
+// scopeOf returns the values of the dimensions of a scope for the resource, false when the
+// resource lacks any of them, such as the VPC of a resource outside any VPC
+func (r ScopedResource) scopeOf(scope []string) ([]string, bool) {
+	values := make([]string, len(scope))
+	for i, dimension := range scope {
+		switch dimension {
+		case configuration.ScopeAccount:
+			values[i] = r.AccountID
+		case configuration.ScopeRegion:
+			values[i] = r.Region
+		case configuration.ScopeType:
+			values[i] = r.Resource.Type
+		case configuration.ScopeVPC:
+			values[i] = r.VPC
+		default:
+			if key, ok := strings.CutPrefix(dimension, configuration.ScopeTagPrefix); ok {
+				_, values[i], _ = lookupTagIgnoringCase(r.Tags, key)
+			}
+		}
+		if values[i] == "" {
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+// CheckCrossResourceRules checks the cross-resource rules against every resource of a scan
+// at once. It returns the violations of the resources breaking a rule, keyed by their index
+// in resources.
+//
+// A unique_value rule groups the resources carrying its tag by the values of the dimensions
+// of its scope, and reports a duplicate_tag_value violation on every resource sharing its
+// value with others of its group, listing them. Resources without the tag, or lacking a
+// dimension of the scope, are left out of the rule.
+func CheckCrossResourceRules(rules []configuration.CrossResourceRule, resources []ScopedResource) map[int][]Violation {
+	violations := make(map[int][]Violation)
+	for _, rule := range rules {
+		if rule.UniqueValue == nil {
+			continue
+		}
+		for i, violation := range uniqueValueViolations(rule, resources) {
+			violations[i] = append(violations[i], violation)
+		}
+	}
+	return violations
+}
+
+// uniqueValueViolations returns the violations of a unique_value rule, keyed by the index of
+// the resource in resources
+func uniqueValueViolations(rule configuration.CrossResourceRule, resources []ScopedResource) map[int]Violation {
+	unique := rule.UniqueValue
+
+	// Members of a group share the values of the scope and the tag value, in scan order
+	groups := make(map[string][]int)
+	var order []string
+	for i, resource := range resources {
+		_, value, found := lookupTagIgnoringCase(resource.Tags, unique.Tag)
+		if !found || value == "" {
+			continue
+		}
+		scope, ok := resource.scopeOf(unique.Scope)
+		if !ok {
+			continue
+		}
+
+		group := strings.Join(append(scope, value), "\x00")
+		if _, exists := groups[group]; !exists {
+			order = append(order, group)
+		}
+		groups[group] = append(groups[group], i)
+	}
+
+	within := "across every resource"
+	if len(unique.Scope) > 0 {
+		within = "within the same " + strings.Join(unique.Scope, " and ")
+	}
+
+	violations := make(map[int]Violation)
+	for _, group := range order {
+		members := groups[group]
+		if len(members) < 2 {
+			continue
+		}
+
+		for _, i := range members {
+			related := make([]string, 0, len(members)-1)
+			for _, j := range members {
+				if j != i {
+					related = append(related, resources[j].identifier())
+				}
+			}
+
+			key, value, _ := lookupTagIgnoringCase(resources[i].Tags, unique.Tag)
+			violations[i] = Violation{
+				Type: ViolationTypeDuplicateTagValue,
+				Message: fmt.Sprintf("Tag value for '%s' must be unique %s, it is shared with %d other resource(s): %s",
+					key, within, len(related), strings.Join(related, ", ")),
+				TagKey:           key,
+				Value:            value,
+				Expected:         "unique " + within,
+				DocURL:           violationDocURL(ViolationTypeDuplicateTagValue),
+				Severity:         severityOf(rule.Severity),
+				RelatedResources: related,
+			}
+		}
+	}
+	return violations
+}
+
+// lookupTagIgnoringCase returns the key, as spelled on the resource, and the value of the tag
+// whose key matches key ignoring case
+func lookupTagIgnoringCase(tags map[string]string, key string) (string, string, bool) {
+	if value, exists := tags[key]; exists {
+		return key, value, true
+	}
+	for tagKey, value := range tags {
+		if strings.EqualFold(tagKey, key) {
+			return tagKey, value, true
+		}
+	}
+	return "", "", false
+}
+
+// AddCrossResourceViolations adds the violations of the cross-resource rules found by
+// CheckCrossResourceRules to the result of a resource. Violations accepted by a suppression
+// of the resource are moved to SuppressedViolations, the others make the resource
+// non-compliant and take their severity off its score.
+func (v *TagValidator) AddCrossResourceViolations(resource ResourceRef, result *ComplianceResult, violations []Violation) {
+	if v.suppressions != nil {
+		var suppressed []Violation
+		violations, suppressed = v.suppressions.Apply(resource, violations, v.now())
+		result.SuppressedViolations = append(result.SuppressedViolations, suppressed...)
+	}
+	if len(violations) == 0 {
+		return
+	}
+
+	penalty := 0.0
+	for _, violation := range violations {
+		penalty += violation.Severity.Penalty()
+	}
+
+	result.Violations = append(result.Violations, violations...)
+	result.IsCompliant = false
+	result.Score = math.Max(0, result.Score-penalty)
+}
@@ -0,0 +1,169 @@
+package compliance
+
+import (
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newScopedInstance(id, vpc string, tags map[string]string) ScopedResource {
+	return ScopedResource{
+		Resource:  ResourceRef{ID: id, ARN: "arn:aws:ec2:us-east-1:123456789012:instance/" + id, Type: "ec2"},
+		Tags:      tags,
+		AccountID: "123456789012",
+		Region:    "us-east-1",
+		VPC:       vpc,
+	}
+}
+
+func TestCheckCrossResourceRules(t *testing.T) {
+	t.Parallel()
+
+	rules := []configuration.CrossResourceRule{{
+		UniqueValue: &configuration.UniqueValueRule{Tag: "Name", Scope: []string{"type", "vpc"}},
+		Severity:    configuration.SeverityHigh,
+	}}
+
+	resources := []ScopedResource{
+		newScopedInstance("i-1", "vpc-a", map[string]string{"Name": "web"}),
+		newScopedInstance("i-2", "vpc-a", map[string]string{"name": "web"}),
+		newScopedInstance("i-3", "vpc-b", map[string]string{"Name": "web"}),
+		newScopedInstance("i-4", "vpc-a", map[string]string{"Name": "api"}),
+		newScopedInstance("i-5", "vpc-a", map[string]string{"Name": "web"}),
+		newScopedInstance("i-6", "", map[string]string{"Name": "web"}),
+		newScopedInstance("i-7", "", map[string]string{"Name": "web"}),
+		newScopedInstance("i-8", "vpc-a", map[string]string{"Name": ""}),
+		newScopedInstance("i-9", "vpc-a", map[string]string{"Name": ""}),
+	}
+
+	violations := CheckCrossResourceRules(rules, resources)
+	require.Len(t, violations, 3, "only the web instances of vpc-a share their name within their scope")
+
+	assert.Equal(t, []Violation{{
+		Type:     ViolationTypeDuplicateTagValue,
+		Message:  "Tag value for 'name' must be unique within the same type and vpc, it is shared with 2 other resource(s): arn:aws:ec2:us-east-1:123456789012:instance/i-1, arn:aws:ec2:us-east-1:123456789012:instance/i-5",
+		TagKey:   "name",
+		Value:    "web",
+		Expected: "unique within the same type and vpc",
+		DocURL:   violationDocURL(ViolationTypeDuplicateTagValue),
+		Severity: SeverityHigh,
+		RelatedResources: []string{
+			"arn:aws:ec2:us-east-1:123456789012:instance/i-1",
+			"arn:aws:ec2:us-east-1:123456789012:instance/i-5",
+		},
+	}}, violations[1])
+	assert.Equal(t, []string{resources[1].Resource.ARN, resources[4].Resource.ARN}, violations[0][0].RelatedResources)
+	assert.Equal(t, []string{resources[0].Resource.ARN, resources[1].Resource.ARN}, violations[4][0].RelatedResources)
+}
+
+func TestCheckCrossResourceRules_Scopes(t *testing.T) {
+	t.Parallel()
+
+	bucket := ScopedResource{
+		Resource:  ResourceRef{ID: "web", Type: "s3"},
+		Tags:      map[string]string{"Name": "web", "Team": "platform"},
+		AccountID: "123456789012",
+		Region:    "us-east-1",
+	}
+	instance := newScopedInstance("i-1", "vpc-a", map[string]string{"Name": "web", "Team": "platform"})
+	otherTeam := newScopedInstance("i-2", "vpc-a", map[string]string{"Name": "web", "Team": "data"})
+
+	tests := []struct {
+		name     string
+		scope    []string
+		expected map[int][]string
+	}{
+		{
+			name:  "Every Resource",
+			scope: nil,
+			expected: map[int][]string{
+				0: {instance.Resource.ARN, otherTeam.Resource.ARN},
+				1: {"web", otherTeam.Resource.ARN},
+				2: {"web", instance.Resource.ARN},
+			},
+		},
+		{
+			name:  "Account And Region",
+			scope: []string{"account", "region"},
+			expected: map[int][]string{
+				0: {instance.Resource.ARN, otherTeam.Resource.ARN},
+				1: {"web", otherTeam.Resource.ARN},
+				2: {"web", instance.Resource.ARN},
+			},
+		},
+		{
+			name:  "Type",
+			scope: []string{"type"},
+			expected: map[int][]string{
+				1: {otherTeam.Resource.ARN},
+				2: {instance.Resource.ARN},
+			},
+		},
+		{
+			name:  "Tag",
+			scope: []string{"tag:team"},
+			expected: map[int][]string{
+				0: {instance.Resource.ARN},
+				1: {"web"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := []configuration.CrossResourceRule{{UniqueValue: &configuration.UniqueValueRule{Tag: "Name", Scope: tt.scope}}}
+			violations := CheckCrossResourceRules(rules, []ScopedResource{bucket, instance, otherTeam})
+
+			related := make(map[int][]string, len(violations))
+			for i, resourceViolations := range violations {
+				require.Len(t, resourceViolations, 1)
+				assert.Equal(t, SeverityMedium, resourceViolations[0].Severity)
+				related[i] = resourceViolations[0].RelatedResources
+			}
+			assert.Equal(t, tt.expected, related)
+		})
+	}
+}
+
+func TestAddCrossResourceViolations(t *testing.T) {
+	t.Parallel()
+
+	config := &configuration.TaggyScanConfig{
+		Global: configuration.GlobalConfig{
+			Enabled:     true,
+			TagCriteria: configuration.TagCriteria{RequiredTags: []string{"Name"}},
+		},
+	}
+
+	suppressions, err := NewSuppressions([]Suppression{
+		{Resource: `legacy-.*`, ViolationType: "duplicate_tag_value", Reason: "Legacy fleet"},
+	})
+	require.NoError(t, err)
+
+	validator := NewTagValidator(config)
+	validator.SetSuppressions(suppressions)
+
+	duplicate := []Violation{{Type: ViolationTypeDuplicateTagValue, TagKey: "Name", Value: "web", Severity: SeverityHigh}}
+
+	resource := ResourceRef{ID: "i-1"}
+	result := validator.ValidateResource(resource, map[string]string{"Name": "web"})
+	require.True(t, result.IsCompliant)
+
+	validator.AddCrossResourceViolations(resource, result, duplicate)
+	assert.False(t, result.IsCompliant)
+	assert.Equal(t, duplicate, result.Violations)
+	assert.Equal(t, MaxComplianceScore-SeverityHigh.Penalty(), result.Score)
+
+	legacy := ResourceRef{ID: "legacy-1"}
+	suppressed := validator.ValidateResource(legacy, map[string]string{"Name": "web"})
+	validator.AddCrossResourceViolations(legacy, suppressed, duplicate)
+	assert.True(t, suppressed.IsCompliant)
+	assert.Empty(t, suppressed.Violations)
+	require.Len(t, suppressed.SuppressedViolations, 1)
+	assert.Equal(t, "suppressed: Legacy fleet", suppressed.SuppressedViolations[0].Note)
+	assert.Equal(t, MaxComplianceScore, suppressed.Score)
+}
@@ -0,0 +1,133 @@
+package compliance
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// TagFix is the tag change mechanically fixing violations of a resource: the tags to set and
+// the tag keys to remove
+type TagFix struct {
+	Set   map[string]string `json:"set,omitempty" yaml:"set,omitempty"`
+	Unset []string          `json:"unset,omitempty" yaml:"unset,omitempty"`
+}
+
+// FixPlanEntry is the tag change of a resource in a fix plan file, which is reviewed and
+// applied outside aws-taggy
+type FixPlanEntry struct {
+	ARN     string            `json:"arn"`
+	Service string            `json:"service"`
+	Set     map[string]string `json:"set,omitempty"`
+	Unset   []string          `json:"unset,omitempty"`
+}
+
+// planFix computes the tag change fixing the violations that need no decision: values whose
+// case breaks a case rule, keys whose case breaks a case rule and alias keys breaking the key
+// rules, renamed to their canonical key. The violations it fixes are marked auto-fixable, the
+// change is nil when none is.
+func (v *TagValidator) planFix(tags map[string]string, violations []Violation) *TagFix {
+	fixed := maps.Clone(tags)
+	renamed := make(map[string]string)
+
+	// currentKey follows the renames already planned, so a value fix lands on the new key
+	currentKey := func(key string) string {
+		if newKey, exists := renamed[key]; exists {
+			return newKey
+		}
+		return key
+	}
+
+	rename := func(key, newKey string) bool {
+		if _, taken := tags[newKey]; taken || key == newKey {
+			return false
+		}
+		if _, exists := fixed[key]; !exists {
+			return false
+		}
+		fixed[newKey] = fixed[key]
+		delete(fixed, key)
+		renamed[key] = newKey
+		return true
+	}
+
+	for i, violation := range violations {
+		if _, exists := tags[violation.TagKey]; !exists {
+			continue
+		}
+
+		switch violation.Type {
+		case ViolationTypeCaseViolation:
+			if violation.SuggestedValue == "" {
+				continue
+			}
+			if violation.Value == "" {
+				violations[i].AutoFixable = rename(violation.TagKey, violation.SuggestedValue)
+				continue
+			}
+			fixed[currentKey(violation.TagKey)] = violation.SuggestedValue
+			violations[i].AutoFixable = true
+		case ViolationTypeInvalidKeyPrefix, ViolationTypeInvalidKeySuffix:
+			canonical, isAlias := v.aliasTarget(violation.TagKey)
+			if !isAlias || len(v.config.TagValidation.KeyValidation.ValidateTagKey(canonical)) > 0 {
+				continue
+			}
+			if newKey, exists := renamed[violation.TagKey]; exists {
+				violations[i].AutoFixable = newKey == canonical
+				continue
+			}
+			violations[i].AutoFixable = rename(violation.TagKey, canonical)
+		}
+	}
+
+	fix := &TagFix{Set: make(map[string]string)}
+	for key, value := range fixed {
+		if previous, exists := tags[key]; !exists || previous != value {
+			fix.Set[key] = value
+		}
+	}
+	for key := range tags {
+		if _, exists := fixed[key]; !exists {
+			fix.Unset = append(fix.Unset, key)
+		}
+	}
+	slices.Sort(fix.Unset)
+
+	if len(fix.Set) == 0 && len(fix.Unset) == 0 {
+		return nil
+	}
+	return fix
+}
+
+// aliasTarget returns the canonical key a tag key is an alias of under the configured tag
+// normalization
+func (v *TagValidator) aliasTarget(key string) (string, bool) {
+	normalization := v.config.TagValidation.TagNormalization
+	for alias, canonical := range normalization.Aliases {
+		if alias == key || (normalization.LowercaseKeys && strings.EqualFold(alias, key)) {
+			return canonical, true
+		}
+	}
+	return "", false
+}
+
+// WriteFixPlan writes the tag changes of a fix plan to a JSON file, ordered by ARN
+func WriteFixPlan(path string, entries []FixPlanEntry) error {
+	sorted := slices.Clone(entries)
+	if sorted == nil {
+		sorted = []FixPlanEntry{}
+	}
+	slices.SortFunc(sorted, func(a, b FixPlanEntry) int { return strings.Compare(a.ARN, b.ARN) })
+
+	data, err := json.MarshalIndent(sorted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fix plan: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write fix plan %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package compliance
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTags_Fix(t *testing.T) {
+	testCases := []struct {
+		name        string
+		tags        map[string]string
+		expectedFix *TagFix
+		fixable     []ViolationType
+	}{
+		{
+			name:        "Value breaking a case rule",
+			tags:        map[string]string{"environment": "Production", "owner": "john@company.com"},
+			expectedFix: &TagFix{Set: map[string]string{"environment": "production"}},
+			fixable:     []ViolationType{ViolationTypeCaseViolation},
+		},
+		{
+			name:        "Key breaking a case rule",
+			tags:        map[string]string{"Environment": "production", "owner": "john@company.com"},
+			expectedFix: &TagFix{Set: map[string]string{"environment": "production"}, Unset: []string{"Environment"}},
+			fixable:     []ViolationType{ViolationTypeCaseViolation},
+		},
+		{
+			name:        "Key and value breaking a case rule",
+			tags:        map[string]string{"Environment": "Staging", "owner": "john@company.com"},
+			expectedFix: &TagFix{Set: map[string]string{"environment": "staging"}, Unset: []string{"Environment"}},
+			fixable:     []ViolationType{ViolationTypeCaseViolation, ViolationTypeCaseViolation},
+		},
+		{
+			name: "Value that is not an allowed value",
+			tags: map[string]string{"environment": "prod", "owner": "john@company.com"},
+		},
+		{
+			name: "Key already spelled as the case rule requires",
+			tags: map[string]string{"Environment": "production", "environment": "production", "owner": "john@company.com"},
+		},
+	}
+
+	validator := NewTagValidator(createTestConfig())
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := validator.ValidateTags(tc.tags)
+			require.False(t, result.IsCompliant)
+			assert.Equal(t, tc.expectedFix, result.Fix)
+
+			var fixable []ViolationType
+			for _, violation := range result.Violations {
+				if violation.AutoFixable {
+					fixable = append(fixable, violation.Type)
+				}
+			}
+			assert.Equal(t, tc.fixable, fixable)
+		})
+	}
+}
+
+func TestValidateTags_FixRenamesAliasKeys(t *testing.T) {
+	config := &configuration.TaggyScanConfig{
+		TagValidation: configuration.TagValidation{
+			KeyValidation: configuration.KeyValidation{AllowedPrefixes: []string{"team-"}},
+			TagNormalization: configuration.TagNormalization{
+				Aliases: map[string]string{"owner": "team-owner"},
+			},
+		},
+	}
+	validator := NewTagValidator(config)
+
+	result := validator.ValidateTags(map[string]string{"owner": "payments", "cost": "42"})
+
+	require.Len(t, result.Violations, 2)
+	assert.Equal(t, &TagFix{Set: map[string]string{"team-owner": "payments"}, Unset: []string{"owner"}}, result.Fix)
+	for _, violation := range result.Violations {
+		assert.Equal(t, ViolationTypeInvalidKeyPrefix, violation.Type)
+		assert.Equal(t, violation.TagKey == "owner", violation.AutoFixable, violation.TagKey)
+	}
+}
+
+func TestWriteFixPlan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixes.json")
+	entries := []FixPlanEntry{
+		{ARN: "arn:aws:s3:::logs", Service: "s3", Set: map[string]string{"environment": "production"}},
+		{ARN: "arn:aws:ec2:us-east-1:111111111111:instance/i-1", Service: "ec2", Set: map[string]string{"environment": "staging"}, Unset: []string{"Environment"}},
+	}
+
+	require.NoError(t, WriteFixPlan(path, entries))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var written []FixPlanEntry
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, []FixPlanEntry{entries[1], entries[0]}, written)
+
+	// An empty plan is written as an empty list
+	require.NoError(t, WriteFixPlan(path, nil))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", string(data))
+}
@@ -0,0 +1,76 @@
+package compliance
+
+import "strings"
+
+// ruleDocsURL is the user guide describing the tag rules, violations link to its sections
+const ruleDocsURL = "https://github.com/Excoriate/aws-taggy/blob/main/docs/user-guide/how-to-tag-compliance.md"
+
+// violationDocAnchors maps violation types to the user guide section explaining their rule
+var violationDocAnchors = map[ViolationType]string{
+	ViolationTypeMissingTags:      "required-tags",
+	ViolationTypeInvalidValue:     "allowed-tag-values",
+	ViolationTypeDeprecatedValue:  "allowed-tag-values",
+	ViolationTypeCaseViolation:    "case-sensitivity",
+	ViolationTypePatternViolation: "pattern-rules",
+	ViolationTypeInvalidKeyFormat: "tag-key-restrictions",
+	ViolationTypeInvalidKeyPrefix: "tag-key-restrictions",
+	ViolationTypeInvalidKeySuffix: "tag-key-restrictions",
+	ViolationTypeKeyTooLong:       "tag-key-restrictions",
+	ViolationTypeTagsUnreadable:   "resources-with-unreadable-tags",
+
+	ViolationTypeDuplicateKeyDifferentCase: "duplicate-keys-differing-in-case",
+	ViolationTypeAWSTagLimit:               "aws-tag-limits",
+	ViolationTypeDuplicateTagValue:         "unique-tag-values",
+}
+
+// violationDocURL returns the documentation of the rule a violation type breaks, empty when
+// the rule has no dedicated section
+func violationDocURL(violationType ViolationType) string {
+	anchor, exists := violationDocAnchors[violationType]
+	if !exists {
+		return ""
+	}
+	return ruleDocsURL + "#" + anchor
+}
+
+// closestAllowedValue returns the allowed value nearest to value: a case-insensitive match
+// first, otherwise the one with the smallest edit distance. Ties go to the first listed value.
+func closestAllowedValue(value string, allowed []string) string {
+	closest, closestDistance := "", -1
+	for _, candidate := range allowed {
+		if strings.EqualFold(candidate, value) {
+			return candidate
+		}
+		distance := levenshtein(strings.ToLower(value), strings.ToLower(candidate))
+		if closestDistance < 0 || distance < closestDistance {
+			closest, closestDistance = candidate, distance
+		}
+	}
+	return closest
+}
+
+// levenshtein returns the number of single rune insertions, deletions and substitutions
+// turning a into b
+func levenshtein(a, b string) int {
+	source, target := []rune(a), []rune(b)
+
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
@@ -0,0 +1,48 @@
+package compliance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClosestAllowedValue(t *testing.T) {
+	t.Parallel()
+
+	allowed := []string{"production", "staging", "development"}
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "Case Insensitive Match", value: "STAGING", expected: "staging"},
+		{name: "Typo", value: "prodution", expected: "production"},
+		{name: "Nearest By Edit Distance", value: "developer", expected: "development"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, closestAllowedValue(tt.value, allowed))
+		})
+	}
+
+	assert.Empty(t, closestAllowedValue("prod", nil))
+}
+
+func TestLevenshtein(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, levenshtein("prod", "prod"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 4, levenshtein("", "prod"))
+	assert.Equal(t, 1, levenshtein("café", "cafe"))
+}
+
+func TestViolationDocURL(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, ruleDocsURL+"#allowed-tag-values", violationDocURL(ViolationTypeInvalidValue))
+	assert.Empty(t, violationDocURL(ViolationTypeTooManyTags))
+}
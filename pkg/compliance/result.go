@@ -2,6 +2,7 @@
 
 import (
 	"fmt"
+	"math"
 	"strings"
 )
 
@@ -16,8 +17,34 @@ type Violation struct {
 	// Tag key associated with the violation (if applicable)
 	TagKey string
 
+	// Tag value that failed the rule (if applicable)
+	Value string
+
+	// Expectation the tag fails, such as the required pattern, the allowed values or the
+	// limit it exceeds (if applicable)
+	Expected string
+
 	// Suggested fix or correction (optional)
 	SuggestedFix string
+
+	// Value that would satisfy the rule, such as the closest allowed value (optional)
+	SuggestedValue string
+
+	// Documentation of the rule the violation breaks (optional)
+	DocURL string
+
+	// Other resources involved in the violation, such as the resources sharing a tag value
+	// that must be unique (optional)
+	RelatedResources []string
+
+	// Severity of the violation
+	Severity Severity
+
+	// Note on the violation, such as the suppression accepting it or the one that expired
+	Note string
+
+	// AutoFixable is set when the tag change of the result fixes the violation
+	AutoFixable bool
 }
 
 // ComplianceResult represents the result of tag compliance validation
@@ -36,6 +63,27 @@ type ComplianceResult struct {
 
 	// Resource type (e.g., s3, ec2)
 	ResourceType string
+
+	// Weighted compliance score, from 0 to 100, lowered by each violation according to its severity
+	Score float64
+
+	// Violations accepted by a suppression, left out of the compliance status and score
+	SuppressedViolations []Violation
+
+	// Warnings are findings that do not break a rule, such as a deprecated allowed value,
+	// left out of the compliance status and score
+	Warnings []Violation
+
+	// IsUnknown is set when the tags of the resource could not be read, its compliance being
+	// then neither confirmed nor refuted. Unknown results are not compliant.
+	IsUnknown bool
+
+	// Fix is the tag change fixing the auto-fixable violations, nil when none is
+	Fix *TagFix
+
+	// SkippedRules lists the built-in rule categories disabled for the resource type, which
+	// were not evaluated
+	SkippedRules []string
 }
 
 // Summary provides a high-level overview of compliance results
@@ -49,6 +97,10 @@ type Summary struct {
 	// Number of non-compliant resources
 	NonCompliantResources int
 
+	// Number of resources whose tags could not be read, counted neither as compliant nor
+	// as non-compliant
+	UnknownResources int
+
 	// Detailed violations across all resources
 	GlobalViolations map[ViolationType]int
 
@@ -57,6 +109,15 @@ type Summary struct {
 
 	// Resource type compliance summary
 	ResourceTypeCompliance map[string]float64
+
+	// Average compliance score of the resources, 100 when no resource was scanned
+	ComplianceScore float64
+
+	// Number of violations accepted by a suppression across all resources
+	SuppressedViolations int
+
+	// Number of warnings across all resources
+	Warnings int
 }
 
 // GenerateSummary creates a summary from multiple compliance results
@@ -69,13 +130,25 @@ func GenerateSummary(results []*ComplianceResult) *Summary {
 	}
 
 	resourceTypeCount := make(map[string]int)
+	totalScore := 0.0
+	scored := 0
 
 	for _, result := range results {
+		summary.SuppressedViolations += len(result.SuppressedViolations)
+		summary.Warnings += len(result.Warnings)
+		resourceTypeCount[result.ResourceType]++
+
+		// Resources of unknown compliance weigh neither on the counters nor on the score
+		if result.IsUnknown {
+			summary.UnknownResources++
+			continue
+		}
+		totalScore += result.Score
+		scored++
+
 		// Track compliance levels
 		summary.ComplianceLevelDistribution[result.ComplianceLevel]++
 
-		// Track resource type compliance
-		resourceTypeCount[result.ResourceType]++
 		if result.IsCompliant {
 			summary.CompliantResources++
 		} else {
@@ -99,6 +172,11 @@ func GenerateSummary(results []*ComplianceResult) *Summary {
 		summary.ResourceTypeCompliance[resourceType] = float64(compliantCount) / float64(count) * 100
 	}
 
+	summary.ComplianceScore = MaxComplianceScore
+	if scored > 0 {
+		summary.ComplianceScore = totalScore / float64(scored)
+	}
+
 	return summary
 }
 
@@ -106,17 +184,25 @@ func GenerateSummary(results []*ComplianceResult) *Summary {
 func (cr *ComplianceResult) String() string {
 	var sb strings.Builder
 
-	sb.WriteString(fmt.Sprintf("Compliance Status: %v\n", cr.IsCompliant))
+	if cr.IsUnknown {
+		sb.WriteString("Compliance Status: unknown\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("Compliance Status: %v\n", cr.IsCompliant))
+	}
 	sb.WriteString(fmt.Sprintf("Compliance Level: %s\n", cr.ComplianceLevel))
 	sb.WriteString(fmt.Sprintf("Resource Type: %s\n", cr.ResourceType))
+	sb.WriteString(fmt.Sprintf("Compliance Score: %.1f\n", cr.Score))
 
 	if !cr.IsCompliant {
 		sb.WriteString("Violations:\n")
 		for _, violation := range cr.Violations {
-			sb.WriteString(fmt.Sprintf("- %s: %s\n", violation.Type, violation.Message))
+			sb.WriteString(fmt.Sprintf("- [%s] %s: %s\n", violation.Severity, violation.Type, violation.Message))
 			if violation.SuggestedFix != "" {
 				sb.WriteString(fmt.Sprintf("  Suggested Fix: %s\n", violation.SuggestedFix))
 			}
+			if violation.SuggestedValue != "" {
+				sb.WriteString(fmt.Sprintf("  Did you mean: '%s'?\n", violation.SuggestedValue))
+			}
 		}
 	}
 
@@ -131,6 +217,7 @@ func (cr *ComplianceResult) ToJSON() map[string]interface{} {
 		"resource_type":    cr.ResourceType,
 		"resource_tags":    cr.ResourceTags,
 		"violations":       cr.Violations,
+		"score":            cr.Score,
 	}
 }
 
@@ -146,6 +233,7 @@ func Merge(results []*ComplianceResult) *ComplianceResult {
 		Violations:      []Violation{},
 		ComplianceLevel: ComplianceLevelLow,
 		ResourceType:    results[0].ResourceType,
+		Score:           MaxComplianceScore,
 	}
 
 	// Determine the lowest compliance level
@@ -162,6 +250,9 @@ func Merge(results []*ComplianceResult) *ComplianceResult {
 		// Merge violations
 		mergedResult.Violations = append(mergedResult.Violations, result.Violations...)
 
+		// Keep the lowest score
+		mergedResult.Score = math.Min(mergedResult.Score, result.Score)
+
 		// Set the most stringent compliance level
 		if result.ComplianceLevel == ComplianceLevelHigh {
 			mergedResult.ComplianceLevel = ComplianceLevelHigh
@@ -91,3 +91,21 @@ func TestGenerateSummary(t *testing.T) {
 	assert.Equal(t, 1, summary.GlobalViolations[ViolationTypeMissingTags])
 	assert.Equal(t, 1, summary.GlobalViolations[ViolationTypeInvalidValue])
 }
+
+func TestGenerateSummary_ComplianceScore(t *testing.T) {
+	t.Run("Average of resource scores", func(t *testing.T) {
+		summary := GenerateSummary([]*ComplianceResult{
+			{IsCompliant: true, Score: 100},
+			{IsCompliant: false, Score: 60},
+			{IsCompliant: false, Score: 20},
+		})
+
+		assert.InDelta(t, 60.0, summary.ComplianceScore, 0.001)
+	})
+
+	t.Run("No resources", func(t *testing.T) {
+		summary := GenerateSummary(nil)
+
+		assert.Equal(t, MaxComplianceScore, summary.ComplianceScore)
+	})
+}
@@ -1,5 +1,7 @@
 package compliance
 
+import "slices"
+
 // ViolationType represents different types of tag compliance violations
 type ViolationType string
 
@@ -25,8 +27,51 @@
 	// ViolationTypeProhibitedTag indicates use of a prohibited tag
 	ViolationTypeProhibitedTag ViolationType = "prohibited_tag"
 
-	// ViolationTypeExcessTags indicates exceeding the maximum number of allowed tags
-	ViolationTypeExcessTags ViolationType = "excess_tags"
+	// ViolationTypeTooManyTags indicates exceeding the maximum number of allowed tags
+	ViolationTypeTooManyTags ViolationType = "too_many_tags"
+
+	// ViolationTypeExcessTags indicates exceeding the maximum number of allowed tags.
+	//
+	// Deprecated: use ViolationTypeTooManyTags, which replaced the excess_tags violation type.
+	ViolationTypeExcessTags = ViolationTypeTooManyTags
+
+	// ViolationTypeForbiddenTag indicates a tag listed in the forbidden tags of the tag criteria
+	ViolationTypeForbiddenTag ViolationType = "forbidden_tag_present"
+
+	// ViolationTypeSpecificTagMismatch indicates a specific tag missing or without its exact value
+	ViolationTypeSpecificTagMismatch ViolationType = "specific_tag_mismatch"
+
+	// ViolationTypeTagsUnreadable indicates a resource whose tags could not be read, such as
+	// when the tagging API denies access
+	ViolationTypeTagsUnreadable ViolationType = "tags_unreadable"
+
+	// ViolationTypeDuplicateKeyDifferentCase indicates tag keys that are equal ignoring case,
+	// such as Environment and environment
+	ViolationTypeDuplicateKeyDifferentCase ViolationType = "duplicate_key_different_case"
+
+	// ViolationTypeAWSTagLimit indicates a tag AWS would refuse to write, such as a value
+	// longer than 256 characters
+	ViolationTypeAWSTagLimit ViolationType = "aws_tag_limit_violation"
+
+	// ViolationTypeInvalidKeyPrefix indicates a tag key not starting with any of the allowed
+	// prefixes of the key validation rules
+	ViolationTypeInvalidKeyPrefix ViolationType = "invalid_key_prefix"
+
+	// ViolationTypeInvalidKeySuffix indicates a tag key not ending with any of the allowed
+	// suffixes of the key validation rules
+	ViolationTypeInvalidKeySuffix ViolationType = "invalid_key_suffix"
+
+	// ViolationTypeKeyTooLong indicates a tag key longer than the maximum length of the key
+	// validation rules
+	ViolationTypeKeyTooLong ViolationType = "key_length_violation"
+
+	// ViolationTypeDeprecatedValue indicates a tag value still allowed but deprecated, reported
+	// as a warning carrying its replacement rather than as a violation
+	ViolationTypeDeprecatedValue ViolationType = "deprecated_value"
+
+	// ViolationTypeDuplicateTagValue indicates a tag value a unique_value cross-resource rule
+	// requires to be unique within a scope, shared with other resources of the scope
+	ViolationTypeDuplicateTagValue ViolationType = "duplicate_tag_value"
 )
 
 // ComplianceLevel defines the strictness of tag compliance
@@ -39,10 +84,82 @@
 	// ComplianceLevelStandard represents a standard compliance level
 	ComplianceLevelStandard ComplianceLevel = "standard"
 
+	// ComplianceLevelMedium represents a moderate compliance level
+	ComplianceLevelMedium ComplianceLevel = "medium"
+
 	// ComplianceLevelLow represents a relaxed compliance level
 	ComplianceLevelLow ComplianceLevel = "low"
 )
 
+// complianceLevelRanking orders the compliance levels from the strictest to the most relaxed
+var complianceLevelRanking = []ComplianceLevel{
+	ComplianceLevelHigh,
+	ComplianceLevelStandard,
+	ComplianceLevelMedium,
+	ComplianceLevelLow,
+}
+
+// ComplianceLevelNone is the key under which resources meeting no compliance level are
+// counted, their ComplianceLevel being empty
+const ComplianceLevelNone ComplianceLevel = "none"
+
+// ComplianceLevels returns the compliance levels from the strictest to the most relaxed
+func ComplianceLevels() []ComplianceLevel {
+	return slices.Clone(complianceLevelRanking)
+}
+
+// Meets reports whether the level is the minimum level or a stricter one. An empty level,
+// met by resources meeting no level, meets no minimum.
+func (l ComplianceLevel) Meets(minimum ComplianceLevel) bool {
+	rank, minimumRank := slices.Index(complianceLevelRanking, l), slices.Index(complianceLevelRanking, minimum)
+	return rank >= 0 && minimumRank >= 0 && rank <= minimumRank
+}
+
+// Severity ranks how serious a violation is, it weighs the violation in the compliance score
+type Severity string
+
+const (
+	// SeverityCritical represents a violation that must be fixed right away
+	SeverityCritical Severity = "critical"
+
+	// SeverityHigh represents a serious violation
+	SeverityHigh Severity = "high"
+
+	// SeverityMedium represents a regular violation, the default for rules without a severity
+	SeverityMedium Severity = "medium"
+
+	// SeverityLow represents a minor violation
+	SeverityLow Severity = "low"
+)
+
+// MaxComplianceScore is the score of a resource without violations
+const MaxComplianceScore = 100.0
+
+// severityPenalties holds how many points a violation of each severity takes off the score
+var severityPenalties = map[Severity]float64{
+	SeverityCritical: 40,
+	SeverityHigh:     20,
+	SeverityMedium:   10,
+	SeverityLow:      5,
+}
+
+// Penalty returns the points a violation of this severity takes off the compliance score.
+// Unknown severities weigh as medium ones.
+func (s Severity) Penalty() float64 {
+	if penalty, ok := severityPenalties[s]; ok {
+		return penalty
+	}
+	return severityPenalties[SeverityMedium]
+}
+
+// severityRanking orders the severities from the most to the least serious
+var severityRanking = []Severity{
+	SeverityCritical,
+	SeverityHigh,
+	SeverityMedium,
+	SeverityLow,
+}
+
 // Rule represents a single tag validation rule
 type Rule struct {
 	// Type of rule (case, value, pattern, key_format, length, prohibited)
@@ -1,6 +1,7 @@
 package compliance
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -22,6 +23,11 @@ func TestComplianceLevels(t *testing.T) {
 			level:    ComplianceLevelStandard,
 			expected: "standard",
 		},
+		{
+			name:     "Medium Compliance Level",
+			level:    ComplianceLevelMedium,
+			expected: "medium",
+		},
 		{
 			name:     "Low Compliance Level",
 			level:    ComplianceLevelLow,
@@ -36,6 +42,27 @@ func TestComplianceLevels(t *testing.T) {
 	}
 }
 
+func TestComplianceLevel_Meets(t *testing.T) {
+	testCases := []struct {
+		level    ComplianceLevel
+		minimum  ComplianceLevel
+		expected bool
+	}{
+		{level: ComplianceLevelHigh, minimum: ComplianceLevelStandard, expected: true},
+		{level: ComplianceLevelStandard, minimum: ComplianceLevelStandard, expected: true},
+		{level: ComplianceLevelMedium, minimum: ComplianceLevelStandard, expected: false},
+		{level: ComplianceLevelLow, minimum: ComplianceLevelLow, expected: true},
+		{level: "", minimum: ComplianceLevelLow, expected: false},
+		{level: ComplianceLevelHigh, minimum: "unknown", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s meets %s", tc.level, tc.minimum), func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.level.Meets(tc.minimum))
+		})
+	}
+}
+
 func TestViolationTypes(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -0,0 +1,155 @@
+package compliance
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+)
+
+// Rules checked by TestValue
+const (
+	RuleCase              = "case"
+	RulePattern           = "pattern"
+	RuleAllowedValues     = "allowed_values"
+	RuleLength            = "length"
+	RuleAllowedCharacters = "allowed_characters"
+	RuleDisallowedValues  = "disallowed_values"
+)
+
+// RuleCheck is the outcome of a configured rule for a tag value
+type RuleCheck struct {
+	// Rule is the kind of rule, such as pattern or allowed_values
+	Rule string `json:"rule" yaml:"rule"`
+
+	// Constraint describes what the rule requires, e.g. the pattern
+	Constraint string `json:"constraint" yaml:"constraint"`
+
+	Passed bool `json:"passed" yaml:"passed"`
+
+	// Message is the configured message of the rule, or the message of its violation when
+	// the rule has none
+	Message string `json:"message" yaml:"message"`
+}
+
+// TestValue runs a value through every rule of the configuration applying to the values of
+// a tag: its case rule, pattern rule, allowed values and length rule, then the value
+// validation rules applying to every tag. The tag is matched like ValidateTags matches it,
+// through the tag normalization and regardless of case. No check is returned when no rule
+// applies to the tag.
+//
+// Length and value validation rules are checked here and when generating tags, compliance
+// checks do not enforce them.
+func (v *TagValidator) TestValue(key, value string) []RuleCheck {
+	normalized, _ := v.normalizeTags(map[string]string{key: value})
+	for canonical := range normalized {
+		key = canonical
+	}
+
+	tagValidation := v.config.TagValidation
+	var checks []RuleCheck
+
+	for _, ruleKey := range slices.Sorted(maps.Keys(tagValidation.CaseRules)) {
+		caseRule := tagValidation.CaseRules[ruleKey]
+		if !strings.EqualFold(key, ruleKey) {
+			continue
+		}
+
+		var expected string
+		switch caseRule.Case {
+		case configuration.CaseLowercase:
+			expected = strings.ToLower(value)
+		case configuration.CaseUppercase:
+			expected = strings.ToUpper(value)
+		default:
+			continue
+		}
+		checks = append(checks, RuleCheck{
+			Rule:       RuleCase,
+			Constraint: string(caseRule.Case),
+			Passed:     value == expected,
+			Message:    ruleMessage(caseRule.Message, "Tag value for '%s' must be %s", key, caseRule.Case),
+		})
+	}
+
+	for _, ruleKey := range sortedKeys(tagValidation.PatternRules) {
+		if !strings.EqualFold(key, ruleKey) {
+			continue
+		}
+		pattern, exists := v.patterns.PatternRule(ruleKey)
+		if !exists {
+			continue
+		}
+		checks = append(checks, RuleCheck{
+			Rule:       RulePattern,
+			Constraint: tagValidation.PatternRules[ruleKey],
+			Passed:     pattern.MatchString(value),
+			Message:    fmt.Sprintf("Tag value for '%s' does not match required pattern", key),
+		})
+	}
+
+	if allowedValues, exists := v.rulesOf("").allowedValuesOf(key); exists {
+		checks = append(checks, RuleCheck{
+			Rule:       RuleAllowedValues,
+			Constraint: strings.Join(allowedValues, ", "),
+			Passed:     slices.ContainsFunc(allowedValues, func(allowed string) bool { return strings.EqualFold(value, allowed) }),
+			Message:    fmt.Sprintf("Tag value for '%s' must be one of: %v", key, allowedValues),
+		})
+	}
+
+	for _, ruleKey := range slices.Sorted(maps.Keys(tagValidation.LengthRules)) {
+		if strings.EqualFold(key, ruleKey) {
+			checks = append(checks, lengthCheck(key, value, tagValidation.LengthRules[ruleKey]))
+		}
+	}
+
+	if allowedCharacters := v.patterns.AllowedCharacters(); allowedCharacters != nil {
+		checks = append(checks, RuleCheck{
+			Rule:       RuleAllowedCharacters,
+			Constraint: tagValidation.ValueValidation.AllowedCharacters,
+			Passed:     allowedCharacters.MatchString(value),
+			Message:    fmt.Sprintf("Tag value for '%s' must only contain the characters %s", key, tagValidation.ValueValidation.AllowedCharacters),
+		})
+	}
+
+	if disallowedValues := tagValidation.ValueValidation.DisallowedValues; len(disallowedValues) > 0 {
+		checks = append(checks, RuleCheck{
+			Rule:       RuleDisallowedValues,
+			Constraint: strings.Join(disallowedValues, ", "),
+			Passed:     !slices.ContainsFunc(disallowedValues, func(disallowed string) bool { return strings.EqualFold(value, disallowed) }),
+			Message:    fmt.Sprintf("Tag value for '%s' cannot be one of: %v", key, disallowedValues),
+		})
+	}
+
+	return checks
+}
+
+// lengthCheck checks the length of a value, in characters, against a length rule
+func lengthCheck(key, value string, lengthRule configuration.LengthRule) RuleCheck {
+	length := len([]rune(value))
+	check := RuleCheck{Rule: RuleLength, Passed: true}
+
+	var bounds []string
+	if lengthRule.MinLength != nil {
+		bounds = append(bounds, fmt.Sprintf("min %d", *lengthRule.MinLength))
+		check.Passed = check.Passed && length >= *lengthRule.MinLength
+	}
+	if lengthRule.MaxLength != nil {
+		bounds = append(bounds, fmt.Sprintf("max %d", *lengthRule.MaxLength))
+		check.Passed = check.Passed && length <= *lengthRule.MaxLength
+	}
+	check.Constraint = strings.Join(bounds, ", ")
+	check.Message = ruleMessage(lengthRule.Message, "Tag value for '%s' must have a length of %s characters", key, check.Constraint)
+
+	return check
+}
+
+// ruleMessage returns the configured message of a rule, or the default one when it has none
+func ruleMessage(configured, format string, args ...any) string {
+	if configured != "" {
+		return configured
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,92 @@
+package compliance
+
+import (
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestValue(t *testing.T) {
+	minLength, maxLength := 3, 7
+	config := createTestConfig()
+	config.TagValidation.LengthRules = map[string]configuration.LengthRule{
+		"CostCenter": {MinLength: &minLength, MaxLength: &maxLength, Message: "CostCenter must have 3 to 7 characters"},
+	}
+	config.TagValidation.PatternRules["CostCenter"] = `^[A-Z]{2}-[0-9]{4}$`
+	config.TagValidation.TagNormalization.Aliases = map[string]string{"env": "environment"}
+	validator := NewTagValidator(config)
+
+	type outcome struct {
+		rule   string
+		passed bool
+	}
+	outcomesOf := func(checks []RuleCheck) []outcome {
+		var outcomes []outcome
+		for _, check := range checks {
+			outcomes = append(outcomes, outcome{rule: check.Rule, passed: check.Passed})
+		}
+		return outcomes
+	}
+
+	testCases := []struct {
+		name     string
+		key      string
+		value    string
+		expected []outcome
+	}{
+		{
+			name:     "Value passing every rule",
+			key:      "environment",
+			value:    "production",
+			expected: []outcome{{RuleCase, true}, {RuleAllowedValues, true}},
+		},
+		{
+			name:     "Tag matched through aliases",
+			key:      "env",
+			value:    "Production",
+			expected: []outcome{{RuleCase, false}, {RuleAllowedValues, true}},
+		},
+		{
+			name:     "Value breaking the pattern and length rules",
+			key:      "costcenter",
+			value:    "CO-12345",
+			expected: []outcome{{RulePattern, false}, {RuleLength, false}},
+		},
+		{
+			name:     "Value passing the pattern and length rules",
+			key:      "CostCenter",
+			value:    "CO-1234",
+			expected: []outcome{{RulePattern, true}, {RuleLength, true}},
+		},
+		{
+			name:  "Tag without rules",
+			key:   "Project",
+			value: "taggy",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, outcomesOf(validator.TestValue(tc.key, tc.value)))
+		})
+	}
+}
+
+func TestTestValue_Messages(t *testing.T) {
+	config := createTestConfig()
+	config.TagValidation.ValueValidation = configuration.ValueValidation{
+		AllowedCharacters: "a-z",
+		DisallowedValues:  []string{"none"},
+	}
+	validator := NewTagValidator(config)
+
+	checks := validator.TestValue("environment", "None")
+
+	assert.Equal(t, []RuleCheck{
+		{Rule: RuleCase, Constraint: "lowercase", Passed: false, Message: "Environment must be lowercase"},
+		{Rule: RuleAllowedValues, Constraint: "production, staging, development", Passed: false, Message: "Tag value for 'environment' must be one of: [production staging development]"},
+		{Rule: RuleAllowedCharacters, Constraint: "a-z", Passed: false, Message: "Tag value for 'environment' must only contain the characters a-z"},
+		{Rule: RuleDisallowedValues, Constraint: "none", Passed: false, Message: "Tag value for 'environment' cannot be one of: [none]"},
+	}, checks)
+}
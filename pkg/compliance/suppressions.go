@@ -0,0 +1,277 @@
+package compliance
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SuppressionDateLayout is the layout of the expiry date of a suppression
+const SuppressionDateLayout = "2006-01-02"
+
+// knownViolationTypes are the violation types a suppression can accept
+var knownViolationTypes = map[ViolationType]bool{
+	ViolationTypeMissingTags:         true,
+	ViolationTypeCaseViolation:       true,
+	ViolationTypeInvalidValue:        true,
+	ViolationTypePatternViolation:    true,
+	ViolationTypeInvalidKeyFormat:    true,
+	ViolationTypeValueLength:         true,
+	ViolationTypeProhibitedTag:       true,
+	ViolationTypeTooManyTags:         true,
+	ViolationTypeForbiddenTag:        true,
+	ViolationTypeSpecificTagMismatch: true,
+
+	ViolationTypeDuplicateKeyDifferentCase: true,
+	ViolationTypeAWSTagLimit:               true,
+	ViolationTypeDuplicateTagValue:         true,
+}
+
+// renamedViolationTypes maps former violation type names to the current ones, so existing
+// suppressions files keep working
+var renamedViolationTypes = map[string]ViolationType{
+	"excess_tags": ViolationTypeTooManyTags,
+}
+
+// ResourceRef identifies the resource whose tags are validated, so suppressions can match it
+// and its resource-level tag criteria apply
+type ResourceRef struct {
+	ID   string
+	ARN  string
+	Type string
+}
+
+// Suppression accepts a violation type on the resources whose ID or ARN matches a pattern,
+// until an optional expiry date
+type Suppression struct {
+	// Regular expression matching the whole ID or ARN of the resources
+	Resource string `yaml:"resource"`
+
+	// Type of the accepted violation, e.g. missing_tags
+	ViolationType string `yaml:"violation_type"`
+
+	// Why the violation is accepted
+	Reason string `yaml:"reason"`
+
+	// Last day (YYYY-MM-DD) the violation is accepted, it never expires when empty
+	Expires string `yaml:"expires,omitempty"`
+}
+
+// SuppressionsFile is the content of a suppressions file
+type SuppressionsFile struct {
+	Suppressions []Suppression `yaml:"suppressions"`
+}
+
+// suppressionRule pairs a compiled suppression pattern with its entry
+type suppressionRule struct {
+	pattern *regexp.Regexp
+	expires time.Time
+	source  Suppression
+}
+
+// expired reports whether the suppression no longer applies at the given time. A suppression
+// applies until the end of its expiry date, in UTC.
+func (r suppressionRule) expired(now time.Time) bool {
+	return !r.expires.IsZero() && !now.Before(r.expires.AddDate(0, 0, 1))
+}
+
+// matches reports whether the rule covers a violation type of the resource
+func (r suppressionRule) matches(resource ResourceRef, violationType ViolationType) bool {
+	if ViolationType(r.source.ViolationType) != violationType {
+		return false
+	}
+	for _, candidate := range []string{resource.ID, resource.ARN} {
+		if candidate != "" && r.pattern.MatchString(candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// Suppressions matches violations against the accepted ones of a suppressions file
+type Suppressions struct {
+	rules []suppressionRule
+}
+
+// NewSuppressions validates and compiles suppression entries
+func NewSuppressions(entries []Suppression) (*Suppressions, error) {
+	rules := make([]suppressionRule, 0, len(entries))
+	for i, entry := range entries {
+		if entry.Resource == "" {
+			return nil, fmt.Errorf("suppression %d: resource pattern is required", i+1)
+		}
+		pattern, err := regexp.Compile("^(?:" + entry.Resource + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("suppression %d: invalid resource pattern %q: %w", i+1, entry.Resource, err)
+		}
+
+		if renamed, exists := renamedViolationTypes[entry.ViolationType]; exists {
+			entry.ViolationType = string(renamed)
+		}
+		if !knownViolationTypes[ViolationType(entry.ViolationType)] {
+			return nil, fmt.Errorf("suppression %d: unknown violation type %q, expected one of: %s",
+				i+1, entry.ViolationType, strings.Join(sortedViolationTypes(), ", "))
+		}
+
+		if strings.TrimSpace(entry.Reason) == "" {
+			return nil, fmt.Errorf("suppression %d: a reason is required to accept %s on %s", i+1, entry.ViolationType, entry.Resource)
+		}
+
+		rule := suppressionRule{pattern: pattern, source: entry}
+		if entry.Expires != "" {
+			rule.expires, err = time.Parse(SuppressionDateLayout, entry.Expires)
+			if err != nil {
+				return nil, fmt.Errorf("suppression %d: invalid expiry date %q, expected YYYY-MM-DD", i+1, entry.Expires)
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return &Suppressions{rules: rules}, nil
+}
+
+// LoadSuppressions reads and validates a suppressions file
+func LoadSuppressions(path string) (*Suppressions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suppressions file %s: %w", path, err)
+	}
+
+	var file SuppressionsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse suppressions file %s: %w", path, err)
+	}
+
+	suppressions, err := NewSuppressions(file.Suppressions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid suppressions file %s: %w", path, err)
+	}
+	return suppressions, nil
+}
+
+// Apply splits the violations of a resource into the ones still counted and the ones accepted
+// by an active suppression. Violations only covered by expired suppressions are kept, with a
+// note telling the suppression expired.
+func (s *Suppressions) Apply(resource ResourceRef, violations []Violation, now time.Time) ([]Violation, []Violation) {
+	if s == nil || len(s.rules) == 0 {
+		return violations, nil
+	}
+
+	kept := make([]Violation, 0, len(violations))
+	var suppressed []Violation
+	for _, violation := range violations {
+		var active, expired *suppressionRule
+		for i := range s.rules {
+			rule := &s.rules[i]
+			if !rule.matches(resource, violation.Type) {
+				continue
+			}
+			if !rule.expired(now) {
+				active = rule
+				break
+			}
+			if expired == nil {
+				expired = rule
+			}
+		}
+
+		switch {
+		case active != nil:
+			violation.Note = fmt.Sprintf("suppressed: %s", active.source.Reason)
+			if active.source.Expires != "" {
+				violation.Note = fmt.Sprintf("suppressed until %s: %s", active.source.Expires, active.source.Reason)
+			}
+			suppressed = append(suppressed, violation)
+		case expired != nil:
+			violation.Note = fmt.Sprintf("suppression expired on %s: %s", expired.source.Expires, expired.source.Reason)
+			kept = append(kept, violation)
+		default:
+			kept = append(kept, violation)
+		}
+	}
+
+	return kept, suppressed
+}
+
+// BaselineSuppressions accepts every violation type of a result, matching the resource by its
+// ARN, or by its ID when the ARN is unknown
+func BaselineSuppressions(resource ResourceRef, result *ComplianceResult, reason, expires string) []Suppression {
+	identifier := resource.ARN
+	if identifier == "" {
+		identifier = resource.ID
+	}
+	if identifier == "" {
+		return nil
+	}
+
+	seen := make(map[ViolationType]bool, len(result.Violations))
+	var suppressions []Suppression
+	for _, violation := range result.Violations {
+		if seen[violation.Type] {
+			continue
+		}
+		seen[violation.Type] = true
+		suppressions = append(suppressions, Suppression{
+			Resource:      regexp.QuoteMeta(identifier),
+			ViolationType: string(violation.Type),
+			Reason:        reason,
+			Expires:       expires,
+		})
+	}
+	return suppressions
+}
+
+// WriteSuppressions writes suppressions to a file, ordered by resource and violation type
+func WriteSuppressions(path string, suppressions []Suppression) error {
+	sorted := append([]Suppression(nil), suppressions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Resource != sorted[j].Resource {
+			return sorted[i].Resource < sorted[j].Resource
+		}
+		return sorted[i].ViolationType < sorted[j].ViolationType
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("# Violations accepted by aws-taggy compliance check --suppressions.\n")
+	buf.WriteString("# resource is a regular expression matching the whole ID or ARN of the resources.\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(SuppressionsFile{Suppressions: sorted}); err != nil {
+		return fmt.Errorf("failed to encode suppressions: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode suppressions: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write suppressions file %s: %w", path, err)
+	}
+	return nil
+}
+
+// hasViolation reports whether a violation of the given type is among the violations
+func hasViolation(violations []Violation, violationType ViolationType) bool {
+	for _, violation := range violations {
+		if violation.Type == violationType {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedViolationTypes lists the known violation types in alphabetical order
+func sortedViolationTypes() []string {
+	types := make([]string, 0, len(knownViolationTypes))
+	for violationType := range knownViolationTypes {
+		types = append(types, string(violationType))
+	}
+	sort.Strings(types)
+	return types
+}
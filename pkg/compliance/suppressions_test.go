@@ -0,0 +1,203 @@
+package compliance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSuppressions_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		suppression Suppression
+		errContains string
+	}{
+		{
+			name:        "Missing Resource",
+			suppression: Suppression{ViolationType: "missing_tags", Reason: "legacy"},
+			errContains: "resource pattern is required",
+		},
+		{
+			name:        "Invalid Pattern",
+			suppression: Suppression{Resource: "legacy-(", ViolationType: "missing_tags", Reason: "legacy"},
+			errContains: "invalid resource pattern",
+		},
+		{
+			name:        "Unknown Violation Type",
+			suppression: Suppression{Resource: "legacy-.*", ViolationType: "missing_owner", Reason: "legacy"},
+			errContains: "unknown violation type",
+		},
+		{
+			name:        "Missing Reason",
+			suppression: Suppression{Resource: "legacy-.*", ViolationType: "missing_tags"},
+			errContains: "a reason is required",
+		},
+		{
+			name:        "Invalid Expiry Date",
+			suppression: Suppression{Resource: "legacy-.*", ViolationType: "missing_tags", Reason: "legacy", Expires: "31/01/2025"},
+			errContains: "invalid expiry date",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewSuppressions([]Suppression{tt.suppression})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestNewSuppressions_RenamedViolationType(t *testing.T) {
+	t.Parallel()
+
+	suppressions, err := NewSuppressions([]Suppression{
+		{Resource: "bucket-1", ViolationType: "excess_tags", Reason: "Tagged by a vendor"},
+	})
+	require.NoError(t, err)
+
+	violations := []Violation{{Type: ViolationTypeTooManyTags, Message: "Number of tags (3) exceeds maximum allowed (2)"}}
+	kept, suppressed := suppressions.Apply(ResourceRef{ID: "bucket-1"}, violations, time.Now())
+	assert.Empty(t, kept)
+	assert.Len(t, suppressed, 1)
+}
+
+func TestSuppressionsApply(t *testing.T) {
+	t.Parallel()
+
+	suppressions, err := NewSuppressions([]Suppression{
+		{Resource: `arn:aws:s3:::legacy-.*`, ViolationType: "missing_tags", Reason: "Legacy buckets"},
+		{Resource: `bucket-1`, ViolationType: "invalid_value", Reason: "Migration pending", Expires: "2025-01-31"},
+	})
+	require.NoError(t, err)
+
+	violations := []Violation{
+		{Type: ViolationTypeMissingTags, Message: "Missing required tags: [owner]"},
+		{Type: ViolationTypeInvalidValue, Message: "Tag value for 'environment' must be one of: [production]"},
+	}
+
+	tests := []struct {
+		name               string
+		resource           ResourceRef
+		now                time.Time
+		expectedKept       []Violation
+		expectedSuppressed []Violation
+	}{
+		{
+			name:         "Active Suppressions",
+			resource:     ResourceRef{ID: "bucket-1", ARN: "arn:aws:s3:::legacy-1"},
+			now:          time.Date(2025, 1, 31, 23, 59, 0, 0, time.UTC),
+			expectedKept: []Violation{},
+			expectedSuppressed: []Violation{
+				{Type: ViolationTypeMissingTags, Message: "Missing required tags: [owner]", Note: "suppressed: Legacy buckets"},
+				{Type: ViolationTypeInvalidValue, Message: "Tag value for 'environment' must be one of: [production]", Note: "suppressed until 2025-01-31: Migration pending"},
+			},
+		},
+		{
+			name:     "Expired Suppression Is Noted",
+			resource: ResourceRef{ID: "bucket-1", ARN: "arn:aws:s3:::legacy-1"},
+			now:      time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			expectedKept: []Violation{
+				{Type: ViolationTypeInvalidValue, Message: "Tag value for 'environment' must be one of: [production]", Note: "suppression expired on 2025-01-31: Migration pending"},
+			},
+			expectedSuppressed: []Violation{
+				{Type: ViolationTypeMissingTags, Message: "Missing required tags: [owner]", Note: "suppressed: Legacy buckets"},
+			},
+		},
+		{
+			name:         "Patterns Match The Whole Identifier",
+			resource:     ResourceRef{ID: "bucket-10", ARN: "arn:aws:s3:::other-legacy-1"},
+			now:          time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedKept: violations,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			kept, suppressed := suppressions.Apply(tt.resource, violations, tt.now)
+			assert.Equal(t, tt.expectedKept, kept)
+			assert.Equal(t, tt.expectedSuppressed, suppressed)
+		})
+	}
+}
+
+func TestValidateResource_Suppressions(t *testing.T) {
+	t.Parallel()
+
+	config := &configuration.TaggyScanConfig{
+		Global: configuration.GlobalConfig{
+			Enabled: true,
+			TagCriteria: configuration.TagCriteria{
+				RequiredTags: []string{"environment", "owner"},
+			},
+		},
+	}
+
+	suppressions, err := NewSuppressions([]Suppression{
+		{Resource: `arn:aws:s3:::legacy-.*`, ViolationType: "missing_tags", Reason: "Legacy buckets"},
+	})
+	require.NoError(t, err)
+
+	validator := NewTagValidator(config)
+	validator.SetSuppressions(suppressions)
+
+	suppressed := validator.ValidateResource(ResourceRef{ID: "legacy-1", ARN: "arn:aws:s3:::legacy-1"}, map[string]string{})
+	assert.True(t, suppressed.IsCompliant)
+	assert.Empty(t, suppressed.Violations)
+	assert.Len(t, suppressed.SuppressedViolations, 1)
+	assert.Equal(t, MaxComplianceScore, suppressed.Score)
+
+	counted := validator.ValidateResource(ResourceRef{ID: "payments", ARN: "arn:aws:s3:::payments"}, map[string]string{})
+	assert.False(t, counted.IsCompliant)
+	assert.Len(t, counted.Violations, 1)
+	assert.Empty(t, counted.SuppressedViolations)
+	assert.Less(t, counted.Score, MaxComplianceScore)
+
+	summary := GenerateSummary([]*ComplianceResult{suppressed, counted})
+	assert.Equal(t, 1, summary.SuppressedViolations)
+	assert.Equal(t, 1, summary.CompliantResources)
+}
+
+func TestWriteAndLoadSuppressions(t *testing.T) {
+	t.Parallel()
+
+	result := &ComplianceResult{
+		Violations: []Violation{
+			{Type: ViolationTypeMissingTags},
+			{Type: ViolationTypeInvalidValue},
+			{Type: ViolationTypeInvalidValue},
+		},
+	}
+	baseline := BaselineSuppressions(ResourceRef{ID: "my.bucket", ARN: "arn:aws:s3:::my.bucket"}, result, "Accepted at adoption", "2030-12-31")
+	require.Len(t, baseline, 2)
+	assert.Equal(t, `arn:aws:s3:::my\.bucket`, baseline[0].Resource)
+
+	path := filepath.Join(t.TempDir(), "suppressions.yaml")
+	require.NoError(t, WriteSuppressions(path, baseline))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "violation_type: invalid_value")
+
+	suppressions, err := LoadSuppressions(path)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	kept, suppressed := suppressions.Apply(ResourceRef{ARN: "arn:aws:s3:::my.bucket"}, result.Violations, now)
+	assert.Empty(t, kept)
+	assert.Len(t, suppressed, 3)
+
+	kept, _ = suppressions.Apply(ResourceRef{ARN: "arn:aws:s3:::myxbucket"}, result.Violations, now)
+	assert.Len(t, kept, 3)
+}
@@ -3,8 +3,12 @@
 import (
 	"fmt"
 	"log"
-	"regexp"
+	"maps"
+	"math"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
 )
@@ -16,83 +20,327 @@ type Validator interface {
 
 // TagValidator implements the Validator interface
 type TagValidator struct {
-	config *configuration.TaggyScanConfig
+	config       *configuration.TaggyScanConfig
+	suppressions *Suppressions
+
+	// patterns are the compiled tag validation patterns, shared by every validation
+	patterns *configuration.CompiledTagPatterns
+
+	// resourceRules are the rules of the resource types overriding the global tag validation
+	resourceRules map[string]tagRules
+
+	// unreadableNonCompliant validates resources whose tags could not be read as untagged
+	unreadableNonCompliant bool
+
+	// batchWorkers is the number of goroutines of ValidateResourcesBatch, GOMAXPROCS when unset
+	batchWorkers int
+
+	// now tells whether suppressions have expired, replaceable in tests
+	now func() time.Time
 }
 
 // NewTagValidator creates a new TagValidator with the given configuration
+// Rules whose pattern does not compile are logged and skipped by every validation.
 func NewTagValidator(config *configuration.TaggyScanConfig) *TagValidator {
+	patterns, err := config.TagValidation.Patterns()
+	if err != nil {
+		log.Printf("Skipping tag validation rules with invalid patterns: %v", err)
+	}
+
+	// Resource types overriding the global rules get their own rules, compiled once too
+	resourceRules := make(map[string]tagRules)
+	for _, resourceType := range slices.Sorted(maps.Keys(config.Resources)) {
+		if config.Resources[resourceType].TagValidation == nil {
+			continue
+		}
+		validation := config.ResourceTagValidation(resourceType)
+		resourcePatterns, err := validation.Patterns()
+		if err != nil {
+			log.Printf("Skipping tag validation rules of resource %s with invalid patterns: %v", resourceType, err)
+		}
+		resourceRules[resourceType] = tagRules{validation: &validation, patterns: resourcePatterns}
+	}
+
 	return &TagValidator{
-		config: config,
+		config:        config,
+		patterns:      patterns,
+		resourceRules: resourceRules,
+		now:           time.Now,
+	}
+}
+
+// tagRules are tag validation rules along with their compiled patterns: the global ones, or
+// those of a resource type overriding them
+type tagRules struct {
+	validation *configuration.TagValidation
+	patterns   *configuration.CompiledTagPatterns
+}
+
+// rulesOf returns the tag validation rules the resources of a type are checked against
+func (v *TagValidator) rulesOf(resourceType string) tagRules {
+	if rules, exists := v.resourceRules[resourceType]; exists {
+		return rules
+	}
+	return tagRules{validation: &v.config.TagValidation, patterns: v.patterns}
+}
+
+// SetSuppressions sets the accepted violations left out of the results of ValidateResource
+func (v *TagValidator) SetSuppressions(suppressions *Suppressions) {
+	v.suppressions = suppressions
+}
+
+// SetTreatUnreadableAsNonCompliant makes ValidateUnreadable validate the resources whose
+// tags could not be read as untagged resources, instead of reporting their compliance as unknown
+func (v *TagValidator) SetTreatUnreadableAsNonCompliant(nonCompliant bool) {
+	v.unreadableNonCompliant = nonCompliant
+}
+
+// ValidateUnreadable reports the compliance of a resource whose tags could not be read, for
+// the given reason. Its compliance is unknown, unless SetTreatUnreadableAsNonCompliant is set:
+// the resource is then validated as carrying no tags. Either way the result carries a
+// tags_unreadable violation explaining why the tags are missing.
+func (v *TagValidator) ValidateUnreadable(resource ResourceRef, reason string) *ComplianceResult {
+	violation := Violation{
+		Type:     ViolationTypeTagsUnreadable,
+		Message:  fmt.Sprintf("Tags could not be read: %s", reason),
+		Severity: SeverityMedium,
+		DocURL:   violationDocURL(ViolationTypeTagsUnreadable),
+	}
+
+	if v.unreadableNonCompliant {
+		result := v.ValidateResource(resource, map[string]string{})
+		result.IsCompliant = false
+		result.Violations = append(result.Violations, violation)
+		return result
+	}
+
+	return &ComplianceResult{
+		IsCompliant:  false,
+		IsUnknown:    true,
+		Violations:   []Violation{violation},
+		ResourceTags: map[string]string{},
+		ResourceType: resource.Type,
 	}
 }
 
-// ValidateTags checks the compliance of a set of tags against the configuration
+// ValidateTags checks the compliance of a set of tags against the configuration. Only the
+// global tag criteria apply, as the tags belong to no resource type.
 func (v *TagValidator) ValidateTags(tags map[string]string) *ComplianceResult {
+	return v.ValidateResource(ResourceRef{}, tags)
+}
+
+// ValidateResource checks the compliance of the tags of a resource against the configuration,
+// including the tag criteria of its resource type.
+// Violations accepted by a suppression of the resource are moved to SuppressedViolations and
+// do not count against its compliance status and score. The rule categories disabled for the
+// resource type are not evaluated and are listed in SkippedRules.
+func (v *TagValidator) ValidateResource(resource ResourceRef, tags map[string]string) *ComplianceResult {
 	result := &ComplianceResult{
 		IsCompliant:  true,
 		Violations:   make([]Violation, 0),
 		ResourceTags: tags,
+		ResourceType: resource.Type,
+	}
+
+	// Normalize tag keys first, so every rule is evaluated against canonical keys while
+	// violations keep referencing the key the resource actually has
+	normalizedTags, originalKeys := v.normalizeTags(tags)
+
+	// Record the strictest compliance level the tags meet
+	result.ComplianceLevel = v.achievedComplianceLevel(normalizedTags)
+
+	// The global tag criteria apply to every resource, along with those of its resource type
+	levels := v.criteriaLevels(resource.Type)
+	rules := v.rulesOf(resource.Type)
+
+	result.SkippedRules = v.disabledRules(resource.Type)
+	enabled := func(rule string) bool {
+		return !slices.Contains(result.SkippedRules, rule)
 	}
 
 	// Check tag count first
-	if v.config.Global.TagCriteria.MaxTags > 0 && len(tags) > v.config.Global.TagCriteria.MaxTags {
+	if maxTags := maxTagsOf(levels); enabled(configuration.RuleMaxTags) && maxTags > 0 && len(tags) > maxTags {
 		result.Violations = append(result.Violations, Violation{
-			Type:    ViolationTypeExcessTags,
-			Message: fmt.Sprintf("Number of tags (%d) exceeds maximum allowed (%d)", len(tags), v.config.Global.TagCriteria.MaxTags),
+			Type:     ViolationTypeTooManyTags,
+			Message:  fmt.Sprintf("Number of tags (%d) exceeds maximum allowed (%d)", len(tags), maxTags),
+			Expected: fmt.Sprintf("at most %d tags", maxTags),
+			Severity: SeverityMedium,
 		})
 		result.IsCompliant = false
 	}
 
-	// Check required tags
-	missingTags := v.checkRequiredTags(tags)
-	if len(missingTags) > 0 {
-		result.Violations = append(result.Violations, Violation{
-			Type:    ViolationTypeMissingTags,
-			Message: fmt.Sprintf("Missing required tags: %v", missingTags),
-		})
-		result.IsCompliant = false
+	// Check required tags, reporting the missing ones of each level in their own violation
+	var missingTags []missingTag
+	if enabled(configuration.RuleRequiredTags) {
+		for i, missing := range checkRequiredTags(levels, normalizedTags) {
+			if len(missing) == 0 {
+				continue
+			}
+			result.Violations = append(result.Violations, Violation{
+				Type:     ViolationTypeMissingTags,
+				Message:  missingTagsMessage(levels[i].name, missing),
+				Expected: strings.Join(missingTagKeys(missing), ", "),
+				Severity: missingTagsSeverity(missing),
+			})
+			result.IsCompliant = false
+			missingTags = append(missingTags, missing...)
+		}
+
+		// Levels requiring any N of their required tags report how many of them are present
+		for _, level := range levels {
+			present, shortfall := checkMinimumRequiredTags(level.criteria, normalizedTags)
+			if len(shortfall) == 0 {
+				continue
+			}
+			result.Violations = append(result.Violations, Violation{
+				Type:     ViolationTypeMissingTags,
+				Message:  minimumRequiredTagsMessage(level.name, present, level.criteria),
+				Expected: fmt.Sprintf("at least %d of %s", level.criteria.RequiredTagsNeeded(), strings.Join(level.criteria.RequiredTags, ", ")),
+				Severity: missingTagsSeverity(shortfall),
+			})
+			result.IsCompliant = false
+			missingTags = append(missingTags, shortfall...)
+		}
+	}
+
+	// Check the exact values of specific tags
+	specificTags := specificTagsOf(levels)
+	for _, key := range sortedKeys(specificTags) {
+		expected := specificTags[key]
+		original, value, found := lookupTag(normalizedTags, originalKeys, key)
+		switch {
+		case !found:
+			result.Violations = append(result.Violations, Violation{
+				Type:     ViolationTypeSpecificTagMismatch,
+				Message:  fmt.Sprintf("Tag '%s' is required with value '%s'", key, expected),
+				TagKey:   key,
+				Expected: expected,
+				Severity: SeverityMedium,
+			})
+			result.IsCompliant = false
+		case value != expected:
+			result.Violations = append(result.Violations, Violation{
+				Type:     ViolationTypeSpecificTagMismatch,
+				Message:  fmt.Sprintf("Tag value for '%s' must be '%s'", original, expected),
+				TagKey:   original,
+				Value:    value,
+				Expected: expected,
+				Severity: SeverityMedium,
+			})
+			result.IsCompliant = false
+		}
 	}
 
 	// Check prohibited tags
-	for key := range tags {
-		if v.isProhibitedTag(key) {
+	for key := range normalizedTags {
+		if enabled(configuration.RuleProhibitedTags) && v.isProhibitedTag(key) {
+			original := originalKeys[key]
 			result.Violations = append(result.Violations, Violation{
-				Type:    ViolationTypeProhibitedTag,
-				Message: fmt.Sprintf("Tag '%s' is prohibited", key),
-				TagKey:  key,
+				Type:     ViolationTypeProhibitedTag,
+				Message:  fmt.Sprintf("Tag '%s' is prohibited", original),
+				TagKey:   original,
+				Expected: absentTag,
+				Severity: SeverityMedium,
 			})
 			result.IsCompliant = false
 		}
 	}
 
-	// Validate case rules and key format for all tags
-	for key, value := range tags {
+	// Check forbidden tags against the keys the resource actually has
+	for _, key := range forbiddenTagsPresent(levels, tags) {
+		result.Violations = append(result.Violations, Violation{
+			Type:     ViolationTypeForbiddenTag,
+			Message:  fmt.Sprintf("Tag '%s' is forbidden", key),
+			TagKey:   key,
+			Expected: absentTag,
+			Severity: SeverityMedium,
+		})
+		result.IsCompliant = false
+	}
+
+	// Check tag keys only differing in case, which AWS keeps as distinct tags
+	if v.config.TagValidation.KeyValidation.DenyCaseDuplicates && enabled(configuration.RuleKeyValidation) {
+		for _, keys := range caseDuplicateKeys(tags) {
+			violation := Violation{
+				Type:     ViolationTypeDuplicateKeyDifferentCase,
+				Message:  fmt.Sprintf("Tag keys '%s' only differ in case", strings.Join(keys, "', '")),
+				TagKey:   keys[0],
+				Expected: "a single spelling of the key",
+				Severity: SeverityMedium,
+			}
+			if preferred := rules.preferredKeySpelling(keys); preferred != "" {
+				violation.SuggestedValue = preferred
+				violation.SuggestedFix = fmt.Sprintf("Keep '%s', which follows the configured case rules, and remove the other keys", preferred)
+			}
+			result.Violations = append(result.Violations, violation)
+			result.IsCompliant = false
+		}
+	}
+
+	// Check the keys against the allowed prefixes, suffixes and maximum length, the rules the
+	// configuration validation checks them against
+	if enabled(configuration.RuleKeyValidation) {
+		if violations := v.keyValidationViolations(tags); len(violations) > 0 {
+			result.Violations = append(result.Violations, violations...)
+			result.IsCompliant = false
+		}
+	}
+
+	// Check the limits AWS enforces on every tag, which the rules of the configuration may
+	// leave out
+	if v.config.TagValidation.AWSLimitsEnforced() {
+		if violations := awsTagLimitViolations(tags, true); len(violations) > 0 {
+			result.Violations = append(result.Violations, violations...)
+			result.IsCompliant = false
+		}
+	}
+
+	// Validate case rules and key format for all tags, leaving out the disabled ones
+	keyFormatRules := v.config.TagValidation.KeyFormatRules
+	if !enabled(configuration.RuleKeyFormat) {
+		keyFormatRules = nil
+	}
+	caseRules := rules.validation.CaseRules
+	if !enabled(configuration.RuleCaseRules) {
+		caseRules = nil
+	}
+	patternRules := rules.validation.PatternRules
+	if !enabled(configuration.RulePatternRules) {
+		patternRules = nil
+	}
+	for key, value := range normalizedTags {
+		original := originalKeys[key]
+
 		// Check key format rules
-		for _, rule := range v.config.TagValidation.KeyFormatRules {
-			matched, err := regexp.MatchString(rule.Pattern, key)
-			if err != nil {
-				log.Printf("Error matching key format pattern for tag %s: %v", key, err)
+		for i, rule := range keyFormatRules {
+			pattern := v.patterns.KeyFormatRule(i)
+			if pattern == nil {
 				continue
 			}
-			if !matched {
+			if !pattern.MatchString(key) {
 				result.Violations = append(result.Violations, Violation{
-					Type:    ViolationTypeInvalidKeyFormat,
-					Message: fmt.Sprintf("Tag key '%s': %s", key, rule.Message),
-					TagKey:  key,
+					Type:     ViolationTypeInvalidKeyFormat,
+					Message:  fmt.Sprintf("Tag key '%s': %s", original, rule.Message),
+					TagKey:   original,
+					Expected: fmt.Sprintf("key matching %s", rule.Pattern),
+					Severity: SeverityMedium,
 				})
 				result.IsCompliant = false
 			}
 		}
 
 		// Check case rules
-		for ruleKey, caseRule := range v.config.TagValidation.CaseRules {
+		for ruleKey, caseRule := range caseRules {
 			if strings.EqualFold(key, ruleKey) {
 				// Check key case
 				if key != strings.ToLower(ruleKey) {
 					result.Violations = append(result.Violations, Violation{
-						Type:    ViolationTypeCaseViolation,
-						Message: fmt.Sprintf("Tag key '%s' must match case '%s'", key, strings.ToLower(ruleKey)),
-						TagKey:  key,
+						Type:           ViolationTypeCaseViolation,
+						Message:        fmt.Sprintf("Tag key '%s' must match case '%s'", original, strings.ToLower(ruleKey)),
+						TagKey:         original,
+						Expected:       strings.ToLower(ruleKey),
+						SuggestedValue: strings.ToLower(ruleKey),
+						Severity:       severityOf(caseRule.Severity),
 					})
 					result.IsCompliant = false
 				}
@@ -102,18 +350,26 @@ func (v *TagValidator) ValidateTags(tags map[string]string) *ComplianceResult {
 				case "lowercase":
 					if value != strings.ToLower(value) {
 						result.Violations = append(result.Violations, Violation{
-							Type:    ViolationTypeCaseViolation,
-							Message: fmt.Sprintf("Tag value for '%s' must be lowercase", key),
-							TagKey:  key,
+							Type:           ViolationTypeCaseViolation,
+							Message:        fmt.Sprintf("Tag value for '%s' must be lowercase", original),
+							TagKey:         original,
+							Value:          value,
+							Expected:       string(caseRule.Case),
+							SuggestedValue: rules.caseFix(key, strings.ToLower(value)),
+							Severity:       severityOf(caseRule.Severity),
 						})
 						result.IsCompliant = false
 					}
 				case "uppercase":
 					if value != strings.ToUpper(value) {
 						result.Violations = append(result.Violations, Violation{
-							Type:    ViolationTypeCaseViolation,
-							Message: fmt.Sprintf("Tag value for '%s' must be uppercase", key),
-							TagKey:  key,
+							Type:           ViolationTypeCaseViolation,
+							Message:        fmt.Sprintf("Tag value for '%s' must be uppercase", original),
+							TagKey:         original,
+							Value:          value,
+							Expected:       string(caseRule.Case),
+							SuggestedValue: rules.caseFix(key, strings.ToUpper(value)),
+							Severity:       severityOf(caseRule.Severity),
 						})
 						result.IsCompliant = false
 					}
@@ -122,18 +378,21 @@ func (v *TagValidator) ValidateTags(tags map[string]string) *ComplianceResult {
 		}
 
 		// Check pattern rules
-		for ruleKey, pattern := range v.config.TagValidation.PatternRules {
+		for ruleKey := range patternRules {
 			if strings.EqualFold(key, ruleKey) {
-				matched, err := regexp.MatchString(pattern, value)
-				if err != nil {
-					log.Printf("Error matching pattern for tag %s: %v", key, err)
+				pattern, exists := rules.patterns.PatternRule(ruleKey)
+				if !exists {
 					continue
 				}
-				if !matched {
+				if !pattern.MatchString(value) {
 					result.Violations = append(result.Violations, Violation{
-						Type:    ViolationTypePatternViolation,
-						Message: fmt.Sprintf("Tag value for '%s' does not match required pattern", key),
-						TagKey:  key,
+						Type:           ViolationTypePatternViolation,
+						Message:        fmt.Sprintf("Tag value for '%s' does not match required pattern %s", original, rules.validation.PatternRules[ruleKey]),
+						TagKey:         original,
+						Value:          value,
+						Expected:       rules.validation.PatternRules[ruleKey],
+						SuggestedValue: rules.validation.PatternRuleExample(ruleKey),
+						Severity:       severityOf(rules.validation.PatternRuleSeverity(ruleKey)),
 					})
 					result.IsCompliant = false
 				}
@@ -141,31 +400,270 @@ func (v *TagValidator) ValidateTags(tags map[string]string) *ComplianceResult {
 		}
 
 		// Check allowed values
-		if allowedValues, exists := v.config.TagValidation.AllowedValues[strings.ToLower(key)]; exists {
-			valueAllowed := false
+		if allowedValues, exists := rules.allowedValuesOf(key); exists && enabled(configuration.RuleAllowedValues) {
+			matched := ""
 			for _, allowedValue := range allowedValues {
 				if strings.EqualFold(value, allowedValue) {
-					valueAllowed = true
+					matched = allowedValue
 					break
 				}
 			}
-			if !valueAllowed {
+			if matched == "" {
 				result.Violations = append(result.Violations, Violation{
-					Type:    ViolationTypeInvalidValue,
-					Message: fmt.Sprintf("Tag value for '%s' must be one of: %v", key, allowedValues),
-					TagKey:  key,
+					Type:           ViolationTypeInvalidValue,
+					Message:        fmt.Sprintf("Tag value for '%s' must be one of: %v", original, allowedValues),
+					TagKey:         original,
+					Value:          value,
+					Expected:       strings.Join(allowedValues, ", "),
+					SuggestedValue: closestAllowedValue(value, rules.currentValuesOf(key, allowedValues)),
+					Severity:       SeverityMedium,
 				})
 				result.IsCompliant = false
+			} else if replacement, deprecated := rules.deprecatedValueOf(key, matched); deprecated {
+				result.Warnings = append(result.Warnings, deprecatedValueWarning(original, value, replacement))
 			}
 		}
 	}
 
+	for i := range result.Violations {
+		result.Violations[i].DocURL = violationDocURL(result.Violations[i].Type)
+	}
+	for i := range result.Warnings {
+		result.Warnings[i].DocURL = violationDocURL(result.Warnings[i].Type)
+	}
+
+	if v.suppressions != nil {
+		result.Violations, result.SuppressedViolations = v.suppressions.Apply(resource, result.Violations, v.now())
+		result.IsCompliant = len(result.Violations) == 0
+		if !hasViolation(result.Violations, ViolationTypeMissingTags) {
+			missingTags = nil
+		}
+	}
+
+	result.Score = complianceScore(result.Violations, missingTags)
+	result.Fix = v.planFix(tags, result.Violations)
+
 	return result
 }
 
-func (v *TagValidator) checkRequiredTags(tags map[string]string) []string {
-	var missingTags []string
-	for _, requiredTag := range v.config.Global.TagCriteria.RequiredTags {
+// globalCriteriaLevel names the level of the global tag criteria
+const globalCriteriaLevel = "global"
+
+// absentTag is the expectation of the violations of tags a resource must not have
+const absentTag = "tag absent"
+
+// criteriaLevel is a set of tag criteria along with where it is configured: globally, or
+// for a resource type
+type criteriaLevel struct {
+	name     string
+	criteria configuration.TagCriteria
+}
+
+// missingTag is a required tag a resource lacks, with the severity set by the level requiring it
+type missingTag struct {
+	key      string
+	severity Severity
+}
+
+// criteriaLevels returns the global tag criteria, followed by those of the resource type when
+// it is configured
+func (v *TagValidator) criteriaLevels(resourceType string) []criteriaLevel {
+	levels := []criteriaLevel{{name: globalCriteriaLevel, criteria: v.config.Global.TagCriteria}}
+	if resourceConfig, exists := v.config.Resources[resourceType]; exists {
+		levels = append(levels, criteriaLevel{name: resourceType, criteria: resourceConfig.TagCriteria})
+	}
+	return levels
+}
+
+// disabledRules returns the built-in rule categories disabled for the resource type, in the
+// order of configuration.DisableableRules
+func (v *TagValidator) disabledRules(resourceType string) []string {
+	resourceConfig, exists := v.config.Resources[resourceType]
+	if !exists || len(resourceConfig.DisabledRules) == 0 {
+		return nil
+	}
+
+	var disabled []string
+	for _, rule := range configuration.DisableableRules() {
+		if resourceConfig.IsRuleDisabled(rule) {
+			disabled = append(disabled, rule)
+		}
+	}
+	return disabled
+}
+
+// maxTagsOf returns the maximum number of tags allowed, a resource type overriding the global limit
+func maxTagsOf(levels []criteriaLevel) int {
+	maxTags := 0
+	for _, level := range levels {
+		if level.criteria.MaxTags > 0 {
+			maxTags = level.criteria.MaxTags
+		}
+	}
+	return maxTags
+}
+
+// specificTagsOf returns the tag values every resource must carry exactly, a resource type
+// overriding the global value of a tag
+func specificTagsOf(levels []criteriaLevel) map[string]string {
+	specificTags := make(map[string]string)
+	for _, level := range levels {
+		for key, value := range level.criteria.SpecificTags {
+			specificTags[key] = value
+		}
+	}
+	return specificTags
+}
+
+// lookupTag finds a tag by key regardless of case, returning the key the resource actually
+// has along with the value
+func lookupTag(tags, originalKeys map[string]string, key string) (string, string, bool) {
+	for tagKey, value := range tags {
+		if strings.EqualFold(tagKey, key) {
+			return originalKeys[tagKey], value, true
+		}
+	}
+	return "", "", false
+}
+
+// sortedKeys returns the keys of a map in lexical order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// complianceScore weighs the violations of a resource by their severity and takes them off
+// the maximum score. Every missing required tag counts with its own severity, even though
+// the missing tags of a level are reported as a single violation.
+func complianceScore(violations []Violation, missingTags []missingTag) float64 {
+	penalty := 0.0
+	for _, violation := range violations {
+		if violation.Type != ViolationTypeMissingTags {
+			penalty += violation.Severity.Penalty()
+		}
+	}
+	for _, tag := range missingTags {
+		penalty += tag.severity.Penalty()
+	}
+
+	return math.Max(0, MaxComplianceScore-penalty)
+}
+
+// missingTagsSeverity returns the most serious severity among the missing required tags
+func missingTagsSeverity(missingTags []missingTag) Severity {
+	severities := make(map[Severity]bool, len(missingTags))
+	for _, tag := range missingTags {
+		severities[tag.severity] = true
+	}
+
+	for _, severity := range severityRanking {
+		if severities[severity] {
+			return severity
+		}
+	}
+
+	return SeverityMedium
+}
+
+// severityOf converts the severity of a configured rule, defaulting to medium when unset
+func severityOf(severity configuration.Severity) Severity {
+	return Severity(severity.OrDefault())
+}
+
+// normalizeTags applies the configured tag normalization, returning the tags keyed by their
+// canonical key along with the original key of each canonical key. When several keys of a
+// resource normalize to the same canonical key, the one already spelled canonically wins,
+// otherwise the first in lexical order.
+func (v *TagValidator) normalizeTags(tags map[string]string) (map[string]string, map[string]string) {
+	normalization := v.config.TagValidation.TagNormalization
+
+	normalize := func(key string) string {
+		if normalization.LowercaseKeys {
+			key = strings.ToLower(key)
+		}
+		return key
+	}
+
+	aliases := make(map[string]string, len(normalization.Aliases))
+	for alias, canonical := range normalization.Aliases {
+		aliases[normalize(alias)] = normalize(canonical)
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	normalized := make(map[string]string, len(tags))
+	originalKeys := make(map[string]string, len(tags))
+	for _, key := range keys {
+		canonical := normalize(key)
+		if target, isAlias := aliases[canonical]; isAlias {
+			canonical = target
+		}
+
+		if existing, seen := originalKeys[canonical]; seen && (existing == canonical || key != canonical) {
+			continue
+		}
+
+		normalized[canonical] = tags[key]
+		originalKeys[canonical] = key
+	}
+
+	return normalized, originalKeys
+}
+
+// checkRequiredTags returns the required tags missing from the tags, for each criteria level
+// requiring all of its required tags, see checkMinimumRequiredTags for the others. A tag
+// required at several levels is only reported at the first one.
+func checkRequiredTags(levels []criteriaLevel, tags map[string]string) [][]missingTag {
+	missingTags := make([][]missingTag, len(levels))
+	checked := make(map[string]bool)
+	for i, level := range levels {
+		if level.criteria.RequiresAnyOf() {
+			continue
+		}
+		for _, requiredTag := range level.criteria.RequiredTags {
+			if checked[strings.ToLower(requiredTag)] {
+				continue
+			}
+			checked[strings.ToLower(requiredTag)] = true
+
+			found := false
+			for tagKey := range tags {
+				if strings.EqualFold(tagKey, requiredTag) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				missingTags[i] = append(missingTags[i], missingTag{
+					key:      requiredTag,
+					severity: severityOf(level.criteria.RequiredTagSeverity(requiredTag)),
+				})
+			}
+		}
+	}
+	return missingTags
+}
+
+// checkMinimumRequiredTags returns how many required tags of criteria requiring any N of them
+// are present and, when fewer than N are, the missing tags making up the shortfall: the least
+// severe ones, as the resource is compliant once any of them is added. The shortfall is
+// empty when the criteria require all of their tags, which checkRequiredTags checks.
+func checkMinimumRequiredTags(criteria configuration.TagCriteria, tags map[string]string) (int, []missingTag) {
+	if !criteria.RequiresAnyOf() {
+		return 0, nil
+	}
+
+	present := 0
+	var missing []missingTag
+	for _, requiredTag := range criteria.RequiredTags {
 		found := false
 		for tagKey := range tags {
 			if strings.EqualFold(tagKey, requiredTag) {
@@ -173,11 +671,304 @@ func (v *TagValidator) checkRequiredTags(tags map[string]string) []string {
 				break
 			}
 		}
-		if !found {
-			missingTags = append(missingTags, requiredTag)
+		if found {
+			present++
+			continue
 		}
+		missing = append(missing, missingTag{
+			key:      requiredTag,
+			severity: severityOf(criteria.RequiredTagSeverity(requiredTag)),
+		})
 	}
-	return missingTags
+
+	needed := criteria.RequiredTagsNeeded()
+	if present >= needed {
+		return present, nil
+	}
+
+	slices.SortStableFunc(missing, func(a, b missingTag) int {
+		return slices.Index(severityRanking, b.severity) - slices.Index(severityRanking, a.severity)
+	})
+	return present, missing[:needed-present]
+}
+
+// minimumRequiredTagsMessage describes the shortfall of required tags of criteria requiring
+// any N of them, naming the resource type that requires them
+func minimumRequiredTagsMessage(level string, present int, criteria configuration.TagCriteria) string {
+	if level == globalCriteriaLevel {
+		return fmt.Sprintf("Only %d of required %d tags from %v present", present, criteria.RequiredTagsNeeded(), criteria.RequiredTags)
+	}
+	return fmt.Sprintf("Only %d of required %d %s tags from %v present", present, criteria.RequiredTagsNeeded(), level, criteria.RequiredTags)
+}
+
+// missingTagsMessage describes the missing required tags of a criteria level, naming the
+// resource type that requires them
+func missingTagsMessage(level string, missingTags []missingTag) string {
+	keys := missingTagKeys(missingTags)
+	if level == globalCriteriaLevel {
+		return fmt.Sprintf("Missing required tags: %v", keys)
+	}
+	return fmt.Sprintf("Missing required %s tags: %v", level, keys)
+}
+
+// missingTagKeys returns the keys of the missing required tags
+func missingTagKeys(missingTags []missingTag) []string {
+	keys := make([]string, 0, len(missingTags))
+	for _, tag := range missingTags {
+		keys = append(keys, tag.key)
+	}
+	return keys
+}
+
+// achievedComplianceLevel returns the strictest configured compliance level whose effective
+// requirements, including those inherited through extends, are met by the tags. It is empty
+// when no level is met.
+func (v *TagValidator) achievedComplianceLevel(tags map[string]string) ComplianceLevel {
+	for _, level := range complianceLevelRanking {
+		if _, exists := v.config.ComplianceLevels[string(level)]; !exists {
+			continue
+		}
+
+		// Levels whose inheritance cannot be resolved are reported by the configuration validation
+		requirements, err := v.config.ResolveComplianceLevel(string(level))
+		if err != nil {
+			continue
+		}
+
+		if meetsComplianceLevel(tags, requirements) {
+			return level
+		}
+	}
+
+	return ""
+}
+
+// meetsComplianceLevel reports whether the tags carry every required tag of a level and the
+// values of its specific tags, keys and values being compared case-insensitively
+func meetsComplianceLevel(tags map[string]string, requirements configuration.ComplianceLevel) bool {
+	valueOf := func(key string) (string, bool) {
+		for tagKey, value := range tags {
+			if strings.EqualFold(tagKey, key) {
+				return value, true
+			}
+		}
+		return "", false
+	}
+
+	for _, requiredTag := range requirements.RequiredTags {
+		if _, found := valueOf(requiredTag); !found {
+			return false
+		}
+	}
+
+	for key, expected := range requirements.SpecificTags {
+		value, found := valueOf(key)
+		if !found || !strings.EqualFold(value, expected) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// forbiddenTagsPresent returns, sorted, the tag keys forbidden at any criteria level. Keys are
+// matched exactly unless a level ignores case.
+func forbiddenTagsPresent(levels []criteriaLevel, tags map[string]string) []string {
+	var forbidden []string
+	ignoreCase := false
+	for _, level := range levels {
+		forbidden = append(forbidden, level.criteria.ForbiddenTags...)
+		ignoreCase = ignoreCase || level.criteria.ForbiddenTagsIgnoreCase
+	}
+
+	var present []string
+	for key := range tags {
+		for _, forbiddenTag := range forbidden {
+			if key == forbiddenTag || (ignoreCase && strings.EqualFold(key, forbiddenTag)) {
+				present = append(present, key)
+				break
+			}
+		}
+	}
+	sort.Strings(present)
+
+	return present
+}
+
+// caseDuplicateKeys returns the groups of tag keys that are equal ignoring case, each sorted,
+// ordered by their first key
+func caseDuplicateKeys(tags map[string]string) [][]string {
+	groups := make(map[string][]string)
+	for key := range tags {
+		folded := strings.ToLower(key)
+		groups[folded] = append(groups[folded], key)
+	}
+
+	var duplicates [][]string
+	for _, keys := range groups {
+		if len(keys) > 1 {
+			sort.Strings(keys)
+			duplicates = append(duplicates, keys)
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i][0] < duplicates[j][0] })
+
+	return duplicates
+}
+
+// preferredKeySpelling returns the only key among keys equal ignoring case that follows the
+// configured key format rules and case rules. It is empty when no rule tells the keys apart.
+func (r tagRules) preferredKeySpelling(keys []string) string {
+	preferred := ""
+	for _, key := range keys {
+		ruled, follows := r.followsKeyCaseRules(key)
+		if !ruled {
+			return ""
+		}
+		if !follows {
+			continue
+		}
+		if preferred != "" {
+			return ""
+		}
+		preferred = key
+	}
+	return preferred
+}
+
+// followsKeyCaseRules reports whether any key format rule or case rule applies to a tag key,
+// and whether the key follows all of them. Case rules expect the key in lowercase.
+func (r tagRules) followsKeyCaseRules(key string) (bool, bool) {
+	ruled, follows := false, true
+	for i := range r.validation.KeyFormatRules {
+		pattern := r.patterns.KeyFormatRule(i)
+		if pattern == nil {
+			continue
+		}
+		ruled = true
+		follows = follows && pattern.MatchString(key)
+	}
+	for ruleKey := range r.validation.CaseRules {
+		if strings.EqualFold(key, ruleKey) {
+			ruled = true
+			follows = follows && key == strings.ToLower(ruleKey)
+		}
+	}
+	return ruled, follows
+}
+
+// keyValidationViolations checks the tag keys against the key validation rules of the
+// configuration, in key order. Keys with the reserved aws: prefix are left out, as AWS
+// creates them.
+func (v *TagValidator) keyValidationViolations(tags map[string]string) []Violation {
+	keyValidation := v.config.TagValidation.KeyValidation
+
+	var violations []Violation
+	for _, key := range sortedKeys(tags) {
+		if hasAWSReservedPrefix(key) {
+			continue
+		}
+
+		for _, keyErr := range keyValidation.ValidateTagKey(key) {
+			violation := Violation{
+				TagKey:   key,
+				Severity: SeverityMedium,
+			}
+			switch keyErr.RuleKind {
+			case configuration.RuleKindKeyPrefix:
+				violation.Type = ViolationTypeInvalidKeyPrefix
+				violation.Message = fmt.Sprintf("Tag key '%s' must start with one of: %s", key, strings.Join(keyErr.Allowed, ", "))
+				violation.Expected = fmt.Sprintf("key prefix %s", strings.Join(keyErr.Allowed, ", "))
+			case configuration.RuleKindKeySuffix:
+				violation.Type = ViolationTypeInvalidKeySuffix
+				violation.Message = fmt.Sprintf("Tag key '%s' must end with one of: %s", key, strings.Join(keyErr.Allowed, ", "))
+				violation.Expected = fmt.Sprintf("key suffix %s", strings.Join(keyErr.Allowed, ", "))
+			default:
+				violation.Type = ViolationTypeKeyTooLong
+				violation.Message = fmt.Sprintf("Tag key '%s' exceeds the maximum length of %d characters", key, keyErr.MaxLength)
+				violation.Expected = fmt.Sprintf("key of at most %d characters", keyErr.MaxLength)
+			}
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// allowedValuesOf returns the allowed values of a tag, matching the configured tag names
+// regardless of case like the case and pattern rules
+func (r tagRules) allowedValuesOf(key string) ([]string, bool) {
+	for ruleKey, allowedValues := range r.validation.AllowedValues {
+		if strings.EqualFold(key, ruleKey) {
+			return allowedValues, true
+		}
+	}
+	return nil, false
+}
+
+// deprecatedValueOf returns the replacement of an allowed value of a tag when the value is
+// deprecated, matching the configured tag names regardless of case like allowedValuesOf
+func (r tagRules) deprecatedValueOf(key, allowedValue string) (string, bool) {
+	for ruleKey := range r.validation.DeprecatedValues {
+		if strings.EqualFold(key, ruleKey) {
+			return r.validation.DeprecatedValue(ruleKey, allowedValue)
+		}
+	}
+	return "", false
+}
+
+// currentValuesOf returns the allowed values of a tag that are not deprecated, the ones
+// suggested in place of a value that is not allowed. All the allowed values are returned
+// when every one of them is deprecated.
+func (r tagRules) currentValuesOf(key string, allowedValues []string) []string {
+	current := make([]string, 0, len(allowedValues))
+	for _, allowedValue := range allowedValues {
+		if _, deprecated := r.deprecatedValueOf(key, allowedValue); !deprecated {
+			current = append(current, allowedValue)
+		}
+	}
+	if len(current) == 0 {
+		return allowedValues
+	}
+	return current
+}
+
+// deprecatedValueWarning returns the warning of a tag carrying a deprecated allowed value
+func deprecatedValueWarning(key, value, replacement string) Violation {
+	warning := Violation{
+		Type:     ViolationTypeDeprecatedValue,
+		Message:  fmt.Sprintf("Tag value '%s' for '%s' is deprecated", value, key),
+		TagKey:   key,
+		Value:    value,
+		Severity: SeverityLow,
+	}
+	if replacement != "" {
+		warning.Message = fmt.Sprintf("%s, use '%s' instead", warning.Message, replacement)
+		warning.Expected = replacement
+		warning.SuggestedValue = replacement
+	}
+	return warning
+}
+
+// caseFix returns the value with its case fixed as the suggestion of a case violation, or an
+// empty suggestion when the fixed value would still break the allowed values or the pattern
+// rule of the tag
+func (r tagRules) caseFix(key, fixed string) string {
+	if allowedValues, exists := r.allowedValuesOf(key); exists {
+		if !slices.ContainsFunc(allowedValues, func(allowed string) bool { return strings.EqualFold(fixed, allowed) }) {
+			return ""
+		}
+	}
+
+	for ruleKey := range r.validation.PatternRules {
+		if !strings.EqualFold(key, ruleKey) {
+			continue
+		}
+		if pattern, exists := r.patterns.PatternRule(ruleKey); exists && !pattern.MatchString(fixed) {
+			return ""
+		}
+	}
+
+	return fixed
 }
 
 func (v *TagValidator) isProhibitedTag(tagKey string) bool {
@@ -2,10 +2,13 @@
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func createTestConfig() *configuration.TaggyScanConfig {
@@ -122,6 +125,156 @@ func TestValidateTags_AllowedValues(t *testing.T) {
 	}
 }
 
+func TestValidateTags_AllowedValuesOfCapitalizedTagName(t *testing.T) {
+	config := createTestConfig()
+	config.TagValidation.AllowedValues = map[string][]string{
+		"Environment": {"production", "staging"},
+	}
+	validator := NewTagValidator(config)
+
+	result := validator.ValidateTags(map[string]string{
+		"environment": "sandbox",
+		"owner":       "team@company.com",
+	})
+
+	assert.False(t, result.IsCompliant)
+	require.Len(t, result.Violations, 1)
+	assert.Equal(t, ViolationTypeInvalidValue, result.Violations[0].Type)
+}
+
+func TestValidateTags_DeprecatedValues(t *testing.T) {
+	config := createTestConfig()
+	config.TagValidation.AllowedValues = map[string][]string{
+		"Environment": {"production", "staging", "qa", "test"},
+	}
+	config.TagValidation.DeprecatedValues = map[string]map[string]string{
+		"Environment": {"qa": "staging", "test": ""},
+	}
+	validator := NewTagValidator(config)
+
+	result := validator.ValidateTags(map[string]string{"environment": "qa", "owner": "team@company.com"})
+	assert.True(t, result.IsCompliant, "deprecated values are still allowed")
+	assert.Empty(t, result.Violations)
+	assert.Equal(t, MaxComplianceScore, result.Score)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, ViolationTypeDeprecatedValue, result.Warnings[0].Type)
+	assert.Equal(t, SeverityLow, result.Warnings[0].Severity)
+	assert.Equal(t, "staging", result.Warnings[0].SuggestedValue)
+	assert.Equal(t, "Tag value 'qa' for 'environment' is deprecated, use 'staging' instead", result.Warnings[0].Message)
+	assert.NotEmpty(t, result.Warnings[0].DocURL)
+
+	result = validator.ValidateTags(map[string]string{"environment": "test", "owner": "team@company.com"})
+	require.Len(t, result.Warnings, 1)
+	assert.Empty(t, result.Warnings[0].SuggestedValue)
+	assert.Equal(t, "Tag value 'test' for 'environment' is deprecated", result.Warnings[0].Message)
+
+	result = validator.ValidateTags(map[string]string{"environment": "production", "owner": "team@company.com"})
+	assert.Empty(t, result.Warnings)
+
+	// Values that are not allowed are never fixed with a deprecated value
+	result = validator.ValidateTags(map[string]string{"environment": "qa1", "owner": "team@company.com"})
+	assert.False(t, result.IsCompliant)
+	require.Len(t, result.Violations, 1)
+	assert.Equal(t, ViolationTypeInvalidValue, result.Violations[0].Type)
+	assert.NotEqual(t, "qa", result.Violations[0].SuggestedValue)
+	assert.Empty(t, result.Warnings)
+
+	summary := GenerateSummary([]*ComplianceResult{
+		validator.ValidateTags(map[string]string{"environment": "qa", "owner": "team@company.com"}),
+		validator.ValidateTags(map[string]string{"environment": "production", "owner": "team@company.com"}),
+	})
+	assert.Equal(t, 2, summary.CompliantResources)
+	assert.Equal(t, 1, summary.Warnings)
+}
+
+func TestValidateResource_TagValidationOverride(t *testing.T) {
+	testCases := []struct {
+		name  string
+		mode  configuration.TagValidationOverrideMode
+		value string
+		want  bool
+	}{
+		{name: "Add Keeps Global Value", mode: configuration.TagValidationOverrideAdd, value: "production", want: true},
+		{name: "Add Allows Added Value", mode: configuration.TagValidationOverrideAdd, value: "scratch", want: true},
+		{name: "Replace Drops Global Value", mode: configuration.TagValidationOverrideReplace, value: "production", want: false},
+		{name: "Replace Allows Replacing Value", mode: configuration.TagValidationOverrideReplace, value: "scratch", want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := createTestConfig()
+			config.Resources = map[string]configuration.ResourceConfig{
+				"s3": {
+					Enabled: true,
+					TagValidation: &configuration.TagValidationOverride{
+						Mode:          tc.mode,
+						AllowedValues: map[string][]string{"environment": {"scratch"}},
+					},
+				},
+			}
+			validator := NewTagValidator(config)
+			tags := map[string]string{"environment": tc.value, "owner": "team@company.com"}
+
+			result := validator.ValidateResource(ResourceRef{ID: "bucket", Type: "s3"}, tags)
+			assert.Equal(t, tc.want, result.IsCompliant, "s3: %v", result.Violations)
+
+			// Resources of other types keep the global rules
+			result = validator.ValidateResource(ResourceRef{ID: "instance", Type: "ec2"}, tags)
+			assert.Equal(t, tc.value != "scratch", result.IsCompliant, "ec2: %v", result.Violations)
+		})
+	}
+
+	t.Run("Pattern Rules", func(t *testing.T) {
+		config := createTestConfig()
+		config.Resources = map[string]configuration.ResourceConfig{
+			"s3": {
+				Enabled: true,
+				TagValidation: &configuration.TagValidationOverride{
+					PatternRules:          map[string]string{"owner": `^[a-z0-9._%+-]+@sandbox\.company\.com$`},
+					PatternRuleSeverities: map[string]configuration.Severity{"owner": configuration.SeverityLow},
+				},
+			},
+		}
+		validator := NewTagValidator(config)
+
+		result := validator.ValidateResource(ResourceRef{ID: "bucket", Type: "s3"}, map[string]string{"environment": "staging", "owner": "team@sandbox.company.com"})
+		assert.True(t, result.IsCompliant, "%v", result.Violations)
+
+		result = validator.ValidateResource(ResourceRef{ID: "bucket", Type: "s3"}, map[string]string{"environment": "staging", "owner": "team@company.com"})
+		require.Len(t, result.Violations, 1)
+		assert.Equal(t, ViolationTypePatternViolation, result.Violations[0].Type)
+		assert.Equal(t, SeverityLow, result.Violations[0].Severity)
+
+		result = validator.ValidateResource(ResourceRef{ID: "instance", Type: "ec2"}, map[string]string{"environment": "staging", "owner": "team@company.com"})
+		assert.True(t, result.IsCompliant, "%v", result.Violations)
+	})
+
+	// Tag keys are matched regardless of case, an override of Owner replacing the global rules
+	// of owner instead of being checked along with them
+	t.Run("Mixed Case Tags", func(t *testing.T) {
+		config := createTestConfig()
+		config.Resources = map[string]configuration.ResourceConfig{
+			"s3": {
+				Enabled: true,
+				TagValidation: &configuration.TagValidationOverride{
+					Mode:          configuration.TagValidationOverrideReplace,
+					AllowedValues: map[string][]string{"Environment": {"scratch"}},
+					PatternRules:  map[string]string{"Owner": `^[a-z0-9._%+-]+@sandbox\.company\.com$`},
+				},
+			},
+		}
+		validator := NewTagValidator(config)
+
+		result := validator.ValidateResource(ResourceRef{ID: "bucket", Type: "s3"}, map[string]string{"environment": "scratch", "owner": "team@sandbox.company.com"})
+		assert.True(t, result.IsCompliant, "%v", result.Violations)
+
+		for range 10 {
+			result = validator.ValidateResource(ResourceRef{ID: "bucket", Type: "s3"}, map[string]string{"environment": "production", "owner": "team@sandbox.company.com"})
+			assert.False(t, result.IsCompliant, "the global allowed values are replaced")
+		}
+	})
+}
+
 func TestValidateTags_CaseRules(t *testing.T) {
 	testCases := []struct {
 		name               string
@@ -329,6 +482,385 @@ func TestValidateTags_ProhibitedTags(t *testing.T) {
 	}
 }
 
+func TestValidateTags_ForbiddenTags(t *testing.T) {
+	testCases := []struct {
+		name         string
+		resourceType string
+		ignoreCase   bool
+		tags         map[string]string
+		expectedKeys []string
+	}{
+		{
+			name:         "Global forbidden tag",
+			tags:         map[string]string{"donotuse": "true"},
+			expectedKeys: []string{"donotuse"},
+		},
+		{
+			name:         "Resource forbidden tags add to global ones",
+			resourceType: "s3",
+			tags:         map[string]string{"donotuse": "true", "scratch": "true"},
+			expectedKeys: []string{"donotuse", "scratch"},
+		},
+		{
+			name:         "Resource forbidden tags only apply to their type",
+			resourceType: "ec2",
+			tags:         map[string]string{"scratch": "true"},
+		},
+		{
+			name: "Keys are matched exactly",
+			tags: map[string]string{"DoNotUse": "true"},
+		},
+		{
+			name:         "Keys are matched ignoring case",
+			ignoreCase:   true,
+			tags:         map[string]string{"DoNotUse": "true"},
+			expectedKeys: []string{"DoNotUse"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := createTestConfig()
+			config.Global.TagCriteria.ForbiddenTags = []string{"donotuse"}
+			config.Global.TagCriteria.ForbiddenTagsIgnoreCase = tc.ignoreCase
+			config.Resources = map[string]configuration.ResourceConfig{
+				"s3": {TagCriteria: configuration.TagCriteria{ForbiddenTags: []string{"scratch"}}},
+			}
+
+			tags := map[string]string{"environment": "production", "owner": "team@company.com"}
+			for key, value := range tc.tags {
+				tags[key] = value
+			}
+
+			result := NewTagValidator(config).ValidateResource(ResourceRef{ID: "bucket", Type: tc.resourceType}, tags)
+
+			var forbiddenKeys []string
+			for _, violation := range result.Violations {
+				if violation.Type == ViolationTypeForbiddenTag {
+					forbiddenKeys = append(forbiddenKeys, violation.TagKey)
+				}
+			}
+			assert.Equal(t, tc.expectedKeys, forbiddenKeys)
+			if len(tc.expectedKeys) > 0 {
+				assert.False(t, result.IsCompliant)
+			}
+		})
+	}
+}
+
+func TestValidateResource_ResourceTagCriteria(t *testing.T) {
+	config := createTestConfig()
+	config.Resources = map[string]configuration.ResourceConfig{
+		"s3": {
+			TagCriteria: configuration.TagCriteria{
+				RequiredTags:          []string{"owner", "dataclassification", "backuppolicy"},
+				RequiredTagSeverities: map[string]configuration.Severity{"dataclassification": configuration.SeverityCritical},
+				MaxTags:               5,
+			},
+		},
+	}
+	validator := NewTagValidator(config)
+
+	tags := map[string]string{
+		"environment": "production",
+		"owner":       "team@company.com",
+	}
+
+	bucket := validator.ValidateResource(ResourceRef{ID: "bucket", Type: "s3"}, tags)
+	assert.False(t, bucket.IsCompliant)
+	assert.Equal(t, "s3", bucket.ResourceType)
+	assert.Equal(t, []Violation{
+		{
+			Type:     ViolationTypeMissingTags,
+			Message:  "Missing required s3 tags: [dataclassification backuppolicy]",
+			Expected: "dataclassification, backuppolicy",
+			Severity: SeverityCritical,
+			DocURL:   violationDocURL(ViolationTypeMissingTags),
+		},
+	}, bucket.Violations)
+	assert.Equal(t, MaxComplianceScore-SeverityCritical.Penalty()-SeverityMedium.Penalty(), bucket.Score)
+
+	instance := validator.ValidateResource(ResourceRef{ID: "instance", Type: "ec2"}, tags)
+	assert.True(t, instance.IsCompliant, fmt.Sprintf("violations: %v", instance.Violations))
+	assert.Equal(t, MaxComplianceScore, instance.Score)
+
+	// Global requirements are reported apart from those of the resource type
+	untagged := validator.ValidateResource(ResourceRef{ID: "bucket", Type: "s3"}, map[string]string{})
+	var messages []string
+	for _, violation := range untagged.Violations {
+		messages = append(messages, violation.Message)
+	}
+	assert.Equal(t, []string{
+		"Missing required tags: [environment owner]",
+		"Missing required s3 tags: [dataclassification backuppolicy]",
+	}, messages)
+}
+
+func TestValidateResource_DisabledRules(t *testing.T) {
+	config := createTestConfig()
+	config.Resources = map[string]configuration.ResourceConfig{
+		"s3": {
+			DisabledRules: []string{
+				configuration.RuleProhibitedTags,
+				configuration.RuleCaseRules,
+				configuration.RuleAllowedValues,
+				configuration.RuleRequiredTags,
+			},
+		},
+	}
+	validator := NewTagValidator(config)
+
+	tags := map[string]string{
+		"environment": "PROD",
+		"owner":       "team@company.com",
+		"temp":        "true",
+	}
+
+	bucket := validator.ValidateResource(ResourceRef{ID: "bucket", Type: "s3"}, tags)
+	assert.True(t, bucket.IsCompliant, fmt.Sprintf("violations: %v", bucket.Violations))
+	assert.Equal(t, MaxComplianceScore, bucket.Score)
+	assert.Equal(t, []string{
+		configuration.RuleRequiredTags,
+		configuration.RuleAllowedValues,
+		configuration.RuleCaseRules,
+		configuration.RuleProhibitedTags,
+	}, bucket.SkippedRules, "skipped rules are listed in their canonical order")
+
+	untagged := validator.ValidateResource(ResourceRef{ID: "bucket", Type: "s3"}, map[string]string{})
+	assert.True(t, untagged.IsCompliant, "required tags are not checked when disabled")
+
+	instance := validator.ValidateResource(ResourceRef{ID: "instance", Type: "ec2"}, tags)
+	assert.False(t, instance.IsCompliant)
+	assert.Empty(t, instance.SkippedRules)
+	var types []ViolationType
+	for _, violation := range instance.Violations {
+		types = append(types, violation.Type)
+	}
+	assert.ElementsMatch(t, []ViolationType{ViolationTypeInvalidValue, ViolationTypeCaseViolation, ViolationTypeProhibitedTag}, types)
+}
+
+func TestValidateTags_MinimumRequiredTags(t *testing.T) {
+	requiredTags := []string{"team", "product", "costcenter"}
+	testCases := []struct {
+		name              string
+		minimum           int
+		tags              map[string]string
+		expectedMessage   string
+		expectedExpected  string
+		expectedSeverity  Severity
+		expectedScoreLoss float64
+	}{
+		{
+			name:              "Zero Requires All",
+			minimum:           0,
+			tags:              map[string]string{"team": "platform", "product": "billing"},
+			expectedMessage:   "Missing required tags: [costcenter]",
+			expectedExpected:  "costcenter",
+			expectedSeverity:  SeverityLow,
+			expectedScoreLoss: SeverityLow.Penalty(),
+		},
+		{
+			name:              "Equal Requires All",
+			minimum:           3,
+			tags:              map[string]string{"team": "platform", "product": "billing"},
+			expectedMessage:   "Missing required tags: [costcenter]",
+			expectedExpected:  "costcenter",
+			expectedSeverity:  SeverityLow,
+			expectedScoreLoss: SeverityLow.Penalty(),
+		},
+		{
+			name:    "Equal With All Present",
+			minimum: 3,
+			tags:    map[string]string{"team": "platform", "product": "billing", "costcenter": "cc-1"},
+		},
+		{
+			name:    "Lower With Exactly Enough Present",
+			minimum: 2,
+			tags:    map[string]string{"team": "platform", "costcenter": "cc-1"},
+		},
+		{
+			name:    "Lower With More Than Enough Present",
+			minimum: 1,
+			tags:    map[string]string{"team": "platform", "product": "billing", "costcenter": "cc-1"},
+		},
+		{
+			name:    "Lower Matches Keys Regardless Of Case",
+			minimum: 2,
+			tags:    map[string]string{"Team": "platform", "PRODUCT": "billing"},
+		},
+		{
+			name:              "Lower With Too Few Present",
+			minimum:           2,
+			tags:              map[string]string{"product": "billing"},
+			expectedMessage:   "Only 1 of required 2 tags from [team product costcenter] present",
+			expectedExpected:  "at least 2 of team, product, costcenter",
+			expectedSeverity:  SeverityLow,
+			expectedScoreLoss: SeverityLow.Penalty(),
+		},
+		{
+			name:              "Lower With None Present",
+			minimum:           2,
+			tags:              map[string]string{},
+			expectedMessage:   "Only 0 of required 2 tags from [team product costcenter] present",
+			expectedExpected:  "at least 2 of team, product, costcenter",
+			expectedSeverity:  SeverityMedium,
+			expectedScoreLoss: SeverityLow.Penalty() + SeverityMedium.Penalty(),
+		},
+		{
+			name:              "Greater Requires All",
+			minimum:           4,
+			tags:              map[string]string{"team": "platform", "product": "billing"},
+			expectedMessage:   "Missing required tags: [costcenter]",
+			expectedExpected:  "costcenter",
+			expectedSeverity:  SeverityLow,
+			expectedScoreLoss: SeverityLow.Penalty(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := createTestConfig()
+			config.Global.TagCriteria = configuration.TagCriteria{
+				MinimumRequiredTags: tc.minimum,
+				RequiredTags:        requiredTags,
+				// The shortfall is made of the least severe missing tags
+				RequiredTagSeverities: map[string]configuration.Severity{
+					"team":       configuration.SeverityCritical,
+					"costcenter": configuration.SeverityLow,
+				},
+			}
+			config.TagValidation.KeyFormatRules = nil
+			result := NewTagValidator(config).ValidateTags(tc.tags)
+
+			if tc.expectedMessage == "" {
+				assert.True(t, result.IsCompliant, fmt.Sprintf("violations: %v", result.Violations))
+				assert.Equal(t, MaxComplianceScore, result.Score)
+				return
+			}
+
+			assert.False(t, result.IsCompliant)
+			assert.Equal(t, []Violation{
+				{
+					Type:     ViolationTypeMissingTags,
+					Message:  tc.expectedMessage,
+					Expected: tc.expectedExpected,
+					Severity: tc.expectedSeverity,
+					DocURL:   violationDocURL(ViolationTypeMissingTags),
+				},
+			}, result.Violations)
+			assert.Equal(t, MaxComplianceScore-tc.expectedScoreLoss, result.Score)
+		})
+	}
+
+	t.Run("Resource Type", func(t *testing.T) {
+		config := createTestConfig()
+		config.Resources = map[string]configuration.ResourceConfig{
+			"s3": {
+				TagCriteria: configuration.TagCriteria{
+					MinimumRequiredTags: 2,
+					RequiredTags:        requiredTags,
+				},
+			},
+		}
+		validator := NewTagValidator(config)
+
+		tags := map[string]string{"environment": "production", "owner": "team@company.com", "team": "platform"}
+		bucket := validator.ValidateResource(ResourceRef{ID: "bucket", Type: "s3"}, tags)
+		require.Len(t, bucket.Violations, 1)
+		assert.Equal(t, "Only 1 of required 2 s3 tags from [team product costcenter] present", bucket.Violations[0].Message)
+
+		// The any-of requirement of the resource type leaves the global required tags required
+		tags = map[string]string{"team": "platform", "product": "billing"}
+		bucket = validator.ValidateResource(ResourceRef{ID: "bucket", Type: "s3"}, tags)
+		require.Len(t, bucket.Violations, 1)
+		assert.Equal(t, "Missing required tags: [environment owner]", bucket.Violations[0].Message)
+	})
+}
+
+func TestValidateResource_TagCountAndSpecificTags(t *testing.T) {
+	config := createTestConfig()
+	config.Global.TagCriteria.MaxTags = 4
+	config.Global.TagCriteria.SpecificTags = map[string]string{"managed-by": "terraform", "team": "platform"}
+	config.Resources = map[string]configuration.ResourceConfig{
+		"s3": {
+			TagCriteria: configuration.TagCriteria{
+				MaxTags:      3,
+				SpecificTags: map[string]string{"team": "storage"},
+			},
+		},
+	}
+	validator := NewTagValidator(config)
+
+	tags := map[string]string{
+		"environment": "production",
+		"owner":       "team@company.com",
+		"managed-by":  "terraform",
+		"team":        "storage",
+	}
+
+	testCases := []struct {
+		name               string
+		resourceType       string
+		tags               map[string]string
+		expectedViolations []Violation
+	}{
+		{
+			name:         "Resource criteria override global ones",
+			resourceType: "s3",
+			tags:         tags,
+			expectedViolations: []Violation{
+				{
+					Type:     ViolationTypeTooManyTags,
+					Message:  "Number of tags (4) exceeds maximum allowed (3)",
+					Expected: "at most 3 tags",
+					Severity: SeverityMedium,
+				},
+			},
+		},
+		{
+			name:         "Global specific tag value differs",
+			resourceType: "ec2",
+			tags:         tags,
+			expectedViolations: []Violation{
+				{
+					Type:     ViolationTypeSpecificTagMismatch,
+					Message:  "Tag value for 'team' must be 'platform'",
+					TagKey:   "team",
+					Value:    "storage",
+					Expected: "platform",
+					Severity: SeverityMedium,
+				},
+			},
+		},
+		{
+			name:         "Specific tag missing",
+			resourceType: "ec2",
+			tags: map[string]string{
+				"environment": "production",
+				"owner":       "team@company.com",
+				"team":        "platform",
+			},
+			expectedViolations: []Violation{
+				{
+					Type:     ViolationTypeSpecificTagMismatch,
+					Message:  "Tag 'managed-by' is required with value 'terraform'",
+					TagKey:   "managed-by",
+					Expected: "terraform",
+					Severity: SeverityMedium,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := validator.ValidateResource(ResourceRef{ID: "resource", Type: tc.resourceType}, tc.tags)
+			assert.False(t, result.IsCompliant)
+			assert.ElementsMatch(t, tc.expectedViolations, result.Violations)
+		})
+	}
+}
+
 func TestValidateTags_MultipleViolationsPerTag(t *testing.T) {
 	config := createTestConfig()
 	validator := NewTagValidator(config)
@@ -367,3 +899,691 @@ func TestValidateTags_MultipleViolationsPerTag(t *testing.T) {
 	}
 	assert.GreaterOrEqual(t, totalViolations, 5, "Expected at least 5 total violations")
 }
+
+func TestValidateTags_TagNormalization(t *testing.T) {
+	testCases := []struct {
+		name               string
+		lowercaseKeys      bool
+		tags               map[string]string
+		expectedResult     bool
+		expectedViolations []Violation
+	}{
+		{
+			name: "Alias satisfies required tag",
+			tags: map[string]string{
+				"ENV":   "production",
+				"owner": "team@company.com",
+			},
+			expectedResult: true,
+		},
+		{
+			name: "Violation references the original key",
+			tags: map[string]string{
+				"ENV":   "prod",
+				"owner": "team@company.com",
+			},
+			expectedResult: false,
+			expectedViolations: []Violation{
+				{
+					Type:     ViolationTypeInvalidValue,
+					Message:  "Tag value for 'ENV' must be one of: [production staging development]",
+					TagKey:   "ENV",
+					Value:    "prod",
+					Expected: "production, staging, development",
+					Severity: SeverityMedium,
+
+					SuggestedValue: "production",
+					DocURL:         violationDocURL(ViolationTypeInvalidValue),
+				},
+			},
+		},
+		{
+			name:          "Lowercased keys satisfy rules",
+			lowercaseKeys: true,
+			tags: map[string]string{
+				"Environment": "staging",
+				"OWNER":       "team@company.com",
+			},
+			expectedResult: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := createTestConfig()
+			config.TagValidation.TagNormalization = configuration.TagNormalization{
+				Aliases:       map[string]string{"ENV": "environment"},
+				LowercaseKeys: tc.lowercaseKeys,
+			}
+
+			result := NewTagValidator(config).ValidateTags(tc.tags)
+			assert.Equal(t, tc.expectedResult, result.IsCompliant, fmt.Sprintf("violations: %v", result.Violations))
+			assert.ElementsMatch(t, tc.expectedViolations, result.Violations)
+			assert.Equal(t, tc.tags, result.ResourceTags)
+		})
+	}
+}
+
+func TestValidateTags_ComplianceLevel(t *testing.T) {
+	config := createTestConfig()
+	config.ComplianceLevels = map[string]configuration.ComplianceLevel{
+		"low": {
+			RequiredTags: []string{"owner"},
+		},
+		"standard": {
+			RequiredTags: []string{"environment"},
+			SpecificTags: map[string]string{"managed-by": "terraform"},
+			Extends:      "low",
+		},
+		"high": {
+			RequiredTags: []string{"data-classification"},
+			Extends:      "standard",
+		},
+	}
+
+	base := map[string]string{
+		"environment": "production",
+		"owner":       "team@company.com",
+	}
+	withTags := func(extra map[string]string) map[string]string {
+		tags := make(map[string]string, len(base)+len(extra))
+		for key, value := range base {
+			tags[key] = value
+		}
+		for key, value := range extra {
+			tags[key] = value
+		}
+		return tags
+	}
+
+	testCases := []struct {
+		name          string
+		tags          map[string]string
+		expectedLevel ComplianceLevel
+	}{
+		{
+			name:          "Only the base level is met",
+			tags:          base,
+			expectedLevel: ComplianceLevelLow,
+		},
+		{
+			name:          "Child level met with inherited tags",
+			tags:          withTags(map[string]string{"managed-by": "terraform"}),
+			expectedLevel: ComplianceLevelStandard,
+		},
+		{
+			name:          "Own tags without inherited specific tags do not meet the child level",
+			tags:          withTags(map[string]string{"data-classification": "internal"}),
+			expectedLevel: ComplianceLevelLow,
+		},
+		{
+			name:          "Strictest level met",
+			tags:          withTags(map[string]string{"managed-by": "terraform", "data-classification": "internal"}),
+			expectedLevel: ComplianceLevelHigh,
+		},
+		{
+			name:          "No level met",
+			tags:          map[string]string{"environment": "production"},
+			expectedLevel: "",
+		},
+	}
+
+	validator := NewTagValidator(config)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := validator.ValidateTags(tc.tags)
+			assert.Equal(t, tc.expectedLevel, result.ComplianceLevel)
+		})
+	}
+
+	t.Run("Summary counts the achieved levels", func(t *testing.T) {
+		summary := GenerateSummary([]*ComplianceResult{
+			validator.ValidateTags(base),
+			validator.ValidateTags(withTags(map[string]string{"managed-by": "terraform", "data-classification": "internal"})),
+		})
+		assert.Equal(t, map[ComplianceLevel]int{ComplianceLevelLow: 1, ComplianceLevelHigh: 1}, summary.ComplianceLevelDistribution)
+	})
+}
+
+func TestValidateTags_SeverityAndScore(t *testing.T) {
+	testCases := []struct {
+		name               string
+		tags               map[string]string
+		expectedSeverities map[ViolationType]Severity
+		expectedScore      float64
+	}{
+		{
+			name: "Compliant tags score the maximum",
+			tags: map[string]string{
+				"environment": "production",
+				"owner":       "team@company.com",
+			},
+			expectedSeverities: map[ViolationType]Severity{},
+			expectedScore:      100,
+		},
+		{
+			name: "Each missing tag weighs with its own severity",
+			tags: map[string]string{
+				"cost-center": "CC-1234",
+			},
+			expectedSeverities: map[ViolationType]Severity{
+				ViolationTypeMissingTags: SeverityCritical,
+			},
+			expectedScore: 50,
+		},
+		{
+			name: "Rule severities are propagated",
+			tags: map[string]string{
+				"environment": "Prod",
+				"owner":       "someone@example.com",
+			},
+			expectedSeverities: map[ViolationType]Severity{
+				ViolationTypeCaseViolation:    SeverityLow,
+				ViolationTypePatternViolation: SeverityHigh,
+				ViolationTypeInvalidValue:     SeverityMedium,
+			},
+			expectedScore: 65,
+		},
+		{
+			name: "Score does not go below zero",
+			tags: map[string]string{
+				"Temp":    "yes",
+				"Test":    "yes",
+				"TempDir": "yes",
+			},
+			expectedSeverities: map[ViolationType]Severity{
+				ViolationTypeMissingTags:      SeverityCritical,
+				ViolationTypeProhibitedTag:    SeverityMedium,
+				ViolationTypeInvalidKeyFormat: SeverityMedium,
+			},
+			expectedScore: 0,
+		},
+	}
+
+	config := createTestConfig()
+	config.Global.TagCriteria.RequiredTagSeverities = map[string]configuration.Severity{
+		"environment": configuration.SeverityCritical,
+	}
+	config.TagValidation.PatternRuleSeverities = map[string]configuration.Severity{
+		"owner": configuration.SeverityHigh,
+	}
+	config.TagValidation.CaseRules["environment"] = configuration.CaseRule{
+		Case:     "lowercase",
+		Severity: configuration.SeverityLow,
+	}
+	validator := NewTagValidator(config)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := validator.ValidateTags(tc.tags)
+
+			severities := make(map[ViolationType]Severity)
+			for _, violation := range result.Violations {
+				severities[violation.Type] = violation.Severity
+			}
+
+			assert.Equal(t, tc.expectedSeverities, severities, fmt.Sprintf("violations: %v", result.Violations))
+			assert.Equal(t, tc.expectedScore, result.Score)
+		})
+	}
+}
+
+func TestValidateTags_Concurrent(t *testing.T) {
+	config := createTestConfig()
+	require.NoError(t, config.TagValidation.CompilePatterns())
+	validator := NewTagValidator(config)
+
+	compliant := map[string]string{"environment": "production", "owner": "team@company.com"}
+	invalid := map[string]string{"environment": "production", "owner": "Team@Example.com", "Bad Key": "x"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if i%2 == 0 {
+					assert.True(t, validator.ValidateTags(compliant).IsCompliant)
+					continue
+				}
+				result := validator.ValidateTags(invalid)
+				assert.False(t, result.IsCompliant)
+				assert.True(t, hasViolation(result.Violations, ViolationTypePatternViolation))
+				assert.True(t, hasViolation(result.Violations, ViolationTypeInvalidKeyFormat))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestValidateTags_InvalidPatternsAreSkipped(t *testing.T) {
+	config := createTestConfig()
+	config.TagValidation.PatternRules["owner"] = "(unclosed"
+	config.TagValidation.KeyFormatRules = []configuration.KeyFormatRule{{Pattern: "[", Message: "never compiles"}}
+
+	result := NewTagValidator(config).ValidateTags(map[string]string{
+		"environment": "production",
+		"owner":       "team@company.com",
+	})
+
+	assert.True(t, result.IsCompliant, fmt.Sprintf("violations: %v", result.Violations))
+}
+
+func TestValidateUnreadable(t *testing.T) {
+	ref := ResourceRef{ID: "locked", ARN: "arn:aws:s3:::locked", Type: "s3"}
+	validator := NewTagValidator(createTestConfig())
+
+	result := validator.ValidateUnreadable(ref, "AccessDenied")
+	assert.True(t, result.IsUnknown)
+	assert.False(t, result.IsCompliant)
+	require.Len(t, result.Violations, 1)
+	assert.Equal(t, ViolationTypeTagsUnreadable, result.Violations[0].Type)
+
+	validator.SetTreatUnreadableAsNonCompliant(true)
+	result = validator.ValidateUnreadable(ref, "AccessDenied")
+	assert.False(t, result.IsUnknown)
+	assert.False(t, result.IsCompliant)
+	assert.True(t, hasViolation(result.Violations, ViolationTypeTagsUnreadable))
+	assert.True(t, hasViolation(result.Violations, ViolationTypeMissingTags))
+}
+
+func TestValidateTags_SuggestedValues(t *testing.T) {
+	config := createTestConfig()
+	config.TagValidation.PatternRuleExamples = map[string]string{"owner": "platform@company.com"}
+	validator := NewTagValidator(config)
+
+	testCases := []struct {
+		name     string
+		tags     map[string]string
+		expected map[string]string
+	}{
+		{
+			name: "Case fixes leading to allowed values",
+			tags: map[string]string{
+				"environment": "Staging",
+				"owner":       "Team@Company.com",
+			},
+			expected: map[string]string{
+				"case_violation/environment": "staging",
+				"case_violation/owner":       "team@company.com",
+				"pattern_violation/owner":    "platform@company.com",
+			},
+		},
+		{
+			name: "No case fix leading to invalid values",
+			tags: map[string]string{
+				"environment": "Prodution",
+				"owner":       "Team@Example.com",
+			},
+			expected: map[string]string{
+				"invalid_value/environment":  "production",
+				"case_violation/environment": "",
+				"case_violation/owner":       "",
+				"pattern_violation/owner":    "platform@company.com",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := validator.ValidateTags(tc.tags)
+
+			// Suggestions are keyed by violation type and tag
+			suggestions := make(map[string]string)
+			for _, violation := range result.Violations {
+				suggestions[string(violation.Type)+"/"+violation.TagKey] = violation.SuggestedValue
+				assert.NotEmpty(t, violation.DocURL, violation.Message)
+			}
+
+			assert.Equal(t, tc.expected, suggestions)
+		})
+	}
+}
+
+func TestValidateTags_CaseDuplicateKeys(t *testing.T) {
+	noKeyRules := func(config *configuration.TaggyScanConfig) {
+		config.TagValidation.KeyFormatRules = nil
+		config.TagValidation.CaseRules = nil
+	}
+
+	testCases := []struct {
+		name              string
+		disabled          bool
+		configure         func(*configuration.TaggyScanConfig)
+		tags              map[string]string
+		expectedMessages  []string
+		expectedSuggested []string
+	}{
+		{
+			name:     "Disabled",
+			disabled: true,
+			tags:     map[string]string{"environment": "production", "Environment": "production", "owner": "team@company.com"},
+		},
+		{
+			name:              "Suggests the key following the case rules",
+			tags:              map[string]string{"environment": "production", "Environment": "production", "ENVIRONMENT": "prod", "owner": "team@company.com"},
+			expectedMessages:  []string{"Tag keys 'ENVIRONMENT', 'Environment', 'environment' only differ in case"},
+			expectedSuggested: []string{"environment"},
+		},
+		{
+			name:              "No suggestion without key rules",
+			configure:         noKeyRules,
+			tags:              map[string]string{"environment": "production", "owner": "team@company.com", "Team": "data", "team": "data"},
+			expectedMessages:  []string{"Tag keys 'Team', 'team' only differ in case"},
+			expectedSuggested: []string{""},
+		},
+		{
+			name:              "One violation per colliding key set",
+			configure:         noKeyRules,
+			tags:              map[string]string{"environment": "production", "Environment": "production", "owner": "team@company.com", "Owner": "team@company.com"},
+			expectedMessages:  []string{"Tag keys 'Environment', 'environment' only differ in case", "Tag keys 'Owner', 'owner' only differ in case"},
+			expectedSuggested: []string{"", ""},
+		},
+		{
+			name: "No duplicates",
+			tags: map[string]string{"environment": "production", "owner": "team@company.com"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := createTestConfig()
+			config.TagValidation.KeyValidation.DenyCaseDuplicates = !tc.disabled
+			if tc.configure != nil {
+				tc.configure(config)
+			}
+
+			result := NewTagValidator(config).ValidateTags(tc.tags)
+
+			var messages, suggested []string
+			for _, violation := range result.Violations {
+				if violation.Type == ViolationTypeDuplicateKeyDifferentCase {
+					messages = append(messages, violation.Message)
+					suggested = append(suggested, violation.SuggestedValue)
+				}
+			}
+			assert.Equal(t, tc.expectedMessages, messages)
+			assert.Equal(t, tc.expectedSuggested, suggested)
+			if len(tc.expectedMessages) > 0 {
+				assert.False(t, result.IsCompliant)
+			}
+		})
+	}
+}
+
+func TestValidateTags_AWSTagLimits(t *testing.T) {
+	t.Parallel()
+
+	longValue := strings.Repeat("a", AWSTagValueMaxLength+1)
+
+	testCases := []struct {
+		name             string
+		enforce          *bool
+		tags             map[string]string
+		expectedMessages []string
+	}{
+		{
+			name: "Value too long",
+			tags: map[string]string{"environment": "production", "owner": "team@company.com", "description": longValue},
+			expectedMessages: []string{
+				"Tag 'description' would be rejected by AWS: value is 257 characters long, the limit is 256",
+			},
+		},
+		{
+			name: "Key too long and invalid characters",
+			tags: map[string]string{"environment": "production", "owner": "team@company.com", strings.Repeat("k", 129): "a,b;c,"},
+			expectedMessages: []string{
+				"Tag '" + strings.Repeat("k", 129) + "' would be rejected by AWS: key is 129 characters long, the limit is 128, value contains characters AWS does not allow: \",;\"",
+			},
+		},
+		{
+			name: "Unicode letters and spaces are accepted",
+			tags: map[string]string{"environment": "production", "owner": "team@company.com", "team": "Équipe données"},
+		},
+		{
+			name: "Tags created by AWS are left out",
+			tags: map[string]string{"environment": "production", "owner": "team@company.com", "aws:cloudformation:stack-name": "core"},
+		},
+		{
+			name:    "Disabled",
+			enforce: new(bool),
+			tags:    map[string]string{"environment": "production", "owner": "team@company.com", "description": longValue},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := createTestConfig()
+			config.TagValidation.ProhibitedTags = nil
+			config.TagValidation.EnforceAWSLimits = tc.enforce
+
+			result := NewTagValidator(config).ValidateTags(tc.tags)
+
+			var messages []string
+			for _, violation := range result.Violations {
+				if violation.Type == ViolationTypeAWSTagLimit {
+					messages = append(messages, violation.Message)
+					assert.Equal(t, SeverityHigh, violation.Severity)
+					assert.Equal(t, ruleDocsURL+"#aws-tag-limits", violation.DocURL)
+				}
+			}
+			assert.Equal(t, tc.expectedMessages, messages)
+		})
+	}
+}
+
+func TestCheckAWSTagLimits(t *testing.T) {
+	t.Parallel()
+
+	violations := CheckAWSTagLimits(map[string]string{
+		"AWS:Owner": "team",
+		"team":      "platform",
+		"cost#":     "100",
+	})
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, "AWS:Owner", violations[0].TagKey)
+	assert.Equal(t, "Tag 'AWS:Owner' would be rejected by AWS: key starts with the reserved aws: prefix", violations[0].Message)
+	assert.Equal(t, "cost#", violations[1].TagKey)
+	assert.Equal(t, "Tag 'cost#' would be rejected by AWS: key contains characters AWS does not allow: \"#\"", violations[1].Message)
+	assert.Empty(t, CheckAWSTagLimits(map[string]string{"team": "platform"}))
+}
+
+func TestValidateTags_KeyValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		keyValidation configuration.KeyValidation
+		tags          map[string]string
+		expected      []Violation
+	}{
+		{
+			name:          "Allowed prefix and suffix",
+			keyValidation: configuration.KeyValidation{AllowedPrefixes: []string{"env-"}, AllowedSuffixes: []string{"-prod"}},
+			tags:          map[string]string{"env-app-prod": "payments"},
+		},
+		{
+			name:          "Suffix enforced like prefixes",
+			keyValidation: configuration.KeyValidation{AllowedPrefixes: []string{"env-"}, AllowedSuffixes: []string{"-prod", "-dev"}},
+			tags:          map[string]string{"env-app": "payments", "team-prod": "data"},
+			expected: []Violation{
+				{Type: ViolationTypeInvalidKeySuffix, TagKey: "env-app", Message: "Tag key 'env-app' must end with one of: -prod, -dev"},
+				{Type: ViolationTypeInvalidKeyPrefix, TagKey: "team-prod", Message: "Tag key 'team-prod' must start with one of: env-"},
+			},
+		},
+		{
+			name:          "Maximum length",
+			keyValidation: configuration.KeyValidation{MaxLength: 8},
+			tags:          map[string]string{"application": "payments"},
+			expected: []Violation{
+				{Type: ViolationTypeKeyTooLong, TagKey: "application", Message: "Tag key 'application' exceeds the maximum length of 8 characters"},
+			},
+		},
+		{
+			name:          "Tags created by AWS are left out",
+			keyValidation: configuration.KeyValidation{AllowedPrefixes: []string{"env-"}},
+			tags:          map[string]string{"aws:cloudformation:stack-name": "core"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := &configuration.TaggyScanConfig{}
+			config.TagValidation.KeyValidation = tc.keyValidation
+
+			result := NewTagValidator(config).ValidateTags(tc.tags)
+
+			var violations []Violation
+			for _, violation := range result.Violations {
+				assert.Equal(t, SeverityMedium, violation.Severity)
+				assert.Equal(t, ruleDocsURL+"#tag-key-restrictions", violation.DocURL)
+				violations = append(violations, Violation{Type: violation.Type, TagKey: violation.TagKey, Message: violation.Message})
+			}
+			assert.Equal(t, tc.expected, violations)
+			assert.Equal(t, len(tc.expected) == 0, result.IsCompliant)
+		})
+	}
+}
+
+func TestValidateTags_ViolationsCarryStructuredFields(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		configure func(config *configuration.TaggyScanConfig)
+		tags      map[string]string
+		expected  Violation
+	}{
+		{
+			name:      "Too many tags",
+			configure: func(config *configuration.TaggyScanConfig) { config.Global.TagCriteria.MaxTags = 1 },
+			tags:      map[string]string{"environment": "production", "owner": "team"},
+			expected:  Violation{Type: ViolationTypeTooManyTags, Expected: "at most 1 tags"},
+		},
+		{
+			name: "Missing required tags",
+			configure: func(config *configuration.TaggyScanConfig) {
+				config.Global.TagCriteria.RequiredTags = []string{"owner", "team"}
+			},
+			tags:     map[string]string{"environment": "production"},
+			expected: Violation{Type: ViolationTypeMissingTags, Expected: "owner, team"},
+		},
+		{
+			name: "Specific tag value differs",
+			configure: func(config *configuration.TaggyScanConfig) {
+				config.Global.TagCriteria.SpecificTags = map[string]string{"managed-by": "terraform"}
+			},
+			tags:     map[string]string{"managed-by": "console"},
+			expected: Violation{Type: ViolationTypeSpecificTagMismatch, TagKey: "managed-by", Value: "console", Expected: "terraform"},
+		},
+		{
+			name:      "Prohibited tag",
+			configure: func(config *configuration.TaggyScanConfig) { config.TagValidation.ProhibitedTags = []string{"temp"} },
+			tags:      map[string]string{"temp": "yes"},
+			expected:  Violation{Type: ViolationTypeProhibitedTag, TagKey: "temp", Expected: absentTag},
+		},
+		{
+			name: "Forbidden tag",
+			configure: func(config *configuration.TaggyScanConfig) {
+				config.Global.TagCriteria.ForbiddenTags = []string{"legacy"}
+			},
+			tags:     map[string]string{"legacy": "true"},
+			expected: Violation{Type: ViolationTypeForbiddenTag, TagKey: "legacy", Expected: absentTag},
+		},
+		{
+			name: "Key format",
+			configure: func(config *configuration.TaggyScanConfig) {
+				config.TagValidation.KeyFormatRules = createTestConfig().TagValidation.KeyFormatRules
+			},
+			tags:     map[string]string{"9lives": "cat"},
+			expected: Violation{Type: ViolationTypeInvalidKeyFormat, TagKey: "9lives", Expected: "key matching ^[a-z][a-z0-9_-]*$"},
+		},
+		{
+			name: "Value case",
+			configure: func(config *configuration.TaggyScanConfig) {
+				config.TagValidation.CaseRules = createTestConfig().TagValidation.CaseRules
+			},
+			tags:     map[string]string{"environment": "Production"},
+			expected: Violation{Type: ViolationTypeCaseViolation, TagKey: "environment", Value: "Production", Expected: "lowercase"},
+		},
+		{
+			name: "Key case",
+			configure: func(config *configuration.TaggyScanConfig) {
+				config.TagValidation.CaseRules = createTestConfig().TagValidation.CaseRules
+			},
+			tags:     map[string]string{"Environment": "production"},
+			expected: Violation{Type: ViolationTypeCaseViolation, TagKey: "Environment", Expected: "environment"},
+		},
+		{
+			name: "Keys only differing in case",
+			configure: func(config *configuration.TaggyScanConfig) {
+				config.TagValidation.KeyValidation.DenyCaseDuplicates = true
+			},
+			tags:     map[string]string{"Team": "data", "team": "data"},
+			expected: Violation{Type: ViolationTypeDuplicateKeyDifferentCase, TagKey: "Team", Expected: "a single spelling of the key"},
+		},
+		{
+			name: "Pattern",
+			configure: func(config *configuration.TaggyScanConfig) {
+				config.TagValidation.PatternRules = createTestConfig().TagValidation.PatternRules
+			},
+			tags:     map[string]string{"owner": "jane"},
+			expected: Violation{Type: ViolationTypePatternViolation, TagKey: "owner", Value: "jane", Expected: `^[a-z0-9._%+-]+@company\.com$`},
+		},
+		{
+			name: "Allowed values",
+			configure: func(config *configuration.TaggyScanConfig) {
+				config.TagValidation.AllowedValues = createTestConfig().TagValidation.AllowedValues
+			},
+			tags:     map[string]string{"environment": "qa"},
+			expected: Violation{Type: ViolationTypeInvalidValue, TagKey: "environment", Value: "qa", Expected: "production, staging, development"},
+		},
+		{
+			name: "Key prefix",
+			configure: func(config *configuration.TaggyScanConfig) {
+				config.TagValidation.KeyValidation.AllowedPrefixes = []string{"env-", "app-"}
+			},
+			tags:     map[string]string{"team": "data"},
+			expected: Violation{Type: ViolationTypeInvalidKeyPrefix, TagKey: "team", Expected: "key prefix env-, app-"},
+		},
+		{
+			name:      "Key length",
+			configure: func(config *configuration.TaggyScanConfig) { config.TagValidation.KeyValidation.MaxLength = 4 },
+			tags:      map[string]string{"environment": "production"},
+			expected:  Violation{Type: ViolationTypeKeyTooLong, TagKey: "environment", Expected: "key of at most 4 characters"},
+		},
+		{
+			name:      "AWS tag limits",
+			configure: func(config *configuration.TaggyScanConfig) {},
+			tags:      map[string]string{"owner": "team#1"},
+			expected:  Violation{Type: ViolationTypeAWSTagLimit, TagKey: "owner", Value: "team#1", Expected: awsTagLimitsExpectation},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := &configuration.TaggyScanConfig{}
+			tc.configure(config)
+
+			result := NewTagValidator(config).ValidateTags(tc.tags)
+			require.Len(t, result.Violations, 1, fmt.Sprintf("violations: %v", result.Violations))
+
+			violation := result.Violations[0]
+			assert.NotEmpty(t, violation.Message)
+			assert.Equal(t, tc.expected, Violation{
+				Type:     violation.Type,
+				TagKey:   violation.TagKey,
+				Value:    violation.Value,
+				Expected: violation.Expected,
+			})
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package configuration
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ResolveComplianceLevel returns the effective requirements of a compliance level, merging
+// the required and specific tags of every level it extends. Parents are applied first, so
+// the specific tags of a child override those of its parents. The resolved level does not
+// extend any other level.
+func (c *TaggyScanConfig) ResolveComplianceLevel(name string) (ComplianceLevel, error) {
+	chain, err := c.complianceLevelChain(name)
+	if err != nil {
+		return ComplianceLevel{}, err
+	}
+
+	var resolved ComplianceLevel
+	seen := make(map[string]bool)
+
+	// Walk from the root parent down to the requested level
+	for i := len(chain) - 1; i >= 0; i-- {
+		level := c.ComplianceLevels[chain[i]]
+
+		for _, tag := range level.RequiredTags {
+			if !seen[tag] {
+				seen[tag] = true
+				resolved.RequiredTags = append(resolved.RequiredTags, tag)
+			}
+		}
+
+		if len(level.SpecificTags) > 0 && resolved.SpecificTags == nil {
+			resolved.SpecificTags = make(map[string]string, len(level.SpecificTags))
+		}
+		maps.Copy(resolved.SpecificTags, level.SpecificTags)
+	}
+
+	return resolved, nil
+}
+
+// ResolvedComplianceLevels returns the effective requirements of every compliance level,
+// keyed by level name
+func (c *TaggyScanConfig) ResolvedComplianceLevels() (map[string]ComplianceLevel, error) {
+	resolved := make(map[string]ComplianceLevel, len(c.ComplianceLevels))
+
+	for _, name := range slices.Sorted(maps.Keys(c.ComplianceLevels)) {
+		level, err := c.ResolveComplianceLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		resolved[name] = level
+	}
+
+	return resolved, nil
+}
+
+// complianceLevelChain returns the level followed by its ancestors, nearest parent first.
+// It fails when a level of the chain is unknown or the chain loops back on itself.
+func (c *TaggyScanConfig) complianceLevelChain(name string) ([]string, error) {
+	if _, exists := c.ComplianceLevels[name]; !exists {
+		return nil, fmt.Errorf("compliance level %s not found", name)
+	}
+
+	chain := []string{name}
+	visited := map[string]bool{name: true}
+
+	for current := name; c.ComplianceLevels[current].Extends != ""; {
+		parent := c.ComplianceLevels[current].Extends
+
+		if _, exists := c.ComplianceLevels[parent]; !exists {
+			return nil, fmt.Errorf("compliance level %s extends unknown level %s", current, parent)
+		}
+		if visited[parent] {
+			return nil, fmt.Errorf("compliance level inheritance cycle: %s -> %s", strings.Join(chain, " -> "), parent)
+		}
+
+		visited[parent] = true
+		chain = append(chain, parent)
+		current = parent
+	}
+
+	return chain, nil
+}
@@ -0,0 +1,133 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createInheritingLevelsConfig() *TaggyScanConfig {
+	return &TaggyScanConfig{
+		ComplianceLevels: map[string]ComplianceLevel{
+			"low": {
+				RequiredTags: []string{"Owner"},
+				SpecificTags: map[string]string{"ManagedBy": "manual"},
+			},
+			"standard": {
+				RequiredTags: []string{"Environment", "Owner"},
+				SpecificTags: map[string]string{"ManagedBy": "terraform"},
+				Extends:      "low",
+			},
+			"high": {
+				RequiredTags: []string{"DataClassification", "Backup", "CostCenter"},
+				SpecificTags: map[string]string{"SecurityApproved": "true"},
+				Extends:      "standard",
+			},
+		},
+	}
+}
+
+func TestResolveComplianceLevel(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		level        string
+		requiredTags []string
+		specificTags map[string]string
+	}{
+		{
+			name:         "Level Without Parent",
+			level:        "low",
+			requiredTags: []string{"Owner"},
+			specificTags: map[string]string{"ManagedBy": "manual"},
+		},
+		{
+			name:         "Child Overrides Parent Specific Tags",
+			level:        "standard",
+			requiredTags: []string{"Owner", "Environment"},
+			specificTags: map[string]string{"ManagedBy": "terraform"},
+		},
+		{
+			name:         "Transitive Inheritance",
+			level:        "high",
+			requiredTags: []string{"Owner", "Environment", "DataClassification", "Backup", "CostCenter"},
+			specificTags: map[string]string{"ManagedBy": "terraform", "SecurityApproved": "true"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolved, err := createInheritingLevelsConfig().ResolveComplianceLevel(tc.level)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.requiredTags, resolved.RequiredTags)
+			assert.Equal(t, tc.specificTags, resolved.SpecificTags)
+			assert.Empty(t, resolved.Extends)
+		})
+	}
+}
+
+func TestResolveComplianceLevelErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		setup   func(*TaggyScanConfig)
+		level   string
+		wantErr string
+
+		// brokenChain reports whether resolving every level fails as well
+		brokenChain bool
+	}{
+		{
+			name:    "Unknown Level",
+			setup:   func(cfg *TaggyScanConfig) {},
+			level:   "medium",
+			wantErr: "compliance level medium not found",
+		},
+		{
+			name: "Unknown Parent",
+			setup: func(cfg *TaggyScanConfig) {
+				low := cfg.ComplianceLevels["low"]
+				low.Extends = "medium"
+				cfg.ComplianceLevels["low"] = low
+			},
+			level:       "high",
+			wantErr:     "compliance level low extends unknown level medium",
+			brokenChain: true,
+		},
+		{
+			name: "Inheritance Cycle",
+			setup: func(cfg *TaggyScanConfig) {
+				low := cfg.ComplianceLevels["low"]
+				low.Extends = "high"
+				cfg.ComplianceLevels["low"] = low
+			},
+			level:       "high",
+			wantErr:     "compliance level inheritance cycle: high -> standard -> low -> high",
+			brokenChain: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := createInheritingLevelsConfig()
+			tc.setup(cfg)
+
+			_, err := cfg.ResolveComplianceLevel(tc.level)
+			require.Error(t, err)
+			assert.EqualError(t, err, tc.wantErr)
+
+			if tc.brokenChain {
+				_, err = cfg.ResolvedComplianceLevels()
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@
 
 import (
 	"fmt"
-	"regexp"
+	"slices"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/Excoriate/aws-taggy/pkg/arn"
 )
 
 // TaggyScanConfig represents the overall configuration structure for the AWS tag management tool.
@@ -14,77 +17,179 @@
 // compliance levels, and notification mechanisms across different AWS resource types.
 type TaggyScanConfig struct {
 	// Version of the configuration file format
-	Version string `yaml:"version"`
+	Version string `yaml:"version" json:"version,omitempty"`
 
 	// Global defines default configuration settings that apply across all resources
-	Global GlobalConfig `yaml:"global"`
+	Global GlobalConfig `yaml:"global" json:"global"`
 
 	// Resources contains configuration specific to individual resource types
-	Resources map[string]ResourceConfig `yaml:"resources"`
+	Resources map[string]ResourceConfig `yaml:"resources" json:"resources,omitempty"`
 
 	// ComplianceLevels defines different levels of tag compliance with their specific requirements
-	ComplianceLevels map[string]ComplianceLevel `yaml:"compliance_levels"`
+	ComplianceLevels map[string]ComplianceLevel `yaml:"compliance_levels" json:"compliance_levels,omitempty"`
 
 	// TagValidation contains rules for validating tags across resources
-	TagValidation TagValidation `yaml:"tag_validation"`
+	TagValidation TagValidation `yaml:"tag_validation" json:"tag_validation"`
+
+	// TagCriteriaTemplates defines tag criteria shared by several resources, keyed by template
+	// name. A resource uses one by naming it in tag_criteria.template.
+	TagCriteriaTemplates map[string]TagCriteria `yaml:"tag_criteria_templates,omitempty" json:"tag_criteria_templates,omitempty"`
+
+	// TagDefaults defines the values used when generating tags, keyed by tag name.
+	// Values are text/template expressions, e.g. "{{ env \"USER\" }}@company.com"
+	TagDefaults map[string]string `yaml:"tag_defaults,omitempty" json:"tag_defaults,omitempty"`
 
 	// Notifications manages the settings for reporting tag inspection results
-	Notifications NotificationConfig `yaml:"notifications"`
+	Notifications NotificationConfig `yaml:"notifications" json:"notifications"`
+
+	// Reporting controls how results are rendered in the reports
+	Reporting ReportingConfig `yaml:"reporting,omitempty" json:"reporting,omitempty"`
+
+	// CrossResourceRules are assertions on the tags of several resources at once, checked
+	// once every resource of the scan is collected
+	CrossResourceRules []CrossResourceRule `yaml:"cross_resource_rules,omitempty" json:"cross_resource_rules,omitempty"`
 
 	// AWS configuration for region scanning
-	AWS AWSConfig `yaml:"aws"`
+	AWS AWSConfig `yaml:"aws" json:"aws"`
 }
 
 // GlobalConfig defines the default configuration settings that apply across all resources.
 // It includes batch processing size, required and forbidden tags, and specific tag requirements.
 type GlobalConfig struct {
 	// Enabled determines if global configuration is active
-	Enabled bool `yaml:"enabled"`
+	Enabled bool `yaml:"enabled" json:"enabled,omitempty"`
 
 	// BatchSize specifies the default number of resources to process in a single batch
 	// If not set, a system-default batch size will be used
 	// This serves as a fallback/default for resource-specific and provider-specific batch sizes
-	BatchSize *int `yaml:"batch_size,omitempty"`
+	BatchSize *int `yaml:"batch_size,omitempty" json:"batch_size,omitempty"`
+
+	// Workers specifies the default number of workers processing the discovered resources of
+	// each resource type
+	// If not set, a system-default number of workers will be used
+	Workers *int `yaml:"workers,omitempty" json:"workers,omitempty"`
+
+	// MaxConcurrency bounds the number of AWS discovery and processing operations running
+	// at the same time across every scanned resource type
+	// If not set, a system-default limit is used
+	MaxConcurrency *int `yaml:"max_concurrency,omitempty" json:"max_concurrency,omitempty"`
 
 	// TagCriteria defines the default tag validation rules for all resources
-	TagCriteria TagCriteria `yaml:"tag_criteria"`
+	TagCriteria TagCriteria `yaml:"tag_criteria" json:"tag_criteria"`
 }
 
 // ResourceConfig provides configuration specific to individual resource types.
 // It allows for more granular control over tag requirements, exclusions, and processing.
 type ResourceConfig struct {
 	// Enabled determines if this resource type is subject to tag inspection
-	Enabled bool `yaml:"enabled"`
+	Enabled bool `yaml:"enabled" json:"enabled,omitempty"`
 
 	// Regions is an optional list of regions to scan for this specific resource type
 	// If set, it overrides the global AWS regions configuration
-	Regions []string `yaml:"regions,omitempty"`
+	Regions []string `yaml:"regions,omitempty" json:"regions,omitempty"`
 
 	// TagCriteria defines tag validation rules specific to this resource type
-	TagCriteria TagCriteria `yaml:"tag_criteria"`
+	TagCriteria TagCriteria `yaml:"tag_criteria" json:"tag_criteria"`
+
+	// TagValidation adds to or replaces the allowed values, pattern rules and case rules of
+	// the global tag validation for this resource type, see TaggyScanConfig.ResourceTagValidation
+	TagValidation *TagValidationOverride `yaml:"tag_validation,omitempty" json:"tag_validation,omitempty"`
 
 	// ExcludedResources lists specific resources to be excluded from tag inspection
-	ExcludedResources []ExcludedResource `yaml:"excluded_resources"`
+	ExcludedResources []ExcludedResource `yaml:"excluded_resources" json:"excluded_resources,omitempty"`
+
+	// BatchSize overrides the AWS and global batch sizes for this resource type
+	BatchSize *int `yaml:"batch_size,omitempty" json:"batch_size,omitempty"`
+
+	// Workers overrides the AWS and global number of workers for this resource type
+	Workers *int `yaml:"workers,omitempty" json:"workers,omitempty"`
+
+	// RateLimit caps the AWS API calls made while scanning this resource type, in requests per second
+	// If not set, API calls are only bounded by the global concurrency
+	RateLimit *float64 `yaml:"rate_limit,omitempty" json:"rate_limit,omitempty"`
+
+	// ResourceTypeFilters restricts the generic resource type to the given Resource Groups
+	// Tagging API resource types, such as "kinesis" or "states:stateMachine"
+	// If not set, every taggable resource is inspected
+	ResourceTypeFilters []string `yaml:"resource_type_filters,omitempty" json:"resource_type_filters,omitempty"`
+
+	// IncludeInstanceStates restricts the ec2 resource type to instances in the given states,
+	// such as "running" or "stopped"
+	// If not set, running and stopped instances are inspected
+	IncludeInstanceStates []string `yaml:"include_instance_states,omitempty" json:"include_instance_states,omitempty"`
+
+	// IncludeSnapshots also inspects the EBS snapshots owned by the account along with the
+	// volumes of the ebs resource type
+	IncludeSnapshots bool `yaml:"include_snapshots,omitempty" json:"include_snapshots,omitempty"`
+
+	// BucketRegions restricts the s3 resource type to the buckets located in the given regions.
+	// Buckets are listed account-wide, so without it every bucket is inspected whatever the
+	// scanned regions.
+	BucketRegions []string `yaml:"bucket_regions,omitempty" json:"bucket_regions,omitempty"`
+
+	// RoleARN is the IAM role assumed to scan this resource type, e.g. in the logging account
+	// owning every log group, instead of the default credentials or the declared accounts
+	RoleARN string `yaml:"role_arn,omitempty" json:"role_arn,omitempty"`
+
+	// ExternalID is passed to AssumeRole when the trust policy of RoleARN requires it
+	ExternalID string `yaml:"external_id,omitempty" json:"external_id,omitempty"`
+
+	// DisabledRules lists the built-in rule categories, such as case_rules, left out of the
+	// compliance checks of the resources of this type, e.g. for a service whose tags cannot
+	// follow the conventions of the rest of the account
+	DisabledRules []string `yaml:"disabled_rules,omitempty" json:"disabled_rules,omitempty"`
+}
+
+// InstanceStates returns the states of the EC2 instances to inspect, defaulting to running
+// and stopped instances
+func (rc ResourceConfig) InstanceStates() []string {
+	if len(rc.IncludeInstanceStates) > 0 {
+		return rc.IncludeInstanceStates
+	}
+	return DefaultEC2InstanceStates()
+}
+
+// IsRuleDisabled reports whether the built-in rule category is disabled for the resource type
+func (rc ResourceConfig) IsRuleDisabled(rule string) bool {
+	return slices.Contains(rc.DisabledRules, rule)
+}
+
+// RoleAccount returns the account scanned with the role of the resource type, identified by
+// the account ID of the role ARN, and false when the resource type has no role
+func (rc ResourceConfig) RoleAccount() (AccountConfig, bool) {
+	if rc.RoleARN == "" {
+		return AccountConfig{}, false
+	}
+
+	account := AccountConfig{RoleARN: rc.RoleARN, ExternalID: rc.ExternalID}
+	if roleARN, err := arn.Parse(rc.RoleARN); err == nil {
+		account.AccountID = roleARN.AccountID
+	}
+	return account, true
 }
 
 // ExcludedResource defines a specific resource to be excluded from tag inspection,
 // with a pattern to match and a reason for exclusion.
 type ExcludedResource struct {
 	// Pattern is a regex or identifier to match resources for exclusion
-	Pattern string `yaml:"pattern"`
+	Pattern string `yaml:"pattern" json:"pattern"`
 
 	// Reason explains why the resource is being excluded from tag inspection
-	Reason string `yaml:"reason"`
+	Reason string `yaml:"reason" json:"reason,omitempty"`
 }
 
 // ComplianceLevel specifies the tag requirements for achieving a particular
 // compliance status or level within the tag inspection process.
 type ComplianceLevel struct {
 	// RequiredTags is a list of tag keys that must be present to meet this compliance level
-	RequiredTags []string `yaml:"required_tags"`
+	RequiredTags []string `yaml:"required_tags" json:"required_tags,omitempty"`
 
 	// SpecificTags defines exact tag key-value pairs required for this compliance level
-	SpecificTags map[string]string `yaml:"specific_tags"`
+	SpecificTags map[string]string `yaml:"specific_tags" json:"specific_tags,omitempty"`
+
+	// Extends names a parent level whose required and specific tags this level inherits,
+	// entries of this level winning on conflicts
+	Extends string `yaml:"extends,omitempty" json:"extends,omitempty"`
 }
 
 // CaseType represents the type of case validation
@@ -98,9 +203,10 @@ type ComplianceLevel struct {
 
 // CaseRule defines the case validation rule for a tag
 type CaseRule struct {
-	Case    CaseType `yaml:"case"`
-	Pattern string   `yaml:"pattern,omitempty"` // Optional pattern for mixed case
-	Message string   `yaml:"message"`
+	Case     CaseType `yaml:"case" json:"case"`
+	Pattern  string   `yaml:"pattern,omitempty" json:"pattern,omitempty"` // Optional pattern for mixed case
+	Message  string   `yaml:"message" json:"message,omitempty"`
+	Severity Severity `yaml:"severity,omitempty" json:"severity,omitempty"` // Defaults to medium
 }
 
 // CaseValidationMode defines the strictness of case validation
@@ -113,7 +219,7 @@ type CaseRule struct {
 
 // CaseSensitivityConfig defines case sensitivity rules for a specific tag
 type CaseSensitivityConfig struct {
-	Mode CaseValidationMode `yaml:"mode"`
+	Mode CaseValidationMode `yaml:"mode" json:"mode"`
 }
 
 // CaseTransformationConfig defines case transformation rules
@@ -122,57 +228,126 @@ type CaseTransformationConfig struct{
 // KeyValidation defines validation rules specific to tag keys
 type KeyValidation struct {
 	// AllowedPrefixes is a list of valid prefixes for tag keys
-	AllowedPrefixes []string `yaml:"allowed_prefixes"`
+	AllowedPrefixes []string `yaml:"allowed_prefixes" json:"allowed_prefixes,omitempty"`
 
 	// AllowedSuffixes is a list of valid suffixes for tag keys
-	AllowedSuffixes []string `yaml:"allowed_suffixes"`
+	AllowedSuffixes []string `yaml:"allowed_suffixes" json:"allowed_suffixes,omitempty"`
 
 	// MaxLength specifies the maximum length allowed for tag keys
-	MaxLength int `yaml:"max_length"`
+	MaxLength int `yaml:"max_length" json:"max_length,omitempty"`
+
+	// DenyCaseDuplicates flags resources carrying tag keys that are equal ignoring case, such
+	// as Environment and environment, which AWS keeps as distinct tags
+	DenyCaseDuplicates bool `yaml:"deny_case_duplicates,omitempty" json:"deny_case_duplicates,omitempty"`
+}
+
+// ValidateTagKey checks a tag key against the key validation rules: it must start with one
+// of the allowed prefixes and end with one of the allowed suffixes when those lists are set,
+// and be at most MaxLength characters long when it is positive. Every broken rule is
+// reported, nil when the key satisfies them all.
+func (kv KeyValidation) ValidateTagKey(key string) []*KeyValidationError {
+	var errs []*KeyValidationError
+
+	if len(kv.AllowedPrefixes) > 0 && !slices.ContainsFunc(kv.AllowedPrefixes, func(prefix string) bool {
+		return strings.HasPrefix(key, prefix)
+	}) {
+		errs = append(errs, &KeyValidationError{TagKey: key, RuleKind: RuleKindKeyPrefix, Allowed: kv.AllowedPrefixes})
+	}
+
+	if len(kv.AllowedSuffixes) > 0 && !slices.ContainsFunc(kv.AllowedSuffixes, func(suffix string) bool {
+		return strings.HasSuffix(key, suffix)
+	}) {
+		errs = append(errs, &KeyValidationError{TagKey: key, RuleKind: RuleKindKeySuffix, Allowed: kv.AllowedSuffixes})
+	}
+
+	if kv.MaxLength > 0 && utf8.RuneCountInString(key) > kv.MaxLength {
+		errs = append(errs, &KeyValidationError{TagKey: key, RuleKind: RuleKindKeyLength, MaxLength: kv.MaxLength})
+	}
+
+	return errs
 }
 
 // ValueValidation defines validation rules specific to tag values
 type ValueValidation struct {
 	// AllowedCharacters specifies the regex pattern of allowed characters
-	AllowedCharacters string `yaml:"allowed_characters"`
+	AllowedCharacters string `yaml:"allowed_characters" json:"allowed_characters,omitempty"`
 
 	// DisallowedValues is a list of values that are not allowed
-	DisallowedValues []string `yaml:"disallowed_values"`
+	DisallowedValues []string `yaml:"disallowed_values" json:"disallowed_values,omitempty"`
+}
+
+// TagNormalization defines how tag keys are normalized before compliance evaluation
+type TagNormalization struct {
+	// Aliases maps alias tag keys to their canonical key (e.g. env: Environment)
+	Aliases map[string]string `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+
+	// LowercaseKeys lowercases every tag key, including aliases and canonical keys,
+	// before the aliases are resolved
+	LowercaseKeys bool `yaml:"lowercase_keys,omitempty" json:"lowercase_keys,omitempty"`
 }
 
 // TagValidation contains all tag validation rules
 type TagValidation struct {
-	AllowedValues map[string][]string `yaml:"allowed_values"`
-	PatternRules  map[string]string   `yaml:"pattern_rules"`
+	AllowedValues map[string][]string `yaml:"allowed_values" json:"allowed_values,omitempty"`
+	PatternRules  map[string]string   `yaml:"pattern_rules" json:"pattern_rules,omitempty"`
+
+	// DeprecatedValues lists the allowed values being retired, keyed by tag and value, along
+	// with the value replacing them, empty when none is given. Allowed values written as
+	// {value, deprecated, replacement} entries are recorded here.
+	DeprecatedValues map[string]map[string]string `yaml:"deprecated_values,omitempty" json:"deprecated_values,omitempty"`
+
+	// PatternRuleSeverities sets the severity of a value not matching its pattern rule,
+	// keyed by tag. Pattern rules written as {pattern, severity} entries are recorded here.
+	PatternRuleSeverities map[string]Severity `yaml:"pattern_rule_severities,omitempty" json:"pattern_rule_severities,omitempty"`
+
+	// PatternRuleExamples holds a value matching the pattern rule of a tag, suggested to fix
+	// values that do not match it. Pattern rules written with an example are recorded here.
+	PatternRuleExamples map[string]string `yaml:"pattern_rule_examples,omitempty" json:"pattern_rule_examples,omitempty"`
 
 	// Advanced case validation
-	CaseSensitivity map[string]CaseSensitivityConfig `yaml:"case_sensitivity"`
+	CaseSensitivity map[string]CaseSensitivityConfig `yaml:"case_sensitivity" json:"case_sensitivity,omitempty"`
 
 	// Maintain backwards compatibility with old case rules
-	CaseRules map[string]CaseRule `yaml:"case_rules,omitempty"`
+	CaseRules map[string]CaseRule `yaml:"case_rules,omitempty" json:"case_rules,omitempty"`
 
 	// New case transformation rules
-	CaseTransformations map[string]CaseTransformationConfig `yaml:"case_transformations,omitempty"`
+	CaseTransformations map[string]CaseTransformationConfig `yaml:"case_transformations,omitempty" json:"case_transformations,omitempty"`
 
 	// ProhibitedTags lists tag keys that are not allowed
-	ProhibitedTags []string `yaml:"prohibited_tags"`
+	ProhibitedTags []string `yaml:"prohibited_tags" json:"prohibited_tags,omitempty"`
 
 	// KeyFormatRules defines format rules for tag keys
-	KeyFormatRules []KeyFormatRule `yaml:"key_format_rules"`
+	KeyFormatRules []KeyFormatRule `yaml:"key_format_rules" json:"key_format_rules,omitempty"`
 
 	// LengthRules defines length constraints for tag values
-	LengthRules map[string]LengthRule `yaml:"length_rules"`
+	LengthRules map[string]LengthRule `yaml:"length_rules" json:"length_rules,omitempty"`
 
 	// KeyValidation contains validation rules specific to tag keys
-	KeyValidation KeyValidation `yaml:"key_validation"`
+	KeyValidation KeyValidation `yaml:"key_validation" json:"key_validation"`
 
 	// ValueValidation contains validation rules specific to tag values
-	ValueValidation ValueValidation `yaml:"value_validation"`
+	ValueValidation ValueValidation `yaml:"value_validation" json:"value_validation"`
+
+	// TagNormalization maps legacy tag keys to canonical keys before validation
+	TagNormalization TagNormalization `yaml:"tag_normalization,omitempty" json:"tag_normalization"`
+
+	// EnforceAWSLimits checks tags against the limits AWS enforces on every tag, see
+	// AWSLimitsEnforced. Left unset, the limits are enforced.
+	EnforceAWSLimits *bool `yaml:"enforce_aws_limits,omitempty" json:"enforce_aws_limits,omitempty"`
 
-	compiledRules map[string]*regexp.Regexp // Internal use for compiled patterns
+	// compiled holds the patterns compiled at load time, see CompilePatterns
+	compiled *CompiledTagPatterns
 }
 
-// ValidateTagCase validates a tag value against case sensitivity rules
+// AWSLimitsEnforced tells whether tags are checked against the key and value lengths, the
+// reserved aws: prefix and the character set AWS accepts, which is the case unless
+// enforce_aws_limits is set to false
+func (tv *TagValidation) AWSLimitsEnforced() bool {
+	return tv.EnforceAWSLimits == nil || *tv.EnforceAWSLimits
+}
+
+// ValidateTagCase validates a tag value against case sensitivity rules. A value failing a
+// rule is reported as a *ValidationError.
 func (tv *TagValidation) ValidateTagCase(tagName, value string) error {
 	// Check case sensitivity configuration
 	if caseSensitivity, exists := tv.CaseSensitivity[tagName]; exists {
@@ -180,7 +355,11 @@ func (tv *TagValidation) ValidateTagCase(tagName, value string) error {
 		case CaseValidationStrict:
 			// Check if the original value matches the allowed values
 			if !tv.isValueAllowed(tagName, value) {
-				return fmt.Errorf("tag %s value %s does not match strict case requirements", tagName, value)
+				return &ValidationError{
+					TagKey:      tagName,
+					RuleKind:    RuleKindCaseSensitivity,
+					ActualValue: value,
+				}
 			}
 		case CaseValidationRelaxed:
 			// Perform case-insensitive matching
@@ -192,13 +371,24 @@ func (tv *TagValidation) ValidateTagCase(tagName, value string) error {
 				}
 			}
 			if !found {
-				return fmt.Errorf("tag %s value %s is not in the allowed values", tagName, value)
+				return &ValidationError{
+					TagKey:      tagName,
+					RuleKind:    RuleKindCaseSensitivity,
+					ActualValue: value,
+				}
 			}
 		}
 	}
 
 	// Apply case transformations if specified
 	if caseRule, exists := tv.CaseRules[tagName]; exists {
+		caseErr := &ValidationError{
+			TagKey:            tagName,
+			RuleKind:          RuleKindCaseRule,
+			ConfiguredMessage: caseRule.Message,
+			ActualValue:       value,
+		}
+
 		var transformedValue string
 		switch caseRule.Case {
 		case CaseLowercase:
@@ -208,12 +398,13 @@ func (tv *TagValidation) ValidateTagCase(tagName, value string) error {
 		case CaseMixed:
 			// For mixed case, validate against pattern if provided
 			if caseRule.Pattern != "" {
-				matched, err := regexp.MatchString(caseRule.Pattern, value)
-				if err != nil {
+				patterns, err := tv.Patterns()
+				compiled, exists := patterns.CaseRule(tagName)
+				if !exists {
 					return fmt.Errorf("invalid mixed case pattern for tag %s: %w", tagName, err)
 				}
-				if !matched {
-					return fmt.Errorf(caseRule.Message)
+				if !compiled.MatchString(value) {
+					return caseErr
 				}
 			}
 			transformedValue = value
@@ -221,7 +412,7 @@ func (tv *TagValidation) ValidateTagCase(tagName, value string) error {
 
 		// Validate the transformed value
 		if transformedValue != value {
-			return fmt.Errorf(caseRule.Message)
+			return caseErr
 		}
 	}
 
@@ -247,58 +438,204 @@ func (tv *TagValidation) isValueAllowed(tagName, value string) bool {
 // tag inspection results through different channels.
 type NotificationConfig struct {
 	// Slack contains configuration for Slack notifications
-	Slack SlackNotificationConfig `yaml:"slack"`
+	Slack SlackNotificationConfig `yaml:"slack" json:"slack"`
 
 	// Email contains configuration for email notifications
-	Email EmailNotificationConfig `yaml:"email"`
+	Email EmailNotificationConfig `yaml:"email" json:"email"`
 
 	// Frequency determines how often notifications are sent
-	Frequency string `yaml:"frequency"`
+	Frequency string `yaml:"frequency" json:"frequency,omitempty"`
+}
+
+// ReportingConfig controls how results are rendered in the reports shared outside the team
+// running the checks
+type ReportingConfig struct {
+	// RedactTags lists the tag keys whose values are masked in every output, keys being
+	// matched case-insensitively. Compliance is still evaluated against the real values.
+	RedactTags []string `yaml:"redact_tags,omitempty" json:"redact_tags,omitempty"`
+
+	// BadgeThresholds sets the compliance percentages turning the compliance badge green and
+	// yellow, DefaultBadgeThresholds when unset
+	BadgeThresholds *BadgeThresholds `yaml:"badge_thresholds,omitempty" json:"badge_thresholds,omitempty"`
+}
+
+// Dimensions of the scope of a unique_value cross-resource rule: resources collide only when
+// they share the value of every dimension of the scope
+const (
+	ScopeAccount = "account"
+	ScopeRegion  = "region"
+	ScopeType    = "type"
+	ScopeVPC     = "vpc"
+
+	// ScopeTagPrefix prefixes the tag whose value is a dimension, e.g. tag:Environment
+	ScopeTagPrefix = "tag:"
+)
+
+// Built-in rule categories of the compliance checks, which disabled_rules turns off for the
+// resources of a type. The value_validation rules are not among them: compliance checks do
+// not enforce them, so there is nothing to disable.
+const (
+	RuleRequiredTags   = "required_tags"
+	RuleAllowedValues  = "allowed_values"
+	RulePatternRules   = "pattern_rules"
+	RuleCaseRules      = "case_rules"
+	RuleKeyFormat      = "key_format"
+	RuleKeyValidation  = "key_validation"
+	RuleProhibitedTags = "prohibited_tags"
+	RuleMaxTags        = "max_tags"
+)
+
+// DisableableRules lists the built-in rule categories disabled_rules accepts
+func DisableableRules() []string {
+	return []string{
+		RuleRequiredTags,
+		RuleAllowedValues,
+		RulePatternRules,
+		RuleCaseRules,
+		RuleKeyFormat,
+		RuleKeyValidation,
+		RuleProhibitedTags,
+		RuleMaxTags,
+	}
+}
+
+// CrossResourceRule is an assertion on the tags of several resources at once. UniqueValue is
+// the only type of rule so far.
+type CrossResourceRule struct {
+	UniqueValue *UniqueValueRule `yaml:"unique_value,omitempty" json:"unique_value,omitempty"`
+
+	// Severity weighs the violations of the rule in the compliance score, medium when unset
+	Severity Severity `yaml:"severity,omitempty" json:"severity,omitempty"`
+}
+
+// UniqueValueRule requires the resources of a scope to carry distinct values of a tag, such
+// as a unique Name among the security groups of a VPC
+type UniqueValueRule struct {
+	// Tag is the key of the tag whose values must be unique, matched ignoring case
+	Tag string `yaml:"tag" json:"tag"`
+
+	// Scope lists the dimensions resources must share to collide: account, region, type, vpc
+	// or tag:<key>. Values are unique across every resource when it is empty.
+	Scope []string `yaml:"scope,omitempty" json:"scope,omitempty"`
+}
+
+// IsValidScope reports whether a dimension of the scope of a unique_value rule is known
+func IsValidScope(dimension string) bool {
+	switch dimension {
+	case ScopeAccount, ScopeRegion, ScopeType, ScopeVPC:
+		return true
+	}
+	key, ok := strings.CutPrefix(dimension, ScopeTagPrefix)
+	return ok && key != ""
+}
+
+// BadgeThresholds are the minimum compliance percentages of the colors of the compliance
+// badge, lower percentages being red
+type BadgeThresholds struct {
+	Green  float64 `yaml:"green" json:"green"`
+	Yellow float64 `yaml:"yellow" json:"yellow"`
+}
+
+// DefaultBadgeThresholds are the badge thresholds of a configuration without badge_thresholds
+var DefaultBadgeThresholds = BadgeThresholds{Green: 95, Yellow: 80}
+
+// Badge returns the badge thresholds of the configuration, DefaultBadgeThresholds when unset
+func (r ReportingConfig) Badge() BadgeThresholds {
+	if r.BadgeThresholds == nil {
+		return DefaultBadgeThresholds
+	}
+	return *r.BadgeThresholds
 }
 
 // SlackNotificationConfig defines the configuration for Slack notifications,
 // including whether they are enabled and which channels to use.
 type SlackNotificationConfig struct {
 	// Enabled determines if Slack notifications are active
-	Enabled bool `yaml:"enabled"`
+	Enabled bool `yaml:"enabled" json:"enabled,omitempty"`
 
 	// Channels maps notification types to specific Slack channels
-	Channels map[string]string `yaml:"channels"`
+	Channels map[string]string `yaml:"channels" json:"channels,omitempty"`
 }
 
 // EmailNotificationConfig specifies the email notification settings,
 // including whether email notifications are enabled and the list of recipients.
 type EmailNotificationConfig struct {
 	// Enabled determines if email notifications are active
-	Enabled bool `yaml:"enabled"`
+	Enabled bool `yaml:"enabled" json:"enabled,omitempty"`
 
 	// Recipients is a list of email addresses to receive notifications
-	Recipients []string `yaml:"recipients"`
+	Recipients []string `yaml:"recipients" json:"recipients,omitempty"`
 
 	// Frequency determines how often email notifications are sent
-	Frequency string `yaml:"frequency"`
+	Frequency string `yaml:"frequency" json:"frequency,omitempty"`
 }
 
 // TagCriteria defines the criteria for validating resource tags in AWS.
 // It allows specifying required, forbidden, and specific tag requirements.
 type TagCriteria struct {
-	// MinimumRequiredTags specifies the minimum number of tags that must be present
-	MinimumRequiredTags int `yaml:"minimum_required_tags"`
+	// MinimumRequiredTags is how many of the RequiredTags must be present: any of them
+	// when lower than their count, e.g. any 2 of 5 ownership tags, all of them when equal or
+	// 0. It counts nothing without required tags, see RequiredTagsNeeded.
+	MinimumRequiredTags int `yaml:"minimum_required_tags" json:"minimum_required_tags"`
 
 	// RequiredTags is a list of tag keys that must be present on the resource
-	RequiredTags []string `yaml:"required_tags"`
+	RequiredTags []string `yaml:"required_tags" json:"required_tags,omitempty"`
+
+	// RequiredTagSeverities sets the severity of a missing required tag, keyed by tag.
+	// Required tags written as {name, severity} entries are recorded here.
+	RequiredTagSeverities map[string]Severity `yaml:"required_tag_severities,omitempty" json:"required_tag_severities,omitempty"`
+
+	// ForbiddenTags is a list of tag keys that must not be present on the resource.
+	// Resource-level forbidden tags add to the global ones.
+	ForbiddenTags []string `yaml:"forbidden_tags" json:"forbidden_tags,omitempty"`
 
-	// ForbiddenTags is a list of tag keys that must not be present on the resource
-	ForbiddenTags []string `yaml:"forbidden_tags"`
+	// ForbiddenTagsIgnoreCase matches forbidden tags regardless of the case of the tag keys,
+	// which are otherwise matched exactly
+	ForbiddenTagsIgnoreCase bool `yaml:"forbidden_tags_ignore_case,omitempty" json:"forbidden_tags_ignore_case,omitempty"`
 
 	// SpecificTags is a map of tag key-value pairs that must exactly match
-	SpecificTags map[string]string `yaml:"specific_tags"`
+	SpecificTags map[string]string `yaml:"specific_tags" json:"specific_tags,omitempty"`
 
 	// ComplianceLevel specifies the required compliance level for the resource
-	ComplianceLevel string `yaml:"compliance_level"`
+	ComplianceLevel string `yaml:"compliance_level" json:"compliance_level,omitempty"`
 
 	// MaxTags specifies the maximum number of tags allowed on a resource
-	MaxTags int `yaml:"max_tags"`
+	MaxTags int `yaml:"max_tags" json:"max_tags,omitempty"`
+
+	// Template names the entry of tag_criteria_templates these criteria are merged onto, the
+	// settings given here overriding those of the template. Only resources may use one.
+	Template string `yaml:"template,omitempty" json:"template,omitempty"`
+}
+
+// RequiredTagsNeeded returns how many of the required tags a resource must carry: all of
+// them, unless MinimumRequiredTags is set lower than their count, in which case any
+// MinimumRequiredTags of them will do
+func (c TagCriteria) RequiredTagsNeeded() int {
+	if c.MinimumRequiredTags > 0 && c.MinimumRequiredTags < len(c.RequiredTags) {
+		return c.MinimumRequiredTags
+	}
+	return len(c.RequiredTags)
+}
+
+// RequiresAnyOf reports whether only some of the required tags must be present, any
+// RequiredTagsNeeded of them
+func (c TagCriteria) RequiresAnyOf() bool {
+	return c.RequiredTagsNeeded() < len(c.RequiredTags)
+}
+
+// RequiredTagKeys returns the tag keys required by the global tag criteria, the tag criteria
+// of any resource type or any compliance level, sorted
+func (c *TaggyScanConfig) RequiredTagKeys() []string {
+	keys := slices.Clone(c.Global.TagCriteria.RequiredTags)
+	for _, resourceConfig := range c.Resources {
+		keys = append(keys, resourceConfig.TagCriteria.RequiredTags...)
+	}
+	for _, level := range c.ComplianceLevels {
+		keys = append(keys, level.RequiredTags...)
+	}
+
+	slices.Sort(keys)
+	return slices.Compact(keys)
 }
 
 // Update the ComplianceLevel type or validation if needed
@@ -319,21 +656,76 @@ func IsValidComplianceLevel(level string) bool {
 // AWSConfig defines the AWS-specific configuration for region scanning
 type AWSConfig struct {
 	// Regions configuration for scanning
-	Regions RegionsConfig `yaml:"regions"`
+	Regions RegionsConfig `yaml:"regions" json:"regions"`
 
 	// BatchSize specifies the number of resources to process in a single batch
 	// If not set, it will fall back to the global batch size or a system default
-	BatchSize *int `yaml:"batch_size,omitempty"`
+	BatchSize *int `yaml:"batch_size,omitempty" json:"batch_size,omitempty"`
+
+	// Workers specifies the number of workers processing the discovered resources of each
+	// resource type
+	// If not set, it will fall back to the global number of workers or a system default
+	Workers *int `yaml:"workers,omitempty" json:"workers,omitempty"`
+
+	// Accounts lists the AWS accounts to scan by assuming a role in each of them
+	// When set, only these accounts are scanned: the account of the default
+	// credentials must be listed as well to be included in the scan
+	// When empty, only the account of the default credentials is scanned
+	Accounts []AccountConfig `yaml:"accounts,omitempty" json:"accounts,omitempty"`
+
+	// Retries configures how AWS API calls failing with transient errors are retried
+	// If not set, the inspector defaults apply
+	Retries *RetryConfig `yaml:"retries,omitempty" json:"retries,omitempty"`
+
+	// EndpointURL, when set, sends every AWS API call to this endpoint instead of AWS, e.g.
+	// http://localhost:4566 for LocalStack. Static test credentials are used when no
+	// credentials are configured.
+	EndpointURL string `yaml:"endpoint_url,omitempty" json:"endpoint_url,omitempty"`
+
+	// MaxResourcesPerService caps the resources discovered for each resource type and
+	// account, the results being marked as truncated once the cap is hit
+	// If not set, every resource is discovered
+	MaxResourcesPerService *int `yaml:"max_resources_per_service,omitempty" json:"max_resources_per_service,omitempty"`
+
+	// MaxAPICalls caps the AWS API calls of a run across every resource type and account:
+	// once they are spent no further resource is discovered nor processed, and the results
+	// are marked as truncated
+	// If not set, API calls are not limited
+	MaxAPICalls *int `yaml:"max_api_calls,omitempty" json:"max_api_calls,omitempty"`
+}
+
+// RetryConfig controls the exponential backoff applied to throttled or transient AWS API errors
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of attempts per API call, including the first one
+	MaxAttempts int `yaml:"max_attempts,omitempty" json:"max_attempts,omitempty"`
+
+	// BaseDelay is the delay before the first retry, doubled on each following attempt (e.g. "500ms")
+	BaseDelay string `yaml:"base_delay,omitempty" json:"base_delay,omitempty"`
+
+	// MaxDelay caps the delay between two attempts (e.g. "20s")
+	MaxDelay string `yaml:"max_delay,omitempty" json:"max_delay,omitempty"`
+}
+
+// AccountConfig describes an AWS account scanned through STS AssumeRole
+type AccountConfig struct {
+	// AccountID is the 12-digit identifier of the account
+	AccountID string `yaml:"account_id" json:"account_id"`
+
+	// RoleARN is the ARN of the IAM role assumed to scan the account
+	RoleARN string `yaml:"role_arn" json:"role_arn"`
+
+	// ExternalID is passed to AssumeRole when the role trust policy requires it
+	ExternalID string `yaml:"external_id,omitempty" json:"external_id,omitempty"`
 }
 
 // RegionsConfig specifies how AWS regions should be scanned
 type RegionsConfig struct {
 	// Mode determines the region scanning strategy
 	// Can be 'all' to scan all regions or 'specific' to scan only listed regions
-	Mode string `yaml:"mode"`
+	Mode string `yaml:"mode" json:"mode"`
 
 	// List of specific regions to scan when Mode is 'specific'
-	List []string `yaml:"list,omitempty"`
+	List []string `yaml:"list,omitempty" json:"list,omitempty"`
 }
 
 // NormalizeAWSConfig ensures that AWS configuration has a valid configuration
@@ -356,7 +748,9 @@ func NormalizeAWSConfig(cfg *AWSConfig, globalCfg *GlobalConfig) {
 	}
 }
 
-// ValidAWSRegions provides a comprehensive list of valid AWS regions
+// ValidAWSRegions provides a comprehensive list of valid AWS regions of the commercial aws
+// partition, those scanned with mode all and --all-regions. GovCloud (US) and China regions
+// are valid too, see PartitionRegions, but must be listed explicitly.
 func ValidAWSRegions() []string {
 	return []string{
 		"us-east-1", "us-east-2", "us-west-1", "us-west-2",
@@ -370,34 +764,39 @@ func ValidAWSRegions() []string {
 	}
 }
 
-// IsValidRegion checks if a given region is valid
+// ValidEC2InstanceStates lists the states of EC2 instances
+func ValidEC2InstanceStates() []string {
+	return []string{"pending", "running", "shutting-down", "terminated", "stopping", "stopped"}
+}
+
+// DefaultEC2InstanceStates lists the states of the EC2 instances inspected by default, leaving
+// out terminated instances that are about to disappear
+func DefaultEC2InstanceStates() []string {
+	return []string{"running", "stopped"}
+}
+
+// IsValidRegion checks if a given region is valid, in any AWS partition
 func IsValidRegion(region string) bool {
-	validRegions := ValidAWSRegions()
-	for _, validRegion := range validRegions {
-		if region == validRegion {
-			return true
-		}
-	}
-	return false
+	return slices.Contains(PartitionRegions(PartitionOfRegion(region)), region)
 }
 
 // KeyFormatRule defines format requirements for tag keys
 type KeyFormatRule struct {
 	// Pattern is a regex pattern that tag keys must match
-	Pattern string `yaml:"pattern"`
+	Pattern string `yaml:"pattern" json:"pattern"`
 
 	// Message provides a description of the format requirement
-	Message string `yaml:"message"`
+	Message string `yaml:"message" json:"message,omitempty"`
 }
 
 // LengthRule defines length constraints for tag values
 type LengthRule struct {
 	// MinLength specifies the minimum length allowed
-	MinLength *int `yaml:"min_length,omitempty"`
+	MinLength *int `yaml:"min_length,omitempty" json:"min_length,omitempty"`
 
 	// MaxLength specifies the maximum length allowed
-	MaxLength *int `yaml:"max_length,omitempty"`
+	MaxLength *int `yaml:"max_length,omitempty" json:"max_length,omitempty"`
 
 	// Message provides a description of the length requirement
-	Message string `yaml:"message,omitempty"`
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
 }
@@ -1,9 +1,11 @@
 package configuration
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIsValidComplianceLevel(t *testing.T) {
@@ -61,6 +63,9 @@ func TestIsValidRegion(t *testing.T) {
 		{"Valid US East Region", "us-east-1", true},
 		{"Valid EU West Region", "eu-west-1", true},
 		{"Valid Asia Pacific Region", "ap-southeast-1", true},
+		{"Valid GovCloud Region", "us-gov-west-1", true},
+		{"Valid China Region", "cn-north-1", true},
+		{"Invalid GovCloud Region", "us-gov-north-9", false},
 		{"Invalid Region", "invalid-region", false},
 		{"Empty Region", "", false},
 		{"Case Sensitive Region", "US-EAST-1", false},
@@ -187,3 +192,178 @@ func TestAWSConfigScenarios(t *testing.T) {
 		assert.Contains(t, cfg.Regions.List, "eu-west-1")
 	})
 }
+
+func TestValidateTagCase(t *testing.T) {
+	validation := &TagValidation{
+		AllowedValues: map[string][]string{"Environment": {"prod", "staging"}},
+		CaseSensitivity: map[string]CaseSensitivityConfig{
+			"Environment": {Mode: CaseValidationStrict},
+		},
+		CaseRules: map[string]CaseRule{
+			"Team": {Case: CaseLowercase, Message: "Value must be 100% lowercase"},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		tag           string
+		value         string
+		expectedError *ValidationError
+		expectedText  string
+	}{
+		{name: "Valid Value", tag: "Environment", value: "prod"},
+		{
+			name:          "Strict Case Mismatch",
+			tag:           "Environment",
+			value:         "Prod",
+			expectedError: &ValidationError{TagKey: "Environment", RuleKind: RuleKindCaseSensitivity, ActualValue: "Prod"},
+			expectedText:  `tag Environment value "Prod" does not satisfy the case_sensitivity rule`,
+		},
+		{
+			name:  "Configured Message With Percent Sign",
+			tag:   "Team",
+			value: "Payments",
+			expectedError: &ValidationError{
+				TagKey:            "Team",
+				RuleKind:          RuleKindCaseRule,
+				ConfiguredMessage: "Value must be 100% lowercase",
+				ActualValue:       "Payments",
+			},
+			expectedText: `tag Team value "Payments": Value must be 100% lowercase`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validation.ValidateTagCase(tc.tag, tc.value)
+			if tc.expectedError == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr))
+			assert.Equal(t, tc.expectedError, validationErr)
+			assert.Equal(t, tc.expectedText, err.Error())
+		})
+	}
+}
+
+func TestValidateTagKey(t *testing.T) {
+	t.Parallel()
+
+	keyValidation := KeyValidation{
+		AllowedPrefixes: []string{"env-", "dept-"},
+		AllowedSuffixes: []string{"-prod", "-dev"},
+		MaxLength:       16,
+	}
+
+	testCases := []struct {
+		name         string
+		key          string
+		expected     []*KeyValidationError
+		expectedText []string
+	}{
+		{name: "Valid Key", key: "env-app-prod"},
+		{
+			name:         "Missing Prefix",
+			key:          "team-prod",
+			expected:     []*KeyValidationError{{TagKey: "team-prod", RuleKind: RuleKindKeyPrefix, Allowed: []string{"env-", "dept-"}}},
+			expectedText: []string{`tag key "team-prod" must start with one of: env-, dept-`},
+		},
+		{
+			name:         "Missing Suffix",
+			key:          "dept-finance",
+			expected:     []*KeyValidationError{{TagKey: "dept-finance", RuleKind: RuleKindKeySuffix, Allowed: []string{"-prod", "-dev"}}},
+			expectedText: []string{`tag key "dept-finance" must end with one of: -prod, -dev`},
+		},
+		{
+			name: "Every Broken Rule",
+			key:  "application-owner",
+			expected: []*KeyValidationError{
+				{TagKey: "application-owner", RuleKind: RuleKindKeyPrefix, Allowed: []string{"env-", "dept-"}},
+				{TagKey: "application-owner", RuleKind: RuleKindKeySuffix, Allowed: []string{"-prod", "-dev"}},
+				{TagKey: "application-owner", RuleKind: RuleKindKeyLength, MaxLength: 16},
+			},
+			expectedText: []string{
+				`tag key "application-owner" must start with one of: env-, dept-`,
+				`tag key "application-owner" must end with one of: -prod, -dev`,
+				`tag key "application-owner" is longer than 16 characters`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			errs := keyValidation.ValidateTagKey(tc.key)
+			assert.Equal(t, tc.expected, errs)
+
+			var texts []string
+			for _, err := range errs {
+				texts = append(texts, err.Error())
+			}
+			assert.Equal(t, tc.expectedText, texts)
+		})
+	}
+
+	assert.Empty(t, KeyValidation{}.ValidateTagKey("anything"))
+}
+
+func TestResourceConfigRoleAccount(t *testing.T) {
+	t.Parallel()
+
+	_, ok := ResourceConfig{Enabled: true}.RoleAccount()
+	assert.False(t, ok)
+
+	account, ok := ResourceConfig{
+		RoleARN:    "arn:aws:iam::333333333333:role/aws-taggy-readonly",
+		ExternalID: "ext",
+	}.RoleAccount()
+	assert.True(t, ok)
+	assert.Equal(t, AccountConfig{
+		AccountID:  "333333333333",
+		RoleARN:    "arn:aws:iam::333333333333:role/aws-taggy-readonly",
+		ExternalID: "ext",
+	}, account)
+}
+
+func TestRequiredTagKeys(t *testing.T) {
+	cfg := &TaggyScanConfig{
+		Global: GlobalConfig{TagCriteria: TagCriteria{RequiredTags: []string{"Owner", "Environment"}}},
+		Resources: map[string]ResourceConfig{
+			"s3":  {TagCriteria: TagCriteria{RequiredTags: []string{"DataClassification", "Owner"}}},
+			"ec2": {},
+		},
+		ComplianceLevels: map[string]ComplianceLevel{
+			"high": {RequiredTags: []string{"CostCenter", "Owner"}},
+		},
+	}
+
+	assert.Equal(t, []string{"CostCenter", "DataClassification", "Environment", "Owner"}, cfg.RequiredTagKeys())
+	assert.Empty(t, (&TaggyScanConfig{}).RequiredTagKeys())
+}
+
+func TestTagCriteria_RequiredTagsNeeded(t *testing.T) {
+	requiredTags := []string{"Owner", "Team", "Contact"}
+	testCases := []struct {
+		name       string
+		criteria   TagCriteria
+		wantNeeded int
+		wantAnyOf  bool
+	}{
+		{name: "Zero Requires All", criteria: TagCriteria{RequiredTags: requiredTags}, wantNeeded: 3},
+		{name: "Lower Requires Any", criteria: TagCriteria{MinimumRequiredTags: 2, RequiredTags: requiredTags}, wantNeeded: 2, wantAnyOf: true},
+		{name: "Equal Requires All", criteria: TagCriteria{MinimumRequiredTags: 3, RequiredTags: requiredTags}, wantNeeded: 3},
+		{name: "Greater Requires All", criteria: TagCriteria{MinimumRequiredTags: 4, RequiredTags: requiredTags}, wantNeeded: 3},
+		{name: "No Required Tags", criteria: TagCriteria{MinimumRequiredTags: 2}, wantNeeded: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantNeeded, tc.criteria.RequiredTagsNeeded())
+			assert.Equal(t, tc.wantAnyOf, tc.criteria.RequiresAnyOf())
+		})
+	}
+}
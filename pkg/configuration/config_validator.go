@@ -3,9 +3,16 @@
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"net/url"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/Excoriate/aws-taggy/pkg/arn"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
 	"github.com/Excoriate/aws-taggy/pkg/util"
 	"github.com/xeipuuv/gojsonschema"
 )
@@ -47,7 +54,7 @@ func (v *FileValidator) Validate() error {
 		return fmt.Errorf("configuration file does not exist: %w", err)
 	}
 
-	if err := util.FileHasExtension(absPath, ".yaml"); err != nil {
+	if _, err := DetectConfigFormat(absPath); err != nil {
 		return fmt.Errorf("configuration file has invalid extension: %w", err)
 	}
 
@@ -58,41 +65,40 @@ func (v *FileValidator) Validate() error {
 	return nil
 }
 
-// ValidateContent performs comprehensive validation of the configuration content
+// ValidateContent performs comprehensive validation of the configuration content. Every
+// check runs even when an earlier one fails, so the returned error is a ValidationErrors
+// listing all the problems found.
 func (v *ContentValidator) ValidateContent() error {
-	if err := v.validateAgainstSchema(); err != nil {
-		return fmt.Errorf("schema validation failed: %w", err)
-	}
-
-	if err := v.validateVersion(); err != nil {
-		return fmt.Errorf("version validation failed: %w", err)
-	}
-
-	if err := v.validateAWSConfig(); err != nil {
-		return fmt.Errorf("AWS configuration validation failed: %w", err)
-	}
-
-	if err := v.validateGlobalConfig(); err != nil {
-		return fmt.Errorf("global configuration validation failed: %w", err)
-	}
-
-	if err := v.validateResourceConfigs(); err != nil {
-		return fmt.Errorf("resource configuration validation failed: %w", err)
-	}
-
-	if err := v.validateComplianceLevels(); err != nil {
-		return fmt.Errorf("compliance levels validation failed: %w", err)
-	}
-
-	if err := v.validateTagValidation(); err != nil {
-		return fmt.Errorf("tag validation configuration failed: %w", err)
-	}
-
-	if err := v.validateNotifications(); err != nil {
-		return fmt.Errorf("notifications configuration failed: %w", err)
+	var issues ValidationErrors
+
+	for _, validate := range []func() error{
+		v.validateVersion,
+		v.validateAWSConfig,
+		v.validateGlobalConfig,
+		v.validateResourceConfigs,
+		v.validateTagCriteriaTemplates,
+		v.validateComplianceLevels,
+		v.validateTagValidation,
+		v.validateNotifications,
+		v.validateReporting,
+		v.validateCrossResourceRules,
+	} {
+		issues.merge(validate())
+	}
+
+	// The schema runs last and only adds the problems the checks above did not report,
+	// so a setting breaking both is listed once with the more descriptive message
+	reported := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		reported[issue.Path] = true
+	}
+	for _, issue := range Issues(v.validateAgainstSchema()) {
+		if issue.Path == "" || !reported[issue.Path] {
+			issues = append(issues, issue)
+		}
 	}
 
-	return nil
+	return issues.err()
 }
 
 func (v *ContentValidator) validateAgainstSchema() error {
@@ -107,7 +113,7 @@ func (v *ContentValidator) validateAgainstSchema() error {
 
 	// If version is empty, add a default version
 	if v.cfg.Version == "" {
-		v.cfg.Version = "1.0"
+		v.cfg.Version = unversionedConfigVersion
 	}
 
 	documentLoader := gojsonschema.NewBytesLoader(configJSON)
@@ -118,21 +124,18 @@ func (v *ContentValidator) validateAgainstSchema() error {
 		return fmt.Errorf("schema validation failed: %w", err)
 	}
 
-	if !result.Valid() {
-		var errors []string
-		for _, err := range result.Errors() {
-			errors = append(errors, err.String())
-		}
-		return fmt.Errorf("configuration does not match schema: %s", strings.Join(errors, "; "))
+	var issues ValidationErrors
+	for _, schemaErr := range result.Errors() {
+		issues.add(schemaErr.Field(), "does not match schema: %s", schemaErr.Description())
 	}
 
-	return nil
+	return issues.err()
 }
 
 func (v *ContentValidator) validateVersion() error {
 	// If version is empty, set a default version
 	if v.cfg.Version == "" {
-		v.cfg.Version = "1.0"
+		v.cfg.Version = unversionedConfigVersion
 		return nil
 	}
 
@@ -141,62 +144,192 @@ func (v *ContentValidator) validateVersion() error {
 
 	versionPattern := regexp.MustCompile(`^\d+\.\d+$`)
 	if !versionPattern.MatchString(version) {
-		return fmt.Errorf("invalid version format: %s, expected format: X.Y", version)
+		var issues ValidationErrors
+		issues.add("version", "invalid version format: %s, expected format: X.Y", version)
+		return issues.err()
+	}
+
+	if comparison, _ := compareConfigVersions(version, constants.SupportedConfigVersion); comparison > 0 {
+		var issues ValidationErrors
+		issues.add("version", "version %s is newer than the supported version %s", version, constants.SupportedConfigVersion)
+		return issues.err()
 	}
 
 	return nil
 }
 
 func (v *ContentValidator) validateAWSConfig() error {
-	if v.cfg.AWS.Regions.Mode == "" {
-		return fmt.Errorf("AWS regions mode is required")
+	var issues ValidationErrors
+
+	switch v.cfg.AWS.Regions.Mode {
+	case "":
+		issues.add("aws.regions.mode", "AWS regions mode is required")
+	case "all":
+	case "specific":
+		if len(v.cfg.AWS.Regions.List) == 0 {
+			issues.add("aws.regions.list", "specific AWS regions mode requires at least one region")
+		}
+	default:
+		issues.add("aws.regions.mode", "invalid AWS regions mode: %s, expected: all or specific", v.cfg.AWS.Regions.Mode)
 	}
 
-	if v.cfg.AWS.Regions.Mode != "all" && v.cfg.AWS.Regions.Mode != "specific" {
-		return fmt.Errorf("invalid AWS regions mode: %s, expected: all or specific", v.cfg.AWS.Regions.Mode)
+	if v.cfg.AWS.BatchSize != nil && *v.cfg.AWS.BatchSize < 1 {
+		issues.add("aws.batch_size", "AWS batch size must be greater than 0")
 	}
 
-	if v.cfg.AWS.Regions.Mode == "specific" && len(v.cfg.AWS.Regions.List) == 0 {
-		return fmt.Errorf("specific AWS regions mode requires at least one region")
+	if v.cfg.AWS.Workers != nil && *v.cfg.AWS.Workers < 1 {
+		issues.add("aws.workers", "AWS workers must be greater than 0")
 	}
 
-	if v.cfg.AWS.BatchSize != nil && *v.cfg.AWS.BatchSize < 1 {
-		return fmt.Errorf("AWS batch size must be greater than 0")
+	if v.cfg.AWS.MaxResourcesPerService != nil && *v.cfg.AWS.MaxResourcesPerService < 1 {
+		issues.add("aws.max_resources_per_service", "AWS max_resources_per_service must be greater than 0")
 	}
 
-	return nil
+	if v.cfg.AWS.MaxAPICalls != nil && *v.cfg.AWS.MaxAPICalls < 1 {
+		issues.add("aws.max_api_calls", "AWS max_api_calls must be greater than 0")
+	}
+
+	v.validateAccounts(&issues)
+	v.validateRetries(&issues)
+	v.validateEndpointURL(&issues)
+
+	return issues.err()
+}
+
+func (v *ContentValidator) validateEndpointURL(issues *ValidationErrors) {
+	endpointURL := v.cfg.AWS.EndpointURL
+	if endpointURL == "" {
+		return
+	}
+
+	parsed, err := url.Parse(endpointURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		issues.add("aws.endpoint_url", "AWS endpoint_url must be an absolute http(s) URL, got %q", endpointURL)
+	}
+}
+
+func (v *ContentValidator) validateRetries(issues *ValidationErrors) {
+	retries := v.cfg.AWS.Retries
+	if retries == nil {
+		return
+	}
+
+	if retries.MaxAttempts < 0 {
+		issues.add("aws.retries.max_attempts", "AWS retries max_attempts must not be negative")
+	}
+
+	var baseDelay, maxDelay time.Duration
+	for _, field := range []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{name: "base_delay", value: retries.BaseDelay, dest: &baseDelay},
+		{name: "max_delay", value: retries.MaxDelay, dest: &maxDelay},
+	} {
+		if field.value == "" {
+			continue
+		}
+
+		path := "aws.retries." + field.name
+		delay, err := time.ParseDuration(field.value)
+		if err != nil {
+			issues.add(path, "AWS retries %s is not a valid duration: %s", field.name, err)
+			continue
+		}
+		if delay <= 0 {
+			issues.add(path, "AWS retries %s must be positive", field.name)
+			continue
+		}
+		*field.dest = delay
+	}
+
+	if baseDelay > 0 && maxDelay > 0 && baseDelay > maxDelay {
+		issues.add("aws.retries.base_delay", "AWS retries base_delay must not exceed max_delay")
+	}
+}
+
+// accountIDPattern matches the 12-digit ID of an AWS account
+var accountIDPattern = regexp.MustCompile(`^\d{12}$`)
+
+// isValidRoleARN reports whether roleARN is the ARN of an IAM role of an account. Parsing the
+// ARN accepts every partition (aws, aws-us-gov, aws-cn).
+func isValidRoleARN(roleARN string) bool {
+	parsed, err := arn.Parse(roleARN)
+	return err == nil && parsed.Service == "iam" && strings.HasPrefix(parsed.Resource, "role/") &&
+		accountIDPattern.MatchString(parsed.AccountID)
+}
+
+func (v *ContentValidator) validateAccounts(issues *ValidationErrors) {
+	seen := make(map[string]bool)
+
+	for i, account := range v.cfg.AWS.Accounts {
+		path := fmt.Sprintf("aws.accounts[%d]", i)
+
+		if !accountIDPattern.MatchString(account.AccountID) {
+			issues.add(path+".account_id", "AWS account %d has invalid account_id %q, expected a 12-digit account ID", i, account.AccountID)
+		} else if seen[account.AccountID] {
+			issues.add(path+".account_id", "AWS account %s is declared more than once", account.AccountID)
+		}
+		seen[account.AccountID] = true
+
+		if !isValidRoleARN(account.RoleARN) {
+			issues.add(path+".role_arn", "AWS account %s has invalid role_arn %q", account.AccountID, account.RoleARN)
+		}
+	}
 }
 
 func (v *ContentValidator) validateGlobalConfig() error {
+	var issues ValidationErrors
+
 	if v.cfg.Global.BatchSize != nil && *v.cfg.Global.BatchSize <= 0 {
-		return fmt.Errorf("global batch size must be positive")
+		issues.add("global.batch_size", "global batch size must be positive")
 	}
 
-	if err := v.validateTagCriteria(v.cfg.Global.TagCriteria, "global"); err != nil {
-		return err
+	if v.cfg.Global.Workers != nil && *v.cfg.Global.Workers <= 0 {
+		issues.add("global.workers", "global workers must be positive")
 	}
 
-	return nil
+	if v.cfg.Global.MaxConcurrency != nil && *v.cfg.Global.MaxConcurrency <= 0 {
+		issues.add("global.max_concurrency", "global max concurrency must be positive")
+	}
+
+	v.validateTagCriteria(&issues, v.cfg.Global.TagCriteria, "global", "global.tag_criteria")
+
+	if v.cfg.Global.TagCriteria.Template != "" {
+		issues.add("global.tag_criteria.template", "global tag criteria cannot use a template, only resources can")
+	}
+
+	return issues.err()
 }
 
-func (v *ContentValidator) validateTagCriteria(criteria TagCriteria, context string) error {
+func (v *ContentValidator) validateTagCriteria(issues *ValidationErrors, criteria TagCriteria, context, path string) {
 	if criteria.MinimumRequiredTags < 0 {
-		return fmt.Errorf("%s minimum required tags cannot be negative", context)
+		issues.add(path+".minimum_required_tags", "%s minimum required tags cannot be negative", context)
 	}
 
+	// A minimum lower than the number of required tags requires any of them, equal all of them
 	if len(criteria.RequiredTags) > 0 && criteria.MinimumRequiredTags > len(criteria.RequiredTags) {
-		return fmt.Errorf("%s minimum required tags (%d) cannot exceed number of required tags (%d)",
-			context, criteria.MinimumRequiredTags, len(criteria.RequiredTags))
+		issues.add(path+".minimum_required_tags", "%s minimum required tags (%d) cannot exceed the number of required tags (%d) it counts present, set it to %d or 0 to require all of them",
+			context, criteria.MinimumRequiredTags, len(criteria.RequiredTags), len(criteria.RequiredTags))
 	}
 
 	if criteria.ComplianceLevel != "" && !v.isValidComplianceLevel(criteria.ComplianceLevel) {
-		return fmt.Errorf("%s invalid compliance level: %s", context, criteria.ComplianceLevel)
+		issues.add(path+".compliance_level", "%s invalid compliance level: %s", context, criteria.ComplianceLevel)
 	}
 
-	return nil
+	for _, tag := range slices.Sorted(maps.Keys(criteria.RequiredTagSeverities)) {
+		severityPath := path + ".required_tag_severities." + tag
+		if !slices.Contains(criteria.RequiredTags, tag) {
+			issues.add(severityPath, "%s severity set for tag %s, which is not required", context, tag)
+		} else if severity := criteria.RequiredTagSeverities[tag]; !severity.IsValid() {
+			issues.add(severityPath, "%s invalid severity for required tag %s: %s", context, tag, severity)
+		}
+	}
 }
 
-// validateResourceType checks if the resource type is a supported AWS resource
+// validateResourceType checks if the resource type is a supported AWS resource or has a
+// registered inspector
 func (v *ContentValidator) validateResourceType(resourceType string) error {
 	supportedResources := map[string]bool{
 		"ec2":             true,
@@ -224,9 +357,17 @@ func (v *ContentValidator) validateResourceType(resourceType string) error {
 		"nacl":            true,
 		"internetgateway": true,
 		"natgateway":      true,
+
+		// Any other taggable resource, through the Resource Groups Tagging API
+		constants.ResourceTypeGeneric: true,
 	}
 
-	if !supportedResources[resourceType] {
+	if !supportedResources[resourceType] && !IsRegisteredResourceType(resourceType) {
+		// Aliases, such as cloudwatch_alarms, are accepted by the CLI flags but configuration
+		// keys must be the resource type itself
+		if normalized := NormalizeResourceType(resourceType); normalized != resourceType && supportedResources[normalized] {
+			return fmt.Errorf("unsupported AWS resource type: %s, configure it as %s", resourceType, normalized)
+		}
 		return fmt.Errorf("unsupported AWS resource type: %s", resourceType)
 	}
 
@@ -235,189 +376,462 @@ func (v *ContentValidator) validateResourceType(resourceType string) error {
 
 // validateResourceConfigs performs validation of resource configurations
 func (v *ContentValidator) validateResourceConfigs() error {
-	for resourceType, config := range v.cfg.Resources {
+	var issues ValidationErrors
+
+	for _, resourceType := range slices.Sorted(maps.Keys(v.cfg.Resources)) {
+		config := v.cfg.Resources[resourceType]
+		path := "resources." + resourceType
+
 		if err := v.validateResourceType(resourceType); err != nil {
-			return err
+			issues.add(path, "%s", err)
+			continue
+		}
+
+		// Criteria using a template are judged once merged onto it
+		criteria, err := v.cfg.ResolveTagCriteria(config.TagCriteria)
+		if err != nil {
+			issues.add(path+".tag_criteria.template", "resource %s: %s", resourceType, err)
+			continue
 		}
 
 		if !config.Enabled {
 			continue
 		}
 
-		if err := v.validateTagCriteria(config.TagCriteria, fmt.Sprintf("resource %s", resourceType)); err != nil {
-			return err
+		v.validateTagCriteria(&issues, criteria, fmt.Sprintf("resource %s", resourceType), path+".tag_criteria")
+
+		if override := config.TagValidation; override != nil {
+			switch override.Mode {
+			case "", TagValidationOverrideAdd, TagValidationOverrideReplace:
+			default:
+				issues.add(path+".tag_validation.mode", "resource %s has invalid tag validation mode %s, expected %s or %s",
+					resourceType, override.Mode, TagValidationOverrideAdd, TagValidationOverrideReplace)
+			}
+			v.validateValueRules(&issues, override.rules(), v.cfg.ResourceTagValidation(resourceType), path+".tag_validation")
 		}
 
 		// Validate resource-specific compliance level against defined levels
-		if config.TagCriteria.ComplianceLevel != "" {
-			if _, exists := v.cfg.ComplianceLevels[config.TagCriteria.ComplianceLevel]; !exists {
-				return fmt.Errorf("resource %s references undefined compliance level: %s",
-					resourceType, config.TagCriteria.ComplianceLevel)
+		if criteria.ComplianceLevel != "" {
+			if _, exists := v.cfg.ComplianceLevels[criteria.ComplianceLevel]; !exists {
+				issues.add(path+".tag_criteria.compliance_level", "resource %s references undefined compliance level: %s",
+					resourceType, criteria.ComplianceLevel)
 			}
 		}
 
-		for _, excluded := range config.ExcludedResources {
+		for i, excluded := range config.ExcludedResources {
+			patternPath := fmt.Sprintf("%s.excluded_resources[%d].pattern", path, i)
 			if excluded.Pattern == "" {
-				return fmt.Errorf("resource %s has empty exclusion pattern", resourceType)
+				issues.add(patternPath, "resource %s has empty exclusion pattern", resourceType)
+				continue
 			}
 			if _, err := regexp.Compile(excluded.Pattern); err != nil {
-				return fmt.Errorf("resource %s has invalid exclusion pattern: %s", resourceType, err)
+				issues.add(patternPath, "resource %s has invalid exclusion pattern: %s", resourceType, err)
+			}
+		}
+
+		if config.BatchSize != nil && *config.BatchSize <= 0 {
+			issues.add(path+".batch_size", "resource %s batch size must be positive", resourceType)
+		}
+
+		if config.Workers != nil && *config.Workers <= 0 {
+			issues.add(path+".workers", "resource %s workers must be positive", resourceType)
+		}
+
+		if config.RateLimit != nil && *config.RateLimit <= 0 {
+			issues.add(path+".rate_limit", "resource %s rate limit must be positive", resourceType)
+		}
+
+		if len(config.ResourceTypeFilters) > 0 && resourceType != constants.ResourceTypeGeneric {
+			issues.add(path+".resource_type_filters", "resource %s does not support resource type filters, only %s does",
+				resourceType, constants.ResourceTypeGeneric)
+		}
+
+		if len(config.IncludeInstanceStates) > 0 && resourceType != constants.ResourceTypeEC2 {
+			issues.add(path+".include_instance_states", "resource %s does not support instance states, only %s does",
+				resourceType, constants.ResourceTypeEC2)
+		}
+		if config.IncludeSnapshots && resourceType != constants.ResourceTypeEBS {
+			issues.add(path+".include_snapshots", "resource %s does not support snapshots, only %s does",
+				resourceType, constants.ResourceTypeEBS)
+		}
+		if len(config.BucketRegions) > 0 && resourceType != constants.ResourceTypeS3 {
+			issues.add(path+".bucket_regions", "resource %s does not support bucket regions, only %s does",
+				resourceType, constants.ResourceTypeS3)
+		}
+		for i, region := range config.BucketRegions {
+			if !IsValidRegion(region) {
+				issues.add(fmt.Sprintf("%s.bucket_regions[%d]", path, i), "invalid bucket region: %s", region)
+			}
+		}
+		if config.RoleARN != "" && !isValidRoleARN(config.RoleARN) {
+			issues.add(path+".role_arn", "resource %s has invalid role_arn %q, expected the ARN of an IAM role", resourceType, config.RoleARN)
+		}
+		if config.ExternalID != "" && config.RoleARN == "" {
+			issues.add(path+".external_id", "resource %s sets external_id without role_arn", resourceType)
+		}
+		for i, state := range config.IncludeInstanceStates {
+			if !slices.Contains(ValidEC2InstanceStates(), state) {
+				issues.add(fmt.Sprintf("%s.include_instance_states[%d]", path, i), "invalid instance state: %s, valid states are: %s",
+					state, strings.Join(ValidEC2InstanceStates(), ", "))
+			}
+		}
+		for i, rule := range config.DisabledRules {
+			rulePath := fmt.Sprintf("%s.disabled_rules[%d]", path, i)
+			switch {
+			case !slices.Contains(DisableableRules(), rule):
+				issues.add(rulePath, "unknown rule: %s, valid rules are: %s", rule, strings.Join(DisableableRules(), ", "))
+			case slices.Index(config.DisabledRules, rule) < i:
+				issues.add(rulePath, "resource %s disables rule %s more than once", resourceType, rule)
 			}
 		}
 	}
 
-	return nil
+	return issues.err()
+}
+
+// validateTagCriteriaTemplates checks the tag criteria templates, which cannot use another
+// template themselves
+func (v *ContentValidator) validateTagCriteriaTemplates() error {
+	var issues ValidationErrors
+
+	for _, name := range slices.Sorted(maps.Keys(v.cfg.TagCriteriaTemplates)) {
+		template := v.cfg.TagCriteriaTemplates[name]
+		path := "tag_criteria_templates." + name
+
+		if template.Template != "" {
+			issues.add(path+".template", "tag criteria template %s references template %s, templates cannot be nested",
+				name, template.Template)
+		}
+
+		v.validateTagCriteria(&issues, template, fmt.Sprintf("template %s", name), path)
+	}
+
+	return issues.err()
 }
 
 func (v *ContentValidator) validateComplianceLevels() error {
+	var issues ValidationErrors
 	validLevels := map[string]bool{"high": true, "medium": true, "low": true, "standard": true}
 
-	for level, config := range v.cfg.ComplianceLevels {
+	for _, level := range slices.Sorted(maps.Keys(v.cfg.ComplianceLevels)) {
+		config := v.cfg.ComplianceLevels[level]
+		path := "compliance_levels." + level
+
 		if !validLevels[level] {
-			return fmt.Errorf("invalid compliance level: %s", level)
+			issues.add(path, "invalid compliance level: %s", level)
+			continue
+		}
+
+		// A level extending another one may only add to it, so judge its resolved requirements
+		if config.Extends != "" {
+			resolved, err := v.cfg.ResolveComplianceLevel(level)
+			if err != nil {
+				issues.add(path+".extends", "%s", err)
+				continue
+			}
+			config = resolved
 		}
 
 		if len(config.RequiredTags) == 0 && len(config.SpecificTags) == 0 {
-			return fmt.Errorf("compliance level %s must define either required tags or specific tags", level)
+			issues.add(path, "compliance level %s must define either required tags or specific tags", level)
 		}
 	}
 
-	return nil
+	return issues.err()
 }
 
 func (v *ContentValidator) validateTagValidation() error {
-	if v.cfg.TagValidation.CaseRules != nil {
-		for tag, rule := range v.cfg.TagValidation.CaseRules {
-			if rule.Case == "" {
-				return fmt.Errorf("case rule for tag %s must specify case type", tag)
-			}
-			if !v.isValidCaseType(rule.Case) {
-				return fmt.Errorf("invalid case type for tag %s: %s", tag, rule.Case)
-			}
-			if rule.Pattern != "" {
-				if _, err := regexp.Compile(rule.Pattern); err != nil {
-					return fmt.Errorf("invalid pattern for tag %s: %s", tag, err)
-				}
+	var issues ValidationErrors
+	tagValidation := v.cfg.TagValidation
+
+	v.validateValueRules(&issues, tagValidation, tagValidation, "tag_validation")
+	v.validateKeyValidation(&issues)
+	v.validateValueValidation(&issues)
+
+	for i, rule := range tagValidation.KeyFormatRules {
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			issues.add(fmt.Sprintf("tag_validation.key_format_rules[%d].pattern", i), "invalid key format pattern: %s", err)
+		}
+	}
+
+	v.validateLengthRules(&issues)
+	v.validateTagNormalization(&issues)
+
+	return issues.err()
+}
+
+// validateValueRules checks the case rules, pattern rules and allowed values of rules found
+// at path: the global tag validation, or the tag_validation override of a resource type.
+// Deprecated values are checked against the effective rules, which hold the allowed values
+// an override adds to.
+func (v *ContentValidator) validateValueRules(issues *ValidationErrors, rules, effective TagValidation, path string) {
+	for _, tag := range slices.Sorted(maps.Keys(rules.CaseRules)) {
+		rule := rules.CaseRules[tag]
+		rulePath := path + ".case_rules." + tag
+
+		if rule.Case == "" {
+			issues.add(rulePath+".case", "case rule for tag %s must specify case type", tag)
+		} else if !v.isValidCaseType(rule.Case) {
+			issues.add(rulePath+".case", "invalid case type for tag %s: %s", tag, rule.Case)
+		}
+		if rule.Pattern != "" {
+			if _, err := regexp.Compile(rule.Pattern); err != nil {
+				issues.add(rulePath+".pattern", "invalid pattern for tag %s: %s", tag, err)
 			}
 		}
+		if !rule.Severity.IsValid() {
+			issues.add(rulePath+".severity", "invalid severity for tag %s: %s", tag, rule.Severity)
+		}
 	}
 
-	// Validate key validation rules
-	if err := v.validateKeyValidation(); err != nil {
-		return fmt.Errorf("key validation failed: %w", err)
+	for _, tag := range slices.Sorted(maps.Keys(rules.PatternRules)) {
+		if _, err := regexp.Compile(rules.PatternRules[tag]); err != nil {
+			issues.add(path+".pattern_rules."+tag, "invalid pattern rule for tag %s: %s", tag, err)
+		}
 	}
 
-	// Validate value validation rules
-	if err := v.validateValueValidation(); err != nil {
-		return fmt.Errorf("value validation failed: %w", err)
+	for _, tag := range slices.Sorted(maps.Keys(rules.PatternRuleSeverities)) {
+		severityPath := path + ".pattern_rule_severities." + tag
+		if _, exists := rules.PatternRules[tag]; !exists {
+			issues.add(severityPath, "severity set for tag %s, which has no pattern rule", tag)
+		} else if severity := rules.PatternRuleSeverities[tag]; !severity.IsValid() {
+			issues.add(severityPath, "invalid severity for tag %s: %s", tag, severity)
+		}
 	}
 
-	if v.cfg.TagValidation.PatternRules != nil {
-		for tag, pattern := range v.cfg.TagValidation.PatternRules {
-			if _, err := regexp.Compile(pattern); err != nil {
-				return fmt.Errorf("invalid pattern rule for tag %s: %s", tag, err)
-			}
+	for _, tag := range slices.Sorted(maps.Keys(rules.PatternRuleExamples)) {
+		examplePath := path + ".pattern_rule_examples." + tag
+		example := rules.PatternRuleExamples[tag]
+		if _, exists := rules.PatternRules[tag]; !exists {
+			issues.add(examplePath, "example set for tag %s, which has no pattern rule", tag)
+		} else if pattern, err := regexp.Compile(rules.PatternRules[tag]); err == nil && !pattern.MatchString(example) {
+			issues.add(examplePath, "example %q does not match the pattern rule of tag %s", example, tag)
+		}
+	}
+
+	for _, tag := range slices.Sorted(maps.Keys(rules.AllowedValues)) {
+		if len(rules.AllowedValues[tag]) == 0 {
+			issues.add(path+".allowed_values."+tag, "no allowed values specified for tag %s", tag)
 		}
 	}
 
-	if v.cfg.TagValidation.AllowedValues != nil {
-		for tag, values := range v.cfg.TagValidation.AllowedValues {
-			if len(values) == 0 {
-				return fmt.Errorf("no allowed values specified for tag %s", tag)
+	for _, tag := range slices.Sorted(maps.Keys(rules.DeprecatedValues)) {
+		allowed := effective.AllowedValues[tag]
+		for _, value := range slices.Sorted(maps.Keys(rules.DeprecatedValues[tag])) {
+			deprecatedPath := fmt.Sprintf("%s.deprecated_values.%s.%s", path, tag, value)
+			if !slices.Contains(allowed, value) {
+				issues.add(deprecatedPath, "deprecated value %s is not an allowed value of tag %s", value, tag)
+				continue
+			}
+			replacement := rules.DeprecatedValues[tag][value]
+			if replacement == "" {
+				continue
+			}
+			if _, deprecated := effective.DeprecatedValue(tag, replacement); deprecated || !slices.Contains(allowed, replacement) {
+				issues.add(deprecatedPath, "replacement %s of deprecated value %s must be an allowed value of tag %s that is not deprecated", replacement, value, tag)
 			}
 		}
 	}
+}
+
+// validateTagNormalization ensures every alias resolves to a canonical key in a single step,
+// rejecting aliases that map to themselves, to another alias, or ambiguously once lowercased
+func (v *ContentValidator) validateTagNormalization(issues *ValidationErrors) {
+	normalization := v.cfg.TagValidation.TagNormalization
+	const path = "tag_validation.tag_normalization.aliases"
 
-	// Validate length rules
-	if err := v.validateLengthRules(); err != nil {
-		return fmt.Errorf("length rules validation failed: %w", err)
+	normalize := func(key string) string {
+		if normalization.LowercaseKeys {
+			return strings.ToLower(key)
+		}
+		return key
 	}
 
-	return nil
+	aliases := make(map[string]string, len(normalization.Aliases))
+	for _, alias := range slices.Sorted(maps.Keys(normalization.Aliases)) {
+		canonical := normalization.Aliases[alias]
+		if strings.TrimSpace(alias) == "" || strings.TrimSpace(canonical) == "" {
+			issues.add(path, "tag alias %q must map a non-empty key to a non-empty canonical key", alias)
+			continue
+		}
+
+		key := normalize(alias)
+		if existing, ok := aliases[key]; ok && existing != normalize(canonical) {
+			issues.add(path+"."+alias, "tag alias %q maps to both %q and %q", key, existing, normalize(canonical))
+			continue
+		}
+		aliases[key] = normalize(canonical)
+	}
+
+	for _, alias := range slices.Sorted(maps.Keys(aliases)) {
+		canonical := aliases[alias]
+		if alias == canonical {
+			issues.add(path+"."+alias, "tag alias %q maps to itself", alias)
+			continue
+		}
+		if _, isAlias := aliases[canonical]; isAlias {
+			issues.add(path+"."+alias, "tag alias %q maps to %q, which is itself an alias", alias, canonical)
+		}
+	}
 }
 
-func (v *ContentValidator) validateKeyValidation() error {
+func (v *ContentValidator) validateKeyValidation(issues *ValidationErrors) {
 	keyValidation := v.cfg.TagValidation.KeyValidation
+	const path = "tag_validation.key_validation"
 
 	// Validate max length
 	if keyValidation.MaxLength <= 0 {
-		return fmt.Errorf("key validation max length must be positive")
+		issues.add(path+".max_length", "key validation max length must be positive")
 	}
 
 	// Validate prefixes and suffixes
-	for _, prefix := range keyValidation.AllowedPrefixes {
+	for i, prefix := range keyValidation.AllowedPrefixes {
 		if prefix == "" {
-			return fmt.Errorf("empty prefix in allowed prefixes")
+			issues.add(fmt.Sprintf("%s.allowed_prefixes[%d]", path, i), "empty prefix in allowed prefixes")
+		} else if keyValidation.MaxLength > 0 && utf8.RuneCountInString(prefix) > keyValidation.MaxLength {
+			issues.add(fmt.Sprintf("%s.allowed_prefixes[%d]", path, i), "prefix %q is longer than the maximum key length %d", prefix, keyValidation.MaxLength)
 		}
 	}
 
-	for _, suffix := range keyValidation.AllowedSuffixes {
+	for i, suffix := range keyValidation.AllowedSuffixes {
 		if suffix == "" {
-			return fmt.Errorf("empty suffix in allowed suffixes")
+			issues.add(fmt.Sprintf("%s.allowed_suffixes[%d]", path, i), "empty suffix in allowed suffixes")
+		} else if keyValidation.MaxLength > 0 && utf8.RuneCountInString(suffix) > keyValidation.MaxLength {
+			issues.add(fmt.Sprintf("%s.allowed_suffixes[%d]", path, i), "suffix %q is longer than the maximum key length %d", suffix, keyValidation.MaxLength)
 		}
 	}
-
-	return nil
 }
 
-func (v *ContentValidator) validateValueValidation() error {
+func (v *ContentValidator) validateValueValidation(issues *ValidationErrors) {
 	valueValidation := v.cfg.TagValidation.ValueValidation
+	const path = "tag_validation.value_validation"
 
 	// Validate allowed characters pattern
 	if valueValidation.AllowedCharacters != "" {
 		if _, err := regexp.Compile(fmt.Sprintf("[%s]", valueValidation.AllowedCharacters)); err != nil {
-			return fmt.Errorf("invalid allowed characters pattern: %s", err)
+			issues.add(path+".allowed_characters", "invalid allowed characters pattern: %s", err)
 		}
 	}
 
 	// Validate disallowed values
-	for _, value := range valueValidation.DisallowedValues {
+	for i, value := range valueValidation.DisallowedValues {
 		if value == "" {
-			return fmt.Errorf("empty value in disallowed values")
+			issues.add(fmt.Sprintf("%s.disallowed_values[%d]", path, i), "empty value in disallowed values")
 		}
 	}
-
-	return nil
 }
 
-func (v *ContentValidator) validateLengthRules() error {
-	for tag, rule := range v.cfg.TagValidation.LengthRules {
+func (v *ContentValidator) validateLengthRules(issues *ValidationErrors) {
+	for _, tag := range slices.Sorted(maps.Keys(v.cfg.TagValidation.LengthRules)) {
+		rule := v.cfg.TagValidation.LengthRules[tag]
+		path := "tag_validation.length_rules." + tag
+
 		if rule.MinLength != nil && *rule.MinLength < 0 {
-			return fmt.Errorf("tag %s has negative minimum length", tag)
+			issues.add(path+".min_length", "tag %s has negative minimum length", tag)
 		}
 		if rule.MaxLength != nil && rule.MinLength != nil && *rule.MaxLength <= *rule.MinLength {
-			return fmt.Errorf("tag %s has maximum length (%d) less than or equal to minimum length (%d)",
+			issues.add(path+".max_length", "tag %s has maximum length (%d) less than or equal to minimum length (%d)",
 				tag, *rule.MaxLength, *rule.MinLength)
 		}
 	}
-	return nil
 }
 
 func (v *ContentValidator) validateNotifications() error {
+	var issues ValidationErrors
+
 	if v.cfg.Notifications.Slack.Enabled {
 		if len(v.cfg.Notifications.Slack.Channels) == 0 {
-			return fmt.Errorf("slack notifications enabled but no channels configured")
+			issues.add("notifications.slack.channels", "slack notifications enabled but no channels configured")
 		}
 	}
 
 	if v.cfg.Notifications.Email.Enabled {
+		const path = "notifications.email"
+
 		if len(v.cfg.Notifications.Email.Recipients) == 0 {
-			return fmt.Errorf("email notifications enabled but no recipients configured")
+			issues.add(path+".recipients", "email notifications enabled but no recipients configured")
 		}
-		for _, email := range v.cfg.Notifications.Email.Recipients {
+		for i, email := range v.cfg.Notifications.Email.Recipients {
 			if !v.isValidEmail(email) {
-				return fmt.Errorf("invalid email address: %s", email)
+				issues.add(fmt.Sprintf("%s.recipients[%d]", path, i), "invalid email address: %s", email)
 			}
 		}
 		if v.cfg.Notifications.Email.Frequency == "" {
-			return fmt.Errorf("email notifications enabled but no frequency specified")
+			issues.add(path+".frequency", "email notifications enabled but no frequency specified")
+		} else if !v.isValidEmailFrequency(v.cfg.Notifications.Email.Frequency) {
+			issues.add(path+".frequency", "invalid email frequency: %s", v.cfg.Notifications.Email.Frequency)
+		}
+	}
+
+	return issues.err()
+}
+
+func (v *ContentValidator) validateReporting() error {
+	var issues ValidationErrors
+
+	seen := make(map[string]bool, len(v.cfg.Reporting.RedactTags))
+	for i, key := range v.cfg.Reporting.RedactTags {
+		path := fmt.Sprintf("reporting.redact_tags[%d]", i)
+		if strings.TrimSpace(key) == "" {
+			issues.add(path, "redacted tag key cannot be empty")
+			continue
 		}
-		if !v.isValidEmailFrequency(v.cfg.Notifications.Email.Frequency) {
-			return fmt.Errorf("invalid email frequency: %s", v.cfg.Notifications.Email.Frequency)
+		if seen[strings.ToLower(key)] {
+			issues.add(path, "duplicate redacted tag key: %s", key)
 		}
+		seen[strings.ToLower(key)] = true
 	}
 
-	return nil
+	if thresholds := v.cfg.Reporting.BadgeThresholds; thresholds != nil {
+		path := "reporting.badge_thresholds"
+		if thresholds.Green < 0 || thresholds.Green > 100 {
+			issues.add(path+".green", "green threshold must be between 0 and 100, got %g", thresholds.Green)
+		}
+		if thresholds.Yellow < 0 || thresholds.Yellow > 100 {
+			issues.add(path+".yellow", "yellow threshold must be between 0 and 100, got %g", thresholds.Yellow)
+		}
+		if thresholds.Yellow > thresholds.Green {
+			issues.add(path, "yellow threshold %g cannot be above the green threshold %g", thresholds.Yellow, thresholds.Green)
+		}
+	}
+
+	return issues.err()
+}
+
+func (v *ContentValidator) validateCrossResourceRules() error {
+	var issues ValidationErrors
+
+	for i, rule := range v.cfg.CrossResourceRules {
+		path := fmt.Sprintf("cross_resource_rules[%d]", i)
+		if !rule.Severity.IsValid() {
+			issues.add(path+".severity", "invalid severity: %s", rule.Severity)
+		}
+
+		unique := rule.UniqueValue
+		if unique == nil {
+			issues.add(path, "cross-resource rule must set unique_value")
+			continue
+		}
+		if strings.TrimSpace(unique.Tag) == "" {
+			issues.add(path+".unique_value.tag", "unique_value tag cannot be empty")
+		}
+
+		seen := make(map[string]bool, len(unique.Scope))
+		for j, dimension := range unique.Scope {
+			scopePath := fmt.Sprintf("%s.unique_value.scope[%d]", path, j)
+			if !IsValidScope(dimension) {
+				issues.add(scopePath, "invalid scope %q, expected one of: %s, %s, %s, %s or %s<key>",
+					dimension, ScopeAccount, ScopeRegion, ScopeType, ScopeVPC, ScopeTagPrefix)
+				continue
+			}
+			if seen[dimension] {
+				issues.add(scopePath, "duplicate scope: %s", dimension)
+			}
+			seen[dimension] = true
+		}
+	}
+
+	return issues.err()
 }
 
 func (v *ContentValidator) isValidComplianceLevel(level string) bool {
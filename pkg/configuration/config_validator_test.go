@@ -1,8 +1,10 @@
 package configuration
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/Excoriate/aws-taggy/pkg/constants"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -138,6 +140,188 @@ func TestContentValidator_ValidateContent(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Invalid Global Max Concurrency",
+			setup: func(cfg *TaggyScanConfig) {
+				maxConcurrency := 0
+				cfg.Global.MaxConcurrency = &maxConcurrency
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid Global Workers",
+			setup: func(cfg *TaggyScanConfig) {
+				workers := 0
+				cfg.Global.Workers = &workers
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid Resource Batch Size",
+			setup: func(cfg *TaggyScanConfig) {
+				batchSize := -10
+				s3 := cfg.Resources["s3"]
+				s3.BatchSize = &batchSize
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid Resource Workers",
+			setup: func(cfg *TaggyScanConfig) {
+				workers := 0
+				s3 := cfg.Resources["s3"]
+				s3.Workers = &workers
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid Resource Rate Limit",
+			setup: func(cfg *TaggyScanConfig) {
+				rateLimit := -5.0
+				s3 := cfg.Resources["s3"]
+				s3.RateLimit = &rateLimit
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: true,
+		},
+		{
+			name: "Generic Resource Type Filters",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.Resources["generic"] = ResourceConfig{
+					Enabled:             true,
+					TagCriteria:         TagCriteria{MinimumRequiredTags: 1},
+					ResourceTypeFilters: []string{"kinesis", "states:stateMachine"},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Resource Type Filters On Dedicated Resource",
+			setup: func(cfg *TaggyScanConfig) {
+				s3 := cfg.Resources["s3"]
+				s3.ResourceTypeFilters = []string{"kinesis"}
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: true,
+		},
+		{
+			name: "EC2 Instance States",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.Resources["ec2"] = ResourceConfig{
+					Enabled:               true,
+					TagCriteria:           TagCriteria{MinimumRequiredTags: 1},
+					IncludeInstanceStates: []string{"running", "pending"},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid EC2 Instance State",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.Resources["ec2"] = ResourceConfig{
+					Enabled:               true,
+					TagCriteria:           TagCriteria{MinimumRequiredTags: 1},
+					IncludeInstanceStates: []string{"hibernated"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Instance States On Another Resource",
+			setup: func(cfg *TaggyScanConfig) {
+				s3 := cfg.Resources["s3"]
+				s3.IncludeInstanceStates = []string{"running"}
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: true,
+		},
+		{
+			name: "EBS Snapshots",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.Resources["ebs"] = ResourceConfig{
+					Enabled:          true,
+					TagCriteria:      TagCriteria{MinimumRequiredTags: 1},
+					IncludeSnapshots: true,
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Snapshots On Another Resource",
+			setup: func(cfg *TaggyScanConfig) {
+				s3 := cfg.Resources["s3"]
+				s3.IncludeSnapshots = true
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: true,
+		},
+		{
+			name: "S3 Bucket Regions",
+			setup: func(cfg *TaggyScanConfig) {
+				s3 := cfg.Resources["s3"]
+				s3.BucketRegions = []string{"eu-west-1"}
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid Bucket Region",
+			setup: func(cfg *TaggyScanConfig) {
+				s3 := cfg.Resources["s3"]
+				s3.BucketRegions = []string{"eu-west-9"}
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: true,
+		},
+		{
+			name: "Bucket Regions On Another Resource",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.Resources["ebs"] = ResourceConfig{
+					Enabled:       true,
+					TagCriteria:   TagCriteria{MinimumRequiredTags: 1},
+					BucketRegions: []string{"eu-west-1"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Disabled Rules",
+			setup: func(cfg *TaggyScanConfig) {
+				s3 := cfg.Resources["s3"]
+				s3.DisabledRules = []string{"case_rules", "key_validation"}
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: false,
+		},
+		{
+			name: "Unknown Disabled Rule",
+			setup: func(cfg *TaggyScanConfig) {
+				s3 := cfg.Resources["s3"]
+				s3.DisabledRules = []string{"naming_rules"}
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: true,
+		},
+		{
+			name: "Value Validation Not Disableable",
+			setup: func(cfg *TaggyScanConfig) {
+				s3 := cfg.Resources["s3"]
+				s3.DisabledRules = []string{"value_validation"}
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: true,
+		},
+		{
+			name: "Rule Disabled Twice",
+			setup: func(cfg *TaggyScanConfig) {
+				s3 := cfg.Resources["s3"]
+				s3.DisabledRules = []string{"max_tags", "max_tags"}
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: true,
+		},
 		{
 			name: "Invalid Tag Criteria",
 			setup: func(cfg *TaggyScanConfig) {
@@ -145,6 +329,27 @@ func TestContentValidator_ValidateContent(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Required Tag Severity",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.Global.TagCriteria.RequiredTagSeverities = map[string]Severity{"Owner": SeverityCritical}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid Required Tag Severity",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.Global.TagCriteria.RequiredTagSeverities = map[string]Severity{"Owner": "urgent"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Severity For Tag That Is Not Required",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.Global.TagCriteria.RequiredTagSeverities = map[string]Severity{"CostCenter": SeverityHigh}
+			},
+			wantErr: true,
+		},
 		{
 			name: "Invalid Compliance Level",
 			setup: func(cfg *TaggyScanConfig) {
@@ -152,6 +357,37 @@ func TestContentValidator_ValidateContent(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Compliance Level Extending Another",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.ComplianceLevels["standard"] = ComplianceLevel{Extends: "high"}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Compliance Level Extending Unknown Level",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.ComplianceLevels["standard"] = ComplianceLevel{
+					RequiredTags: []string{"Owner"},
+					Extends:      "medium",
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Compliance Level Inheritance Cycle",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.ComplianceLevels["high"] = ComplianceLevel{
+					RequiredTags: []string{"SecurityLevel"},
+					Extends:      "standard",
+				}
+				cfg.ComplianceLevels["standard"] = ComplianceLevel{
+					RequiredTags: []string{"Owner"},
+					Extends:      "high",
+				}
+			},
+			wantErr: true,
+		},
 		{
 			name: "Invalid Case Rule",
 			setup: func(cfg *TaggyScanConfig) {
@@ -182,6 +418,82 @@ func TestContentValidator_ValidateContent(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Valid Tag Criteria Template",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagCriteriaTemplates = map[string]TagCriteria{
+					"workload": {MinimumRequiredTags: 2, RequiredTags: []string{"DataClassification", "BackupPolicy"}},
+				}
+				// The minimum is judged against the required tags of the template
+				cfg.Resources["s3"] = ResourceConfig{
+					Enabled:     true,
+					TagCriteria: TagCriteria{Template: "workload", MinimumRequiredTags: 3, RequiredTags: []string{"Owner"}},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Unknown Tag Criteria Template",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.Resources["s3"] = ResourceConfig{Enabled: true, TagCriteria: TagCriteria{Template: "workload"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Nested Tag Criteria Template",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagCriteriaTemplates = map[string]TagCriteria{
+					"base":     {RequiredTags: []string{"Owner"}},
+					"workload": {Template: "base"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Global Tag Criteria Template",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagCriteriaTemplates = map[string]TagCriteria{"workload": {RequiredTags: []string{"Owner"}}}
+				cfg.Global.TagCriteria.Template = "workload"
+			},
+			wantErr: true,
+		},
+		{
+			name: "Resource Role",
+			setup: func(cfg *TaggyScanConfig) {
+				s3 := cfg.Resources["s3"]
+				s3.RoleARN = "arn:aws:iam::333333333333:role/taggy"
+				s3.ExternalID = "ext"
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid Resource Role ARN",
+			setup: func(cfg *TaggyScanConfig) {
+				s3 := cfg.Resources["s3"]
+				s3.RoleARN = "arn:aws:iam::333333333333:user/taggy"
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: true,
+		},
+		{
+			name: "Resource Role ARN Without Account",
+			setup: func(cfg *TaggyScanConfig) {
+				s3 := cfg.Resources["s3"]
+				s3.RoleARN = "arn:aws:iam:::role/taggy"
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: true,
+		},
+		{
+			name: "Resource External ID Without Role",
+			setup: func(cfg *TaggyScanConfig) {
+				s3 := cfg.Resources["s3"]
+				s3.ExternalID = "ext"
+				cfg.Resources["s3"] = s3
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -202,6 +514,47 @@ func TestContentValidator_ValidateContent(t *testing.T) {
 	}
 }
 
+func TestContentValidator_ValidateMinimumRequiredTags(t *testing.T) {
+	testCases := []struct {
+		name     string
+		criteria TagCriteria
+		wantErr  string
+	}{
+		{name: "Zero", criteria: TagCriteria{RequiredTags: []string{"Owner", "Team"}}},
+		{name: "Lower Than Required Tags", criteria: TagCriteria{MinimumRequiredTags: 1, RequiredTags: []string{"Owner", "Team"}}},
+		{name: "Equal To Required Tags", criteria: TagCriteria{MinimumRequiredTags: 2, RequiredTags: []string{"Owner", "Team"}}},
+		{name: "Without Required Tags", criteria: TagCriteria{MinimumRequiredTags: 2}},
+		{
+			name:     "Greater Than Required Tags",
+			criteria: TagCriteria{MinimumRequiredTags: 3, RequiredTags: []string{"Owner", "Team"}},
+			wantErr:  "global.tag_criteria.minimum_required_tags: global minimum required tags (3) cannot exceed the number of required tags (2) it counts present, set it to 2 or 0 to require all of them",
+		},
+		{
+			name:     "Negative",
+			criteria: TagCriteria{MinimumRequiredTags: -1, RequiredTags: []string{"Owner", "Team"}},
+			wantErr:  "global.tag_criteria.minimum_required_tags: global minimum required tags cannot be negative",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createTestConfig()
+			cfg.Global.TagCriteria = tc.criteria
+
+			validator, err := NewContentValidator(cfg)
+			require.NoError(t, err)
+
+			err = validator.validateGlobalConfig()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
 func TestContentValidator_ValidateAWSConfig(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -243,6 +596,138 @@ func TestContentValidator_ValidateAWSConfig(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Invalid Workers",
+			setup: func(cfg *TaggyScanConfig) {
+				workers := -1
+				cfg.AWS.Workers = &workers
+			},
+			wantErr: true,
+		},
+		{
+			name: "Valid Scan Limits",
+			setup: func(cfg *TaggyScanConfig) {
+				maxResources, maxAPICalls := 5000, 20000
+				cfg.AWS.MaxResourcesPerService = &maxResources
+				cfg.AWS.MaxAPICalls = &maxAPICalls
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid Max Resources Per Service",
+			setup: func(cfg *TaggyScanConfig) {
+				maxResources := 0
+				cfg.AWS.MaxResourcesPerService = &maxResources
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid Max API Calls",
+			setup: func(cfg *TaggyScanConfig) {
+				maxAPICalls := -1
+				cfg.AWS.MaxAPICalls = &maxAPICalls
+			},
+			wantErr: true,
+		},
+		{
+			name: "Valid Accounts",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.AWS.Accounts = []AccountConfig{
+					{AccountID: "111111111111", RoleARN: "arn:aws:iam::111111111111:role/taggy"},
+					{AccountID: "222222222222", RoleARN: "arn:aws:iam::222222222222:role/taggy", ExternalID: "ext"},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid Account ID",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.AWS.Accounts = []AccountConfig{
+					{AccountID: "1234", RoleARN: "arn:aws:iam::111111111111:role/taggy"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Duplicate Account",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.AWS.Accounts = []AccountConfig{
+					{AccountID: "111111111111", RoleARN: "arn:aws:iam::111111111111:role/taggy"},
+					{AccountID: "111111111111", RoleARN: "arn:aws:iam::111111111111:role/other"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Missing Role ARN",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.AWS.Accounts = []AccountConfig{{AccountID: "111111111111"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Role ARNs In GovCloud And China Partitions",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.AWS.Accounts = []AccountConfig{
+					{AccountID: "111111111111", RoleARN: "arn:aws-us-gov:iam::111111111111:role/taggy"},
+					{AccountID: "222222222222", RoleARN: "arn:aws-cn:iam::222222222222:role/taggy"},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Role ARN Of Another Service",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.AWS.Accounts = []AccountConfig{
+					{AccountID: "111111111111", RoleARN: "arn:aws:s3:::taggy-bucket"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Role ARN Naming A User",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.AWS.Accounts = []AccountConfig{
+					{AccountID: "111111111111", RoleARN: "arn:aws:iam::111111111111:user/taggy"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Valid Retries",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.AWS.Retries = &RetryConfig{MaxAttempts: 8, BaseDelay: "250ms", MaxDelay: "10s"}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid Retries Base Delay",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.AWS.Retries = &RetryConfig{BaseDelay: "soon"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Retries Base Delay Above Max Delay",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.AWS.Retries = &RetryConfig{BaseDelay: "30s", MaxDelay: "1s"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Valid Endpoint URL",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.AWS.EndpointURL = "http://localhost:4566"
+			},
+			wantErr: false,
+		},
+		{
+			name: "Endpoint URL Without Scheme",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.AWS.EndpointURL = "localhost:4566"
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -293,6 +778,120 @@ func TestContentValidator_ValidateTagValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Invalid Case Rule Severity",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.CaseRules["Test"] = CaseRule{
+					Case:     CaseLowercase,
+					Severity: "blocker",
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Pattern Rule Severity",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.PatternRuleSeverities = map[string]Severity{"CostCenter": SeverityLow}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid Pattern Rule Severity",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.PatternRuleSeverities = map[string]Severity{"CostCenter": "blocker"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Severity For Tag Without Pattern Rule",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.PatternRuleSeverities = map[string]Severity{"Owner": SeverityHigh}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Pattern Rule Example",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.PatternRuleExamples = map[string]string{"CostCenter": "CC-1234"}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Pattern Rule Example Not Matching",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.PatternRuleExamples = map[string]string{"CostCenter": "cc1234"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Deprecated Value With Replacement",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.AllowedValues["Environment"] = []string{"production", "staging", "qa"}
+				cfg.TagValidation.DeprecatedValues = map[string]map[string]string{"Environment": {"qa": "staging"}}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Deprecated Value Not Allowed",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.DeprecatedValues = map[string]map[string]string{"Environment": {"qa": ""}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Deprecated Value Replaced By Deprecated Value",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.AllowedValues["Environment"] = []string{"production", "qa", "test"}
+				cfg.TagValidation.DeprecatedValues = map[string]map[string]string{"Environment": {"qa": "test", "test": ""}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Key Prefix Longer Than Max Length",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.KeyValidation.MaxLength = 4
+				cfg.TagValidation.KeyValidation.AllowedPrefixes = []string{"dept-"}
+				cfg.TagValidation.KeyValidation.AllowedSuffixes = nil
+			},
+			wantErr: true,
+		},
+		{
+			name: "Valid Tag Normalization",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.TagNormalization = TagNormalization{
+					Aliases: map[string]string{"env": "Environment", "ENV": "Environment"},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "Tag Alias Chain",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.TagNormalization = TagNormalization{
+					Aliases: map[string]string{"env": "stage", "stage": "Environment"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Tag Alias Cycle",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.TagNormalization = TagNormalization{
+					Aliases: map[string]string{"env": "Environment", "Environment": "env"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "Tag Alias To Itself Once Lowercased",
+			setup: func(cfg *TaggyScanConfig) {
+				cfg.TagValidation.TagNormalization = TagNormalization{
+					Aliases:       map[string]string{"ENV": "env"},
+					LowercaseKeys: true,
+				}
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -375,3 +974,214 @@ func TestContentValidator_ValidateNotifications(t *testing.T) {
 		})
 	}
 }
+
+func TestContentValidator_ValidateReporting(t *testing.T) {
+	tests := []struct {
+		name            string
+		redactTags      []string
+		badgeThresholds *BadgeThresholds
+		wantErr         bool
+	}{
+		{name: "No Redacted Tags", wantErr: false},
+		{name: "Redacted Tags", redactTags: []string{"Owner", "Contact"}, wantErr: false},
+		{name: "Empty Key", redactTags: []string{"Owner", " "}, wantErr: true},
+		{name: "Duplicate Key", redactTags: []string{"Owner", "owner"}, wantErr: true},
+		{name: "Badge Thresholds", badgeThresholds: &BadgeThresholds{Green: 90, Yellow: 70}, wantErr: false},
+		{name: "Equal Badge Thresholds", badgeThresholds: &BadgeThresholds{Green: 90, Yellow: 90}, wantErr: false},
+		{name: "Yellow Above Green", badgeThresholds: &BadgeThresholds{Green: 80, Yellow: 95}, wantErr: true},
+		{name: "Threshold Above 100", badgeThresholds: &BadgeThresholds{Green: 120, Yellow: 80}, wantErr: true},
+		{name: "Negative Threshold", badgeThresholds: &BadgeThresholds{Green: 95, Yellow: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig()
+			cfg.Reporting.RedactTags = tt.redactTags
+			cfg.Reporting.BadgeThresholds = tt.badgeThresholds
+
+			validator, err := NewContentValidator(cfg)
+			require.NoError(t, err)
+
+			err = validator.validateReporting()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestContentValidator_ValidateCrossResourceRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    CrossResourceRule
+		wantErr bool
+	}{
+		{
+			name:    "Unique Name Per VPC",
+			rule:    CrossResourceRule{UniqueValue: &UniqueValueRule{Tag: "Name", Scope: []string{"type", "vpc"}}, Severity: SeverityHigh},
+			wantErr: false,
+		},
+		{
+			name:    "Unique Across Every Resource",
+			rule:    CrossResourceRule{UniqueValue: &UniqueValueRule{Tag: "Name"}},
+			wantErr: false,
+		},
+		{
+			name:    "Tag Scope",
+			rule:    CrossResourceRule{UniqueValue: &UniqueValueRule{Tag: "Name", Scope: []string{"account", "tag:Team"}}},
+			wantErr: false,
+		},
+		{name: "Missing Unique Value", rule: CrossResourceRule{}, wantErr: true},
+		{name: "Empty Tag", rule: CrossResourceRule{UniqueValue: &UniqueValueRule{Tag: " "}}, wantErr: true},
+		{name: "Invalid Scope", rule: CrossResourceRule{UniqueValue: &UniqueValueRule{Tag: "Name", Scope: []string{"subnet"}}}, wantErr: true},
+		{name: "Tag Scope Without Key", rule: CrossResourceRule{UniqueValue: &UniqueValueRule{Tag: "Name", Scope: []string{"tag:"}}}, wantErr: true},
+		{name: "Duplicate Scope", rule: CrossResourceRule{UniqueValue: &UniqueValueRule{Tag: "Name", Scope: []string{"vpc", "vpc"}}}, wantErr: true},
+		{name: "Invalid Severity", rule: CrossResourceRule{UniqueValue: &UniqueValueRule{Tag: "Name"}, Severity: "blocker"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig()
+			cfg.CrossResourceRules = []CrossResourceRule{tt.rule}
+
+			validator, err := NewContentValidator(cfg)
+			require.NoError(t, err)
+
+			err = validator.validateCrossResourceRules()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestContentValidator_ValidateContentCollectsAllErrors(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.AWS.Regions.Mode = "some"
+	cfg.Resources["s3"] = ResourceConfig{
+		Enabled: true,
+		TagCriteria: TagCriteria{
+			MinimumRequiredTags: 3,
+			RequiredTags:        []string{"DataClassification"},
+		},
+	}
+	cfg.Resources["mainframe"] = ResourceConfig{Enabled: true}
+	cfg.TagValidation.PatternRules["Owner"] = "[invalid"
+	cfg.Notifications.Email.Frequency = "yearly"
+
+	validator, err := NewContentValidator(cfg)
+	require.NoError(t, err)
+
+	err = validator.ValidateContent()
+	require.Error(t, err)
+
+	var issues ValidationErrors
+	require.ErrorAs(t, err, &issues)
+
+	paths := make([]string, len(issues))
+	for i, issue := range issues {
+		paths[i] = issue.Path
+	}
+	assert.Equal(t, []string{
+		"aws.regions.mode",
+		"resources.mainframe",
+		"resources.s3.tag_criteria.minimum_required_tags",
+		"tag_validation.pattern_rules.Owner",
+		"notifications.email.frequency",
+	}, paths)
+}
+
+func TestContentValidator_ValidateContentRejectsMalformedPatterns(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.TagValidation.PatternRules["Owner"] = "^[A-Z{2,}$"
+	cfg.TagValidation.CaseRules["Project"] = CaseRule{Case: CaseMixed, Pattern: "^(Alpha|Beta"}
+	cfg.TagValidation.KeyFormatRules = []KeyFormatRule{{Pattern: "^[A-Z]"}, {Pattern: "*Key"}}
+	cfg.TagValidation.ValueValidation.AllowedCharacters = `a-z\`
+
+	validator, err := NewContentValidator(cfg)
+	require.NoError(t, err)
+
+	messages := make(map[string]string)
+	for _, issue := range Issues(validator.ValidateContent()) {
+		messages[issue.Path] = issue.Message
+	}
+
+	assert.Equal(t, map[string]string{
+		"tag_validation.case_rules.Project.pattern":          "invalid pattern for tag Project: error parsing regexp: missing closing ): `^(Alpha|Beta`",
+		"tag_validation.key_format_rules[1].pattern":         "invalid key format pattern: error parsing regexp: missing argument to repetition operator: `*`",
+		"tag_validation.value_validation.allowed_characters": "invalid allowed characters pattern: error parsing regexp: missing closing ]: `[a-z\\]`",
+		"tag_validation.pattern_rules.Owner":                 "invalid pattern rule for tag Owner: error parsing regexp: missing closing ]: `[A-Z{2,}$`",
+	}, messages)
+}
+
+func TestIssues(t *testing.T) {
+	assert.Empty(t, Issues(nil))
+
+	issues := Issues(assert.AnError)
+	require.Len(t, issues, 1)
+	assert.Empty(t, issues[0].Path)
+	assert.Equal(t, assert.AnError.Error(), issues[0].Message)
+
+	collected := ValidationErrors{{Path: "version", Message: "invalid version format"}}
+	assert.Equal(t, []ValidationIssue(collected), Issues(fmt.Errorf("wrapped: %w", collected)))
+	assert.Equal(t, "version: invalid version format", collected.Error())
+}
+
+func TestContentValidator_ValidateContentReportsIndependentProblems(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Version = "v1"
+	cfg.AWS.Regions = RegionsConfig{Mode: "specific"}
+	cfg.Notifications.Slack.Channels = nil
+
+	validator, err := NewContentValidator(cfg)
+	require.NoError(t, err)
+
+	err = validator.ValidateContent()
+	require.Error(t, err)
+
+	issues := Issues(err)
+	require.Len(t, issues, 3)
+	assert.Equal(t, "version", issues[0].Path)
+	assert.Equal(t, "aws.regions.list", issues[1].Path)
+	assert.Equal(t, "notifications.slack.channels", issues[2].Path)
+
+	assert.Equal(t, "3 configuration problems found:\n"+
+		"  1. version: invalid version format: v1, expected format: X.Y\n"+
+		"  2. aws.regions.list: specific AWS regions mode requires at least one region\n"+
+		"  3. notifications.slack.channels: slack notifications enabled but no channels configured",
+		err.Error())
+}
+
+func TestContentValidator_AcceptsRegisteredResourceTypes(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Resources["acme-cmdb"] = ResourceConfig{Enabled: true}
+
+	validator, err := NewContentValidator(cfg)
+	require.NoError(t, err)
+	assert.ErrorContains(t, validator.ValidateContent(), "unsupported AWS resource type: acme-cmdb")
+	assert.Error(t, IsSupportedAWSResource("acme-cmdb"))
+
+	RegisterResourceType("acme-cmdb")
+	assert.True(t, IsRegisteredResourceType("acme-cmdb"))
+	assert.NoError(t, validator.ValidateContent())
+	assert.NoError(t, IsSupportedAWSResource("acme-cmdb"))
+}
+
+func TestContentValidator_SuggestsResourceTypeOfAliases(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Resources["cloudwatch_alarms"] = ResourceConfig{Enabled: true}
+
+	validator, err := NewContentValidator(cfg)
+	require.NoError(t, err)
+	assert.ErrorContains(t, validator.ValidateContent(),
+		"unsupported AWS resource type: cloudwatch_alarms, configure it as cloudwatch")
+
+	// The CLI flags accept the aliases, resolved to the configured resource type
+	assert.Equal(t, constants.ResourceTypeCloudWatchAlarms, NormalizeResourceType("CloudWatch_Alarms"))
+	assert.Equal(t, constants.ResourceTypeCloudWatchLogs, NormalizeResourceType("cloudwatch_logs"))
+	assert.NoError(t, IsSupportedAWSResource("cloudwatch"))
+}
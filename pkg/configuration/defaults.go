@@ -1,11 +1,13 @@
 package configuration
 
+import "github.com/Excoriate/aws-taggy/pkg/constants"
+
 // DefaultConfiguration returns the default TaggyScanConfig with pre-configured values
 // that follow best practices for AWS resource tagging.
 func DefaultConfiguration() *TaggyScanConfig {
 	batchSize := 20
 	return &TaggyScanConfig{
-		Version: "1.0",
+		Version: constants.SupportedConfigVersion,
 		AWS: AWSConfig{
 			Regions: RegionsConfig{
 				Mode: "all",
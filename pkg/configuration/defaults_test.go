@@ -3,6 +3,7 @@
 import (
 	"testing"
 
+	"github.com/Excoriate/aws-taggy/pkg/constants"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -10,7 +11,7 @@ func TestDefaultConfiguration(t *testing.T) {
 	config := DefaultConfiguration()
 
 	// Test basic configuration values
-	assert.Equal(t, "1.0", config.Version)
+	assert.Equal(t, constants.SupportedConfigVersion, config.Version)
 	assert.Equal(t, "all", config.AWS.Regions.Mode)
 	assert.Equal(t, 20, *config.AWS.BatchSize)
 
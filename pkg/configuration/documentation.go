@@ -20,15 +20,20 @@ func DefaultDocumentation() string {
 
 #### Batch Size
 - **batch_size**: Controls the number of resources processed in parallel (default: 20)
+- **workers**: Number of workers processing the resources of each service (default: 10)
+
+Both can also be set under global, and per resource type, which overrides the aws and global values.
+The values in effect are reported in the scan metadata of each resource type.
 
 ### Global Settings
 Global settings define the default tagging rules applied across all resources unless overridden.
 
 #### Tag Criteria
-- **minimum_required_tags**: Minimum number of tags required for compliance
+- **minimum_required_tags**: How many of the required tags must be present: any of them when lower than their number, all of them when equal or 0
 - **max_tags**: Maximum number of tags allowed per resource
 - **required_tags**: List of tags that must be present on every resource
 - **forbidden_tags**: List of tags that are not allowed
+- **forbidden_tags_ignore_case**: Match forbidden tags regardless of the case of tag keys
 - **specific_tags**: Exact tag key-value pairs that must be present
 - **compliance_level**: Overall tag compliance standard (e.g., 'high', 'standard')
 
@@ -38,13 +43,16 @@ func DefaultDocumentation() string {
 #### Example: S3 Configuration
 - **enabled**: Enable/disable tag compliance for S3
 - **tag_criteria**: Custom tag requirements for S3 buckets
-  - **minimum_required_tags**: S3-specific minimum tag requirement
+  - **minimum_required_tags**: How many of the S3-specific required tags must be present
   - **required_tags**: S3-specific required tags
-  - **forbidden_tags**: S3-specific forbidden tags
+  - **forbidden_tags**: S3-specific forbidden tags, added to the global ones
   - **specific_tags**: S3-specific required tag key-value pairs
   - **compliance_level**: S3-specific compliance level
 - **excluded_resources**: Patterns for S3 buckets to exclude from compliance checks
 
+#### Example: EC2 Configuration
+- **include_instance_states**: States of the inspected instances (default: running and stopped)
+
 ### Compliance Levels
 Define different compliance standards with specific requirements.
 
@@ -68,6 +76,10 @@ func DefaultDocumentation() string {
 - Length constraints
 - Case sensitivity rules
 
+### Reporting
+Tag keys listed in redact_tags have their values masked in every report, keeping the keys visible.
+badge_thresholds sets the compliance percentages turning the badge of compliance check --badge-file green and yellow.
+
 ### Notifications
 Configure alerts and reports for non-compliant resources.
 
@@ -0,0 +1,73 @@
+package configuration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFormat is the format a configuration file is written in
+type ConfigFormat string
+
+const (
+	// ConfigFormatYAML is used for .yaml and .yml files, which may hold several documents
+	ConfigFormatYAML ConfigFormat = "YAML"
+
+	// ConfigFormatJSON is used for .json files
+	ConfigFormatJSON ConfigFormat = "JSON"
+)
+
+// configFormatsByExtension maps the supported file extensions to their format
+var configFormatsByExtension = map[string]ConfigFormat{
+	".yaml": ConfigFormatYAML,
+	".yml":  ConfigFormatYAML,
+	".json": ConfigFormatJSON,
+}
+
+// DetectConfigFormat returns the format of a configuration file from its extension
+func DetectConfigFormat(path string) (ConfigFormat, error) {
+	ext := filepath.Ext(path)
+	if format, ok := configFormatsByExtension[strings.ToLower(ext)]; ok {
+		return format, nil
+	}
+
+	return "", fmt.Errorf("unsupported configuration file extension %q, expected one of .yaml, .yml or .json", ext)
+}
+
+// decodeConfig parses configuration content written in the given format.
+//
+// A YAML file may hold several documents separated by "---", each decoded on top of the
+// previous ones: settings a later document sets replace earlier ones, while settings it
+// leaves out are kept. Map entries, such as resources or compliance levels, are replaced
+// as a whole per key.
+func decodeConfig(content []byte, format ConfigFormat) (*TaggyScanConfig, error) {
+	cfg := &TaggyScanConfig{}
+
+	switch format {
+	case ConfigFormatJSON:
+		if err := json.Unmarshal(content, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse %s configuration: %w", format, err)
+		}
+	case ConfigFormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		for document := 1; ; document++ {
+			err := decoder.Decode(cfg)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s configuration (document %d): %w", format, document, err)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported configuration format: %s", format)
+	}
+
+	return cfg, nil
+}
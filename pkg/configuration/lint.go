@@ -0,0 +1,130 @@
+package configuration
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Lint rules reported by Lint
+const (
+	LintRuleUnusedComplianceLevel = "unused-compliance-level"
+	LintRuleUnusedAllowedValues   = "unused-allowed-values"
+	LintRuleUnusedCaseRule        = "unused-case-rule"
+	LintRuleEmptyTagCriteria      = "empty-tag-criteria"
+)
+
+// LintFinding is a setting of a valid configuration that has no effect, located by the same
+// path-like pointer as a ValidationIssue
+type LintFinding struct {
+	Rule    string `json:"rule" yaml:"rule"`
+	Path    string `json:"path" yaml:"path"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// String returns the finding as "path: message (rule)"
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s: %s (%s)", f.Path, f.Message, f.Rule)
+}
+
+// Lint cross-references the sections of a configuration and reports the dead ones, ordered
+// by path: compliance levels no tag criteria or level references, allowed values and case
+// rules of tags no tag criteria or compliance level requires, and enabled resources whose tag
+// criteria, like the global ones, require nothing, so every resource passes them.
+func Lint(cfg *TaggyScanConfig) []LintFinding {
+	var findings []LintFinding
+
+	referencedLevels := map[string]bool{cfg.Global.TagCriteria.ComplianceLevel: true}
+	for _, resourceConfig := range cfg.Resources {
+		referencedLevels[resourceConfig.TagCriteria.ComplianceLevel] = true
+	}
+	for _, level := range cfg.ComplianceLevels {
+		if level.Extends != "" {
+			referencedLevels[level.Extends] = true
+		}
+	}
+
+	for name := range cfg.ComplianceLevels {
+		if !referencedLevels[name] {
+			findings = append(findings, LintFinding{
+				Rule:    LintRuleUnusedComplianceLevel,
+				Path:    "compliance_levels." + name,
+				Message: "no tag_criteria.compliance_level or extends references this level",
+			})
+		}
+	}
+
+	required := requiredTagsOf(cfg)
+	isRequired := func(key string) bool {
+		return slices.ContainsFunc(required, func(tag string) bool { return strings.EqualFold(tag, key) })
+	}
+
+	for key := range cfg.TagValidation.AllowedValues {
+		if !isRequired(key) {
+			findings = append(findings, LintFinding{
+				Rule:    LintRuleUnusedAllowedValues,
+				Path:    "tag_validation.allowed_values." + key,
+				Message: fmt.Sprintf("tag '%s' is required by no tag criteria or compliance level", key),
+			})
+		}
+	}
+
+	for key := range cfg.TagValidation.CaseRules {
+		if !isRequired(key) {
+			findings = append(findings, LintFinding{
+				Rule:    LintRuleUnusedCaseRule,
+				Path:    "tag_validation.case_rules." + key,
+				Message: fmt.Sprintf("tag '%s' is required by no tag criteria or compliance level", key),
+			})
+		}
+	}
+
+	if tagCriteriaEmpty(cfg.Global.TagCriteria) {
+		for resourceType, resourceConfig := range cfg.Resources {
+			if resourceConfig.Enabled && tagCriteriaEmpty(resourceConfig.TagCriteria) {
+				findings = append(findings, LintFinding{
+					Rule:    LintRuleEmptyTagCriteria,
+					Path:    fmt.Sprintf("resources.%s.tag_criteria", resourceType),
+					Message: "resource is enabled but neither its tag criteria nor the global ones require anything, so every resource passes",
+				})
+			}
+		}
+	}
+
+	slices.SortFunc(findings, func(a, b LintFinding) int { return strings.Compare(a.Path, b.Path) })
+	return findings
+}
+
+// requiredTagsOf returns the tags required by the global and resource tag criteria and by the
+// compliance levels, specific tags included
+func requiredTagsOf(cfg *TaggyScanConfig) []string {
+	var tags []string
+	addCriteria := func(tagCriteria TagCriteria) {
+		tags = append(tags, tagCriteria.RequiredTags...)
+		for key := range tagCriteria.SpecificTags {
+			tags = append(tags, key)
+		}
+	}
+
+	addCriteria(cfg.Global.TagCriteria)
+	for _, resourceConfig := range cfg.Resources {
+		addCriteria(resourceConfig.TagCriteria)
+	}
+	for _, level := range cfg.ComplianceLevels {
+		tags = append(tags, level.RequiredTags...)
+		for key := range level.SpecificTags {
+			tags = append(tags, key)
+		}
+	}
+	return tags
+}
+
+// tagCriteriaEmpty reports whether tag criteria set no requirement at all
+func tagCriteriaEmpty(tagCriteria TagCriteria) bool {
+	return tagCriteria.MinimumRequiredTags == 0 &&
+		len(tagCriteria.RequiredTags) == 0 &&
+		len(tagCriteria.ForbiddenTags) == 0 &&
+		len(tagCriteria.SpecificTags) == 0 &&
+		tagCriteria.ComplianceLevel == "" &&
+		tagCriteria.MaxTags == 0
+}
@@ -0,0 +1,58 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.ComplianceLevels["standard"] = ComplianceLevel{RequiredTags: []string{"Owner"}}
+	cfg.ComplianceLevels["legacy"] = ComplianceLevel{RequiredTags: []string{"CostCenter"}, Extends: "standard"}
+	cfg.Resources["s3"] = ResourceConfig{
+		Enabled:     true,
+		TagCriteria: TagCriteria{RequiredTags: []string{"DataClassification"}, ComplianceLevel: "high"},
+	}
+	cfg.TagValidation.AllowedValues["costcenter"] = []string{"CC-1"}
+	cfg.TagValidation.AllowedValues["Project"] = []string{"apollo"}
+	cfg.TagValidation.CaseRules["Team"] = CaseRule{Case: CaseLowercase}
+
+	assert.Equal(t, []LintFinding{
+		{
+			Rule:    LintRuleUnusedComplianceLevel,
+			Path:    "compliance_levels.legacy",
+			Message: "no tag_criteria.compliance_level or extends references this level",
+		},
+		{
+			Rule:    LintRuleUnusedAllowedValues,
+			Path:    "tag_validation.allowed_values.Project",
+			Message: "tag 'Project' is required by no tag criteria or compliance level",
+		},
+		{
+			Rule:    LintRuleUnusedCaseRule,
+			Path:    "tag_validation.case_rules.Team",
+			Message: "tag 'Team' is required by no tag criteria or compliance level",
+		},
+	}, Lint(cfg))
+}
+
+func TestLint_EmptyTagCriteria(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.ComplianceLevels = nil
+	cfg.Resources["ec2"] = ResourceConfig{Enabled: true}
+	cfg.Resources["rds"] = ResourceConfig{Enabled: false}
+
+	// The global criteria still apply to resources without their own
+	assert.Empty(t, Lint(cfg))
+
+	cfg.Global.TagCriteria = TagCriteria{}
+	cfg.TagValidation.AllowedValues = nil
+	cfg.TagValidation.CaseRules = nil
+
+	assert.Equal(t, []LintFinding{{
+		Rule:    LintRuleEmptyTagCriteria,
+		Path:    "resources.ec2.tag_criteria",
+		Message: "resource is enabled but neither its tag criteria nor the global ones require anything, so every resource passes",
+	}}, Lint(cfg))
+}
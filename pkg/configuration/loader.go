@@ -3,26 +3,42 @@
 import (
 	"fmt"
 	"os"
-	"regexp"
+	"strings"
 
-	"gopkg.in/yaml.v3"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
 )
 
 // ConfigLoader handles loading configuration files
 type ConfigLoader struct {
 	config *TaggyScanConfig
+
+	// overrides are applied on top of the file and the environment, see SetOverrides
+	overrides []Override
+
+	// environ returns the environment read for AWS_TAGGY_ overrides
+	environ func() []string
 }
 
 // NewTaggyScanConfigLoader creates a new ConfigLoader instance
 func NewTaggyScanConfigLoader() *ConfigLoader {
-	return &ConfigLoader{}
+	return &ConfigLoader{environ: os.Environ}
+}
+
+// SetOverrides sets overrides, typically from --set flags, applied to every configuration
+// parsed afterwards. They take precedence over the file and the AWS_TAGGY_ environment
+// variables.
+func (l *ConfigLoader) SetOverrides(overrides []Override) {
+	l.overrides = overrides
 }
 
 // LoadConfig loads a configuration file from the specified path
 // LoadConfig performs the following steps:
 // 1. Validate the configuration file path and existence
-// 2. Parse the YAML configuration
-// 3. Validate the parsed configuration structure
+// 2. Parse the YAML or JSON configuration, migrating older versions to the supported one
+// 3. Apply the AWS_TAGGY_ environment variables, then the overrides set on the loader
+// 4. Validate the parsed configuration structure
+// 5. Merge the tag criteria of the resources onto the templates they name
+// 6. Compile the tag validation patterns
 //
 // Parameters:
 //   - configPath: Full path to the configuration file
@@ -31,6 +47,58 @@ func NewTaggyScanConfigLoader() *ConfigLoader {
 //   - *TaggyScanConfig: Fully loaded and validated configuration
 //   - error: Any error encountered during loading or validation
 func (l *ConfigLoader) LoadConfig(configPath string) (*TaggyScanConfig, error) {
+	parsedCfg, err := l.ParseConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.load(parsedCfg)
+}
+
+// LoadEffectiveConfig loads a configuration that was already parsed and overridden, such as
+// the effective configuration recorded in a run manifest, like LoadConfig loads a file: it
+// is validated, its tag criteria templates merged and its patterns compiled. Neither the
+// AWS_TAGGY_ environment variables nor the overrides of the loader are applied again.
+func (l *ConfigLoader) LoadEffectiveConfig(cfg *TaggyScanConfig) (*TaggyScanConfig, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("no configuration to load")
+	}
+	return l.load(cfg)
+}
+
+// load validates a parsed configuration, merges its tag criteria templates and compiles its
+// patterns, storing it as the loaded configuration
+func (l *ConfigLoader) load(parsedCfg *TaggyScanConfig) (*TaggyScanConfig, error) {
+	// Validate configuration content
+	configValidator, err := NewContentValidator(parsedCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create configuration validator: %w", err)
+	}
+
+	// Perform comprehensive configuration validation
+	if err := configValidator.ValidateContent(); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	// Resources use the merged criteria from now on, see config show --resolve
+	if err := parsedCfg.ResolveTagCriteriaTemplates(); err != nil {
+		return nil, fmt.Errorf("failed to resolve tag criteria templates: %w", err)
+	}
+
+	// Compile the validated patterns once, validations share them afterwards
+	if err := parsedCfg.TagValidation.CompilePatterns(); err != nil {
+		return nil, fmt.Errorf("failed to compile tag validation patterns: %w", err)
+	}
+
+	// Store the loaded configuration
+	l.config = parsedCfg
+
+	return parsedCfg, nil
+}
+
+// ParseConfig reads and parses a configuration file without validating its content, which
+// lets callers such as linters report every content problem through a ContentValidator
+func (l *ConfigLoader) ParseConfig(configPath string) (*TaggyScanConfig, error) {
 	// Validate file path and existence
 	fileValidator, err := NewFileValidator(configPath)
 	if err != nil {
@@ -42,34 +110,44 @@ func (l *ConfigLoader) LoadConfig(configPath string) (*TaggyScanConfig, error) {
 		return nil, fmt.Errorf("configuration file validation failed: %w", err)
 	}
 
+	// The file validation already rejected unsupported extensions
+	format, err := DetectConfigFormat(configPath)
+	if err != nil {
+		return nil, err
+	}
+
 	// Read file contents
 	fileContent, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read configuration file: %w", err)
 	}
 
-	// Parse YAML
-	parsedCfg := &TaggyScanConfig{}
-	if err := yaml.Unmarshal(fileContent, parsedCfg); err != nil {
-		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
+	// Older configuration versions are upgraded in memory, config migrate rewrites the file
+	migration, err := MigrateConfig(fileContent, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file %s: %w", configPath, err)
+	}
+	if migration.Migrated() {
+		o11y.DefaultLogger().Warn(fmt.Sprintf("Configuration %s was migrated from version %s to %s, run 'aws-taggy config migrate' to upgrade the file",
+			configPath, migration.FromVersion, migration.ToVersion), "changes", strings.Join(migration.Changes, "; "))
 	}
 
-	// Normalize AWS configuration
-	NormalizeAWSConfig(&parsedCfg.AWS, &parsedCfg.Global)
-
-	// Validate configuration content
-	configValidator, err := NewContentValidator(parsedCfg)
+	parsedCfg, err := decodeConfig(migration.Content, migration.Format)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create configuration validator: %w", err)
+		return nil, fmt.Errorf("failed to parse configuration file %s: %w", configPath, err)
 	}
 
-	// Perform comprehensive configuration validation
-	if err := configValidator.ValidateContent(); err != nil {
-		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	// Overrides apply in increasing precedence: file, then environment, then explicit overrides
+	envOverrides, err := EnvOverrides(l.environ())
+	if err != nil {
+		return nil, err
+	}
+	if err := ApplyOverrides(parsedCfg, append(envOverrides, l.overrides...)); err != nil {
+		return nil, err
 	}
 
-	// Store the loaded configuration
-	l.config = parsedCfg
+	// Normalize AWS configuration
+	NormalizeAWSConfig(&parsedCfg.AWS, &parsedCfg.Global)
 
 	return parsedCfg, nil
 }
@@ -80,36 +158,23 @@ func (l *ConfigLoader) GetLoadedConfig() *TaggyScanConfig {
 	return l.config
 }
 
-// CompilePatternRules compiles regex patterns for tag validation
-// This method can be moved to the ConfigValidator if it makes more sense
+// CompilePatternRules compiles the regex patterns of the loaded tag validation rules.
+// LoadConfig already compiles them, this recompiles them after the rules were changed.
 func (l *ConfigLoader) CompilePatternRules() error {
 	if l.config == nil {
 		return fmt.Errorf("no configuration loaded")
 	}
 
-	// Initialize compiledRules if not already initialized
-	if l.config.TagValidation.compiledRules == nil {
-		l.config.TagValidation.compiledRules = make(map[string]*regexp.Regexp)
-	}
-
-	// Compile pattern rules
-	for tagName, pattern := range l.config.TagValidation.PatternRules {
-		compiled, err := regexp.Compile(pattern)
-		if err != nil {
-			return fmt.Errorf("invalid pattern for tag %s: %w", tagName, err)
-		}
-		l.config.TagValidation.compiledRules[tagName] = compiled
-	}
-
-	return nil
+	return l.config.TagValidation.CompilePatterns()
 }
 
-// GetComplianceLevelRequirements returns the compliance level requirements for the specified level
+// GetComplianceLevelRequirements returns the effective requirements for the specified level,
+// merged with those of the levels it extends
 // Returns nil if the level is not found
 func (l *ConfigLoader) GetComplianceLevelRequirements(level string) (*ComplianceLevel, error) {
-	complianceLevel, exists := l.config.ComplianceLevels[level]
-	if !exists {
-		return nil, fmt.Errorf("compliance level %s not found", level)
+	complianceLevel, err := l.config.ResolveComplianceLevel(level)
+	if err != nil {
+		return nil, err
 	}
 	return &complianceLevel, nil
 }
@@ -2,6 +2,7 @@
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -92,7 +93,7 @@ func TestConfigLoader(t *testing.T) {
   regions:
     mode: "all"`,
 			wantErr: true,
-			errMsg:  "key validation failed: key validation max length must be positive",
+			errMsg:  "tag_validation.key_validation.max_length: key validation max length must be positive",
 		},
 		{
 			name: "Invalid Tag Validation",
@@ -138,7 +139,7 @@ func TestConfigLoader(t *testing.T) {
 				assert.NotNil(t, cfg)
 				if cfg != nil {
 					// Add specific assertions for the loaded configuration
-					assert.Equal(t, "1.0", cfg.Version)
+					assert.Equal(t, "1.1", cfg.Version)
 					assert.Equal(t, "all", cfg.AWS.Regions.Mode)
 					assert.Equal(t, 2, cfg.Global.TagCriteria.MinimumRequiredTags)
 					assert.Contains(t, cfg.Global.TagCriteria.RequiredTags, "Environment")
@@ -190,3 +191,290 @@ func TestConfigLoader(t *testing.T) {
 		}
 	})
 }
+
+func TestConfigLoader_Formats(t *testing.T) {
+	const yamlConfig = `version: "1.0"
+aws:
+  regions:
+    mode: "all"
+global:
+  tag_criteria:
+    minimum_required_tags: 2
+    required_tags:
+      - "Environment"
+      - "Owner"
+tag_validation:
+  key_validation:
+    max_length: 128`
+
+	tests := []struct {
+		name      string
+		extension string
+		content   string
+		errMsg    string
+		check     func(t *testing.T, cfg *TaggyScanConfig)
+	}{
+		{
+			name:      "YAML",
+			extension: ".yaml",
+			content:   yamlConfig,
+			check: func(t *testing.T, cfg *TaggyScanConfig) {
+				assert.Equal(t, []string{"Environment", "Owner"}, cfg.Global.TagCriteria.RequiredTags)
+			},
+		},
+		{
+			name:      "YML",
+			extension: ".yml",
+			content:   yamlConfig,
+			check: func(t *testing.T, cfg *TaggyScanConfig) {
+				assert.Equal(t, []string{"Environment", "Owner"}, cfg.Global.TagCriteria.RequiredTags)
+			},
+		},
+		{
+			name:      "JSON",
+			extension: ".json",
+			content: `{
+  "version": "1.0",
+  "aws": {"regions": {"mode": "all"}},
+  "global": {
+    "tag_criteria": {
+      "minimum_required_tags": 2,
+      "required_tags": ["Environment", {"name": "Owner", "severity": "high"}]
+    }
+  },
+  "tag_validation": {
+    "key_validation": {"max_length": 128},
+    "pattern_rules": {
+      "CostCenter": "^[A-Z]{2}-[0-9]{4}$",
+      "Owner": {"pattern": "^[a-z]+@company\\.com$", "severity": "critical"}
+    }
+  }
+}`,
+			check: func(t *testing.T, cfg *TaggyScanConfig) {
+				assert.Equal(t, "all", cfg.AWS.Regions.Mode)
+				assert.Equal(t, []string{"Environment", "Owner"}, cfg.Global.TagCriteria.RequiredTags)
+				assert.Equal(t, map[string]Severity{"Owner": SeverityHigh}, cfg.Global.TagCriteria.RequiredTagSeverities)
+				assert.Equal(t, `^[a-z]+@company\.com$`, cfg.TagValidation.PatternRules["Owner"])
+				assert.Equal(t, map[string]Severity{"Owner": SeverityCritical}, cfg.TagValidation.PatternRuleSeverities)
+				assert.Equal(t, 128, cfg.TagValidation.KeyValidation.MaxLength)
+			},
+		},
+		{
+			name:      "Base And Override Documents",
+			extension: ".yaml",
+			content: yamlConfig + `
+resources:
+  s3:
+    enabled: true
+    tag_criteria:
+      minimum_required_tags: 1
+      required_tags: ["DataClassification"]
+---
+global:
+  tag_criteria:
+    minimum_required_tags: 3
+    required_tags: ["Environment", "Owner", "CostCenter"]
+resources:
+  ec2:
+    enabled: true
+    tag_criteria:
+      minimum_required_tags: 1
+`,
+			check: func(t *testing.T, cfg *TaggyScanConfig) {
+				// Settings of the override replace the base ones
+				assert.Equal(t, 3, cfg.Global.TagCriteria.MinimumRequiredTags)
+				assert.Equal(t, []string{"Environment", "Owner", "CostCenter"}, cfg.Global.TagCriteria.RequiredTags)
+
+				// Settings the override leaves out are kept, map entries are merged by key
+				assert.Equal(t, "all", cfg.AWS.Regions.Mode)
+				assert.Equal(t, 128, cfg.TagValidation.KeyValidation.MaxLength)
+				assert.Contains(t, cfg.Resources, "s3")
+				assert.Contains(t, cfg.Resources, "ec2")
+			},
+		},
+		{
+			name:      "Invalid JSON",
+			extension: ".json",
+			content:   `{"version": "1.0"`,
+			errMsg:    "failed to parse JSON configuration",
+		},
+		{
+			name:      "Invalid Override Document",
+			extension: ".yml",
+			content:   yamlConfig + "\n---\nglobal: [\n",
+			errMsg:    "failed to parse YAML configuration (document 2)",
+		},
+		{
+			name:      "Unsupported Extension",
+			extension: ".toml",
+			content:   `version = "1.0"`,
+			errMsg:    `unsupported configuration file extension ".toml"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config"+tt.extension)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o644))
+
+			cfg, err := NewTaggyScanConfigLoader().LoadConfig(path)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestConfigLoader_OverridePrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`version: "1.0"
+aws:
+  regions:
+    mode: "all"
+  batch_size: 100
+global:
+  tag_criteria:
+    minimum_required_tags: 1
+    required_tags:
+      - "Owner"
+tag_validation:
+  key_validation:
+    max_length: 128
+`), 0o644))
+
+	tests := []struct {
+		name              string
+		environ           []string
+		set               []string
+		expectedBatchSize int
+		expectedMode      string
+	}{
+		{
+			name:              "File",
+			expectedBatchSize: 100,
+			expectedMode:      "all",
+		},
+		{
+			name:              "Environment Over File",
+			environ:           []string{"AWS_TAGGY_AWS_BATCH_SIZE=50"},
+			expectedBatchSize: 50,
+			expectedMode:      "all",
+		},
+		{
+			name:              "Flag Over Environment",
+			environ:           []string{"AWS_TAGGY_AWS_BATCH_SIZE=50", "AWS_TAGGY_AWS_REGIONS_MODE=all"},
+			set:               []string{"aws.batch_size=25", "aws.regions.mode=specific", "aws.regions.list=us-east-1,eu-west-1"},
+			expectedBatchSize: 25,
+			expectedMode:      "specific",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overrides, err := ParseOverrides(tt.set)
+			require.NoError(t, err)
+
+			loader := NewTaggyScanConfigLoader()
+			loader.environ = func() []string { return tt.environ }
+			loader.SetOverrides(overrides)
+
+			cfg, err := loader.LoadConfig(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBatchSize, *cfg.AWS.BatchSize)
+			assert.Equal(t, tt.expectedMode, cfg.AWS.Regions.Mode)
+		})
+	}
+
+	t.Run("Unknown Environment Setting", func(t *testing.T) {
+		loader := NewTaggyScanConfigLoader()
+		loader.environ = func() []string { return []string{"AWS_TAGGY_AWS_BATCH=50"} }
+
+		_, err := loader.LoadConfig(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "AWS_TAGGY_AWS_BATCH does not match any configuration setting")
+	})
+}
+
+func TestConfigLoader_TagCriteriaTemplates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`version: "1.0"
+aws:
+  regions:
+    mode: "all"
+global:
+  tag_criteria:
+    minimum_required_tags: 1
+    required_tags:
+      - "Owner"
+tag_criteria_templates:
+  workload:
+    minimum_required_tags: 2
+    required_tags:
+      - "Environment"
+      - name: "CostCenter"
+        severity: critical
+resources:
+  ec2:
+    enabled: true
+    tag_criteria:
+      template: workload
+  rds:
+    enabled: true
+    tag_criteria:
+      template: workload
+      required_tags:
+        - "BackupPolicy"
+tag_validation:
+  key_validation:
+    max_length: 128
+`), 0o644))
+
+	cfg, err := NewTaggyScanConfigLoader().LoadConfig(path)
+	require.NoError(t, err)
+
+	ec2Criteria := cfg.Resources["ec2"].TagCriteria
+	assert.Empty(t, ec2Criteria.Template)
+	assert.Equal(t, 2, ec2Criteria.MinimumRequiredTags)
+	assert.Equal(t, []string{"Environment", "CostCenter"}, ec2Criteria.RequiredTags)
+	assert.Equal(t, SeverityCritical, ec2Criteria.RequiredTagSeverity("CostCenter"))
+	assert.Equal(t, []string{"Environment", "CostCenter", "BackupPolicy"}, cfg.Resources["rds"].TagCriteria.RequiredTags)
+}
+
+func TestConfigLoader_RejectsMalformedPatterns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`version: "1.0"
+aws:
+  regions:
+    mode: "all"
+global:
+  tag_criteria:
+    minimum_required_tags: 1
+    required_tags:
+      - "Owner"
+resources:
+  s3:
+    enabled: true
+tag_validation:
+  key_validation:
+    max_length: 128
+  pattern_rules:
+    Owner: "^[A-Z{2,}$"
+  case_rules:
+    Project:
+      case: mixed
+      pattern: "(?P<team"
+  key_format_rules:
+    - pattern: "+Key"
+`), 0o644))
+
+	_, err := NewTaggyScanConfigLoader().LoadConfig(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid pattern rule for tag Owner: error parsing regexp: missing closing ]: `[A-Z{2,}$`")
+	assert.Contains(t, err.Error(), "invalid pattern for tag Project: error parsing regexp: invalid named capture: `(?P<team`")
+	assert.Contains(t, err.Error(), "invalid key format pattern: error parsing regexp: missing argument to repetition operator: `+`")
+}
@@ -0,0 +1,432 @@
+package configuration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"gopkg.in/yaml.v3"
+)
+
+// unversionedConfigVersion is the version of configuration files that do not declare one
+const unversionedConfigVersion = "1.0"
+
+// ConfigMigration upgrades the documents of a configuration file from a version to the next
+type ConfigMigration struct {
+	From        string
+	To          string
+	Description string
+
+	// Migrate rewrites a configuration document in place, returning a description of every
+	// change it made
+	Migrate func(document *yaml.Node) []string
+}
+
+// configMigrations chains the migrations up to constants.SupportedConfigVersion, in order
+var configMigrations = []ConfigMigration{
+	{
+		From:        "1.0",
+		To:          "1.1",
+		Description: "fold the flat severity, example and deprecation maps into the structured entries of required tags, pattern rules and allowed values",
+		Migrate:     migrateStructuredEntries,
+	},
+}
+
+// MigrationResult is a configuration file upgraded to the supported version
+type MigrationResult struct {
+	// FromVersion is the version the file declares, 1.0 when it declares none, and ToVersion
+	// the version it was upgraded to
+	FromVersion string
+	ToVersion   string
+
+	// Changes describes what the migrations rewrote, besides the version
+	Changes []string
+
+	// Content is the upgraded file in Format. A file that needed no migration is returned
+	// unchanged, an upgraded one is written as YAML.
+	Content []byte
+	Format  ConfigFormat
+}
+
+// Migrated reports whether the file was upgraded from an older version
+func (r *MigrationResult) Migrated() bool {
+	return r.FromVersion != r.ToVersion
+}
+
+// MigrateConfig upgrades configuration content written in the given format to the supported
+// version through the chain of migrations. Content of the supported version, or whose version
+// is malformed and left to validation, is returned unchanged. Versions newer than the
+// supported one are rejected.
+func MigrateConfig(content []byte, format ConfigFormat) (*MigrationResult, error) {
+	documents, err := parseConfigDocuments(content, format)
+	if err != nil {
+		return nil, err
+	}
+
+	version := documentsVersion(documents)
+	result := &MigrationResult{FromVersion: version, ToVersion: version, Content: content, Format: format}
+
+	comparison, ok := compareConfigVersions(version, constants.SupportedConfigVersion)
+	if !ok || comparison == 0 {
+		return result, nil
+	}
+	if comparison > 0 {
+		return nil, fmt.Errorf("configuration version %s is newer than the supported version %s, upgrade aws-taggy to read it",
+			version, constants.SupportedConfigVersion)
+	}
+
+	for result.ToVersion != constants.SupportedConfigVersion {
+		migration, found := findConfigMigration(result.ToVersion)
+		if !found {
+			return nil, fmt.Errorf("configuration version %s cannot be migrated to the supported version %s",
+				result.ToVersion, constants.SupportedConfigVersion)
+		}
+		for _, document := range documents {
+			result.Changes = append(result.Changes, migration.Migrate(document)...)
+		}
+		result.ToVersion = migration.To
+	}
+	setDocumentsVersion(documents, result.ToVersion)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for _, document := range documents {
+		if err := encoder.Encode(document); err != nil {
+			return nil, fmt.Errorf("failed to write migrated configuration: %w", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write migrated configuration: %w", err)
+	}
+
+	result.Content = buf.Bytes()
+	result.Format = ConfigFormatYAML
+	return result, nil
+}
+
+// parseConfigDocuments parses the documents of configuration content into YAML nodes. JSON
+// content is a single document, whose keys lose their order.
+func parseConfigDocuments(content []byte, format ConfigFormat) ([]*yaml.Node, error) {
+	if format == ConfigFormatJSON {
+		var value interface{}
+		if err := json.Unmarshal(content, &value); err != nil {
+			return nil, fmt.Errorf("failed to parse %s configuration: %w", format, err)
+		}
+		document := &yaml.Node{}
+		if err := document.Encode(value); err != nil {
+			return nil, fmt.Errorf("failed to parse %s configuration: %w", format, err)
+		}
+		return []*yaml.Node{{Kind: yaml.DocumentNode, Content: []*yaml.Node{document}}}, nil
+	}
+
+	var documents []*yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for number := 1; ; number++ {
+		document := &yaml.Node{}
+		err := decoder.Decode(document)
+		if errors.Is(err, io.EOF) {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s configuration (document %d): %w", format, number, err)
+		}
+		documents = append(documents, document)
+	}
+}
+
+// documentsVersion returns the version of a configuration file, set by the last document
+// declaring one like any other setting
+func documentsVersion(documents []*yaml.Node) string {
+	version := unversionedConfigVersion
+	for _, document := range documents {
+		if node := mappingValue(documentRoot(document), "version"); node != nil && node.Kind == yaml.ScalarNode {
+			version = strings.Trim(strings.TrimSpace(node.Value), `"'`)
+		}
+	}
+	return version
+}
+
+// setDocumentsVersion sets the version of a configuration file in the documents declaring
+// one, or in the first document when none does
+func setDocumentsVersion(documents []*yaml.Node, version string) {
+	set := false
+	for _, document := range documents {
+		if node := mappingValue(documentRoot(document), "version"); node != nil {
+			*node = *stringNode(version)
+			set = true
+		}
+	}
+	if set || len(documents) == 0 {
+		return
+	}
+
+	if root := documentRoot(documents[0]); root != nil {
+		root.Content = append([]*yaml.Node{stringNode("version"), stringNode(version)}, root.Content...)
+	}
+}
+
+// findConfigMigration returns the migration upgrading the given version
+func findConfigMigration(version string) (ConfigMigration, bool) {
+	for _, migration := range configMigrations {
+		if migration.From == version {
+			return migration, true
+		}
+	}
+	return ConfigMigration{}, false
+}
+
+// compareConfigVersions compares two X.Y versions, returning a negative number when a is
+// older than b, zero when they are equal and a positive number when a is newer. It returns
+// false when either version is malformed.
+func compareConfigVersions(a, b string) (int, bool) {
+	parse := func(version string) ([2]int, bool) {
+		major, minor, found := strings.Cut(version, ".")
+		if !found {
+			return [2]int{}, false
+		}
+		majorNumber, majorErr := strconv.Atoi(major)
+		minorNumber, minorErr := strconv.Atoi(minor)
+		return [2]int{majorNumber, minorNumber}, majorErr == nil && minorErr == nil && majorNumber >= 0 && minorNumber >= 0
+	}
+
+	versionA, okA := parse(a)
+	versionB, okB := parse(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	if versionA[0] != versionB[0] {
+		return versionA[0] - versionB[0], true
+	}
+	return versionA[1] - versionB[1], true
+}
+
+// migrateStructuredEntries folds the required_tag_severities of the tag criteria, the
+// pattern_rule_severities and pattern_rule_examples, and the deprecated_values of the tag
+// validation into the entries of the required tags, pattern rules and allowed values they
+// describe. Entries whose tag or value is not listed are left in their flat map.
+func migrateStructuredEntries(document *yaml.Node) []string {
+	root := documentRoot(document)
+	if root == nil {
+		return nil
+	}
+
+	var changes []string
+	changes = append(changes, foldRequiredTagSeverities(mappingValue(mappingValue(root, "global"), "tag_criteria"), "global.tag_criteria")...)
+	for _, entry := range mappingEntries(mappingValue(root, "resources")) {
+		criteria := mappingValue(entry.value, "tag_criteria")
+		changes = append(changes, foldRequiredTagSeverities(criteria, "resources."+entry.key+".tag_criteria")...)
+	}
+	for _, entry := range mappingEntries(mappingValue(root, "tag_criteria_templates")) {
+		changes = append(changes, foldRequiredTagSeverities(entry.value, "tag_criteria_templates."+entry.key)...)
+	}
+
+	validation := mappingValue(root, "tag_validation")
+	changes = append(changes, foldPatternRuleSettings(validation, "pattern_rule_severities", "severity")...)
+	changes = append(changes, foldPatternRuleSettings(validation, "pattern_rule_examples", "example")...)
+	changes = append(changes, foldDeprecatedValues(validation)...)
+	return changes
+}
+
+// foldRequiredTagSeverities folds the required_tag_severities of tag criteria into
+// {name, severity} entries of their required_tags
+func foldRequiredTagSeverities(criteria *yaml.Node, path string) []string {
+	requiredTags := mappingValue(criteria, "required_tags")
+	if requiredTags == nil || requiredTags.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	return foldMapping(criteria, "required_tag_severities", func(tag string, severity *yaml.Node) (string, bool) {
+		for i, item := range requiredTags.Content {
+			switch {
+			case item.Kind == yaml.ScalarNode && item.Value == tag:
+				requiredTags.Content[i] = mappingNode("name", stringNode(tag), "severity", severity)
+			case item.Kind == yaml.MappingNode && scalarValue(mappingValue(item, "name")) == tag:
+				setMappingValue(item, "severity", severity)
+			default:
+				continue
+			}
+			return fmt.Sprintf("%s.required_tag_severities.%s moved to the entry of %s in %s.required_tags", path, tag, tag, path), true
+		}
+		return "", false
+	})
+}
+
+// foldPatternRuleSettings folds a flat map of pattern rule settings, such as the
+// pattern_rule_severities, into the given field of {pattern, ...} pattern rules
+func foldPatternRuleSettings(validation *yaml.Node, flatKey, field string) []string {
+	patternRules := mappingValue(validation, "pattern_rules")
+	if patternRules == nil || patternRules.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	return foldMapping(validation, flatKey, func(tag string, setting *yaml.Node) (string, bool) {
+		index := mappingIndex(patternRules, tag)
+		if index < 0 {
+			return "", false
+		}
+		switch rule := patternRules.Content[index+1]; rule.Kind {
+		case yaml.ScalarNode:
+			patternRules.Content[index+1] = mappingNode("pattern", rule, field, setting)
+		case yaml.MappingNode:
+			setMappingValue(rule, field, setting)
+		default:
+			return "", false
+		}
+		return fmt.Sprintf("tag_validation.%s.%s moved to tag_validation.pattern_rules.%s.%s", flatKey, tag, tag, field), true
+	})
+}
+
+// foldDeprecatedValues folds the deprecated_values of the tag validation into
+// {value, deprecated, replacement} entries of the allowed values
+func foldDeprecatedValues(validation *yaml.Node) []string {
+	allowedValues := mappingValue(validation, "allowed_values")
+	deprecatedValues := mappingValue(validation, "deprecated_values")
+	if allowedValues == nil || deprecatedValues == nil || deprecatedValues.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var changes []string
+	for _, entry := range mappingEntries(deprecatedValues) {
+		values := mappingValue(allowedValues, entry.key)
+		if values == nil || values.Kind != yaml.SequenceNode {
+			continue
+		}
+		changes = append(changes, foldMapping(deprecatedValues, entry.key, func(value string, replacement *yaml.Node) (string, bool) {
+			for i, item := range values.Content {
+				if item.Kind != yaml.ScalarNode || item.Value != value {
+					continue
+				}
+				folded := mappingNode("value", item, "deprecated", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+				if scalarValue(replacement) != "" {
+					setMappingValue(folded, "replacement", replacement)
+				}
+				values.Content[i] = folded
+				return fmt.Sprintf("tag_validation.deprecated_values.%s.%s moved to its entry in tag_validation.allowed_values.%s", entry.key, value, entry.key), true
+			}
+			return "", false
+		})...)
+	}
+	if len(deprecatedValues.Content) == 0 {
+		removeMappingValue(validation, "deprecated_values")
+	}
+	return changes
+}
+
+// foldMapping hands every entry of the mapping under key to fold, removing the entries it
+// folded, and the mapping once empty. It returns the changes reported by fold.
+func foldMapping(parent *yaml.Node, key string, fold func(key string, value *yaml.Node) (string, bool)) []string {
+	flat := mappingValue(parent, key)
+	if flat == nil || flat.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var changes []string
+	var kept []*yaml.Node
+	for i := 0; i+1 < len(flat.Content); i += 2 {
+		if change, folded := fold(flat.Content[i].Value, flat.Content[i+1]); folded {
+			changes = append(changes, change)
+			continue
+		}
+		kept = append(kept, flat.Content[i], flat.Content[i+1])
+	}
+
+	flat.Content = kept
+	if len(kept) == 0 {
+		removeMappingValue(parent, key)
+	}
+	return changes
+}
+
+// mappingEntry is a key of a mapping node along with its value
+type mappingEntry struct {
+	key   string
+	value *yaml.Node
+}
+
+// mappingEntries returns the entries of a mapping node, none when the node is not a mapping
+func mappingEntries(node *yaml.Node) []mappingEntry {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	entries := make([]mappingEntry, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		entries = append(entries, mappingEntry{key: node.Content[i].Value, value: node.Content[i+1]})
+	}
+	return entries
+}
+
+// documentRoot returns the mapping at the root of a document node, nil when it holds none
+func documentRoot(document *yaml.Node) *yaml.Node {
+	if document == nil || document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil
+	}
+	if root := document.Content[0]; root.Kind == yaml.MappingNode {
+		return root
+	}
+	return nil
+}
+
+// mappingIndex returns the index of a key in a mapping node, -1 when it is missing
+func mappingIndex(node *yaml.Node, key string) int {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mappingValue returns the value of a key in a mapping node, nil when it is missing
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if index := mappingIndex(node, key); index >= 0 {
+		return node.Content[index+1]
+	}
+	return nil
+}
+
+// setMappingValue sets the value of a key in a mapping node, appending the key when missing
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	if index := mappingIndex(node, key); index >= 0 {
+		node.Content[index+1] = value
+		return
+	}
+	node.Content = append(node.Content, stringNode(key), value)
+}
+
+// removeMappingValue removes a key and its value from a mapping node
+func removeMappingValue(node *yaml.Node, key string) {
+	if index := mappingIndex(node, key); index >= 0 {
+		node.Content = append(node.Content[:index], node.Content[index+2:]...)
+	}
+}
+
+// mappingNode returns a mapping node of the given keys and values
+func mappingNode(keysAndValues ...interface{}) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		node.Content = append(node.Content, stringNode(keysAndValues[i].(string)), keysAndValues[i+1].(*yaml.Node))
+	}
+	return node
+}
+
+// stringNode returns a string scalar node
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// scalarValue returns the value of a scalar node, empty for any other node
+func scalarValue(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
@@ -0,0 +1,248 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const legacyConfig = `version: "1.0"
+aws:
+  regions:
+    mode: all
+global:
+  enabled: true
+  tag_criteria:
+    minimum_required_tags: 2
+    required_tags:
+      - Owner
+      - Environment
+    required_tag_severities:
+      Owner: critical
+resources:
+  s3:
+    enabled: true
+    tag_criteria:
+      required_tags:
+        - DataClassification
+      required_tag_severities:
+        DataClassification: high
+        Unlisted: low
+tag_criteria_templates:
+  baseline:
+    required_tags:
+      - CostCenter
+    required_tag_severities:
+      CostCenter: medium
+tag_validation:
+  allowed_values:
+    Environment:
+      - production
+      - prod
+      - staging
+  deprecated_values:
+    Environment:
+      prod: production
+  pattern_rules:
+    Owner: "^[a-z]+@example\\.com$"
+    CostCenter: "^CC-[0-9]+$"
+  pattern_rule_severities:
+    Owner: high
+  pattern_rule_examples:
+    Owner: team@example.com
+    CostCenter: CC-1234
+`
+
+func TestMigrateConfig_RoundTrip(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		format  ConfigFormat
+	}{
+		{name: "YAML", content: legacyConfig, format: ConfigFormatYAML},
+		{
+			name:   "YAML Override Document",
+			format: ConfigFormatYAML,
+			content: legacyConfig + `---
+tag_validation:
+  pattern_rules:
+    Team: "^[a-z-]+$"
+  pattern_rule_severities:
+    Team: low
+`,
+		},
+		{
+			name:   "JSON",
+			format: ConfigFormatJSON,
+			content: `{
+  "version": "1.0",
+  "aws": {"regions": {"mode": "all"}, "batch_size": 20},
+  "global": {
+    "enabled": true,
+    "tag_criteria": {
+      "required_tags": ["Owner"],
+      "required_tag_severities": {"Owner": "critical"}
+    }
+  },
+  "tag_validation": {
+    "allowed_values": {"Environment": ["production", "prod"]},
+    "deprecated_values": {"Environment": {"prod": "production"}}
+  }
+}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original, err := decodeConfig([]byte(tc.content), tc.format)
+			require.NoError(t, err)
+
+			result, err := MigrateConfig([]byte(tc.content), tc.format)
+			require.NoError(t, err)
+			assert.True(t, result.Migrated())
+			assert.Equal(t, "1.0", result.FromVersion)
+			assert.Equal(t, "1.1", result.ToVersion)
+			assert.Equal(t, ConfigFormatYAML, result.Format)
+
+			migrated, err := decodeConfig(result.Content, result.Format)
+			require.NoError(t, err)
+			assert.Equal(t, "1.1", migrated.Version)
+
+			original.Version = migrated.Version
+			assert.Equal(t, original, migrated)
+
+			// A migrated file needs no further migration
+			again, err := MigrateConfig(result.Content, result.Format)
+			require.NoError(t, err)
+			assert.False(t, again.Migrated())
+			assert.Empty(t, again.Changes)
+			assert.Equal(t, result.Content, again.Content)
+		})
+	}
+}
+
+func TestMigrateConfig_FoldsStructuredEntries(t *testing.T) {
+	result, err := MigrateConfig([]byte(legacyConfig), ConfigFormatYAML)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"global.tag_criteria.required_tag_severities.Owner moved to the entry of Owner in global.tag_criteria.required_tags",
+		"resources.s3.tag_criteria.required_tag_severities.DataClassification moved to the entry of DataClassification in resources.s3.tag_criteria.required_tags",
+		"tag_criteria_templates.baseline.required_tag_severities.CostCenter moved to the entry of CostCenter in tag_criteria_templates.baseline.required_tags",
+		"tag_validation.pattern_rule_severities.Owner moved to tag_validation.pattern_rules.Owner.severity",
+		"tag_validation.pattern_rule_examples.Owner moved to tag_validation.pattern_rules.Owner.example",
+		"tag_validation.pattern_rule_examples.CostCenter moved to tag_validation.pattern_rules.CostCenter.example",
+		"tag_validation.deprecated_values.Environment.prod moved to its entry in tag_validation.allowed_values.Environment",
+	}, result.Changes)
+
+	var migrated map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(result.Content, &migrated))
+	assert.Equal(t, "1.1", migrated["version"])
+
+	global := migrated["global"].(map[string]interface{})["tag_criteria"].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "Owner", "severity": "critical"},
+		"Environment",
+	}, global["required_tags"])
+	assert.NotContains(t, global, "required_tag_severities")
+
+	// Severities of tags that are not required stay in their flat map
+	s3 := migrated["resources"].(map[string]interface{})["s3"].(map[string]interface{})["tag_criteria"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"Unlisted": "low"}, s3["required_tag_severities"])
+
+	validation := migrated["tag_validation"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"Owner":      map[string]interface{}{"pattern": `^[a-z]+@example\.com$`, "severity": "high", "example": "team@example.com"},
+		"CostCenter": map[string]interface{}{"pattern": "^CC-[0-9]+$", "example": "CC-1234"},
+	}, validation["pattern_rules"])
+	assert.Equal(t, []interface{}{
+		"production",
+		map[string]interface{}{"value": "prod", "deprecated": true, "replacement": "production"},
+		"staging",
+	}, validation["allowed_values"].(map[string]interface{})["Environment"])
+	assert.NotContains(t, validation, "pattern_rule_severities")
+	assert.NotContains(t, validation, "pattern_rule_examples")
+	assert.NotContains(t, validation, "deprecated_values")
+}
+
+func TestMigrateConfig_Versions(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		wantVersion string
+		wantChanged bool
+		errMsg      string
+	}{
+		{
+			name:        "Supported Version",
+			content:     "version: \"1.1\"\naws:\n  regions:\n    mode: all\n",
+			wantVersion: "1.1",
+		},
+		{
+			name:        "Unversioned",
+			content:     "aws:\n  regions:\n    mode: all\n",
+			wantVersion: "1.1",
+			wantChanged: true,
+		},
+		{
+			name:        "Malformed Version Left To Validation",
+			content:     "version: latest\n",
+			wantVersion: "latest",
+		},
+		{
+			name:    "Newer Version",
+			content: "version: \"1.2\"\n",
+			errMsg:  "configuration version 1.2 is newer than the supported version 1.1",
+		},
+		{
+			name:    "Newer Major Version",
+			content: "version: \"2.0\"\n",
+			errMsg:  "configuration version 2.0 is newer than the supported version 1.1",
+		},
+		{
+			name:    "No Migration Path",
+			content: "version: \"0.9\"\n",
+			errMsg:  "configuration version 0.9 cannot be migrated to the supported version 1.1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := MigrateConfig([]byte(tc.content), ConfigFormatYAML)
+			if tc.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantVersion, result.ToVersion)
+			assert.Equal(t, tc.wantChanged, result.Migrated())
+			if !tc.wantChanged {
+				assert.Equal(t, tc.content, string(result.Content))
+			}
+		})
+	}
+}
+
+func TestLoadConfig_MigratesLegacyVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := strings.Replace(legacyConfig, "        Unlisted: low\n", "", 1) + "  key_validation:\n    max_length: 128\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	cfg, err := NewTaggyScanConfigLoader().LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1", cfg.Version)
+	assert.Equal(t, Severity("critical"), cfg.Global.TagCriteria.RequiredTagSeverities["Owner"])
+	assert.Equal(t, "production", cfg.TagValidation.DeprecatedValues["Environment"]["prod"])
+
+	require.NoError(t, os.WriteFile(path, []byte("version: \"1.2\"\n"), 0o644))
+	_, err = NewTaggyScanConfigLoader().LoadConfig(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "newer than the supported version")
+}
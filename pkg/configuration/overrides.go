@@ -0,0 +1,228 @@
+package configuration
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvOverridePrefix prefixes the environment variables overriding configuration settings,
+// e.g. AWS_TAGGY_AWS_BATCH_SIZE=50 overrides aws.batch_size
+const EnvOverridePrefix = "AWS_TAGGY_"
+
+// EndpointURLEnvVar overrides aws.endpoint_url, as a shorter alias of AWS_TAGGY_AWS_ENDPOINT_URL
+const EndpointURLEnvVar = EnvOverridePrefix + "ENDPOINT_URL"
+
+// envAliases maps environment variables to the setting they override when their name does
+// not follow the setting path
+var envAliases = map[string]string{
+	EndpointURLEnvVar: "aws.endpoint_url",
+}
+
+// Override replaces the value of a single configuration setting
+type Override struct {
+	// Path locates the setting by its YAML keys joined with dots (e.g. aws.regions.mode).
+	// Entries of maps are addressed by their key, e.g. resources.s3.enabled
+	Path string
+
+	// Value is the new value of the setting. Lists are written as comma-separated values.
+	Value string
+
+	// Source describes where the override comes from, for error messages
+	Source string
+}
+
+// ParseOverrides parses overrides written as path=value, as given to the --set flag
+func ParseOverrides(assignments []string) ([]Override, error) {
+	overrides := make([]Override, 0, len(assignments))
+	for _, assignment := range assignments {
+		path, value, found := strings.Cut(assignment, "=")
+		path = strings.TrimSpace(path)
+		if !found || path == "" {
+			return nil, fmt.Errorf("invalid override %q, expected path=value", assignment)
+		}
+		overrides = append(overrides, Override{Path: path, Value: value, Source: "--set " + path})
+	}
+	return overrides, nil
+}
+
+// EnvOverrides returns the overrides set through AWS_TAGGY_ prefixed variables of environ,
+// given as KEY=value entries like os.Environ returns them. The variable name is the setting
+// path in upper case with dots replaced by underscores. Settings inside maps, such as
+// resources, cannot be overridden through the environment.
+func EnvOverrides(environ []string) ([]Override, error) {
+	var overrides []Override
+	for _, entry := range environ {
+		name, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, EnvOverridePrefix) {
+			continue
+		}
+
+		path, ok := envAliases[name]
+		if !ok {
+			path, ok = envSettingPath(reflect.TypeOf(TaggyScanConfig{}), strings.TrimPrefix(name, EnvOverridePrefix))
+		}
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s does not match any configuration setting", name)
+		}
+		overrides = append(overrides, Override{Path: path, Value: value, Source: name})
+	}
+	return overrides, nil
+}
+
+// ApplyOverrides applies the overrides to the configuration, in order
+func ApplyOverrides(cfg *TaggyScanConfig, overrides []Override) error {
+	for _, override := range overrides {
+		segments := strings.Split(override.Path, ".")
+		if err := setSetting(reflect.ValueOf(cfg).Elem(), segments, override.Value); err != nil {
+			source := override.Source
+			if source == "" {
+				source = override.Path
+			}
+			return fmt.Errorf("invalid override %s: %w", source, err)
+		}
+	}
+	return nil
+}
+
+// setSetting assigns value to the setting found by following the path segments from target
+func setSetting(target reflect.Value, segments []string, value string) error {
+	if target.Kind() == reflect.Pointer {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		target = target.Elem()
+	}
+
+	if len(segments) == 0 {
+		return setValue(target, value)
+	}
+
+	switch target.Kind() {
+	case reflect.Struct:
+		field, ok := fieldByYAMLName(target, segments[0])
+		if !ok {
+			return fmt.Errorf("unknown configuration setting %q", segments[0])
+		}
+		return setSetting(field, segments[1:], value)
+
+	case reflect.Map:
+		if target.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("setting %q cannot be overridden", segments[0])
+		}
+		if target.IsNil() {
+			target.Set(reflect.MakeMap(target.Type()))
+		}
+
+		// Map entries are not addressable, so the entry is updated on a copy stored back
+		key := reflect.ValueOf(segments[0]).Convert(target.Type().Key())
+		entry := reflect.New(target.Type().Elem()).Elem()
+		if existing := target.MapIndex(key); existing.IsValid() {
+			entry.Set(existing)
+		}
+		if err := setSetting(entry, segments[1:], value); err != nil {
+			return err
+		}
+		target.SetMapIndex(key, entry)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown configuration setting %q", segments[0])
+	}
+}
+
+// setValue parses value into the setting according to its type
+func setValue(target reflect.Value, value string) error {
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(value)
+	case reflect.Int, reflect.Int64:
+		parsed, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", value)
+		}
+		target.SetInt(int64(parsed))
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("expected a number, got %q", value)
+		}
+		target.SetFloat(parsed)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("expected a boolean, got %q", value)
+		}
+		target.SetBool(parsed)
+	case reflect.Slice:
+		if target.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("lists of %s cannot be overridden", target.Type().Elem())
+		}
+		items := reflect.MakeSlice(target.Type(), 0, 0)
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = reflect.Append(items, reflect.ValueOf(item).Convert(target.Type().Elem()))
+			}
+		}
+		target.Set(items)
+	case reflect.Struct, reflect.Map:
+		return fmt.Errorf("a whole section cannot be overridden, set one of its settings instead")
+	default:
+		return fmt.Errorf("settings of type %s cannot be overridden", target.Type())
+	}
+	return nil
+}
+
+// fieldByYAMLName returns the field of a struct whose YAML key is name
+func fieldByYAMLName(target reflect.Value, name string) (reflect.Value, bool) {
+	for i := 0; i < target.NumField(); i++ {
+		if yamlName(target.Type().Field(i)) == name {
+			return target.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// envSettingPath resolves the name of an environment variable, stripped from its prefix,
+// into the path of the struct setting it designates
+func envSettingPath(t reflect.Type, name string) (string, bool) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return "", false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := yamlName(field)
+		if key == "" {
+			continue
+		}
+
+		envName := strings.ToUpper(key)
+		if name == envName {
+			return key, true
+		}
+		if rest, ok := strings.CutPrefix(name, envName+"_"); ok {
+			if path, ok := envSettingPath(field.Type, rest); ok {
+				return key + "." + path, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// yamlName returns the YAML key of a struct field, empty for fields not read from YAML
+func yamlName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
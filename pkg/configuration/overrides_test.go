@@ -0,0 +1,127 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		overrides []string
+		errMsg    string
+		check     func(t *testing.T, cfg *TaggyScanConfig)
+	}{
+		{
+			name:      "String",
+			overrides: []string{"aws.regions.mode=specific"},
+			check: func(t *testing.T, cfg *TaggyScanConfig) {
+				assert.Equal(t, "specific", cfg.AWS.Regions.Mode)
+			},
+		},
+		{
+			name:      "Integer Pointer",
+			overrides: []string{"aws.batch_size=50"},
+			check: func(t *testing.T, cfg *TaggyScanConfig) {
+				require.NotNil(t, cfg.AWS.BatchSize)
+				assert.Equal(t, 50, *cfg.AWS.BatchSize)
+			},
+		},
+		{
+			name:      "Boolean",
+			overrides: []string{"global.enabled=true"},
+			check: func(t *testing.T, cfg *TaggyScanConfig) {
+				assert.True(t, cfg.Global.Enabled)
+			},
+		},
+		{
+			name:      "String List",
+			overrides: []string{"aws.regions.list=us-east-1, eu-west-1"},
+			check: func(t *testing.T, cfg *TaggyScanConfig) {
+				assert.Equal(t, []string{"us-east-1", "eu-west-1"}, cfg.AWS.Regions.List)
+			},
+		},
+		{
+			name:      "Map Entry",
+			overrides: []string{"resources.s3.enabled=true"},
+			check: func(t *testing.T, cfg *TaggyScanConfig) {
+				assert.True(t, cfg.Resources["s3"].Enabled)
+			},
+		},
+		{
+			name:      "Later Override Wins",
+			overrides: []string{"aws.batch_size=50", "aws.batch_size=25"},
+			check: func(t *testing.T, cfg *TaggyScanConfig) {
+				assert.Equal(t, 25, *cfg.AWS.BatchSize)
+			},
+		},
+		{
+			name:      "Unknown Setting",
+			overrides: []string{"aws.regions.modes=all"},
+			errMsg:    `invalid override --set aws.regions.modes: unknown configuration setting "modes"`,
+		},
+		{
+			name:      "Invalid Integer",
+			overrides: []string{"aws.batch_size=many"},
+			errMsg:    `expected an integer, got "many"`,
+		},
+		{
+			name:      "Whole Section",
+			overrides: []string{"aws.regions=all"},
+			errMsg:    "a whole section cannot be overridden",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			overrides, err := ParseOverrides(tc.overrides)
+			require.NoError(t, err)
+
+			cfg := &TaggyScanConfig{}
+			err = ApplyOverrides(cfg, overrides)
+			if tc.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			tc.check(t, cfg)
+		})
+	}
+}
+
+func TestParseOverrides_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseOverrides([]string{"aws.batch_size"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected path=value")
+}
+
+func TestEnvOverrides(t *testing.T) {
+	t.Parallel()
+
+	overrides, err := EnvOverrides([]string{
+		"HOME=/root",
+		"AWS_TAGGY_AWS_BATCH_SIZE=50",
+		"AWS_TAGGY_AWS_REGIONS_LIST=us-east-1,eu-west-1",
+		"AWS_TAGGY_ENDPOINT_URL=http://localhost:4566",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Override{
+		{Path: "aws.batch_size", Value: "50", Source: "AWS_TAGGY_AWS_BATCH_SIZE"},
+		{Path: "aws.regions.list", Value: "us-east-1,eu-west-1", Source: "AWS_TAGGY_AWS_REGIONS_LIST"},
+		{Path: "aws.endpoint_url", Value: "http://localhost:4566", Source: "AWS_TAGGY_ENDPOINT_URL"},
+	}, overrides)
+
+	_, err = EnvOverrides([]string{"AWS_TAGGY_UNKNOWN=1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AWS_TAGGY_UNKNOWN does not match any configuration setting")
+}
@@ -0,0 +1,47 @@
+package configuration
+
+import (
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+)
+
+// PartitionOfRegion returns the AWS partition a region belongs to, the commercial aws
+// partition for any region outside GovCloud (US) and China
+func PartitionOfRegion(region string) string {
+	switch {
+	case strings.HasPrefix(region, "us-gov-"):
+		return constants.PartitionAWSUSGov
+	case strings.HasPrefix(region, "cn-"):
+		return constants.PartitionAWSCN
+	default:
+		return constants.PartitionAWS
+	}
+}
+
+// DefaultPartitionRegion returns the region queried by default in an AWS partition, such as
+// the region of resources whose ARN carries none
+func DefaultPartitionRegion(partition string) string {
+	switch partition {
+	case constants.PartitionAWSUSGov:
+		return "us-gov-west-1"
+	case constants.PartitionAWSCN:
+		return "cn-north-1"
+	default:
+		return constants.DefaultAWSRegion
+	}
+}
+
+// PartitionRegions returns the valid regions of an AWS partition, none for an unknown one
+func PartitionRegions(partition string) []string {
+	switch partition {
+	case constants.PartitionAWS:
+		return ValidAWSRegions()
+	case constants.PartitionAWSUSGov:
+		return []string{"us-gov-east-1", "us-gov-west-1"}
+	case constants.PartitionAWSCN:
+		return []string{"cn-north-1", "cn-northwest-1"}
+	default:
+		return nil
+	}
+}
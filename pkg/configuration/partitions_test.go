@@ -0,0 +1,40 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionOfRegion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		region        string
+		partition     string
+		defaultRegion string
+	}{
+		{region: "eu-west-1", partition: constants.PartitionAWS, defaultRegion: "us-east-1"},
+		{region: "us-east-1", partition: constants.PartitionAWS, defaultRegion: "us-east-1"},
+		{region: "us-gov-west-1", partition: constants.PartitionAWSUSGov, defaultRegion: "us-gov-west-1"},
+		{region: "us-gov-east-1", partition: constants.PartitionAWSUSGov, defaultRegion: "us-gov-west-1"},
+		{region: "cn-northwest-1", partition: constants.PartitionAWSCN, defaultRegion: "cn-north-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			t.Parallel()
+
+			partition := PartitionOfRegion(tt.region)
+			assert.Equal(t, tt.partition, partition)
+			assert.Contains(t, PartitionRegions(partition), tt.region)
+			assert.Equal(t, tt.defaultRegion, DefaultPartitionRegion(partition))
+			assert.True(t, IsValidRegion(tt.region))
+		})
+	}
+
+	assert.Empty(t, PartitionRegions("aws-iso"))
+	assert.NotContains(t, ValidAWSRegions(), "us-gov-west-1", "GovCloud regions must be listed explicitly")
+	assert.NotContains(t, ValidAWSRegions(), "cn-north-1", "China regions must be listed explicitly")
+}
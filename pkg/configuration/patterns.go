@@ -0,0 +1,116 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// CompiledTagPatterns holds the regular expressions of a TagValidation compiled once. It is
+// never modified once built, so concurrent validations can share it without locking.
+type CompiledTagPatterns struct {
+	patternRules      map[string]*regexp.Regexp
+	keyFormatRules    []*regexp.Regexp
+	caseRules         map[string]*regexp.Regexp
+	allowedCharacters *regexp.Regexp
+}
+
+// CompileTagPatterns compiles the pattern rules, key format rules, case rule patterns and
+// allowed value characters of tv. Patterns failing to compile are left out of the returned
+// set and reported together in the error.
+func CompileTagPatterns(tv TagValidation) (*CompiledTagPatterns, error) {
+	patterns := &CompiledTagPatterns{
+		patternRules:   make(map[string]*regexp.Regexp, len(tv.PatternRules)),
+		keyFormatRules: make([]*regexp.Regexp, len(tv.KeyFormatRules)),
+		caseRules:      make(map[string]*regexp.Regexp),
+	}
+	var errs []error
+
+	for tag, pattern := range tv.PatternRules {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid pattern rule for tag %s: %w", tag, err))
+			continue
+		}
+		patterns.patternRules[tag] = compiled
+	}
+
+	for i, rule := range tv.KeyFormatRules {
+		compiled, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid key format rule %d: %w", i, err))
+			continue
+		}
+		patterns.keyFormatRules[i] = compiled
+	}
+
+	for tag, rule := range tv.CaseRules {
+		if rule.Pattern == "" {
+			continue
+		}
+		compiled, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid case rule pattern for tag %s: %w", tag, err))
+			continue
+		}
+		patterns.caseRules[tag] = compiled
+	}
+
+	if tv.ValueValidation.AllowedCharacters != "" {
+		compiled, err := regexp.Compile(fmt.Sprintf("^[%s]+$", tv.ValueValidation.AllowedCharacters))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid allowed characters pattern: %w", err))
+		} else {
+			patterns.allowedCharacters = compiled
+		}
+	}
+
+	return patterns, errors.Join(errs...)
+}
+
+// PatternRule returns the compiled pattern rule of a tag
+func (p *CompiledTagPatterns) PatternRule(tag string) (*regexp.Regexp, bool) {
+	compiled, exists := p.patternRules[tag]
+	return compiled, exists
+}
+
+// KeyFormatRule returns the compiled pattern of the i-th key format rule, nil when it is out
+// of range or failed to compile
+func (p *CompiledTagPatterns) KeyFormatRule(i int) *regexp.Regexp {
+	if i < 0 || i >= len(p.keyFormatRules) {
+		return nil
+	}
+	return p.keyFormatRules[i]
+}
+
+// CaseRule returns the compiled pattern of the case rule of a tag
+func (p *CompiledTagPatterns) CaseRule(tag string) (*regexp.Regexp, bool) {
+	compiled, exists := p.caseRules[tag]
+	return compiled, exists
+}
+
+// AllowedCharacters returns the pattern a whole tag value must match to contain only allowed
+// characters, nil when no allowed characters are configured
+func (p *CompiledTagPatterns) AllowedCharacters() *regexp.Regexp {
+	return p.allowedCharacters
+}
+
+// CompilePatterns compiles the patterns of tv and keeps them for Patterns to return. It must
+// be called again after the rules change.
+func (tv *TagValidation) CompilePatterns() error {
+	patterns, err := CompileTagPatterns(*tv)
+	if err != nil {
+		return err
+	}
+	tv.compiled = patterns
+	return nil
+}
+
+// Patterns returns the patterns compiled by CompilePatterns. Without them, as for
+// configurations built in code, the patterns are compiled on each call.
+func (tv *TagValidation) Patterns() (*CompiledTagPatterns, error) {
+	if tv.compiled != nil {
+		return tv.compiled, nil
+	}
+	return CompileTagPatterns(*tv)
+}
@@ -0,0 +1,76 @@
+package configuration
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileTagPatterns(t *testing.T) {
+	t.Parallel()
+
+	validation := TagValidation{
+		PatternRules: map[string]string{"CostCenter": `^CC-\d{4}$`},
+		KeyFormatRules: []KeyFormatRule{
+			{Pattern: `^[A-Z]`},
+			{Pattern: `[`},
+		},
+		CaseRules: map[string]CaseRule{
+			"Project": {Case: CaseMixed, Pattern: `^[A-Z][a-z]+$`},
+			"Team":    {Case: CaseLowercase},
+			"Broken":  {Case: CaseMixed, Pattern: `(`},
+		},
+		ValueValidation: ValueValidation{AllowedCharacters: `a-z0-9\-`},
+	}
+
+	patterns, err := CompileTagPatterns(validation)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid key format rule 1")
+	assert.Contains(t, err.Error(), "invalid case rule pattern for tag Broken")
+
+	costCenter, exists := patterns.PatternRule("CostCenter")
+	require.True(t, exists)
+	assert.True(t, costCenter.MatchString("CC-1234"))
+
+	require.NotNil(t, patterns.KeyFormatRule(0))
+	assert.Nil(t, patterns.KeyFormatRule(1), "invalid patterns are left out")
+	assert.Nil(t, patterns.KeyFormatRule(2))
+
+	project, exists := patterns.CaseRule("Project")
+	require.True(t, exists)
+	assert.True(t, project.MatchString("Taggy"))
+	_, exists = patterns.CaseRule("Team")
+	assert.False(t, exists, "case rules without a pattern have nothing to compile")
+
+	assert.True(t, patterns.AllowedCharacters().MatchString("web-01"))
+	assert.False(t, patterns.AllowedCharacters().MatchString("Web 01"))
+
+	assert.Error(t, validation.CompilePatterns())
+	assert.Nil(t, validation.compiled, "failed compilations are not kept")
+}
+
+func TestValidateTagCaseConcurrent(t *testing.T) {
+	t.Parallel()
+
+	validation := &TagValidation{
+		CaseRules: map[string]CaseRule{
+			"Project": {Case: CaseMixed, Pattern: `^[A-Z][a-z]+$`},
+		},
+	}
+	require.NoError(t, validation.CompilePatterns())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				assert.NoError(t, validation.ValidateTagCase("Project", "Taggy"))
+				assert.Error(t, validation.ValidateTagCase("Project", "taggy"))
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -101,24 +101,29 @@ func (q *ConfigQuerier) GetResourceByType(resourceType string) (*ResourceConfig,
 	return &resourceConfig, nil
 }
 
-// GetComplianceLevelByName retrieves a specific compliance level configuration
+// GetComplianceLevelByName retrieves a specific compliance level configuration, including
+// the tags it inherits from the levels it extends
 //
 // Parameters:
 //   - levelName: The name of the compliance level to retrieve
 //
 // Returns:
-//   - The compliance level configuration
-//   - An error if the compliance level is not found
+//   - The resolved compliance level configuration
+//   - An error if the compliance level is not found or its parents cannot be resolved
 func (q *ConfigQuerier) GetComplianceLevelByName(levelName string) (*ComplianceLevel, error) {
 	if levelName == "" {
 		return nil, fmt.Errorf("compliance level name cannot be empty")
 	}
 
-	complianceLevel, exists := q.config.ComplianceLevels[levelName]
-	if !exists {
+	if _, exists := q.config.ComplianceLevels[levelName]; !exists {
 		return nil, fmt.Errorf("compliance level %s not found in configuration", levelName)
 	}
 
+	complianceLevel, err := q.config.ResolveComplianceLevel(levelName)
+	if err != nil {
+		return nil, err
+	}
+
 	return &complianceLevel, nil
 }
 
@@ -99,6 +99,20 @@ func TestConfigQuerier(t *testing.T) {
 		assert.Contains(t, highLevel.RequiredTags, "DataClassification")
 	})
 
+	t.Run("GetComplianceLevelByName Resolves Parents", func(t *testing.T) {
+		extending, err := NewConfigQuerier(&TaggyScanConfig{
+			ComplianceLevels: map[string]ComplianceLevel{
+				"standard": {RequiredTags: []string{"Owner"}},
+				"high":     {RequiredTags: []string{"SecurityLevel"}, Extends: "standard"},
+			},
+		})
+		require.NoError(t, err)
+
+		highLevel, err := extending.GetComplianceLevelByName("high")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Owner", "SecurityLevel"}, highLevel.RequiredTags)
+	})
+
 	t.Run("Error Scenarios", func(t *testing.T) {
 		t.Run("Empty Configuration", func(t *testing.T) {
 			emptyQuerier, err := NewConfigQuerier(&TaggyScanConfig{})
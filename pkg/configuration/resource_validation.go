@@ -0,0 +1,244 @@
+package configuration
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TagValidationOverrideMode tells how the allowed values of a tag_validation override of a
+// resource type combine with the global ones
+type TagValidationOverrideMode string
+
+const (
+	// TagValidationOverrideAdd adds the allowed values of the override to the global allowed
+	// values of the tag. It is the default mode.
+	TagValidationOverrideAdd TagValidationOverrideMode = "add"
+
+	// TagValidationOverrideReplace replaces the global allowed values of the tag, and their
+	// deprecated values, by those of the override
+	TagValidationOverrideReplace TagValidationOverrideMode = "replace"
+)
+
+// TagValidationOverride changes the allowed values, pattern rules and case rules of the
+// global tag validation for the resources of a type. Rules are overridden tag by tag, the
+// tags the override leaves out keep their global rules: a tag has a single pattern rule and
+// case rule, so those of the override replace the global ones whatever the mode, while its
+// allowed values are added to or replace the global ones depending on Mode.
+type TagValidationOverride struct {
+	// Mode is add or replace, add when empty
+	Mode TagValidationOverrideMode `yaml:"mode,omitempty" json:"mode,omitempty"`
+
+	// AllowedValues, PatternRules and CaseRules are written like those of the global tag
+	// validation, structured entries included, and their metadata recorded alike
+	AllowedValues         map[string][]string          `yaml:"allowed_values,omitempty" json:"allowed_values,omitempty"`
+	DeprecatedValues      map[string]map[string]string `yaml:"deprecated_values,omitempty" json:"deprecated_values,omitempty"`
+	PatternRules          map[string]string            `yaml:"pattern_rules,omitempty" json:"pattern_rules,omitempty"`
+	PatternRuleSeverities map[string]Severity          `yaml:"pattern_rule_severities,omitempty" json:"pattern_rule_severities,omitempty"`
+	PatternRuleExamples   map[string]string            `yaml:"pattern_rule_examples,omitempty" json:"pattern_rule_examples,omitempty"`
+	CaseRules             map[string]CaseRule          `yaml:"case_rules,omitempty" json:"case_rules,omitempty"`
+}
+
+// Replaces reports whether the allowed values of the override replace the global ones
+func (o TagValidationOverride) Replaces() bool {
+	return o.Mode == TagValidationOverrideReplace
+}
+
+// rules returns the rules of the override as a tag validation
+func (o TagValidationOverride) rules() TagValidation {
+	return TagValidation{
+		AllowedValues:         o.AllowedValues,
+		DeprecatedValues:      o.DeprecatedValues,
+		PatternRules:          o.PatternRules,
+		PatternRuleSeverities: o.PatternRuleSeverities,
+		PatternRuleExamples:   o.PatternRuleExamples,
+		CaseRules:             o.CaseRules,
+	}
+}
+
+// setRules sets the rules of the override from those of a tag validation
+func (o *TagValidationOverride) setRules(rules TagValidation) {
+	o.AllowedValues = rules.AllowedValues
+	o.DeprecatedValues = rules.DeprecatedValues
+	o.PatternRules = rules.PatternRules
+	o.PatternRuleSeverities = rules.PatternRuleSeverities
+	o.PatternRuleExamples = rules.PatternRuleExamples
+	o.CaseRules = rules.CaseRules
+}
+
+// UnmarshalYAML accepts the structured entries of pattern rules and allowed values, like
+// TagValidation.UnmarshalYAML does
+func (o *TagValidationOverride) UnmarshalYAML(node *yaml.Node) error {
+	var mode struct {
+		Mode TagValidationOverrideMode `yaml:"mode"`
+	}
+	if err := node.Decode(&mode); err != nil {
+		return err
+	}
+
+	var rules TagValidation
+	if err := node.Decode(&rules); err != nil {
+		return err
+	}
+
+	o.Mode = mode.Mode
+	o.setRules(rules)
+	return nil
+}
+
+// UnmarshalJSON accepts the structured entries of pattern rules and allowed values, like
+// TagValidation.UnmarshalJSON does
+func (o *TagValidationOverride) UnmarshalJSON(data []byte) error {
+	var mode struct {
+		Mode TagValidationOverrideMode `json:"mode"`
+	}
+	if err := json.Unmarshal(data, &mode); err != nil {
+		return err
+	}
+
+	var rules TagValidation
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return err
+	}
+
+	o.Mode = mode.Mode
+	o.setRules(rules)
+	return nil
+}
+
+// ResourceTagValidation returns the tag validation rules the resources of a type are checked
+// against: the global ones, with the tag_validation override of the resource type applied.
+// The returned rules share nothing the override changes with the global ones, and their
+// patterns are compiled on demand, see TagValidation.Patterns.
+func (c *TaggyScanConfig) ResourceTagValidation(resourceType string) TagValidation {
+	resourceConfig, exists := c.Resources[resourceType]
+	if !exists || resourceConfig.TagValidation == nil {
+		return c.TagValidation
+	}
+	override := resourceConfig.TagValidation.matchGlobalTags(c.TagValidation)
+
+	effective := c.TagValidation
+	effective.compiled = nil
+
+	effective.PatternRules = mergeTagMaps(c.TagValidation.PatternRules, override.PatternRules)
+	effective.PatternRuleSeverities = overrideTagMetadata(c.TagValidation.PatternRuleSeverities, override.PatternRuleSeverities, override.PatternRules)
+	effective.PatternRuleExamples = overrideTagMetadata(c.TagValidation.PatternRuleExamples, override.PatternRuleExamples, override.PatternRules)
+	effective.CaseRules = mergeTagMaps(c.TagValidation.CaseRules, override.CaseRules)
+
+	effective.AllowedValues = maps.Clone(c.TagValidation.AllowedValues)
+	effective.DeprecatedValues = maps.Clone(c.TagValidation.DeprecatedValues)
+	for _, tag := range slices.Sorted(maps.Keys(override.AllowedValues)) {
+		if effective.AllowedValues == nil {
+			effective.AllowedValues = make(map[string][]string)
+		}
+		values := slices.Clone(override.AllowedValues[tag])
+		if override.Replaces() {
+			delete(effective.DeprecatedValues, tag)
+		} else {
+			values = addAllowedValues(effective.AllowedValues[tag], values)
+		}
+		effective.AllowedValues[tag] = values
+	}
+	for tag, deprecated := range override.DeprecatedValues {
+		if effective.DeprecatedValues == nil {
+			effective.DeprecatedValues = make(map[string]map[string]string)
+		}
+		merged := maps.Clone(effective.DeprecatedValues[tag])
+		if merged == nil {
+			merged = make(map[string]string, len(deprecated))
+		}
+		maps.Copy(merged, deprecated)
+		effective.DeprecatedValues[tag] = merged
+	}
+
+	return effective
+}
+
+// matchGlobalTags returns the override with its tags written as in the global rules. Tag
+// keys are checked regardless of case, so the rules of an override of environment replace or
+// extend those of a global Environment instead of being kept next to them.
+func (o TagValidationOverride) matchGlobalTags(global TagValidation) TagValidationOverride {
+	globalTags := slices.Concat(
+		slices.Collect(maps.Keys(global.AllowedValues)),
+		slices.Collect(maps.Keys(global.DeprecatedValues)),
+		slices.Collect(maps.Keys(global.PatternRules)),
+		slices.Collect(maps.Keys(global.PatternRuleSeverities)),
+		slices.Collect(maps.Keys(global.PatternRuleExamples)),
+		slices.Collect(maps.Keys(global.CaseRules)),
+	)
+	slices.Sort(globalTags)
+	globalTag := func(tag string) string {
+		if i := slices.IndexFunc(globalTags, func(globalTag string) bool { return strings.EqualFold(globalTag, tag) }); i >= 0 {
+			return globalTags[i]
+		}
+		return tag
+	}
+
+	matched := o
+	matched.AllowedValues = renameTags(o.AllowedValues, globalTag)
+	matched.DeprecatedValues = renameTags(o.DeprecatedValues, globalTag)
+	matched.PatternRules = renameTags(o.PatternRules, globalTag)
+	matched.PatternRuleSeverities = renameTags(o.PatternRuleSeverities, globalTag)
+	matched.PatternRuleExamples = renameTags(o.PatternRuleExamples, globalTag)
+	matched.CaseRules = renameTags(o.CaseRules, globalTag)
+	return matched
+}
+
+// renameTags returns the entries of a map of tags under the keys rename returns
+func renameTags[V any](tags map[string]V, rename func(string) string) map[string]V {
+	if tags == nil {
+		return nil
+	}
+	renamed := make(map[string]V, len(tags))
+	for tag, value := range tags {
+		renamed[rename(tag)] = value
+	}
+	return renamed
+}
+
+// ResolveTagValidationOverrides replaces the tag_validation override of every resource type
+// having one by the whole rule set its resources are checked against, see
+// ResourceTagValidation, written as an override replacing the global rules
+func (c *TaggyScanConfig) ResolveTagValidationOverrides() {
+	for _, resourceType := range slices.Sorted(maps.Keys(c.Resources)) {
+		resourceConfig := c.Resources[resourceType]
+		if resourceConfig.TagValidation == nil {
+			continue
+		}
+
+		resolved := &TagValidationOverride{Mode: TagValidationOverrideReplace}
+		resolved.setRules(c.ResourceTagValidation(resourceType))
+		resourceConfig.TagValidation = resolved
+		c.Resources[resourceType] = resourceConfig
+	}
+}
+
+// addAllowedValues returns the global allowed values of a tag followed by the values of the
+// override they lack, compared regardless of case like values are checked
+func addAllowedValues(global, added []string) []string {
+	merged := slices.Clone(global)
+	for _, value := range added {
+		if !slices.ContainsFunc(merged, func(allowed string) bool { return strings.EqualFold(allowed, value) }) {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
+
+// overrideTagMetadata returns the global metadata of pattern rules, such as their
+// severities, without the entries of the tags whose rule the override replaces, with the
+// metadata of the override added
+func overrideTagMetadata[V any](global, override map[string]V, overriddenRules map[string]string) map[string]V {
+	merged := maps.Clone(global)
+	for tag := range overriddenRules {
+		delete(merged, tag)
+	}
+	if len(override) > 0 && merged == nil {
+		merged = make(map[string]V, len(override))
+	}
+	maps.Copy(merged, override)
+	return merged
+}
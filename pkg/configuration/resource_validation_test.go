@@ -0,0 +1,260 @@
+package configuration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// overrideTestConfig returns a configuration whose s3 resources override the global rules
+func overrideTestConfig(override *TagValidationOverride) *TaggyScanConfig {
+	cfg := createTestConfig()
+	cfg.TagValidation.AllowedValues = map[string][]string{
+		"Environment": {"production", "staging", "prod"},
+		"Team":        {"platform"},
+	}
+	cfg.TagValidation.DeprecatedValues = map[string]map[string]string{"Environment": {"prod": "production"}}
+	cfg.TagValidation.PatternRules = map[string]string{"CostCenter": `^[A-Z]{2}-[0-9]{4}$`, "Owner": `^[a-z]+$`}
+	cfg.TagValidation.PatternRuleSeverities = map[string]Severity{"CostCenter": SeverityHigh}
+	cfg.TagValidation.PatternRuleExamples = map[string]string{"CostCenter": "CC-1234"}
+
+	resourceConfig := cfg.Resources["s3"]
+	resourceConfig.TagValidation = override
+	cfg.Resources["s3"] = resourceConfig
+	return cfg
+}
+
+func TestResourceTagValidation(t *testing.T) {
+	t.Run("Add", func(t *testing.T) {
+		cfg := overrideTestConfig(&TagValidationOverride{
+			AllowedValues: map[string][]string{"Environment": {"scratch", "Staging"}, "Sandbox": {"yes"}},
+		})
+
+		effective := cfg.ResourceTagValidation("s3")
+		assert.Equal(t, []string{"production", "staging", "prod", "scratch"}, effective.AllowedValues["Environment"])
+		assert.Equal(t, []string{"yes"}, effective.AllowedValues["Sandbox"])
+		assert.Equal(t, []string{"platform"}, effective.AllowedValues["Team"])
+
+		// Deprecated values of the global allowed values are kept
+		replacement, deprecated := effective.DeprecatedValue("Environment", "prod")
+		assert.True(t, deprecated)
+		assert.Equal(t, "production", replacement)
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		cfg := overrideTestConfig(&TagValidationOverride{
+			Mode:          TagValidationOverrideReplace,
+			AllowedValues: map[string][]string{"Environment": {"scratch", "sandbox"}},
+		})
+
+		effective := cfg.ResourceTagValidation("s3")
+		assert.Equal(t, []string{"scratch", "sandbox"}, effective.AllowedValues["Environment"])
+		assert.Equal(t, []string{"platform"}, effective.AllowedValues["Team"])
+
+		// The deprecated values of the replaced allowed values are dropped along with them
+		_, deprecated := effective.DeprecatedValue("Environment", "prod")
+		assert.False(t, deprecated)
+	})
+
+	t.Run("Pattern And Case Rules", func(t *testing.T) {
+		for _, mode := range []TagValidationOverrideMode{TagValidationOverrideAdd, TagValidationOverrideReplace} {
+			cfg := overrideTestConfig(&TagValidationOverride{
+				Mode:                  mode,
+				PatternRules:          map[string]string{"CostCenter": `^SBX-[0-9]+$`, "Project": `^[a-z-]+$`},
+				PatternRuleSeverities: map[string]Severity{"Project": SeverityLow},
+				CaseRules:             map[string]CaseRule{"Environment": {Case: CaseUppercase}},
+			})
+
+			effective := cfg.ResourceTagValidation("s3")
+			assert.Equal(t, map[string]string{
+				"CostCenter": `^SBX-[0-9]+$`,
+				"Owner":      `^[a-z]+$`,
+				"Project":    `^[a-z-]+$`,
+			}, effective.PatternRules, mode)
+
+			// The severity and example of a replaced pattern rule go with it
+			assert.Equal(t, map[string]Severity{"Project": SeverityLow}, effective.PatternRuleSeverities, mode)
+			assert.Empty(t, effective.PatternRuleExamples, mode)
+			assert.Equal(t, CaseUppercase, effective.CaseRules["Environment"].Case, mode)
+		}
+	})
+
+	t.Run("Tags Matched Regardless Of Case", func(t *testing.T) {
+		cfg := overrideTestConfig(&TagValidationOverride{
+			Mode:                  TagValidationOverrideReplace,
+			AllowedValues:         map[string][]string{"environment": {"scratch"}},
+			PatternRules:          map[string]string{"costcenter": `^SBX-[0-9]+$`},
+			PatternRuleSeverities: map[string]Severity{"COSTCENTER": SeverityLow},
+		})
+		cfg.TagValidation.CaseRules = map[string]CaseRule{"Environment": {Case: CaseLowercase}}
+		cfg.Resources["s3"].TagValidation.CaseRules = map[string]CaseRule{"ENVIRONMENT": {Case: CaseUppercase}}
+
+		// The rules of the override replace the global rules of the tag, under its global key
+		effective := cfg.ResourceTagValidation("s3")
+		assert.Equal(t, map[string][]string{"Environment": {"scratch"}, "Team": {"platform"}}, effective.AllowedValues)
+		assert.Empty(t, effective.DeprecatedValues)
+		assert.Equal(t, map[string]string{"CostCenter": `^SBX-[0-9]+$`, "Owner": `^[a-z]+$`}, effective.PatternRules)
+		assert.Equal(t, map[string]Severity{"CostCenter": SeverityLow}, effective.PatternRuleSeverities)
+		assert.Empty(t, effective.PatternRuleExamples)
+		assert.Equal(t, map[string]CaseRule{"Environment": {Case: CaseUppercase}}, effective.CaseRules)
+
+		// Tags without a global rule keep the key of the override
+		cfg.Resources["s3"].TagValidation.AllowedValues = map[string][]string{"sandbox": {"yes"}}
+		assert.Equal(t, []string{"yes"}, cfg.ResourceTagValidation("s3").AllowedValues["sandbox"])
+	})
+
+	t.Run("Global Rules Unchanged", func(t *testing.T) {
+		cfg := overrideTestConfig(&TagValidationOverride{
+			AllowedValues:    map[string][]string{"Environment": {"scratch"}},
+			DeprecatedValues: map[string]map[string]string{"Environment": {"staging": "production"}},
+			PatternRules:     map[string]string{"CostCenter": `^SBX-[0-9]+$`},
+		})
+
+		effective := cfg.ResourceTagValidation("s3")
+		_, deprecated := effective.DeprecatedValue("Environment", "staging")
+		assert.True(t, deprecated)
+
+		assert.Equal(t, []string{"production", "staging", "prod"}, cfg.TagValidation.AllowedValues["Environment"])
+		assert.Equal(t, map[string]string{"prod": "production"}, cfg.TagValidation.DeprecatedValues["Environment"])
+		assert.Equal(t, `^[A-Z]{2}-[0-9]{4}$`, cfg.TagValidation.PatternRules["CostCenter"])
+		assert.Equal(t, cfg.TagValidation.AllowedValues, cfg.ResourceTagValidation("ec2").AllowedValues)
+	})
+}
+
+func TestTagValidationOverride_Unmarshal(t *testing.T) {
+	want := TagValidationOverride{
+		Mode:                  TagValidationOverrideReplace,
+		AllowedValues:         map[string][]string{"Environment": {"scratch", "sandbox"}},
+		DeprecatedValues:      map[string]map[string]string{"Environment": {"sandbox": "scratch"}},
+		PatternRules:          map[string]string{"Owner": `^[a-z]+$`},
+		PatternRuleSeverities: map[string]Severity{"Owner": SeverityHigh},
+		PatternRuleExamples:   map[string]string{"Owner": "platform"},
+		CaseRules:             map[string]CaseRule{"Environment": {Case: CaseLowercase}},
+	}
+
+	var fromYAML TagValidationOverride
+	require.NoError(t, yaml.Unmarshal([]byte(`
+mode: replace
+allowed_values:
+  Environment:
+    - scratch
+    - value: sandbox
+      deprecated: true
+      replacement: scratch
+pattern_rules:
+  Owner:
+    pattern: "^[a-z]+$"
+    severity: high
+    example: platform
+case_rules:
+  Environment:
+    case: lowercase
+`), &fromYAML))
+	assert.Equal(t, want, fromYAML)
+
+	var fromJSON TagValidationOverride
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "mode": "replace",
+  "allowed_values": {"Environment": ["scratch", {"value": "sandbox", "deprecated": true, "replacement": "scratch"}]},
+  "pattern_rules": {"Owner": {"pattern": "^[a-z]+$", "severity": "high", "example": "platform"}},
+  "case_rules": {"Environment": {"case": "lowercase"}}
+}`), &fromJSON))
+	assert.Equal(t, want, fromJSON)
+}
+
+func TestResolveTagValidationOverrides(t *testing.T) {
+	cfg := overrideTestConfig(&TagValidationOverride{
+		AllowedValues: map[string][]string{"Environment": {"scratch"}},
+	})
+	effective := cfg.ResourceTagValidation("s3")
+
+	cfg.ResolveTagValidationOverrides()
+
+	resolved := cfg.Resources["s3"].TagValidation
+	require.NotNil(t, resolved)
+	assert.Equal(t, TagValidationOverrideReplace, resolved.Mode)
+	assert.Equal(t, effective.AllowedValues, resolved.AllowedValues)
+	assert.Equal(t, effective.PatternRules, resolved.PatternRules)
+	assert.Equal(t, effective.CaseRules, resolved.CaseRules)
+
+	// The resolved override yields the same rules
+	assert.Equal(t, effective.AllowedValues, cfg.ResourceTagValidation("s3").AllowedValues)
+	assert.Equal(t, effective.DeprecatedValues, cfg.ResourceTagValidation("s3").DeprecatedValues)
+}
+
+func TestContentValidator_ValidateTagValidationOverride(t *testing.T) {
+	testCases := []struct {
+		name     string
+		override *TagValidationOverride
+		wantErr  []string
+	}{
+		{
+			name: "Valid Override",
+			override: &TagValidationOverride{
+				AllowedValues: map[string][]string{"Environment": {"scratch"}},
+				PatternRules:  map[string]string{"CostCenter": `^SBX-[0-9]+$`},
+				CaseRules:     map[string]CaseRule{"Environment": {Case: CaseLowercase}},
+			},
+		},
+		{
+			name: "Deprecates Added Global Value",
+			override: &TagValidationOverride{
+				DeprecatedValues: map[string]map[string]string{"Environment": {"staging": "production"}},
+			},
+		},
+		{
+			name: "Deprecates Replaced Global Value",
+			override: &TagValidationOverride{
+				Mode:             TagValidationOverrideReplace,
+				AllowedValues:    map[string][]string{"Environment": {"scratch"}},
+				DeprecatedValues: map[string]map[string]string{"Environment": {"staging": ""}},
+			},
+			wantErr: []string{"resources.s3.tag_validation.deprecated_values.Environment.staging: deprecated value staging is not an allowed value of tag Environment"},
+		},
+		{
+			name:     "Invalid Mode",
+			override: &TagValidationOverride{Mode: "merge"},
+			wantErr:  []string{"resources.s3.tag_validation.mode: resource s3 has invalid tag validation mode merge, expected add or replace"},
+		},
+		{
+			name: "Invalid Rules",
+			override: &TagValidationOverride{
+				AllowedValues:         map[string][]string{"Team": {}},
+				PatternRules:          map[string]string{"CostCenter": `^[A-Z`},
+				PatternRuleSeverities: map[string]Severity{"Owner": SeverityHigh},
+				CaseRules:             map[string]CaseRule{"Environment": {Case: "title"}},
+			},
+			wantErr: []string{
+				"resources.s3.tag_validation.case_rules.Environment.case: invalid case type for tag Environment: title",
+				"resources.s3.tag_validation.pattern_rules.CostCenter: invalid pattern rule for tag CostCenter",
+				"resources.s3.tag_validation.pattern_rule_severities.Owner: severity set for tag Owner, which has no pattern rule",
+				"resources.s3.tag_validation.allowed_values.Team: no allowed values specified for tag Team",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createTestConfig()
+			resourceConfig := cfg.Resources["s3"]
+			resourceConfig.TagValidation = tc.override
+			cfg.Resources["s3"] = resourceConfig
+
+			validator, err := NewContentValidator(cfg)
+			require.NoError(t, err)
+
+			err = validator.validateResourceConfigs()
+			if len(tc.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tc.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
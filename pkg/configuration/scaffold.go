@@ -0,0 +1,225 @@
+package configuration
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+)
+
+// ScaffoldOptions controls the starter configuration produced by Scaffold
+type ScaffoldOptions struct {
+	// Minimal produces a bare-bones configuration instead of the fully commented one
+	Minimal bool
+
+	// Resources lists the resource types pre-populated in the resources map, s3 when empty
+	Resources []string
+}
+
+// Scaffold renders a starter configuration file that passes the content validation of this
+// package. The full variant documents every section with comments and examples, the minimal
+// one only holds the settings a scan cannot run without.
+func Scaffold(opts ScaffoldOptions) ([]byte, error) {
+	resources, err := scaffoldResources(opts.Resources)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := fullScaffoldTemplate
+	if opts.Minimal {
+		tmpl = minimalScaffoldTemplate
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, struct{ Resources []string }{Resources: resources}); err != nil {
+		return nil, fmt.Errorf("failed to render starter configuration: %w", err)
+	}
+
+	return rendered.Bytes(), nil
+}
+
+// scaffoldResources normalizes and deduplicates the requested resource types, rejecting the
+// ones a scan cannot inspect
+func scaffoldResources(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return []string{constants.ResourceTypeS3}, nil
+	}
+
+	validator := &ContentValidator{}
+	seen := make(map[string]bool, len(requested))
+	resources := make([]string, 0, len(requested))
+
+	for _, resource := range requested {
+		normalized := NormalizeResourceType(resource)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+
+		if err := IsSupportedAWSResource(normalized); err != nil {
+			return nil, err
+		}
+		if err := validator.validateResourceType(normalized); err != nil {
+			return nil, err
+		}
+
+		seen[normalized] = true
+		resources = append(resources, normalized)
+	}
+
+	if len(resources) == 0 {
+		return []string{constants.ResourceTypeS3}, nil
+	}
+
+	return resources, nil
+}
+
+var minimalScaffoldTemplate = template.Must(template.New("minimal").Parse(`# AWS Taggy tag compliance configuration
+# Validate it with: aws-taggy config validate --config <file>
+version: "1.1"
+
+aws:
+  regions:
+    mode: all
+
+global:
+  enabled: true
+  tag_criteria:
+    minimum_required_tags: 1
+    required_tags:
+      - Owner
+
+resources:
+{{- range .Resources }}
+  {{ . }}:
+    enabled: true
+{{- end }}
+
+tag_validation:
+  key_validation:
+    max_length: 128
+`))
+
+var fullScaffoldTemplate = template.Must(template.New("full").Parse(`# =====================================================
+# AWS Taggy - Tag Compliance Configuration
+# =====================================================
+#
+# Starter configuration generated by 'aws-taggy config init'.
+# Adjust it to your tagging policy, then check it with:
+#
+#   aws-taggy config validate --config <file>
+
+# Configuration format version (X.Y)
+version: "1.1"
+
+# AWS connection settings
+aws:
+  regions:
+    # 'all' scans every enabled region, 'specific' only the regions in 'list'
+    mode: all
+    # list:
+    #   - us-east-1
+    #   - eu-west-1
+
+  # Number of resources processed per batch
+  batch_size: 20
+
+# Rules applied to every resource unless a resource overrides them
+global:
+  enabled: true
+  tag_criteria:
+    # How many of the required tags a resource must carry at least
+    minimum_required_tags: 2
+    max_tags: 50
+
+    # Tags every resource must carry
+    required_tags:
+      - Environment
+      - Owner
+
+    # Tags that must not be used
+    forbidden_tags:
+      - Temporary
+
+    # Compliance level from 'compliance_levels' applied by default
+    compliance_level: standard
+
+# Resource types to scan
+resources:
+{{- range .Resources }}
+  {{ . }}:
+    enabled: true
+    tag_criteria:
+      minimum_required_tags: 2
+      required_tags:
+        - Environment
+        - Owner
+      compliance_level: standard
+
+    # Resources skipped by the compliance check, matched by name or ID
+    excluded_resources: []
+    #   - pattern: "terraform-state-*"
+    #     reason: "Terraform state is managed separately"
+{{- end }}
+
+# Named sets of tag requirements, referenced by 'compliance_level'
+compliance_levels:
+  standard:
+    required_tags:
+      - Environment
+      - Owner
+      - Project
+    specific_tags:
+      ManagedBy: terraform
+
+# Rules tag keys and values are checked against
+tag_validation:
+  # Reject what AWS would: keys over 128 characters, values over 256, the aws: prefix and
+  # characters outside the tag character set
+  enforce_aws_limits: true
+
+  # Values a tag may take, compared case-insensitively
+  allowed_values:
+    Environment:
+      - production
+      - staging
+      - development
+
+  # Regular expressions tag values must match
+  pattern_rules:
+    Owner: "^[a-z0-9._-]+@company\\.com$"
+
+  # Case tag values must follow (lowercase, uppercase or mixed)
+  case_rules:
+    Environment:
+      case: lowercase
+      message: "Environment tag must be lowercase"
+
+  # Tag keys containing any of these are rejected
+  prohibited_tags:
+    - "aws:"
+
+  key_validation:
+    max_length: 128
+    # Flag tag keys that only differ in case, e.g. Environment and environment
+    deny_case_duplicates: true
+
+  value_validation:
+    # Characters allowed in tag values, as a regular expression character class
+    allowed_characters: "a-zA-Z0-9._:/@ -"
+    disallowed_values:
+      - "undefined"
+      - "null"
+
+# Where compliance reports are sent, disabled until configured
+notifications:
+  slack:
+    enabled: false
+    # channels:
+    #   high_priority: compliance-alerts
+  email:
+    enabled: false
+    # recipients:
+    #   - compliance-team@company.com
+    # frequency: daily
+`))
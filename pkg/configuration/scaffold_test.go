@@ -0,0 +1,67 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaffold(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          ScaffoldOptions
+		wantResources []string
+	}{
+		{
+			name:          "Full Configuration",
+			opts:          ScaffoldOptions{},
+			wantResources: []string{"s3"},
+		},
+		{
+			name:          "Minimal Configuration",
+			opts:          ScaffoldOptions{Minimal: true},
+			wantResources: []string{"s3"},
+		},
+		{
+			name:          "Selected Resources",
+			opts:          ScaffoldOptions{Resources: []string{"s3", "EC2", " rds ", "s3"}},
+			wantResources: []string{"s3", "ec2", "rds"},
+		},
+		{
+			name:          "Minimal Configuration With Selected Resources",
+			opts:          ScaffoldOptions{Minimal: true, Resources: []string{"sqs", "sns"}},
+			wantResources: []string{"sqs", "sns"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := Scaffold(tt.opts)
+			require.NoError(t, err)
+
+			// The generated file must load through the same validation as a user's file
+			configPath := filepath.Join(t.TempDir(), "tag-compliance.yaml")
+			require.NoError(t, os.WriteFile(configPath, content, 0o600))
+
+			cfg, err := NewTaggyScanConfigLoader().LoadConfig(configPath)
+			require.NoError(t, err)
+
+			assert.Len(t, cfg.Resources, len(tt.wantResources))
+			for _, resource := range tt.wantResources {
+				assert.True(t, cfg.Resources[resource].Enabled, "resource %s should be enabled", resource)
+			}
+		})
+	}
+}
+
+func TestScaffold_UnsupportedResources(t *testing.T) {
+	for _, resource := range []string{"mainframe", "lambda"} {
+		t.Run(resource, func(t *testing.T) {
+			_, err := Scaffold(ScaffoldOptions{Resources: []string{resource}})
+			assert.Error(t, err)
+		})
+	}
+}
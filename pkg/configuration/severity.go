@@ -0,0 +1,462 @@
+package configuration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity ranks how serious the violation of a tag rule is
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityHigh     Severity = "high"
+	SeverityMedium   Severity = "medium"
+	SeverityLow      Severity = "low"
+
+	// DefaultSeverity applies to rules that do not declare a severity
+	DefaultSeverity = SeverityMedium
+)
+
+// IsValid reports whether the severity is a known one. An empty severity is valid and
+// stands for DefaultSeverity.
+func (s Severity) IsValid() bool {
+	switch s {
+	case "", SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrDefault returns the severity, or DefaultSeverity when it is not set
+func (s Severity) OrDefault() Severity {
+	if s == "" {
+		return DefaultSeverity
+	}
+	return s
+}
+
+// RequiredTagSeverity returns the severity of a missing required tag
+func (c TagCriteria) RequiredTagSeverity(tag string) Severity {
+	return c.RequiredTagSeverities[tag].OrDefault()
+}
+
+// PatternRuleSeverity returns the severity of a tag value not matching its pattern rule
+func (t TagValidation) PatternRuleSeverity(tag string) Severity {
+	return t.PatternRuleSeverities[tag].OrDefault()
+}
+
+// PatternRuleExample returns the example value of the pattern rule of a tag, empty when the
+// rule has none
+func (t TagValidation) PatternRuleExample(tag string) string {
+	return t.PatternRuleExamples[tag]
+}
+
+// DeprecatedValue returns the replacement of an allowed value of a tag when the value is
+// deprecated, the replacement being empty when the configuration gives none
+func (t TagValidation) DeprecatedValue(tag, value string) (string, bool) {
+	replacement, deprecated := t.DeprecatedValues[tag][value]
+	return replacement, deprecated
+}
+
+// UnmarshalYAML accepts required tags written either as a plain tag name or as a
+// {name, severity} mapping, e.g.:
+//
+//	required_tags:
+//	  - Owner
+//	  - name: DataClassification
+//	    severity: critical
+func (c *TagCriteria) UnmarshalYAML(node *yaml.Node) error {
+	severities, err := extractSeverities(node, "required_tags", func(item *yaml.Node) (string, Severity, error) {
+		var entry struct {
+			Name     string   `yaml:"name"`
+			Severity Severity `yaml:"severity"`
+		}
+		if err := item.Decode(&entry); err != nil {
+			return "", "", err
+		}
+		if entry.Name == "" {
+			return "", "", fmt.Errorf("line %d: required tag must have a name", item.Line)
+		}
+		return entry.Name, entry.Severity, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	type plain TagCriteria
+	if err := node.Decode((*plain)(c)); err != nil {
+		return err
+	}
+
+	c.RequiredTagSeverities = mergeSeverities(c.RequiredTagSeverities, severities)
+	return nil
+}
+
+// UnmarshalYAML accepts pattern rules written either as a plain pattern or as a
+// {pattern, severity, example} mapping, and allowed values written either as a plain value
+// or as a {value, deprecated, replacement} mapping, e.g.:
+//
+//	pattern_rules:
+//	  CostCenter: "^[A-Z]{2}-[0-9]{4}$"
+//	  Owner:
+//	    pattern: "^[a-z]+@company\\.com$"
+//	    severity: high
+//	    example: platform@company.com
+//	allowed_values:
+//	  Environment:
+//	    - production
+//	    - staging
+//	    - value: qa
+//	      deprecated: true
+//	      replacement: staging
+func (t *TagValidation) UnmarshalYAML(node *yaml.Node) error {
+	// Examples are read before extractSeverities rewrites the entries into plain patterns
+	examples := extractPatternExamples(node)
+
+	deprecated, err := extractDeprecatedValues(node)
+	if err != nil {
+		return err
+	}
+
+	severities, err := extractSeverities(node, "pattern_rules", func(item *yaml.Node) (string, Severity, error) {
+		var entry struct {
+			Pattern  string   `yaml:"pattern"`
+			Severity Severity `yaml:"severity"`
+		}
+		if err := item.Decode(&entry); err != nil {
+			return "", "", err
+		}
+		return entry.Pattern, entry.Severity, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	type plain TagValidation
+	if err := node.Decode((*plain)(t)); err != nil {
+		return err
+	}
+
+	t.PatternRuleSeverities = mergeSeverities(t.PatternRuleSeverities, severities)
+	t.PatternRuleExamples = mergeExamples(t.PatternRuleExamples, examples)
+	t.DeprecatedValues = mergeDeprecatedValues(t.DeprecatedValues, deprecated)
+	return nil
+}
+
+// UnmarshalJSON accepts required tags written either as a plain tag name or as a
+// {"name", "severity"} object, like UnmarshalYAML does
+func (c *TagCriteria) UnmarshalJSON(data []byte) error {
+	type plain TagCriteria
+	raw := struct {
+		*plain
+		RequiredTags []json.RawMessage `json:"required_tags,omitempty"`
+	}{plain: (*plain)(c)}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.RequiredTags == nil {
+		return nil
+	}
+
+	c.RequiredTags = make([]string, 0, len(raw.RequiredTags))
+	severities := make(map[string]Severity)
+	for _, item := range raw.RequiredTags {
+		var entry struct {
+			Name     string   `json:"name"`
+			Severity Severity `json:"severity"`
+		}
+		if err := decodeJSONEntry(item, &entry.Name, &entry); err != nil {
+			return err
+		}
+		if entry.Name == "" {
+			return fmt.Errorf("required tag must have a name")
+		}
+		c.RequiredTags = append(c.RequiredTags, entry.Name)
+		if entry.Severity != "" {
+			severities[entry.Name] = entry.Severity
+		}
+	}
+
+	c.RequiredTagSeverities = mergeSeverities(c.RequiredTagSeverities, severities)
+	return nil
+}
+
+// UnmarshalJSON accepts pattern rules written either as a plain pattern or as a
+// {"pattern", "severity", "example"} object, and allowed values written either as a plain
+// value or as a {"value", "deprecated", "replacement"} object, like UnmarshalYAML does
+func (t *TagValidation) UnmarshalJSON(data []byte) error {
+	type plain TagValidation
+	raw := struct {
+		*plain
+		PatternRules  map[string]json.RawMessage   `json:"pattern_rules,omitempty"`
+		AllowedValues map[string][]json.RawMessage `json:"allowed_values,omitempty"`
+	}{plain: (*plain)(t)}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.AllowedValues != nil {
+		if err := t.decodeJSONAllowedValues(raw.AllowedValues); err != nil {
+			return err
+		}
+	}
+	if raw.PatternRules == nil {
+		return nil
+	}
+
+	t.PatternRules = make(map[string]string, len(raw.PatternRules))
+	severities := make(map[string]Severity)
+	examples := make(map[string]string)
+	for tag, item := range raw.PatternRules {
+		var entry struct {
+			Pattern  string   `json:"pattern"`
+			Severity Severity `json:"severity"`
+			Example  string   `json:"example"`
+		}
+		if err := decodeJSONEntry(item, &entry.Pattern, &entry); err != nil {
+			return fmt.Errorf("pattern rule for tag %s: %w", tag, err)
+		}
+		t.PatternRules[tag] = entry.Pattern
+		if entry.Severity != "" {
+			severities[tag] = entry.Severity
+		}
+		if entry.Example != "" {
+			examples[tag] = entry.Example
+		}
+	}
+
+	t.PatternRuleSeverities = mergeSeverities(t.PatternRuleSeverities, severities)
+	t.PatternRuleExamples = mergeExamples(t.PatternRuleExamples, examples)
+	return nil
+}
+
+// decodeJSONAllowedValues records the allowed values of every tag, and the deprecated ones
+// among them
+func (t *TagValidation) decodeJSONAllowedValues(allowedValues map[string][]json.RawMessage) error {
+	t.AllowedValues = make(map[string][]string, len(allowedValues))
+	deprecated := make(map[string]map[string]string)
+	for tag, items := range allowedValues {
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			var entry allowedValueEntry
+			if err := decodeJSONEntry(item, &entry.Value, &entry); err != nil {
+				return fmt.Errorf("allowed value of tag %s: %w", tag, err)
+			}
+			if err := entry.record(tag, deprecated); err != nil {
+				return err
+			}
+			values = append(values, entry.Value)
+		}
+		t.AllowedValues[tag] = values
+	}
+
+	t.DeprecatedValues = mergeDeprecatedValues(t.DeprecatedValues, deprecated)
+	return nil
+}
+
+// allowedValueEntry is an allowed value written as a {value, deprecated, replacement} entry
+type allowedValueEntry struct {
+	Value       string `yaml:"value" json:"value"`
+	Deprecated  bool   `yaml:"deprecated" json:"deprecated"`
+	Replacement string `yaml:"replacement" json:"replacement"`
+}
+
+// record adds the entry of an allowed value of a tag to the deprecated values when it is
+// deprecated
+func (e allowedValueEntry) record(tag string, deprecated map[string]map[string]string) error {
+	if e.Value == "" {
+		return fmt.Errorf("allowed value of tag %s must have a value", tag)
+	}
+	if !e.Deprecated {
+		if e.Replacement != "" {
+			return fmt.Errorf("allowed value %s of tag %s has a replacement but is not deprecated", e.Value, tag)
+		}
+		return nil
+	}
+
+	if deprecated[tag] == nil {
+		deprecated[tag] = make(map[string]string)
+	}
+	deprecated[tag][e.Value] = e.Replacement
+	return nil
+}
+
+// decodeJSONEntry decodes a JSON string into scalar, or any other value into entry
+func decodeJSONEntry(data json.RawMessage, scalar *string, entry interface{}) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		return json.Unmarshal(data, scalar)
+	}
+	return json.Unmarshal(data, entry)
+}
+
+// extractSeverities rewrites the mapping entries of the given field of a mapping node into
+// plain scalars, so the field keeps decoding into its plain Go type, and returns the
+// severities they declared. Entries of a sequence are keyed by the scalar they are
+// rewritten to, entries of a mapping by their key.
+func extractSeverities(node *yaml.Node, field string, decode func(*yaml.Node) (string, Severity, error)) (map[string]Severity, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	severities := make(map[string]Severity)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != field {
+			continue
+		}
+
+		value := node.Content[i+1]
+		switch value.Kind {
+		case yaml.SequenceNode:
+			for j, item := range value.Content {
+				if item.Kind != yaml.MappingNode {
+					continue
+				}
+				scalar, severity, err := decode(item)
+				if err != nil {
+					return nil, err
+				}
+				value.Content[j] = scalarNode(item, scalar)
+				if severity != "" {
+					severities[scalar] = severity
+				}
+			}
+		case yaml.MappingNode:
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				key, item := value.Content[j].Value, value.Content[j+1]
+				if item.Kind != yaml.MappingNode {
+					continue
+				}
+				scalar, severity, err := decode(item)
+				if err != nil {
+					return nil, err
+				}
+				value.Content[j+1] = scalarNode(item, scalar)
+				if severity != "" {
+					severities[key] = severity
+				}
+			}
+		}
+	}
+
+	return severities, nil
+}
+
+// extractPatternExamples returns the examples declared by the {pattern, example} entries of
+// the pattern_rules field of a mapping node, keyed by tag
+func extractPatternExamples(node *yaml.Node) map[string]string {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	examples := make(map[string]string)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		rules := node.Content[i+1]
+		if node.Content[i].Value != "pattern_rules" || rules.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(rules.Content); j += 2 {
+			var entry struct {
+				Example string `yaml:"example"`
+			}
+			if item := rules.Content[j+1]; item.Kind == yaml.MappingNode && item.Decode(&entry) == nil && entry.Example != "" {
+				examples[rules.Content[j].Value] = entry.Example
+			}
+		}
+	}
+	return examples
+}
+
+// extractDeprecatedValues rewrites the {value, deprecated, replacement} entries of the
+// allowed_values field of a mapping node into plain values and returns the deprecated ones,
+// keyed by tag and value
+func extractDeprecatedValues(node *yaml.Node) (map[string]map[string]string, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	deprecated := make(map[string]map[string]string)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		allowedValues := node.Content[i+1]
+		if node.Content[i].Value != "allowed_values" || allowedValues.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(allowedValues.Content); j += 2 {
+			tag, values := allowedValues.Content[j].Value, allowedValues.Content[j+1]
+			if values.Kind != yaml.SequenceNode {
+				continue
+			}
+			for k, item := range values.Content {
+				if item.Kind != yaml.MappingNode {
+					continue
+				}
+				var entry allowedValueEntry
+				if err := item.Decode(&entry); err != nil {
+					return nil, err
+				}
+				if err := entry.record(tag, deprecated); err != nil {
+					return nil, fmt.Errorf("line %d: %w", item.Line, err)
+				}
+				values.Content[k] = scalarNode(item, entry.Value)
+			}
+		}
+	}
+	return deprecated, nil
+}
+
+// scalarNode returns a string node replacing the given node
+func scalarNode(replaced *yaml.Node, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, Line: replaced.Line, Column: replaced.Column}
+}
+
+// mergeSeverities adds the extracted severities to the ones declared explicitly
+func mergeSeverities(declared, extracted map[string]Severity) map[string]Severity {
+	if len(extracted) == 0 {
+		return declared
+	}
+	if declared == nil {
+		declared = make(map[string]Severity, len(extracted))
+	}
+	for key, severity := range extracted {
+		declared[key] = severity
+	}
+	return declared
+}
+
+// mergeDeprecatedValues adds the extracted deprecated values to the ones declared explicitly
+func mergeDeprecatedValues(declared, extracted map[string]map[string]string) map[string]map[string]string {
+	if len(extracted) == 0 {
+		return declared
+	}
+	if declared == nil {
+		declared = make(map[string]map[string]string, len(extracted))
+	}
+	for tag, values := range extracted {
+		if declared[tag] == nil {
+			declared[tag] = make(map[string]string, len(values))
+		}
+		for value, replacement := range values {
+			declared[tag][value] = replacement
+		}
+	}
+	return declared
+}
+
+// mergeExamples adds the extracted pattern rule examples to the ones declared explicitly
+func mergeExamples(declared, extracted map[string]string) map[string]string {
+	if len(extracted) == 0 {
+		return declared
+	}
+	if declared == nil {
+		declared = make(map[string]string, len(extracted))
+	}
+	for tag, example := range extracted {
+		declared[tag] = example
+	}
+	return declared
+}
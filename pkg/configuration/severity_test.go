@@ -0,0 +1,247 @@
+package configuration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+func TestTagCriteria_UnmarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		yaml               string
+		expectedTags       []string
+		expectedSeverities map[string]Severity
+		wantErr            bool
+	}{
+		{
+			name: "Plain Tag Names",
+			yaml: `
+required_tags:
+  - Owner
+  - Environment
+`,
+			expectedTags: []string{"Owner", "Environment"},
+		},
+		{
+			name: "Tags With Severity",
+			yaml: `
+required_tags:
+  - Owner
+  - name: DataClassification
+    severity: critical
+  - name: Project
+required_tag_severities:
+  Owner: high
+`,
+			expectedTags: []string{"Owner", "DataClassification", "Project"},
+			expectedSeverities: map[string]Severity{
+				"Owner":              SeverityHigh,
+				"DataClassification": SeverityCritical,
+			},
+		},
+		{
+			name: "Tag Without Name",
+			yaml: `
+required_tags:
+  - severity: high
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var criteria TagCriteria
+			err := yaml.Unmarshal([]byte(tc.yaml), &criteria)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTags, criteria.RequiredTags)
+			assert.Equal(t, tc.expectedSeverities, criteria.RequiredTagSeverities)
+		})
+	}
+}
+
+func TestSchema_AcceptsRequiredTagsWithSeverity(t *testing.T) {
+	t.Parallel()
+
+	// Every tag_criteria is a TagCriteria, taking the required tags in both forms
+	testCases := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{
+			name: "Global",
+			yaml: `
+global:
+  tag_criteria:
+    minimum_required_tags: 1
+    required_tags:
+      - Owner
+      - name: DataClassification
+        severity: critical
+`,
+		},
+		{
+			name: "Resource",
+			yaml: `
+resources:
+  s3:
+    tag_criteria:
+      minimum_required_tags: 1
+      max_tags: 20
+      required_tags:
+        - name: DataClassification
+          severity: critical
+      required_tag_severities:
+        Owner: high
+`,
+		},
+		{
+			name: "Template",
+			yaml: `
+tag_criteria_templates:
+  data-stores:
+    minimum_required_tags: 1
+    required_tags:
+      - Owner
+      - name: DataClassification
+        severity: critical
+    required_tag_severities:
+      Owner: high
+`,
+		},
+		{
+			name: "Template With Unknown Severity",
+			yaml: `
+tag_criteria_templates:
+  data-stores:
+    required_tags:
+      - name: DataClassification
+        severity: urgent
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var document map[string]any
+			require.NoError(t, yaml.Unmarshal([]byte(tc.yaml), &document))
+
+			result, err := gojsonschema.Validate(gojsonschema.NewStringLoader(tagComplianceSchema),
+				gojsonschema.NewGoLoader(document))
+			require.NoError(t, err)
+			assert.Equal(t, !tc.wantErr, result.Valid(), "%v", result.Errors())
+		})
+	}
+}
+
+func TestTagValidation_UnmarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	var validation TagValidation
+	err := yaml.Unmarshal([]byte(`
+pattern_rules:
+  CostCenter: "^[A-Z]{2}-[0-9]{4}$"
+  Owner:
+    pattern: "^[a-z]+@company\\.com$"
+    severity: high
+    example: platform@company.com
+case_rules:
+  Environment:
+    case: lowercase
+    severity: low
+`), &validation)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"CostCenter": `^[A-Z]{2}-[0-9]{4}$`,
+		"Owner":      `^[a-z]+@company\.com$`,
+	}, validation.PatternRules)
+	assert.Equal(t, map[string]Severity{"Owner": SeverityHigh}, validation.PatternRuleSeverities)
+	assert.Equal(t, SeverityLow, validation.CaseRules["Environment"].Severity)
+
+	assert.Equal(t, SeverityHigh, validation.PatternRuleSeverity("Owner"))
+	assert.Equal(t, DefaultSeverity, validation.PatternRuleSeverity("CostCenter"))
+
+	assert.Equal(t, "platform@company.com", validation.PatternRuleExample("Owner"))
+	assert.Empty(t, validation.PatternRuleExample("CostCenter"))
+}
+
+func TestTagValidation_UnmarshalDeprecatedValues(t *testing.T) {
+	t.Parallel()
+
+	var fromYAML TagValidation
+	err := yaml.Unmarshal([]byte(`
+allowed_values:
+  Environment:
+    - production
+    - staging
+    - value: qa
+      deprecated: true
+      replacement: staging
+    - value: test
+      deprecated: true
+  Team: [payments, web]
+`), &fromYAML)
+	require.NoError(t, err)
+
+	var fromJSON TagValidation
+	err = json.Unmarshal([]byte(`{"allowed_values": {
+		"Environment": ["production", "staging", {"value": "qa", "deprecated": true, "replacement": "staging"}, {"value": "test", "deprecated": true}],
+		"Team": ["payments", "web"]
+	}}`), &fromJSON)
+	require.NoError(t, err)
+
+	for name, validation := range map[string]TagValidation{"YAML": fromYAML, "JSON": fromJSON} {
+		assert.Equal(t, map[string][]string{
+			"Environment": {"production", "staging", "qa", "test"},
+			"Team":        {"payments", "web"},
+		}, validation.AllowedValues, name)
+		assert.Equal(t, map[string]map[string]string{
+			"Environment": {"qa": "staging", "test": ""},
+		}, validation.DeprecatedValues, name)
+
+		replacement, deprecated := validation.DeprecatedValue("Environment", "qa")
+		assert.True(t, deprecated, name)
+		assert.Equal(t, "staging", replacement, name)
+		_, deprecated = validation.DeprecatedValue("Environment", "staging")
+		assert.False(t, deprecated, name)
+	}
+
+	err = yaml.Unmarshal([]byte(`
+allowed_values:
+  Environment:
+    - value: qa
+      replacement: staging
+`), &fromYAML)
+	assert.ErrorContains(t, err, "has a replacement but is not deprecated")
+
+	err = json.Unmarshal([]byte(`{"allowed_values": {"Environment": [{"deprecated": true}]}}`), &fromJSON)
+	assert.ErrorContains(t, err, "must have a value")
+}
+
+func TestSeverity_OrDefault(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, SeverityMedium, Severity("").OrDefault())
+	assert.Equal(t, SeverityCritical, SeverityCritical.OrDefault())
+	assert.True(t, Severity("").IsValid())
+	assert.False(t, Severity("urgent").IsValid())
+}
@@ -3,6 +3,7 @@
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/Excoriate/aws-taggy/pkg/constants"
 )
@@ -16,10 +17,37 @@
 	constants.ResourceTypeSNS:            true,
 	constants.ResourceTypeRDS:            true,
 	constants.ResourceTypeSQS:            true,
+	constants.ResourceTypeEBS:            true,
+	constants.ResourceTypeCloudfront:     true,
+	constants.ResourceTypeAPIGateway:     true,
+	constants.ResourceTypeElastiCache:    true,
+	constants.ResourceTypeSecurityGroup:  true,
+	constants.ResourceTypeGeneric:        true,
 	constants.ResourceTypeLambda:         false,
 	constants.ResourceTypeEKS:            false,
 	constants.ResourceTypeECR:            false,
-	constants.ResourceTypeCloudfront:     false,
+
+	constants.ResourceTypeCloudWatchAlarms: true,
+	constants.ResourceTypeNATGateway:       true,
+	constants.ResourceTypeInternetGateway:  true,
+}
+
+// registeredResourceTypes are the resource types with a registered inspector, see
+// RegisterResourceType
+var registeredResourceTypes sync.Map
+
+// RegisterResourceType accepts a resource type in configuration files. It is called by
+// inspector.RegisterInspector for every registered inspector, so custom resource types pass
+// the configuration validation; the configuration cannot look up the inspector registry
+// itself, which depends on it.
+func RegisterResourceType(resourceType string) {
+	registeredResourceTypes.Store(resourceType, true)
+}
+
+// IsRegisteredResourceType reports whether an inspector is registered for the resource type
+func IsRegisteredResourceType(resourceType string) bool {
+	_, registered := registeredResourceTypes.Load(resourceType)
+	return registered
 }
 
 var SupportedAWSRegions = map[string]bool{
@@ -41,6 +69,12 @@
 	"sa-east-1":      true,
 	"me-south-1":     true,
 	"af-south-1":     true,
+
+	// GovCloud (US) and China regions, scanned when listed explicitly
+	"us-gov-east-1":  true,
+	"us-gov-west-1":  true,
+	"cn-north-1":     true,
+	"cn-northwest-1": true,
 }
 
 // NormalizeResourceType normalizes the resource type string by:
@@ -67,8 +101,18 @@ func NormalizeResourceType(resource string) string {
 		return constants.ResourceTypeS3
 	case "simple-notification-service", "sns":
 		return constants.ResourceTypeSNS
+	case "security-group", "security_group", "securitygroup":
+		return constants.ResourceTypeSecurityGroup
+	case "nat-gateway", "nat_gateway", "natgateway":
+		return constants.ResourceTypeNATGateway
+	case "internet-gateway", "internet_gateway", "internetgateway":
+		return constants.ResourceTypeInternetGateway
 	case "relational-database-service", "rds":
 		return constants.ResourceTypeRDS
+	case "cloudwatch-alarms", "cloudwatch_alarms", "cloudwatchalarms", "cloudwatch":
+		return constants.ResourceTypeCloudWatchAlarms
+	case "cloudwatch-logs", "cloudwatch_logs", "cloudwatchlogs":
+		return constants.ResourceTypeCloudWatchLogs
 	default:
 		return normalized
 	}
@@ -80,6 +124,8 @@ func NormalizeResourceType(resource string) string {
 // 2. Checks if the normalized resource type exists in the predefined SupportedAWSResources map
 // 3. Verifies that the resource type is enabled (value is true in the map)
 //
+// Resource types with a registered inspector, see RegisterResourceType, are supported as well.
+//
 // Parameters:
 //   - resource: A string representing the AWS resource type to validate
 //
@@ -95,6 +141,10 @@ func NormalizeResourceType(resource string) string {
 func IsSupportedAWSResource(resource string) error {
 	normalized := NormalizeResourceType(resource)
 
+	if IsRegisteredResourceType(resource) || IsRegisteredResourceType(normalized) {
+		return nil
+	}
+
 	supported, exists := SupportedAWSResources[normalized]
 	if !exists {
 		return fmt.Errorf("unsupported resource type: %s", resource)
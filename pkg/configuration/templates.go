@@ -0,0 +1,92 @@
+package configuration
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ResolveTagCriteria returns tag criteria merged onto the template they name. Required and
+// forbidden tags add to those of the template, required tag severities and specific tags
+// override them key by key, and the minimum required tags, compliance level and maximum
+// tags override them when set. The resolved criteria do not name any template.
+func (c *TaggyScanConfig) ResolveTagCriteria(criteria TagCriteria) (TagCriteria, error) {
+	if criteria.Template == "" {
+		return criteria, nil
+	}
+
+	template, exists := c.TagCriteriaTemplates[criteria.Template]
+	if !exists {
+		return TagCriteria{}, fmt.Errorf("unknown tag criteria template %s", criteria.Template)
+	}
+	if template.Template != "" {
+		return TagCriteria{}, fmt.Errorf("tag criteria template %s references template %s, templates cannot be nested",
+			criteria.Template, template.Template)
+	}
+
+	resolved := TagCriteria{
+		MinimumRequiredTags:     template.MinimumRequiredTags,
+		RequiredTags:            mergeTagKeys(template.RequiredTags, criteria.RequiredTags),
+		RequiredTagSeverities:   mergeTagMaps(template.RequiredTagSeverities, criteria.RequiredTagSeverities),
+		ForbiddenTags:           mergeTagKeys(template.ForbiddenTags, criteria.ForbiddenTags),
+		ForbiddenTagsIgnoreCase: template.ForbiddenTagsIgnoreCase || criteria.ForbiddenTagsIgnoreCase,
+		SpecificTags:            mergeTagMaps(template.SpecificTags, criteria.SpecificTags),
+		ComplianceLevel:         template.ComplianceLevel,
+		MaxTags:                 template.MaxTags,
+	}
+	if criteria.MinimumRequiredTags != 0 {
+		resolved.MinimumRequiredTags = criteria.MinimumRequiredTags
+	}
+	if criteria.ComplianceLevel != "" {
+		resolved.ComplianceLevel = criteria.ComplianceLevel
+	}
+	if criteria.MaxTags != 0 {
+		resolved.MaxTags = criteria.MaxTags
+	}
+
+	return resolved, nil
+}
+
+// ResolveTagCriteriaTemplates replaces the tag criteria of every resource naming a template
+// by their resolved criteria, see ResolveTagCriteria
+func (c *TaggyScanConfig) ResolveTagCriteriaTemplates() error {
+	for _, resourceType := range slices.Sorted(maps.Keys(c.Resources)) {
+		resourceConfig := c.Resources[resourceType]
+		if resourceConfig.TagCriteria.Template == "" {
+			continue
+		}
+
+		resolved, err := c.ResolveTagCriteria(resourceConfig.TagCriteria)
+		if err != nil {
+			return fmt.Errorf("resource %s: %w", resourceType, err)
+		}
+		resourceConfig.TagCriteria = resolved
+		c.Resources[resourceType] = resourceConfig
+	}
+
+	return nil
+}
+
+// mergeTagKeys returns the tag keys of the template followed by the new ones of the overrides
+func mergeTagKeys(template, overrides []string) []string {
+	var merged []string
+	for _, key := range append(slices.Clone(template), overrides...) {
+		if !slices.Contains(merged, key) {
+			merged = append(merged, key)
+		}
+	}
+	return merged
+}
+
+// mergeTagMaps returns the entries of the template overridden by those of the overrides,
+// nil when both are empty
+func mergeTagMaps[V any](template, overrides map[string]V) map[string]V {
+	if len(template) == 0 && len(overrides) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]V, len(template)+len(overrides))
+	maps.Copy(merged, template)
+	maps.Copy(merged, overrides)
+	return merged
+}
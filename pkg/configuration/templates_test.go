@@ -0,0 +1,120 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTemplatesConfig() *TaggyScanConfig {
+	return &TaggyScanConfig{
+		TagCriteriaTemplates: map[string]TagCriteria{
+			"workload": {
+				MinimumRequiredTags:   2,
+				RequiredTags:          []string{"Environment", "Owner"},
+				RequiredTagSeverities: map[string]Severity{"Owner": SeverityHigh},
+				ForbiddenTags:         []string{"Temp"},
+				SpecificTags:          map[string]string{"ManagedBy": "terraform"},
+				ComplianceLevel:       "standard",
+			},
+			"nested": {
+				Template:     "workload",
+				RequiredTags: []string{"Team"},
+			},
+		},
+	}
+}
+
+func TestResolveTagCriteria(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		criteria TagCriteria
+		expected TagCriteria
+	}{
+		{
+			name:     "Criteria Without Template",
+			criteria: TagCriteria{RequiredTags: []string{"Team"}},
+			expected: TagCriteria{RequiredTags: []string{"Team"}},
+		},
+		{
+			name:     "Template Only",
+			criteria: TagCriteria{Template: "workload"},
+			expected: TagCriteria{
+				MinimumRequiredTags:   2,
+				RequiredTags:          []string{"Environment", "Owner"},
+				RequiredTagSeverities: map[string]Severity{"Owner": SeverityHigh},
+				ForbiddenTags:         []string{"Temp"},
+				SpecificTags:          map[string]string{"ManagedBy": "terraform"},
+				ComplianceLevel:       "standard",
+			},
+		},
+		{
+			name: "Local Settings Override The Template",
+			criteria: TagCriteria{
+				Template:              "workload",
+				MinimumRequiredTags:   3,
+				RequiredTags:          []string{"Owner", "BackupPolicy"},
+				RequiredTagSeverities: map[string]Severity{"Owner": SeverityCritical},
+				SpecificTags:          map[string]string{"ManagedBy": "cloudformation", "Tier": "data"},
+				ComplianceLevel:       "high",
+				MaxTags:               20,
+			},
+			expected: TagCriteria{
+				MinimumRequiredTags:   3,
+				RequiredTags:          []string{"Environment", "Owner", "BackupPolicy"},
+				RequiredTagSeverities: map[string]Severity{"Owner": SeverityCritical},
+				ForbiddenTags:         []string{"Temp"},
+				SpecificTags:          map[string]string{"ManagedBy": "cloudformation", "Tier": "data"},
+				ComplianceLevel:       "high",
+				MaxTags:               20,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolved, err := createTemplatesConfig().ResolveTagCriteria(tc.criteria)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, resolved)
+		})
+	}
+}
+
+func TestResolveTagCriteriaErrors(t *testing.T) {
+	t.Parallel()
+
+	cfg := createTemplatesConfig()
+
+	_, err := cfg.ResolveTagCriteria(TagCriteria{Template: "unknown"})
+	assert.ErrorContains(t, err, "unknown tag criteria template unknown")
+
+	_, err = cfg.ResolveTagCriteria(TagCriteria{Template: "nested"})
+	assert.ErrorContains(t, err, "templates cannot be nested")
+}
+
+func TestResolveTagCriteriaTemplates(t *testing.T) {
+	t.Parallel()
+
+	cfg := createTemplatesConfig()
+	cfg.Resources = map[string]ResourceConfig{
+		"ec2": {Enabled: true, TagCriteria: TagCriteria{Template: "workload"}},
+		"rds": {Enabled: true, TagCriteria: TagCriteria{Template: "workload", RequiredTags: []string{"BackupPolicy"}}},
+		"s3":  {Enabled: true, TagCriteria: TagCriteria{RequiredTags: []string{"DataClassification"}}},
+	}
+
+	require.NoError(t, cfg.ResolveTagCriteriaTemplates())
+
+	assert.Equal(t, []string{"Environment", "Owner"}, cfg.Resources["ec2"].TagCriteria.RequiredTags)
+	assert.Equal(t, []string{"Environment", "Owner", "BackupPolicy"}, cfg.Resources["rds"].TagCriteria.RequiredTags)
+	assert.Equal(t, []string{"DataClassification"}, cfg.Resources["s3"].TagCriteria.RequiredTags)
+	assert.Empty(t, cfg.Resources["rds"].TagCriteria.Template)
+
+	// Resources do not share the slices and maps of the template
+	cfg.Resources["ec2"].TagCriteria.SpecificTags["ManagedBy"] = "manual"
+	assert.Equal(t, "terraform", cfg.TagCriteriaTemplates["workload"].SpecificTags["ManagedBy"])
+}
@@ -0,0 +1,129 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidationIssue is a single problem found in a configuration, located by a path-like
+// pointer to the offending setting (e.g. resources.s3.tag_criteria.minimum_required_tags)
+type ValidationIssue struct {
+	Path    string `json:"path" yaml:"path"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// Error implements the error interface
+func (i ValidationIssue) Error() string {
+	if i.Path == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("%s: %s", i.Path, i.Message)
+}
+
+// ValidationErrors collects every issue found while validating a configuration
+type ValidationErrors []ValidationIssue
+
+// Error implements the error interface, listing every issue as a numbered list
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d configuration problems found:", len(e))
+	for i, issue := range e {
+		fmt.Fprintf(&b, "\n  %d. %s", i+1, issue.Error())
+	}
+	return b.String()
+}
+
+// add records an issue at the given path
+func (e *ValidationErrors) add(path, format string, args ...interface{}) {
+	*e = append(*e, ValidationIssue{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// merge records the issues of err, which is kept as a single issue unless it already
+// carries ValidationErrors
+func (e *ValidationErrors) merge(err error) {
+	if err == nil {
+		return
+	}
+
+	var issues ValidationErrors
+	if errors.As(err, &issues) {
+		*e = append(*e, issues...)
+		return
+	}
+
+	*e = append(*e, ValidationIssue{Message: err.Error()})
+}
+
+// err returns the collected issues as an error, or nil when there are none
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Issues returns the issues carried by err: the collected issues for ValidationErrors, a
+// single issue without a path for any other error, and none for nil
+func Issues(err error) []ValidationIssue {
+	var issues ValidationErrors
+	issues.merge(err)
+	return issues
+}
+
+// RuleKind identifies the kind of tag validation rule a tag failed
+type RuleKind string
+
+const (
+	RuleKindCaseSensitivity RuleKind = "case_sensitivity"
+	RuleKindCaseRule        RuleKind = "case_rule"
+	RuleKindKeyPrefix       RuleKind = "key_prefix"
+	RuleKindKeySuffix       RuleKind = "key_suffix"
+	RuleKindKeyLength       RuleKind = "key_length"
+)
+
+// ValidationError is a tag failing a validation rule. It carries the message configured for
+// the rule, if any, so callers can tell rules apart without matching the error text.
+type ValidationError struct {
+	TagKey            string   `json:"tag_key" yaml:"tag_key"`
+	RuleKind          RuleKind `json:"rule_kind" yaml:"rule_kind"`
+	ConfiguredMessage string   `json:"configured_message,omitempty" yaml:"configured_message,omitempty"`
+	ActualValue       string   `json:"actual_value" yaml:"actual_value"`
+}
+
+// Error implements the error interface, preferring the configured message of the rule
+func (e *ValidationError) Error() string {
+	if e.ConfiguredMessage != "" {
+		return fmt.Sprintf("tag %s value %q: %s", e.TagKey, e.ActualValue, e.ConfiguredMessage)
+	}
+	return fmt.Sprintf("tag %s value %q does not satisfy the %s rule", e.TagKey, e.ActualValue, e.RuleKind)
+}
+
+// KeyValidationError is a tag key breaking a key_validation rule, along with what the rule
+// allows, so callers can report the allowed prefixes or suffixes without parsing the message
+type KeyValidationError struct {
+	TagKey   string   `json:"tag_key" yaml:"tag_key"`
+	RuleKind RuleKind `json:"rule_kind" yaml:"rule_kind"`
+
+	// Allowed lists the prefixes or suffixes allowed by the key_prefix and key_suffix rules
+	Allowed []string `json:"allowed,omitempty" yaml:"allowed,omitempty"`
+
+	// MaxLength is the maximum number of characters allowed by the key_length rule
+	MaxLength int `json:"max_length,omitempty" yaml:"max_length,omitempty"`
+}
+
+// Error implements the error interface
+func (e *KeyValidationError) Error() string {
+	switch e.RuleKind {
+	case RuleKindKeyPrefix:
+		return fmt.Sprintf("tag key %q must start with one of: %s", e.TagKey, strings.Join(e.Allowed, ", "))
+	case RuleKindKeySuffix:
+		return fmt.Sprintf("tag key %q must end with one of: %s", e.TagKey, strings.Join(e.Allowed, ", "))
+	default:
+		return fmt.Sprintf("tag key %q is longer than %d characters", e.TagKey, e.MaxLength)
+	}
+}
@@ -3,5 +3,5 @@
 const (
 	AppName                = "aws-taggy"
 	AppDescription         = "A powerful CLI to inspect and manage AWS resources tags"
-	SupportedConfigVersion = "1.0"
+	SupportedConfigVersion = "1.1"
 )
@@ -13,4 +13,39 @@
 	ResourceTypeRoute53        = "route53"
 	ResourceTypeSNS            = "sns"
 	ResourceTypeSQS            = "sqs"
+	ResourceTypeEBS            = "ebs"
+	ResourceTypeAPIGateway     = "apigateway"
+	ResourceTypeElastiCache    = "elasticache"
+	ResourceTypeSecurityGroup  = "securitygroup"
+
+	// ResourceTypeCloudWatchAlarms is the resource type of CloudWatch metric and composite
+	// alarms, named after the service; log groups are the ResourceTypeCloudWatchLogs type
+	ResourceTypeCloudWatchAlarms = "cloudwatch"
+
+	// NAT gateways and internet gateways, the egress points of VPCs
+	ResourceTypeNATGateway      = "natgateway"
+	ResourceTypeInternetGateway = "internetgateway"
+
+	// ResourceTypeGeneric covers any taggable resource through the Resource Groups Tagging API
+	ResourceTypeGeneric = "generic"
+)
+
+const (
+	// GlobalRegion is the region reported for the resources of global services, such as
+	// CloudFront distributions, which belong to no region
+	GlobalRegion = "global"
+
+	// GlobalServiceRegion is the region whose endpoint serves the APIs of global services
+	GlobalServiceRegion = "us-east-1"
+)
+
+const (
+	// PartitionAWS is the partition of the commercial AWS regions
+	PartitionAWS = "aws"
+
+	// PartitionAWSUSGov is the partition of the AWS GovCloud (US) regions
+	PartitionAWSUSGov = "aws-us-gov"
+
+	// PartitionAWSCN is the partition of the AWS China regions
+	PartitionAWSCN = "aws-cn"
 )
@@ -27,7 +27,7 @@ func TestAppConstants(t *testing.T) {
 		{
 			name:     "Supported Config Version",
 			constant: SupportedConfigVersion,
-			expected: "1.0",
+			expected: "1.1",
 		},
 	}
 
@@ -51,6 +51,11 @@ func TestAWSConstants(t *testing.T) {
 			constant: DefaultAWSRegion,
 			expected: "us-east-1",
 		},
+		{
+			name:     "Global Service Region",
+			constant: GlobalServiceRegion,
+			expected: "us-east-1",
+		},
 	}
 
 	for _, tc := range testCases {
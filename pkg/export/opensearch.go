@@ -0,0 +1,241 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// DefaultBulkSize is the number of documents sent per bulk request when the options set none
+const DefaultBulkSize = 500
+
+// DefaultSigV4Service is the signing name of Amazon OpenSearch Service domains, serverless
+// collections use "aoss"
+const DefaultSigV4Service = "es"
+
+// OpenSearchOptions configures the cluster documents are exported to
+type OpenSearchOptions struct {
+	// URL is the endpoint of the cluster, e.g. https://search-findings.eu-west-1.es.amazonaws.com
+	URL string
+
+	// Index is the index the documents are written to
+	Index string
+
+	// Username and Password authenticate the requests with HTTP basic auth when set
+	Username string
+	Password string
+
+	// SigV4 signs the requests for Amazon OpenSearch Service when set, instead of basic auth
+	SigV4 *SigV4Options
+
+	// BulkSize is the number of documents per bulk request, DefaultBulkSize when 0
+	BulkSize int
+
+	// Client sends the requests, http.DefaultClient when nil
+	Client *http.Client
+}
+
+// SigV4Options are the AWS credentials and signing scope of the requests sent to Amazon
+// OpenSearch Service
+type SigV4Options struct {
+	Credentials aws.CredentialsProvider
+	Region      string
+
+	// Service is the signing name, DefaultSigV4Service when empty
+	Service string
+}
+
+// Document is a document indexed under its ID, so exporting it again replaces it
+type Document struct {
+	ID     string
+	Source any
+}
+
+// RejectedDocument is a document the cluster refused to index
+type RejectedDocument struct {
+	ID     string
+	Status int
+	Reason string
+}
+
+// BulkResult counts the documents indexed by an export and lists the rejected ones
+type BulkResult struct {
+	Indexed  int
+	Rejected []RejectedDocument
+}
+
+// OpenSearchExporter indexes documents into OpenSearch or Elasticsearch with the bulk API
+type OpenSearchExporter struct {
+	options  OpenSearchOptions
+	endpoint string
+	signer   *v4.Signer
+}
+
+// NewOpenSearchExporter creates an exporter writing to the cluster and index of the options
+func NewOpenSearchExporter(options OpenSearchOptions) (*OpenSearchExporter, error) {
+	endpoint, err := url.Parse(options.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid OpenSearch URL %q: an http or https URL is required", options.URL)
+	}
+	if options.Index == "" {
+		return nil, fmt.Errorf("an OpenSearch index is required")
+	}
+	if options.SigV4 != nil {
+		if options.SigV4.Credentials == nil || options.SigV4.Region == "" {
+			return nil, fmt.Errorf("SigV4 signing requires AWS credentials and a region")
+		}
+		if options.SigV4.Service == "" {
+			options.SigV4.Service = DefaultSigV4Service
+		}
+	}
+	if options.BulkSize <= 0 {
+		options.BulkSize = DefaultBulkSize
+	}
+	if options.Client == nil {
+		options.Client = http.DefaultClient
+	}
+
+	return &OpenSearchExporter{
+		options:  options,
+		endpoint: strings.TrimSuffix(endpoint.String(), "/") + "/_bulk",
+		signer:   v4.NewSigner(),
+	}, nil
+}
+
+// Export indexes the documents in bulk requests of the configured size. Documents rejected
+// by the cluster are reported in the result, a failing request stops the export with an
+// error and the result of the requests sent before it.
+func (e *OpenSearchExporter) Export(ctx context.Context, documents []Document) (*BulkResult, error) {
+	result := &BulkResult{}
+
+	for start := 0; start < len(documents); start += e.options.BulkSize {
+		batch := documents[start:min(start+e.options.BulkSize, len(documents))]
+		if err := e.sendBulk(ctx, batch, result); err != nil {
+			return result, fmt.Errorf("bulk request of documents %d to %d failed: %w", start+1, start+len(batch), err)
+		}
+	}
+
+	return result, nil
+}
+
+// bulkResponse is the part of a bulk API response reporting the outcome of each document
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		ID     string `json:"_id"`
+		Status int    `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// sendBulk indexes a batch of documents and adds their outcome to the result
+func (e *OpenSearchExporter) sendBulk(ctx context.Context, documents []Document, result *BulkResult) error {
+	body, err := e.bulkBody(documents)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-ndjson")
+
+	if err := e.authenticate(ctx, request, body); err != nil {
+		return err
+	}
+
+	response, err := e.options.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("OpenSearch returned %s: %s", response.Status, strings.TrimSpace(string(responseBody)))
+	}
+
+	var bulk bulkResponse
+	if err := json.Unmarshal(responseBody, &bulk); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	for _, item := range bulk.Items {
+		for _, outcome := range item {
+			if outcome.Status >= 200 && outcome.Status < 300 {
+				result.Indexed++
+				continue
+			}
+			rejected := RejectedDocument{ID: outcome.ID, Status: outcome.Status}
+			if outcome.Error != nil {
+				rejected.Reason = fmt.Sprintf("%s: %s", outcome.Error.Type, outcome.Error.Reason)
+			}
+			result.Rejected = append(result.Rejected, rejected)
+		}
+	}
+
+	return nil
+}
+
+// bulkBody encodes the documents as index actions of a bulk request, in newline-delimited JSON
+func (e *OpenSearchExporter) bulkBody(documents []Document) ([]byte, error) {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+
+	for _, document := range documents {
+		action := map[string]map[string]string{"index": {"_index": e.options.Index, "_id": document.ID}}
+		if err := encoder.Encode(action); err != nil {
+			return nil, fmt.Errorf("failed to encode bulk action of document %s: %w", document.ID, err)
+		}
+		if err := encoder.Encode(document.Source); err != nil {
+			return nil, fmt.Errorf("failed to encode document %s: %w", document.ID, err)
+		}
+	}
+
+	return body.Bytes(), nil
+}
+
+// authenticate signs the request with SigV4 or sets its basic auth credentials, as the
+// options require
+func (e *OpenSearchExporter) authenticate(ctx context.Context, request *http.Request, body []byte) error {
+	if e.options.SigV4 == nil {
+		if e.options.Username != "" {
+			request.SetBasicAuth(e.options.Username, e.options.Password)
+		}
+		return nil
+	}
+
+	credentials, err := e.options.SigV4.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+
+	payloadHash := sha256.Sum256(body)
+	hash := hex.EncodeToString(payloadHash[:])
+
+	// Serverless collections require the payload hash header
+	request.Header.Set("X-Amz-Content-Sha256", hash)
+
+	if err := e.signer.SignHTTP(ctx, credentials, request, hash, e.options.SigV4.Service, e.options.SigV4.Region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bulkServer is an OpenSearch bulk API double recording the requests it receives and
+// rejecting the documents whose ID starts with "bad"
+type bulkServer struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	actions  [][]map[string]map[string]string
+}
+
+func (s *bulkServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var actions []map[string]map[string]string
+	var items []map[string]any
+
+	scanner := bufio.NewScanner(r.Body)
+	for line := 0; scanner.Scan(); line++ {
+		if line%2 == 1 {
+			continue
+		}
+		var action map[string]map[string]string
+		if err := json.Unmarshal(scanner.Bytes(), &action); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		actions = append(actions, action)
+
+		id := action["index"]["_id"]
+		outcome := map[string]any{"_id": id, "status": http.StatusCreated}
+		if strings.HasPrefix(id, "bad") {
+			outcome = map[string]any{
+				"_id":    id,
+				"status": http.StatusBadRequest,
+				"error":  map[string]string{"type": "mapper_parsing_exception", "reason": "failed to parse field [score]"},
+			}
+		}
+		items = append(items, map[string]any{"index": outcome})
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, r)
+	s.actions = append(s.actions, actions)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"errors": true, "items": items})
+}
+
+func newDocuments(ids ...string) []Document {
+	documents := make([]Document, 0, len(ids))
+	for _, id := range ids {
+		documents = append(documents, Document{ID: id, Source: map[string]string{"resource_arn": id}})
+	}
+	return documents
+}
+
+func TestOpenSearchExporter_Export(t *testing.T) {
+	server := &bulkServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	exporter, err := NewOpenSearchExporter(OpenSearchOptions{
+		URL:      httpServer.URL + "/",
+		Index:    "aws-taggy",
+		Username: "taggy",
+		Password: "secret",
+		BulkSize: 2,
+	})
+	require.NoError(t, err)
+
+	result, err := exporter.Export(context.Background(), newDocuments("arn:1", "bad:2", "arn:3"))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Indexed)
+	assert.Equal(t, []RejectedDocument{
+		{ID: "bad:2", Status: http.StatusBadRequest, Reason: "mapper_parsing_exception: failed to parse field [score]"},
+	}, result.Rejected)
+
+	require.Len(t, server.requests, 2)
+	for _, request := range server.requests {
+		assert.Equal(t, "/_bulk", request.URL.Path)
+		assert.Equal(t, "application/x-ndjson", request.Header.Get("Content-Type"))
+		username, password, ok := request.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "taggy", username)
+		assert.Equal(t, "secret", password)
+	}
+	assert.Equal(t, [][]map[string]map[string]string{
+		{{"index": {"_index": "aws-taggy", "_id": "arn:1"}}, {"index": {"_index": "aws-taggy", "_id": "bad:2"}}},
+		{{"index": {"_index": "aws-taggy", "_id": "arn:3"}}},
+	}, server.actions)
+}
+
+func TestOpenSearchExporter_SigV4(t *testing.T) {
+	server := &bulkServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	exporter, err := NewOpenSearchExporter(OpenSearchOptions{
+		URL:   httpServer.URL,
+		Index: "aws-taggy",
+		SigV4: &SigV4Options{
+			Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+				return aws.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}, nil
+			}),
+			Region: "eu-west-1",
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = exporter.Export(context.Background(), newDocuments("arn:1"))
+	require.NoError(t, err)
+
+	require.Len(t, server.requests, 1)
+	authorization := server.requests[0].Header.Get("Authorization")
+	assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), authorization)
+	assert.Contains(t, authorization, "/eu-west-1/es/aws4_request")
+	assert.NotEmpty(t, server.requests[0].Header.Get("X-Amz-Content-Sha256"))
+}
+
+func TestOpenSearchExporter_FailedRequest(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"security_exception"}`, http.StatusForbidden)
+	}))
+	defer httpServer.Close()
+
+	exporter, err := NewOpenSearchExporter(OpenSearchOptions{URL: httpServer.URL, Index: "aws-taggy"})
+	require.NoError(t, err)
+
+	result, err := exporter.Export(context.Background(), newDocuments("arn:1"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403 Forbidden")
+	assert.Zero(t, result.Indexed)
+}
+
+func TestNewOpenSearchExporter_InvalidOptions(t *testing.T) {
+	testCases := []OpenSearchOptions{
+		{URL: "search.example.com", Index: "aws-taggy"},
+		{URL: "https://search.example.com"},
+		{URL: "https://search.example.com", Index: "aws-taggy", SigV4: &SigV4Options{Region: "eu-west-1"}},
+	}
+
+	for i, options := range testCases {
+		t.Run(fmt.Sprintf("case %d", i+1), func(t *testing.T) {
+			_, err := NewOpenSearchExporter(options)
+			assert.Error(t, err)
+		})
+	}
+}
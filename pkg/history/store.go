@@ -0,0 +1,405 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/util"
+)
+
+// stateVersion identifies the layout of the state file. Files of older versions are
+// upgraded on open through migrations, files of newer versions are refused.
+const stateVersion = 1
+
+// migrations upgrade a snapshot line written by the version they are keyed by to the next
+// version. Every version bump adds the migration from the previous one.
+var migrations = map[int]func(json.RawMessage) (json.RawMessage, error){}
+
+// Snapshot is the state of a resource recorded by a run
+type Snapshot struct {
+	ARN          string            `json:"arn"`
+	ResourceID   string            `json:"resource_id,omitempty"`
+	ResourceType string            `json:"resource_type,omitempty"`
+	AccountID    string            `json:"account_id,omitempty"`
+	Region       string            `json:"region,omitempty"`
+	Tags         map[string]string `json:"tags"`
+
+	// Compliant is the compliance status of the resource, nil when the run recording it did
+	// not validate its tags, like discover
+	Compliant *bool `json:"compliant,omitempty"`
+
+	// ChangeMarker is the provider-supplied indicator of the last change of the resource,
+	// which lets incremental scans reuse the recorded tags while it is unchanged
+	ChangeMarker string `json:"change_marker,omitempty"`
+
+	// Deleted marks a resource an incremental scan no longer discovered
+	Deleted bool `json:"deleted,omitempty"`
+
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// stateHeader is the first line of the state file
+type stateHeader struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Store records the tags and compliance status of resources over time in a local file, to
+// answer questions like "when did this bucket lose its Owner tag?".
+//
+// The file holds a version header followed by one JSON snapshot per line. A snapshot is
+// only appended when the tags or the compliance status of a resource changed since its
+// last one, so the file grows with the changes rather than with the number of runs.
+type Store struct {
+	path string
+	now  func() time.Time
+}
+
+// DefaultPath returns the default state file location, ~/.aws-taggy/state.db
+func DefaultPath() (string, error) {
+	return util.ExpandHome(filepath.Join("~", ".aws-taggy", "state.db"))
+}
+
+// Open returns the store kept in the file at path, which is created on first write. A
+// leading "~" in path is expanded to the home directory of the current user.
+func Open(path string) (*Store, error) {
+	if path == "" {
+		return nil, fmt.Errorf("state file path cannot be empty")
+	}
+
+	expanded, err := util.ExpandHome(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{path: expanded, now: time.Now}, nil
+}
+
+// Path returns the location of the state file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Record stores the snapshots of a run, stamped with the current time, and returns how many
+// were written. Snapshots equal to the last one of their resource are skipped, the others
+// are appended in a single write.
+func (s *Store) Record(snapshots []Snapshot) (int, error) {
+	existing, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+
+	latest := make(map[string]Snapshot, len(existing))
+	for _, snapshot := range existing {
+		latest[snapshot.ARN] = snapshot
+	}
+
+	recordedAt := s.now().UTC()
+	var changed []Snapshot
+	for _, snapshot := range snapshots {
+		if previous, seen := latest[snapshot.ARN]; seen && !snapshot.differsFrom(previous) {
+			continue
+		}
+		snapshot.RecordedAt = recordedAt
+		latest[snapshot.ARN] = snapshot
+		changed = append(changed, snapshot)
+	}
+
+	if len(changed) == 0 {
+		return 0, nil
+	}
+
+	if err := s.append(changed, existing == nil); err != nil {
+		return 0, err
+	}
+	return len(changed), nil
+}
+
+// Snapshots returns the snapshots of a resource, oldest first
+func (s *Store) Snapshots(arn string) ([]Snapshot, error) {
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+	for _, snapshot := range all {
+		if snapshot.ARN == arn {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+// Latest returns the last snapshot of every resource, in the order they were recorded
+func (s *Store) Latest() ([]Snapshot, error) {
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	last := make(map[string]int, len(all))
+	for i, snapshot := range all {
+		last[snapshot.ARN] = i
+	}
+
+	latest := make([]Snapshot, 0, len(last))
+	for i, snapshot := range all {
+		if last[snapshot.ARN] == i {
+			latest = append(latest, snapshot)
+		}
+	}
+	return latest, nil
+}
+
+// Prune removes the snapshots recorded before cutoff and returns how many were removed. The
+// last snapshot of every resource is kept, as later runs compare against it.
+func (s *Store) Prune(cutoff time.Time) (int, error) {
+	all, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+
+	last := make(map[string]int, len(all))
+	for i, snapshot := range all {
+		last[snapshot.ARN] = i
+	}
+
+	kept := make([]Snapshot, 0, len(all))
+	for i, snapshot := range all {
+		if snapshot.RecordedAt.Before(cutoff) && last[snapshot.ARN] != i {
+			continue
+		}
+		kept = append(kept, snapshot)
+	}
+
+	removed := len(all) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.rewrite(kept)
+}
+
+// ParseAge parses the age of snapshots to prune: a Go duration (e.g. 720h) or a number of
+// days (e.g. 90d)
+func ParseAge(age string) (time.Duration, error) {
+	var duration time.Duration
+	if days, found := strings.CutSuffix(age, "d"); found {
+		count, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid age %q, expected a number of days (e.g. 90d) or a duration (e.g. 720h)", age)
+		}
+		duration = time.Duration(count) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(age)
+		if err != nil {
+			return 0, fmt.Errorf("invalid age %q, expected a number of days (e.g. 90d) or a duration (e.g. 720h)", age)
+		}
+		duration = parsed
+	}
+
+	if duration <= 0 {
+		return 0, fmt.Errorf("age must be positive, got %q", age)
+	}
+	return duration, nil
+}
+
+// differsFrom reports whether the snapshot changes the tags, the known compliance status,
+// the location, the change marker or the deletion of the previous snapshot of its resource
+func (s Snapshot) differsFrom(previous Snapshot) bool {
+	if s.Deleted != previous.Deleted {
+		return true
+	}
+	if s.Deleted {
+		return false
+	}
+	if !maps.Equal(s.Tags, previous.Tags) {
+		return true
+	}
+	if s.AccountID != previous.AccountID || s.Region != previous.Region || s.ChangeMarker != previous.ChangeMarker {
+		return true
+	}
+	if s.Compliant == nil {
+		return false
+	}
+	return previous.Compliant == nil || *s.Compliant != *previous.Compliant
+}
+
+// load reads every snapshot of the state file, oldest first, upgrading those written by
+// older versions. A missing or empty file yields nil, telling writers to start it with its
+// header.
+func (s *Store) load() ([]Snapshot, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state file %s: %w", s.path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read state file %s: %w", s.path, err)
+		}
+		// An empty file is started over, like a missing one
+		return nil, nil
+	}
+
+	var header stateHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.Version == 0 {
+		return nil, fmt.Errorf("state file %s has no valid version header", s.path)
+	}
+	if header.Version > stateVersion {
+		return nil, fmt.Errorf("state file %s has version %d, written by a newer aws-taggy supporting up to version %d",
+			s.path, header.Version, stateVersion)
+	}
+
+	snapshots := []Snapshot{}
+	for line := 2; scanner.Scan(); line++ {
+		data, err := migrate(header.Version, scanner.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to upgrade line %d of state file %s: %w", line, s.path, err)
+		}
+
+		var snapshot Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, fmt.Errorf("invalid snapshot on line %d of state file %s: %w", line, s.path, err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %w", s.path, err)
+	}
+
+	// Appending to a file of an older version would mix layouts, it is rewritten instead
+	if header.Version < stateVersion {
+		if err := s.rewrite(snapshots); err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshots, nil
+}
+
+// migrate upgrades a snapshot line written by the given version to the current one
+func migrate(version int, data []byte) (json.RawMessage, error) {
+	upgraded := json.RawMessage(data)
+	for ; version < stateVersion; version++ {
+		upgrade, exists := migrations[version]
+		if !exists {
+			return nil, fmt.Errorf("no migration from state version %d", version)
+		}
+
+		var err error
+		if upgraded, err = upgrade(upgraded); err != nil {
+			return nil, err
+		}
+	}
+	return upgraded, nil
+}
+
+// append adds the snapshots at the end of the state file in a single write, starting the
+// file with its header when it does not exist yet
+func (s *Store) append(snapshots []Snapshot, create bool) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory for %s: %w", s.path, err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open state file %s: %w", s.path, err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	if create {
+		if err := writeHeader(writer, s.now().UTC()); err != nil {
+			return err
+		}
+	}
+	if err := writeSnapshots(writer, snapshots); err != nil {
+		return err
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write state file %s: %w", s.path, err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync state file %s: %w", s.path, err)
+	}
+	return file.Close()
+}
+
+// rewrite replaces the state file with the given snapshots, through a temporary file renamed
+// over it so an interrupted rewrite leaves the previous file intact
+func (s *Store) rewrite(snapshots []Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory for %s: %w", s.path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	writer := bufio.NewWriter(tmp)
+	if err := writeHeader(writer, s.now().UTC()); err != nil {
+		return err
+	}
+	if err := writeSnapshots(writer, snapshots); err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write temporary state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary state file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace state file %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// writeHeader writes the version header line of a new state file
+func writeHeader(writer *bufio.Writer, createdAt time.Time) error {
+	return writeLine(writer, stateHeader{Version: stateVersion, CreatedAt: createdAt})
+}
+
+// writeSnapshots writes one snapshot per line
+func writeSnapshots(writer *bufio.Writer, snapshots []Snapshot) error {
+	for _, snapshot := range snapshots {
+		if err := writeLine(writer, snapshot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLine writes a value as a single JSON line
+func writeLine(writer *bufio.Writer, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode state entry: %w", err)
+	}
+	data = append(data, '\n')
+	if _, err := writer.Write(data); err != nil {
+		return fmt.Errorf("failed to write state entry: %w", err)
+	}
+	return nil
+}
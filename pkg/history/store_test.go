@@ -0,0 +1,201 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bucketARN = "arn:aws:s3:::logs"
+
+// newTestStore returns a store in a temporary directory whose clock is advanced by a day
+// with every call to tick
+func newTestStore(t *testing.T) (*Store, func()) {
+	t.Helper()
+
+	store, err := Open(filepath.Join(t.TempDir(), "state.db"))
+	require.NoError(t, err)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	return store, func() { now = now.Add(24 * time.Hour) }
+}
+
+func compliant(status bool) *bool {
+	return &status
+}
+
+func TestStoreRecordsOnlyChanges(t *testing.T) {
+	t.Parallel()
+
+	store, tick := newTestStore(t)
+
+	runs := []struct {
+		tags      map[string]string
+		compliant *bool
+		written   int
+	}{
+		{tags: map[string]string{"Owner": "data"}, compliant: compliant(true), written: 1},
+		{tags: map[string]string{"Owner": "data"}, compliant: compliant(true), written: 0},
+		{tags: map[string]string{"Owner": "data"}, written: 0},
+		{tags: map[string]string{}, compliant: compliant(false), written: 1},
+	}
+
+	for i, run := range runs {
+		written, err := store.Record([]Snapshot{{ARN: bucketARN, ResourceType: "s3", Tags: run.tags, Compliant: run.compliant}})
+		require.NoError(t, err, "run %d", i)
+		assert.Equal(t, run.written, written, "run %d", i)
+		tick()
+	}
+
+	snapshots, err := store.Snapshots(bucketARN)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC), snapshots[1].RecordedAt)
+
+	assert.Equal(t, []Event{
+		{Time: snapshots[0].RecordedAt, Kind: EventFirstSeen},
+		{Time: snapshots[0].RecordedAt, Kind: EventBecameCompliant},
+		{Time: snapshots[1].RecordedAt, Kind: EventTagRemoved, TagKey: "Owner", OldValue: "data"},
+		{Time: snapshots[1].RecordedAt, Kind: EventBecameNonCompliant},
+	}, Timeline(snapshots))
+}
+
+func TestStoreRecordBatchesLargeScans(t *testing.T) {
+	t.Parallel()
+
+	store, _ := newTestStore(t)
+
+	snapshots := make([]Snapshot, 10000)
+	for i := range snapshots {
+		snapshots[i] = Snapshot{
+			ARN:  fmt.Sprintf("arn:aws:s3:::bucket-%05d", i),
+			Tags: map[string]string{"Owner": "platform", "Project": fmt.Sprintf("project-%d", i%100)},
+		}
+	}
+
+	written, err := store.Record(snapshots)
+	require.NoError(t, err)
+	assert.Equal(t, 10000, written)
+
+	written, err = store.Record(snapshots)
+	require.NoError(t, err)
+	assert.Zero(t, written)
+}
+
+func TestStorePruneKeepsTheLastSnapshot(t *testing.T) {
+	t.Parallel()
+
+	store, tick := newTestStore(t)
+	for _, owner := range []string{"data", "platform", "security"} {
+		_, err := store.Record([]Snapshot{
+			{ARN: bucketARN, Tags: map[string]string{"Owner": owner}},
+			{ARN: "arn:aws:s3:::archive", Tags: map[string]string{"Owner": "data"}},
+		})
+		require.NoError(t, err)
+		tick()
+	}
+
+	removed, err := store.Prune(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	snapshots, err := store.Snapshots(bucketARN)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "platform", snapshots[0].Tags["Owner"])
+
+	archive, err := store.Snapshots("arn:aws:s3:::archive")
+	require.NoError(t, err)
+	assert.Len(t, archive, 1)
+}
+
+func TestStoreRefusesNewerVersions(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.db")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version":99}`+"\n"), 0o644))
+
+	store, err := Open(path)
+	require.NoError(t, err)
+
+	_, err = store.Snapshots(bucketARN)
+	assert.ErrorContains(t, err, "written by a newer aws-taggy")
+}
+
+func TestTimelineKeepsTheLastKnownComplianceStatus(t *testing.T) {
+	t.Parallel()
+
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	events := Timeline([]Snapshot{
+		{Tags: map[string]string{"Owner": "data"}, Compliant: compliant(false), RecordedAt: day(1)},
+		{Tags: map[string]string{"Owner": "platform"}, RecordedAt: day(2)},
+		{Tags: map[string]string{"Owner": "platform", "Team": "core"}, Compliant: compliant(false), RecordedAt: day(3)},
+	})
+
+	assert.Equal(t, []Event{
+		{Time: day(1), Kind: EventFirstSeen},
+		{Time: day(1), Kind: EventBecameNonCompliant},
+		{Time: day(2), Kind: EventTagChanged, TagKey: "Owner", OldValue: "data", NewValue: "platform"},
+		{Time: day(3), Kind: EventTagAdded, TagKey: "Team", NewValue: "core"},
+	}, events)
+}
+
+func TestParseAge(t *testing.T) {
+	t.Parallel()
+
+	age, err := ParseAge("90d")
+	require.NoError(t, err)
+	assert.Equal(t, 90*24*time.Hour, age)
+
+	age, err = ParseAge("36h")
+	require.NoError(t, err)
+	assert.Equal(t, 36*time.Hour, age)
+
+	for _, invalid := range []string{"", "d", "ninety days", "0d", "-5h"} {
+		_, err := ParseAge(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestStoreRecordsDeletions(t *testing.T) {
+	t.Parallel()
+
+	store, tick := newTestStore(t)
+	tags := map[string]string{"Owner": "data"}
+
+	runs := [][]Snapshot{
+		{{ARN: bucketARN, Tags: tags, Compliant: compliant(true)}},
+		{{ARN: bucketARN, Deleted: true}},
+		{{ARN: bucketARN, Deleted: true}},
+		{{ARN: bucketARN, Tags: tags, Compliant: compliant(true)}},
+	}
+	for i, run := range runs {
+		_, err := store.Record(run)
+		require.NoError(t, err, "run %d", i)
+		tick()
+	}
+
+	snapshots, err := store.Snapshots(bucketARN)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 3)
+
+	assert.Equal(t, []Event{
+		{Time: snapshots[0].RecordedAt, Kind: EventFirstSeen},
+		{Time: snapshots[0].RecordedAt, Kind: EventBecameCompliant},
+		{Time: snapshots[1].RecordedAt, Kind: EventDeleted},
+		{Time: snapshots[2].RecordedAt, Kind: EventFirstSeen},
+		{Time: snapshots[2].RecordedAt, Kind: EventBecameCompliant},
+	}, Timeline(snapshots))
+
+	latest, err := store.Latest()
+	require.NoError(t, err)
+	require.Len(t, latest, 1)
+	assert.False(t, latest[0].Deleted)
+}
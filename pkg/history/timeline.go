@@ -0,0 +1,96 @@
+package history
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// Kinds of events of a resource timeline
+const (
+	EventFirstSeen          = "first_seen"
+	EventTagAdded           = "tag_added"
+	EventTagRemoved         = "tag_removed"
+	EventTagChanged         = "tag_changed"
+	EventBecameCompliant    = "became_compliant"
+	EventBecameNonCompliant = "became_non_compliant"
+	EventDeleted            = "deleted"
+)
+
+// Event is a change of a resource between two of its snapshots
+type Event struct {
+	Time     time.Time `json:"time" yaml:"time"`
+	Kind     string    `json:"kind" yaml:"kind"`
+	TagKey   string    `json:"tag_key,omitempty" yaml:"tag_key,omitempty"`
+	OldValue string    `json:"old_value,omitempty" yaml:"old_value,omitempty"`
+	NewValue string    `json:"new_value,omitempty" yaml:"new_value,omitempty"`
+}
+
+// Timeline returns the changes between consecutive snapshots of a resource, oldest first.
+// The first snapshot is reported as the resource being first seen. Compliance events are
+// reported when the status differs from the last known one, snapshots recorded without a
+// status leaving it unchanged. Tag events of a snapshot are sorted by key. A resource
+// discovered again after being deleted is reported as first seen anew.
+func Timeline(snapshots []Snapshot) []Event {
+	var events []Event
+	var compliant *bool
+	for i, snapshot := range snapshots {
+		if snapshot.Deleted {
+			events = append(events, Event{Time: snapshot.RecordedAt, Kind: EventDeleted})
+			compliant = nil
+			continue
+		}
+
+		if i == 0 || snapshots[i-1].Deleted {
+			events = append(events, Event{Time: snapshot.RecordedAt, Kind: EventFirstSeen})
+			events, compliant = appendComplianceEvent(events, compliant, snapshot)
+			continue
+		}
+
+		previous := snapshots[i-1]
+		keys := slices.Sorted(maps.Keys(unionKeys(previous.Tags, snapshot.Tags)))
+		for _, key := range keys {
+			oldValue, had := previous.Tags[key]
+			newValue, has := snapshot.Tags[key]
+			switch {
+			case !had:
+				events = append(events, Event{Time: snapshot.RecordedAt, Kind: EventTagAdded, TagKey: key, NewValue: newValue})
+			case !has:
+				events = append(events, Event{Time: snapshot.RecordedAt, Kind: EventTagRemoved, TagKey: key, OldValue: oldValue})
+			case oldValue != newValue:
+				events = append(events, Event{Time: snapshot.RecordedAt, Kind: EventTagChanged, TagKey: key, OldValue: oldValue, NewValue: newValue})
+			}
+		}
+		events, compliant = appendComplianceEvent(events, compliant, snapshot)
+	}
+	return events
+}
+
+// appendComplianceEvent adds the compliance status of the snapshot when it is known and
+// differs from the last known one, returning the status known after the snapshot
+func appendComplianceEvent(events []Event, known *bool, snapshot Snapshot) ([]Event, *bool) {
+	if snapshot.Compliant == nil {
+		return events, known
+	}
+	if known != nil && *known == *snapshot.Compliant {
+		return events, known
+	}
+
+	kind := EventBecameNonCompliant
+	if *snapshot.Compliant {
+		kind = EventBecameCompliant
+	}
+	return append(events, Event{Time: snapshot.RecordedAt, Kind: kind}), snapshot.Compliant
+}
+
+// unionKeys returns the set of keys of both tag maps
+func unionKeys(a, b map[string]string) map[string]bool {
+	keys := make(map[string]bool, len(a)+len(b))
+	for key := range a {
+		keys[key] = true
+	}
+	for key := range b {
+		keys[key] = true
+	}
+	return keys
+}
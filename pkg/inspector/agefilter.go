@@ -0,0 +1,63 @@
+package inspector
+
+import (
+	"fmt"
+	"time"
+)
+
+// AgeFilter restricts resources to the ones created in a time window. Resources whose
+// creation time is unknown are kept unless ExcludeUnknown is set.
+type AgeFilter struct {
+	// CreatedAfter keeps the resources created after this time, no lower bound when zero
+	CreatedAfter time.Time
+
+	// CreatedBefore keeps the resources created before this time, such as the time a minimum
+	// age ago, no upper bound when zero
+	CreatedBefore time.Time
+
+	// ExcludeUnknown drops the resources whose inspector cannot tell the creation time
+	ExcludeUnknown bool
+}
+
+// ParseCreationDate parses a creation date bound written as a date (e.g. 2024-01-01, midnight
+// UTC) or an RFC 3339 time
+func ParseCreationDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	if moment, err := time.Parse(time.RFC3339, value); err == nil {
+		return moment, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or an RFC 3339 time", value)
+}
+
+// IsZero reports whether the filter keeps every resource
+func (f AgeFilter) IsZero() bool {
+	return f.CreatedAfter.IsZero() && f.CreatedBefore.IsZero() && !f.ExcludeUnknown
+}
+
+// Matches reports whether a resource created at createdAt, zero when unknown, is in the window
+func (f AgeFilter) Matches(createdAt time.Time) bool {
+	if createdAt.IsZero() {
+		return !f.ExcludeUnknown
+	}
+	if !f.CreatedAfter.IsZero() && !createdAt.After(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !createdAt.Before(f.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// FilterByAge keeps, in every inspection result, the resources created in the window of the
+// filter. Excluded resources are filtered alike, and results left without any resource are
+// dropped. With a zero filter, the results are returned as they are.
+func FilterByAge(results map[string]*InspectResult, filter AgeFilter) map[string]*InspectResult {
+	if filter.IsZero() {
+		return results
+	}
+	return filterResources(results, func(resource ResourceMetadata) bool {
+		return filter.Matches(resource.CreatedAt)
+	})
+}
@@ -0,0 +1,99 @@
+package inspector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCreationDate(t *testing.T) {
+	t.Parallel()
+
+	date, err := ParseCreationDate("2024-01-01")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), date)
+
+	moment, err := ParseCreationDate("2024-01-01T12:30:00+02:00")
+	require.NoError(t, err)
+	assert.True(t, moment.Equal(time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)))
+
+	for _, invalid := range []string{"", "01/01/2024", "2024-13-01", "yesterday"} {
+		_, err := ParseCreationDate(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestFilterByAge(t *testing.T) {
+	t.Parallel()
+
+	resource := func(id string, createdAt time.Time) ResourceMetadata {
+		return ResourceMetadata{ID: id, CreatedAt: createdAt}
+	}
+	policyDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	results := map[string]*InspectResult{
+		"ec2": {
+			Resources: []ResourceMetadata{
+				resource("legacy", policyDate.AddDate(-1, 0, 0)),
+				resource("recent", policyDate.AddDate(0, 3, 0)),
+				resource("brand-new", policyDate.AddDate(0, 6, 0)),
+				resource("unknown", time.Time{}),
+			},
+			TotalResources: 4,
+			ExcludedResources: []ExcludedResource{
+				{Resource: resource("excluded-legacy", policyDate.AddDate(-1, 0, 0)), Pattern: "excluded-.*"},
+			},
+		},
+		"sns": {
+			Resources:      []ResourceMetadata{resource("topic", time.Time{})},
+			TotalResources: 1,
+		},
+	}
+
+	ids := func(resources []ResourceMetadata) []string {
+		var ids []string
+		for _, resource := range resources {
+			ids = append(ids, resource.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, results, FilterByAge(results, AgeFilter{}))
+
+	filtered := FilterByAge(results, AgeFilter{CreatedAfter: policyDate})
+	require.Contains(t, filtered, "ec2")
+	assert.Equal(t, []string{"recent", "brand-new", "unknown"}, ids(filtered["ec2"].Resources))
+	assert.Equal(t, 3, filtered["ec2"].TotalResources)
+	assert.Empty(t, filtered["ec2"].ExcludedResources)
+	assert.Contains(t, filtered, "sns", "resources of unknown creation time are kept by default")
+
+	filtered = FilterByAge(results, AgeFilter{
+		CreatedAfter:   policyDate,
+		CreatedBefore:  policyDate.AddDate(0, 4, 0),
+		ExcludeUnknown: true,
+	})
+	require.Contains(t, filtered, "ec2")
+	assert.Equal(t, []string{"recent"}, ids(filtered["ec2"].Resources))
+	assert.NotContains(t, filtered, "sns")
+
+	// The original results are left untouched
+	assert.Len(t, results["ec2"].Resources, 4)
+}
+
+func TestQueueCreatedAt(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, time.Unix(1704067200, 0).UTC(), queueCreatedAt(map[string]string{"CreatedTimestamp": "1704067200"}))
+	assert.True(t, queueCreatedAt(map[string]string{}).IsZero())
+	assert.True(t, queueCreatedAt(map[string]string{"CreatedTimestamp": "soon"}).IsZero())
+}
+
+func TestEpochMillis(t *testing.T) {
+	t.Parallel()
+
+	millis := int64(1704067200123)
+	assert.Equal(t, time.UnixMilli(millis).UTC(), epochMillis(&millis))
+	assert.True(t, epochMillis(nil).IsZero())
+}
@@ -0,0 +1,75 @@
+package inspector
+
+import (
+	"maps"
+	"time"
+)
+
+// AggregateResult is a snapshot of the results of the scans of an InspectorManager, returned
+// by Results. It is a deep copy, safe to read and modify while the manager keeps scanning.
+type AggregateResult struct {
+	// ByService holds the result of every resource type scanned, keyed by ResultKey: the
+	// resource type, prefixed by the account ID for the accounts scanned through AssumeRole
+	ByService map[string]*InspectResult `json:"by_service"`
+
+	// Errors are the failures of the last scan by resource type, account and region
+	Errors []ServiceError `json:"errors,omitempty"`
+
+	// StartedAt and FinishedAt bound the last scan, FinishedAt being zero while it runs
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// Results returns a snapshot of the scanning results. It may be called while Inspect runs,
+// and then holds the results of the inspectors completed so far. ByService is empty when a
+// result handler receives the results instead.
+func (sm *InspectorManager) Results() AggregateResult {
+	sm.resultsMu.RLock()
+	defer sm.resultsMu.RUnlock()
+
+	aggregate := AggregateResult{
+		ByService:  make(map[string]*InspectResult, len(sm.results)),
+		StartedAt:  sm.startedAt,
+		FinishedAt: sm.finishedAt,
+	}
+	for key, result := range sm.results {
+		aggregate.ByService[key] = result.clone()
+	}
+	if len(sm.serviceErrors) > 0 {
+		aggregate.Errors = append([]ServiceError(nil), sm.serviceErrors...)
+	}
+	return aggregate
+}
+
+// clone returns a deep copy of the result. The values of the Properties and the RawResponse
+// of the resources are shared, inspectors never modify them once the resource is returned.
+func (r *InspectResult) clone() *InspectResult {
+	if r == nil {
+		return nil
+	}
+
+	clone := *r
+	if r.Resources != nil {
+		clone.Resources = make([]ResourceMetadata, len(r.Resources))
+		for i, resource := range r.Resources {
+			clone.Resources[i] = resource.clone()
+		}
+	}
+	if r.ExcludedResources != nil {
+		clone.ExcludedResources = make([]ExcludedResource, len(r.ExcludedResources))
+		for i, excluded := range r.ExcludedResources {
+			excluded.Resource = excluded.Resource.clone()
+			clone.ExcludedResources[i] = excluded
+		}
+	}
+	clone.Errors = append([]string(nil), r.Errors...)
+	return &clone
+}
+
+// clone returns a copy of the resource sharing none of its maps and slices
+func (r ResourceMetadata) clone() ResourceMetadata {
+	r.Tags = maps.Clone(r.Tags)
+	r.Details.Properties = maps.Clone(r.Details.Properties)
+	r.Details.Compliance.Violations = append([]string(nil), r.Details.Compliance.Violations...)
+	return r
+}
@@ -0,0 +1,446 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/arn"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	apigatewayv2types "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+)
+
+// Protocols of the APIs reported in the protocol property of their metadata
+const (
+	APIGatewayProtocolREST      = "REST"
+	APIGatewayProtocolHTTP      = "HTTP"
+	APIGatewayProtocolWebSocket = "WEBSOCKET"
+)
+
+// Resource paths of the APIs in their ARNs, REST APIs being managed by the API Gateway API
+// and HTTP and WebSocket APIs by the API Gateway V2 API
+const (
+	apiGatewayRESTAPIsPath = "restapis"
+	apiGatewayAPIsPath     = "apis"
+)
+
+// APIGatewayClientCreator implements AWSClient for API Gateway
+type APIGatewayClientCreator struct{}
+
+func (c *APIGatewayClientCreator) CreateFromConfig(cfg *aws.Config) interface{} {
+	return apigateway.NewFromConfig(*cfg)
+}
+
+// APIGatewayV2ClientCreator implements AWSClient for API Gateway V2
+type APIGatewayV2ClientCreator struct{}
+
+func (c *APIGatewayV2ClientCreator) CreateFromConfig(cfg *aws.Config) interface{} {
+	return apigatewayv2.NewFromConfig(*cfg)
+}
+
+// GetAPIGatewayClient retrieves an API Gateway client, managing REST APIs, for the specified
+// AWS region
+func (m *AWSClientManager) GetAPIGatewayClient(region string) (*apigateway.Client, error) {
+	client, err := m.GetClient(region, &APIGatewayClientCreator{})
+	if err != nil {
+		return nil, err
+	}
+	return client.(*apigateway.Client), nil
+}
+
+// GetAPIGatewayV2Client retrieves an API Gateway V2 client, managing HTTP and WebSocket APIs,
+// for the specified AWS region
+func (m *AWSClientManager) GetAPIGatewayV2Client(region string) (*apigatewayv2.Client, error) {
+	client, err := m.GetClient(region, &APIGatewayV2ClientCreator{})
+	if err != nil {
+		return nil, err
+	}
+	return client.(*apigatewayv2.Client), nil
+}
+
+// APIGatewayAPI is the subset of the API Gateway client used to discover REST APIs
+type APIGatewayAPI interface {
+	apigateway.GetRestApisAPIClient
+	GetRestApi(ctx context.Context, params *apigateway.GetRestApiInput, optFns ...func(*apigateway.Options)) (*apigateway.GetRestApiOutput, error)
+	GetTags(ctx context.Context, params *apigateway.GetTagsInput, optFns ...func(*apigateway.Options)) (*apigateway.GetTagsOutput, error)
+}
+
+// APIGatewayV2API is the subset of the API Gateway V2 client used to discover HTTP and
+// WebSocket APIs
+type APIGatewayV2API interface {
+	GetApis(ctx context.Context, params *apigatewayv2.GetApisInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApisOutput, error)
+	GetApi(ctx context.Context, params *apigatewayv2.GetApiInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApiOutput, error)
+}
+
+// apiGatewayClientProvider returns the API Gateway and API Gateway V2 clients to use for a region
+type apiGatewayClientProvider func(region string) (APIGatewayAPI, APIGatewayV2API, error)
+
+// APIGatewayInspector implements the Inspector interface for API Gateway APIs. REST APIs and
+// HTTP and WebSocket APIs are reported under the same resource type, told apart by the
+// protocol property of their metadata.
+type APIGatewayInspector struct {
+	Regions       []string
+	ClientManager *AWSClientManager
+	Logger        *o11y.Logger
+}
+
+// NewAPIGatewayInspector creates a new inspector with AWS client management
+func NewAPIGatewayInspector(regions []string) (*APIGatewayInspector, error) {
+	// Create AWS client manager for the specified regions
+	clientManager, err := NewAWSRegionalClientManager(regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client manager: %w", err)
+	}
+
+	return &APIGatewayInspector{
+		Regions:       regions,
+		ClientManager: clientManager,
+		Logger:        o11y.DefaultLogger(),
+	}, nil
+}
+
+// Inspect discovers the REST, HTTP and WebSocket APIs and their tags across specified regions
+func (a *APIGatewayInspector) Inspect(ctx context.Context, config configuration.TaggyScanConfig) (*InspectResult, error) {
+	a.Logger.Info("Starting API Gateway resource scanning",
+		"regions", a.Regions)
+
+	result := &InspectResult{
+		StartTime: time.Now(),
+		Region:    a.Regions[0],
+	}
+
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := a.ClientManager.resolveAccountID(ctx, a.Logger)
+
+	discoverer, processor := a.newScanFuncs(a.regionalClients, accountID)
+
+	// Perform the async scan
+	resources, err := scanner.InspectResourcesAsync(ctx, a.Regions, discoverer, processor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan API Gateway resources: %w", err)
+	}
+
+	// Update result with scanned resources
+	result.Resources = resources
+	result.TotalResources = len(resources)
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+
+	a.Logger.Info("API Gateway scanning completed",
+		"total_resources", result.TotalResources,
+		"duration", result.Duration)
+
+	return result, nil
+}
+
+// regionalClients returns the API Gateway clients of a region from the client manager
+func (a *APIGatewayInspector) regionalClients(region string) (APIGatewayAPI, APIGatewayV2API, error) {
+	restClient, err := a.ClientManager.GetAPIGatewayClient(region)
+	if err != nil {
+		return nil, nil, err
+	}
+	v2Client, err := a.ClientManager.GetAPIGatewayV2Client(region)
+	if err != nil {
+		return nil, nil, err
+	}
+	return restClient, v2Client, nil
+}
+
+// Probe lists a single REST API and a single HTTP or WebSocket API of the region. Their tags
+// are part of the listing.
+func (a *APIGatewayInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return a.probe(ctx, region, a.regionalClients)
+}
+
+// probe issues the calls of Probe with the clients of clientFor
+func (a *APIGatewayInspector) probe(ctx context.Context, region string, clientFor apiGatewayClientProvider) error {
+	restClient, v2Client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get API Gateway clients: %w", err)
+	}
+
+	// Reading any API Gateway resource takes the apigateway:GET action
+	if _, err := restClient.GetRestApis(ctx, &apigateway.GetRestApisInput{Limit: aws.Int32(1)}); err != nil {
+		return probeCall("apigateway:GET", err)
+	}
+	_, err = v2Client.GetApis(ctx, &apigatewayv2.GetApisInput{MaxResults: aws.String("1")})
+	return probeCall("apigateway:GET", err)
+}
+
+// newScanFuncs returns the discoverer listing the APIs of a region, and the processor
+// building their metadata. Both listings return the tags of the APIs, so the processor makes
+// no further call.
+func (a *APIGatewayInspector) newScanFuncs(clientFor apiGatewayClientProvider, accountID string) (ResourceDiscoverer, ResourceProcessor) {
+	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
+		restClient, v2Client, err := clientFor(region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get API Gateway clients: %w", err)
+		}
+
+		restAPIs, err := a.listRestAPIs(ctx, restClient, region)
+		if err != nil {
+			return nil, err
+		}
+		apis, err := a.listAPIs(ctx, v2Client, region)
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]interface{}, 0, len(restAPIs)+len(apis))
+		for _, api := range append(restAPIs, apis...) {
+			resources = append(resources, RegionalResource{Region: region, Item: api})
+		}
+		return resources, nil
+	}
+
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		return newAPIGatewayMetadata(resource.(RegionalResource).Item.(apiGatewayAttributes), accountID), nil
+	}
+
+	return discoverer, processor
+}
+
+// listRestAPIs pages through the REST APIs of a region
+func (a *APIGatewayInspector) listRestAPIs(ctx context.Context, client APIGatewayAPI, region string) ([]apiGatewayAttributes, error) {
+	var apis []apiGatewayAttributes
+	paginator := apigateway.NewGetRestApisPaginator(client, &apigateway.GetRestApisInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list REST APIs: %w", err)
+		}
+		for _, api := range output.Items {
+			attributes := apiGatewayAttributes{
+				region:      region,
+				protocol:    APIGatewayProtocolREST,
+				id:          aws.ToString(api.Id),
+				name:        aws.ToString(api.Name),
+				description: aws.ToString(api.Description),
+				createdAt:   api.CreatedDate,
+				tags:        api.Tags,
+				raw:         api,
+			}
+			if api.EndpointConfiguration != nil {
+				for _, endpointType := range api.EndpointConfiguration.Types {
+					attributes.endpointTypes = append(attributes.endpointTypes, string(endpointType))
+				}
+			}
+			apis = append(apis, attributes)
+		}
+	}
+	return apis, nil
+}
+
+// listAPIs pages through the HTTP and WebSocket APIs of a region. API Gateway V2 has no
+// paginator, the pages are followed through their next token.
+func (a *APIGatewayInspector) listAPIs(ctx context.Context, client APIGatewayV2API, region string) ([]apiGatewayAttributes, error) {
+	var apis []apiGatewayAttributes
+	input := &apigatewayv2.GetApisInput{}
+	for {
+		output, err := client.GetApis(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list HTTP and WebSocket APIs: %w", err)
+		}
+		for _, api := range output.Items {
+			apis = append(apis, apiGatewayV2Attributes(api, region))
+		}
+
+		if aws.ToString(output.NextToken) == "" {
+			return apis, nil
+		}
+		input = &apigatewayv2.GetApisInput{NextToken: output.NextToken}
+	}
+}
+
+// getRestAPITags retrieves the tags of a REST API
+func (a *APIGatewayInspector) getRestAPITags(ctx context.Context, client APIGatewayAPI, apiARN string) (map[string]string, error) {
+	output, err := client.GetTags(ctx, &apigateway.GetTagsInput{
+		ResourceArn: aws.String(apiARN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get REST API tags: %w", err)
+	}
+
+	tags := make(map[string]string, len(output.Tags))
+	for key, value := range output.Tags {
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// Fetch implements the Inspector interface for retrieving a specific REST, HTTP or WebSocket API
+func (a *APIGatewayInspector) Fetch(ctx context.Context, arn string, config configuration.TaggyScanConfig) (*ResourceMetadata, error) {
+	return a.fetch(ctx, arn, a.regionalClients, a.ClientManager.resolveAccountID(ctx, a.Logger))
+}
+
+// fetch retrieves the API of an ARN with the clients of its region
+func (a *APIGatewayInspector) fetch(ctx context.Context, arn string, clientFor apiGatewayClientProvider, accountID string) (*ResourceMetadata, error) {
+	region, path, apiID, err := ParseAPIGatewayARN(arn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse API Gateway ARN: %w", err)
+	}
+
+	restClient, v2Client, err := clientFor(region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API Gateway clients: %w", err)
+	}
+
+	if path == apiGatewayAPIsPath {
+		output, err := v2Client.GetApi(ctx, &apigatewayv2.GetApiInput{ApiId: aws.String(apiID)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch API %s: %w", apiID, err)
+		}
+
+		metadata := newAPIGatewayMetadata(apiGatewayV2Attributes(apigatewayv2types.Api{
+			ApiId:        output.ApiId,
+			Name:         output.Name,
+			Description:  output.Description,
+			ProtocolType: output.ProtocolType,
+			ApiEndpoint:  output.ApiEndpoint,
+			CreatedDate:  output.CreatedDate,
+			Tags:         output.Tags,
+		}, region), accountID)
+		metadata.RawResponse = output
+		return &metadata, nil
+	}
+
+	output, err := restClient.GetRestApi(ctx, &apigateway.GetRestApiInput{RestApiId: aws.String(apiID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch REST API %s: %w", apiID, err)
+	}
+
+	attributes := apiGatewayAttributes{
+		region:      region,
+		protocol:    APIGatewayProtocolREST,
+		id:          apiID,
+		name:        aws.ToString(output.Name),
+		description: aws.ToString(output.Description),
+		createdAt:   output.CreatedDate,
+	}
+	if output.EndpointConfiguration != nil {
+		for _, endpointType := range output.EndpointConfiguration.Types {
+			attributes.endpointTypes = append(attributes.endpointTypes, string(endpointType))
+		}
+	}
+
+	attributes.tags, err = a.getRestAPITags(ctx, restClient, APIGatewayARN(region, APIGatewayProtocolREST, apiID))
+	if err != nil {
+		a.Logger.Warn("Failed to get REST API tags", "api_id", apiID, "error", err)
+		attributes.tags = make(map[string]string)
+	}
+
+	metadata := newAPIGatewayMetadata(attributes, accountID)
+	metadata.TagFetchError = tagFetchError(err)
+	metadata.RawResponse = output
+	return &metadata, nil
+}
+
+// apiGatewayAttributes are the attributes of a REST, HTTP or WebSocket API reported in its
+// metadata
+type apiGatewayAttributes struct {
+	region        string
+	protocol      string
+	id            string
+	name          string
+	description   string
+	endpoint      string
+	endpointTypes []string
+	createdAt     *time.Time
+	tags          map[string]string
+
+	// raw is the API as listed, reported as the raw response of the resource
+	raw interface{}
+}
+
+// apiGatewayV2Attributes returns the attributes of an HTTP or WebSocket API
+func apiGatewayV2Attributes(api apigatewayv2types.Api, region string) apiGatewayAttributes {
+	protocol := APIGatewayProtocolHTTP
+	if api.ProtocolType == apigatewayv2types.ProtocolTypeWebsocket {
+		protocol = APIGatewayProtocolWebSocket
+	}
+
+	return apiGatewayAttributes{
+		region:      region,
+		protocol:    protocol,
+		id:          aws.ToString(api.ApiId),
+		name:        aws.ToString(api.Name),
+		description: aws.ToString(api.Description),
+		endpoint:    aws.ToString(api.ApiEndpoint),
+		createdAt:   api.CreatedDate,
+		tags:        api.Tags,
+		raw:         api,
+	}
+}
+
+// newAPIGatewayMetadata builds the metadata of an API
+func newAPIGatewayMetadata(attributes apiGatewayAttributes, accountID string) ResourceMetadata {
+	tags := attributes.tags
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+
+	metadata := ResourceMetadata{
+		ID:           attributes.id,
+		Type:         constants.ResourceTypeAPIGateway,
+		Provider:     "aws",
+		AccountID:    accountID,
+		Region:       attributes.region,
+		DiscoveredAt: time.Now(),
+		CreatedAt:    aws.ToTime(attributes.createdAt),
+		Tags:         tags,
+		RawResponse:  attributes.raw,
+	}
+
+	metadata.Details.ARN = APIGatewayARN(attributes.region, attributes.protocol, attributes.id)
+	metadata.Details.Name = attributes.name
+	metadata.Details.Properties = map[string]interface{}{
+		"api_id":      attributes.id,
+		"protocol":    attributes.protocol,
+		"description": attributes.description,
+	}
+	if attributes.endpoint != "" {
+		metadata.Details.Properties["api_endpoint"] = attributes.endpoint
+	}
+	if len(attributes.endpointTypes) > 0 {
+		metadata.Details.Properties["endpoint_types"] = attributes.endpointTypes
+	}
+
+	return metadata
+}
+
+// APIGatewayARN returns the ARN of an API of the protocol, the REST APIs being addressed
+// under /restapis and the HTTP and WebSocket APIs under /apis
+func APIGatewayARN(region, protocol, apiID string) string {
+	path := apiGatewayAPIsPath
+	if protocol == APIGatewayProtocolREST {
+		path = apiGatewayRESTAPIsPath
+	}
+	return resourceARN("apigateway", region, true, "", fmt.Sprintf("/%s/%s", path, apiID))
+}
+
+// ParseAPIGatewayARN extracts the region, the resource path, "restapis" for REST APIs or
+// "apis" for HTTP and WebSocket APIs, and the API ID from an API Gateway API ARN
+func ParseAPIGatewayARN(resourceARN string) (string, string, string, error) {
+	// ARN formats: arn:partition:apigateway:region::/restapis/api-id
+	//              arn:partition:apigateway:region::/apis/api-id
+	parsed, err := arn.ParseRegionalService(resourceARN, "apigateway")
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid API Gateway ARN format: %w", err)
+	}
+
+	segments := strings.Split(strings.TrimPrefix(parsed.Resource, "/"), "/")
+	if !strings.HasPrefix(parsed.Resource, "/") || len(segments) != 2 || segments[1] == "" ||
+		(segments[0] != apiGatewayRESTAPIsPath && segments[0] != apiGatewayAPIsPath) {
+		return "", "", "", fmt.Errorf("invalid API Gateway API in ARN: %s", resourceARN)
+	}
+	return parsed.Region, segments[0], segments[1], nil
+}
@@ -0,0 +1,366 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	apigatewaytypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	apigatewayv2types "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// apiCreatedDate is the creation time of the mock APIs
+var apiCreatedDate = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// mockAPIGatewayClient serves restAPIs REST APIs pageSize items per page, and fails to read
+// the tags of the APIs whose ARN is listed in tagErrors
+type mockAPIGatewayClient struct {
+	restAPIs  int
+	pageSize  int
+	tagErrors map[string]bool
+
+	// tagARNs are the ARNs the tags were read for
+	tagARNs []string
+}
+
+func restAPIID(i int) string {
+	return fmt.Sprintf("rest%03d", i)
+}
+
+func (m *mockAPIGatewayClient) GetRestApis(ctx context.Context, params *apigateway.GetRestApisInput, optFns ...func(*apigateway.Options)) (*apigateway.GetRestApisOutput, error) {
+	start := 0
+	if params.Position != nil {
+		start, _ = strconv.Atoi(*params.Position)
+	}
+	end := min(start+m.pageSize, m.restAPIs)
+
+	output := &apigateway.GetRestApisOutput{}
+	if end < m.restAPIs {
+		output.Position = aws.String(strconv.Itoa(end))
+	}
+	for i := start; i < end; i++ {
+		output.Items = append(output.Items, apigatewaytypes.RestApi{
+			Id:          aws.String(restAPIID(i)),
+			Name:        aws.String(fmt.Sprintf("orders-%d", i)),
+			CreatedDate: aws.Time(apiCreatedDate),
+			Tags:        map[string]string{"Team": "orders"},
+			EndpointConfiguration: &apigatewaytypes.EndpointConfiguration{
+				Types: []apigatewaytypes.EndpointType{apigatewaytypes.EndpointTypeRegional},
+			},
+		})
+	}
+	return output, nil
+}
+
+func (m *mockAPIGatewayClient) GetRestApi(ctx context.Context, params *apigateway.GetRestApiInput, optFns ...func(*apigateway.Options)) (*apigateway.GetRestApiOutput, error) {
+	return &apigateway.GetRestApiOutput{
+		Id:          params.RestApiId,
+		Name:        aws.String("orders"),
+		CreatedDate: aws.Time(apiCreatedDate),
+	}, nil
+}
+
+func (m *mockAPIGatewayClient) GetTags(ctx context.Context, params *apigateway.GetTagsInput, optFns ...func(*apigateway.Options)) (*apigateway.GetTagsOutput, error) {
+	resourceARN := aws.ToString(params.ResourceArn)
+	m.tagARNs = append(m.tagARNs, resourceARN)
+	if m.tagErrors[resourceARN] {
+		return nil, errors.New("access denied")
+	}
+	return &apigateway.GetTagsOutput{Tags: map[string]string{"Team": "orders"}}, nil
+}
+
+// mockAPIGatewayV2Client serves the HTTP and WebSocket APIs pageSize items per page
+type mockAPIGatewayV2Client struct {
+	apis     []apigatewayv2types.Api
+	pageSize int
+
+	// pages counts the pages served
+	pages int
+}
+
+func (m *mockAPIGatewayV2Client) GetApis(ctx context.Context, params *apigatewayv2.GetApisInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApisOutput, error) {
+	m.pages++
+
+	start := 0
+	if params.NextToken != nil {
+		start, _ = strconv.Atoi(*params.NextToken)
+	}
+	end := min(start+m.pageSize, len(m.apis))
+
+	output := &apigatewayv2.GetApisOutput{Items: m.apis[start:end]}
+	if end < len(m.apis) {
+		output.NextToken = aws.String(strconv.Itoa(end))
+	}
+	return output, nil
+}
+
+func (m *mockAPIGatewayV2Client) GetApi(ctx context.Context, params *apigatewayv2.GetApiInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApiOutput, error) {
+	for _, api := range m.apis {
+		if aws.ToString(api.ApiId) == aws.ToString(params.ApiId) {
+			return &apigatewayv2.GetApiOutput{
+				ApiId:        api.ApiId,
+				Name:         api.Name,
+				ProtocolType: api.ProtocolType,
+				ApiEndpoint:  api.ApiEndpoint,
+				CreatedDate:  api.CreatedDate,
+				Tags:         api.Tags,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("API %s not found", aws.ToString(params.ApiId))
+}
+
+func newMockV2APIs() []apigatewayv2types.Api {
+	var apis []apigatewayv2types.Api
+	for i := 0; i < 5; i++ {
+		protocol := apigatewayv2types.ProtocolTypeHttp
+		if i%2 == 1 {
+			protocol = apigatewayv2types.ProtocolTypeWebsocket
+		}
+		id := fmt.Sprintf("api%03d", i)
+		apis = append(apis, apigatewayv2types.Api{
+			ApiId:        aws.String(id),
+			Name:         aws.String(fmt.Sprintf("billing-%d", i)),
+			ProtocolType: protocol,
+			ApiEndpoint:  aws.String(fmt.Sprintf("https://%s.execute-api.eu-west-1.amazonaws.com", id)),
+			CreatedDate:  aws.Time(apiCreatedDate),
+			Tags:         map[string]string{"Team": "billing"},
+		})
+	}
+	return apis
+}
+
+func newTestAPIGatewayInspector() *APIGatewayInspector {
+	return &APIGatewayInspector{
+		Regions: []string{"eu-west-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+}
+
+func TestAPIGatewayInspectorDiscoversRESTAndV2APIs(t *testing.T) {
+	t.Parallel()
+
+	restClient := &mockAPIGatewayClient{restAPIs: 7, pageSize: 3}
+	v2Client := &mockAPIGatewayV2Client{apis: newMockV2APIs(), pageSize: 2}
+	clientFor := func(region string) (APIGatewayAPI, APIGatewayV2API, error) {
+		return restClient, v2Client, nil
+	}
+
+	discoverer, processor := newTestAPIGatewayInspector().newScanFuncs(clientFor, "123456789012")
+
+	resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(context.Background(), []string{"eu-west-1"}, discoverer, processor)
+	require.NoError(t, err)
+	require.Len(t, resources, 12)
+	assert.Equal(t, 3, v2Client.pages)
+
+	protocols := make(map[string]int)
+	for _, resource := range resources {
+		protocol := resource.Details.Properties["protocol"].(string)
+		protocols[protocol]++
+
+		assert.Equal(t, constants.ResourceTypeAPIGateway, resource.Type)
+		assert.Equal(t, "eu-west-1", resource.Region)
+		assert.Equal(t, "123456789012", resource.AccountID)
+		assert.Equal(t, apiCreatedDate, resource.CreatedAt)
+		assert.Equal(t, resource.ID, resource.Details.Properties["api_id"])
+
+		if protocol == APIGatewayProtocolREST {
+			assert.Equal(t, "arn:aws:apigateway:eu-west-1::/restapis/"+resource.ID, resource.Details.ARN)
+			assert.Equal(t, []string{"REGIONAL"}, resource.Details.Properties["endpoint_types"])
+			assert.Equal(t, map[string]string{"Team": "orders"}, resource.Tags)
+			continue
+		}
+		assert.Equal(t, "arn:aws:apigateway:eu-west-1::/apis/"+resource.ID, resource.Details.ARN)
+		assert.Equal(t, fmt.Sprintf("https://%s.execute-api.eu-west-1.amazonaws.com", resource.ID), resource.Details.Properties["api_endpoint"])
+		assert.Equal(t, map[string]string{"Team": "billing"}, resource.Tags)
+	}
+
+	assert.Equal(t, map[string]int{
+		APIGatewayProtocolREST:      7,
+		APIGatewayProtocolHTTP:      3,
+		APIGatewayProtocolWebSocket: 2,
+	}, protocols)
+
+	// The tags of REST APIs are returned by the listing
+	assert.Empty(t, restClient.tagARNs)
+}
+
+func TestAPIGatewayInspectorFetch(t *testing.T) {
+	t.Parallel()
+
+	failingARN := "arn:aws:apigateway:eu-west-1::/restapis/denied"
+	restClient := &mockAPIGatewayClient{tagErrors: map[string]bool{failingARN: true}}
+	v2Client := &mockAPIGatewayV2Client{apis: newMockV2APIs(), pageSize: 2}
+	clientFor := func(region string) (APIGatewayAPI, APIGatewayV2API, error) {
+		if region != "eu-west-1" {
+			return nil, nil, fmt.Errorf("unexpected region %s", region)
+		}
+		return restClient, v2Client, nil
+	}
+	inspector := newTestAPIGatewayInspector()
+
+	t.Run("REST API", func(t *testing.T) {
+		arn := "arn:aws:apigateway:eu-west-1::/restapis/rest001"
+		metadata, err := inspector.fetch(context.Background(), arn, clientFor, "123456789012")
+		require.NoError(t, err)
+
+		assert.Equal(t, "rest001", metadata.ID)
+		assert.Equal(t, arn, metadata.Details.ARN)
+		assert.Equal(t, APIGatewayProtocolREST, metadata.Details.Properties["protocol"])
+		assert.Equal(t, map[string]string{"Team": "orders"}, metadata.Tags)
+		assert.Empty(t, metadata.TagFetchError)
+		assert.Contains(t, restClient.tagARNs, arn)
+	})
+
+	t.Run("REST API without readable tags", func(t *testing.T) {
+		metadata, err := inspector.fetch(context.Background(), failingARN, clientFor, "123456789012")
+		require.NoError(t, err)
+
+		assert.Empty(t, metadata.Tags)
+		assert.NotEmpty(t, metadata.TagFetchError)
+	})
+
+	t.Run("WebSocket API", func(t *testing.T) {
+		arn := "arn:aws:apigateway:eu-west-1::/apis/api001"
+		metadata, err := inspector.fetch(context.Background(), arn, clientFor, "123456789012")
+		require.NoError(t, err)
+
+		assert.Equal(t, "api001", metadata.ID)
+		assert.Equal(t, arn, metadata.Details.ARN)
+		assert.Equal(t, APIGatewayProtocolWebSocket, metadata.Details.Properties["protocol"])
+		assert.Equal(t, map[string]string{"Team": "billing"}, metadata.Tags)
+	})
+
+	t.Run("stage ARN", func(t *testing.T) {
+		_, err := inspector.fetch(context.Background(), "arn:aws:apigateway:eu-west-1::/restapis/rest001/stages/prod", clientFor, "123456789012")
+		assert.Error(t, err)
+	})
+}
+
+func TestParseAPIGatewayARN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		arn    string
+		region string
+		path   string
+		apiID  string
+		valid  bool
+	}{
+		{arn: "arn:aws:apigateway:us-east-1::/restapis/a1b2c3", region: "us-east-1", path: "restapis", apiID: "a1b2c3", valid: true},
+		{arn: "arn:aws:apigateway:eu-west-1::/apis/d4e5f6", region: "eu-west-1", path: "apis", apiID: "d4e5f6", valid: true},
+		{arn: "arn:aws:apigateway:us-east-1::/restapis/a1b2c3/stages/prod"},
+		{arn: "arn:aws:apigateway:us-east-1::/restapis/"},
+		{arn: "arn:aws:apigateway:us-east-1::/domainnames/example.com"},
+		{arn: "arn:aws:apigateway:::/restapis/a1b2c3"},
+		{arn: "arn:aws:execute-api:us-east-1:123456789012:a1b2c3/prod/GET/orders"},
+		{arn: "not-an-arn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arn, func(t *testing.T) {
+			t.Parallel()
+
+			region, path, apiID, err := ParseAPIGatewayARN(tt.arn)
+			if !tt.valid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.region, region)
+			assert.Equal(t, tt.path, path)
+			assert.Equal(t, tt.apiID, apiID)
+			assert.Equal(t, tt.arn, APIGatewayARN(region, map[string]string{"restapis": APIGatewayProtocolREST, "apis": APIGatewayProtocolHTTP}[path], apiID))
+		})
+	}
+}
+
+// probedAPIGatewayClient is a mockAPIGatewayClient recording the calls of a probe
+type probedAPIGatewayClient struct {
+	*mockAPIGatewayClient
+	calls *probeCalls
+}
+
+func (c *probedAPIGatewayClient) GetRestApis(ctx context.Context, params *apigateway.GetRestApisInput, optFns ...func(*apigateway.Options)) (*apigateway.GetRestApisOutput, error) {
+	if err := c.calls.call("GetRestApis"); err != nil {
+		return nil, err
+	}
+	return c.mockAPIGatewayClient.GetRestApis(ctx, params, optFns...)
+}
+
+// probedAPIGatewayV2Client is a mockAPIGatewayV2Client recording the calls of a probe
+type probedAPIGatewayV2Client struct {
+	*mockAPIGatewayV2Client
+	calls *probeCalls
+}
+
+func (c *probedAPIGatewayV2Client) GetApis(ctx context.Context, params *apigatewayv2.GetApisInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApisOutput, error) {
+	if err := c.calls.call("GetApis"); err != nil {
+		return nil, err
+	}
+	return c.mockAPIGatewayV2Client.GetApis(ctx, params, optFns...)
+}
+
+func TestAPIGatewayInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &APIGatewayInspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	// Both calls take the apigateway:GET action
+	tests := []struct {
+		name           string
+		denied         string
+		expectedAction string
+		expectedCalls  []string
+	}{
+		{
+			name:          "Success",
+			expectedCalls: []string{"GetRestApis", "GetApis"},
+		},
+		{
+			name:           "REST APIs Denied",
+			denied:         "GetRestApis",
+			expectedAction: "apigateway:GET",
+			expectedCalls:  []string{"GetRestApis"},
+		},
+		{
+			name:           "V2 APIs Denied",
+			denied:         "GetApis",
+			expectedAction: "apigateway:GET",
+			expectedCalls:  []string{"GetRestApis", "GetApis"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := &probeCalls{denied: tt.denied}
+			restClient := &mockAPIGatewayClient{restAPIs: 3, pageSize: 1}
+			v2Client := &mockAPIGatewayV2Client{apis: newMockV2APIs(), pageSize: 1}
+			clientFor := func(region string) (APIGatewayAPI, APIGatewayV2API, error) {
+				return &probedAPIGatewayClient{mockAPIGatewayClient: restClient, calls: calls},
+					&probedAPIGatewayV2Client{mockAPIGatewayV2Client: v2Client, calls: calls}, nil
+			}
+
+			err := inspector.probe(context.Background(), "us-east-1", clientFor)
+			assertProbeError(t, err, tt.expectedAction)
+			assert.Equal(t, tt.expectedCalls, calls.made)
+		})
+	}
+}
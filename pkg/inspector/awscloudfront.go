@@ -0,0 +1,355 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/arn"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+// CloudFrontClientCreator implements AWSClient for CloudFront
+type CloudFrontClientCreator struct{}
+
+func (c *CloudFrontClientCreator) CreateFromConfig(cfg *aws.Config) interface{} {
+	return cloudfront.NewFromConfig(*cfg)
+}
+
+// GetCloudFrontClient retrieves a CloudFront client for the specified AWS region. CloudFront
+// is a global service, served by the endpoint of constants.GlobalServiceRegion.
+func (m *AWSClientManager) GetCloudFrontClient(region string) (*cloudfront.Client, error) {
+	client, err := m.GetClient(region, &CloudFrontClientCreator{})
+	if err != nil {
+		return nil, err
+	}
+	return client.(*cloudfront.Client), nil
+}
+
+// CloudFrontAPI is the subset of the CloudFront client used to discover distributions
+type CloudFrontAPI interface {
+	cloudfront.ListDistributionsAPIClient
+	ListTagsForResource(ctx context.Context, params *cloudfront.ListTagsForResourceInput, optFns ...func(*cloudfront.Options)) (*cloudfront.ListTagsForResourceOutput, error)
+}
+
+// cloudFrontClientProvider returns the CloudFront client to use for a region
+type cloudFrontClientProvider func(region string) (CloudFrontAPI, error)
+
+// CloudFrontInspector implements the Inspector interface for CloudFront distributions. As
+// CloudFront is a global service, distributions are listed once through the endpoint of
+// constants.GlobalServiceRegion and reported in the constants.GlobalRegion region.
+type CloudFrontInspector struct {
+	Regions       []string
+	ClientManager *AWSClientManager
+	Logger        *o11y.Logger
+}
+
+// NewCloudFrontInspector creates a new CloudFrontInspector with AWS client management. The
+// regions are ignored, distributions being listed through the global endpoint.
+func NewCloudFrontInspector(regions []string) (*CloudFrontInspector, error) {
+	globalRegions := []string{constants.GlobalServiceRegion}
+	clientManager, err := NewAWSRegionalClientManager(globalRegions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client manager: %w", err)
+	}
+
+	return &CloudFrontInspector{
+		Regions:       globalRegions,
+		ClientManager: clientManager,
+		Logger:        o11y.DefaultLogger(),
+	}, nil
+}
+
+// Inspect discovers CloudFront distributions and their tags
+func (c *CloudFrontInspector) Inspect(ctx context.Context, config configuration.TaggyScanConfig) (*InspectResult, error) {
+	c.Logger.Info("Starting CloudFront resource scanning")
+
+	result := &InspectResult{
+		StartTime: time.Now(),
+		Region:    constants.GlobalRegion,
+	}
+
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := c.ClientManager.resolveAccountID(ctx, c.Logger)
+
+	discoverer, processor := c.newScanFuncs(c.regionalClient, accountID)
+
+	// Distributions are listed once, through the endpoint of the global service
+	resources, err := scanner.InspectResourcesAsync(ctx, []string{constants.GlobalServiceRegion}, discoverer, processor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan CloudFront resources: %w", err)
+	}
+
+	// Update result with scanned resources
+	result.Resources = resources
+	result.TotalResources = len(resources)
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+
+	c.Logger.Info("CloudFront scanning completed",
+		"total_resources", result.TotalResources,
+		"duration", result.Duration)
+
+	return result, nil
+}
+
+// regionalClient returns the CloudFront client of a region from the client manager
+func (c *CloudFrontInspector) regionalClient(region string) (CloudFrontAPI, error) {
+	client, err := c.ClientManager.GetCloudFrontClient(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe lists a single distribution of the account, then reads its tags
+func (c *CloudFrontInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return c.probe(ctx, region, c.regionalClient)
+}
+
+// probe issues the calls of Probe with the clients of clientFor
+func (c *CloudFrontInspector) probe(ctx context.Context, region string, clientFor cloudFrontClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get CloudFront client: %w", err)
+	}
+
+	output, err := client.ListDistributions(ctx, &cloudfront.ListDistributionsInput{MaxItems: aws.Int32(1)})
+	if err != nil {
+		return probeCall("cloudfront:ListDistributions", err)
+	}
+	if output.DistributionList == nil || len(output.DistributionList.Items) == 0 {
+		return nil
+	}
+
+	_, err = client.ListTagsForResource(ctx, &cloudfront.ListTagsForResourceInput{
+		Resource: output.DistributionList.Items[0].ARN,
+	})
+	return probeCall("cloudfront:ListTagsForResource", err)
+}
+
+// newScanFuncs returns the discoverer listing the distributions of the account, and the
+// processor reading their tags
+func (c *CloudFrontInspector) newScanFuncs(clientFor cloudFrontClientProvider, accountID string) (ResourceDiscoverer, ResourceProcessor) {
+	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
+		client, err := clientFor(region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get CloudFront client: %w", err)
+		}
+
+		distributions, err := c.listDistributions(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]interface{}, len(distributions))
+		for i, distribution := range distributions {
+			resources[i] = distribution
+		}
+		return resources, nil
+	}
+
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		distribution := resource.(types.DistributionSummary)
+
+		client, err := clientFor(constants.GlobalServiceRegion)
+		if err != nil {
+			return ResourceMetadata{}, fmt.Errorf("failed to get CloudFront client: %w", err)
+		}
+
+		attributes := distributionAttributes{
+			arn:          aws.ToString(distribution.ARN),
+			id:           aws.ToString(distribution.Id),
+			domainName:   aws.ToString(distribution.DomainName),
+			status:       aws.ToString(distribution.Status),
+			enabled:      aws.ToBool(distribution.Enabled),
+			aliases:      distribution.Aliases,
+			priceClass:   distribution.PriceClass,
+			lastModified: distribution.LastModifiedTime,
+		}
+
+		// Tags recorded by an incremental scan are reused while the distribution is unchanged
+		tags, fromSnapshot := previousTags(ctx, attributes.arn, attributes.changeMarker())
+		if !fromSnapshot {
+			tags, err = c.getDistributionTags(ctx, client, attributes.arn)
+			if err != nil {
+				c.Logger.Warn("Failed to get distribution tags",
+					"distribution_id", attributes.id,
+					"error", err)
+				tags = make(map[string]string)
+			}
+		}
+
+		metadata := newDistributionMetadata(attributes, accountID, tags)
+		metadata.TagFetchError = tagFetchError(err)
+		metadata.FromSnapshot = fromSnapshot
+		metadata.RawResponse = distribution
+		return metadata, nil
+	}
+
+	return discoverer, processor
+}
+
+// listDistributions pages through the CloudFront distributions of the account
+func (c *CloudFrontInspector) listDistributions(ctx context.Context, client CloudFrontAPI) ([]types.DistributionSummary, error) {
+	var distributions []types.DistributionSummary
+	paginator := cloudfront.NewListDistributionsPaginator(client, &cloudfront.ListDistributionsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list distributions: %w", err)
+		}
+		if output.DistributionList != nil {
+			distributions = append(distributions, output.DistributionList.Items...)
+		}
+	}
+	return distributions, nil
+}
+
+// getDistributionTags retrieves the tags of a distribution
+func (c *CloudFrontInspector) getDistributionTags(ctx context.Context, client CloudFrontAPI, distributionARN string) (map[string]string, error) {
+	output, err := client.ListTagsForResource(ctx, &cloudfront.ListTagsForResourceInput{
+		Resource: aws.String(distributionARN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get distribution tags: %w", err)
+	}
+
+	tags := make(map[string]string)
+	if output.Tags != nil {
+		for _, tag := range output.Tags.Items {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+	return tags, nil
+}
+
+// Fetch implements the Inspector interface for retrieving a specific distribution
+func (c *CloudFrontInspector) Fetch(ctx context.Context, arn string, config configuration.TaggyScanConfig) (*ResourceMetadata, error) {
+	distributionID, err := ParseCloudFrontARN(arn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CloudFront ARN: %w", err)
+	}
+
+	client, err := c.ClientManager.GetCloudFrontClient(constants.GlobalServiceRegion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CloudFront client: %w", err)
+	}
+
+	output, err := client.GetDistribution(ctx, &cloudfront.GetDistributionInput{
+		Id: aws.String(distributionID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch CloudFront distribution: %w", err)
+	}
+	if output.Distribution == nil {
+		return nil, fmt.Errorf("no distribution found with ID %s", distributionID)
+	}
+
+	distribution := output.Distribution
+	attributes := distributionAttributes{
+		arn:        arn,
+		id:         distributionID,
+		domainName: aws.ToString(distribution.DomainName),
+		status:     aws.ToString(distribution.Status),
+
+		lastModified: distribution.LastModifiedTime,
+	}
+	if distribution.DistributionConfig != nil {
+		attributes.enabled = aws.ToBool(distribution.DistributionConfig.Enabled)
+		attributes.aliases = distribution.DistributionConfig.Aliases
+		attributes.priceClass = distribution.DistributionConfig.PriceClass
+	}
+
+	tags, err := c.getDistributionTags(ctx, client, arn)
+	if err != nil {
+		c.Logger.Warn("Failed to get distribution tags", "distribution_id", distributionID, "error", err)
+		tags = make(map[string]string)
+	}
+
+	metadata := newDistributionMetadata(attributes, c.ClientManager.resolveAccountID(ctx, c.Logger), tags)
+	metadata.TagFetchError = tagFetchError(err)
+	metadata.RawResponse = distribution
+	return &metadata, nil
+}
+
+// distributionAttributes are the attributes of a distribution reported in its metadata,
+// read from a distribution summary when scanning and from the distribution when fetching
+type distributionAttributes struct {
+	arn        string
+	id         string
+	domainName string
+	status     string
+	enabled    bool
+	aliases    *types.Aliases
+	priceClass types.PriceClass
+
+	// lastModified is when the configuration of the distribution last changed
+	lastModified *time.Time
+}
+
+// changeMarker returns the change marker of the distribution, its last modification time
+func (a distributionAttributes) changeMarker() string {
+	if a.lastModified == nil {
+		return ""
+	}
+	return a.lastModified.UTC().Format(time.RFC3339Nano)
+}
+
+// newDistributionMetadata builds the metadata of a distribution
+func newDistributionMetadata(attributes distributionAttributes, accountID string, tags map[string]string) ResourceMetadata {
+	var aliases []string
+	if attributes.aliases != nil {
+		aliases = attributes.aliases.Items
+	}
+
+	metadata := ResourceMetadata{
+		ID:           attributes.id,
+		Type:         constants.ResourceTypeCloudfront,
+		Provider:     "aws",
+		AccountID:    accountID,
+		Region:       constants.GlobalRegion,
+		DiscoveredAt: time.Now(),
+		Tags:         tags,
+		ChangeMarker: attributes.changeMarker(),
+	}
+
+	metadata.Details.ARN = attributes.arn
+	metadata.Details.Name = attributes.domainName
+	if len(aliases) > 0 {
+		metadata.Details.Name = aliases[0]
+	}
+	metadata.Details.Status = attributes.status
+	metadata.Details.Properties = map[string]interface{}{
+		"domain_name": attributes.domainName,
+		"status":      attributes.status,
+		"enabled":     attributes.enabled,
+		"aliases":     aliases,
+		"price_class": string(attributes.priceClass),
+	}
+
+	return metadata
+}
+
+// ParseCloudFrontARN extracts the distribution ID from a CloudFront distribution ARN
+func ParseCloudFrontARN(resourceARN string) (string, error) {
+	// ARN format: arn:partition:cloudfront::account-id:distribution/distribution-id
+	parsed, err := arn.ParseService(resourceARN, "cloudfront")
+	if err != nil {
+		return "", fmt.Errorf("invalid CloudFront ARN format: %w", err)
+	}
+
+	distributionID, ok := parsed.ResourceName("distribution")
+	if !ok {
+		return "", fmt.Errorf("invalid CloudFront distribution in ARN: %s", resourceARN)
+	}
+	return distributionID, nil
+}
@@ -0,0 +1,282 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// distributionLastModified is the last modification time of the mock distributions
+var distributionLastModified = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// mockCloudFrontClient serves distributions pageSize items per page, every odd one with an
+// alias, and fails to read the tags of the distributions listed in tagErrors
+type mockCloudFrontClient struct {
+	distributions int
+	pageSize      int
+	tagErrors     map[string]bool
+	tagCalls      atomic.Int64
+}
+
+func (m *mockCloudFrontClient) ListDistributions(ctx context.Context, params *cloudfront.ListDistributionsInput, optFns ...func(*cloudfront.Options)) (*cloudfront.ListDistributionsOutput, error) {
+	start := 0
+	if params.Marker != nil {
+		start, _ = strconv.Atoi(*params.Marker)
+	}
+	end := min(start+m.pageSize, m.distributions)
+
+	list := &types.DistributionList{IsTruncated: aws.Bool(end < m.distributions)}
+	if end < m.distributions {
+		list.NextMarker = aws.String(strconv.Itoa(end))
+	}
+	for i := start; i < end; i++ {
+		id := fmt.Sprintf("E%03d", i)
+		distribution := types.DistributionSummary{
+			Id:         aws.String(id),
+			ARN:        aws.String("arn:aws:cloudfront::123456789012:distribution/" + id),
+			DomainName: aws.String(fmt.Sprintf("d%03d.cloudfront.net", i)),
+			Status:     aws.String("Deployed"),
+			Enabled:    aws.Bool(true),
+			PriceClass: types.PriceClassPriceClassAll,
+			Aliases:    &types.Aliases{Quantity: aws.Int32(0)},
+
+			LastModifiedTime: aws.Time(distributionLastModified),
+		}
+		if i%2 == 1 {
+			distribution.Aliases = &types.Aliases{Quantity: aws.Int32(1), Items: []string{fmt.Sprintf("cdn%d.example.com", i)}}
+		}
+		list.Items = append(list.Items, distribution)
+	}
+	return &cloudfront.ListDistributionsOutput{DistributionList: list}, nil
+}
+
+func (m *mockCloudFrontClient) ListTagsForResource(ctx context.Context, params *cloudfront.ListTagsForResourceInput, optFns ...func(*cloudfront.Options)) (*cloudfront.ListTagsForResourceOutput, error) {
+	m.tagCalls.Add(1)
+	if m.tagErrors[aws.ToString(params.Resource)] {
+		return nil, errors.New("access denied")
+	}
+	return &cloudfront.ListTagsForResourceOutput{
+		Tags: &types.Tags{Items: []types.Tag{{Key: aws.String("Environment"), Value: aws.String("production")}}},
+	}, nil
+}
+
+func TestCloudFrontInspectorDiscoversDistributions(t *testing.T) {
+	t.Parallel()
+
+	failingARN := "arn:aws:cloudfront::123456789012:distribution/E002"
+	clientFor := func(region string) (CloudFrontAPI, error) {
+		if region != constants.GlobalServiceRegion {
+			return nil, fmt.Errorf("unexpected region %s", region)
+		}
+		return &mockCloudFrontClient{distributions: 7, pageSize: 3, tagErrors: map[string]bool{failingARN: true}}, nil
+	}
+
+	inspector := &CloudFrontInspector{
+		Regions: []string{constants.GlobalServiceRegion},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	discoverer, processor := inspector.newScanFuncs(clientFor, "123456789012")
+
+	resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(context.Background(), []string{constants.GlobalServiceRegion}, discoverer, processor)
+	require.NoError(t, err)
+	require.Len(t, resources, 7)
+
+	for _, resource := range resources {
+		assert.Equal(t, constants.ResourceTypeCloudfront, resource.Type)
+		assert.Equal(t, constants.GlobalRegion, resource.Region)
+		assert.Equal(t, "123456789012", resource.AccountID)
+		assert.Equal(t, "arn:aws:cloudfront::123456789012:distribution/"+resource.ID, resource.Details.ARN)
+		assert.Equal(t, "Deployed", resource.Details.Properties["status"])
+		assert.Equal(t, "PriceClass_All", resource.Details.Properties["price_class"])
+
+		aliases := resource.Details.Properties["aliases"].([]string)
+		if len(aliases) > 0 {
+			assert.Equal(t, aliases[0], resource.Details.Name)
+		} else {
+			assert.Equal(t, resource.Details.Properties["domain_name"], resource.Details.Name)
+		}
+
+		if resource.Details.ARN == failingARN {
+			assert.NotEmpty(t, resource.TagFetchError)
+			assert.Empty(t, resource.Tags)
+			continue
+		}
+		assert.Empty(t, resource.TagFetchError)
+		assert.Equal(t, map[string]string{"Environment": "production"}, resource.Tags)
+	}
+}
+
+func TestCloudFrontInspectorReusesTagsOfUnchangedDistributions(t *testing.T) {
+	t.Parallel()
+
+	client := &mockCloudFrontClient{distributions: 3, pageSize: 3}
+	clientFor := func(region string) (CloudFrontAPI, error) {
+		return client, nil
+	}
+
+	inspector := &CloudFrontInspector{
+		Regions: []string{constants.GlobalServiceRegion},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	discoverer, processor := inspector.newScanFuncs(clientFor, "123456789012")
+
+	// E000 is unchanged since the previous run, E001 was modified since and E002 is new
+	previous := NewPreviousScan([]PreviousResource{
+		{
+			ARN:          "arn:aws:cloudfront::123456789012:distribution/E000",
+			Tags:         map[string]string{"Owner": "cdn"},
+			ChangeMarker: distributionLastModified.Format(time.RFC3339Nano),
+		},
+		{
+			ARN:          "arn:aws:cloudfront::123456789012:distribution/E001",
+			Tags:         map[string]string{"Owner": "cdn"},
+			ChangeMarker: distributionLastModified.Add(-time.Hour).Format(time.RFC3339Nano),
+		},
+	})
+	ctx := WithScanControls(context.Background(), ScanControls{Previous: previous})
+
+	resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(ctx, []string{constants.GlobalServiceRegion}, discoverer, processor)
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	for _, resource := range resources {
+		assert.Equal(t, distributionLastModified.Format(time.RFC3339Nano), resource.ChangeMarker)
+		if resource.ID == "E000" {
+			assert.True(t, resource.FromSnapshot)
+			assert.Equal(t, map[string]string{"Owner": "cdn"}, resource.Tags)
+			continue
+		}
+		assert.False(t, resource.FromSnapshot, resource.ID)
+		assert.Equal(t, map[string]string{"Environment": "production"}, resource.Tags)
+	}
+	assert.Equal(t, int64(2), client.tagCalls.Load())
+}
+
+func TestIsGlobalResourceType(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsGlobalResourceType(constants.ResourceTypeCloudfront))
+	assert.True(t, IsGlobalResourceType(constants.ResourceTypeRoute53))
+	assert.False(t, IsGlobalResourceType(constants.ResourceTypeS3))
+	assert.False(t, IsGlobalResourceType("unknown"))
+}
+
+func TestParseCloudFrontARN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		arn    string
+		id     string
+		errMsg string
+	}{
+		{arn: "arn:aws:cloudfront::123456789012:distribution/E2QWRUHEXAMPLE", id: "E2QWRUHEXAMPLE"},
+		{arn: "arn:aws:cloudfront::123456789012:origin-access-identity/E2QWRUHEXAMPLE", errMsg: "invalid CloudFront distribution"},
+		{arn: "arn:aws:cloudfront::123456789012:distribution/", errMsg: "invalid CloudFront distribution"},
+		{arn: "arn:aws:s3:::distribution/E2QWRUHEXAMPLE", errMsg: "invalid CloudFront ARN format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arn, func(t *testing.T) {
+			t.Parallel()
+
+			id, err := ParseCloudFrontARN(tt.arn)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.id, id)
+		})
+	}
+}
+
+// probedCloudFrontClient is a mockCloudFrontClient recording the calls of a probe
+type probedCloudFrontClient struct {
+	*mockCloudFrontClient
+	calls *probeCalls
+}
+
+func (c *probedCloudFrontClient) ListDistributions(ctx context.Context, params *cloudfront.ListDistributionsInput, optFns ...func(*cloudfront.Options)) (*cloudfront.ListDistributionsOutput, error) {
+	if err := c.calls.call("cloudfront:ListDistributions"); err != nil {
+		return nil, err
+	}
+	return c.mockCloudFrontClient.ListDistributions(ctx, params, optFns...)
+}
+
+func (c *probedCloudFrontClient) ListTagsForResource(ctx context.Context, params *cloudfront.ListTagsForResourceInput, optFns ...func(*cloudfront.Options)) (*cloudfront.ListTagsForResourceOutput, error) {
+	if err := c.calls.call("cloudfront:ListTagsForResource"); err != nil {
+		return nil, err
+	}
+	return c.mockCloudFrontClient.ListTagsForResource(ctx, params, optFns...)
+}
+
+func TestCloudFrontInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &CloudFrontInspector{
+		Regions: []string{constants.GlobalServiceRegion},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	tests := []struct {
+		name          string
+		distributions int
+		denied        string
+		expectedCalls []string
+	}{
+		{
+			name:          "Success",
+			distributions: 3,
+			expectedCalls: []string{"cloudfront:ListDistributions", "cloudfront:ListTagsForResource"},
+		},
+		{
+			name:          "No Distribution",
+			expectedCalls: []string{"cloudfront:ListDistributions"},
+		},
+		{
+			name:          "List Denied",
+			distributions: 3,
+			denied:        "cloudfront:ListDistributions",
+			expectedCalls: []string{"cloudfront:ListDistributions"},
+		},
+		{
+			name:          "Tags Denied",
+			distributions: 3,
+			denied:        "cloudfront:ListTagsForResource",
+			expectedCalls: []string{"cloudfront:ListDistributions", "cloudfront:ListTagsForResource"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := &probeCalls{denied: tt.denied}
+			mock := &mockCloudFrontClient{distributions: tt.distributions, pageSize: 1}
+			clientFor := func(region string) (CloudFrontAPI, error) {
+				return &probedCloudFrontClient{mockCloudFrontClient: mock, calls: calls}, nil
+			}
+
+			err := inspector.probe(context.Background(), constants.GlobalServiceRegion, clientFor)
+			assertProbeError(t, err, tt.denied)
+			assert.Equal(t, tt.expectedCalls, calls.made)
+		})
+	}
+}
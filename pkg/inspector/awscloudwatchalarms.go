@@ -0,0 +1,380 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/arn"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// CloudWatchClientCreator implements AWSClient for CloudWatch
+type CloudWatchClientCreator struct{}
+
+func (c *CloudWatchClientCreator) CreateFromConfig(cfg *aws.Config) interface{} {
+	return cloudwatch.NewFromConfig(*cfg)
+}
+
+// GetCloudWatchClient retrieves a CloudWatch client for the specified AWS region
+func (m *AWSClientManager) GetCloudWatchClient(region string) (*cloudwatch.Client, error) {
+	client, err := m.GetClient(region, &CloudWatchClientCreator{})
+	if err != nil {
+		return nil, err
+	}
+	return client.(*cloudwatch.Client), nil
+}
+
+// CloudWatchAlarmsAPI is the subset of the CloudWatch client used by the CloudWatchAlarmsInspector
+type CloudWatchAlarmsAPI interface {
+	cloudwatch.DescribeAlarmsAPIClient
+	ListTagsForResource(ctx context.Context, params *cloudwatch.ListTagsForResourceInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListTagsForResourceOutput, error)
+}
+
+// cloudWatchAlarmsClientProvider returns the CloudWatch client to use for a region
+type cloudWatchAlarmsClientProvider func(region string) (CloudWatchAlarmsAPI, error)
+
+// alarmTypes are the types of alarms inspected, DescribeAlarms only listing metric alarms
+// when no type is given
+var alarmTypes = []types.AlarmType{types.AlarmTypeMetricAlarm, types.AlarmTypeCompositeAlarm}
+
+// CloudWatchAlarmsInspector implements the Inspector interface for CloudWatch metric and
+// composite alarms, configured under the cloudwatch resource type
+type CloudWatchAlarmsInspector struct {
+	Regions       []string
+	ClientManager *AWSClientManager
+	Logger        *o11y.Logger
+}
+
+// NewCloudWatchAlarmsInspector creates a new inspector with AWS client management
+func NewCloudWatchAlarmsInspector(regions []string) (*CloudWatchAlarmsInspector, error) {
+	// Create AWS client manager for the specified regions
+	clientManager, err := NewAWSRegionalClientManager(regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client manager: %w", err)
+	}
+
+	return &CloudWatchAlarmsInspector{
+		Regions:       regions,
+		ClientManager: clientManager,
+		Logger:        o11y.DefaultLogger(),
+	}, nil
+}
+
+// Inspect discovers CloudWatch alarms and their tags across specified regions
+func (c *CloudWatchAlarmsInspector) Inspect(ctx context.Context, config configuration.TaggyScanConfig) (*InspectResult, error) {
+	c.Logger.Info("Starting CloudWatch alarms resource scanning",
+		"regions", c.Regions)
+
+	result := &InspectResult{
+		StartTime: time.Now(),
+		Region:    c.Regions[0],
+	}
+
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := c.ClientManager.resolveAccountID(ctx, c.Logger)
+
+	discoverer, processor := c.newScanFuncs(c.regionalClient, accountID)
+
+	// Perform the async scan
+	resources, err := scanner.InspectResourcesAsync(ctx, c.Regions, discoverer, processor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan CloudWatch alarms resources: %w", err)
+	}
+
+	// Update result with scanned resources
+	result.Resources = resources
+	result.TotalResources = len(resources)
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+
+	c.Logger.Info("CloudWatch alarms scanning completed",
+		"total_resources", result.TotalResources,
+		"duration", result.Duration)
+
+	return result, nil
+}
+
+// regionalClient returns the CloudWatch client of a region from the client manager
+func (c *CloudWatchAlarmsInspector) regionalClient(region string) (CloudWatchAlarmsAPI, error) {
+	client, err := c.ClientManager.GetCloudWatchClient(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe describes a single alarm of the region, then reads its tags
+func (c *CloudWatchAlarmsInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return c.probe(ctx, region, c.regionalClient)
+}
+
+// probe issues the calls of Probe with the clients of clientFor
+func (c *CloudWatchAlarmsInspector) probe(ctx context.Context, region string, clientFor cloudWatchAlarmsClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get CloudWatch client: %w", err)
+	}
+
+	output, err := client.DescribeAlarms(ctx, &cloudwatch.DescribeAlarmsInput{
+		AlarmTypes: alarmTypes,
+		MaxRecords: aws.Int32(1),
+	})
+	if err != nil {
+		return probeCall("cloudwatch:DescribeAlarms", err)
+	}
+
+	var alarm alarmAttributes
+	switch {
+	case len(output.MetricAlarms) > 0:
+		alarm = metricAlarmAttributes(output.MetricAlarms[0])
+	case len(output.CompositeAlarms) > 0:
+		alarm = compositeAlarmAttributes(output.CompositeAlarms[0])
+	default:
+		return nil
+	}
+	_, err = client.ListTagsForResource(ctx, &cloudwatch.ListTagsForResourceInput{ResourceARN: aws.String(alarm.arn)})
+	return probeCall("cloudwatch:ListTagsForResource", err)
+}
+
+// newScanFuncs returns the discoverer listing the alarms of a region, and the processor
+// reading their tags
+func (c *CloudWatchAlarmsInspector) newScanFuncs(clientFor cloudWatchAlarmsClientProvider, accountID string) (ResourceDiscoverer, ResourceProcessor) {
+	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
+		client, err := clientFor(region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get CloudWatch client: %w", err)
+		}
+
+		alarms, err := c.listAlarms(ctx, client, &cloudwatch.DescribeAlarmsInput{AlarmTypes: alarmTypes})
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]interface{}, len(alarms))
+		for i, alarm := range alarms {
+			resources[i] = RegionalResource{Region: region, Item: alarm}
+		}
+		return resources, nil
+	}
+
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		regional := resource.(RegionalResource)
+		attributes := regional.Item.(alarmAttributes)
+
+		client, err := clientFor(regional.Region)
+		if err != nil {
+			return ResourceMetadata{}, fmt.Errorf("failed to get CloudWatch client: %w", err)
+		}
+
+		// Tags recorded by an incremental scan are reused while the alarm is unchanged
+		tags, fromSnapshot := previousTags(ctx, attributes.arn, attributes.changeMarker())
+		if !fromSnapshot {
+			tags, err = c.getAlarmTags(ctx, client, attributes.arn)
+			if err != nil {
+				c.Logger.Warn("Failed to get alarm tags",
+					"alarm_name", attributes.name,
+					"error", err)
+				tags = make(map[string]string)
+			}
+		}
+
+		metadata := newAlarmMetadata(attributes, regional.Region, accountID, tags)
+		metadata.TagFetchError = tagFetchError(err)
+		metadata.FromSnapshot = fromSnapshot
+		return metadata, nil
+	}
+
+	return discoverer, processor
+}
+
+// listAlarms pages through the metric and composite alarms matching the input
+func (c *CloudWatchAlarmsInspector) listAlarms(ctx context.Context, client CloudWatchAlarmsAPI, input *cloudwatch.DescribeAlarmsInput) ([]alarmAttributes, error) {
+	var alarms []alarmAttributes
+	paginator := cloudwatch.NewDescribeAlarmsPaginator(client, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list alarms: %w", err)
+		}
+		for _, alarm := range output.MetricAlarms {
+			alarms = append(alarms, metricAlarmAttributes(alarm))
+		}
+		for _, alarm := range output.CompositeAlarms {
+			alarms = append(alarms, compositeAlarmAttributes(alarm))
+		}
+	}
+	return alarms, nil
+}
+
+// getAlarmTags retrieves the tags of an alarm
+func (c *CloudWatchAlarmsInspector) getAlarmTags(ctx context.Context, client CloudWatchAlarmsAPI, alarmARN string) (map[string]string, error) {
+	output, err := client.ListTagsForResource(ctx, &cloudwatch.ListTagsForResourceInput{
+		ResourceARN: aws.String(alarmARN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alarm tags: %w", err)
+	}
+
+	tags := make(map[string]string)
+	for _, tag := range output.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
+// Fetch implements the Inspector interface for retrieving a specific alarm
+func (c *CloudWatchAlarmsInspector) Fetch(ctx context.Context, arn string, config configuration.TaggyScanConfig) (*ResourceMetadata, error) {
+	return c.fetch(ctx, arn, c.regionalClient, c.ClientManager.resolveAccountID(ctx, c.Logger))
+}
+
+// fetch retrieves the alarm of an ARN with the client of its region
+func (c *CloudWatchAlarmsInspector) fetch(ctx context.Context, arn string, clientFor cloudWatchAlarmsClientProvider, accountID string) (*ResourceMetadata, error) {
+	alarmName, region, err := ParseCloudWatchAlarmARN(arn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CloudWatch alarm ARN: %w", err)
+	}
+
+	client, err := clientFor(region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CloudWatch client: %w", err)
+	}
+
+	alarms, err := c.listAlarms(ctx, client, &cloudwatch.DescribeAlarmsInput{
+		AlarmNames: []string{alarmName},
+		AlarmTypes: alarmTypes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch CloudWatch alarm: %w", err)
+	}
+	if len(alarms) == 0 {
+		return nil, fmt.Errorf("no alarm found with name %s", alarmName)
+	}
+
+	tags, err := c.getAlarmTags(ctx, client, alarms[0].arn)
+	if err != nil {
+		c.Logger.Warn("Failed to get alarm tags", "alarm_name", alarmName, "error", err)
+		tags = make(map[string]string)
+	}
+
+	metadata := newAlarmMetadata(alarms[0], region, accountID, tags)
+	metadata.TagFetchError = tagFetchError(err)
+	return &metadata, nil
+}
+
+// alarmAttributes are the attributes of a metric or composite alarm reported in its metadata
+type alarmAttributes struct {
+	arn            string
+	name           string
+	alarmType      types.AlarmType
+	description    string
+	state          types.StateValue
+	namespace      string
+	metricName     string
+	alarmRule      string
+	actionsEnabled bool
+
+	// configurationUpdated is when the configuration of the alarm last changed
+	configurationUpdated *time.Time
+
+	// raw is the alarm as described, reported as the raw response of the resource
+	raw interface{}
+}
+
+// metricAlarmAttributes returns the attributes of a metric alarm. Alarms on a metric math
+// expression watch no single metric and report no namespace or metric name.
+func metricAlarmAttributes(alarm types.MetricAlarm) alarmAttributes {
+	return alarmAttributes{
+		arn:                  aws.ToString(alarm.AlarmArn),
+		name:                 aws.ToString(alarm.AlarmName),
+		alarmType:            types.AlarmTypeMetricAlarm,
+		description:          aws.ToString(alarm.AlarmDescription),
+		state:                alarm.StateValue,
+		namespace:            aws.ToString(alarm.Namespace),
+		metricName:           aws.ToString(alarm.MetricName),
+		actionsEnabled:       aws.ToBool(alarm.ActionsEnabled),
+		configurationUpdated: alarm.AlarmConfigurationUpdatedTimestamp,
+		raw:                  alarm,
+	}
+}
+
+// compositeAlarmAttributes returns the attributes of a composite alarm
+func compositeAlarmAttributes(alarm types.CompositeAlarm) alarmAttributes {
+	return alarmAttributes{
+		arn:                  aws.ToString(alarm.AlarmArn),
+		name:                 aws.ToString(alarm.AlarmName),
+		alarmType:            types.AlarmTypeCompositeAlarm,
+		description:          aws.ToString(alarm.AlarmDescription),
+		state:                alarm.StateValue,
+		alarmRule:            aws.ToString(alarm.AlarmRule),
+		actionsEnabled:       aws.ToBool(alarm.ActionsEnabled),
+		configurationUpdated: alarm.AlarmConfigurationUpdatedTimestamp,
+		raw:                  alarm,
+	}
+}
+
+// changeMarker returns the change marker of the alarm, the last update time of its configuration
+func (a alarmAttributes) changeMarker() string {
+	if a.configurationUpdated == nil {
+		return ""
+	}
+	return a.configurationUpdated.UTC().Format(time.RFC3339Nano)
+}
+
+// newAlarmMetadata builds the metadata of an alarm
+func newAlarmMetadata(attributes alarmAttributes, region, accountID string, tags map[string]string) ResourceMetadata {
+	metadata := ResourceMetadata{
+		ID:           attributes.arn,
+		Type:         constants.ResourceTypeCloudWatchAlarms,
+		Provider:     "aws",
+		AccountID:    accountID,
+		Region:       region,
+		DiscoveredAt: time.Now(),
+		Tags:         tags,
+		ChangeMarker: attributes.changeMarker(),
+		RawResponse:  attributes.raw,
+	}
+
+	metadata.Details.ARN = attributes.arn
+	metadata.Details.Name = attributes.name
+	metadata.Details.Status = string(attributes.state)
+	metadata.Details.Properties = map[string]interface{}{
+		"alarm_type":      string(attributes.alarmType),
+		"description":     attributes.description,
+		"state":           string(attributes.state),
+		"actions_enabled": attributes.actionsEnabled,
+	}
+	if attributes.alarmType == types.AlarmTypeCompositeAlarm {
+		metadata.Details.Properties["alarm_rule"] = attributes.alarmRule
+	} else {
+		metadata.Details.Properties["namespace"] = attributes.namespace
+		metadata.Details.Properties["metric_name"] = attributes.metricName
+	}
+
+	return metadata
+}
+
+// ParseCloudWatchAlarmARN extracts the alarm name and region from a CloudWatch alarm ARN
+func ParseCloudWatchAlarmARN(resourceARN string) (string, string, error) {
+	// ARN format: arn:partition:cloudwatch:region:account-id:alarm:alarm-name
+	parsed, err := arn.ParseRegionalService(resourceARN, "cloudwatch")
+	if err != nil {
+		return "", "", fmt.Errorf("invalid CloudWatch ARN format: %w", err)
+	}
+
+	// Alarm names may hold colons, the name is the whole rest of the resource
+	alarmName, ok := parsed.ResourceName("alarm")
+	if !ok || !strings.HasPrefix(parsed.Resource, "alarm:") {
+		return "", "", fmt.Errorf("invalid CloudWatch alarm in ARN: %s", resourceARN)
+	}
+	return alarmName, parsed.Region, nil
+}
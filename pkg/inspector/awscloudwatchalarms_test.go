@@ -0,0 +1,259 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// alarmUpdated is the last configuration update time of the mock alarms
+var alarmUpdated = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func alarmARN(name string) string {
+	return "arn:aws:cloudwatch:eu-west-1:123456789012:alarm:" + name
+}
+
+// mockCloudWatchClient serves metricAlarms metric alarms pageSize items per page, and a
+// composite alarm on the last page, when the alarm types include composite alarms. It
+// fails to read the tags of the alarms listed in tagErrors.
+type mockCloudWatchClient struct {
+	metricAlarms int
+	pageSize     int
+	tagErrors    map[string]bool
+}
+
+func (m *mockCloudWatchClient) DescribeAlarms(ctx context.Context, params *cloudwatch.DescribeAlarmsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error) {
+	var metricAlarms []types.MetricAlarm
+	for i := 0; i < m.metricAlarms; i++ {
+		name := fmt.Sprintf("cpu-high-%d", i)
+		metricAlarms = append(metricAlarms, types.MetricAlarm{
+			AlarmArn:                           aws.String(alarmARN(name)),
+			AlarmName:                          aws.String(name),
+			StateValue:                         types.StateValueOk,
+			Namespace:                          aws.String("AWS/EC2"),
+			MetricName:                         aws.String("CPUUtilization"),
+			ActionsEnabled:                     aws.Bool(true),
+			AlarmConfigurationUpdatedTimestamp: aws.Time(alarmUpdated),
+		})
+	}
+	composite := types.CompositeAlarm{
+		AlarmArn:                           aws.String(alarmARN("service-degraded")),
+		AlarmName:                          aws.String("service-degraded"),
+		AlarmRule:                          aws.String(`ALARM("cpu-high-0") OR ALARM("cpu-high-1")`),
+		StateValue:                         types.StateValueAlarm,
+		ActionsEnabled:                     aws.Bool(false),
+		AlarmConfigurationUpdatedTimestamp: aws.Time(alarmUpdated),
+	}
+
+	if len(params.AlarmNames) > 0 {
+		output := &cloudwatch.DescribeAlarmsOutput{}
+		for _, alarm := range metricAlarms {
+			if aws.ToString(alarm.AlarmName) == params.AlarmNames[0] {
+				output.MetricAlarms = append(output.MetricAlarms, alarm)
+			}
+		}
+		if params.AlarmNames[0] == aws.ToString(composite.AlarmName) {
+			output.CompositeAlarms = append(output.CompositeAlarms, composite)
+		}
+		return output, nil
+	}
+
+	start := 0
+	if params.NextToken != nil {
+		start, _ = strconv.Atoi(*params.NextToken)
+	}
+	end := min(start+m.pageSize, m.metricAlarms)
+
+	output := &cloudwatch.DescribeAlarmsOutput{MetricAlarms: metricAlarms[start:end]}
+	if end < m.metricAlarms {
+		output.NextToken = aws.String(strconv.Itoa(end))
+	} else {
+		for _, alarmType := range params.AlarmTypes {
+			if alarmType == types.AlarmTypeCompositeAlarm {
+				output.CompositeAlarms = []types.CompositeAlarm{composite}
+			}
+		}
+	}
+	return output, nil
+}
+
+func (m *mockCloudWatchClient) ListTagsForResource(ctx context.Context, params *cloudwatch.ListTagsForResourceInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListTagsForResourceOutput, error) {
+	if m.tagErrors[aws.ToString(params.ResourceARN)] {
+		return nil, errors.New("access denied")
+	}
+	return &cloudwatch.ListTagsForResourceOutput{
+		Tags: []types.Tag{{Key: aws.String("Team"), Value: aws.String("sre")}},
+	}, nil
+}
+
+func newTestCloudWatchAlarmsInspector() *CloudWatchAlarmsInspector {
+	return &CloudWatchAlarmsInspector{
+		Regions: []string{"eu-west-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+}
+
+func TestCloudWatchAlarmsInspectorDiscoversMetricAndCompositeAlarms(t *testing.T) {
+	t.Parallel()
+
+	failingARN := alarmARN("cpu-high-3")
+	client := &mockCloudWatchClient{metricAlarms: 5, pageSize: 2, tagErrors: map[string]bool{failingARN: true}}
+	clientFor := func(region string) (CloudWatchAlarmsAPI, error) {
+		return client, nil
+	}
+
+	discoverer, processor := newTestCloudWatchAlarmsInspector().newScanFuncs(clientFor, "123456789012")
+
+	resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(context.Background(), []string{"eu-west-1"}, discoverer, processor)
+	require.NoError(t, err)
+	require.Len(t, resources, 6)
+
+	for _, resource := range resources {
+		assert.Equal(t, constants.ResourceTypeCloudWatchAlarms, resource.Type)
+		assert.Equal(t, "eu-west-1", resource.Region)
+		assert.Equal(t, alarmARN(resource.Details.Name), resource.Details.ARN)
+		assert.Equal(t, alarmUpdated.Format(time.RFC3339Nano), resource.ChangeMarker)
+
+		if resource.Details.Properties["alarm_type"] == string(types.AlarmTypeCompositeAlarm) {
+			assert.Equal(t, "ALARM", resource.Details.Properties["state"])
+			assert.Equal(t, false, resource.Details.Properties["actions_enabled"])
+			assert.Contains(t, resource.Details.Properties["alarm_rule"], "cpu-high-0")
+		} else {
+			assert.Equal(t, "OK", resource.Details.Properties["state"])
+			assert.Equal(t, true, resource.Details.Properties["actions_enabled"])
+			assert.Equal(t, "AWS/EC2", resource.Details.Properties["namespace"])
+			assert.Equal(t, "CPUUtilization", resource.Details.Properties["metric_name"])
+		}
+
+		if resource.Details.ARN == failingARN {
+			assert.NotEmpty(t, resource.TagFetchError)
+			assert.Empty(t, resource.Tags)
+			continue
+		}
+		assert.Equal(t, map[string]string{"Team": "sre"}, resource.Tags)
+	}
+}
+
+func TestCloudWatchAlarmsInspectorFetch(t *testing.T) {
+	t.Parallel()
+
+	client := &mockCloudWatchClient{metricAlarms: 3, pageSize: 2}
+	clientFor := func(region string) (CloudWatchAlarmsAPI, error) {
+		if region != "eu-west-1" {
+			return nil, fmt.Errorf("unexpected region %s", region)
+		}
+		return client, nil
+	}
+	inspector := newTestCloudWatchAlarmsInspector()
+
+	metadata, err := inspector.fetch(context.Background(), alarmARN("service-degraded"), clientFor, "123456789012")
+	require.NoError(t, err)
+	assert.Equal(t, "service-degraded", metadata.Details.Name)
+	assert.Equal(t, string(types.AlarmTypeCompositeAlarm), metadata.Details.Properties["alarm_type"])
+	assert.Equal(t, map[string]string{"Team": "sre"}, metadata.Tags)
+
+	metadata, err = inspector.fetch(context.Background(), alarmARN("cpu-high-1"), clientFor, "123456789012")
+	require.NoError(t, err)
+	assert.Equal(t, "CPUUtilization", metadata.Details.Properties["metric_name"])
+
+	_, err = inspector.fetch(context.Background(), alarmARN("missing"), clientFor, "123456789012")
+	assert.Error(t, err)
+}
+
+func TestParseCloudWatchAlarmARN(t *testing.T) {
+	t.Parallel()
+
+	name, region, err := ParseCloudWatchAlarmARN("arn:aws:cloudwatch:us-east-1:123456789012:alarm:TargetTracking-table/orders:AlarmHigh")
+	require.NoError(t, err)
+	assert.Equal(t, "TargetTracking-table/orders:AlarmHigh", name)
+	assert.Equal(t, "us-east-1", region)
+
+	for _, invalid := range []string{
+		"arn:aws:cloudwatch:us-east-1:123456789012:dashboard:orders",
+		"arn:aws:cloudwatch:us-east-1:123456789012:alarm:",
+		"arn:aws:logs:us-east-1:123456789012:log-group:orders",
+		"not-an-arn",
+	} {
+		_, _, err := ParseCloudWatchAlarmARN(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+// probedCloudWatchClient is a mockCloudWatchClient recording the calls of a probe
+type probedCloudWatchClient struct {
+	*mockCloudWatchClient
+	calls *probeCalls
+}
+
+func (c *probedCloudWatchClient) DescribeAlarms(ctx context.Context, params *cloudwatch.DescribeAlarmsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error) {
+	if err := c.calls.call("cloudwatch:DescribeAlarms"); err != nil {
+		return nil, err
+	}
+	return c.mockCloudWatchClient.DescribeAlarms(ctx, params, optFns...)
+}
+
+func (c *probedCloudWatchClient) ListTagsForResource(ctx context.Context, params *cloudwatch.ListTagsForResourceInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListTagsForResourceOutput, error) {
+	if err := c.calls.call("cloudwatch:ListTagsForResource"); err != nil {
+		return nil, err
+	}
+	return c.mockCloudWatchClient.ListTagsForResource(ctx, params, optFns...)
+}
+
+func TestCloudWatchAlarmsInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &CloudWatchAlarmsInspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	tests := []struct {
+		name          string
+		denied        string
+		expectedCalls []string
+	}{
+		{
+			name:          "Success",
+			expectedCalls: []string{"cloudwatch:DescribeAlarms", "cloudwatch:ListTagsForResource"},
+		},
+		{
+			name:          "Describe Denied",
+			denied:        "cloudwatch:DescribeAlarms",
+			expectedCalls: []string{"cloudwatch:DescribeAlarms"},
+		},
+		{
+			name:          "Tags Denied",
+			denied:        "cloudwatch:ListTagsForResource",
+			expectedCalls: []string{"cloudwatch:DescribeAlarms", "cloudwatch:ListTagsForResource"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := &probeCalls{denied: tt.denied}
+			mock := &mockCloudWatchClient{metricAlarms: 3, pageSize: 1}
+			clientFor := func(region string) (CloudWatchAlarmsAPI, error) {
+				return &probedCloudWatchClient{mockCloudWatchClient: mock, calls: calls}, nil
+			}
+
+			err := inspector.probe(context.Background(), "us-east-1", clientFor)
+			assertProbeError(t, err, tt.denied)
+			assert.Equal(t, tt.expectedCalls, calls.made)
+		})
+	}
+}
@@ -4,9 +4,11 @@
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Excoriate/aws-taggy/pkg/arn"
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
 	"github.com/Excoriate/aws-taggy/pkg/o11y"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -63,6 +65,15 @@ func (m *AWSClientManager) GetCloudWatchLogsClient(region string) (*cloudwatchlo
 	return client.(*cloudwatchlogs.Client), nil
 }
 
+// CloudWatchLogsAPI is the subset of the CloudWatch Logs client used to discover log groups
+type CloudWatchLogsAPI interface {
+	cloudwatchlogs.DescribeLogGroupsAPIClient
+	ListTagsForResource(ctx context.Context, params *cloudwatchlogs.ListTagsForResourceInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.ListTagsForResourceOutput, error)
+}
+
+// cloudWatchLogsClientProvider returns the CloudWatch Logs client to use for a region
+type cloudWatchLogsClientProvider func(region string) (CloudWatchLogsAPI, error)
+
 // CloudWatchLogsInspector implements the Scanner interface for AWS CloudWatch Logs resources.
 // It provides functionality to discover and inspect CloudWatch Log Groups across multiple AWS regions.
 type CloudWatchLogsInspector struct {
@@ -109,8 +120,11 @@ func (s *CloudWatchLogsInspector) Inspect(ctx context.Context, config configurat
 		Region:    s.Regions[0],
 	}
 
-	// Create async scanner with default config
-	scanner := NewAsyncResourceInspector(DefaultInspectorConfig())
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := s.ClientManager.resolveAccountID(ctx, s.Logger)
 
 	// Define the resource discoverer function
 	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
@@ -129,7 +143,7 @@ func (s *CloudWatchLogsInspector) Inspect(ctx context.Context, config configurat
 		// Convert to interface slice
 		resources := make([]interface{}, len(logGroups))
 		for i, logGroup := range logGroups {
-			resources[i] = logGroup
+			resources[i] = RegionalResource{Region: region, Item: logGroup}
 		}
 
 		return resources, nil
@@ -137,40 +151,54 @@ func (s *CloudWatchLogsInspector) Inspect(ctx context.Context, config configurat
 
 	// Define the resource processor function
 	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
-		logGroup, ok := resource.(types.LogGroup)
+		regional, ok := resource.(RegionalResource)
+		if !ok {
+			return ResourceMetadata{}, fmt.Errorf("invalid resource type: expected RegionalResource")
+		}
+		logGroup, ok := regional.Item.(types.LogGroup)
 		if !ok {
 			return ResourceMetadata{}, fmt.Errorf("invalid resource type: expected LogGroup")
 		}
 
-		// Get CloudWatch Logs client for the region
-		cwLogsClient, err := s.ClientManager.GetCloudWatchLogsClient(s.Regions[0])
+		// Get CloudWatch Logs client for the log group region
+		cwLogsClient, err := s.ClientManager.GetCloudWatchLogsClient(regional.Region)
 		if err != nil {
 			return ResourceMetadata{}, fmt.Errorf("failed to get CloudWatch Logs client: %w", err)
 		}
 
-		// Get log group tags
-		tags, err := s.getLogGroupTags(ctx, cwLogsClient, aws.ToString(logGroup.LogGroupName))
-		if err != nil {
-			s.Logger.Warn("Failed to get log group tags",
-				"log_group", aws.ToString(logGroup.LogGroupName),
-				"error", err)
-			tags = make(map[string]string)
+		arn := fmt.Sprintf("%s:*", logGroupARN(regional.Region, accountID, aws.ToString(logGroup.LogGroupName)))
+		changeMarker := logGroupChangeMarker(logGroup)
+
+		// Get log group tags, unless an incremental scan recorded them for the same log group
+		tags, fromSnapshot := previousTags(ctx, arn, changeMarker)
+		if !fromSnapshot {
+			tags, err = s.getLogGroupTags(ctx, cwLogsClient, regional.Region, accountID, aws.ToString(logGroup.LogGroupName))
+			if err != nil {
+				s.Logger.Warn("Failed to get log group tags",
+					"log_group", aws.ToString(logGroup.LogGroupName),
+					"error", err)
+				tags = make(map[string]string)
+			}
 		}
 
 		// Create resource metadata
 		metadata := ResourceMetadata{
-			ID:           aws.ToString(logGroup.LogGroupName),
-			Type:         "cloudwatch_logs",
-			Provider:     "aws",
-			Region:       s.Regions[0],
-			DiscoveredAt: time.Now(),
-			Tags:         tags,
-			RawResponse:  logGroup,
+			ID:            aws.ToString(logGroup.LogGroupName),
+			Type:          "cloudwatch_logs",
+			Provider:      "aws",
+			AccountID:     accountID,
+			Region:        regional.Region,
+			DiscoveredAt:  time.Now(),
+			CreatedAt:     epochMillis(logGroup.CreationTime),
+			Tags:          tags,
+			TagFetchError: tagFetchError(err),
+			ChangeMarker:  changeMarker,
+			FromSnapshot:  fromSnapshot,
+			RawResponse:   logGroup,
 		}
 
 		// Populate extended details
-		metadata.Details.ARN = fmt.Sprintf("arn:aws:logs:%s:%s:log-group:%s:*",
-			s.Regions[0], "unknown_account", aws.ToString(logGroup.LogGroupName))
+		metadata.Details.ARN = arn
 		metadata.Details.Name = aws.ToString(logGroup.LogGroupName)
 		metadata.Details.Properties = map[string]interface{}{
 			"creation_time":     logGroup.CreationTime,
@@ -201,6 +229,40 @@ func (s *CloudWatchLogsInspector) Inspect(ctx context.Context, config configurat
 	return result, nil
 }
 
+// regionalClient returns the CloudWatch Logs client of a region from the client manager
+func (s *CloudWatchLogsInspector) regionalClient(region string) (CloudWatchLogsAPI, error) {
+	client, err := s.ClientManager.GetCloudWatchLogsClient(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe describes a single log group of the region, then reads its tags
+func (s *CloudWatchLogsInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return s.probe(ctx, region, s.ClientManager.resolveAccountID(ctx, s.Logger), s.regionalClient)
+}
+
+// probe issues the calls of Probe with the clients of clientFor, the tags being read with
+// the log group ARN of accountID
+func (s *CloudWatchLogsInspector) probe(ctx context.Context, region, accountID string, clientFor cloudWatchLogsClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get CloudWatch Logs client: %w", err)
+	}
+
+	output, err := client.DescribeLogGroups(ctx, &cloudwatchlogs.DescribeLogGroupsInput{Limit: aws.Int32(1)})
+	if err != nil {
+		return probeCall("logs:DescribeLogGroups", err)
+	}
+	if len(output.LogGroups) == 0 {
+		return nil
+	}
+
+	_, err = s.getLogGroupTags(ctx, client, region, accountID, aws.ToString(output.LogGroups[0].LogGroupName))
+	return probeCall("logs:ListTagsForResource", err)
+}
+
 // listLogGroups retrieves all CloudWatch Log Groups in a region.
 //
 // This method uses pagination to retrieve all log groups in the specified region.
@@ -213,7 +275,7 @@ func (s *CloudWatchLogsInspector) Inspect(ctx context.Context, config configurat
 // Returns:
 //   - []types.LogGroup: A slice of discovered log groups
 //   - error: An error if the operation fails
-func (s *CloudWatchLogsInspector) listLogGroups(ctx context.Context, client *cloudwatchlogs.Client) ([]types.LogGroup, error) {
+func (s *CloudWatchLogsInspector) listLogGroups(ctx context.Context, client CloudWatchLogsAPI) ([]types.LogGroup, error) {
 	var logGroups []types.LogGroup
 	input := &cloudwatchlogs.DescribeLogGroupsInput{}
 	paginator := cloudwatchlogs.NewDescribeLogGroupsPaginator(client, input)
@@ -239,18 +301,17 @@ func (s *CloudWatchLogsInspector) listLogGroups(ctx context.Context, client *clo
 // Parameters:
 //   - ctx: Context for the API calls
 //   - client: The CloudWatch Logs client to use
+//   - region: The region the log group lives in
+//   - accountID: The account owning the log group
 //   - logGroupName: The name of the log group
 //
 // Returns:
 //   - map[string]string: A map of tag key-value pairs
 //   - error: An error if the operation fails
-func (s *CloudWatchLogsInspector) getLogGroupTags(ctx context.Context, client *cloudwatchlogs.Client, logGroupName string) (map[string]string, error) {
-	// Construct the ARN for the log group
-	logGroupARN := fmt.Sprintf("arn:aws:logs:%s:%s:log-group:%s:*",
-		s.Regions[0], "unknown_account", logGroupName)
-
+func (s *CloudWatchLogsInspector) getLogGroupTags(ctx context.Context, client CloudWatchLogsAPI, region, accountID, logGroupName string) (map[string]string, error) {
+	// ListTagsForResource expects the log group ARN without the trailing ":*"
 	input := &cloudwatchlogs.ListTagsForResourceInput{
-		ResourceArn: aws.String(logGroupARN),
+		ResourceArn: aws.String(logGroupARN(region, accountID, logGroupName)),
 	}
 
 	// Retrieve log group tags
@@ -309,7 +370,8 @@ func (s *CloudWatchLogsInspector) Fetch(ctx context.Context, arn string, config
 	}
 
 	// Get log group tags
-	tags, err := s.getLogGroupTags(ctx, cwLogsClient, logGroupName)
+	accountID := s.ClientManager.resolveAccountID(ctx, s.Logger)
+	tags, err := s.getLogGroupTags(ctx, cwLogsClient, region, accountID, logGroupName)
 	if err != nil {
 		s.Logger.Warn("Failed to get log group tags", "log_group", logGroupName, "error", err)
 		tags = make(map[string]string)
@@ -317,13 +379,17 @@ func (s *CloudWatchLogsInspector) Fetch(ctx context.Context, arn string, config
 
 	// Create resource metadata
 	resourceMeta := &ResourceMetadata{
-		ID:           logGroupName,
-		Type:         "cloudwatch_logs",
-		Provider:     "aws",
-		Region:       region,
-		Tags:         tags,
-		DiscoveredAt: time.Now(),
-		RawResponse:  logGroup,
+		ID:            logGroupName,
+		Type:          "cloudwatch_logs",
+		Provider:      "aws",
+		AccountID:     accountID,
+		Region:        region,
+		Tags:          tags,
+		TagFetchError: tagFetchError(err),
+		ChangeMarker:  logGroupChangeMarker(*logGroup),
+		DiscoveredAt:  time.Now(),
+		CreatedAt:     epochMillis(logGroup.CreationTime),
+		RawResponse:   logGroup,
 	}
 
 	// Populate extended details
@@ -342,33 +408,54 @@ func (s *CloudWatchLogsInspector) Fetch(ctx context.Context, arn string, config
 // ParseCloudWatchLogsARN extracts log group name and region from CloudWatch Logs ARN
 //
 // This function parses an ARN for a CloudWatch Logs log group and extracts the log group name
-// and region. It handles ARNs in the format:
-// arn:aws:logs:region:account-id:log-group:log-group-name:*
+// and region. It handles ARNs in the formats:
+// arn:partition:logs:region:account-id:log-group:log-group-name:*
+// arn:partition:logs:region:account-id:log-group:log-group-name
 //
-// The function also handles log group names that may contain ':' characters by joining
-// all remaining parts after the log-group prefix.
+// Log group names hold no ':' characters, so the ARNs of log streams, which follow the log
+// group name with a colon, are rejected.
 //
 // Parameters:
-//   - arn: The ARN string to parse
+//   - resourceARN: The ARN string to parse
 //
 // Returns:
 //   - string: The log group name
 //   - string: The AWS region
 //   - error: An error if the ARN format is invalid
-func ParseCloudWatchLogsARN(arn string) (string, string, error) {
-	// ARN format: arn:aws:logs:region:account-id:log-group:log-group-name:*
-	parts := strings.Split(arn, ":")
-	if len(parts) < 7 {
-		return "", "", fmt.Errorf("invalid CloudWatch Logs ARN format: %s", arn)
+func ParseCloudWatchLogsARN(resourceARN string) (string, string, error) {
+	parsed, err := arn.ParseRegionalService(resourceARN, "logs")
+	if err != nil {
+		return "", "", fmt.Errorf("invalid CloudWatch Logs ARN format: %w", err)
+	}
+
+	resource, ok := parsed.ResourceName("log-group")
+	logGroupName, suffix, _ := strings.Cut(resource, ":")
+	if !ok || logGroupName == "" || (suffix != "" && suffix != "*") {
+		return "", "", fmt.Errorf("invalid CloudWatch Logs log group in ARN: %s", resourceARN)
 	}
+	return logGroupName, parsed.Region, nil
+}
 
-	// Extract region
-	region := parts[3]
+// logGroupChangeMarker returns the change marker of a log group, its creation time, which
+// tells a log group apart from an earlier one of the same name. Log groups expose no cheap
+// indicator of tag changes, so incremental scans only detect recreated log groups.
+func logGroupChangeMarker(logGroup types.LogGroup) string {
+	if logGroup.CreationTime == nil {
+		return ""
+	}
+	return strconv.FormatInt(*logGroup.CreationTime, 10)
+}
 
-	// Handle log group names that may contain ':' characters
-	// Join all parts after "log-group" until the last part (which should be "*")
-	logGroupParts := parts[6 : len(parts)-1]
-	logGroupName := strings.Join(logGroupParts, ":")
+// epochMillis converts a time in milliseconds since the epoch, as CloudWatch Logs reports
+// creation times, zero when unset
+func epochMillis(milliseconds *int64) time.Time {
+	if milliseconds == nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(*milliseconds).UTC()
+}
 
-	return logGroupName, region, nil
+// logGroupARN builds the ARN of a log group, without the trailing ":*" stream wildcard
+func logGroupARN(region, accountID, logGroupName string) string {
+	return resourceARN("logs", region, true, accountID, "log-group:"+logGroupName)
 }
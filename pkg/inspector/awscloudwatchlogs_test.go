@@ -0,0 +1,94 @@
+package inspector
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCloudWatchLogsClient serves its log groups in a single page, recording the calls made
+type mockCloudWatchLogsClient struct {
+	logGroups  []types.LogGroup
+	calls      *probeCalls
+	taggedARNs []string
+}
+
+func (m *mockCloudWatchLogsClient) DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	if err := m.calls.call("logs:DescribeLogGroups"); err != nil {
+		return nil, err
+	}
+	return &cloudwatchlogs.DescribeLogGroupsOutput{LogGroups: m.logGroups}, nil
+}
+
+func (m *mockCloudWatchLogsClient) ListTagsForResource(ctx context.Context, params *cloudwatchlogs.ListTagsForResourceInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.ListTagsForResourceOutput, error) {
+	m.taggedARNs = append(m.taggedARNs, aws.ToString(params.ResourceArn))
+	if err := m.calls.call("logs:ListTagsForResource"); err != nil {
+		return nil, err
+	}
+	return &cloudwatchlogs.ListTagsForResourceOutput{Tags: map[string]string{"Environment": "production"}}, nil
+}
+
+func TestCloudWatchLogsInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &CloudWatchLogsInspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	logGroups := []types.LogGroup{{LogGroupName: aws.String("/aws/lambda/orders")}}
+
+	tests := []struct {
+		name          string
+		logGroups     []types.LogGroup
+		denied        string
+		expectedCalls []string
+	}{
+		{
+			name:          "Success",
+			logGroups:     logGroups,
+			expectedCalls: []string{"logs:DescribeLogGroups", "logs:ListTagsForResource"},
+		},
+		{
+			name:          "No Log Group",
+			expectedCalls: []string{"logs:DescribeLogGroups"},
+		},
+		{
+			name:          "Describe Denied",
+			logGroups:     logGroups,
+			denied:        "logs:DescribeLogGroups",
+			expectedCalls: []string{"logs:DescribeLogGroups"},
+		},
+		{
+			name:          "Tags Denied",
+			logGroups:     logGroups,
+			denied:        "logs:ListTagsForResource",
+			expectedCalls: []string{"logs:DescribeLogGroups", "logs:ListTagsForResource"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := &probeCalls{denied: tt.denied}
+			client := &mockCloudWatchLogsClient{logGroups: tt.logGroups, calls: calls}
+			clientFor := func(region string) (CloudWatchLogsAPI, error) {
+				return client, nil
+			}
+
+			err := inspector.probe(context.Background(), "us-east-1", "123456789012", clientFor)
+			assertProbeError(t, err, tt.denied)
+			assert.Equal(t, tt.expectedCalls, calls.made)
+			if len(client.taggedARNs) > 0 {
+				assert.Equal(t, []string{"arn:aws:logs:us-east-1:123456789012:log-group:/aws/lambda/orders"}, client.taggedARNs,
+					"tags are read with the log group ARN without the trailing :*")
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/arn"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// OrphanedProperty is the property of the resource details flagging resources nothing uses,
+// such as EBS volumes attached to no instance
+const OrphanedProperty = "orphaned"
+
+// EBS resource kinds, recorded under the kind property of EBS resources
+const (
+	ebsKindVolume   = "volume"
+	ebsKindSnapshot = "snapshot"
+)
+
+// EBSAPI is the subset of the EC2 client used to discover EBS volumes and snapshots
+type EBSAPI interface {
+	ec2.DescribeVolumesAPIClient
+	ec2.DescribeSnapshotsAPIClient
+}
+
+// ebsClientProvider returns the EC2 client to use for a region
+type ebsClientProvider func(region string) (EBSAPI, error)
+
+// EBSInspector implements the Inspector interface for AWS EBS volumes, and the snapshots
+// owned by the account when the ebs resource type includes them
+type EBSInspector struct {
+	Regions       []string
+	ClientManager *AWSClientManager
+	Logger        *o11y.Logger
+}
+
+// NewEBSInspector creates a new EBSInspector with AWS client management
+func NewEBSInspector(regions []string) (*EBSInspector, error) {
+	clientManager, err := NewAWSRegionalClientManager(regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client manager: %w", err)
+	}
+
+	return &EBSInspector{
+		Regions:       regions,
+		ClientManager: clientManager,
+		Logger:        o11y.DefaultLogger(),
+	}, nil
+}
+
+// Inspect discovers EBS volumes, and snapshots when configured, across specified regions
+func (s *EBSInspector) Inspect(ctx context.Context, config configuration.TaggyScanConfig) (*InspectResult, error) {
+	includeSnapshots := config.Resources[constants.ResourceTypeEBS].IncludeSnapshots
+
+	s.Logger.Info("Starting EBS resource scanning",
+		"regions", s.Regions,
+		"include_snapshots", includeSnapshots)
+
+	result := &InspectResult{
+		StartTime: time.Now(),
+		Region:    s.Regions[0],
+	}
+
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := s.ClientManager.resolveAccountID(ctx, s.Logger)
+
+	discoverer := s.newDiscoverer(includeSnapshots, s.regionalClient)
+
+	// Define the resource processor function
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		return s.processResource(resource.(RegionalResource), accountID), nil
+	}
+
+	// Perform the async scan
+	resources, err := scanner.InspectResourcesAsync(ctx, s.Regions, discoverer, processor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan EBS resources: %w", err)
+	}
+
+	// Update result with scanned resources
+	result.Resources = resources
+	result.TotalResources = len(resources)
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+
+	s.Logger.Info("EBS scanning completed",
+		"total_resources", result.TotalResources,
+		"duration", result.Duration)
+
+	return result, nil
+}
+
+// regionalClient returns the EC2 client of a region from the client manager
+func (s *EBSInspector) regionalClient(region string) (EBSAPI, error) {
+	client, err := s.ClientManager.GetEC2Client(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe describes a few volumes of the region, and a few of its snapshots when the ebs
+// resource type includes them. Their tags are part of the description.
+func (s *EBSInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return s.probe(ctx, region, config.Resources[constants.ResourceTypeEBS].IncludeSnapshots, s.regionalClient)
+}
+
+// probe issues the calls of Probe with the clients of clientFor
+func (s *EBSInspector) probe(ctx context.Context, region string, includeSnapshots bool, clientFor ebsClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get EC2 client: %w", err)
+	}
+
+	if _, err := client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{MaxResults: aws.Int32(probeMaxResults)}); err != nil {
+		return probeCall("ec2:DescribeVolumes", err)
+	}
+	if !includeSnapshots {
+		return nil
+	}
+	_, err = client.DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{
+		OwnerIds:   []string{"self"},
+		MaxResults: aws.Int32(probeMaxResults),
+	})
+	return probeCall("ec2:DescribeSnapshots", err)
+}
+
+// newDiscoverer returns a discoverer listing the volumes of a region, along with the
+// snapshots owned by the account when includeSnapshots is set
+func (s *EBSInspector) newDiscoverer(includeSnapshots bool, clientFor ebsClientProvider) ResourceDiscoverer {
+	return func(ctx context.Context, region string) ([]interface{}, error) {
+		client, err := clientFor(region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get EC2 client: %w", err)
+		}
+
+		volumes, err := s.listVolumes(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]interface{}, 0, len(volumes))
+		for _, volume := range volumes {
+			resources = append(resources, RegionalResource{Region: region, Item: volume})
+		}
+
+		if !includeSnapshots {
+			return resources, nil
+		}
+
+		snapshots, err := s.listSnapshots(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		for _, snapshot := range snapshots {
+			resources = append(resources, RegionalResource{Region: region, Item: snapshot})
+		}
+
+		return resources, nil
+	}
+}
+
+// listVolumes pages through the EBS volumes of a region
+func (s *EBSInspector) listVolumes(ctx context.Context, client EBSAPI) ([]types.Volume, error) {
+	var volumes []types.Volume
+	paginator := ec2.NewDescribeVolumesPaginator(client, &ec2.DescribeVolumesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list volumes: %w", err)
+		}
+		volumes = append(volumes, output.Volumes...)
+	}
+	return volumes, nil
+}
+
+// listSnapshots pages through the EBS snapshots of a region owned by the account, leaving
+// out the public and shared ones
+func (s *EBSInspector) listSnapshots(ctx context.Context, client EBSAPI) ([]types.Snapshot, error) {
+	var snapshots []types.Snapshot
+	paginator := ec2.NewDescribeSnapshotsPaginator(client, &ec2.DescribeSnapshotsInput{
+		OwnerIds: []string{"self"},
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list snapshots: %w", err)
+		}
+		snapshots = append(snapshots, output.Snapshots...)
+	}
+	return snapshots, nil
+}
+
+// processResource builds the metadata of a volume or a snapshot
+func (s *EBSInspector) processResource(regional RegionalResource, accountID string) ResourceMetadata {
+	switch item := regional.Item.(type) {
+	case types.Snapshot:
+		return s.processSnapshot(item, regional.Region, accountID)
+	default:
+		return s.processVolume(item.(types.Volume), regional.Region, accountID)
+	}
+}
+
+// processVolume builds the metadata of a volume, flagged as orphaned when it is attached to
+// no instance
+func (s *EBSInspector) processVolume(volume types.Volume, region, accountID string) ResourceMetadata {
+	volumeID := aws.ToString(volume.VolumeId)
+
+	instanceIDs := make([]string, 0, len(volume.Attachments))
+	for _, attachment := range volume.Attachments {
+		instanceIDs = append(instanceIDs, aws.ToString(attachment.InstanceId))
+	}
+
+	metadata := ResourceMetadata{
+		ID:           volumeID,
+		Type:         constants.ResourceTypeEBS,
+		Provider:     "aws",
+		AccountID:    accountID,
+		Region:       region,
+		DiscoveredAt: time.Now(),
+		CreatedAt:    aws.ToTime(volume.CreateTime),
+		Tags:         ec2TagMap(volume.Tags),
+		RawResponse:  volume,
+	}
+
+	metadata.Details.ARN = resourceARN("ec2", region, true, accountID, "volume/"+volumeID)
+	metadata.Details.Name = ec2ResourceName(volume.Tags, volumeID)
+	metadata.Details.Status = string(volume.State)
+	metadata.Details.Properties = map[string]interface{}{
+		"kind":              ebsKindVolume,
+		"size_gib":          aws.ToInt32(volume.Size),
+		"state":             string(volume.State),
+		"volume_type":       string(volume.VolumeType),
+		"encrypted":         aws.ToBool(volume.Encrypted),
+		"availability_zone": aws.ToString(volume.AvailabilityZone),
+		"create_time":       volume.CreateTime,
+		"instance_ids":      instanceIDs,
+		OrphanedProperty:    len(instanceIDs) == 0,
+	}
+
+	return metadata
+}
+
+// processSnapshot builds the metadata of a snapshot
+func (s *EBSInspector) processSnapshot(snapshot types.Snapshot, region, accountID string) ResourceMetadata {
+	snapshotID := aws.ToString(snapshot.SnapshotId)
+
+	metadata := ResourceMetadata{
+		ID:           snapshotID,
+		Type:         constants.ResourceTypeEBS,
+		Provider:     "aws",
+		AccountID:    accountID,
+		Region:       region,
+		DiscoveredAt: time.Now(),
+		CreatedAt:    aws.ToTime(snapshot.StartTime),
+		Tags:         ec2TagMap(snapshot.Tags),
+		RawResponse:  snapshot,
+	}
+
+	metadata.Details.ARN = resourceARN("ec2", region, true, accountID, "snapshot/"+snapshotID)
+	metadata.Details.Name = ec2ResourceName(snapshot.Tags, snapshotID)
+	metadata.Details.Status = string(snapshot.State)
+	metadata.Details.Properties = map[string]interface{}{
+		"kind":       ebsKindSnapshot,
+		"size_gib":   aws.ToInt32(snapshot.VolumeSize),
+		"state":      string(snapshot.State),
+		"encrypted":  aws.ToBool(snapshot.Encrypted),
+		"volume_id":  aws.ToString(snapshot.VolumeId),
+		"start_time": snapshot.StartTime,
+	}
+
+	return metadata
+}
+
+// Fetch implements the Inspector interface for retrieving a specific volume or snapshot
+func (s *EBSInspector) Fetch(ctx context.Context, arn string, config configuration.TaggyScanConfig) (*ResourceMetadata, error) {
+	kind, id, region, err := ParseEBSARN(arn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse EBS ARN: %w", err)
+	}
+
+	client, err := s.ClientManager.GetEC2Client(region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create EC2 client: %w", err)
+	}
+
+	accountID := s.ClientManager.resolveAccountID(ctx, s.Logger)
+
+	var metadata ResourceMetadata
+	switch kind {
+	case ebsKindSnapshot:
+		output, err := client.DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{SnapshotIds: []string{id}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch EBS snapshot: %w", err)
+		}
+		if len(output.Snapshots) == 0 {
+			return nil, fmt.Errorf("no snapshot found with ID %s", id)
+		}
+		metadata = s.processSnapshot(output.Snapshots[0], region, accountID)
+	default:
+		output, err := client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{VolumeIds: []string{id}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch EBS volume: %w", err)
+		}
+		if len(output.Volumes) == 0 {
+			return nil, fmt.Errorf("no volume found with ID %s", id)
+		}
+		metadata = s.processVolume(output.Volumes[0], region, accountID)
+	}
+
+	metadata.Details.ARN = arn
+	return &metadata, nil
+}
+
+// ParseEBSARN extracts the kind (volume or snapshot), ID and region from an EBS ARN
+func ParseEBSARN(resourceARN string) (string, string, string, error) {
+	// ARN format: arn:partition:ec2:region:account-id:volume/vol-id or .../snapshot/snap-id
+	parsed, err := arn.ParseRegionalService(resourceARN, "ec2")
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid EBS ARN format: %w", err)
+	}
+
+	kind, id := parsed.ResourceTypeAndName()
+	if id == "" || (kind != ebsKindVolume && kind != ebsKindSnapshot) {
+		return "", "", "", fmt.Errorf("invalid EBS volume or snapshot in ARN: %s", resourceARN)
+	}
+	return kind, id, parsed.Region, nil
+}
+
+// ec2TagMap converts EC2 tags to a map
+func ec2TagMap(ec2Tags []types.Tag) map[string]string {
+	tags := make(map[string]string, len(ec2Tags))
+	for _, tag := range ec2Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags
+}
+
+// ec2ResourceName returns the Name tag of an EC2 resource, such as a volume or a NAT
+// gateway, or its ID
+func ec2ResourceName(tags []types.Tag, id string) string {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == "Name" {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return id
+}
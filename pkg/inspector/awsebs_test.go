@@ -0,0 +1,254 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockEBSClient serves the volumes of its region, every other one attached to an instance,
+// and one snapshot of the account per volume, pageSize items per page
+type mockEBSClient struct {
+	region   string
+	volumes  int
+	pageSize int
+}
+
+func (m *mockEBSClient) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	start, end, next := m.page(params.NextToken)
+
+	output := &ec2.DescribeVolumesOutput{NextToken: next}
+	for i := start; i < end; i++ {
+		volume := types.Volume{
+			VolumeId:   aws.String(fmt.Sprintf("vol-%s-%03d", m.region, i)),
+			Size:       aws.Int32(8),
+			State:      types.VolumeStateAvailable,
+			VolumeType: types.VolumeTypeGp3,
+			Encrypted:  aws.Bool(true),
+			Tags:       []types.Tag{{Key: aws.String("Name"), Value: aws.String(fmt.Sprintf("data-%d", i))}},
+		}
+		if i%2 == 0 {
+			volume.State = types.VolumeStateInUse
+			volume.Attachments = []types.VolumeAttachment{{InstanceId: aws.String(fmt.Sprintf("i-%03d", i))}}
+		}
+		output.Volumes = append(output.Volumes, volume)
+	}
+	return output, nil
+}
+
+func (m *mockEBSClient) DescribeSnapshots(ctx context.Context, params *ec2.DescribeSnapshotsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+	if len(params.OwnerIds) != 1 || params.OwnerIds[0] != "self" {
+		return nil, fmt.Errorf("unexpected owners %v", params.OwnerIds)
+	}
+
+	start, end, next := m.page(params.NextToken)
+
+	output := &ec2.DescribeSnapshotsOutput{NextToken: next}
+	for i := start; i < end; i++ {
+		output.Snapshots = append(output.Snapshots, types.Snapshot{
+			SnapshotId: aws.String(fmt.Sprintf("snap-%s-%03d", m.region, i)),
+			VolumeId:   aws.String(fmt.Sprintf("vol-%s-%03d", m.region, i)),
+			VolumeSize: aws.Int32(8),
+			State:      types.SnapshotStateCompleted,
+		})
+	}
+	return output, nil
+}
+
+// page returns the bounds of the page starting at token, and the token of the next one
+func (m *mockEBSClient) page(token *string) (int, int, *string) {
+	start := 0
+	if token != nil {
+		start, _ = strconv.Atoi(*token)
+	}
+	end := min(start+m.pageSize, m.volumes)
+	if end < m.volumes {
+		return start, end, aws.String(strconv.Itoa(end))
+	}
+	return start, end, nil
+}
+
+func TestEBSInspectorDiscoversVolumesAndSnapshots(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		includeSnapshots bool
+		expected         map[string]int // resources per kind, per region
+	}{
+		{
+			name:     "Volumes Only",
+			expected: map[string]int{ebsKindVolume: 11},
+		},
+		{
+			name:             "With Snapshots",
+			includeSnapshots: true,
+			expected:         map[string]int{ebsKindVolume: 11, ebsKindSnapshot: 11},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			regions := []string{"us-east-1", "eu-west-1"}
+			clientFor := func(region string) (EBSAPI, error) {
+				return &mockEBSClient{region: region, volumes: 11, pageSize: 4}, nil
+			}
+
+			inspector := &EBSInspector{
+				Regions: regions,
+				Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+			}
+			discoverer := inspector.newDiscoverer(tt.includeSnapshots, clientFor)
+			processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+				return inspector.processResource(resource.(RegionalResource), "123456789012"), nil
+			}
+
+			resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+				InspectResourcesAsync(context.Background(), regions, discoverer, processor)
+			require.NoError(t, err)
+
+			counts := make(map[string]map[string]int)
+			for _, resource := range resources {
+				kind := resource.Details.Properties["kind"].(string)
+				if counts[resource.Region] == nil {
+					counts[resource.Region] = make(map[string]int)
+				}
+				counts[resource.Region][kind]++
+
+				assert.Equal(t, fmt.Sprintf("arn:aws:ec2:%s:123456789012:%s/%s", resource.Region, kind, resource.ID), resource.Details.ARN)
+				if kind == ebsKindSnapshot {
+					assert.False(t, resource.IsOrphaned(), resource.ID)
+					continue
+				}
+
+				instanceIDs := resource.Details.Properties["instance_ids"].([]string)
+				assert.Equal(t, len(instanceIDs) == 0, resource.IsOrphaned(), resource.ID)
+				assert.Equal(t, "gp3", resource.Details.Properties["volume_type"])
+				assert.Equal(t, true, resource.Details.Properties["encrypted"])
+				assert.Equal(t, int32(8), resource.Details.Properties["size_gib"])
+			}
+			for _, region := range regions {
+				assert.Equal(t, tt.expected, counts[region], "resources of %s", region)
+			}
+		})
+	}
+}
+
+func TestParseEBSARN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		arn    string
+		kind   string
+		id     string
+		region string
+		errMsg string
+	}{
+		{arn: "arn:aws:ec2:us-east-1:123456789012:volume/vol-0abc", kind: ebsKindVolume, id: "vol-0abc", region: "us-east-1"},
+		{arn: "arn:aws:ec2:eu-west-1:123456789012:snapshot/snap-0abc", kind: ebsKindSnapshot, id: "snap-0abc", region: "eu-west-1"},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:instance/i-0abc", errMsg: "invalid EBS volume or snapshot"},
+		{arn: "arn:aws:s3:::volume/vol-0abc", errMsg: "invalid EBS ARN format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arn, func(t *testing.T) {
+			t.Parallel()
+
+			kind, id, region, err := ParseEBSARN(tt.arn)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.kind, kind)
+			assert.Equal(t, tt.id, id)
+			assert.Equal(t, tt.region, region)
+		})
+	}
+}
+
+// probedEBSClient is a mockEBSClient recording the calls of a probe
+type probedEBSClient struct {
+	*mockEBSClient
+	calls *probeCalls
+}
+
+func (c *probedEBSClient) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	if err := c.calls.call("ec2:DescribeVolumes"); err != nil {
+		return nil, err
+	}
+	return c.mockEBSClient.DescribeVolumes(ctx, params, optFns...)
+}
+
+func (c *probedEBSClient) DescribeSnapshots(ctx context.Context, params *ec2.DescribeSnapshotsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+	if err := c.calls.call("ec2:DescribeSnapshots"); err != nil {
+		return nil, err
+	}
+	return c.mockEBSClient.DescribeSnapshots(ctx, params, optFns...)
+}
+
+func TestEBSInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &EBSInspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	tests := []struct {
+		name             string
+		includeSnapshots bool
+		denied           string
+		expectedCalls    []string
+	}{
+		{
+			name:          "Volumes",
+			expectedCalls: []string{"ec2:DescribeVolumes"},
+		},
+		{
+			name:             "Volumes And Snapshots",
+			includeSnapshots: true,
+			expectedCalls:    []string{"ec2:DescribeVolumes", "ec2:DescribeSnapshots"},
+		},
+		{
+			name:             "Volumes Denied",
+			includeSnapshots: true,
+			denied:           "ec2:DescribeVolumes",
+			expectedCalls:    []string{"ec2:DescribeVolumes"},
+		},
+		{
+			name:             "Snapshots Denied",
+			includeSnapshots: true,
+			denied:           "ec2:DescribeSnapshots",
+			expectedCalls:    []string{"ec2:DescribeVolumes", "ec2:DescribeSnapshots"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := &probeCalls{denied: tt.denied}
+			clientFor := func(region string) (EBSAPI, error) {
+				return &probedEBSClient{mockEBSClient: &mockEBSClient{region: region, volumes: 3, pageSize: 5}, calls: calls}, nil
+			}
+
+			err := inspector.probe(context.Background(), "us-east-1", tt.includeSnapshots, clientFor)
+			assertProbeError(t, err, tt.denied)
+			assert.Equal(t, tt.expectedCalls, calls.made)
+		})
+	}
+}
@@ -3,10 +3,11 @@
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
+	"github.com/Excoriate/aws-taggy/pkg/arn"
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
 	"github.com/Excoriate/aws-taggy/pkg/o11y"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -62,6 +63,14 @@ func (m *AWSClientManager) GetEC2Client(region string) (*ec2.Client, error) {
 	return client.(*ec2.Client), nil
 }
 
+// EC2API is the subset of the EC2 client used to discover instances
+type EC2API interface {
+	ec2.DescribeInstancesAPIClient
+}
+
+// ec2ClientProvider returns the EC2 client to use for a region
+type ec2ClientProvider func(region string) (EC2API, error)
+
 // EC2Inspector implements the Inspector interface for AWS EC2 resources
 type EC2Inspector struct {
 	Regions       []string
@@ -69,8 +78,15 @@ type EC2Inspector struct {
 	Logger        *o11y.Logger
 }
 
-// NewEC2Scanner creates a new EC2Scanner with AWS client management
+// NewEC2Scanner creates a new EC2Inspector with AWS client management.
+//
+// Deprecated: use NewEC2Inspector, named like the constructors of the other inspectors.
 func NewEC2Scanner(regions []string) (*EC2Inspector, error) {
+	return NewEC2Inspector(regions)
+}
+
+// NewEC2Inspector creates a new EC2Inspector with AWS client management
+func NewEC2Inspector(regions []string) (*EC2Inspector, error) {
 	// Create AWS client manager for the specified regions
 	clientManager, err := NewAWSRegionalClientManager(regions)
 	if err != nil {
@@ -98,77 +114,28 @@ func (s *EC2Inspector) getRegionFromAZ(az string) string {
 
 // Inspect discovers EC2 instances and their metadata across specified regions
 func (s *EC2Inspector) Inspect(ctx context.Context, config configuration.TaggyScanConfig) (*InspectResult, error) {
+	instanceStates := config.Resources[constants.ResourceTypeEC2].InstanceStates()
+
 	s.Logger.Info("Starting EC2 resource scanning",
-		"regions", s.Regions)
+		"regions", s.Regions,
+		"instance_states", instanceStates)
 
 	result := &InspectResult{
 		StartTime: time.Now(),
 		Region:    s.Regions[0],
 	}
 
-	// Create async scanner with default config
-	scanner := NewAsyncResourceInspector(DefaultInspectorConfig())
-
-	// Define the resource discoverer function
-	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
-		// Get EC2 client for this region
-		ec2Client, err := s.ClientManager.GetEC2Client(region)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get EC2 client: %w", err)
-		}
-
-		// List instances
-		instances, err := s.listInstances(ctx, ec2Client)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list instances: %w", err)
-		}
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
 
-		// Convert to interface slice
-		resources := make([]interface{}, len(instances))
-		for i, instance := range instances {
-			resources[i] = instance
-		}
+	// Resolve the account ID once for every discovered resource
+	accountID := s.ClientManager.resolveAccountID(ctx, s.Logger)
 
-		return resources, nil
-	}
+	discoverer := s.newDiscoverer(instanceStates, s.regionalClient)
 
 	// Define the resource processor function
 	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
-		instance := resource.(types.Instance)
-
-		// Get EC2 client for the instance's region
-		region := s.getRegionFromAZ(aws.ToString(instance.Placement.AvailabilityZone))
-
-		// Get instance tags
-		tags := make(map[string]string)
-		for _, tag := range instance.Tags {
-			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
-		}
-
-		// Create resource metadata
-		metadata := ResourceMetadata{
-			ID:           aws.ToString(instance.InstanceId),
-			Type:         "ec2",
-			Provider:     "aws",
-			Region:       region,
-			DiscoveredAt: time.Now(),
-			Tags:         tags,
-			RawResponse:  instance,
-		}
-
-		// Populate extended details
-		accountID := "unknown" // EC2 instances don't have direct OwnerId field
-		metadata.Details.ARN = fmt.Sprintf("arn:aws:ec2:%s:%s:instance/%s",
-			region, accountID, aws.ToString(instance.InstanceId))
-		metadata.Details.Name = s.getInstanceName(instance)
-		metadata.Details.Status = string(instance.State.Name)
-		metadata.Details.Properties = map[string]interface{}{
-			"instance_type":     instance.InstanceType,
-			"availability_zone": instance.Placement.AvailabilityZone,
-			"launch_time":       instance.LaunchTime,
-		}
-
-		return metadata, nil
+		return s.processInstance(resource.(types.Instance), accountID), nil
 	}
 
 	// Perform the async scan
@@ -190,21 +157,119 @@ func (s *EC2Inspector) Inspect(ctx context.Context, config configuration.TaggySc
 	return result, nil
 }
 
-// listInstances retrieves all EC2 instances in a region
-func (s *EC2Inspector) listInstances(ctx context.Context, client *ec2.Client) ([]types.Instance, error) {
-	input := &ec2.DescribeInstancesInput{}
-	output, err := client.DescribeInstances(ctx, input)
+// regionalClient returns the EC2 client of a region from the client manager
+func (s *EC2Inspector) regionalClient(region string) (EC2API, error) {
+	client, err := s.ClientManager.GetEC2Client(region)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list instances: %w", err)
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe describes a few instances of the region. Their tags are part of the description.
+func (s *EC2Inspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return s.probe(ctx, region, s.regionalClient)
+}
+
+// probe issues the call of Probe with the client of clientFor
+func (s *EC2Inspector) probe(ctx context.Context, region string, clientFor ec2ClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get EC2 client: %w", err)
+	}
+
+	_, err = client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{MaxResults: aws.Int32(probeMaxResults)})
+	return probeCall("ec2:DescribeInstances", err)
+}
+
+// newDiscoverer returns a discoverer listing the instances of a region in the given states
+func (s *EC2Inspector) newDiscoverer(instanceStates []string, clientFor ec2ClientProvider) ResourceDiscoverer {
+	return func(ctx context.Context, region string) ([]interface{}, error) {
+		// Get EC2 client for this region
+		ec2Client, err := clientFor(region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get EC2 client: %w", err)
+		}
+
+		// List instances
+		instances, err := s.listInstances(ctx, ec2Client, instanceStates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instances: %w", err)
+		}
+
+		// Convert to interface slice
+		resources := make([]interface{}, len(instances))
+		for i, instance := range instances {
+			resources[i] = instance
+		}
+
+		return resources, nil
+	}
+}
+
+// listInstances pages through the EC2 instances of a region in the given states
+func (s *EC2Inspector) listInstances(ctx context.Context, client EC2API, instanceStates []string) ([]types.Instance, error) {
+	input := &ec2.DescribeInstancesInput{}
+	if len(instanceStates) > 0 {
+		input.Filters = []types.Filter{{
+			Name:   aws.String("instance-state-name"),
+			Values: instanceStates,
+		}}
 	}
 
 	var instances []types.Instance
-	for _, reservation := range output.Reservations {
-		instances = append(instances, reservation.Instances...)
+	paginator := ec2.NewDescribeInstancesPaginator(client, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instances: %w", err)
+		}
+		for _, reservation := range output.Reservations {
+			instances = append(instances, reservation.Instances...)
+		}
 	}
 	return instances, nil
 }
 
+// processInstance builds the metadata of an instance
+func (s *EC2Inspector) processInstance(instance types.Instance, accountID string) ResourceMetadata {
+	// Resolve the region of the instance from its availability zone
+	region := s.getRegionFromAZ(aws.ToString(instance.Placement.AvailabilityZone))
+
+	// Get instance tags
+	tags := make(map[string]string)
+	for _, tag := range instance.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	// Create resource metadata
+	metadata := ResourceMetadata{
+		ID:           aws.ToString(instance.InstanceId),
+		Type:         "ec2",
+		Provider:     "aws",
+		AccountID:    accountID,
+		Region:       region,
+		DiscoveredAt: time.Now(),
+		CreatedAt:    aws.ToTime(instance.LaunchTime),
+		Tags:         tags,
+		RawResponse:  instance,
+	}
+
+	// Populate extended details
+	metadata.Details.ARN = resourceARN("ec2", region, true, accountID,
+		"instance/"+aws.ToString(instance.InstanceId))
+	metadata.Details.Name = s.getInstanceName(instance)
+	metadata.Details.Status = string(instance.State.Name)
+	metadata.Details.Properties = map[string]interface{}{
+		"instance_type":     instance.InstanceType,
+		"availability_zone": instance.Placement.AvailabilityZone,
+		"launch_time":       instance.LaunchTime,
+		"vpc_id":            aws.ToString(instance.VpcId),
+	}
+
+	return metadata
+}
+
 // getInstanceName extracts the Name tag or returns a default name
 func (s *EC2Inspector) getInstanceName(instance types.Instance) string {
 	for _, tag := range instance.Tags {
@@ -259,9 +324,12 @@ func (s *EC2Inspector) Fetch(ctx context.Context, arn string, config configurati
 		ID:           instanceID,
 		Type:         "ec2",
 		Provider:     "aws",
+		AccountID:    s.ClientManager.resolveAccountID(ctx, s.Logger),
 		Region:       region,
 		Tags:         tags,
 		DiscoveredAt: time.Now(),
+		CreatedAt:    aws.ToTime(instance.LaunchTime),
+		RawResponse:  instance,
 	}
 
 	// Populate extended details
@@ -272,22 +340,23 @@ func (s *EC2Inspector) Fetch(ctx context.Context, arn string, config configurati
 		"instance_type":     instance.InstanceType,
 		"availability_zone": instance.Placement.AvailabilityZone,
 		"launch_time":       instance.LaunchTime,
+		"vpc_id":            aws.ToString(instance.VpcId),
 	}
 
 	return resourceMeta, nil
 }
 
 // ParseEC2ARN extracts instance ID and region from EC2 ARN
-func ParseEC2ARN(arn string) (string, string, error) {
-	// ARN format: arn:aws:ec2:region:account-id:instance/instance-id
-	parts := strings.Split(arn, ":")
-	if len(parts) != 6 {
-		return "", "", fmt.Errorf("invalid EC2 ARN format: %s", arn)
+func ParseEC2ARN(resourceARN string) (string, string, error) {
+	// ARN format: arn:partition:ec2:region:account-id:instance/instance-id
+	parsed, err := arn.ParseRegionalService(resourceARN, "ec2")
+	if err != nil {
+		return "", "", fmt.Errorf("invalid EC2 ARN format: %w", err)
 	}
-	region := parts[3]
-	instanceParts := strings.Split(parts[5], "/")
-	if len(instanceParts) != 2 {
-		return "", "", fmt.Errorf("invalid EC2 instance ID format in ARN: %s", arn)
+
+	instanceID, ok := parsed.ResourceName("instance")
+	if !ok {
+		return "", "", fmt.Errorf("invalid EC2 instance ID format in ARN: %s", resourceARN)
 	}
-	return instanceParts[1], region, nil
+	return instanceID, parsed.Region, nil
 }
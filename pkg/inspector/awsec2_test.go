@@ -0,0 +1,191 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockEC2Client serves the instances of its region matching the state filter, pageSize
+// reservations per page with one instance each
+type mockEC2Client struct {
+	region    string
+	instances map[string]types.InstanceStateName // instance ID -> state
+	pageSize  int
+
+	mu    *sync.Mutex
+	pages *int // number of pages served
+}
+
+func (m *mockEC2Client) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	m.mu.Lock()
+	*m.pages++
+	m.mu.Unlock()
+
+	states := map[string]bool{}
+	for _, filter := range params.Filters {
+		if aws.ToString(filter.Name) != "instance-state-name" {
+			return nil, fmt.Errorf("unexpected filter %s", aws.ToString(filter.Name))
+		}
+		for _, state := range filter.Values {
+			states[state] = true
+		}
+	}
+
+	var matching []types.Instance
+	for i := 0; i < len(m.instances); i++ {
+		id := fmt.Sprintf("i-%s-%03d", m.region, i)
+		state := m.instances[id]
+		if len(states) > 0 && !states[string(state)] {
+			continue
+		}
+		matching = append(matching, types.Instance{
+			InstanceId: aws.String(id),
+			State:      &types.InstanceState{Name: state},
+			Placement:  &types.Placement{AvailabilityZone: aws.String(m.region + "a")},
+		})
+	}
+
+	start := 0
+	if params.NextToken != nil {
+		start, _ = strconv.Atoi(*params.NextToken)
+	}
+	end := min(start+m.pageSize, len(matching))
+
+	output := &ec2.DescribeInstancesOutput{}
+	for _, instance := range matching[start:end] {
+		output.Reservations = append(output.Reservations, types.Reservation{Instances: []types.Instance{instance}})
+	}
+	if end < len(matching) {
+		output.NextToken = aws.String(strconv.Itoa(end))
+	}
+	return output, nil
+}
+
+// newMockEC2Instances returns count instances of a region, every third one terminated and
+// every fifth one stopped
+func newMockEC2Instances(region string, count int) map[string]types.InstanceStateName {
+	instances := make(map[string]types.InstanceStateName, count)
+	for i := 0; i < count; i++ {
+		state := types.InstanceStateNameRunning
+		switch {
+		case i%3 == 0:
+			state = types.InstanceStateNameTerminated
+		case i%5 == 0:
+			state = types.InstanceStateNameStopped
+		}
+		instances[fmt.Sprintf("i-%s-%03d", region, i)] = state
+	}
+	return instances
+}
+
+func TestEC2InspectorPaginatesInstances(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		resourceConfig configuration.ResourceConfig
+		expected       map[types.InstanceStateName]int // instances reported per state, per region
+	}{
+		{
+			name:     "Default States Leave Out Terminated Instances",
+			expected: map[types.InstanceStateName]int{types.InstanceStateNameRunning: 16, types.InstanceStateNameStopped: 4},
+		},
+		{
+			name:           "Configured States",
+			resourceConfig: configuration.ResourceConfig{IncludeInstanceStates: []string{"terminated"}},
+			expected:       map[types.InstanceStateName]int{types.InstanceStateNameTerminated: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			regions := []string{"us-east-1", "eu-west-1"}
+			mu := &sync.Mutex{}
+			pages := 0
+			clientFor := func(region string) (EC2API, error) {
+				return &mockEC2Client{region: region, instances: newMockEC2Instances(region, 30), pageSize: 7, mu: mu, pages: &pages}, nil
+			}
+
+			inspector := &EC2Inspector{
+				Regions: regions,
+				Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+			}
+			discoverer := inspector.newDiscoverer(tt.resourceConfig.InstanceStates(), clientFor)
+			processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+				return inspector.processInstance(resource.(types.Instance), "123456789012"), nil
+			}
+
+			resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+				InspectResourcesAsync(context.Background(), regions, discoverer, processor)
+			require.NoError(t, err)
+
+			expectedPerRegion := 0
+			for _, count := range tt.expected {
+				expectedPerRegion += count
+			}
+			require.Len(t, resources, expectedPerRegion*len(regions))
+
+			// Every page holds up to 7 reservations
+			assert.Equal(t, len(regions)*((expectedPerRegion+6)/7), pages)
+
+			counts := make(map[string]map[types.InstanceStateName]int)
+			for _, resource := range resources {
+				if counts[resource.Region] == nil {
+					counts[resource.Region] = make(map[types.InstanceStateName]int)
+				}
+				counts[resource.Region][types.InstanceStateName(resource.Details.Status)]++
+				assert.Equal(t, fmt.Sprintf("arn:aws:ec2:%s:123456789012:instance/%s", resource.Region, resource.ID), resource.Details.ARN)
+			}
+			for _, region := range regions {
+				assert.Equal(t, tt.expected, counts[region], "instances of %s", region)
+			}
+		})
+	}
+}
+
+// probedEC2Client is a mockEC2Client recording the calls of a probe
+type probedEC2Client struct {
+	*mockEC2Client
+	calls *probeCalls
+}
+
+func (c *probedEC2Client) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	if err := c.calls.call("ec2:DescribeInstances"); err != nil {
+		return nil, err
+	}
+	return c.mockEC2Client.DescribeInstances(ctx, params, optFns...)
+}
+
+func TestEC2InspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &EC2Inspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	for _, denied := range []string{"", "ec2:DescribeInstances"} {
+		calls := &probeCalls{denied: denied}
+		clientFor := func(region string) (EC2API, error) {
+			return &probedEC2Client{mockEC2Client: &mockEC2Client{region: region, instances: newMockEC2Instances(region, 3), pageSize: 5, mu: &sync.Mutex{}, pages: new(int)}, calls: calls}, nil
+		}
+
+		err := inspector.probe(context.Background(), "us-east-1", clientFor)
+		assertProbeError(t, err, denied)
+		assert.Equal(t, []string{"ec2:DescribeInstances"}, calls.made)
+	}
+}
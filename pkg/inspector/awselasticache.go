@@ -0,0 +1,454 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/arn"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+)
+
+// Kinds of ElastiCache resources, as named in their ARNs
+const (
+	elastiCacheKindCluster          = "cluster"
+	elastiCacheKindReplicationGroup = "replicationgroup"
+)
+
+// ElastiCacheClientCreator implements AWSClient for ElastiCache
+type ElastiCacheClientCreator struct{}
+
+func (c *ElastiCacheClientCreator) CreateFromConfig(cfg *aws.Config) interface{} {
+	return elasticache.NewFromConfig(*cfg)
+}
+
+// GetElastiCacheClient retrieves an ElastiCache client for the specified AWS region
+func (m *AWSClientManager) GetElastiCacheClient(region string) (*elasticache.Client, error) {
+	client, err := m.GetClient(region, &ElastiCacheClientCreator{})
+	if err != nil {
+		return nil, err
+	}
+	return client.(*elasticache.Client), nil
+}
+
+// ElastiCacheAPI is the subset of the ElastiCache client used by the ElastiCacheInspector
+type ElastiCacheAPI interface {
+	elasticache.DescribeCacheClustersAPIClient
+	elasticache.DescribeReplicationGroupsAPIClient
+	ListTagsForResource(ctx context.Context, params *elasticache.ListTagsForResourceInput, optFns ...func(*elasticache.Options)) (*elasticache.ListTagsForResourceOutput, error)
+}
+
+// elastiCacheClientProvider returns the ElastiCache client to use for a region
+type elastiCacheClientProvider func(region string) (ElastiCacheAPI, error)
+
+// ElastiCacheInspector implements the Inspector interface for ElastiCache clusters. The
+// clusters members of a replication group are reported through their replication group, so
+// a Redis deployment counts once in the compliance results.
+type ElastiCacheInspector struct {
+	Regions       []string
+	ClientManager *AWSClientManager
+	Logger        *o11y.Logger
+}
+
+// NewElastiCacheInspector creates a new inspector with AWS client management
+func NewElastiCacheInspector(regions []string) (*ElastiCacheInspector, error) {
+	// Create AWS client manager for the specified regions
+	clientManager, err := NewAWSRegionalClientManager(regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client manager: %w", err)
+	}
+
+	return &ElastiCacheInspector{
+		Regions:       regions,
+		ClientManager: clientManager,
+		Logger:        o11y.DefaultLogger(),
+	}, nil
+}
+
+// Inspect discovers ElastiCache clusters and replication groups and their tags across
+// specified regions
+func (e *ElastiCacheInspector) Inspect(ctx context.Context, config configuration.TaggyScanConfig) (*InspectResult, error) {
+	e.Logger.Info("Starting ElastiCache resource scanning",
+		"regions", e.Regions)
+
+	result := &InspectResult{
+		StartTime: time.Now(),
+		Region:    e.Regions[0],
+	}
+
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := e.ClientManager.resolveAccountID(ctx, e.Logger)
+
+	discoverer, processor := e.newScanFuncs(e.regionalClient, accountID)
+
+	// Perform the async scan
+	resources, err := scanner.InspectResourcesAsync(ctx, e.Regions, discoverer, processor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan ElastiCache resources: %w", err)
+	}
+
+	// Update result with scanned resources
+	result.Resources = resources
+	result.TotalResources = len(resources)
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+
+	e.Logger.Info("ElastiCache scanning completed",
+		"total_resources", result.TotalResources,
+		"duration", result.Duration)
+
+	return result, nil
+}
+
+// regionalClient returns the ElastiCache client of a region from the client manager
+func (e *ElastiCacheInspector) regionalClient(region string) (ElastiCacheAPI, error) {
+	client, err := e.ClientManager.GetElastiCacheClient(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe describes a few cache clusters and replication groups of the region, then reads the
+// tags of one of them
+func (e *ElastiCacheInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return e.probe(ctx, region, e.regionalClient)
+}
+
+// probe issues the calls of Probe with the clients of clientFor
+func (e *ElastiCacheInspector) probe(ctx context.Context, region string, clientFor elastiCacheClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get ElastiCache client: %w", err)
+	}
+
+	// Twenty records is the smallest page the describe calls accept
+	clusters, err := client.DescribeCacheClusters(ctx, &elasticache.DescribeCacheClustersInput{MaxRecords: aws.Int32(20)})
+	if err != nil {
+		return probeCall("elasticache:DescribeCacheClusters", err)
+	}
+	replicationGroups, err := client.DescribeReplicationGroups(ctx, &elasticache.DescribeReplicationGroupsInput{MaxRecords: aws.Int32(20)})
+	if err != nil {
+		return probeCall("elasticache:DescribeReplicationGroups", err)
+	}
+
+	var resourceARN *string
+	switch {
+	case len(replicationGroups.ReplicationGroups) > 0:
+		resourceARN = replicationGroups.ReplicationGroups[0].ARN
+	case len(clusters.CacheClusters) > 0:
+		resourceARN = clusters.CacheClusters[0].ARN
+	default:
+		return nil
+	}
+	_, err = client.ListTagsForResource(ctx, &elasticache.ListTagsForResourceInput{ResourceName: resourceARN})
+	return probeCall("elasticache:ListTagsForResource", err)
+}
+
+// newScanFuncs returns the discoverer listing the replication groups and the standalone
+// clusters of a region, and the processor reading their tags
+func (e *ElastiCacheInspector) newScanFuncs(clientFor elastiCacheClientProvider, accountID string) (ResourceDiscoverer, ResourceProcessor) {
+	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
+		client, err := clientFor(region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ElastiCache client: %w", err)
+		}
+
+		clusters, err := e.listCacheClusters(ctx, client, &elasticache.DescribeCacheClustersInput{})
+		if err != nil {
+			return nil, err
+		}
+		replicationGroups, err := e.listReplicationGroups(ctx, client, &elasticache.DescribeReplicationGroupsInput{})
+		if err != nil {
+			return nil, err
+		}
+
+		var resources []interface{}
+		for _, attributes := range dedupeElastiCacheResources(clusters, replicationGroups) {
+			resources = append(resources, RegionalResource{Region: region, Item: attributes})
+		}
+		return resources, nil
+	}
+
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		regional := resource.(RegionalResource)
+		attributes := regional.Item.(elastiCacheAttributes)
+
+		client, err := clientFor(regional.Region)
+		if err != nil {
+			return ResourceMetadata{}, fmt.Errorf("failed to get ElastiCache client: %w", err)
+		}
+
+		tags, err := e.getResourceTags(ctx, client, attributes.arn)
+		if err != nil {
+			e.Logger.Warn("Failed to get ElastiCache resource tags",
+				"arn", attributes.arn,
+				"error", err)
+			tags = make(map[string]string)
+		}
+
+		metadata := newElastiCacheMetadata(attributes, regional.Region, accountID, tags)
+		metadata.TagFetchError = tagFetchError(err)
+		return metadata, nil
+	}
+
+	return discoverer, processor
+}
+
+// listCacheClusters pages through the cache clusters matching the input
+func (e *ElastiCacheInspector) listCacheClusters(ctx context.Context, client ElastiCacheAPI, input *elasticache.DescribeCacheClustersInput) ([]types.CacheCluster, error) {
+	var clusters []types.CacheCluster
+	paginator := elasticache.NewDescribeCacheClustersPaginator(client, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cache clusters: %w", err)
+		}
+		clusters = append(clusters, output.CacheClusters...)
+	}
+	return clusters, nil
+}
+
+// listReplicationGroups pages through the replication groups matching the input
+func (e *ElastiCacheInspector) listReplicationGroups(ctx context.Context, client ElastiCacheAPI, input *elasticache.DescribeReplicationGroupsInput) ([]types.ReplicationGroup, error) {
+	var replicationGroups []types.ReplicationGroup
+	paginator := elasticache.NewDescribeReplicationGroupsPaginator(client, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list replication groups: %w", err)
+		}
+		replicationGroups = append(replicationGroups, output.ReplicationGroups...)
+	}
+	return replicationGroups, nil
+}
+
+// getResourceTags retrieves the tags of a cluster or replication group
+func (e *ElastiCacheInspector) getResourceTags(ctx context.Context, client ElastiCacheAPI, resourceARN string) (map[string]string, error) {
+	output, err := client.ListTagsForResource(ctx, &elasticache.ListTagsForResourceInput{
+		ResourceName: aws.String(resourceARN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ElastiCache resource tags: %w", err)
+	}
+
+	tags := make(map[string]string)
+	for _, tag := range output.TagList {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
+// Fetch implements the Inspector interface for retrieving a specific cluster or replication group
+func (e *ElastiCacheInspector) Fetch(ctx context.Context, arn string, config configuration.TaggyScanConfig) (*ResourceMetadata, error) {
+	return e.fetch(ctx, arn, e.regionalClient, e.ClientManager.resolveAccountID(ctx, e.Logger))
+}
+
+// fetch retrieves the cluster or replication group of an ARN with the client of its region
+func (e *ElastiCacheInspector) fetch(ctx context.Context, arn string, clientFor elastiCacheClientProvider, accountID string) (*ResourceMetadata, error) {
+	region, kind, name, err := ParseElastiCacheARN(arn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ElastiCache ARN: %w", err)
+	}
+
+	client, err := clientFor(region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ElastiCache client: %w", err)
+	}
+
+	var attributes elastiCacheAttributes
+	if kind == elastiCacheKindReplicationGroup {
+		replicationGroups, err := e.listReplicationGroups(ctx, client, &elasticache.DescribeReplicationGroupsInput{
+			ReplicationGroupId: aws.String(name),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch replication group %s: %w", name, err)
+		}
+		if len(replicationGroups) == 0 {
+			return nil, fmt.Errorf("no replication group found with ID %s", name)
+		}
+
+		// The engine version is only known to the member clusters of the group
+		var members []types.CacheCluster
+		if memberIDs := replicationGroups[0].MemberClusters; len(memberIDs) > 0 {
+			members, err = e.listCacheClusters(ctx, client, &elasticache.DescribeCacheClustersInput{
+				CacheClusterId: aws.String(memberIDs[0]),
+			})
+			if err != nil {
+				e.Logger.Warn("Failed to get member cluster of replication group",
+					"replication_group_id", name,
+					"error", err)
+			}
+		}
+		attributes = replicationGroupAttributes(replicationGroups[0], members)
+	} else {
+		clusters, err := e.listCacheClusters(ctx, client, &elasticache.DescribeCacheClustersInput{
+			CacheClusterId: aws.String(name),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch cache cluster %s: %w", name, err)
+		}
+		if len(clusters) == 0 {
+			return nil, fmt.Errorf("no cache cluster found with ID %s", name)
+		}
+		attributes = cacheClusterAttributes(clusters[0])
+	}
+
+	tags, err := e.getResourceTags(ctx, client, attributes.arn)
+	if err != nil {
+		e.Logger.Warn("Failed to get ElastiCache resource tags", "arn", attributes.arn, "error", err)
+		tags = make(map[string]string)
+	}
+
+	metadata := newElastiCacheMetadata(attributes, region, accountID, tags)
+	metadata.TagFetchError = tagFetchError(err)
+	return &metadata, nil
+}
+
+// elastiCacheAttributes are the attributes of a cluster or replication group reported in
+// its metadata
+type elastiCacheAttributes struct {
+	kind          string
+	arn           string
+	id            string
+	engine        string
+	engineVersion string
+	nodeType      string
+	numNodes      int
+	status        string
+	createdAt     *time.Time
+
+	// replicationGroupID is the replication group of a member cluster, and memberClusters
+	// the clusters of a replication group
+	replicationGroupID string
+	memberClusters     []string
+
+	// raw is the cluster or replication group as described, reported as the raw response
+	raw interface{}
+}
+
+// cacheClusterAttributes returns the attributes of a cache cluster
+func cacheClusterAttributes(cluster types.CacheCluster) elastiCacheAttributes {
+	return elastiCacheAttributes{
+		kind:               elastiCacheKindCluster,
+		arn:                aws.ToString(cluster.ARN),
+		id:                 aws.ToString(cluster.CacheClusterId),
+		engine:             aws.ToString(cluster.Engine),
+		engineVersion:      aws.ToString(cluster.EngineVersion),
+		nodeType:           aws.ToString(cluster.CacheNodeType),
+		numNodes:           int(aws.ToInt32(cluster.NumCacheNodes)),
+		status:             aws.ToString(cluster.CacheClusterStatus),
+		createdAt:          cluster.CacheClusterCreateTime,
+		replicationGroupID: aws.ToString(cluster.ReplicationGroupId),
+		raw:                cluster,
+	}
+}
+
+// replicationGroupAttributes returns the attributes of a replication group, its engine
+// version being read from the first of its member clusters found in members
+func replicationGroupAttributes(group types.ReplicationGroup, members []types.CacheCluster) elastiCacheAttributes {
+	attributes := elastiCacheAttributes{
+		kind:           elastiCacheKindReplicationGroup,
+		arn:            aws.ToString(group.ARN),
+		id:             aws.ToString(group.ReplicationGroupId),
+		engine:         aws.ToString(group.Engine),
+		nodeType:       aws.ToString(group.CacheNodeType),
+		numNodes:       len(group.MemberClusters),
+		status:         aws.ToString(group.Status),
+		createdAt:      group.ReplicationGroupCreateTime,
+		memberClusters: group.MemberClusters,
+		raw:            group,
+	}
+	for _, member := range members {
+		if aws.ToString(member.ReplicationGroupId) != attributes.id {
+			continue
+		}
+		attributes.engineVersion = aws.ToString(member.EngineVersion)
+		if attributes.engine == "" {
+			attributes.engine = aws.ToString(member.Engine)
+		}
+		break
+	}
+	return attributes
+}
+
+// dedupeElastiCacheResources returns the replication groups and the clusters that are not a
+// member of one of them. The members of a replication group are the nodes of a single
+// logical resource, which is reported once, through its replication group.
+func dedupeElastiCacheResources(clusters []types.CacheCluster, replicationGroups []types.ReplicationGroup) []elastiCacheAttributes {
+	groupIDs := make(map[string]bool, len(replicationGroups))
+	resources := make([]elastiCacheAttributes, 0, len(clusters)+len(replicationGroups))
+	for _, group := range replicationGroups {
+		groupIDs[aws.ToString(group.ReplicationGroupId)] = true
+		resources = append(resources, replicationGroupAttributes(group, clusters))
+	}
+
+	for _, cluster := range clusters {
+		if groupIDs[aws.ToString(cluster.ReplicationGroupId)] {
+			continue
+		}
+		resources = append(resources, cacheClusterAttributes(cluster))
+	}
+	return resources
+}
+
+// newElastiCacheMetadata builds the metadata of a cluster or replication group
+func newElastiCacheMetadata(attributes elastiCacheAttributes, region, accountID string, tags map[string]string) ResourceMetadata {
+	metadata := ResourceMetadata{
+		ID:           attributes.arn,
+		Type:         constants.ResourceTypeElastiCache,
+		Provider:     "aws",
+		AccountID:    accountID,
+		Region:       region,
+		DiscoveredAt: time.Now(),
+		CreatedAt:    aws.ToTime(attributes.createdAt),
+		Tags:         tags,
+		RawResponse:  attributes.raw,
+	}
+
+	metadata.Details.ARN = attributes.arn
+	metadata.Details.Name = attributes.id
+	metadata.Details.Status = attributes.status
+	metadata.Details.Properties = map[string]interface{}{
+		"kind":           attributes.kind,
+		"engine":         attributes.engine,
+		"engine_version": attributes.engineVersion,
+		"node_type":      attributes.nodeType,
+		"num_nodes":      attributes.numNodes,
+		"status":         attributes.status,
+	}
+	if attributes.replicationGroupID != "" {
+		metadata.Details.Properties["replication_group_id"] = attributes.replicationGroupID
+	}
+	if len(attributes.memberClusters) > 0 {
+		metadata.Details.Properties["member_clusters"] = attributes.memberClusters
+	}
+
+	return metadata
+}
+
+// ParseElastiCacheARN extracts the region, the kind, "cluster" or "replicationgroup", and the
+// name of the resource of an ElastiCache ARN
+func ParseElastiCacheARN(resourceARN string) (string, string, string, error) {
+	// ARN formats: arn:partition:elasticache:region:account-id:cluster:cluster-name
+	//              arn:partition:elasticache:region:account-id:replicationgroup:group-name
+	parsed, err := arn.ParseRegionalService(resourceARN, "elasticache")
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid ElastiCache ARN format: %w", err)
+	}
+
+	kind, name := parsed.ResourceTypeAndName()
+	if (kind != elastiCacheKindCluster && kind != elastiCacheKindReplicationGroup) || name == "" || strings.Contains(name, ":") {
+		return "", "", "", fmt.Errorf("invalid ElastiCache cluster or replication group in ARN: %s", resourceARN)
+	}
+	return parsed.Region, kind, name, nil
+}
@@ -0,0 +1,354 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cacheCreateTime is the creation time of the mock clusters and replication groups
+var cacheCreateTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func elastiCacheARN(kind, name string) string {
+	return fmt.Sprintf("arn:aws:elasticache:eu-west-1:123456789012:%s:%s", kind, name)
+}
+
+// mockElastiCacheClient serves the clusters and replication groups pageSize items per page,
+// and fails to read the tags of the resources whose ARN is listed in tagErrors
+type mockElastiCacheClient struct {
+	clusters          []types.CacheCluster
+	replicationGroups []types.ReplicationGroup
+	pageSize          int
+	tagErrors         map[string]bool
+
+	mu      sync.Mutex
+	tagARNs []string
+}
+
+// page returns the bounds of the page starting at marker, and the marker of the next page
+func (m *mockElastiCacheClient) page(marker *string, total int) (int, int, *string) {
+	start := 0
+	if marker != nil {
+		start, _ = strconv.Atoi(*marker)
+	}
+	end := min(start+m.pageSize, total)
+	if end < total {
+		return start, end, aws.String(strconv.Itoa(end))
+	}
+	return start, end, nil
+}
+
+func (m *mockElastiCacheClient) DescribeCacheClusters(ctx context.Context, params *elasticache.DescribeCacheClustersInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error) {
+	if params.CacheClusterId != nil {
+		for _, cluster := range m.clusters {
+			if aws.ToString(cluster.CacheClusterId) == *params.CacheClusterId {
+				return &elasticache.DescribeCacheClustersOutput{CacheClusters: []types.CacheCluster{cluster}}, nil
+			}
+		}
+		return nil, fmt.Errorf("CacheClusterNotFound: %s", *params.CacheClusterId)
+	}
+
+	start, end, marker := m.page(params.Marker, len(m.clusters))
+	return &elasticache.DescribeCacheClustersOutput{CacheClusters: m.clusters[start:end], Marker: marker}, nil
+}
+
+func (m *mockElastiCacheClient) DescribeReplicationGroups(ctx context.Context, params *elasticache.DescribeReplicationGroupsInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	if params.ReplicationGroupId != nil {
+		for _, group := range m.replicationGroups {
+			if aws.ToString(group.ReplicationGroupId) == *params.ReplicationGroupId {
+				return &elasticache.DescribeReplicationGroupsOutput{ReplicationGroups: []types.ReplicationGroup{group}}, nil
+			}
+		}
+		return nil, fmt.Errorf("ReplicationGroupNotFoundFault: %s", *params.ReplicationGroupId)
+	}
+
+	start, end, marker := m.page(params.Marker, len(m.replicationGroups))
+	return &elasticache.DescribeReplicationGroupsOutput{ReplicationGroups: m.replicationGroups[start:end], Marker: marker}, nil
+}
+
+func (m *mockElastiCacheClient) ListTagsForResource(ctx context.Context, params *elasticache.ListTagsForResourceInput, optFns ...func(*elasticache.Options)) (*elasticache.ListTagsForResourceOutput, error) {
+	resourceARN := aws.ToString(params.ResourceName)
+
+	m.mu.Lock()
+	m.tagARNs = append(m.tagARNs, resourceARN)
+	m.mu.Unlock()
+
+	if m.tagErrors[resourceARN] {
+		return nil, errors.New("access denied")
+	}
+	return &elasticache.ListTagsForResourceOutput{
+		TagList: []types.Tag{{Key: aws.String("Team"), Value: aws.String("platform")}},
+	}, nil
+}
+
+// newMockElastiCacheClient returns a client serving 4 standalone Memcached clusters and
+// 2 Redis replication groups of 3 member clusters each
+func newMockElastiCacheClient() *mockElastiCacheClient {
+	client := &mockElastiCacheClient{pageSize: 3}
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("memcached-%d", i)
+		client.clusters = append(client.clusters, types.CacheCluster{
+			ARN:                    aws.String(elastiCacheARN("cluster", name)),
+			CacheClusterId:         aws.String(name),
+			CacheClusterStatus:     aws.String("available"),
+			CacheNodeType:          aws.String("cache.t3.micro"),
+			Engine:                 aws.String("memcached"),
+			EngineVersion:          aws.String("1.6.22"),
+			NumCacheNodes:          aws.Int32(2),
+			CacheClusterCreateTime: aws.Time(cacheCreateTime),
+		})
+	}
+
+	for i := 0; i < 2; i++ {
+		groupID := fmt.Sprintf("sessions-%d", i)
+		group := types.ReplicationGroup{
+			ARN:                        aws.String(elastiCacheARN("replicationgroup", groupID)),
+			ReplicationGroupId:         aws.String(groupID),
+			Status:                     aws.String("available"),
+			CacheNodeType:              aws.String("cache.r6g.large"),
+			Engine:                     aws.String("redis"),
+			ReplicationGroupCreateTime: aws.Time(cacheCreateTime),
+		}
+		for j := 1; j <= 3; j++ {
+			name := fmt.Sprintf("%s-00%d", groupID, j)
+			group.MemberClusters = append(group.MemberClusters, name)
+			client.clusters = append(client.clusters, types.CacheCluster{
+				ARN:                    aws.String(elastiCacheARN("cluster", name)),
+				CacheClusterId:         aws.String(name),
+				CacheClusterStatus:     aws.String("available"),
+				CacheNodeType:          aws.String("cache.r6g.large"),
+				Engine:                 aws.String("redis"),
+				EngineVersion:          aws.String("7.1.0"),
+				NumCacheNodes:          aws.Int32(1),
+				ReplicationGroupId:     aws.String(groupID),
+				CacheClusterCreateTime: aws.Time(cacheCreateTime),
+			})
+		}
+		client.replicationGroups = append(client.replicationGroups, group)
+	}
+	return client
+}
+
+func newTestElastiCacheInspector() *ElastiCacheInspector {
+	return &ElastiCacheInspector{
+		Regions: []string{"eu-west-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+}
+
+func TestElastiCacheInspectorDeduplicatesReplicationGroupMembers(t *testing.T) {
+	t.Parallel()
+
+	client := newMockElastiCacheClient()
+	failingARN := elastiCacheARN("cluster", "memcached-3")
+	client.tagErrors = map[string]bool{failingARN: true}
+	clientFor := func(region string) (ElastiCacheAPI, error) {
+		return client, nil
+	}
+
+	discoverer, processor := newTestElastiCacheInspector().newScanFuncs(clientFor, "123456789012")
+
+	resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(context.Background(), []string{"eu-west-1"}, discoverer, processor)
+	require.NoError(t, err)
+
+	// The 6 member clusters are reported through their 2 replication groups
+	require.Len(t, resources, 6)
+
+	kinds := make(map[string]int)
+	for _, resource := range resources {
+		kind := resource.Details.Properties["kind"].(string)
+		kinds[kind]++
+
+		assert.Equal(t, constants.ResourceTypeElastiCache, resource.Type)
+		assert.Equal(t, "eu-west-1", resource.Region)
+		assert.Equal(t, resource.Details.ARN, resource.ID)
+		assert.Equal(t, elastiCacheARN(kind, resource.Details.Name), resource.Details.ARN)
+		assert.Equal(t, cacheCreateTime, resource.CreatedAt)
+		assert.Equal(t, "available", resource.Details.Status)
+
+		if kind == elastiCacheKindReplicationGroup {
+			assert.Equal(t, "redis", resource.Details.Properties["engine"])
+			assert.Equal(t, "7.1.0", resource.Details.Properties["engine_version"])
+			assert.Equal(t, "cache.r6g.large", resource.Details.Properties["node_type"])
+			assert.Equal(t, 3, resource.Details.Properties["num_nodes"])
+			assert.Len(t, resource.Details.Properties["member_clusters"], 3)
+		} else {
+			assert.Equal(t, "memcached", resource.Details.Properties["engine"])
+			assert.Equal(t, 2, resource.Details.Properties["num_nodes"])
+		}
+
+		if resource.Details.ARN == failingARN {
+			assert.NotEmpty(t, resource.TagFetchError)
+			assert.Empty(t, resource.Tags)
+			continue
+		}
+		assert.Empty(t, resource.TagFetchError)
+		assert.Equal(t, map[string]string{"Team": "platform"}, resource.Tags)
+	}
+	assert.Equal(t, map[string]int{elastiCacheKindCluster: 4, elastiCacheKindReplicationGroup: 2}, kinds)
+}
+
+func TestElastiCacheInspectorFetch(t *testing.T) {
+	t.Parallel()
+
+	client := newMockElastiCacheClient()
+	clientFor := func(region string) (ElastiCacheAPI, error) {
+		if region != "eu-west-1" {
+			return nil, fmt.Errorf("unexpected region %s", region)
+		}
+		return client, nil
+	}
+	inspector := newTestElastiCacheInspector()
+
+	metadata, err := inspector.fetch(context.Background(), elastiCacheARN("replicationgroup", "sessions-1"), clientFor, "123456789012")
+	require.NoError(t, err)
+	assert.Equal(t, "sessions-1", metadata.Details.Name)
+	assert.Equal(t, elastiCacheKindReplicationGroup, metadata.Details.Properties["kind"])
+	assert.Equal(t, "7.1.0", metadata.Details.Properties["engine_version"])
+	assert.Equal(t, map[string]string{"Team": "platform"}, metadata.Tags)
+
+	metadata, err = inspector.fetch(context.Background(), elastiCacheARN("cluster", "sessions-1-002"), clientFor, "123456789012")
+	require.NoError(t, err)
+	assert.Equal(t, elastiCacheKindCluster, metadata.Details.Properties["kind"])
+	assert.Equal(t, "sessions-1", metadata.Details.Properties["replication_group_id"])
+
+	_, err = inspector.fetch(context.Background(), elastiCacheARN("cluster", "missing"), clientFor, "123456789012")
+	assert.Error(t, err)
+}
+
+func TestParseElastiCacheARN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		arn    string
+		region string
+		kind   string
+		name   string
+		valid  bool
+	}{
+		{arn: "arn:aws:elasticache:us-east-1:123456789012:cluster:sessions-001", region: "us-east-1", kind: "cluster", name: "sessions-001", valid: true},
+		{arn: "arn:aws:elasticache:eu-west-1:123456789012:replicationgroup:sessions", region: "eu-west-1", kind: "replicationgroup", name: "sessions", valid: true},
+		{arn: "arn:aws:elasticache:us-east-1:123456789012:snapshot:sessions-backup"},
+		{arn: "arn:aws:elasticache:us-east-1:123456789012:cluster:"},
+		{arn: "arn:aws:rds:us-east-1:123456789012:cluster:orders"},
+		{arn: "not-an-arn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arn, func(t *testing.T) {
+			t.Parallel()
+
+			region, kind, name, err := ParseElastiCacheARN(tt.arn)
+			if !tt.valid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.region, region)
+			assert.Equal(t, tt.kind, kind)
+			assert.Equal(t, tt.name, name)
+		})
+	}
+}
+
+// probedElastiCacheClient is a mockElastiCacheClient recording the calls of a probe
+type probedElastiCacheClient struct {
+	*mockElastiCacheClient
+	calls *probeCalls
+}
+
+func (c *probedElastiCacheClient) DescribeCacheClusters(ctx context.Context, params *elasticache.DescribeCacheClustersInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error) {
+	if err := c.calls.call("elasticache:DescribeCacheClusters"); err != nil {
+		return nil, err
+	}
+	return c.mockElastiCacheClient.DescribeCacheClusters(ctx, params, optFns...)
+}
+
+func (c *probedElastiCacheClient) DescribeReplicationGroups(ctx context.Context, params *elasticache.DescribeReplicationGroupsInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	if err := c.calls.call("elasticache:DescribeReplicationGroups"); err != nil {
+		return nil, err
+	}
+	return c.mockElastiCacheClient.DescribeReplicationGroups(ctx, params, optFns...)
+}
+
+func (c *probedElastiCacheClient) ListTagsForResource(ctx context.Context, params *elasticache.ListTagsForResourceInput, optFns ...func(*elasticache.Options)) (*elasticache.ListTagsForResourceOutput, error) {
+	if err := c.calls.call("elasticache:ListTagsForResource"); err != nil {
+		return nil, err
+	}
+	return c.mockElastiCacheClient.ListTagsForResource(ctx, params, optFns...)
+}
+
+func TestElastiCacheInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &ElastiCacheInspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	describeCalls := []string{"elasticache:DescribeCacheClusters", "elasticache:DescribeReplicationGroups"}
+
+	tests := []struct {
+		name          string
+		empty         bool
+		denied        string
+		expectedCalls []string
+	}{
+		{
+			name:          "Success",
+			expectedCalls: append(describeCalls, "elasticache:ListTagsForResource"),
+		},
+		{
+			name:          "No Cache",
+			empty:         true,
+			expectedCalls: describeCalls,
+		},
+		{
+			name:          "Clusters Denied",
+			denied:        "elasticache:DescribeCacheClusters",
+			expectedCalls: []string{"elasticache:DescribeCacheClusters"},
+		},
+		{
+			name:          "Replication Groups Denied",
+			denied:        "elasticache:DescribeReplicationGroups",
+			expectedCalls: describeCalls,
+		},
+		{
+			name:          "Tags Denied",
+			denied:        "elasticache:ListTagsForResource",
+			expectedCalls: append(describeCalls, "elasticache:ListTagsForResource"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := &probeCalls{denied: tt.denied}
+			mock := newMockElastiCacheClient()
+			if tt.empty {
+				mock = &mockElastiCacheClient{pageSize: 3}
+			}
+			clientFor := func(region string) (ElastiCacheAPI, error) {
+				return &probedElastiCacheClient{mockElastiCacheClient: mock, calls: calls}, nil
+			}
+
+			err := inspector.probe(context.Background(), "us-east-1", clientFor)
+			assertProbeError(t, err, tt.denied)
+			assert.Equal(t, tt.expectedCalls, calls.made)
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/arn"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+)
+
+// TaggingClientCreator implements AWSClient for the Resource Groups Tagging API
+type TaggingClientCreator struct{}
+
+func (c *TaggingClientCreator) CreateFromConfig(cfg *aws.Config) interface{} {
+	return resourcegroupstaggingapi.NewFromConfig(*cfg)
+}
+
+// GetTaggingClient retrieves a Resource Groups Tagging API client for the specified AWS region.
+//
+// Parameters:
+//   - region: The AWS region for which to create or retrieve the client
+//
+// Returns:
+//   - *resourcegroupstaggingapi.Client: A configured Resource Groups Tagging API client
+//   - error: An error if client creation fails
+func (m *AWSClientManager) GetTaggingClient(region string) (*resourcegroupstaggingapi.Client, error) {
+	client, err := m.GetClient(region, &TaggingClientCreator{})
+	if err != nil {
+		return nil, err
+	}
+	return client.(*resourcegroupstaggingapi.Client), nil
+}
+
+// TaggingAPI is the subset of the Resource Groups Tagging API client used by the GenericInspector
+type TaggingAPI interface {
+	resourcegroupstaggingapi.GetResourcesAPIClient
+}
+
+// taggingClientProvider returns the Resource Groups Tagging API client to use for a region
+type taggingClientProvider func(region string) (TaggingAPI, error)
+
+// dedicatedResourceTypes maps the services, or service:type pairs, of resource ARNs to the
+// resource type of the dedicated inspector covering them
+var dedicatedResourceTypes = map[string]string{
+	"s3":                 constants.ResourceTypeS3,
+	"ec2:instance":       constants.ResourceTypeEC2,
+	"ec2:vpc":            constants.ResourceTypeVPC,
+	"ec2:volume":         constants.ResourceTypeEBS,
+	"ec2:snapshot":       constants.ResourceTypeEBS,
+	"ec2:security-group": constants.ResourceTypeSecurityGroup,
+	"ec2:natgateway":     constants.ResourceTypeNATGateway,
+	"logs:log-group":     constants.ResourceTypeCloudWatchLogs,
+	"cloudwatch:alarm":   constants.ResourceTypeCloudWatchAlarms,
+	"route53:hostedzone": constants.ResourceTypeRoute53,
+	"sns":                constants.ResourceTypeSNS,
+	"rds:db":             constants.ResourceTypeRDS,
+	"sqs":                constants.ResourceTypeSQS,
+
+	"cloudfront:distribution": constants.ResourceTypeCloudfront,
+	"ec2:internet-gateway":    constants.ResourceTypeInternetGateway,
+
+	"elasticache:cluster":          constants.ResourceTypeElastiCache,
+	"elasticache:replicationgroup": constants.ResourceTypeElastiCache,
+}
+
+// GenericInspector implements the Inspector interface for any taggable resource, through the
+// Resource Groups Tagging API. It only knows the ARN and tags of the resources it discovers.
+type GenericInspector struct {
+	Regions       []string
+	ClientManager *AWSClientManager
+	Logger        *o11y.Logger
+}
+
+// NewGenericInspector creates a new inspector with AWS client management
+func NewGenericInspector(regions []string) (*GenericInspector, error) {
+	// Create AWS client manager for the specified regions
+	clientManager, err := NewAWSRegionalClientManager(regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client manager: %w", err)
+	}
+
+	return &GenericInspector{
+		Regions:       regions,
+		ClientManager: clientManager,
+		Logger:        o11y.DefaultLogger(),
+	}, nil
+}
+
+// Inspect discovers the taggable resources of the configured resource types across the
+// specified regions. Resources covered by an enabled dedicated inspector are skipped.
+func (g *GenericInspector) Inspect(ctx context.Context, config configuration.TaggyScanConfig) (*InspectResult, error) {
+	resourceTypeFilters := config.Resources[constants.ResourceTypeGeneric].ResourceTypeFilters
+
+	g.Logger.Info("Starting generic resource scanning",
+		"regions", g.Regions,
+		"resource_type_filters", resourceTypeFilters)
+
+	result := &InspectResult{
+		StartTime: time.Now(),
+		Region:    g.Regions[0],
+	}
+
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	discoverer := g.newDiscoverer(config, resourceTypeFilters, g.regionalClient)
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		return g.processMapping(resource.(RegionalResource))
+	}
+
+	// Perform the async scan
+	resources, err := scanner.InspectResourcesAsync(ctx, g.Regions, discoverer, processor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan generic resources: %w", err)
+	}
+
+	// Update result with scanned resources
+	result.Resources = resources
+	result.TotalResources = len(resources)
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+
+	g.Logger.Info("Generic scanning completed",
+		"total_resources", result.TotalResources,
+		"duration", result.Duration)
+
+	return result, nil
+}
+
+// regionalClient returns the Resource Groups Tagging API client of a region from the client manager
+func (g *GenericInspector) regionalClient(region string) (TaggingAPI, error) {
+	client, err := g.ClientManager.GetTaggingClient(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe reads a single resource of the region matching the resource type filters. Its tags
+// are part of the response.
+func (g *GenericInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return g.probe(ctx, region, config.Resources[constants.ResourceTypeGeneric].ResourceTypeFilters, g.regionalClient)
+}
+
+// probe issues the call of Probe with the client of clientFor
+func (g *GenericInspector) probe(ctx context.Context, region string, resourceTypeFilters []string, clientFor taggingClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get Resource Groups Tagging API client: %w", err)
+	}
+
+	_, err = client.GetResources(ctx, &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: resourceTypeFilters,
+		ResourcesPerPage:    aws.Int32(1),
+	})
+	return probeCall("tag:GetResources", err)
+}
+
+// newDiscoverer returns a discoverer listing the resources of a region through GetResources.
+// Global resources, reported by every region, are only kept once.
+func (g *GenericInspector) newDiscoverer(config configuration.TaggyScanConfig, resourceTypeFilters []string, clientFor taggingClientProvider) ResourceDiscoverer {
+	var seen sync.Map
+
+	return func(ctx context.Context, region string) ([]interface{}, error) {
+		client, err := clientFor(region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Resource Groups Tagging API client: %w", err)
+		}
+
+		mappings, err := g.getResources(ctx, client, resourceTypeFilters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resources: %w", err)
+		}
+
+		var resources []interface{}
+		for _, mapping := range mappings {
+			resourceARN := aws.ToString(mapping.ResourceARN)
+			if dedicated := DedicatedResourceType(resourceARN); dedicated != "" && config.Resources[dedicated].Enabled {
+				continue
+			}
+			if _, duplicate := seen.LoadOrStore(resourceARN, struct{}{}); duplicate {
+				continue
+			}
+			resources = append(resources, RegionalResource{Region: region, Item: mapping})
+		}
+
+		return resources, nil
+	}
+}
+
+// getResources pages through the resources of a region matching the resource type filters
+func (g *GenericInspector) getResources(ctx context.Context, client TaggingAPI, resourceTypeFilters []string) ([]types.ResourceTagMapping, error) {
+	var mappings []types.ResourceTagMapping
+	paginator := resourcegroupstaggingapi.NewGetResourcesPaginator(client, &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: resourceTypeFilters,
+		ResourcesPerPage:    aws.Int32(100),
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, output.ResourceTagMappingList...)
+	}
+
+	return mappings, nil
+}
+
+// processMapping builds the metadata of a resource from its ARN and tags
+func (g *GenericInspector) processMapping(resource RegionalResource) (ResourceMetadata, error) {
+	mapping := resource.Item.(types.ResourceTagMapping)
+	resourceARN := aws.ToString(mapping.ResourceARN)
+
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return ResourceMetadata{}, fmt.Errorf("failed to parse resource ARN %s: %w", resourceARN, err)
+	}
+
+	// Global resources have no region in their ARN
+	region := parsed.Region
+	if region == "" {
+		region = resource.Region
+	}
+
+	tags := make(map[string]string, len(mapping.Tags))
+	for _, tag := range mapping.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	resourceType, name := parsed.ResourceTypeAndName()
+
+	metadata := ResourceMetadata{
+		ID:           resourceARN,
+		Type:         constants.ResourceTypeGeneric,
+		Provider:     "aws",
+		AccountID:    parsed.AccountID,
+		Region:       region,
+		DiscoveredAt: time.Now(),
+		Tags:         tags,
+		RawResponse:  mapping,
+	}
+
+	// Populate extended details, only the ARN and tags are known to the Tagging API
+	metadata.Details.ARN = resourceARN
+	metadata.Details.Name = name
+	metadata.Details.Properties = map[string]interface{}{
+		"service":                       parsed.Service,
+		"resource_type":                 resourceType,
+		"detailed_attributes_available": false,
+	}
+
+	return metadata, nil
+}
+
+// Fetch implements the Scanner interface for retrieving the tags of a specific resource
+func (g *GenericInspector) Fetch(ctx context.Context, resourceARN string, config configuration.TaggyScanConfig) (*ResourceMetadata, error) {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse resource ARN: %w", err)
+	}
+
+	region := parsed.Region
+	if region == "" {
+		region = g.Regions[0]
+	}
+
+	client, err := g.ClientManager.GetTaggingClient(region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Resource Groups Tagging API client: %w", err)
+	}
+
+	output, err := client.GetResources(ctx, &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceARNList: []string{resourceARN},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource %s: %w", resourceARN, err)
+	}
+	if len(output.ResourceTagMappingList) == 0 {
+		return nil, fmt.Errorf("resource %s not found", resourceARN)
+	}
+
+	metadata, err := g.processMapping(RegionalResource{Region: region, Item: output.ResourceTagMappingList[0]})
+	if err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// DedicatedResourceType returns the resource type of the dedicated inspector covering the
+// resource of an ARN, or an empty string when only the generic inspector covers it
+func DedicatedResourceType(resourceARN string) string {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return ""
+	}
+
+	// Only the APIs are covered by the API Gateway inspector, not their stages or routes
+	if parsed.Service == "apigateway" {
+		if _, _, _, err := ParseAPIGatewayARN(resourceARN); err == nil {
+			return constants.ResourceTypeAPIGateway
+		}
+		return ""
+	}
+
+	resourceType, _ := parsed.ResourceTypeAndName()
+	if dedicated, ok := dedicatedResourceTypes[parsed.Service+":"+resourceType]; ok {
+		return dedicated
+	}
+	return dedicatedResourceTypes[parsed.Service]
+}
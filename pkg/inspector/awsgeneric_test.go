@@ -0,0 +1,198 @@
+package inspector
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTaggingClient serves the resources of its region one per page
+type mockTaggingClient struct {
+	arns []string
+
+	mu      *sync.Mutex
+	filters *[]string // resource type filters of the last request
+}
+
+func (m *mockTaggingClient) GetResources(ctx context.Context, params *resourcegroupstaggingapi.GetResourcesInput, optFns ...func(*resourcegroupstaggingapi.Options)) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+	m.mu.Lock()
+	*m.filters = params.ResourceTypeFilters
+	m.mu.Unlock()
+
+	page := 0
+	if params.PaginationToken != nil {
+		page, _ = strconv.Atoi(*params.PaginationToken)
+	}
+
+	output := &resourcegroupstaggingapi.GetResourcesOutput{}
+	if page < len(m.arns) {
+		output.ResourceTagMappingList = []types.ResourceTagMapping{{
+			ResourceARN: aws.String(m.arns[page]),
+			Tags:        []types.Tag{{Key: aws.String("Owner"), Value: aws.String("platform")}},
+		}}
+	}
+	if page+1 < len(m.arns) {
+		output.PaginationToken = aws.String(strconv.Itoa(page + 1))
+	}
+	return output, nil
+}
+
+func TestGenericInspectorDiscoversTaggableResources(t *testing.T) {
+	t.Parallel()
+
+	arnsByRegion := map[string][]string{
+		"us-east-1": {
+			"arn:aws:kinesis:us-east-1:123456789012:stream/orders",
+			"arn:aws:sns:us-east-1:123456789012:alerts",
+			"arn:aws:cloudfront::123456789012:distribution/E2QWRUHEXAMPLE",
+		},
+		"eu-west-1": {
+			"arn:aws:states:eu-west-1:123456789012:stateMachine:billing",
+			"arn:aws:cloudfront::123456789012:distribution/E2QWRUHEXAMPLE",
+		},
+	}
+
+	mu := &sync.Mutex{}
+	var filters []string
+	clientFor := func(region string) (TaggingAPI, error) {
+		return &mockTaggingClient{arns: arnsByRegion[region], mu: mu, filters: &filters}, nil
+	}
+
+	config := configuration.TaggyScanConfig{
+		Resources: map[string]configuration.ResourceConfig{
+			constants.ResourceTypeGeneric: {Enabled: true, ResourceTypeFilters: []string{"kinesis", "states", "sns", "cloudfront"}},
+			constants.ResourceTypeSNS:     {Enabled: true},
+		},
+	}
+
+	inspector := &GenericInspector{
+		Regions: []string{"us-east-1", "eu-west-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	discoverer := inspector.newDiscoverer(config, config.Resources[constants.ResourceTypeGeneric].ResourceTypeFilters, clientFor)
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		return inspector.processMapping(resource.(RegionalResource))
+	}
+
+	resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(context.Background(), inspector.Regions, discoverer, processor)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kinesis", "states", "sns", "cloudfront"}, filters)
+
+	// The SNS topic is left to the enabled SNS inspector, the distribution is reported once
+	sort.Slice(resources, func(i, j int) bool { return resources[i].ID < resources[j].ID })
+	require.Len(t, resources, 3)
+
+	distribution, stream, stateMachine := resources[0], resources[1], resources[2]
+	assert.Equal(t, "arn:aws:cloudfront::123456789012:distribution/E2QWRUHEXAMPLE", distribution.ID)
+	assert.Contains(t, []string{"us-east-1", "eu-west-1"}, distribution.Region)
+
+	assert.Equal(t, constants.ResourceTypeGeneric, stream.Type)
+	assert.Equal(t, "us-east-1", stream.Region)
+	assert.Equal(t, "123456789012", stream.AccountID)
+	assert.Equal(t, "orders", stream.Details.Name)
+	assert.Equal(t, map[string]string{"Owner": "platform"}, stream.Tags)
+	assert.Equal(t, map[string]interface{}{
+		"service":                       "kinesis",
+		"resource_type":                 "stream",
+		"detailed_attributes_available": false,
+	}, stream.Details.Properties)
+
+	assert.Equal(t, "eu-west-1", stateMachine.Region)
+	assert.Equal(t, "billing", stateMachine.Details.Name)
+	assert.Equal(t, "stateMachine", stateMachine.Details.Properties["resource_type"])
+}
+
+func TestDedicatedResourceType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		arn      string
+		expected string
+	}{
+		{arn: "arn:aws:s3:::my-bucket", expected: constants.ResourceTypeS3},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:instance/i-0abc", expected: constants.ResourceTypeEC2},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0abc", expected: constants.ResourceTypeVPC},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:volume/vol-0abc", expected: constants.ResourceTypeEBS},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:snapshot/snap-0abc", expected: constants.ResourceTypeEBS},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:security-group/sg-0abc", expected: constants.ResourceTypeSecurityGroup},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:natgateway/nat-0abc", expected: constants.ResourceTypeNATGateway},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:internet-gateway/igw-0abc", expected: constants.ResourceTypeInternetGateway},
+		{arn: "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-0abc", expected: ""},
+		{arn: "arn:aws:logs:us-east-1:123456789012:log-group:/aws/lambda/orders", expected: constants.ResourceTypeCloudWatchLogs},
+		{arn: "arn:aws:cloudwatch:us-east-1:123456789012:alarm:cpu-high", expected: constants.ResourceTypeCloudWatchAlarms},
+		{arn: "arn:aws:rds:us-east-1:123456789012:db:orders", expected: constants.ResourceTypeRDS},
+		{arn: "arn:aws:rds:us-east-1:123456789012:cluster:orders", expected: ""},
+		{arn: "arn:aws:sqs:us-east-1:123456789012:orders", expected: constants.ResourceTypeSQS},
+		{arn: "arn:aws:kinesis:us-east-1:123456789012:stream/orders", expected: ""},
+		{arn: "arn:aws:apigateway:us-east-1::/restapis/a1b2c3", expected: constants.ResourceTypeAPIGateway},
+		{arn: "arn:aws:apigateway:us-east-1::/apis/d4e5f6", expected: constants.ResourceTypeAPIGateway},
+		{arn: "arn:aws:apigateway:us-east-1::/restapis/a1b2c3/stages/prod", expected: ""},
+		{arn: "arn:aws:elasticache:us-east-1:123456789012:cluster:sessions", expected: constants.ResourceTypeElastiCache},
+		{arn: "arn:aws:elasticache:us-east-1:123456789012:replicationgroup:sessions", expected: constants.ResourceTypeElastiCache},
+		{arn: "arn:aws:elasticache:us-east-1:123456789012:snapshot:sessions-backup", expected: ""},
+		{arn: "not-an-arn", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arn, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, DedicatedResourceType(tt.arn))
+		})
+	}
+}
+
+// probedTaggingClient is a mockTaggingClient recording the calls of a probe
+type probedTaggingClient struct {
+	*mockTaggingClient
+	calls *probeCalls
+}
+
+func (c *probedTaggingClient) GetResources(ctx context.Context, params *resourcegroupstaggingapi.GetResourcesInput, optFns ...func(*resourcegroupstaggingapi.Options)) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+	if err := c.calls.call("tag:GetResources"); err != nil {
+		return nil, err
+	}
+	return c.mockTaggingClient.GetResources(ctx, params, optFns...)
+}
+
+func TestGenericInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &GenericInspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	resourceTypeFilters := []string{"lambda:function", "dynamodb:table"}
+
+	for _, denied := range []string{"", "tag:GetResources"} {
+		calls := &probeCalls{denied: denied}
+		var filters []string
+		mock := &mockTaggingClient{
+			arns:    []string{"arn:aws:lambda:us-east-1:123456789012:function:orders"},
+			mu:      &sync.Mutex{},
+			filters: &filters,
+		}
+		clientFor := func(region string) (TaggingAPI, error) {
+			return &probedTaggingClient{mockTaggingClient: mock, calls: calls}, nil
+		}
+
+		err := inspector.probe(context.Background(), "us-east-1", resourceTypeFilters, clientFor)
+		assertProbeError(t, err, denied)
+		assert.Equal(t, []string{"tag:GetResources"}, calls.made)
+		if denied == "" {
+			assert.Equal(t, resourceTypeFilters, filters, "the probe lists the resource types of the scan")
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/arn"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// InternetGatewayAPI is the subset of the EC2 client used to discover internet gateways
+type InternetGatewayAPI interface {
+	ec2.DescribeInternetGatewaysAPIClient
+}
+
+// internetGatewayClientProvider returns the EC2 client to use for a region
+type internetGatewayClientProvider func(region string) (InternetGatewayAPI, error)
+
+// InternetGatewayInspector implements the Inspector interface for internet gateways
+type InternetGatewayInspector struct {
+	Regions       []string
+	ClientManager *AWSClientManager
+	Logger        *o11y.Logger
+}
+
+// NewInternetGatewayInspector creates a new inspector with AWS client management
+func NewInternetGatewayInspector(regions []string) (*InternetGatewayInspector, error) {
+	// Create AWS client manager for the specified regions
+	clientManager, err := NewAWSRegionalClientManager(regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client manager: %w", err)
+	}
+
+	return &InternetGatewayInspector{
+		Regions:       regions,
+		ClientManager: clientManager,
+		Logger:        o11y.DefaultLogger(),
+	}, nil
+}
+
+// Inspect discovers internet gateways and their tags across specified regions
+func (g *InternetGatewayInspector) Inspect(ctx context.Context, config configuration.TaggyScanConfig) (*InspectResult, error) {
+	g.Logger.Info("Starting internet gateway resource scanning",
+		"regions", g.Regions)
+
+	result := &InspectResult{
+		StartTime: time.Now(),
+		Region:    g.Regions[0],
+	}
+
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := g.ClientManager.resolveAccountID(ctx, g.Logger)
+
+	discoverer, processor := g.newScanFuncs(g.regionalClient, accountID)
+
+	// Perform the async scan
+	resources, err := scanner.InspectResourcesAsync(ctx, g.Regions, discoverer, processor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan internet gateway resources: %w", err)
+	}
+
+	// Update result with scanned resources
+	result.Resources = resources
+	result.TotalResources = len(resources)
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+
+	g.Logger.Info("Internet gateway scanning completed",
+		"total_resources", result.TotalResources,
+		"duration", result.Duration)
+
+	return result, nil
+}
+
+// regionalClient returns the EC2 client of a region from the client manager
+func (g *InternetGatewayInspector) regionalClient(region string) (InternetGatewayAPI, error) {
+	client, err := g.ClientManager.GetEC2Client(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe describes a few internet gateways of the region. Their tags are part of the description.
+func (g *InternetGatewayInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return g.probe(ctx, region, g.regionalClient)
+}
+
+// probe issues the call of Probe with the client of clientFor
+func (g *InternetGatewayInspector) probe(ctx context.Context, region string, clientFor internetGatewayClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get EC2 client: %w", err)
+	}
+
+	_, err = client.DescribeInternetGateways(ctx, &ec2.DescribeInternetGatewaysInput{MaxResults: aws.Int32(probeMaxResults)})
+	return probeCall("ec2:DescribeInternetGateways", err)
+}
+
+// newScanFuncs returns the discoverer listing the internet gateways of a region, and the
+// processor building their metadata from the tags returned by the listing
+func (g *InternetGatewayInspector) newScanFuncs(clientFor internetGatewayClientProvider, accountID string) (ResourceDiscoverer, ResourceProcessor) {
+	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
+		client, err := clientFor(region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get EC2 client: %w", err)
+		}
+
+		gateways, err := g.listInternetGateways(ctx, client, &ec2.DescribeInternetGatewaysInput{})
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]interface{}, len(gateways))
+		for i, gateway := range gateways {
+			resources[i] = RegionalResource{Region: region, Item: gateway}
+		}
+		return resources, nil
+	}
+
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		regional := resource.(RegionalResource)
+		return newInternetGatewayMetadata(regional.Item.(types.InternetGateway), regional.Region, accountID), nil
+	}
+
+	return discoverer, processor
+}
+
+// listInternetGateways pages through the internet gateways matching the input
+func (g *InternetGatewayInspector) listInternetGateways(ctx context.Context, client InternetGatewayAPI, input *ec2.DescribeInternetGatewaysInput) ([]types.InternetGateway, error) {
+	var gateways []types.InternetGateway
+	paginator := ec2.NewDescribeInternetGatewaysPaginator(client, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list internet gateways: %w", err)
+		}
+		gateways = append(gateways, output.InternetGateways...)
+	}
+	return gateways, nil
+}
+
+// Fetch implements the Inspector interface for retrieving a specific internet gateway
+func (g *InternetGatewayInspector) Fetch(ctx context.Context, arn string, config configuration.TaggyScanConfig) (*ResourceMetadata, error) {
+	return g.fetch(ctx, arn, g.regionalClient, g.ClientManager.resolveAccountID(ctx, g.Logger))
+}
+
+// fetch retrieves the internet gateway of an ARN with the client of its region
+func (g *InternetGatewayInspector) fetch(ctx context.Context, arn string, clientFor internetGatewayClientProvider, accountID string) (*ResourceMetadata, error) {
+	gatewayID, region, err := ParseInternetGatewayARN(arn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse internet gateway ARN: %w", err)
+	}
+
+	client, err := clientFor(region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create EC2 client: %w", err)
+	}
+
+	gateways, err := g.listInternetGateways(ctx, client, &ec2.DescribeInternetGatewaysInput{InternetGatewayIds: []string{gatewayID}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch internet gateway: %w", err)
+	}
+	if len(gateways) == 0 {
+		return nil, fmt.Errorf("no internet gateway found with ID %s", gatewayID)
+	}
+
+	metadata := newInternetGatewayMetadata(gateways[0], region, accountID)
+	return &metadata, nil
+}
+
+// newInternetGatewayMetadata builds the metadata of an internet gateway. The gateway is
+// reported in the account owning it, the account of the scan when the owner is unknown.
+func newInternetGatewayMetadata(gateway types.InternetGateway, region, accountID string) ResourceMetadata {
+	if ownerID := aws.ToString(gateway.OwnerId); ownerID != "" {
+		accountID = ownerID
+	}
+	gatewayID := aws.ToString(gateway.InternetGatewayId)
+
+	attachedVPCs := make([]string, 0, len(gateway.Attachments))
+	for _, attachment := range gateway.Attachments {
+		attachedVPCs = append(attachedVPCs, aws.ToString(attachment.VpcId))
+	}
+
+	metadata := ResourceMetadata{
+		ID:           gatewayID,
+		Type:         constants.ResourceTypeInternetGateway,
+		Provider:     "aws",
+		AccountID:    accountID,
+		Region:       region,
+		DiscoveredAt: time.Now(),
+		Tags:         ec2TagMap(gateway.Tags),
+		RawResponse:  gateway,
+	}
+
+	metadata.Details.ARN = resourceARN("ec2", region, true, accountID, "internet-gateway/"+gatewayID)
+	metadata.Details.Name = ec2ResourceName(gateway.Tags, gatewayID)
+	metadata.Details.Properties = map[string]interface{}{
+		"attached_vpcs": attachedVPCs,
+		"attached":      len(attachedVPCs) > 0,
+	}
+
+	return metadata
+}
+
+// ParseInternetGatewayARN extracts the internet gateway ID and region from an internet
+// gateway ARN
+func ParseInternetGatewayARN(resourceARN string) (string, string, error) {
+	// ARN format: arn:partition:ec2:region:account-id:internet-gateway/igw-id
+	parsed, err := arn.ParseRegionalService(resourceARN, "ec2")
+	if err != nil {
+		return "", "", fmt.Errorf("invalid internet gateway ARN format: %w", err)
+	}
+
+	gatewayID, ok := parsed.ResourceName("internet-gateway")
+	if !ok || !strings.HasPrefix(gatewayID, "igw-") {
+		return "", "", fmt.Errorf("invalid internet gateway ID in ARN: %s", resourceARN)
+	}
+	return gatewayID, parsed.Region, nil
+}
@@ -0,0 +1,153 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockInternetGatewayClient serves the internet gateways pageSize items per page
+type mockInternetGatewayClient struct {
+	gateways []types.InternetGateway
+	pageSize int
+}
+
+func (m *mockInternetGatewayClient) DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error) {
+	if len(params.InternetGatewayIds) > 0 {
+		output := &ec2.DescribeInternetGatewaysOutput{}
+		for _, gateway := range m.gateways {
+			if aws.ToString(gateway.InternetGatewayId) == params.InternetGatewayIds[0] {
+				output.InternetGateways = append(output.InternetGateways, gateway)
+			}
+		}
+		return output, nil
+	}
+
+	start := 0
+	if params.NextToken != nil {
+		start, _ = strconv.Atoi(*params.NextToken)
+	}
+	end := min(start+m.pageSize, len(m.gateways))
+
+	output := &ec2.DescribeInternetGatewaysOutput{InternetGateways: m.gateways[start:end]}
+	if end < len(m.gateways) {
+		output.NextToken = aws.String(strconv.Itoa(end))
+	}
+	return output, nil
+}
+
+// newMockInternetGateways returns 3 gateways attached to a VPC and a detached one
+func newMockInternetGateways() []types.InternetGateway {
+	var gateways []types.InternetGateway
+	for i := 0; i < 3; i++ {
+		gateways = append(gateways, types.InternetGateway{
+			InternetGatewayId: aws.String(fmt.Sprintf("igw-%04d", i)),
+			OwnerId:           aws.String("123456789012"),
+			Attachments: []types.InternetGatewayAttachment{{
+				VpcId: aws.String(fmt.Sprintf("vpc-%04d", i)),
+				State: types.AttachmentStatusAttached,
+			}},
+			Tags: []types.Tag{{Key: aws.String("CostCenter"), Value: aws.String("networking")}},
+		})
+	}
+	return append(gateways, types.InternetGateway{
+		InternetGatewayId: aws.String("igw-detached"),
+		OwnerId:           aws.String("123456789012"),
+	})
+}
+
+func TestInternetGatewayInspectorDiscoversGateways(t *testing.T) {
+	t.Parallel()
+
+	client := &mockInternetGatewayClient{gateways: newMockInternetGateways(), pageSize: 3}
+	clientFor := func(region string) (InternetGatewayAPI, error) {
+		return client, nil
+	}
+
+	inspector := &InternetGatewayInspector{
+		Regions: []string{"eu-west-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	discoverer, processor := inspector.newScanFuncs(clientFor, "123456789012")
+
+	resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(context.Background(), []string{"eu-west-1"}, discoverer, processor)
+	require.NoError(t, err)
+	require.Len(t, resources, 4)
+
+	for _, resource := range resources {
+		assert.Equal(t, constants.ResourceTypeInternetGateway, resource.Type)
+		assert.Equal(t, "arn:aws:ec2:eu-west-1:123456789012:internet-gateway/"+resource.ID, resource.Details.ARN)
+		assert.Equal(t, resource.ID, resource.Details.Name)
+
+		if resource.ID == "igw-detached" {
+			assert.Equal(t, false, resource.Details.Properties["attached"])
+			assert.Empty(t, resource.Details.Properties["attached_vpcs"])
+			assert.Empty(t, resource.Tags)
+			continue
+		}
+		assert.Equal(t, true, resource.Details.Properties["attached"])
+		assert.Equal(t, []string{"vpc-" + resource.ID[len("igw-"):]}, resource.Details.Properties["attached_vpcs"])
+		assert.Equal(t, map[string]string{"CostCenter": "networking"}, resource.Tags)
+	}
+}
+
+func TestInternetGatewayInspectorFetch(t *testing.T) {
+	t.Parallel()
+
+	client := &mockInternetGatewayClient{gateways: newMockInternetGateways(), pageSize: 3}
+	clientFor := func(region string) (InternetGatewayAPI, error) {
+		return client, nil
+	}
+	inspector := &InternetGatewayInspector{Logger: o11y.NewLogger(io.Discard, o11y.LogLevelError)}
+
+	metadata, err := inspector.fetch(context.Background(), "arn:aws:ec2:eu-west-1:123456789012:internet-gateway/igw-0002", clientFor, "123456789012")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vpc-0002"}, metadata.Details.Properties["attached_vpcs"])
+
+	_, _, err = ParseInternetGatewayARN("arn:aws:ec2:eu-west-1:123456789012:natgateway/nat-0abc")
+	assert.Error(t, err)
+}
+
+// probedInternetGatewayClient is a mockInternetGatewayClient recording the calls of a probe
+type probedInternetGatewayClient struct {
+	*mockInternetGatewayClient
+	calls *probeCalls
+}
+
+func (c *probedInternetGatewayClient) DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error) {
+	if err := c.calls.call("ec2:DescribeInternetGateways"); err != nil {
+		return nil, err
+	}
+	return c.mockInternetGatewayClient.DescribeInternetGateways(ctx, params, optFns...)
+}
+
+func TestInternetGatewayInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &InternetGatewayInspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	for _, denied := range []string{"", "ec2:DescribeInternetGateways"} {
+		calls := &probeCalls{denied: denied}
+		clientFor := func(region string) (InternetGatewayAPI, error) {
+			return &probedInternetGatewayClient{mockInternetGatewayClient: &mockInternetGatewayClient{gateways: newMockInternetGateways(), pageSize: 5}, calls: calls}, nil
+		}
+
+		err := inspector.probe(context.Background(), "us-east-1", clientFor)
+		assertProbeError(t, err, denied)
+		assert.Equal(t, []string{"ec2:DescribeInternetGateways"}, calls.made)
+	}
+}
@@ -0,0 +1,240 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/arn"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// NATGatewayAPI is the subset of the EC2 client used to discover NAT gateways
+type NATGatewayAPI interface {
+	ec2.DescribeNatGatewaysAPIClient
+}
+
+// natGatewayClientProvider returns the EC2 client to use for a region
+type natGatewayClientProvider func(region string) (NATGatewayAPI, error)
+
+// NATGatewayInspector implements the Inspector interface for NAT gateways. Deleted gateways,
+// still listed for a while after their deletion, are left out.
+type NATGatewayInspector struct {
+	Regions       []string
+	ClientManager *AWSClientManager
+	Logger        *o11y.Logger
+}
+
+// NewNATGatewayInspector creates a new inspector with AWS client management
+func NewNATGatewayInspector(regions []string) (*NATGatewayInspector, error) {
+	// Create AWS client manager for the specified regions
+	clientManager, err := NewAWSRegionalClientManager(regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client manager: %w", err)
+	}
+
+	return &NATGatewayInspector{
+		Regions:       regions,
+		ClientManager: clientManager,
+		Logger:        o11y.DefaultLogger(),
+	}, nil
+}
+
+// Inspect discovers NAT gateways and their tags across specified regions
+func (n *NATGatewayInspector) Inspect(ctx context.Context, config configuration.TaggyScanConfig) (*InspectResult, error) {
+	n.Logger.Info("Starting NAT gateway resource scanning",
+		"regions", n.Regions)
+
+	result := &InspectResult{
+		StartTime: time.Now(),
+		Region:    n.Regions[0],
+	}
+
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := n.ClientManager.resolveAccountID(ctx, n.Logger)
+
+	discoverer, processor := n.newScanFuncs(n.regionalClient, accountID)
+
+	// Perform the async scan
+	resources, err := scanner.InspectResourcesAsync(ctx, n.Regions, discoverer, processor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan NAT gateway resources: %w", err)
+	}
+
+	// Update result with scanned resources
+	result.Resources = resources
+	result.TotalResources = len(resources)
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+
+	n.Logger.Info("NAT gateway scanning completed",
+		"total_resources", result.TotalResources,
+		"duration", result.Duration)
+
+	return result, nil
+}
+
+// regionalClient returns the EC2 client of a region from the client manager
+func (n *NATGatewayInspector) regionalClient(region string) (NATGatewayAPI, error) {
+	client, err := n.ClientManager.GetEC2Client(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe describes a few NAT gateways of the region. Their tags are part of the description.
+func (n *NATGatewayInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return n.probe(ctx, region, n.regionalClient)
+}
+
+// probe issues the call of Probe with the client of clientFor
+func (n *NATGatewayInspector) probe(ctx context.Context, region string, clientFor natGatewayClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get EC2 client: %w", err)
+	}
+
+	_, err = client.DescribeNatGateways(ctx, &ec2.DescribeNatGatewaysInput{MaxResults: aws.Int32(probeMaxResults)})
+	return probeCall("ec2:DescribeNatGateways", err)
+}
+
+// newScanFuncs returns the discoverer listing the NAT gateways of a region, and the
+// processor building their metadata from the tags returned by the listing
+func (n *NATGatewayInspector) newScanFuncs(clientFor natGatewayClientProvider, accountID string) (ResourceDiscoverer, ResourceProcessor) {
+	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
+		client, err := clientFor(region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get EC2 client: %w", err)
+		}
+
+		gateways, err := n.listNATGateways(ctx, client, &ec2.DescribeNatGatewaysInput{})
+		if err != nil {
+			return nil, err
+		}
+
+		var resources []interface{}
+		for _, gateway := range gateways {
+			if gateway.State == types.NatGatewayStateDeleted {
+				continue
+			}
+			resources = append(resources, RegionalResource{Region: region, Item: gateway})
+		}
+		return resources, nil
+	}
+
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		regional := resource.(RegionalResource)
+		return newNATGatewayMetadata(regional.Item.(types.NatGateway), regional.Region, accountID), nil
+	}
+
+	return discoverer, processor
+}
+
+// listNATGateways pages through the NAT gateways matching the input
+func (n *NATGatewayInspector) listNATGateways(ctx context.Context, client NATGatewayAPI, input *ec2.DescribeNatGatewaysInput) ([]types.NatGateway, error) {
+	var gateways []types.NatGateway
+	paginator := ec2.NewDescribeNatGatewaysPaginator(client, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list NAT gateways: %w", err)
+		}
+		gateways = append(gateways, output.NatGateways...)
+	}
+	return gateways, nil
+}
+
+// Fetch implements the Inspector interface for retrieving a specific NAT gateway
+func (n *NATGatewayInspector) Fetch(ctx context.Context, arn string, config configuration.TaggyScanConfig) (*ResourceMetadata, error) {
+	return n.fetch(ctx, arn, n.regionalClient, n.ClientManager.resolveAccountID(ctx, n.Logger))
+}
+
+// fetch retrieves the NAT gateway of an ARN with the client of its region
+func (n *NATGatewayInspector) fetch(ctx context.Context, arn string, clientFor natGatewayClientProvider, accountID string) (*ResourceMetadata, error) {
+	gatewayID, region, err := ParseNATGatewayARN(arn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse NAT gateway ARN: %w", err)
+	}
+
+	client, err := clientFor(region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create EC2 client: %w", err)
+	}
+
+	gateways, err := n.listNATGateways(ctx, client, &ec2.DescribeNatGatewaysInput{NatGatewayIds: []string{gatewayID}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch NAT gateway: %w", err)
+	}
+	if len(gateways) == 0 {
+		return nil, fmt.Errorf("no NAT gateway found with ID %s", gatewayID)
+	}
+
+	metadata := newNATGatewayMetadata(gateways[0], region, accountID)
+	return &metadata, nil
+}
+
+// newNATGatewayMetadata builds the metadata of a NAT gateway
+func newNATGatewayMetadata(gateway types.NatGateway, region, accountID string) ResourceMetadata {
+	gatewayID := aws.ToString(gateway.NatGatewayId)
+
+	var allocationIDs, publicIPs []string
+	for _, address := range gateway.NatGatewayAddresses {
+		if allocationID := aws.ToString(address.AllocationId); allocationID != "" {
+			allocationIDs = append(allocationIDs, allocationID)
+		}
+		if publicIP := aws.ToString(address.PublicIp); publicIP != "" {
+			publicIPs = append(publicIPs, publicIP)
+		}
+	}
+
+	metadata := ResourceMetadata{
+		ID:           gatewayID,
+		Type:         constants.ResourceTypeNATGateway,
+		Provider:     "aws",
+		AccountID:    accountID,
+		Region:       region,
+		DiscoveredAt: time.Now(),
+		CreatedAt:    aws.ToTime(gateway.CreateTime),
+		Tags:         ec2TagMap(gateway.Tags),
+		RawResponse:  gateway,
+	}
+
+	metadata.Details.ARN = resourceARN("ec2", region, true, accountID, "natgateway/"+gatewayID)
+	metadata.Details.Name = ec2ResourceName(gateway.Tags, gatewayID)
+	metadata.Details.Status = string(gateway.State)
+	metadata.Details.Properties = map[string]interface{}{
+		"state":             string(gateway.State),
+		"subnet_id":         aws.ToString(gateway.SubnetId),
+		"vpc_id":            aws.ToString(gateway.VpcId),
+		"connectivity_type": string(gateway.ConnectivityType),
+		"allocation_ids":    allocationIDs,
+		"public_ips":        publicIPs,
+	}
+
+	return metadata
+}
+
+// ParseNATGatewayARN extracts the NAT gateway ID and region from a NAT gateway ARN
+func ParseNATGatewayARN(resourceARN string) (string, string, error) {
+	// ARN format: arn:partition:ec2:region:account-id:natgateway/nat-id
+	parsed, err := arn.ParseRegionalService(resourceARN, "ec2")
+	if err != nil {
+		return "", "", fmt.Errorf("invalid NAT gateway ARN format: %w", err)
+	}
+
+	gatewayID, ok := parsed.ResourceName("natgateway")
+	if !ok || !strings.HasPrefix(gatewayID, "nat-") {
+		return "", "", fmt.Errorf("invalid NAT gateway ID in ARN: %s", resourceARN)
+	}
+	return gatewayID, parsed.Region, nil
+}
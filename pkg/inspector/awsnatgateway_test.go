@@ -0,0 +1,197 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockNATGatewayClient serves the NAT gateways pageSize items per page
+type mockNATGatewayClient struct {
+	gateways []types.NatGateway
+	pageSize int
+}
+
+func (m *mockNATGatewayClient) DescribeNatGateways(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error) {
+	if len(params.NatGatewayIds) > 0 {
+		output := &ec2.DescribeNatGatewaysOutput{}
+		for _, gateway := range m.gateways {
+			if aws.ToString(gateway.NatGatewayId) == params.NatGatewayIds[0] {
+				output.NatGateways = append(output.NatGateways, gateway)
+			}
+		}
+		return output, nil
+	}
+
+	start := 0
+	if params.NextToken != nil {
+		start, _ = strconv.Atoi(*params.NextToken)
+	}
+	end := min(start+m.pageSize, len(m.gateways))
+
+	output := &ec2.DescribeNatGatewaysOutput{NatGateways: m.gateways[start:end]}
+	if end < len(m.gateways) {
+		output.NextToken = aws.String(strconv.Itoa(end))
+	}
+	return output, nil
+}
+
+// newMockNATGateways returns 3 public gateways, a private one and a deleted one
+func newMockNATGateways() []types.NatGateway {
+	createTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var gateways []types.NatGateway
+	for i := 0; i < 3; i++ {
+		gateways = append(gateways, types.NatGateway{
+			NatGatewayId:     aws.String(fmt.Sprintf("nat-%04d", i)),
+			State:            types.NatGatewayStateAvailable,
+			SubnetId:         aws.String("subnet-0abc"),
+			VpcId:            aws.String("vpc-0abc"),
+			ConnectivityType: types.ConnectivityTypePublic,
+			CreateTime:       aws.Time(createTime),
+			NatGatewayAddresses: []types.NatGatewayAddress{{
+				AllocationId: aws.String(fmt.Sprintf("eipalloc-%04d", i)),
+				PublicIp:     aws.String(fmt.Sprintf("203.0.113.%d", i)),
+				PrivateIp:    aws.String(fmt.Sprintf("10.0.0.%d", i)),
+			}},
+			Tags: []types.Tag{{Key: aws.String("Name"), Value: aws.String(fmt.Sprintf("egress-%d", i))}},
+		})
+	}
+	gateways = append(gateways,
+		types.NatGateway{
+			NatGatewayId:        aws.String("nat-private"),
+			State:               types.NatGatewayStateAvailable,
+			ConnectivityType:    types.ConnectivityTypePrivate,
+			NatGatewayAddresses: []types.NatGatewayAddress{{PrivateIp: aws.String("10.0.1.10")}},
+		},
+		types.NatGateway{NatGatewayId: aws.String("nat-deleted"), State: types.NatGatewayStateDeleted},
+	)
+	return gateways
+}
+
+func TestNATGatewayInspectorDiscoversGateways(t *testing.T) {
+	t.Parallel()
+
+	client := &mockNATGatewayClient{gateways: newMockNATGateways(), pageSize: 2}
+	clientFor := func(region string) (NATGatewayAPI, error) {
+		return client, nil
+	}
+
+	inspector := &NATGatewayInspector{
+		Regions: []string{"eu-west-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	discoverer, processor := inspector.newScanFuncs(clientFor, "123456789012")
+
+	resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(context.Background(), []string{"eu-west-1"}, discoverer, processor)
+	require.NoError(t, err)
+
+	// The deleted gateway is left out
+	require.Len(t, resources, 4)
+
+	for _, resource := range resources {
+		assert.Equal(t, constants.ResourceTypeNATGateway, resource.Type)
+		assert.Equal(t, "arn:aws:ec2:eu-west-1:123456789012:natgateway/"+resource.ID, resource.Details.ARN)
+		assert.Equal(t, "available", resource.Details.Properties["state"])
+
+		if resource.ID == "nat-private" {
+			assert.Equal(t, "private", resource.Details.Properties["connectivity_type"])
+			assert.Empty(t, resource.Details.Properties["allocation_ids"])
+			assert.Equal(t, "nat-private", resource.Details.Name)
+			continue
+		}
+
+		i := resource.ID[len(resource.ID)-1:]
+		assert.Equal(t, "egress-"+i, resource.Details.Name)
+		assert.Equal(t, "public", resource.Details.Properties["connectivity_type"])
+		assert.Equal(t, "subnet-0abc", resource.Details.Properties["subnet_id"])
+		assert.Equal(t, "vpc-0abc", resource.Details.Properties["vpc_id"])
+		assert.Equal(t, []string{"eipalloc-000" + i}, resource.Details.Properties["allocation_ids"])
+		assert.Equal(t, []string{"203.0.113." + i}, resource.Details.Properties["public_ips"])
+		assert.False(t, resource.CreatedAt.IsZero())
+	}
+}
+
+func TestNATGatewayInspectorFetch(t *testing.T) {
+	t.Parallel()
+
+	client := &mockNATGatewayClient{gateways: newMockNATGateways(), pageSize: 2}
+	clientFor := func(region string) (NATGatewayAPI, error) {
+		if region != "eu-west-1" {
+			return nil, fmt.Errorf("unexpected region %s", region)
+		}
+		return client, nil
+	}
+	inspector := &NATGatewayInspector{Logger: o11y.NewLogger(io.Discard, o11y.LogLevelError)}
+
+	metadata, err := inspector.fetch(context.Background(), "arn:aws:ec2:eu-west-1:123456789012:natgateway/nat-0001", clientFor, "123456789012")
+	require.NoError(t, err)
+	assert.Equal(t, "egress-1", metadata.Details.Name)
+	assert.Equal(t, map[string]string{"Name": "egress-1"}, metadata.Tags)
+
+	_, err = inspector.fetch(context.Background(), "arn:aws:ec2:eu-west-1:123456789012:natgateway/nat-missing", clientFor, "123456789012")
+	assert.Error(t, err)
+}
+
+func TestParseNATGatewayARN(t *testing.T) {
+	t.Parallel()
+
+	gatewayID, region, err := ParseNATGatewayARN("arn:aws:ec2:us-east-1:123456789012:natgateway/nat-0abc123")
+	require.NoError(t, err)
+	assert.Equal(t, "nat-0abc123", gatewayID)
+	assert.Equal(t, "us-east-1", region)
+
+	for _, invalid := range []string{
+		"arn:aws:ec2:us-east-1:123456789012:internet-gateway/igw-0abc",
+		"arn:aws:ec2:us-east-1:123456789012:natgateway/",
+		"nat-0abc123",
+	} {
+		_, _, err := ParseNATGatewayARN(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+// probedNATGatewayClient is a mockNATGatewayClient recording the calls of a probe
+type probedNATGatewayClient struct {
+	*mockNATGatewayClient
+	calls *probeCalls
+}
+
+func (c *probedNATGatewayClient) DescribeNatGateways(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error) {
+	if err := c.calls.call("ec2:DescribeNatGateways"); err != nil {
+		return nil, err
+	}
+	return c.mockNATGatewayClient.DescribeNatGateways(ctx, params, optFns...)
+}
+
+func TestNATGatewayInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &NATGatewayInspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	for _, denied := range []string{"", "ec2:DescribeNatGateways"} {
+		calls := &probeCalls{denied: denied}
+		clientFor := func(region string) (NATGatewayAPI, error) {
+			return &probedNATGatewayClient{mockNATGatewayClient: &mockNATGatewayClient{gateways: newMockNATGateways(), pageSize: 5}, calls: calls}, nil
+		}
+
+		err := inspector.probe(context.Background(), "us-east-1", clientFor)
+		assertProbeError(t, err, denied)
+		assert.Equal(t, []string{"ec2:DescribeNatGateways"}, calls.made)
+	}
+}
@@ -3,9 +3,9 @@
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
+	"github.com/Excoriate/aws-taggy/pkg/arn"
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
 	"github.com/Excoriate/aws-taggy/pkg/o11y"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -39,6 +39,15 @@ func (m *AWSClientManager) GetRDSClient(region string) (*rds.Client, error) {
 	return client.(*rds.Client), nil
 }
 
+// RDSAPI is the subset of the RDS client used to discover database instances
+type RDSAPI interface {
+	rds.DescribeDBInstancesAPIClient
+	ListTagsForResource(ctx context.Context, params *rds.ListTagsForResourceInput, optFns ...func(*rds.Options)) (*rds.ListTagsForResourceOutput, error)
+}
+
+// rdsClientProvider returns the RDS client to use for a region
+type rdsClientProvider func(region string) (RDSAPI, error)
+
 // RDSInspector implements the Inspector interface for AWS RDS resources
 type RDSInspector struct {
 	Regions       []string
@@ -74,8 +83,11 @@ func (r *RDSInspector) Inspect(ctx context.Context, config configuration.TaggySc
 		Region:    r.Regions[0],
 	}
 
-	// Create async scanner with default config
-	scanner := NewAsyncResourceInspector(DefaultInspectorConfig())
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := r.ClientManager.resolveAccountID(ctx, r.Logger)
 
 	// Define the resource discoverer function
 	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
@@ -94,7 +106,7 @@ func (r *RDSInspector) Inspect(ctx context.Context, config configuration.TaggySc
 		// Convert to interface slice
 		resources := make([]interface{}, len(instances))
 		for i, instance := range instances {
-			resources[i] = instance
+			resources[i] = RegionalResource{Region: region, Item: instance}
 		}
 
 		return resources, nil
@@ -102,10 +114,11 @@ func (r *RDSInspector) Inspect(ctx context.Context, config configuration.TaggySc
 
 	// Define the resource processor function
 	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
-		instance := resource.(types.DBInstance)
+		regional := resource.(RegionalResource)
+		instance := regional.Item.(types.DBInstance)
 
-		// Get RDS client for initial region
-		rdsClient, err := r.ClientManager.GetRDSClient(r.Regions[0])
+		// Get RDS client for the instance region
+		rdsClient, err := r.ClientManager.GetRDSClient(regional.Region)
 		if err != nil {
 			return ResourceMetadata{}, fmt.Errorf("failed to get RDS client: %w", err)
 		}
@@ -121,13 +134,16 @@ func (r *RDSInspector) Inspect(ctx context.Context, config configuration.TaggySc
 
 		// Create resource metadata
 		metadata := ResourceMetadata{
-			ID:           *instance.DBInstanceArn,
-			Type:         "rds",
-			Provider:     "aws",
-			Region:       r.Regions[0], // RDS is regional
-			DiscoveredAt: time.Now(),
-			Tags:         tags,
-			RawResponse:  instance,
+			ID:            *instance.DBInstanceArn,
+			Type:          "rds",
+			Provider:      "aws",
+			AccountID:     accountID,
+			Region:        regional.Region, // RDS is regional
+			DiscoveredAt:  time.Now(),
+			CreatedAt:     aws.ToTime(instance.InstanceCreateTime),
+			Tags:          tags,
+			TagFetchError: tagFetchError(err),
+			RawResponse:   instance,
 		}
 
 		// Populate extended details
@@ -163,8 +179,42 @@ func (r *RDSInspector) Inspect(ctx context.Context, config configuration.TaggySc
 	return result, nil
 }
 
+// regionalClient returns the RDS client of a region from the client manager
+func (r *RDSInspector) regionalClient(region string) (RDSAPI, error) {
+	client, err := r.ClientManager.GetRDSClient(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe describes a few database instances of the region, then reads the tags of one of them
+func (r *RDSInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return r.probe(ctx, region, r.regionalClient)
+}
+
+// probe issues the calls of Probe with the clients of clientFor
+func (r *RDSInspector) probe(ctx context.Context, region string, clientFor rdsClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get RDS client: %w", err)
+	}
+
+	// Twenty records is the smallest page DescribeDBInstances accepts
+	output, err := client.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{MaxRecords: aws.Int32(20)})
+	if err != nil {
+		return probeCall("rds:DescribeDBInstances", err)
+	}
+	if len(output.DBInstances) == 0 {
+		return nil
+	}
+
+	_, err = client.ListTagsForResource(ctx, &rds.ListTagsForResourceInput{ResourceName: output.DBInstances[0].DBInstanceArn})
+	return probeCall("rds:ListTagsForResource", err)
+}
+
 // listDatabaseInstances retrieves all RDS database instances
-func (r *RDSInspector) listDatabaseInstances(ctx context.Context, client *rds.Client) ([]types.DBInstance, error) {
+func (r *RDSInspector) listDatabaseInstances(ctx context.Context, client RDSAPI) ([]types.DBInstance, error) {
 	var instances []types.DBInstance
 	paginator := rds.NewDescribeDBInstancesPaginator(client, &rds.DescribeDBInstancesInput{})
 
@@ -180,7 +230,7 @@ func (r *RDSInspector) listDatabaseInstances(ctx context.Context, client *rds.Cl
 }
 
 // getDatabaseInstanceTags retrieves tags for a specific RDS database instance
-func (r *RDSInspector) getDatabaseInstanceTags(ctx context.Context, client *rds.Client, instanceARN string) (map[string]string, error) {
+func (r *RDSInspector) getDatabaseInstanceTags(ctx context.Context, client RDSAPI, instanceARN string) (map[string]string, error) {
 	// List tags for the database instance
 	tagsOutput, err := client.ListTagsForResource(ctx, &rds.ListTagsForResourceInput{
 		ResourceName: aws.String(instanceARN),
@@ -249,12 +299,16 @@ func (r *RDSInspector) Fetch(ctx context.Context, arn string, config configurati
 
 	// Create resource metadata
 	resourceMeta := &ResourceMetadata{
-		ID:           instanceARN,
-		Type:         "rds",
-		Provider:     "aws",
-		Region:       region,
-		Tags:         tags,
-		DiscoveredAt: time.Now(),
+		ID:            instanceARN,
+		Type:          "rds",
+		Provider:      "aws",
+		AccountID:     r.ClientManager.resolveAccountID(ctx, r.Logger),
+		Region:        region,
+		Tags:          tags,
+		TagFetchError: tagFetchError(err),
+		DiscoveredAt:  time.Now(),
+		CreatedAt:     aws.ToTime(instance.InstanceCreateTime),
+		RawResponse:   instance,
 	}
 
 	// Populate extended details
@@ -272,13 +326,16 @@ func (r *RDSInspector) Fetch(ctx context.Context, arn string, config configurati
 }
 
 // ParseRDSARN extracts database instance ARN and region from RDS ARN
-func ParseRDSARN(arn string) (string, string, error) {
-	// ARN format: arn:aws:rds:region:account-id:db:db-instance-name
-	parts := strings.Split(arn, ":")
-	if len(parts) != 7 {
-		return "", "", fmt.Errorf("invalid RDS ARN format: %s", arn)
+func ParseRDSARN(resourceARN string) (string, string, error) {
+	// ARN format: arn:partition:rds:region:account-id:db:db-instance-name
+	parsed, err := arn.ParseRegionalService(resourceARN, "rds")
+	if err != nil {
+		return "", "", fmt.Errorf("invalid RDS ARN format: %w", err)
+	}
+
+	instanceName, ok := parsed.ResourceName("db")
+	if !ok {
+		return "", "", fmt.Errorf("invalid RDS database instance in ARN: %s", resourceARN)
 	}
-	region := parts[3]
-	instanceName := parts[6]
-	return instanceName, region, nil
+	return instanceName, parsed.Region, nil
 }
@@ -0,0 +1,92 @@
+package inspector
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockRDSClient serves its database instances in a single page, recording the calls made
+type mockRDSClient struct {
+	instances []types.DBInstance
+	calls     *probeCalls
+}
+
+func (m *mockRDSClient) DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	if err := m.calls.call("rds:DescribeDBInstances"); err != nil {
+		return nil, err
+	}
+	return &rds.DescribeDBInstancesOutput{DBInstances: m.instances}, nil
+}
+
+func (m *mockRDSClient) ListTagsForResource(ctx context.Context, params *rds.ListTagsForResourceInput, optFns ...func(*rds.Options)) (*rds.ListTagsForResourceOutput, error) {
+	if err := m.calls.call("rds:ListTagsForResource"); err != nil {
+		return nil, err
+	}
+	return &rds.ListTagsForResourceOutput{
+		TagList: []types.Tag{{Key: aws.String("Environment"), Value: aws.String("production")}},
+	}, nil
+}
+
+func TestRDSInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &RDSInspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	instances := []types.DBInstance{{
+		DBInstanceIdentifier: aws.String("orders"),
+		DBInstanceArn:        aws.String("arn:aws:rds:us-east-1:123456789012:db:orders"),
+	}}
+
+	tests := []struct {
+		name          string
+		instances     []types.DBInstance
+		denied        string
+		expectedCalls []string
+	}{
+		{
+			name:          "Success",
+			instances:     instances,
+			expectedCalls: []string{"rds:DescribeDBInstances", "rds:ListTagsForResource"},
+		},
+		{
+			name:          "No Instance",
+			expectedCalls: []string{"rds:DescribeDBInstances"},
+		},
+		{
+			name:          "Describe Denied",
+			instances:     instances,
+			denied:        "rds:DescribeDBInstances",
+			expectedCalls: []string{"rds:DescribeDBInstances"},
+		},
+		{
+			name:          "Tags Denied",
+			instances:     instances,
+			denied:        "rds:ListTagsForResource",
+			expectedCalls: []string{"rds:DescribeDBInstances", "rds:ListTagsForResource"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := &probeCalls{denied: tt.denied}
+			clientFor := func(region string) (RDSAPI, error) {
+				return &mockRDSClient{instances: tt.instances, calls: calls}, nil
+			}
+
+			err := inspector.probe(context.Background(), "us-east-1", clientFor)
+			assertProbeError(t, err, tt.denied)
+			assert.Equal(t, tt.expectedCalls, calls.made)
+		})
+	}
+}
@@ -3,9 +3,9 @@
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
+	"github.com/Excoriate/aws-taggy/pkg/arn"
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
 	"github.com/Excoriate/aws-taggy/pkg/o11y"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -39,6 +39,15 @@ func (m *AWSClientManager) GetRoute53Client(region string) (*route53.Client, err
 	return client.(*route53.Client), nil
 }
 
+// Route53API is the subset of the Route 53 client used to discover hosted zones
+type Route53API interface {
+	route53.ListHostedZonesAPIClient
+	ListTagsForResource(ctx context.Context, params *route53.ListTagsForResourceInput, optFns ...func(*route53.Options)) (*route53.ListTagsForResourceOutput, error)
+}
+
+// route53ClientProvider returns the Route 53 client to use for a region
+type route53ClientProvider func(region string) (Route53API, error)
+
 // Route53Inspector implements the Inspector interface for AWS Route 53 resources
 type Route53Inspector struct {
 	Regions       []string
@@ -74,8 +83,11 @@ func (r *Route53Inspector) Inspect(ctx context.Context, config configuration.Tag
 		Region:    r.Regions[0],
 	}
 
-	// Create async scanner with default config
-	scanner := NewAsyncResourceInspector(DefaultInspectorConfig())
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := r.ClientManager.resolveAccountID(ctx, r.Logger)
 
 	// Define the resource discoverer function
 	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
@@ -121,17 +133,19 @@ func (r *Route53Inspector) Inspect(ctx context.Context, config configuration.Tag
 
 		// Create resource metadata
 		metadata := ResourceMetadata{
-			ID:           *hostedZone.Id,
-			Type:         "route53_hosted_zone",
-			Provider:     "aws",
-			Region:       r.Regions[0], // Route 53 is a global service
-			DiscoveredAt: time.Now(),
-			Tags:         tags,
-			RawResponse:  hostedZone,
+			ID:            *hostedZone.Id,
+			Type:          "route53_hosted_zone",
+			Provider:      "aws",
+			AccountID:     accountID,
+			Region:        r.Regions[0], // Route 53 is a global service
+			DiscoveredAt:  time.Now(),
+			Tags:          tags,
+			TagFetchError: tagFetchError(err),
+			RawResponse:   hostedZone,
 		}
 
 		// Populate extended details
-		metadata.Details.ARN = fmt.Sprintf("arn:aws:route53:::hostedzone/%s", *hostedZone.Id)
+		metadata.Details.ARN = resourceARN("route53", r.Regions[0], false, "", "hostedzone/"+*hostedZone.Id)
 		metadata.Details.Name = *hostedZone.Name
 		metadata.Details.Properties = map[string]interface{}{
 			"caller_reference": hostedZone.CallerReference,
@@ -160,8 +174,44 @@ func (r *Route53Inspector) Inspect(ctx context.Context, config configuration.Tag
 	return result, nil
 }
 
+// regionalClient returns the Route 53 client of a region from the client manager
+func (r *Route53Inspector) regionalClient(region string) (Route53API, error) {
+	client, err := r.ClientManager.GetRoute53Client(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe lists a single hosted zone of the account, then reads its tags
+func (r *Route53Inspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return r.probe(ctx, region, r.regionalClient)
+}
+
+// probe issues the calls of Probe with the clients of clientFor
+func (r *Route53Inspector) probe(ctx context.Context, region string, clientFor route53ClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get Route 53 client: %w", err)
+	}
+
+	output, err := client.ListHostedZones(ctx, &route53.ListHostedZonesInput{MaxItems: aws.Int32(1)})
+	if err != nil {
+		return probeCall("route53:ListHostedZones", err)
+	}
+	if len(output.HostedZones) == 0 {
+		return nil
+	}
+
+	_, err = client.ListTagsForResource(ctx, &route53.ListTagsForResourceInput{
+		ResourceId:   output.HostedZones[0].Id,
+		ResourceType: types.TagResourceTypeHostedzone,
+	})
+	return probeCall("route53:ListTagsForResource", err)
+}
+
 // listHostedZones retrieves all Route 53 hosted zones
-func (r *Route53Inspector) listHostedZones(ctx context.Context, client *route53.Client) ([]types.HostedZone, error) {
+func (r *Route53Inspector) listHostedZones(ctx context.Context, client Route53API) ([]types.HostedZone, error) {
 	var hostedZones []types.HostedZone
 	paginator := route53.NewListHostedZonesPaginator(client, &route53.ListHostedZonesInput{})
 
@@ -177,7 +227,7 @@ func (r *Route53Inspector) listHostedZones(ctx context.Context, client *route53.
 }
 
 // getHostedZoneTags retrieves tags for a specific hosted zone
-func (r *Route53Inspector) getHostedZoneTags(ctx context.Context, client *route53.Client, hostedZoneID string) (map[string]string, error) {
+func (r *Route53Inspector) getHostedZoneTags(ctx context.Context, client Route53API, hostedZoneID string) (map[string]string, error) {
 	// List tags for the hosted zone
 	tagsOutput, err := client.ListTagsForResource(ctx, &route53.ListTagsForResourceInput{
 		ResourceId:   aws.String(hostedZoneID),
@@ -226,12 +276,15 @@ func (r *Route53Inspector) Fetch(ctx context.Context, arn string, config configu
 
 	// Create resource metadata
 	resourceMeta := &ResourceMetadata{
-		ID:           hostedZoneID,
-		Type:         "route53_hosted_zone",
-		Provider:     "aws",
-		Region:       r.Regions[0], // Route 53 is a global service
-		Tags:         tags,
-		DiscoveredAt: time.Now(),
+		ID:            hostedZoneID,
+		Type:          "route53_hosted_zone",
+		Provider:      "aws",
+		AccountID:     r.ClientManager.resolveAccountID(ctx, r.Logger),
+		Region:        r.Regions[0], // Route 53 is a global service
+		Tags:          tags,
+		TagFetchError: tagFetchError(err),
+		DiscoveredAt:  time.Now(),
+		RawResponse:   zoneOutput.HostedZone,
 	}
 
 	// Populate extended details
@@ -246,11 +299,16 @@ func (r *Route53Inspector) Fetch(ctx context.Context, arn string, config configu
 }
 
 // ParseRoute53ARN extracts hosted zone ID from Route 53 ARN
-func ParseRoute53ARN(arn string) (string, error) {
-	// ARN format: arn:aws:route53:::hostedzone/ZONEID
-	parts := strings.Split(arn, "/")
-	if len(parts) != 2 {
-		return "", fmt.Errorf("invalid Route 53 ARN format: %s", arn)
+func ParseRoute53ARN(resourceARN string) (string, error) {
+	// ARN format: arn:partition:route53:::hostedzone/ZONEID
+	parsed, err := arn.ParseService(resourceARN, "route53")
+	if err != nil {
+		return "", fmt.Errorf("invalid Route 53 ARN format: %w", err)
+	}
+
+	hostedZoneID, ok := parsed.ResourceName("hostedzone")
+	if !ok {
+		return "", fmt.Errorf("invalid Route 53 hosted zone in ARN: %s", resourceARN)
 	}
-	return parts[1], nil
+	return hostedZoneID, nil
 }
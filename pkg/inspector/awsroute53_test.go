@@ -0,0 +1,93 @@
+package inspector
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockRoute53Client serves its hosted zones in a single page, recording the calls made
+type mockRoute53Client struct {
+	zones []types.HostedZone
+	calls *probeCalls
+}
+
+func (m *mockRoute53Client) ListHostedZones(ctx context.Context, params *route53.ListHostedZonesInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error) {
+	if err := m.calls.call("route53:ListHostedZones"); err != nil {
+		return nil, err
+	}
+	return &route53.ListHostedZonesOutput{HostedZones: m.zones}, nil
+}
+
+func (m *mockRoute53Client) ListTagsForResource(ctx context.Context, params *route53.ListTagsForResourceInput, optFns ...func(*route53.Options)) (*route53.ListTagsForResourceOutput, error) {
+	if err := m.calls.call("route53:ListTagsForResource"); err != nil {
+		return nil, err
+	}
+	return &route53.ListTagsForResourceOutput{
+		ResourceTagSet: &types.ResourceTagSet{
+			ResourceId:   params.ResourceId,
+			ResourceType: params.ResourceType,
+			Tags:         []types.Tag{{Key: aws.String("Environment"), Value: aws.String("production")}},
+		},
+	}, nil
+}
+
+func TestRoute53InspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &Route53Inspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	zones := []types.HostedZone{{Id: aws.String("/hostedzone/Z123456789"), Name: aws.String("example.com.")}}
+
+	tests := []struct {
+		name          string
+		zones         []types.HostedZone
+		denied        string
+		expectedCalls []string
+	}{
+		{
+			name:          "Success",
+			zones:         zones,
+			expectedCalls: []string{"route53:ListHostedZones", "route53:ListTagsForResource"},
+		},
+		{
+			name:          "No Hosted Zone",
+			expectedCalls: []string{"route53:ListHostedZones"},
+		},
+		{
+			name:          "List Denied",
+			zones:         zones,
+			denied:        "route53:ListHostedZones",
+			expectedCalls: []string{"route53:ListHostedZones"},
+		},
+		{
+			name:          "Tags Denied",
+			zones:         zones,
+			denied:        "route53:ListTagsForResource",
+			expectedCalls: []string{"route53:ListHostedZones", "route53:ListTagsForResource"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := &probeCalls{denied: tt.denied}
+			clientFor := func(region string) (Route53API, error) {
+				return &mockRoute53Client{zones: tt.zones, calls: calls}, nil
+			}
+
+			err := inspector.probe(context.Background(), "us-east-1", clientFor)
+			assertProbeError(t, err, tt.denied)
+			assert.Equal(t, tt.expectedCalls, calls.made)
+		})
+	}
+}
@@ -2,11 +2,17 @@
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/Excoriate/aws-taggy/pkg/arn"
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
 	"github.com/Excoriate/aws-taggy/pkg/o11y"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -17,7 +23,10 @@
 type S3ClientCreator struct{}
 
 func (c *S3ClientCreator) CreateFromConfig(cfg *aws.Config) interface{} {
-	return s3.NewFromConfig(*cfg)
+	return s3.NewFromConfig(*cfg, func(o *s3.Options) {
+		// Emulators behind a custom endpoint do not resolve bucket subdomains
+		o.UsePathStyle = cfg.BaseEndpoint != nil
+	})
 }
 
 // GetS3Client retrieves an S3 client for a specific region
@@ -42,6 +51,23 @@ func (m *AWSClientManager) GetS3Client(region string) (*s3.Client, error) {
 	return client.(*s3.Client), nil
 }
 
+// S3API is the subset of the S3 client used by the S3Inspector
+type S3API interface {
+	ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
+	GetBucketLocation(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error)
+	GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error)
+	HeadBucket(ctx context.Context, params *s3.HeadBucketInput, optFns ...func(*s3.Options)) (*s3.HeadBucketOutput, error)
+}
+
+// s3ClientProvider returns the S3 client to use for a region
+type s3ClientProvider func(region string) (S3API, error)
+
+// locatedBucket is a listed bucket whose region is already resolved
+type locatedBucket struct {
+	bucket types.Bucket
+	region string
+}
+
 // S3Inspector implements the Scanner interface for AWS S3 resources
 type S3Inspector struct {
 	Regions       []string
@@ -67,18 +93,44 @@ func NewS3Inspector(regions []string) (*S3Inspector, error) {
 	}, nil
 }
 
-// Inspect discovers S3 buckets and their metadata across specified regions
+// Inspect discovers S3 buckets and their metadata across specified regions. With
+// bucket_regions configured, buckets are listed once and only those located in these regions
+// are inspected.
 func (s *S3Inspector) Inspect(ctx context.Context, config configuration.TaggyScanConfig) (*InspectResult, error) {
+	bucketRegions := config.Resources[constants.ResourceTypeS3].BucketRegions
+
 	s.Logger.Info("Starting S3 resource scanning",
-		"regions", s.Regions)
+		"regions", s.Regions,
+		"bucket_regions", bucketRegions)
 
 	result := &InspectResult{
 		StartTime: time.Now(),
 		Region:    s.Regions[0],
 	}
 
-	// Create async scanner with default config
-	scanner := NewAsyncResourceInspector(DefaultInspectorConfig())
+	// Create async scanner with the batch size and workers resolved for the resource type
+	inspectorConfig := inspectorConfigFromContext(ctx)
+	scanner := NewAsyncResourceInspector(inspectorConfig)
+
+	// Buckets are listed account-wide, so filtered listings go through a single region
+	listRegions := s.Regions
+	var outOfRegion atomic.Int64
+
+	// Buckets whose region cannot be resolved are left out, and reported in the errors of
+	// the result
+	var unlocatedMu sync.Mutex
+	var unlocated []string
+	addUnlocated := func(failures ...string) {
+		unlocatedMu.Lock()
+		defer unlocatedMu.Unlock()
+		unlocated = append(unlocated, failures...)
+	}
+	if len(bucketRegions) > 0 {
+		listRegions = s.Regions[:1]
+	}
+
+	// Resolve the account ID once for every discovered resource
+	accountID := s.ClientManager.resolveAccountID(ctx, s.Logger)
 
 	// Define the resource discoverer function
 	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
@@ -94,6 +146,19 @@ func (s *S3Inspector) Inspect(ctx context.Context, config configuration.TaggySca
 			return nil, fmt.Errorf("failed to list buckets: %w", err)
 		}
 
+		// Buckets outside the bucket regions are left out before their tags are read
+		if len(bucketRegions) > 0 {
+			located, skipped, failures := s.locateBuckets(ctx, s3Client, buckets, bucketRegions, inspectorConfig.NumWorkers)
+			outOfRegion.Add(int64(skipped))
+			addUnlocated(failures...)
+
+			resources := make([]interface{}, len(located))
+			for i, bucket := range located {
+				resources[i] = bucket
+			}
+			return resources, nil
+		}
+
 		// Convert to interface slice
 		resources := make([]interface{}, len(buckets))
 		for i, bucket := range buckets {
@@ -105,69 +170,19 @@ func (s *S3Inspector) Inspect(ctx context.Context, config configuration.TaggySca
 
 	// Define the resource processor function
 	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
-		bucket := resource.(types.Bucket)
-
-		// Get S3 client for initial region
-		s3Client, err := s.ClientManager.GetS3Client(s.Regions[0])
-		if err != nil {
-			return ResourceMetadata{}, fmt.Errorf("failed to get S3 client: %w", err)
-		}
-
-		// Get bucket location
-		locationOutput, err := s3Client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
-			Bucket: bucket.Name,
-		})
-		if err != nil {
-			return ResourceMetadata{}, fmt.Errorf("failed to get bucket location: %w", err)
-		}
-
-		// Determine bucket region
-		bucketRegion := string(locationOutput.LocationConstraint)
-		if bucketRegion == "" {
-			bucketRegion = "us-east-1"
+		if located, ok := resource.(locatedBucket); ok {
+			return s.inspectBucket(ctx, located.bucket, located.region, accountID, s.regionalClient)
 		}
-
-		// Get client for correct region if different
-		if bucketRegion != s.Regions[0] {
-			s3Client, err = s.ClientManager.GetS3Client(bucketRegion)
-			if err != nil {
-				return ResourceMetadata{}, fmt.Errorf("failed to get region-specific S3 client: %w", err)
-			}
-		}
-
-		// Fetch bucket tags
-		tags, err := s.getBucketTags(ctx, s3Client, *bucket.Name)
+		bucket := resource.(types.Bucket)
+		metadata, err := s.processBucket(ctx, bucket, accountID, s.regionalClient)
 		if err != nil {
-			s.Logger.Warn("Failed to get bucket tags",
-				"bucket", *bucket.Name,
-				"error", err)
-			tags = make(map[string]string)
-		}
-
-		// Create resource metadata
-		metadata := ResourceMetadata{
-			ID:           *bucket.Name,
-			Type:         "s3",
-			Provider:     "aws",
-			Region:       bucketRegion,
-			DiscoveredAt: time.Now(),
-			Tags:         tags,
-			RawResponse:  bucket,
-		}
-
-		// Populate extended details
-		metadata.Details.ARN = fmt.Sprintf("arn:aws:s3:::%s", *bucket.Name)
-		metadata.Details.Name = *bucket.Name
-		metadata.Details.Properties = map[string]interface{}{
-			"creation_date": bucket.CreationDate,
-			"region":        bucketRegion,
+			addUnlocated(fmt.Sprintf("bucket %s: %v", aws.ToString(bucket.Name), err))
 		}
-
-		return metadata, nil
+		return metadata, err
 	}
 
 	// Perform the async scan
-	resources, err := scanner.InspectResourcesAsync(ctx, s.Regions, discoverer, processor)
+	resources, err := scanner.InspectResourcesAsync(ctx, listRegions, discoverer, processor)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan S3 resources: %w", err)
 	}
@@ -175,18 +190,189 @@ func (s *S3Inspector) Inspect(ctx context.Context, config configuration.TaggySca
 	// Update result with scanned resources
 	result.Resources = resources
 	result.TotalResources = len(resources)
+	result.OutOfRegionResources = int(outOfRegion.Load())
+	result.Errors = unlocated
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
 
 	s.Logger.Info("S3 scanning completed",
 		"total_resources", result.TotalResources,
+		"out_of_region_resources", result.OutOfRegionResources,
 		"duration", result.Duration)
 
 	return result, nil
 }
 
+// regionalClient returns the S3 client of a region from the client manager
+func (s *S3Inspector) regionalClient(region string) (S3API, error) {
+	client, err := s.ClientManager.GetS3Client(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe lists a single bucket of the region, then reads its location and tags
+func (s *S3Inspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return s.probe(ctx, region, s.regionalClient)
+}
+
+// probe issues the calls of Probe with the clients of clientFor. A bucket without tags
+// reports NoSuchTagSet, which proves the tags of the bucket can be read.
+func (s *S3Inspector) probe(ctx context.Context, region string, clientFor s3ClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get S3 client: %w", err)
+	}
+
+	output, err := client.ListBuckets(ctx, &s3.ListBucketsInput{
+		MaxBuckets:   aws.Int32(1),
+		BucketRegion: aws.String(region),
+	})
+	if err != nil {
+		return probeCall("s3:ListAllMyBuckets", err)
+	}
+	if len(output.Buckets) == 0 {
+		return nil
+	}
+
+	bucket := output.Buckets[0].Name
+	if _, err := client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: bucket}); err != nil {
+		return probeCall("s3:GetBucketLocation", err)
+	}
+	_, err = client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: bucket})
+	if err != nil && !strings.Contains(err.Error(), "NoSuchTagSet") {
+		return probeCall("s3:GetBucketTagging", err)
+	}
+	return nil
+}
+
+// processBucket builds the metadata of a bucket. The bucket region is resolved exactly once,
+// then its tags are read with a client of that region.
+func (s *S3Inspector) processBucket(ctx context.Context, bucket types.Bucket, accountID string, clientFor s3ClientProvider) (ResourceMetadata, error) {
+	// Get S3 client for initial region
+	s3Client, err := clientFor(s.Regions[0])
+	if err != nil {
+		return ResourceMetadata{}, fmt.Errorf("failed to get S3 client: %w", err)
+	}
+
+	bucketRegion, err := resolveBucketRegion(ctx, s3Client, *bucket.Name)
+	if err != nil {
+		return ResourceMetadata{}, err
+	}
+
+	return s.inspectBucket(ctx, bucket, bucketRegion, accountID, clientFor)
+}
+
+// locateBuckets resolves the region of the buckets concurrently, with at most workers
+// GetBucketLocation calls in flight, and keeps those located in the given regions, in listing
+// order. It also returns the number of buckets left out as out of region, and the errors of
+// the buckets whose region cannot be resolved, left out too as they cannot be told in region.
+func (s *S3Inspector) locateBuckets(ctx context.Context, client S3API, buckets []types.Bucket, regions []string, workers int) ([]locatedBucket, int, []string) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	bucketRegions := make([]string, len(buckets))
+	locateErrors := make([]error, len(buckets))
+	slots := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for i, bucket := range buckets {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			region, err := resolveBucketRegion(ctx, client, name)
+			if err != nil {
+				s.Logger.Warn("Failed to locate bucket",
+					"bucket", name,
+					"error", err)
+				locateErrors[i] = err
+				return
+			}
+			bucketRegions[i] = region
+		}(i, aws.ToString(bucket.Name))
+	}
+	wg.Wait()
+
+	located := make([]locatedBucket, 0, len(buckets))
+	outOfRegion := 0
+	var failures []string
+	for i, bucket := range buckets {
+		switch {
+		case locateErrors[i] != nil:
+			failures = append(failures, fmt.Sprintf("bucket %s: %v", aws.ToString(bucket.Name), locateErrors[i]))
+		case bucketRegions[i] == "":
+			continue
+		case slices.Contains(regions, bucketRegions[i]):
+			located = append(located, locatedBucket{bucket: bucket, region: bucketRegions[i]})
+		default:
+			outOfRegion++
+		}
+	}
+	return located, outOfRegion, failures
+}
+
+// inspectBucket builds the metadata of a bucket located in bucketRegion, reading its tags with
+// a client of that region
+func (s *S3Inspector) inspectBucket(ctx context.Context, bucket types.Bucket, bucketRegion, accountID string, clientFor s3ClientProvider) (ResourceMetadata, error) {
+	if bucketRegion != s.Regions[0] {
+		s.Logger.Debug("Bucket in different region",
+			"bucket", *bucket.Name,
+			"detected_region", bucketRegion)
+	}
+
+	s3Client, err := clientFor(bucketRegion)
+	if err != nil {
+		return ResourceMetadata{}, fmt.Errorf("failed to get region-specific S3 client: %w", err)
+	}
+
+	// Fetch bucket tags
+	tags, err := s.getBucketTagsInRegion(ctx, s3Client, *bucket.Name)
+	if err != nil {
+		s.Logger.Warn("Failed to get bucket tags",
+			"bucket", *bucket.Name,
+			"error", err)
+		tags = make(map[string]string)
+	}
+
+	// Create resource metadata
+	metadata := ResourceMetadata{
+		ID:            *bucket.Name,
+		Type:          "s3",
+		Provider:      "aws",
+		AccountID:     accountID,
+		Region:        bucketRegion,
+		DiscoveredAt:  time.Now(),
+		CreatedAt:     aws.ToTime(bucket.CreationDate),
+		Tags:          tags,
+		TagFetchError: tagFetchError(err),
+		RawResponse:   bucket,
+	}
+
+	// Populate extended details
+	metadata.Details.ARN = resourceARN("s3", bucketRegion, false, "", *bucket.Name)
+	metadata.Details.Name = *bucket.Name
+	metadata.Details.Properties = map[string]interface{}{
+		"creation_date": bucket.CreationDate,
+		"region":        bucketRegion,
+	}
+
+	return metadata, nil
+}
+
 // listBuckets retrieves all S3 buckets
-func (s *S3Inspector) listBuckets(ctx context.Context, client *s3.Client) ([]types.Bucket, error) {
+func (s *S3Inspector) listBuckets(ctx context.Context, client S3API) ([]types.Bucket, error) {
 	output, err := client.ListBuckets(ctx, &s3.ListBucketsInput{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list buckets: %w", err)
@@ -194,36 +380,63 @@ func (s *S3Inspector) listBuckets(ctx context.Context, client *s3.Client) ([]typ
 	return output.Buckets, nil
 }
 
-// getBucketTags retrieves tags for a specific bucket
-func (s *S3Inspector) getBucketTags(ctx context.Context, client *s3.Client, bucketName string) (map[string]string, error) {
-	// First, try to get the bucket location
+// resolveBucketRegion returns the region a bucket lives in
+func resolveBucketRegion(ctx context.Context, client S3API, bucketName string) (string, error) {
 	locationOutput, err := client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
 		Bucket: aws.String(bucketName),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get bucket location: %w", err)
+		return "", fmt.Errorf("failed to get bucket location: %w", err)
 	}
 
-	// If the bucket is in a different region, create a new client for that region
 	bucketRegion := string(locationOutput.LocationConstraint)
 	if bucketRegion == "" {
 		bucketRegion = "us-east-1" // Default region for buckets without explicit location
 	}
 
-	// If the bucket is in a different region, create a new client
-	if bucketRegion != s.Regions[0] {
-		s.Logger.Debug("Bucket in different region",
-			"bucket", bucketName,
-			"detected_region", bucketRegion)
+	return bucketRegion, nil
+}
 
-		// Create a new S3 client for the specific region
-		regionClient, err := s.ClientManager.GetS3Client(bucketRegion)
+// locateBucket resolves the region of a bucket by querying the given regions in order. Each
+// region is asked through HeadBucket, which reports the bucket region in its response, then
+// through GetBucketLocation, whose transient failures are retried by the client. The error
+// lists the attempted regions when none of them can locate the bucket.
+func locateBucket(ctx context.Context, bucketName string, regions []string, clientFor s3ClientProvider) (string, error) {
+	var errs []error
+	for _, region := range regions {
+		client, err := clientFor(region)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create client for bucket region %s: %w", bucketRegion, err)
+			errs = append(errs, fmt.Errorf("%s: %w", region, err))
+			continue
+		}
+
+		headOutput, headErr := client.HeadBucket(ctx, &s3.HeadBucketInput{
+			Bucket: aws.String(bucketName),
+		})
+		if headErr == nil && aws.ToString(headOutput.BucketRegion) != "" {
+			return aws.ToString(headOutput.BucketRegion), nil
+		}
+
+		bucketRegion, err := resolveBucketRegion(ctx, client, bucketName)
+		if err == nil {
+			return bucketRegion, nil
+		}
+		if headErr != nil {
+			err = errors.Join(fmt.Errorf("failed to head bucket: %w", headErr), err)
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", region, err))
+
+		if ctx.Err() != nil {
+			break
 		}
-		client = regionClient
 	}
 
+	return "", fmt.Errorf("failed to locate bucket %s, attempted regions: %s: %w",
+		bucketName, strings.Join(regions, ", "), errors.Join(errs...))
+}
+
+// getBucketTagsInRegion retrieves tags for a specific bucket, using a client of the bucket's region
+func (s *S3Inspector) getBucketTagsInRegion(ctx context.Context, client S3API, bucketName string) (map[string]string, error) {
 	// Attempt to get bucket tags
 	tagsOutput, err := client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
 		Bucket: aws.String(bucketName),
@@ -261,36 +474,19 @@ func (s *S3Inspector) Fetch(ctx context.Context, arn string, config configuratio
 		return nil, fmt.Errorf("failed to parse S3 ARN: %w", err)
 	}
 
-	// Get the bucket's region first
-	s3Client, err := s.ClientManager.GetS3Client("us-east-1") // Start with default region
+	// S3 ARNs carry no region, locate the bucket from the configured regions first
+	bucketRegion, err := locateBucket(ctx, bucketName, s.Regions, s.regionalClient)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+		return nil, err
 	}
 
-	// Get bucket location
-	locationOutput, err := s3Client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
-		Bucket: aws.String(bucketName),
-	})
+	s3Client, err := s.regionalClient(bucketRegion)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get bucket location: %w", err)
-	}
-
-	// Get the correct region
-	bucketRegion := string(locationOutput.LocationConstraint)
-	if bucketRegion == "" {
-		bucketRegion = "us-east-1"
-	}
-
-	// Get client for the correct region if different
-	if bucketRegion != "us-east-1" {
-		s3Client, err = s.ClientManager.GetS3Client(bucketRegion)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create S3 client for region %s: %w", bucketRegion, err)
-		}
+		return nil, fmt.Errorf("failed to create S3 client for region %s: %w", bucketRegion, err)
 	}
 
 	// Get bucket tags
-	tags, err := s.getBucketTags(ctx, s3Client, bucketName)
+	tags, err := s.getBucketTagsInRegion(ctx, s3Client, bucketName)
 	if err != nil {
 		s.Logger.Warn("Failed to get bucket tags", "bucket", bucketName, "error", err)
 		tags = make(map[string]string)
@@ -298,12 +494,14 @@ func (s *S3Inspector) Fetch(ctx context.Context, arn string, config configuratio
 
 	// Create resource metadata
 	resourceMeta := &ResourceMetadata{
-		ID:           bucketName,
-		Type:         "s3",
-		Provider:     "aws",
-		Region:       bucketRegion,
-		Tags:         tags,
-		DiscoveredAt: time.Now(),
+		ID:            bucketName,
+		Type:          "s3",
+		Provider:      "aws",
+		AccountID:     s.ClientManager.resolveAccountID(ctx, s.Logger),
+		Region:        bucketRegion,
+		Tags:          tags,
+		TagFetchError: tagFetchError(err),
+		DiscoveredAt:  time.Now(),
 	}
 
 	// Populate extended details
@@ -317,11 +515,16 @@ func (s *S3Inspector) Fetch(ctx context.Context, arn string, config configuratio
 }
 
 // ParseS3ARN extracts bucket name from S3 ARN
-func ParseS3ARN(arn string) (string, error) {
-	// ARN format: arn:aws:s3:::bucket-name
-	parts := strings.Split(arn, ":")
-	if len(parts) != 6 {
-		return "", fmt.Errorf("invalid S3 ARN format: %s", arn)
+func ParseS3ARN(resourceARN string) (string, error) {
+	// ARN format: arn:partition:s3:::bucket-name
+	parsed, err := arn.ParseService(resourceARN, "s3")
+	if err != nil {
+		return "", fmt.Errorf("invalid S3 ARN format: %w", err)
+	}
+
+	// Bucket names hold neither slashes nor colons, unlike object and access point resources
+	if strings.ContainsAny(parsed.Resource, "/:") {
+		return "", fmt.Errorf("invalid S3 bucket in ARN: %s", resourceARN)
 	}
-	return parts[5], nil
+	return parsed.Resource, nil
 }
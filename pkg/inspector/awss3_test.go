@@ -0,0 +1,306 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockS3Calls records the S3 API calls made through every regional mock client
+type mockS3Calls struct {
+	mu             sync.Mutex
+	locationCalls  map[string]int
+	taggingRegions map[string]string
+}
+
+// mockS3Client is a regional S3API returning canned bucket locations and tags. HeadBucket
+// only reports the regions of headRegions, and every call fails when the client is denied.
+type mockS3Client struct {
+	region      string
+	locations   map[string]types.BucketLocationConstraint
+	headRegions map[string]string
+	denied      bool
+	calls       *mockS3Calls
+}
+
+func (m *mockS3Client) ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+	output := &s3.ListBucketsOutput{}
+	for name := range m.locations {
+		output.Buckets = append(output.Buckets, types.Bucket{Name: aws.String(name)})
+	}
+	return output, nil
+}
+
+func (m *mockS3Client) GetBucketLocation(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+	m.calls.mu.Lock()
+	defer m.calls.mu.Unlock()
+
+	m.calls.locationCalls[*params.Bucket]++
+	if m.denied {
+		return nil, fmt.Errorf("access denied in %s", m.region)
+	}
+	return &s3.GetBucketLocationOutput{LocationConstraint: m.locations[*params.Bucket]}, nil
+}
+
+func (m *mockS3Client) GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+	m.calls.mu.Lock()
+	defer m.calls.mu.Unlock()
+
+	m.calls.taggingRegions[*params.Bucket] = m.region
+	return &s3.GetBucketTaggingOutput{
+		TagSet: []types.Tag{{Key: aws.String("Owner"), Value: aws.String("platform")}},
+	}, nil
+}
+
+func (m *mockS3Client) HeadBucket(ctx context.Context, params *s3.HeadBucketInput, optFns ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+	region, ok := m.headRegions[*params.Bucket]
+	if m.denied || !ok {
+		return nil, fmt.Errorf("forbidden in %s", m.region)
+	}
+	return &s3.HeadBucketOutput{BucketRegion: aws.String(region)}, nil
+}
+
+func TestS3InspectorResolvesBucketRegionOnce(t *testing.T) {
+	t.Parallel()
+
+	locations := map[string]types.BucketLocationConstraint{
+		"legacy-bucket": "", // us-east-1 buckets report an empty location constraint
+		"eu-bucket":     "eu-west-1",
+		"us-bucket":     "us-west-2",
+	}
+	calls := &mockS3Calls{
+		locationCalls:  make(map[string]int),
+		taggingRegions: make(map[string]string),
+	}
+	clientFor := func(region string) (S3API, error) {
+		return &mockS3Client{region: region, locations: locations, calls: calls}, nil
+	}
+
+	inspector := &S3Inspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	expectedRegions := map[string]string{
+		"legacy-bucket": "us-east-1",
+		"eu-bucket":     "eu-west-1",
+		"us-bucket":     "us-west-2",
+	}
+
+	for name, expectedRegion := range expectedRegions {
+		metadata, err := inspector.processBucket(context.Background(),
+			types.Bucket{Name: aws.String(name)}, "123456789012", clientFor)
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedRegion, metadata.Region, name)
+		assert.Equal(t, map[string]string{"Owner": "platform"}, metadata.Tags, name)
+		assert.Equal(t, 1, calls.locationCalls[name], "GetBucketLocation must be called once for %s", name)
+		assert.Equal(t, expectedRegion, calls.taggingRegions[name], "tags of %s must be read in its region", name)
+	}
+}
+
+func TestS3InspectorLocateBucketsInRegions(t *testing.T) {
+	t.Parallel()
+
+	locations := map[string]types.BucketLocationConstraint{
+		"legacy-bucket": "",
+		"eu-bucket":     "eu-west-1",
+		"us-bucket":     "us-west-2",
+		"eu-logs":       "eu-west-1",
+	}
+	calls := &mockS3Calls{
+		locationCalls:  make(map[string]int),
+		taggingRegions: make(map[string]string),
+	}
+	clientFor := func(region string) (S3API, error) {
+		return &mockS3Client{region: region, locations: locations, calls: calls}, nil
+	}
+
+	inspector := &S3Inspector{
+		Regions: []string{"eu-west-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	var buckets []types.Bucket
+	for _, name := range []string{"legacy-bucket", "eu-bucket", "us-bucket", "eu-logs"} {
+		buckets = append(buckets, types.Bucket{Name: aws.String(name)})
+	}
+
+	client, err := clientFor("eu-west-1")
+	require.NoError(t, err)
+	located, outOfRegion, failures := inspector.locateBuckets(context.Background(), client, buckets, []string{"eu-west-1", "us-east-1"}, 2)
+
+	var names, regions []string
+	for _, bucket := range located {
+		names = append(names, aws.ToString(bucket.bucket.Name))
+		regions = append(regions, bucket.region)
+	}
+	assert.Equal(t, []string{"legacy-bucket", "eu-bucket", "eu-logs"}, names, "buckets keep their listing order")
+	assert.Equal(t, []string{"us-east-1", "eu-west-1", "eu-west-1"}, regions)
+	assert.Equal(t, 1, outOfRegion)
+	assert.Empty(t, failures)
+	for name := range locations {
+		assert.Equal(t, 1, calls.locationCalls[name], "GetBucketLocation must be called once for %s", name)
+	}
+
+	// Tags of located buckets are read in their region without locating them again
+	metadata, err := inspector.inspectBucket(context.Background(), located[0].bucket, located[0].region, "123456789012", clientFor)
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1", metadata.Region)
+	assert.Equal(t, "us-east-1", calls.taggingRegions["legacy-bucket"])
+	assert.Equal(t, 1, calls.locationCalls["legacy-bucket"])
+	assert.Empty(t, calls.taggingRegions["us-bucket"], "out of region buckets are not inspected")
+
+	// Buckets that cannot be located are neither kept nor counted as out of region, but
+	// reported as failures
+	denied := &mockS3Client{region: "eu-west-1", locations: locations, denied: true, calls: calls}
+	located, outOfRegion, failures = inspector.locateBuckets(context.Background(), denied, buckets, []string{"eu-west-1"}, 2)
+	assert.Empty(t, located)
+	assert.Zero(t, outOfRegion)
+	require.Len(t, failures, len(buckets))
+	assert.Contains(t, failures[0], "bucket "+aws.ToString(buckets[0].Name)+": failed to get bucket location")
+}
+
+func TestLocateBucket(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		regions         []string
+		deniedRegions   []string
+		headRegions     map[string]string
+		expectedRegion  string
+		expectedLookups int
+		errMsg          string
+	}{
+		{
+			name:            "Region Reported By HeadBucket",
+			regions:         []string{"eu-south-1"},
+			headRegions:     map[string]string{"opt-in-bucket": "eu-south-1"},
+			expectedRegion:  "eu-south-1",
+			expectedLookups: 0,
+		},
+		{
+			name:            "Falls Back To GetBucketLocation",
+			regions:         []string{"eu-south-1"},
+			expectedRegion:  "eu-south-1",
+			expectedLookups: 1,
+		},
+		{
+			name:            "Denied Region Is Skipped",
+			regions:         []string{"us-east-1", "eu-south-1"},
+			deniedRegions:   []string{"us-east-1"},
+			expectedRegion:  "eu-south-1",
+			expectedLookups: 2,
+		},
+		{
+			name:          "Every Region Denied",
+			regions:       []string{"eu-south-1", "us-east-1"},
+			deniedRegions: []string{"eu-south-1", "us-east-1"},
+			errMsg:        "failed to locate bucket opt-in-bucket, attempted regions: eu-south-1, us-east-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := &mockS3Calls{
+				locationCalls:  make(map[string]int),
+				taggingRegions: make(map[string]string),
+			}
+			clientFor := func(region string) (S3API, error) {
+				return &mockS3Client{
+					region:      region,
+					locations:   map[string]types.BucketLocationConstraint{"opt-in-bucket": "eu-south-1"},
+					headRegions: tt.headRegions,
+					denied:      slices.Contains(tt.deniedRegions, region),
+					calls:       calls,
+				}, nil
+			}
+
+			region, err := locateBucket(context.Background(), "opt-in-bucket", tt.regions, clientFor)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRegion, region)
+			assert.Equal(t, tt.expectedLookups, calls.locationCalls["opt-in-bucket"])
+		})
+	}
+}
+
+func TestS3InspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &S3Inspector{
+		Regions: []string{"eu-west-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	locations := map[string]types.BucketLocationConstraint{"eu-bucket": "eu-west-1"}
+
+	tests := []struct {
+		name           string
+		locations      map[string]types.BucketLocationConstraint
+		denied         bool
+		expectedAction string
+		expectedCalls  map[string]int
+	}{
+		{
+			name:          "Success",
+			locations:     locations,
+			expectedCalls: map[string]int{"eu-bucket": 1},
+		},
+		{
+			name:          "No Bucket",
+			expectedCalls: map[string]int{},
+		},
+		{
+			name:           "Access Denied",
+			locations:      locations,
+			denied:         true,
+			expectedAction: "s3:GetBucketLocation",
+			expectedCalls:  map[string]int{"eu-bucket": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := &mockS3Calls{
+				locationCalls:  make(map[string]int),
+				taggingRegions: make(map[string]string),
+			}
+			clientFor := func(region string) (S3API, error) {
+				return &mockS3Client{region: region, locations: tt.locations, denied: tt.denied, calls: calls}, nil
+			}
+
+			err := inspector.probe(context.Background(), "eu-west-1", clientFor)
+			assert.Equal(t, tt.expectedCalls, calls.locationCalls)
+			if tt.expectedAction == "" {
+				require.NoError(t, err)
+				assert.Len(t, calls.taggingRegions, len(tt.locations), "the tags of the listed bucket must be read")
+				return
+			}
+
+			var probeErr *ProbeError
+			require.ErrorAs(t, err, &probeErr)
+			assert.Equal(t, tt.expectedAction, probeErr.Action)
+			assert.Empty(t, calls.taggingRegions)
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/arn"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Any-address ranges, which open an ingress rule to the whole internet
+const (
+	anyIPv4Range = "0.0.0.0/0"
+	anyIPv6Range = "::/0"
+)
+
+// SecurityGroupAPI is the subset of the EC2 client used to discover security groups
+type SecurityGroupAPI interface {
+	ec2.DescribeSecurityGroupsAPIClient
+}
+
+// securityGroupClientProvider returns the EC2 client to use for a region
+type securityGroupClientProvider func(region string) (SecurityGroupAPI, error)
+
+// SecurityGroupInspector implements the Inspector interface for EC2 security groups
+type SecurityGroupInspector struct {
+	Regions       []string
+	ClientManager *AWSClientManager
+	Logger        *o11y.Logger
+}
+
+// NewSecurityGroupInspector creates a new inspector with AWS client management
+func NewSecurityGroupInspector(regions []string) (*SecurityGroupInspector, error) {
+	// Create AWS client manager for the specified regions
+	clientManager, err := NewAWSRegionalClientManager(regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client manager: %w", err)
+	}
+
+	return &SecurityGroupInspector{
+		Regions:       regions,
+		ClientManager: clientManager,
+		Logger:        o11y.DefaultLogger(),
+	}, nil
+}
+
+// Inspect discovers security groups and their tags across specified regions
+func (s *SecurityGroupInspector) Inspect(ctx context.Context, config configuration.TaggyScanConfig) (*InspectResult, error) {
+	s.Logger.Info("Starting security group resource scanning",
+		"regions", s.Regions)
+
+	result := &InspectResult{
+		StartTime: time.Now(),
+		Region:    s.Regions[0],
+	}
+
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := s.ClientManager.resolveAccountID(ctx, s.Logger)
+
+	discoverer, processor := s.newScanFuncs(s.regionalClient, accountID)
+
+	// Perform the async scan
+	resources, err := scanner.InspectResourcesAsync(ctx, s.Regions, discoverer, processor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan security group resources: %w", err)
+	}
+
+	// Update result with scanned resources
+	result.Resources = resources
+	result.TotalResources = len(resources)
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+
+	s.Logger.Info("Security group scanning completed",
+		"total_resources", result.TotalResources,
+		"duration", result.Duration)
+
+	return result, nil
+}
+
+// regionalClient returns the EC2 client of a region from the client manager
+func (s *SecurityGroupInspector) regionalClient(region string) (SecurityGroupAPI, error) {
+	client, err := s.ClientManager.GetEC2Client(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe describes a few security groups of the region. Their tags are part of the description.
+func (s *SecurityGroupInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return s.probe(ctx, region, s.regionalClient)
+}
+
+// probe issues the call of Probe with the client of clientFor
+func (s *SecurityGroupInspector) probe(ctx context.Context, region string, clientFor securityGroupClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get EC2 client: %w", err)
+	}
+
+	_, err = client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{MaxResults: aws.Int32(probeMaxResults)})
+	return probeCall("ec2:DescribeSecurityGroups", err)
+}
+
+// newScanFuncs returns the discoverer listing the security groups of a region, and the
+// processor building their metadata from the tags returned by the listing
+func (s *SecurityGroupInspector) newScanFuncs(clientFor securityGroupClientProvider, accountID string) (ResourceDiscoverer, ResourceProcessor) {
+	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
+		client, err := clientFor(region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get EC2 client: %w", err)
+		}
+
+		groups, err := s.listSecurityGroups(ctx, client, &ec2.DescribeSecurityGroupsInput{})
+		if err != nil {
+			return nil, err
+		}
+
+		resources := make([]interface{}, len(groups))
+		for i, group := range groups {
+			resources[i] = RegionalResource{Region: region, Item: group}
+		}
+		return resources, nil
+	}
+
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		regional := resource.(RegionalResource)
+		return newSecurityGroupMetadata(regional.Item.(types.SecurityGroup), regional.Region, accountID), nil
+	}
+
+	return discoverer, processor
+}
+
+// listSecurityGroups pages through the security groups matching the input
+func (s *SecurityGroupInspector) listSecurityGroups(ctx context.Context, client SecurityGroupAPI, input *ec2.DescribeSecurityGroupsInput) ([]types.SecurityGroup, error) {
+	var groups []types.SecurityGroup
+	paginator := ec2.NewDescribeSecurityGroupsPaginator(client, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list security groups: %w", err)
+		}
+		groups = append(groups, output.SecurityGroups...)
+	}
+	return groups, nil
+}
+
+// Fetch implements the Inspector interface for retrieving a specific security group
+func (s *SecurityGroupInspector) Fetch(ctx context.Context, arn string, config configuration.TaggyScanConfig) (*ResourceMetadata, error) {
+	return s.fetch(ctx, arn, s.regionalClient, s.ClientManager.resolveAccountID(ctx, s.Logger))
+}
+
+// fetch retrieves the security group of an ARN with the client of its region
+func (s *SecurityGroupInspector) fetch(ctx context.Context, arn string, clientFor securityGroupClientProvider, accountID string) (*ResourceMetadata, error) {
+	groupID, region, err := ParseSecurityGroupARN(arn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse security group ARN: %w", err)
+	}
+
+	client, err := clientFor(region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create EC2 client: %w", err)
+	}
+
+	groups, err := s.listSecurityGroups(ctx, client, &ec2.DescribeSecurityGroupsInput{GroupIds: []string{groupID}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch security group: %w", err)
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no security group found with ID %s", groupID)
+	}
+
+	metadata := newSecurityGroupMetadata(groups[0], region, accountID)
+	return &metadata, nil
+}
+
+// newSecurityGroupMetadata builds the metadata of a security group. The group is reported in
+// the account owning it, the account of the scan when the owner is unknown.
+func newSecurityGroupMetadata(group types.SecurityGroup, region, accountID string) ResourceMetadata {
+	tags := make(map[string]string)
+	for _, tag := range group.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	if ownerID := aws.ToString(group.OwnerId); ownerID != "" {
+		accountID = ownerID
+	}
+	groupID := aws.ToString(group.GroupId)
+
+	metadata := ResourceMetadata{
+		ID:           groupID,
+		Type:         constants.ResourceTypeSecurityGroup,
+		Provider:     "aws",
+		AccountID:    accountID,
+		Region:       region,
+		DiscoveredAt: time.Now(),
+		Tags:         tags,
+		RawResponse:  group,
+	}
+
+	metadata.Details.ARN = resourceARN("ec2", region, true, accountID, "security-group/"+groupID)
+	metadata.Details.Name = aws.ToString(group.GroupName)
+	metadata.Details.Properties = map[string]interface{}{
+		"group_name":     aws.ToString(group.GroupName),
+		"vpc_id":         aws.ToString(group.VpcId),
+		"description":    aws.ToString(group.Description),
+		"inbound_rules":  countSecurityGroupRules(group.IpPermissions),
+		"outbound_rules": countSecurityGroupRules(group.IpPermissionsEgress),
+		"open_to_world":  isOpenToWorld(group.IpPermissions),
+	}
+
+	return metadata
+}
+
+// countSecurityGroupRules counts the rules of the permissions, a permission holding a rule
+// per source, whether an address range, a prefix list or a security group
+func countSecurityGroupRules(permissions []types.IpPermission) int {
+	count := 0
+	for _, permission := range permissions {
+		sources := len(permission.IpRanges) + len(permission.Ipv6Ranges) +
+			len(permission.PrefixListIds) + len(permission.UserIdGroupPairs)
+		count += max(sources, 1)
+	}
+	return count
+}
+
+// isOpenToWorld reports whether an ingress permission allows traffic from any IPv4 or IPv6
+// address
+func isOpenToWorld(permissions []types.IpPermission) bool {
+	for _, permission := range permissions {
+		for _, ipRange := range permission.IpRanges {
+			if aws.ToString(ipRange.CidrIp) == anyIPv4Range {
+				return true
+			}
+		}
+		for _, ipRange := range permission.Ipv6Ranges {
+			if aws.ToString(ipRange.CidrIpv6) == anyIPv6Range {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseSecurityGroupARN extracts the security group ID and region from a security group ARN
+func ParseSecurityGroupARN(resourceARN string) (string, string, error) {
+	// ARN format: arn:partition:ec2:region:account-id:security-group/sg-id
+	parsed, err := arn.ParseRegionalService(resourceARN, "ec2")
+	if err != nil {
+		return "", "", fmt.Errorf("invalid security group ARN format: %w", err)
+	}
+
+	groupID, ok := parsed.ResourceName("security-group")
+	if !ok || !strings.HasPrefix(groupID, "sg-") {
+		return "", "", fmt.Errorf("invalid security group ID in ARN: %s", resourceARN)
+	}
+	return groupID, parsed.Region, nil
+}
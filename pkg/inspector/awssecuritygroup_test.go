@@ -0,0 +1,196 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSecurityGroupClient serves the security groups pageSize items per page
+type mockSecurityGroupClient struct {
+	groups   []types.SecurityGroup
+	pageSize int
+}
+
+func (m *mockSecurityGroupClient) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	if len(params.GroupIds) > 0 {
+		output := &ec2.DescribeSecurityGroupsOutput{}
+		for _, group := range m.groups {
+			if aws.ToString(group.GroupId) == params.GroupIds[0] {
+				output.SecurityGroups = append(output.SecurityGroups, group)
+			}
+		}
+		return output, nil
+	}
+
+	start := 0
+	if params.NextToken != nil {
+		start, _ = strconv.Atoi(*params.NextToken)
+	}
+	end := min(start+m.pageSize, len(m.groups))
+
+	output := &ec2.DescribeSecurityGroupsOutput{SecurityGroups: m.groups[start:end]}
+	if end < len(m.groups) {
+		output.NextToken = aws.String(strconv.Itoa(end))
+	}
+	return output, nil
+}
+
+// newMockSecurityGroups returns 5 security groups, the web one being open to the internet
+func newMockSecurityGroups() []types.SecurityGroup {
+	var groups []types.SecurityGroup
+	for i := 0; i < 4; i++ {
+		groups = append(groups, types.SecurityGroup{
+			GroupId:     aws.String(fmt.Sprintf("sg-%04d", i)),
+			GroupName:   aws.String(fmt.Sprintf("internal-%d", i)),
+			Description: aws.String("Internal traffic"),
+			VpcId:       aws.String("vpc-0abc"),
+			OwnerId:     aws.String("123456789012"),
+			IpPermissions: []types.IpPermission{{
+				IpProtocol: aws.String("tcp"),
+				IpRanges:   []types.IpRange{{CidrIp: aws.String("10.0.0.0/8")}},
+				UserIdGroupPairs: []types.UserIdGroupPair{
+					{GroupId: aws.String("sg-0100")},
+				},
+			}},
+			IpPermissionsEgress: []types.IpPermission{{IpProtocol: aws.String("-1"), IpRanges: []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}}},
+			Tags:                []types.Tag{{Key: aws.String("Team"), Value: aws.String("network")}},
+		})
+	}
+
+	groups = append(groups, types.SecurityGroup{
+		GroupId:   aws.String("sg-web"),
+		GroupName: aws.String("web"),
+		VpcId:     aws.String("vpc-0abc"),
+		OwnerId:   aws.String("123456789012"),
+		IpPermissions: []types.IpPermission{
+			{IpProtocol: aws.String("tcp"), Ipv6Ranges: []types.Ipv6Range{{CidrIpv6: aws.String("::/0")}}},
+			{IpProtocol: aws.String("tcp"), PrefixListIds: []types.PrefixListId{{PrefixListId: aws.String("pl-0abc")}}},
+		},
+	})
+	return groups
+}
+
+func TestSecurityGroupInspectorDiscoversGroups(t *testing.T) {
+	t.Parallel()
+
+	client := &mockSecurityGroupClient{groups: newMockSecurityGroups(), pageSize: 2}
+	clientFor := func(region string) (SecurityGroupAPI, error) {
+		return client, nil
+	}
+
+	inspector := &SecurityGroupInspector{
+		Regions: []string{"eu-west-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	discoverer, processor := inspector.newScanFuncs(clientFor, "123456789012")
+
+	resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(context.Background(), []string{"eu-west-1"}, discoverer, processor)
+	require.NoError(t, err)
+	require.Len(t, resources, 5)
+
+	for _, resource := range resources {
+		assert.Equal(t, constants.ResourceTypeSecurityGroup, resource.Type)
+		assert.Equal(t, "arn:aws:ec2:eu-west-1:123456789012:security-group/"+resource.ID, resource.Details.ARN)
+		assert.Equal(t, resource.Details.Properties["group_name"], resource.Details.Name)
+		assert.Equal(t, "vpc-0abc", resource.Details.Properties["vpc_id"])
+
+		if resource.ID == "sg-web" {
+			assert.Equal(t, true, resource.Details.Properties["open_to_world"])
+			assert.Equal(t, 2, resource.Details.Properties["inbound_rules"])
+			assert.Equal(t, 0, resource.Details.Properties["outbound_rules"])
+			assert.Empty(t, resource.Tags)
+			continue
+		}
+
+		// An egress rule open to the internet does not make the group open to the world
+		assert.Equal(t, false, resource.Details.Properties["open_to_world"])
+		assert.Equal(t, 2, resource.Details.Properties["inbound_rules"])
+		assert.Equal(t, 1, resource.Details.Properties["outbound_rules"])
+		assert.Equal(t, map[string]string{"Team": "network"}, resource.Tags)
+	}
+}
+
+func TestSecurityGroupInspectorFetch(t *testing.T) {
+	t.Parallel()
+
+	client := &mockSecurityGroupClient{groups: newMockSecurityGroups(), pageSize: 2}
+	clientFor := func(region string) (SecurityGroupAPI, error) {
+		if region != "eu-west-1" {
+			return nil, fmt.Errorf("unexpected region %s", region)
+		}
+		return client, nil
+	}
+	inspector := &SecurityGroupInspector{Logger: o11y.NewLogger(io.Discard, o11y.LogLevelError)}
+
+	metadata, err := inspector.fetch(context.Background(), "arn:aws:ec2:eu-west-1:123456789012:security-group/sg-web", clientFor, "123456789012")
+	require.NoError(t, err)
+	assert.Equal(t, "web", metadata.Details.Name)
+	assert.Equal(t, true, metadata.Details.Properties["open_to_world"])
+
+	_, err = inspector.fetch(context.Background(), "arn:aws:ec2:eu-west-1:123456789012:security-group/sg-missing", clientFor, "123456789012")
+	assert.Error(t, err)
+}
+
+func TestParseSecurityGroupARN(t *testing.T) {
+	t.Parallel()
+
+	groupID, region, err := ParseSecurityGroupARN("arn:aws:ec2:us-east-1:123456789012:security-group/sg-0abc123")
+	require.NoError(t, err)
+	assert.Equal(t, "sg-0abc123", groupID)
+	assert.Equal(t, "us-east-1", region)
+
+	for _, invalid := range []string{
+		"arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0abc",
+		"arn:aws:ec2:us-east-1:123456789012:security-group/",
+		"arn:aws:rds:us-east-1:123456789012:security-group/sg-0abc",
+		"sg-0abc123",
+	} {
+		_, _, err := ParseSecurityGroupARN(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+// probedSecurityGroupClient is a mockSecurityGroupClient recording the calls of a probe
+type probedSecurityGroupClient struct {
+	*mockSecurityGroupClient
+	calls *probeCalls
+}
+
+func (c *probedSecurityGroupClient) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	if err := c.calls.call("ec2:DescribeSecurityGroups"); err != nil {
+		return nil, err
+	}
+	return c.mockSecurityGroupClient.DescribeSecurityGroups(ctx, params, optFns...)
+}
+
+func TestSecurityGroupInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &SecurityGroupInspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	for _, denied := range []string{"", "ec2:DescribeSecurityGroups"} {
+		calls := &probeCalls{denied: denied}
+		clientFor := func(region string) (SecurityGroupAPI, error) {
+			return &probedSecurityGroupClient{mockSecurityGroupClient: &mockSecurityGroupClient{groups: newMockSecurityGroups(), pageSize: 5}, calls: calls}, nil
+		}
+
+		err := inspector.probe(context.Background(), "us-east-1", clientFor)
+		assertProbeError(t, err, denied)
+		assert.Equal(t, []string{"ec2:DescribeSecurityGroups"}, calls.made)
+	}
+}
@@ -6,6 +6,7 @@
 	"strings"
 	"time"
 
+	"github.com/Excoriate/aws-taggy/pkg/arn"
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
 	"github.com/Excoriate/aws-taggy/pkg/o11y"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -39,6 +40,15 @@ func (m *AWSClientManager) GetSNSClient(region string) (*sns.Client, error) {
 	return client.(*sns.Client), nil
 }
 
+// SNSAPI is the subset of the SNS client used to discover topics
+type SNSAPI interface {
+	sns.ListTopicsAPIClient
+	ListTagsForResource(ctx context.Context, params *sns.ListTagsForResourceInput, optFns ...func(*sns.Options)) (*sns.ListTagsForResourceOutput, error)
+}
+
+// snsClientProvider returns the SNS client to use for a region
+type snsClientProvider func(region string) (SNSAPI, error)
+
 // SNSInspector implements the Inspector interface for AWS SNS resources
 type SNSInspector struct {
 	Regions       []string
@@ -74,8 +84,11 @@ func (s *SNSInspector) Inspect(ctx context.Context, config configuration.TaggySc
 		Region:    s.Regions[0],
 	}
 
-	// Create async scanner with default config
-	scanner := NewAsyncResourceInspector(DefaultInspectorConfig())
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := s.ClientManager.resolveAccountID(ctx, s.Logger)
 
 	// Define the resource discoverer function
 	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
@@ -94,7 +107,7 @@ func (s *SNSInspector) Inspect(ctx context.Context, config configuration.TaggySc
 		// Convert to interface slice
 		resources := make([]interface{}, len(topics))
 		for i, topic := range topics {
-			resources[i] = topic
+			resources[i] = RegionalResource{Region: region, Item: topic}
 		}
 
 		return resources, nil
@@ -102,10 +115,11 @@ func (s *SNSInspector) Inspect(ctx context.Context, config configuration.TaggySc
 
 	// Define the resource processor function
 	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
-		topic := resource.(types.Topic)
+		regional := resource.(RegionalResource)
+		topic := regional.Item.(types.Topic)
 
-		// Get SNS client for initial region
-		snsClient, err := s.ClientManager.GetSNSClient(s.Regions[0])
+		// Get SNS client for the topic region
+		snsClient, err := s.ClientManager.GetSNSClient(regional.Region)
 		if err != nil {
 			return ResourceMetadata{}, fmt.Errorf("failed to get SNS client: %w", err)
 		}
@@ -121,13 +135,15 @@ func (s *SNSInspector) Inspect(ctx context.Context, config configuration.TaggySc
 
 		// Create resource metadata
 		metadata := ResourceMetadata{
-			ID:           *topic.TopicArn,
-			Type:         "sns",
-			Provider:     "aws",
-			Region:       s.Regions[0], // SNS is regional
-			DiscoveredAt: time.Now(),
-			Tags:         tags,
-			RawResponse:  topic,
+			ID:            *topic.TopicArn,
+			Type:          "sns",
+			Provider:      "aws",
+			AccountID:     accountID,
+			Region:        regional.Region, // SNS is regional
+			DiscoveredAt:  time.Now(),
+			Tags:          tags,
+			TagFetchError: tagFetchError(err),
+			RawResponse:   topic,
 		}
 
 		// Populate extended details
@@ -159,8 +175,42 @@ func (s *SNSInspector) Inspect(ctx context.Context, config configuration.TaggySc
 	return result, nil
 }
 
+// regionalClient returns the SNS client of a region from the client manager
+func (s *SNSInspector) regionalClient(region string) (SNSAPI, error) {
+	client, err := s.ClientManager.GetSNSClient(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe lists the first page of topics of the region, then reads the tags of one of them
+func (s *SNSInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return s.probe(ctx, region, s.regionalClient)
+}
+
+// probe issues the calls of Probe with the clients of clientFor
+func (s *SNSInspector) probe(ctx context.Context, region string, clientFor snsClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get SNS client: %w", err)
+	}
+
+	// ListTopics takes no page size
+	output, err := client.ListTopics(ctx, &sns.ListTopicsInput{})
+	if err != nil {
+		return probeCall("sns:ListTopics", err)
+	}
+	if len(output.Topics) == 0 {
+		return nil
+	}
+
+	_, err = client.ListTagsForResource(ctx, &sns.ListTagsForResourceInput{ResourceArn: output.Topics[0].TopicArn})
+	return probeCall("sns:ListTagsForResource", err)
+}
+
 // listTopics retrieves all SNS topics
-func (s *SNSInspector) listTopics(ctx context.Context, client *sns.Client) ([]types.Topic, error) {
+func (s *SNSInspector) listTopics(ctx context.Context, client SNSAPI) ([]types.Topic, error) {
 	var topics []types.Topic
 	paginator := sns.NewListTopicsPaginator(client, &sns.ListTopicsInput{})
 
@@ -176,7 +226,7 @@ func (s *SNSInspector) listTopics(ctx context.Context, client *sns.Client) ([]ty
 }
 
 // getTopicTags retrieves tags for a specific SNS topic
-func (s *SNSInspector) getTopicTags(ctx context.Context, client *sns.Client, topicARN string) (map[string]string, error) {
+func (s *SNSInspector) getTopicTags(ctx context.Context, client SNSAPI, topicARN string) (map[string]string, error) {
 	// List tags for the topic
 	tagsOutput, err := client.ListTagsForResource(ctx, &sns.ListTagsForResourceInput{
 		ResourceArn: aws.String(topicARN),
@@ -195,9 +245,8 @@ func (s *SNSInspector) getTopicTags(ctx context.Context, client *sns.Client, top
 
 // getTopicName extracts the topic name from its ARN
 func (s *SNSInspector) getTopicName(topicARN string) string {
-	parts := strings.Split(topicARN, ":")
-	if len(parts) > 0 {
-		return parts[len(parts)-1]
+	if parsed, err := arn.Parse(topicARN); err == nil {
+		return parsed.Resource
 	}
 	return "Unnamed Topic"
 }
@@ -225,12 +274,14 @@ func (s *SNSInspector) Fetch(ctx context.Context, arn string, config configurati
 
 	// Create resource metadata
 	resourceMeta := &ResourceMetadata{
-		ID:           topicARN,
-		Type:         "sns",
-		Provider:     "aws",
-		Region:       region,
-		Tags:         tags,
-		DiscoveredAt: time.Now(),
+		ID:            topicARN,
+		Type:          "sns",
+		Provider:      "aws",
+		AccountID:     s.ClientManager.resolveAccountID(ctx, s.Logger),
+		Region:        region,
+		Tags:          tags,
+		TagFetchError: tagFetchError(err),
+		DiscoveredAt:  time.Now(),
 	}
 
 	// Populate extended details
@@ -244,12 +295,16 @@ func (s *SNSInspector) Fetch(ctx context.Context, arn string, config configurati
 }
 
 // ParseSNSARN extracts topic ARN and region from SNS ARN
-func ParseSNSARN(arn string) (string, string, error) {
-	// ARN format: arn:aws:sns:region:account-id:topic-name
-	parts := strings.Split(arn, ":")
-	if len(parts) != 6 {
-		return "", "", fmt.Errorf("invalid SNS ARN format: %s", arn)
-	}
-	region := parts[3]
-	return arn, region, nil
+func ParseSNSARN(resourceARN string) (string, string, error) {
+	// ARN format: arn:partition:sns:region:account-id:topic-name
+	parsed, err := arn.ParseRegionalService(resourceARN, "sns")
+	if err != nil {
+		return "", "", fmt.Errorf("invalid SNS ARN format: %w", err)
+	}
+
+	// Subscription ARNs follow the topic name with a colon and the subscription ID
+	if strings.Contains(parsed.Resource, ":") {
+		return "", "", fmt.Errorf("invalid SNS topic in ARN: %s", resourceARN)
+	}
+	return resourceARN, parsed.Region, nil
 }
@@ -0,0 +1,89 @@
+package inspector
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSNSClient serves its topics in a single page, recording the calls made
+type mockSNSClient struct {
+	topics []types.Topic
+	calls  *probeCalls
+}
+
+func (m *mockSNSClient) ListTopics(ctx context.Context, params *sns.ListTopicsInput, optFns ...func(*sns.Options)) (*sns.ListTopicsOutput, error) {
+	if err := m.calls.call("sns:ListTopics"); err != nil {
+		return nil, err
+	}
+	return &sns.ListTopicsOutput{Topics: m.topics}, nil
+}
+
+func (m *mockSNSClient) ListTagsForResource(ctx context.Context, params *sns.ListTagsForResourceInput, optFns ...func(*sns.Options)) (*sns.ListTagsForResourceOutput, error) {
+	if err := m.calls.call("sns:ListTagsForResource"); err != nil {
+		return nil, err
+	}
+	return &sns.ListTagsForResourceOutput{
+		Tags: []types.Tag{{Key: aws.String("Environment"), Value: aws.String("production")}},
+	}, nil
+}
+
+func TestSNSInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &SNSInspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	topics := []types.Topic{{TopicArn: aws.String("arn:aws:sns:us-east-1:123456789012:alerts")}}
+
+	tests := []struct {
+		name          string
+		topics        []types.Topic
+		denied        string
+		expectedCalls []string
+	}{
+		{
+			name:          "Success",
+			topics:        topics,
+			expectedCalls: []string{"sns:ListTopics", "sns:ListTagsForResource"},
+		},
+		{
+			name:          "No Topic",
+			expectedCalls: []string{"sns:ListTopics"},
+		},
+		{
+			name:          "List Denied",
+			topics:        topics,
+			denied:        "sns:ListTopics",
+			expectedCalls: []string{"sns:ListTopics"},
+		},
+		{
+			name:          "Tags Denied",
+			topics:        topics,
+			denied:        "sns:ListTagsForResource",
+			expectedCalls: []string{"sns:ListTopics", "sns:ListTagsForResource"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := &probeCalls{denied: tt.denied}
+			clientFor := func(region string) (SNSAPI, error) {
+				return &mockSNSClient{topics: tt.topics, calls: calls}, nil
+			}
+
+			err := inspector.probe(context.Background(), "us-east-1", clientFor)
+			assertProbeError(t, err, tt.denied)
+			assert.Equal(t, tt.expectedCalls, calls.made)
+		})
+	}
+}
@@ -3,9 +3,11 @@
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Excoriate/aws-taggy/pkg/arn"
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
 	"github.com/Excoriate/aws-taggy/pkg/o11y"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -39,6 +41,17 @@ func (m *AWSClientManager) GetSQSClient(region string) (*sqs.Client, error) {
 	return client.(*sqs.Client), nil
 }
 
+// SQSAPI is the subset of the SQS client used by the SQSInspector
+type SQSAPI interface {
+	sqs.ListQueuesAPIClient
+	GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
+	ListQueueTags(ctx context.Context, params *sqs.ListQueueTagsInput, optFns ...func(*sqs.Options)) (*sqs.ListQueueTagsOutput, error)
+	GetQueueUrl(ctx context.Context, params *sqs.GetQueueUrlInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error)
+}
+
+// sqsClientProvider returns the SQS client to use for a region
+type sqsClientProvider func(region string) (SQSAPI, error)
+
 // SQSInspector implements the Inspector interface for AWS SQS resources
 type SQSInspector struct {
 	Regions       []string
@@ -74,82 +87,20 @@ func (s *SQSInspector) Inspect(ctx context.Context, config configuration.TaggySc
 		Region:    s.Regions[0],
 	}
 
-	// Create async scanner with default config
-	scanner := NewAsyncResourceInspector(DefaultInspectorConfig())
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := s.ClientManager.resolveAccountID(ctx, s.Logger)
 
 	// Define the resource discoverer function
 	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
-		// Get SQS client for this region
-		sqsClient, err := s.ClientManager.GetSQSClient(region)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get SQS client: %w", err)
-		}
-
-		// List queues
-		queues, err := s.listQueues(ctx, sqsClient)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list queues: %w", err)
-		}
-
-		// Convert to interface slice
-		resources := make([]interface{}, len(queues))
-		for i, queueURL := range queues {
-			resources[i] = queueURL
-		}
-
-		return resources, nil
+		return s.discoverQueues(ctx, region, s.regionalClient)
 	}
 
 	// Define the resource processor function
 	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
-		queueURL := resource.(string)
-
-		// Get SQS client for initial region
-		sqsClient, err := s.ClientManager.GetSQSClient(s.Regions[0])
-		if err != nil {
-			return ResourceMetadata{}, fmt.Errorf("failed to get SQS client: %w", err)
-		}
-
-		// Get queue attributes to fetch ARN and other details
-		attributes, err := s.getQueueAttributes(ctx, sqsClient, queueURL)
-		if err != nil {
-			return ResourceMetadata{}, fmt.Errorf("failed to get queue attributes: %w", err)
-		}
-
-		// Get queue tags
-		tags, err := s.getQueueTags(ctx, sqsClient, queueURL)
-		if err != nil {
-			s.Logger.Warn("Failed to get queue tags",
-				"queue_url", queueURL,
-				"error", err)
-			tags = make(map[string]string)
-		}
-
-		queueARN := attributes["QueueArn"]
-
-		// Create resource metadata
-		metadata := ResourceMetadata{
-			ID:           queueARN,
-			Type:         "sqs",
-			Provider:     "aws",
-			Region:       s.Regions[0], // SQS is regional
-			DiscoveredAt: time.Now(),
-			Tags:         tags,
-			RawResponse:  attributes,
-		}
-
-		// Populate extended details
-		metadata.Details.ARN = queueARN
-		metadata.Details.Name = s.getQueueName(queueURL)
-		metadata.Details.Properties = map[string]interface{}{
-			"queue_url":          queueURL,
-			"queue_arn":          queueARN,
-			"visibility_timeout": attributes["VisibilityTimeout"],
-			"delay_seconds":      attributes["DelaySeconds"],
-			"queue_type":         attributes["FifoQueue"],
-		}
-
-		return metadata, nil
+		return s.processQueue(ctx, resource.(RegionalResource), accountID, s.regionalClient)
 	}
 
 	// Perform the async scan
@@ -171,8 +122,127 @@ func (s *SQSInspector) Inspect(ctx context.Context, config configuration.TaggySc
 	return result, nil
 }
 
+// regionalClient returns the SQS client of a region from the client manager
+func (s *SQSInspector) regionalClient(region string) (SQSAPI, error) {
+	client, err := s.ClientManager.GetSQSClient(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe lists a single queue of the region, then reads its attributes and tags
+func (s *SQSInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return s.probe(ctx, region, s.regionalClient)
+}
+
+// probe issues the calls of Probe with the clients of clientFor
+func (s *SQSInspector) probe(ctx context.Context, region string, clientFor sqsClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get SQS client: %w", err)
+	}
+
+	output, err := client.ListQueues(ctx, &sqs.ListQueuesInput{MaxResults: aws.Int32(1)})
+	if err != nil {
+		return probeCall("sqs:ListQueues", err)
+	}
+	if len(output.QueueUrls) == 0 {
+		return nil
+	}
+
+	queueURL := aws.String(output.QueueUrls[0])
+	if _, err := client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       queueURL,
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameQueueArn},
+	}); err != nil {
+		return probeCall("sqs:GetQueueAttributes", err)
+	}
+	_, err = client.ListQueueTags(ctx, &sqs.ListQueueTagsInput{QueueUrl: queueURL})
+	return probeCall("sqs:ListQueueTags", err)
+}
+
+// discoverQueues lists the queues of a region, keeping track of the region each queue was
+// found in so it is processed with a client of that region
+func (s *SQSInspector) discoverQueues(ctx context.Context, region string, clientFor sqsClientProvider) ([]interface{}, error) {
+	// Get SQS client for this region
+	sqsClient, err := clientFor(region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SQS client: %w", err)
+	}
+
+	// List queues
+	queues, err := s.listQueues(ctx, sqsClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queues: %w", err)
+	}
+
+	// Convert to interface slice, keeping track of the queue region
+	resources := make([]interface{}, len(queues))
+	for i, queueURL := range queues {
+		resources[i] = RegionalResource{Region: region, Item: queueURL}
+	}
+
+	return resources, nil
+}
+
+// processQueue builds the metadata of a queue, querying it in the region it was discovered in
+func (s *SQSInspector) processQueue(ctx context.Context, resource RegionalResource, accountID string, clientFor sqsClientProvider) (ResourceMetadata, error) {
+	queueURL := resource.Item.(string)
+
+	// Get SQS client for the queue region
+	sqsClient, err := clientFor(resource.Region)
+	if err != nil {
+		return ResourceMetadata{}, fmt.Errorf("failed to get SQS client: %w", err)
+	}
+
+	// Get queue attributes to fetch ARN and other details
+	attributes, err := s.getQueueAttributes(ctx, sqsClient, queueURL)
+	if err != nil {
+		return ResourceMetadata{}, fmt.Errorf("failed to get queue attributes: %w", err)
+	}
+
+	// Get queue tags
+	tags, err := s.getQueueTags(ctx, sqsClient, queueURL)
+	if err != nil {
+		s.Logger.Warn("Failed to get queue tags",
+			"queue_url", queueURL,
+			"error", err)
+		tags = make(map[string]string)
+	}
+
+	queueARN := attributes["QueueArn"]
+
+	// Create resource metadata
+	metadata := ResourceMetadata{
+		ID:            queueARN,
+		Type:          "sqs",
+		Provider:      "aws",
+		AccountID:     accountID,
+		Region:        resource.Region, // SQS is regional
+		DiscoveredAt:  time.Now(),
+		CreatedAt:     queueCreatedAt(attributes),
+		Tags:          tags,
+		TagFetchError: tagFetchError(err),
+		RawResponse:   attributes,
+	}
+
+	// Populate extended details
+	metadata.Details.ARN = queueARN
+	metadata.Details.Name = s.getQueueName(queueURL)
+	metadata.Details.Properties = map[string]interface{}{
+		"queue_url":          queueURL,
+		"queue_arn":          queueARN,
+		"visibility_timeout": attributes["VisibilityTimeout"],
+		"delay_seconds":      attributes["DelaySeconds"],
+		"queue_type":         attributes["FifoQueue"],
+	}
+
+	return metadata, nil
+}
+
 // listQueues retrieves all SQS queues
-func (s *SQSInspector) listQueues(ctx context.Context, client *sqs.Client) ([]string, error) {
+func (s *SQSInspector) listQueues(ctx context.Context, client SQSAPI) ([]string, error) {
 	var queueURLs []string
 	paginator := sqs.NewListQueuesPaginator(client, &sqs.ListQueuesInput{})
 
@@ -188,13 +258,14 @@ func (s *SQSInspector) listQueues(ctx context.Context, client *sqs.Client) ([]st
 }
 
 // getQueueAttributes retrieves the attributes for a specific SQS queue
-func (s *SQSInspector) getQueueAttributes(ctx context.Context, client *sqs.Client, queueURL string) (map[string]string, error) {
+func (s *SQSInspector) getQueueAttributes(ctx context.Context, client SQSAPI, queueURL string) (map[string]string, error) {
 	// Define the attributes we want to retrieve
 	attributeNames := []types.QueueAttributeName{
 		types.QueueAttributeNameVisibilityTimeout,
 		types.QueueAttributeNameDelaySeconds,
 		types.QueueAttributeNameFifoQueue,
 		types.QueueAttributeNameQueueArn,
+		types.QueueAttributeNameCreatedTimestamp,
 	}
 
 	// Get queue attributes
@@ -216,7 +287,7 @@ func (s *SQSInspector) getQueueAttributes(ctx context.Context, client *sqs.Clien
 }
 
 // getQueueTags retrieves the tags for a specific SQS queue
-func (s *SQSInspector) getQueueTags(ctx context.Context, client *sqs.Client, queueURL string) (map[string]string, error) {
+func (s *SQSInspector) getQueueTags(ctx context.Context, client SQSAPI, queueURL string) (map[string]string, error) {
 	// List tags for the queue
 	tagsResult, err := client.ListQueueTags(ctx, &sqs.ListQueueTagsInput{
 		QueueUrl: aws.String(queueURL),
@@ -238,6 +309,16 @@ func (s *SQSInspector) getQueueName(queueURL string) string {
 	return ""
 }
 
+// queueCreatedAt returns the creation time of a queue from its CreatedTimestamp attribute,
+// in seconds since the epoch, zero when missing
+func queueCreatedAt(attributes map[string]string) time.Time {
+	seconds, err := strconv.ParseInt(attributes[string(types.QueueAttributeNameCreatedTimestamp)], 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0).UTC()
+}
+
 // Fetch implements the Scanner interface for retrieving specific SQS queue details
 func (s *SQSInspector) Fetch(ctx context.Context, arn string, config configuration.TaggyScanConfig) (*ResourceMetadata, error) {
 	// Parse queue ARN
@@ -275,13 +356,16 @@ func (s *SQSInspector) Fetch(ctx context.Context, arn string, config configurati
 
 	// Create resource metadata
 	resourceMeta := &ResourceMetadata{
-		ID:           arn,
-		Type:         "sqs",
-		Provider:     "aws",
-		Region:       region,
-		Tags:         tags,
-		DiscoveredAt: time.Now(),
-		RawResponse:  attributes,
+		ID:            arn,
+		Type:          "sqs",
+		Provider:      "aws",
+		AccountID:     s.ClientManager.resolveAccountID(ctx, s.Logger),
+		Region:        region,
+		Tags:          tags,
+		TagFetchError: tagFetchError(err),
+		DiscoveredAt:  time.Now(),
+		CreatedAt:     queueCreatedAt(attributes),
+		RawResponse:   attributes,
 	}
 
 	// Populate extended details
@@ -299,19 +383,21 @@ func (s *SQSInspector) Fetch(ctx context.Context, arn string, config configurati
 }
 
 // ParseSQSARN extracts queue ARN and region from SQS ARN
-func ParseSQSARN(arn string) (string, string, error) {
-	// ARN format: arn:aws:sqs:region:account-id:queue-name
-	parts := strings.Split(arn, ":")
-	if len(parts) != 6 {
-		return "", "", fmt.Errorf("invalid SQS ARN format: %s", arn)
-	}
-	region := parts[3]
-	queueName := parts[5]
-	return queueName, region, nil
+func ParseSQSARN(resourceARN string) (string, string, error) {
+	// ARN format: arn:partition:sqs:region:account-id:queue-name
+	parsed, err := arn.ParseRegionalService(resourceARN, "sqs")
+	if err != nil {
+		return "", "", fmt.Errorf("invalid SQS ARN format: %w", err)
+	}
+
+	if strings.ContainsAny(parsed.Resource, "/:") {
+		return "", "", fmt.Errorf("invalid SQS queue in ARN: %s", resourceARN)
+	}
+	return parsed.Resource, parsed.Region, nil
 }
 
 // getQueueURLFromARN retrieves the queue URL using the ARN
-func (s *SQSInspector) getQueueURLFromARN(ctx context.Context, client *sqs.Client, queueName string) (string, error) {
+func (s *SQSInspector) getQueueURLFromARN(ctx context.Context, client SQSAPI, queueName string) (string, error) {
 	// Get queue URL
 	result, err := client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
 		QueueName: aws.String(queueName),
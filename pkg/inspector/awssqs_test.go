@@ -0,0 +1,134 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSQSClient is a regional SQSAPI serving the queues of its own region only
+type mockSQSClient struct {
+	region string
+	queues map[string]map[string]string // queue URL -> tags
+
+	mu        *sync.Mutex
+	tagLookup map[string]string // queue URL -> region of the client that read its tags
+}
+
+func (m *mockSQSClient) ListQueues(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
+	output := &sqs.ListQueuesOutput{}
+	for queueURL := range m.queues {
+		output.QueueUrls = append(output.QueueUrls, queueURL)
+	}
+	return output, nil
+}
+
+func (m *mockSQSClient) GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	if _, ok := m.queues[*params.QueueUrl]; !ok {
+		return nil, fmt.Errorf("queue %s does not exist in %s", *params.QueueUrl, m.region)
+	}
+	return &sqs.GetQueueAttributesOutput{
+		Attributes: map[string]string{"QueueArn": "arn:aws:sqs:" + m.region + ":123456789012:queue"},
+	}, nil
+}
+
+func (m *mockSQSClient) ListQueueTags(ctx context.Context, params *sqs.ListQueueTagsInput, optFns ...func(*sqs.Options)) (*sqs.ListQueueTagsOutput, error) {
+	m.mu.Lock()
+	m.tagLookup[*params.QueueUrl] = m.region
+	m.mu.Unlock()
+
+	tags, ok := m.queues[*params.QueueUrl]
+	if !ok {
+		return nil, fmt.Errorf("queue %s does not exist in %s", *params.QueueUrl, m.region)
+	}
+	return &sqs.ListQueueTagsOutput{Tags: tags}, nil
+}
+
+func (m *mockSQSClient) GetQueueUrl(ctx context.Context, params *sqs.GetQueueUrlInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func TestSQSInspectorProcessesQueuesInTheirRegion(t *testing.T) {
+	t.Parallel()
+
+	queuesByRegion := map[string]map[string]map[string]string{
+		"us-east-1": {
+			"https://sqs.us-east-1.amazonaws.com/123456789012/orders": {"Team": "orders"},
+		},
+		"eu-west-1": {
+			"https://sqs.eu-west-1.amazonaws.com/123456789012/billing": {"Team": "billing"},
+		},
+	}
+
+	mu := &sync.Mutex{}
+	tagLookup := make(map[string]string)
+	clientFor := func(region string) (SQSAPI, error) {
+		return &mockSQSClient{region: region, queues: queuesByRegion[region], mu: mu, tagLookup: tagLookup}, nil
+	}
+
+	inspector := &SQSInspector{
+		Regions: []string{"us-east-1", "eu-west-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
+		return inspector.discoverQueues(ctx, region, clientFor)
+	}
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		return inspector.processQueue(ctx, resource.(RegionalResource), "123456789012", clientFor)
+	}
+
+	resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(context.Background(), inspector.Regions, discoverer, processor)
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	for _, resource := range resources {
+		queueURL := resource.Details.Properties["queue_url"].(string)
+		expectedTags := queuesByRegion[resource.Region][queueURL]
+
+		require.NotNil(t, expectedTags, "queue %s reported in the wrong region %s", queueURL, resource.Region)
+		assert.Equal(t, expectedTags, resource.Tags)
+		assert.Equal(t, resource.Region, tagLookup[queueURL], "tags of %s must be read with a client of its region", queueURL)
+	}
+}
+
+func TestSQSInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	queueURL := "https://sqs.us-east-1.amazonaws.com/123456789012/orders"
+	mu := &sync.Mutex{}
+	tagLookup := make(map[string]string)
+	clientFor := func(region string) (SQSAPI, error) {
+		return &mockSQSClient{
+			region:    region,
+			queues:    map[string]map[string]string{queueURL: {"Team": "orders"}},
+			mu:        mu,
+			tagLookup: tagLookup,
+		}, nil
+	}
+
+	inspector := &SQSInspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	require.NoError(t, inspector.probe(context.Background(), "us-east-1", clientFor))
+	assert.Equal(t, map[string]string{queueURL: "us-east-1"}, tagLookup)
+
+	failing := func(region string) (SQSAPI, error) {
+		return nil, fmt.Errorf("no credentials")
+	}
+	err := inspector.probe(context.Background(), "us-east-1", failing)
+	require.Error(t, err)
+	var probeErr *ProbeError
+	assert.False(t, errors.As(err, &probeErr), "a client that cannot be created names no IAM action")
+}
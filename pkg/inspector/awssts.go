@@ -0,0 +1,161 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// accountIDCache caches resolved account IDs keyed by the access key ID of the credential set,
+// so every inspector sharing the same credentials issues a single GetCallerIdentity call.
+// Credentials without an access key ID cannot be told apart, their account is never cached.
+var accountIDCache sync.Map
+
+// STSClientCreator implements AWSClient for STS
+type STSClientCreator struct{}
+
+// CreateFromConfig creates a new STS client from the provided AWS configuration.
+func (c *STSClientCreator) CreateFromConfig(cfg *aws.Config) interface{} {
+	return sts.NewFromConfig(*cfg)
+}
+
+// GetSTSClient retrieves an STS client for the specified AWS region.
+func (m *AWSClientManager) GetSTSClient(region string) (*sts.Client, error) {
+	client, err := m.GetClient(region, &STSClientCreator{})
+	if err != nil {
+		return nil, err
+	}
+	return client.(*sts.Client), nil
+}
+
+// GetAccountID resolves the AWS account ID of the credentials used by the manager.
+//
+// For managers created for a configured account, the declared account ID is returned.
+// Otherwise the account ID is looked up once through STS GetCallerIdentity and cached per credential
+// set (identified by its access key ID), so repeated calls across inspectors are cheap. It is
+// looked up on every call when the access key ID of the credentials is unknown.
+//
+// Parameters:
+//   - ctx: Context for the STS API call
+//
+// Returns:
+//   - string: The 12-digit AWS account ID
+//   - error: An error if the credentials cannot be retrieved or the STS call fails
+func (m *AWSClientManager) GetAccountID(ctx context.Context) (string, error) {
+	// Accounts scanned through AssumeRole declare their ID in the configuration
+	if m.account != nil {
+		return m.account.AccountID, nil
+	}
+
+	region := m.anyRegion()
+
+	m.mu.RLock()
+	cfg, exists := m.clients[m.clientKey(region)]
+	m.mu.RUnlock()
+
+	cacheKey := ""
+	if exists && cfg.Credentials != nil {
+		creds, err := cfg.Credentials.Retrieve(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+		}
+		cacheKey = creds.AccessKeyID
+	}
+
+	return cachedAccountID(cacheKey, func() (string, error) {
+		stsClient, err := m.GetSTSClient(region)
+		if err != nil {
+			return "", fmt.Errorf("failed to get STS client: %w", err)
+		}
+
+		identity, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get caller identity: %w", err)
+		}
+		return aws.ToString(identity.Account), nil
+	})
+}
+
+// cachedAccountID returns the account ID cached for the credentials with the given access
+// key ID, resolving and caching it with lookup on the first call. An empty access key ID
+// identifies no credential set, so lookup resolves the account ID on every call instead of
+// sharing it between identities.
+func cachedAccountID(accessKeyID string, lookup func() (string, error)) (string, error) {
+	if accessKeyID == "" {
+		return lookup()
+	}
+	if cached, ok := accountIDCache.Load(accessKeyID); ok {
+		return cached.(string), nil
+	}
+
+	accountID, err := lookup()
+	if err != nil {
+		return "", err
+	}
+	accountIDCache.Store(accessKeyID, accountID)
+	return accountID, nil
+}
+
+// resolveAccountID returns the account ID of the manager's credentials, logging a warning
+// and falling back to an empty account ID when the lookup fails.
+func (m *AWSClientManager) resolveAccountID(ctx context.Context, logger *o11y.Logger) string {
+	accountID, err := m.GetAccountID(ctx)
+	if err != nil {
+		logger.Warn("Failed to resolve AWS account ID, ARNs will omit it", "error", err)
+		return ""
+	}
+	return accountID
+}
+
+// CallerIdentity is the AWS account and principal of the credentials used to scan
+type CallerIdentity struct {
+	AccountID string `json:"account_id" yaml:"account_id"`
+	ARN       string `json:"arn" yaml:"arn"`
+}
+
+// ResolveCallerIdentity looks up the account and principal of the default credentials, those
+// of the identity set with cloud.SetDefaultIdentity, through STS GetCallerIdentity in the
+// first of the regions.
+func ResolveCallerIdentity(ctx context.Context, regions []string, endpointURL string) (CallerIdentity, error) {
+	region := constants.DefaultAWSRegion
+	if len(regions) > 0 {
+		region = regions[0]
+	}
+
+	clientManager, err := NewAWSRegionalClientManager([]string{region})
+	if err != nil {
+		return CallerIdentity{}, fmt.Errorf("failed to create AWS client manager: %w", err)
+	}
+	clientManager.SetEndpointURL(endpointURL)
+
+	stsClient, err := clientManager.GetSTSClient(region)
+	if err != nil {
+		return CallerIdentity{}, fmt.Errorf("failed to get STS client: %w", err)
+	}
+
+	identity, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return CallerIdentity{}, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+
+	return CallerIdentity{
+		AccountID: aws.ToString(identity.Account),
+		ARN:       aws.ToString(identity.Arn),
+	}, nil
+}
+
+// anyRegion returns one of the regions the manager holds a configuration for
+func (m *AWSClientManager) anyRegion() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for key := range m.clients {
+		return key.region
+	}
+	return ""
+}
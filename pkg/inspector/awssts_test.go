@@ -0,0 +1,72 @@
+package inspector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// accountLookup resolves the given account IDs in turn, counting its calls
+type accountLookup struct {
+	accounts []string
+	calls    int
+}
+
+func (l *accountLookup) lookup() (string, error) {
+	accountID := l.accounts[min(l.calls, len(l.accounts)-1)]
+	l.calls++
+	return accountID, nil
+}
+
+func TestCachedAccountID(t *testing.T) {
+	t.Run("Cached Per Access Key", func(t *testing.T) {
+		lookup := &accountLookup{accounts: []string{"111111111111", "222222222222"}}
+
+		for range 3 {
+			accountID, err := cachedAccountID("AKIACACHEDTEST00001", lookup.lookup)
+			require.NoError(t, err)
+			assert.Equal(t, "111111111111", accountID)
+		}
+		assert.Equal(t, 1, lookup.calls)
+
+		// Other credentials resolve their own account
+		accountID, err := cachedAccountID("AKIACACHEDTEST00002", lookup.lookup)
+		require.NoError(t, err)
+		assert.Equal(t, "222222222222", accountID)
+		assert.Equal(t, 2, lookup.calls)
+	})
+
+	t.Run("Not Cached Without Access Key", func(t *testing.T) {
+		// Credentials without access key ID, such as those of two roles, are not told apart
+		lookup := &accountLookup{accounts: []string{"111111111111", "222222222222"}}
+
+		accountID, err := cachedAccountID("", lookup.lookup)
+		require.NoError(t, err)
+		assert.Equal(t, "111111111111", accountID)
+
+		accountID, err = cachedAccountID("", lookup.lookup)
+		require.NoError(t, err)
+		assert.Equal(t, "222222222222", accountID)
+		assert.Equal(t, 2, lookup.calls)
+
+		_, cached := accountIDCache.Load("")
+		assert.False(t, cached)
+	})
+
+	t.Run("Failures Not Cached", func(t *testing.T) {
+		failures := 0
+		_, err := cachedAccountID("AKIACACHEDTEST00003", func() (string, error) {
+			failures++
+			return "", errors.New("ExpiredToken")
+		})
+		require.Error(t, err)
+
+		lookup := &accountLookup{accounts: []string{"333333333333"}}
+		accountID, err := cachedAccountID("AKIACACHEDTEST00003", lookup.lookup)
+		require.NoError(t, err)
+		assert.Equal(t, "333333333333", accountID)
+		assert.Equal(t, 1, failures)
+	})
+}
@@ -3,9 +3,9 @@
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
+	"github.com/Excoriate/aws-taggy/pkg/arn"
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
 	"github.com/Excoriate/aws-taggy/pkg/o11y"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -13,6 +13,14 @@
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 )
 
+// VPCAPI is the subset of the EC2 client used to discover VPCs
+type VPCAPI interface {
+	ec2.DescribeVpcsAPIClient
+}
+
+// vpcClientProvider returns the EC2 client to use for a region
+type vpcClientProvider func(region string) (VPCAPI, error)
+
 // VPCInspector implements the Inspector interface for AWS VPC resources
 type VPCInspector struct {
 	Regions       []string
@@ -47,8 +55,11 @@ func (s *VPCInspector) Inspect(ctx context.Context, config configuration.TaggySc
 		Region:    s.Regions[0],
 	}
 
-	// Create async scanner with default config
-	scanner := NewAsyncResourceInspector(DefaultInspectorConfig())
+	// Create async scanner with the batch size and workers resolved for the resource type
+	scanner := NewAsyncResourceInspector(inspectorConfigFromContext(ctx))
+
+	// Resolve the account ID once for every discovered resource
+	accountID := s.ClientManager.resolveAccountID(ctx, s.Logger)
 
 	// Define the resource discoverer function
 	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
@@ -67,7 +78,7 @@ func (s *VPCInspector) Inspect(ctx context.Context, config configuration.TaggySc
 		// Convert to interface slice
 		resources := make([]interface{}, len(vpcs))
 		for i, vpc := range vpcs {
-			resources[i] = vpc
+			resources[i] = RegionalResource{Region: region, Item: vpc}
 		}
 
 		return resources, nil
@@ -75,7 +86,8 @@ func (s *VPCInspector) Inspect(ctx context.Context, config configuration.TaggySc
 
 	// Define the resource processor function
 	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
-		vpc := resource.(types.Vpc)
+		regional := resource.(RegionalResource)
+		vpc := regional.Item.(types.Vpc)
 
 		// Get VPC tags
 		tags := make(map[string]string)
@@ -88,15 +100,16 @@ func (s *VPCInspector) Inspect(ctx context.Context, config configuration.TaggySc
 			ID:           aws.ToString(vpc.VpcId),
 			Type:         "vpc",
 			Provider:     "aws",
-			Region:       s.Regions[0], // VPCs are region-specific
+			AccountID:    accountID,
+			Region:       regional.Region, // VPCs are region-specific
 			DiscoveredAt: time.Now(),
 			Tags:         tags,
 			RawResponse:  vpc,
 		}
 
 		// Populate extended details
-		metadata.Details.ARN = fmt.Sprintf("arn:aws:ec2:%s:%s:vpc/%s",
-			s.Regions[0], "unknown", aws.ToString(vpc.VpcId)) // VPCs don't have direct OwnerId
+		metadata.Details.ARN = resourceARN("ec2", regional.Region, true, accountID,
+			"vpc/"+aws.ToString(vpc.VpcId))
 		metadata.Details.Name = s.getVPCName(vpc)
 		metadata.Details.Status = s.getVPCStatus(vpc)
 		metadata.Details.Properties = map[string]interface{}{
@@ -127,8 +140,33 @@ func (s *VPCInspector) Inspect(ctx context.Context, config configuration.TaggySc
 	return result, nil
 }
 
+// regionalClient returns the EC2 client of a region from the client manager
+func (s *VPCInspector) regionalClient(region string) (VPCAPI, error) {
+	client, err := s.ClientManager.GetEC2Client(region)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Probe describes a few VPCs of the region. Their tags are part of the description.
+func (s *VPCInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return s.probe(ctx, region, s.regionalClient)
+}
+
+// probe issues the calls of Probe with the clients of clientFor
+func (s *VPCInspector) probe(ctx context.Context, region string, clientFor vpcClientProvider) error {
+	client, err := clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to get EC2 client: %w", err)
+	}
+
+	_, err = client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{MaxResults: aws.Int32(probeMaxResults)})
+	return probeCall("ec2:DescribeVpcs", err)
+}
+
 // listVPCs retrieves all VPCs in a region
-func (s *VPCInspector) listVPCs(ctx context.Context, client *ec2.Client) ([]types.Vpc, error) {
+func (s *VPCInspector) listVPCs(ctx context.Context, client VPCAPI) ([]types.Vpc, error) {
 	input := &ec2.DescribeVpcsInput{}
 	output, err := client.DescribeVpcs(ctx, input)
 	if err != nil {
@@ -198,9 +236,11 @@ func (s *VPCInspector) Fetch(ctx context.Context, arn string, config configurati
 		Type: "vpc",
 
 		Provider:     "aws",
+		AccountID:    s.ClientManager.resolveAccountID(ctx, s.Logger),
 		Region:       region,
 		Tags:         tags,
 		DiscoveredAt: time.Now(),
+		RawResponse:  vpc,
 	}
 
 	// Populate extended details
@@ -217,16 +257,16 @@ func (s *VPCInspector) Fetch(ctx context.Context, arn string, config configurati
 }
 
 // ParseVPCARN extracts VPC ID and region from VPC ARN
-func ParseVPCARN(arn string) (string, string, error) {
-	// ARN format: arn:aws:ec2:region:account-id:vpc/vpc-id
-	parts := strings.Split(arn, ":")
-	if len(parts) != 6 {
-		return "", "", fmt.Errorf("invalid VPC ARN format: %s", arn)
-	}
-	region := parts[3]
-	vpcParts := strings.Split(parts[5], "/")
-	if len(vpcParts) != 2 {
-		return "", "", fmt.Errorf("invalid VPC ID format in ARN: %s", arn)
-	}
-	return vpcParts[1], region, nil
+func ParseVPCARN(resourceARN string) (string, string, error) {
+	// ARN format: arn:partition:ec2:region:account-id:vpc/vpc-id
+	parsed, err := arn.ParseRegionalService(resourceARN, "ec2")
+	if err != nil {
+		return "", "", fmt.Errorf("invalid VPC ARN format: %w", err)
+	}
+
+	vpcID, ok := parsed.ResourceName("vpc")
+	if !ok {
+		return "", "", fmt.Errorf("invalid VPC ID format in ARN: %s", resourceARN)
+	}
+	return vpcID, parsed.Region, nil
 }
@@ -0,0 +1,48 @@
+package inspector
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockVPCClient serves a single VPC, recording the calls made
+type mockVPCClient struct {
+	calls *probeCalls
+}
+
+func (m *mockVPCClient) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	if err := m.calls.call("ec2:DescribeVpcs"); err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeVpcsOutput{Vpcs: []types.Vpc{{
+		VpcId: aws.String("vpc-0123456789abcdef0"),
+		Tags:  []types.Tag{{Key: aws.String("Name"), Value: aws.String("main")}},
+	}}}, nil
+}
+
+func TestVPCInspectorProbe(t *testing.T) {
+	t.Parallel()
+
+	inspector := &VPCInspector{
+		Regions: []string{"us-east-1"},
+		Logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	for _, denied := range []string{"", "ec2:DescribeVpcs"} {
+		calls := &probeCalls{denied: denied}
+		clientFor := func(region string) (VPCAPI, error) {
+			return &mockVPCClient{calls: calls}, nil
+		}
+
+		err := inspector.probe(context.Background(), "us-east-1", clientFor)
+		assertProbeError(t, err, denied)
+		assert.Equal(t, []string{"ec2:DescribeVpcs"}, calls.made)
+	}
+}
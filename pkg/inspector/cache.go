@@ -0,0 +1,208 @@
+package inspector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/util"
+)
+
+const (
+	// DefaultScanCacheTTL is how long cached inspection results are reused by default
+	DefaultScanCacheTTL = 30 * time.Minute
+
+	// scanCacheVersion identifies the layout of cache entries, entries of other versions are ignored
+	scanCacheVersion = 1
+
+	// scanCacheExt is the extension of cache entry files
+	scanCacheExt = ".json"
+)
+
+// ScanCacheKey identifies the cached inspection of a resource type
+type ScanCacheKey struct {
+	// AccountID is the AWS account the resources belong to
+	AccountID string
+
+	// ResourceType is the inspected service (e.g. s3, ec2)
+	ResourceType string
+
+	// Regions is the set of regions the inspection covered, in any order
+	Regions []string
+
+	// BucketRegions is the set of regions the S3 buckets were restricted to, empty when every
+	// bucket was inspected
+	BucketRegions []string
+}
+
+// scanCacheEntry is the on-disk representation of a cached inspection
+type scanCacheEntry struct {
+	Version       int            `json:"version"`
+	AccountID     string         `json:"account_id"`
+	ResourceType  string         `json:"resource_type"`
+	Regions       []string       `json:"regions"`
+	BucketRegions []string       `json:"bucket_regions,omitempty"`
+	CachedAt      time.Time      `json:"cached_at"`
+	Result        *InspectResult `json:"result"`
+}
+
+// ScanCache persists inspection results on disk so runs within the TTL validate cached
+// resource metadata instead of calling AWS again.
+//
+// Entries are stored as <dir>/<account-id>/<resource-type>.json. An entry is only reused
+// when it was written for the same account and the same set of regions; any other entry
+// for the key is considered stale and removed on lookup.
+type ScanCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewScanCache creates a cache rooted at dir, which is created on first write. A leading
+// "~" in dir is expanded to the home directory of the current user.
+func NewScanCache(dir string, ttl time.Duration) (*ScanCache, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("scan cache directory cannot be empty")
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("scan cache TTL must be positive, got %s", ttl)
+	}
+
+	expanded, err := util.ExpandHome(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScanCache{dir: expanded, ttl: ttl, now: time.Now}, nil
+}
+
+// DefaultScanCacheDir returns the default cache location, ~/.aws-taggy/cache
+func DefaultScanCacheDir() (string, error) {
+	return util.ExpandHome(filepath.Join("~", ".aws-taggy", "cache"))
+}
+
+// Dir returns the directory holding the cache entries
+func (c *ScanCache) Dir() string {
+	return c.dir
+}
+
+// Load returns the cached result for key. It reports false when there is no entry, the
+// entry expired, or it was written for another account, region set or bucket region set.
+func (c *ScanCache) Load(key ScanCacheKey) (*InspectResult, bool) {
+	path := c.entryPath(key)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry scanCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Result == nil {
+		_ = os.Remove(path)
+		return nil, false
+	}
+
+	stale := entry.Version != scanCacheVersion ||
+		entry.AccountID != key.AccountID ||
+		entry.ResourceType != key.ResourceType ||
+		!slices.Equal(entry.Regions, sortedRegions(key.Regions)) ||
+		!slices.Equal(entry.BucketRegions, sortedRegions(key.BucketRegions))
+	if stale {
+		_ = os.Remove(path)
+		return nil, false
+	}
+
+	if c.now().Sub(entry.CachedAt) > c.ttl {
+		return nil, false
+	}
+
+	return entry.Result, true
+}
+
+// Store writes the result of an inspection under key, replacing any previous entry
+func (c *ScanCache) Store(key ScanCacheKey, result *InspectResult) error {
+	if result == nil {
+		return fmt.Errorf("cannot cache an empty inspection result for %s", key.ResourceType)
+	}
+
+	data, err := json.Marshal(scanCacheEntry{
+		Version:       scanCacheVersion,
+		AccountID:     key.AccountID,
+		ResourceType:  key.ResourceType,
+		Regions:       sortedRegions(key.Regions),
+		BucketRegions: sortedRegions(key.BucketRegions),
+		CachedAt:      c.now(),
+		Result:        result,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode cached result for %s: %w", key.ResourceType, err)
+	}
+
+	path := c.entryPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create scan cache directory: %w", err)
+	}
+
+	// Write to a temporary file first so a concurrent run never reads a partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write scan cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write scan cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write scan cache entry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write scan cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// Clear removes every cache entry and returns how many were removed. Only files laid out
+// by the cache are deleted, so pointing the cache at a shared directory is harmless.
+func (c *ScanCache) Clear() (int, error) {
+	entries, err := filepath.Glob(filepath.Join(c.dir, "*", "*"+scanCacheExt))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list scan cache entries: %w", err)
+	}
+
+	removed := 0
+	for _, path := range entries {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, fmt.Errorf("failed to remove scan cache entry %s: %w", path, err)
+		}
+		removed++
+
+		// Drop the account directory once it holds no more entries
+		_ = os.Remove(filepath.Dir(path))
+	}
+
+	return removed, nil
+}
+
+// entryPath returns the file holding the entry of key
+func (c *ScanCache) entryPath(key ScanCacheKey) string {
+	account := key.AccountID
+	if account == "" {
+		account = "default"
+	}
+	return filepath.Join(c.dir, filepath.Base(account), filepath.Base(key.ResourceType)+scanCacheExt)
+}
+
+// sortedRegions returns a sorted copy of regions, so region sets compare regardless of order
+func sortedRegions(regions []string) []string {
+	sorted := slices.Clone(regions)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
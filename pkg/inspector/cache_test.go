@@ -0,0 +1,209 @@
+package inspector
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestScanCache(t *testing.T, ttl time.Duration) (*ScanCache, *time.Time) {
+	t.Helper()
+
+	cache, err := NewScanCache(t.TempDir(), ttl)
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	return cache, &now
+}
+
+func cachedResult(ids ...string) *InspectResult {
+	result := &InspectResult{TotalResources: len(ids)}
+	for _, id := range ids {
+		result.Resources = append(result.Resources, ResourceMetadata{
+			ID:   id,
+			Type: "s3",
+			Tags: map[string]string{"Owner": "platform"},
+		})
+	}
+	return result
+}
+
+func TestNewScanCache(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewScanCache("", time.Minute)
+	assert.Error(t, err)
+
+	_, err = NewScanCache(t.TempDir(), 0)
+	assert.Error(t, err)
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	cache, err := NewScanCache("~/.aws-taggy/cache", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".aws-taggy", "cache"), cache.Dir())
+}
+
+func TestScanCacheLoad(t *testing.T) {
+	t.Parallel()
+
+	key := ScanCacheKey{AccountID: "111111111111", ResourceType: "s3", Regions: []string{"us-east-1", "eu-west-1"}}
+
+	testCases := []struct {
+		name    string
+		lookup  ScanCacheKey
+		elapsed time.Duration
+		hit     bool
+	}{
+		{name: "Same key within TTL", lookup: key, elapsed: 10 * time.Minute, hit: true},
+		{
+			name:   "Regions in another order",
+			lookup: ScanCacheKey{AccountID: key.AccountID, ResourceType: "s3", Regions: []string{"eu-west-1", "us-east-1"}},
+			hit:    true,
+		},
+		{name: "Expired entry", lookup: key, elapsed: 31 * time.Minute},
+		{
+			name:   "Different region set",
+			lookup: ScanCacheKey{AccountID: key.AccountID, ResourceType: "s3", Regions: []string{"us-east-1"}},
+		},
+		{
+			name:   "Different account",
+			lookup: ScanCacheKey{AccountID: "222222222222", ResourceType: "s3", Regions: key.Regions},
+		},
+		{
+			name:   "Different resource type",
+			lookup: ScanCacheKey{AccountID: key.AccountID, ResourceType: "ec2", Regions: key.Regions},
+		},
+		{
+			name:   "Buckets restricted to some regions",
+			lookup: ScanCacheKey{AccountID: key.AccountID, ResourceType: "s3", Regions: key.Regions, BucketRegions: []string{"eu-west-1"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cache, now := newTestScanCache(t, 30*time.Minute)
+			require.NoError(t, cache.Store(key, cachedResult("bucket-a", "bucket-b")))
+
+			*now = now.Add(tc.elapsed)
+			result, ok := cache.Load(tc.lookup)
+
+			assert.Equal(t, tc.hit, ok)
+			if tc.hit {
+				require.NotNil(t, result)
+				assert.Equal(t, 2, result.TotalResources)
+				assert.Equal(t, "bucket-a", result.Resources[0].ID)
+				assert.Equal(t, "platform", result.Resources[0].Tags["Owner"])
+			}
+		})
+	}
+}
+
+func TestScanCacheInvalidatesChangedRegionSet(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := newTestScanCache(t, time.Hour)
+	key := ScanCacheKey{AccountID: "111111111111", ResourceType: "s3", Regions: []string{"us-east-1"}}
+	require.NoError(t, cache.Store(key, cachedResult("bucket-a")))
+
+	_, ok := cache.Load(ScanCacheKey{AccountID: key.AccountID, ResourceType: "s3", Regions: []string{"us-east-1", "eu-west-1"}})
+	assert.False(t, ok)
+
+	// The stale entry is dropped, so the original region set no longer hits either
+	_, ok = cache.Load(key)
+	assert.False(t, ok)
+}
+
+func TestScanCacheClear(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := newTestScanCache(t, time.Hour)
+	regions := []string{"us-east-1"}
+	require.NoError(t, cache.Store(ScanCacheKey{AccountID: "111111111111", ResourceType: "s3", Regions: regions}, cachedResult("a")))
+	require.NoError(t, cache.Store(ScanCacheKey{AccountID: "111111111111", ResourceType: "sqs", Regions: regions}, cachedResult("b")))
+	require.NoError(t, cache.Store(ScanCacheKey{AccountID: "222222222222", ResourceType: "s3", Regions: regions}, cachedResult("c")))
+
+	// Files not written by the cache are left alone
+	unrelated := filepath.Join(cache.Dir(), "notes.txt")
+	require.NoError(t, os.WriteFile(unrelated, []byte("keep"), 0o600))
+
+	removed, err := cache.Clear()
+	require.NoError(t, err)
+	assert.Equal(t, 3, removed)
+	assert.FileExists(t, unrelated)
+
+	_, ok := cache.Load(ScanCacheKey{AccountID: "111111111111", ResourceType: "s3", Regions: regions})
+	assert.False(t, ok)
+}
+
+// countingInspector returns a fixed result and counts how often it is called
+type countingInspector struct {
+	calls  int
+	result func() *InspectResult
+}
+
+func (i *countingInspector) Inspect(ctx context.Context, config configuration.TaggyScanConfig) (*InspectResult, error) {
+	i.calls++
+	return i.result(), nil
+}
+
+func (i *countingInspector) Fetch(ctx context.Context, arn string, config configuration.TaggyScanConfig) (*ResourceMetadata, error) {
+	return nil, nil
+}
+
+func TestInspectorManagerReusesCachedResults(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := newTestScanCache(t, time.Hour)
+	filter, err := NewExclusionFilter(nil)
+	require.NoError(t, err)
+
+	fake := &countingInspector{result: func() *InspectResult { return cachedResult("bucket-a", "bucket-b") }}
+	account := "111111111111"
+
+	newManager := func() *InspectorManager {
+		return &InspectorManager{
+			inspectors:   map[string]inspectorTarget{"s3": {resourceType: "s3", inspector: fake}},
+			exclusions:   map[string]*ExclusionFilter{"s3": filter},
+			rateLimiters: map[string]RateLimiter{},
+			config: configuration.TaggyScanConfig{
+				AWS: configuration.AWSConfig{Regions: configuration.RegionsConfig{Mode: "specific", List: []string{"us-east-1"}}},
+			},
+			results: map[string]*InspectResult{},
+			logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+			cache:   cache,
+			callerAccountID: func(ctx context.Context, regions []string) (string, error) {
+				return account, nil
+			},
+		}
+	}
+
+	first := newManager()
+	require.NoError(t, first.Inspect(context.Background()))
+	assert.False(t, first.Results().ByService["s3"].Metadata.Cached)
+
+	second := newManager()
+	require.NoError(t, second.Inspect(context.Background()))
+	assert.Equal(t, 1, fake.calls, "the second run must be served from the cache")
+	assert.True(t, second.Results().ByService["s3"].Metadata.Cached)
+	assert.Len(t, second.Results().ByService["s3"].Resources, 2)
+
+	// Credentials of another account must not reuse the cached results
+	account = "222222222222"
+	third := newManager()
+	require.NoError(t, third.Inspect(context.Background()))
+	assert.Equal(t, 2, fake.calls)
+	assert.False(t, third.Results().ByService["s3"].Metadata.Cached)
+}
@@ -0,0 +1,549 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// DefaultMaxConcurrency is the number of discovery and processing operations allowed to run
+// at the same time across all inspectors when the configuration does not set max_concurrency
+const DefaultMaxConcurrency = 20
+
+const (
+	// DefaultThrottleThreshold is the number of throttled attempts within
+	// DefaultThrottleWindow that halves the concurrency of an adaptive limiter
+	DefaultThrottleThreshold = 5
+
+	// DefaultThrottleWindow is the period over which throttled attempts are counted
+	DefaultThrottleWindow = 10 * time.Second
+
+	// DefaultThrottleCooldown is the period without throttling after which an adaptive
+	// limiter doubles its reduced concurrency, up to its capacity
+	DefaultThrottleCooldown = 30 * time.Second
+)
+
+// AdaptiveThrottling describes how a ConcurrencyLimiter adapts its concurrency to the
+// throttling of AWS API calls: it halves its limit when Threshold attempts are throttled
+// within Window, and doubles it back once no attempt was throttled for Cooldown.
+type AdaptiveThrottling struct {
+	Threshold int
+	Window    time.Duration
+	Cooldown  time.Duration
+}
+
+// DefaultAdaptiveThrottling returns the adaptive throttling applied by the InspectorManager
+func DefaultAdaptiveThrottling() AdaptiveThrottling {
+	return AdaptiveThrottling{
+		Threshold: DefaultThrottleThreshold,
+		Window:    DefaultThrottleWindow,
+		Cooldown:  DefaultThrottleCooldown,
+	}
+}
+
+// ConcurrencyLimiter bounds the number of operations running at the same time.
+// A single limiter is shared by every inspector started by the InspectorManager,
+// so the total concurrency of a run does not grow with the number of services.
+//
+// An adaptive limiter lowers its limit below its capacity while AWS throttles the
+// API calls of the scan, see EnableAdaptiveThrottling.
+type ConcurrencyLimiter struct {
+	mu       sync.Mutex
+	capacity int
+	limit    int
+	active   int
+
+	// released is closed and replaced whenever a slot frees or the limit grows, waking the
+	// operations waiting for a slot
+	released chan struct{}
+
+	// throttles counts the throttled attempts reported with Throttled, recent holds the
+	// times of the ones within the window of the adaptive throttling and adjustedAt the
+	// time of the last change of the limit
+	throttles  int64
+	adaptive   *AdaptiveThrottling
+	recent     []time.Time
+	adjustedAt time.Time
+	logger     *o11y.Logger
+	now        func() time.Time
+}
+
+// NewConcurrencyLimiter creates a limiter allowing at most maxConcurrency concurrent operations.
+// A non-positive value falls back to DefaultMaxConcurrency.
+func NewConcurrencyLimiter(maxConcurrency int) *ConcurrencyLimiter {
+	if maxConcurrency <= 0 {
+		maxConcurrency = DefaultMaxConcurrency
+	}
+
+	return &ConcurrencyLimiter{
+		capacity: maxConcurrency,
+		limit:    maxConcurrency,
+		released: make(chan struct{}),
+		now:      time.Now,
+	}
+}
+
+// EnableAdaptiveThrottling makes the limiter adapt its limit to the throttled attempts
+// reported with Throttled, logging every change with the logger
+func (l *ConcurrencyLimiter) EnableAdaptiveThrottling(throttling AdaptiveThrottling, logger *o11y.Logger) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.adaptive = &throttling
+	l.logger = logger
+}
+
+// Acquire blocks until a slot is available or the context is cancelled.
+// A nil limiter never blocks.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	for {
+		l.mu.Lock()
+		l.regrow()
+		if l.active < l.limit {
+			l.active++
+			l.mu.Unlock()
+			return nil
+		}
+		released := l.released
+		l.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Release frees a slot previously obtained with Acquire
+func (l *ConcurrencyLimiter) Release() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active--
+	l.regrow()
+	l.wake()
+}
+
+// Capacity returns the maximum number of concurrent operations
+func (l *ConcurrencyLimiter) Capacity() int {
+	if l == nil {
+		return 0
+	}
+	return l.capacity
+}
+
+// Limit returns the number of concurrent operations currently allowed, lower than the
+// capacity while an adaptive limiter backs off from throttling
+func (l *ConcurrencyLimiter) Limit() int {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// Throttles returns the number of throttled attempts reported to the limiter
+func (l *ConcurrencyLimiter) Throttles() int64 {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.throttles
+}
+
+// Throttled reports attempts of AWS API calls that were throttled. An adaptive limiter
+// halves its limit, down to a single operation, once the throttled attempts within its
+// window reach its threshold.
+func (l *ConcurrencyLimiter) Throttled(attempts int) {
+	if l == nil || attempts <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.throttles += int64(attempts)
+	if l.adaptive == nil {
+		return
+	}
+
+	now := l.now()
+	for range attempts {
+		l.recent = append(l.recent, now)
+	}
+	for len(l.recent) > 0 && now.Sub(l.recent[0]) > l.adaptive.Window {
+		l.recent = l.recent[1:]
+	}
+
+	if len(l.recent) < l.adaptive.Threshold || l.limit == 1 {
+		return
+	}
+
+	reduced := max(1, l.limit/2)
+	if l.logger != nil {
+		l.logger.Warn(fmt.Sprintf("⏳ AWS throttled %d API calls within %s, reducing the scan concurrency from %d to %d",
+			len(l.recent), l.adaptive.Window, l.limit, reduced))
+	}
+	l.limit = reduced
+	l.adjustedAt = now
+	l.recent = nil
+}
+
+// regrow doubles the reduced limit of an adaptive limiter, up to its capacity, once no
+// attempt was throttled for its cooldown. The caller holds the lock.
+func (l *ConcurrencyLimiter) regrow() {
+	if l.adaptive == nil || l.limit == l.capacity {
+		return
+	}
+
+	now := l.now()
+	calmSince := l.adjustedAt
+	if len(l.recent) > 0 && l.recent[len(l.recent)-1].After(calmSince) {
+		calmSince = l.recent[len(l.recent)-1]
+	}
+	if now.Sub(calmSince) < l.adaptive.Cooldown {
+		return
+	}
+
+	raised := min(l.capacity, l.limit*2)
+	if l.logger != nil {
+		l.logger.Info(fmt.Sprintf("AWS stopped throttling for %s, raising the scan concurrency from %d to %d",
+			l.adaptive.Cooldown, l.limit, raised))
+	}
+	l.limit = raised
+	l.adjustedAt = now
+	l.wake()
+}
+
+// wake wakes the operations waiting for a slot. The caller holds the lock.
+func (l *ConcurrencyLimiter) wake() {
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+// RateLimiter is a hook invoked before every AWS API call made while scanning a service.
+// Implementations block until the call is allowed, or return an error to abort it.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// IntervalRateLimiter is a RateLimiter spacing API calls evenly to a fixed number of
+// requests per second.
+type IntervalRateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+	rate     float64
+}
+
+// NewIntervalRateLimiter creates a rate limiter allowing requestsPerSecond API calls per second
+func NewIntervalRateLimiter(requestsPerSecond float64) *IntervalRateLimiter {
+	return &IntervalRateLimiter{
+		interval: time.Duration(float64(time.Second) / requestsPerSecond),
+		rate:     requestsPerSecond,
+	}
+}
+
+// Rate returns the configured number of requests per second
+func (r *IntervalRateLimiter) Rate() float64 {
+	return r.rate
+}
+
+// Wait blocks until the next API call slot, or until the context is cancelled
+func (r *IntervalRateLimiter) Wait(ctx context.Context) error {
+	r.mu.Lock()
+	now := time.Now()
+	if r.next.Before(now) {
+		r.next = now
+	}
+	wait := r.next.Sub(now)
+	r.next = r.next.Add(r.interval)
+	r.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// APICallBudget caps the AWS API calls of a run. A single budget is shared by every
+// inspector started by the InspectorManager, like the ConcurrencyLimiter.
+type APICallBudget struct {
+	max  int64
+	used atomic.Int64
+}
+
+// ErrAPICallBudgetExhausted is the error of the AWS API calls refused once every call of the
+// APICallBudget of the run is spent
+var ErrAPICallBudgetExhausted = errors.New("max_api_calls budget exhausted")
+
+// NewAPICallBudget creates a budget allowing maxCalls AWS API calls
+func NewAPICallBudget(maxCalls int64) *APICallBudget {
+	return &APICallBudget{max: maxCalls}
+}
+
+// Exhausted reports whether every call of the budget is spent. A nil budget is never
+// exhausted.
+func (b *APICallBudget) Exhausted() bool {
+	if b == nil {
+		return false
+	}
+	return b.used.Load() >= b.max
+}
+
+// take spends a call of the budget, reporting false when every call is already spent. A nil
+// budget always has calls left.
+func (b *APICallBudget) take() bool {
+	if b == nil {
+		return true
+	}
+	for {
+		used := b.used.Load()
+		if used >= b.max {
+			return false
+		}
+		if b.used.CompareAndSwap(used, used+1) {
+			return true
+		}
+	}
+}
+
+// Used returns the number of API calls made against the budget so far
+func (b *APICallBudget) Used() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.used.Load()
+}
+
+// ScanStats accumulates counters about the AWS API usage of a scan
+type ScanStats struct {
+	apiCalls  atomic.Int64
+	retries   atomic.Int64
+	throttles atomic.Int64
+	truncated atomic.Bool
+
+	// discovered counts the resources discovered per region by a discovery-only scan, and
+	// sampling the resources discovered and sampled by a sampled scan
+	mu         sync.Mutex
+	discovered map[string]int
+	sampling   *SampleInfo
+}
+
+// APICalls returns the number of AWS API calls made so far
+func (s *ScanStats) APICalls() int64 {
+	return s.apiCalls.Load()
+}
+
+// Retries returns the number of retried attempts of AWS API calls made so far
+func (s *ScanStats) Retries() int64 {
+	return s.retries.Load()
+}
+
+// Throttles returns the number of attempts of AWS API calls throttled so far
+func (s *ScanStats) Throttles() int64 {
+	return s.throttles.Load()
+}
+
+// Truncated reports whether the scan stopped early because a scan limit was hit
+func (s *ScanStats) Truncated() bool {
+	return s.truncated.Load()
+}
+
+// Discovered returns the number of resources discovered per region by a discovery-only scan
+func (s *ScanStats) Discovered() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	discovered := make(map[string]int, len(s.discovered))
+	for region, count := range s.discovered {
+		discovered[region] = count
+	}
+	return discovered
+}
+
+// Sampling returns the resources discovered and sampled by a sampled scan, nil when the
+// scan is not sampled
+func (s *ScanStats) Sampling() *SampleInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sampling == nil {
+		return nil
+	}
+	sampling := *s.sampling
+	return &sampling
+}
+
+// addSampled records the resources discovered and sampled by a sampled scan
+func (s *ScanStats) addSampled(discovered, sampled int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sampling == nil {
+		s.sampling = &SampleInfo{}
+	}
+	s.sampling.Discovered += discovered
+	s.sampling.Sampled += sampled
+}
+
+// addDiscovered records the resources discovered in a region
+func (s *ScanStats) addDiscovered(region string, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.discovered == nil {
+		s.discovered = make(map[string]int)
+	}
+	s.discovered[region] += count
+}
+
+// ScanControls carries the concurrency limiter, rate limiter and statistics applied to
+// a scan. They travel through the context so every inspector and AWS client honors them
+// without having to be wired individually.
+type ScanControls struct {
+	Limiter     *ConcurrencyLimiter
+	RateLimiter RateLimiter
+	Stats       *ScanStats
+
+	// BatchSize and NumWorkers configure the asynchronous inspection of the scanned resource
+	// type, zero keeping the defaults of DefaultInspectorConfig
+	BatchSize  int
+	NumWorkers int
+
+	// Previous holds the resources recorded by the previous run of an incremental scan, nil
+	// for a full scan
+	Previous *PreviousScan
+
+	// MaxResources caps the resources discovered by the scan, unlimited when zero, and
+	// Budget caps the API calls of the run, unlimited when nil. Hitting either marks the
+	// statistics of the scan as truncated.
+	MaxResources int
+	Budget       *APICallBudget
+
+	// DiscoveryOnly counts the discovered resources in the statistics of the scan instead
+	// of processing them, to estimate the size of a scan cheaply
+	DiscoveryOnly bool
+
+	// Sample restricts the processing to a sample of the discovered resources, every
+	// discovered resource being processed when zero
+	Sample Sample
+}
+
+type scanControlsKey struct{}
+
+// WithScanControls returns a context carrying the given scan controls
+func WithScanControls(ctx context.Context, controls ScanControls) context.Context {
+	return context.WithValue(ctx, scanControlsKey{}, controls)
+}
+
+// scanControlsFromContext returns the scan controls carried by the context, if any
+func scanControlsFromContext(ctx context.Context) ScanControls {
+	controls, _ := ctx.Value(scanControlsKey{}).(ScanControls)
+	return controls
+}
+
+// beforeAPICall applies the rate limiter and counts the call against the statistics and the
+// API call budget. Once the budget is spent the call is refused with
+// ErrAPICallBudgetExhausted, and the scan marked as truncated.
+func (c ScanControls) beforeAPICall(ctx context.Context) error {
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	if !c.Budget.take() {
+		c.markTruncated()
+		return ErrAPICallBudgetExhausted
+	}
+	if c.Stats != nil {
+		c.Stats.apiCalls.Add(1)
+	}
+
+	return nil
+}
+
+// markTruncated records in the statistics of the scan that it stopped early
+func (c ScanControls) markTruncated() {
+	if c.Stats != nil {
+		c.Stats.truncated.Store(true)
+	}
+}
+
+// throttleErrors classifies the errors of the attempts of AWS API calls like the SDK retryer
+var throttleErrors = retry.IsErrorThrottles(retry.DefaultThrottles)
+
+// afterAPICall records the retries the AWS SDK performed for a completed call, and reports
+// its throttled attempts to the concurrency limiter
+func (c ScanControls) afterAPICall(metadata middleware.Metadata) {
+	attempts, ok := retry.GetAttemptResults(metadata)
+	if !ok {
+		return
+	}
+
+	throttled := 0
+	for _, attempt := range attempts.Results {
+		if attempt.Err != nil && throttleErrors.IsErrorThrottle(attempt.Err) == aws.TrueTernary {
+			throttled++
+		}
+	}
+	c.Limiter.Throttled(throttled)
+
+	if c.Stats == nil {
+		return
+	}
+	if len(attempts.Results) > 1 {
+		c.Stats.retries.Add(int64(len(attempts.Results) - 1))
+	}
+	c.Stats.throttles.Add(int64(throttled))
+}
+
+// addScanControlsMiddleware registers a middleware on the AWS SDK stack that applies the
+// scan controls carried by the request context to every API call. It sits before the SDK
+// retry loop, so a call is rate limited and counted once however many attempts it takes.
+func addScanControlsMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("AWSTaggyScanControls",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (
+			middleware.InitializeOutput, middleware.Metadata, error,
+		) {
+			controls := scanControlsFromContext(ctx)
+			if err := controls.beforeAPICall(ctx); err != nil {
+				return middleware.InitializeOutput{}, middleware.Metadata{}, err
+			}
+
+			out, metadata, err := next.HandleInitialize(ctx, in)
+			controls.afterAPICall(metadata)
+			return out, metadata, err
+		}), middleware.Before)
+}
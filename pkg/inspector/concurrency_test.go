@@ -0,0 +1,370 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrencyProbe records the highest number of operations observed running at once
+type concurrencyProbe struct {
+	active int64
+	peak   int64
+}
+
+func (p *concurrencyProbe) enter() {
+	current := atomic.AddInt64(&p.active, 1)
+	for {
+		peak := atomic.LoadInt64(&p.peak)
+		if current <= peak || atomic.CompareAndSwapInt64(&p.peak, peak, current) {
+			return
+		}
+	}
+}
+
+func (p *concurrencyProbe) leave() {
+	atomic.AddInt64(&p.active, -1)
+}
+
+// newMockedScan returns a discoverer yielding count resources per region and a processor
+// simulating an API call of the given latency
+func newMockedScan(count int, latency time.Duration, probe *concurrencyProbe) (ResourceDiscoverer, ResourceProcessor) {
+	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
+		resources := make([]interface{}, count)
+		for i := range resources {
+			resources[i] = fmt.Sprintf("%s-resource-%d", region, i)
+		}
+		return resources, nil
+	}
+
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		if probe != nil {
+			probe.enter()
+			defer probe.leave()
+		}
+		time.Sleep(latency)
+		return ResourceMetadata{ID: resource.(string), Type: "mock"}, nil
+	}
+
+	return discoverer, processor
+}
+
+func quietInspectorConfig() InspectorConfig {
+	config := DefaultInspectorConfig()
+	config.Logger = o11y.NewLogger(io.Discard, o11y.LogLevelError)
+	return config
+}
+
+func TestConcurrencyLimiterBoundsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewConcurrencyLimiter(3)
+	probe := &concurrencyProbe{}
+	var wg sync.WaitGroup
+
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, limiter.Acquire(context.Background()))
+			probe.enter()
+			time.Sleep(time.Millisecond)
+			probe.leave()
+			limiter.Release()
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, probe.peak, int64(3))
+	assert.Equal(t, 3, limiter.Capacity())
+}
+
+func TestConcurrencyLimiterCancelledContext(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewConcurrencyLimiter(1)
+	require.NoError(t, limiter.Acquire(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, limiter.Acquire(ctx), context.Canceled)
+}
+
+func TestConcurrencyLimiterAdaptiveThrottling(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewConcurrencyLimiter(8)
+	limiter.now = func() time.Time { return now }
+	limiter.EnableAdaptiveThrottling(AdaptiveThrottling{Threshold: 3, Window: 10 * time.Second, Cooldown: 30 * time.Second},
+		o11y.NewLogger(io.Discard, o11y.LogLevelError))
+
+	acquireRelease := func() {
+		require.NoError(t, limiter.Acquire(context.Background()))
+		limiter.Release()
+	}
+
+	// Throttles spread beyond the window stay below the threshold
+	limiter.Throttled(2)
+	now = now.Add(11 * time.Second)
+	limiter.Throttled(1)
+	assert.Equal(t, 8, limiter.Limit())
+
+	limiter.Throttled(2)
+	assert.Equal(t, 4, limiter.Limit(), "the limit halves once the threshold is reached within the window")
+	limiter.Throttled(3)
+	assert.Equal(t, 2, limiter.Limit())
+	limiter.Throttled(3)
+	limiter.Throttled(3)
+	assert.Equal(t, 1, limiter.Limit(), "the limit never drops below a single operation")
+
+	now = now.Add(29 * time.Second)
+	acquireRelease()
+	assert.Equal(t, 1, limiter.Limit(), "the limit stays reduced during the cooldown")
+
+	now = now.Add(time.Second)
+	acquireRelease()
+	assert.Equal(t, 2, limiter.Limit(), "the limit doubles after the cooldown")
+
+	now = now.Add(30 * time.Second)
+	acquireRelease()
+	now = now.Add(30 * time.Second)
+	acquireRelease()
+	now = now.Add(30 * time.Second)
+	acquireRelease()
+	assert.Equal(t, 8, limiter.Limit(), "the limit grows back up to the capacity")
+	assert.Equal(t, 8, limiter.Capacity())
+	assert.Equal(t, int64(14), limiter.Throttles())
+}
+
+func TestConcurrencyLimiterWithoutAdaptiveThrottling(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewConcurrencyLimiter(4)
+	limiter.Throttled(100)
+
+	assert.Equal(t, 4, limiter.Limit(), "a limiter without adaptive throttling only counts throttles")
+	assert.Equal(t, int64(100), limiter.Throttles())
+}
+
+func TestConcurrencyLimiterReducedLimitBoundsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewConcurrencyLimiter(6)
+	limiter.EnableAdaptiveThrottling(AdaptiveThrottling{Threshold: 1, Window: time.Minute, Cooldown: time.Hour}, nil)
+	limiter.Throttled(1)
+	limiter.Throttled(1)
+	require.Equal(t, 1, limiter.Limit())
+
+	probe := &concurrencyProbe{}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, limiter.Acquire(context.Background()))
+			probe.enter()
+			time.Sleep(100 * time.Microsecond)
+			probe.leave()
+			limiter.Release()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(1), probe.peak)
+}
+
+// throttlingClient is a fake AWS client whose calls go through the scan controls and the
+// retry middlewares of the AWS SDK. The first attempt of each of its first throttledCalls
+// calls is throttled, every other attempt succeeds.
+type throttlingClient struct {
+	throttledCalls int64
+	calls          atomic.Int64
+}
+
+func (c *throttlingClient) call(ctx context.Context) error {
+	throttle := c.calls.Add(1) <= c.throttledCalls
+
+	stack := middleware.NewStack("FakeThrottlingClient", func() interface{} { return struct{}{} })
+	if err := addScanControlsMiddleware(stack); err != nil {
+		return err
+	}
+	retryer := retry.NewStandard(func(o *retry.StandardOptions) {
+		o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return 0, nil })
+		o.RateLimiter = ratelimit.None
+	})
+	attempt := retry.NewAttemptMiddleware(retryer, func(request interface{}) interface{} { return request })
+	if err := stack.Finalize.Add(attempt, middleware.After); err != nil {
+		return err
+	}
+
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(
+		func(ctx context.Context, input interface{}) (interface{}, middleware.Metadata, error) {
+			if throttle {
+				throttle = false
+				return nil, middleware.Metadata{}, &smithy.GenericAPIError{Code: "Throttling"}
+			}
+			return struct{}{}, middleware.Metadata{}, nil
+		}), stack)
+
+	_, _, err := handler.Handle(ctx, struct{}{})
+	return err
+}
+
+func TestInspectResourcesAsyncBacksOffFromThrottling(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewConcurrencyLimiter(8)
+	limiter.EnableAdaptiveThrottling(AdaptiveThrottling{Threshold: 5, Window: time.Minute, Cooldown: time.Hour},
+		o11y.NewLogger(io.Discard, o11y.LogLevelError))
+	stats := &ScanStats{}
+	ctx := WithScanControls(context.Background(), ScanControls{Limiter: limiter, Stats: stats})
+
+	client := &throttlingClient{throttledCalls: 12}
+	discoverer, _ := newMockedScan(40, 0, nil)
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		if err := client.call(ctx); err != nil {
+			return ResourceMetadata{}, err
+		}
+		return ResourceMetadata{ID: resource.(string), Type: "mock"}, nil
+	}
+
+	resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(ctx, []string{"us-east-1"}, discoverer, processor)
+	require.NoError(t, err)
+	assert.Len(t, resources, 40)
+
+	// Every fifth throttled call halves the limit: 8, then 4 and 2
+	assert.Equal(t, int64(12), stats.Throttles())
+	assert.Equal(t, int64(12), stats.Retries())
+	assert.Equal(t, int64(40), stats.APICalls())
+	assert.Equal(t, int64(12), limiter.Throttles())
+	assert.Equal(t, 2, limiter.Limit())
+	assert.Equal(t, 8, limiter.Capacity())
+}
+
+func TestIntervalRateLimiterSpacesCalls(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewIntervalRateLimiter(100)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	// The first call is immediate, the following four are spaced by 10ms each
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+	assert.Equal(t, float64(100), limiter.Rate())
+}
+
+func TestScanControlsCountAPICalls(t *testing.T) {
+	t.Parallel()
+
+	stats := &ScanStats{}
+	ctx := WithScanControls(context.Background(), ScanControls{Stats: stats})
+
+	controls := scanControlsFromContext(ctx)
+	for i := 0; i < 4; i++ {
+		require.NoError(t, controls.beforeAPICall(ctx))
+	}
+
+	assert.Equal(t, int64(4), stats.APICalls())
+}
+
+func TestScanControlsRefuseCallsBeyondBudget(t *testing.T) {
+	t.Parallel()
+
+	stats := &ScanStats{}
+	budget := NewAPICallBudget(3)
+	ctx := WithScanControls(context.Background(), ScanControls{Stats: stats, Budget: budget})
+	client := &throttlingClient{}
+
+	// The calls within the budget reach AWS, the one crossing it fails in the middleware
+	for i := 0; i < 3; i++ {
+		require.NoError(t, client.call(ctx), "call %d", i+1)
+	}
+	assert.False(t, stats.Truncated())
+
+	err := client.call(ctx)
+	require.ErrorIs(t, err, ErrAPICallBudgetExhausted)
+	assert.Equal(t, int64(3), budget.Used())
+	assert.Equal(t, int64(3), stats.APICalls())
+	assert.True(t, stats.Truncated())
+}
+
+func TestInspectResourcesAsyncSharedLimiter(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewConcurrencyLimiter(2)
+	probe := &concurrencyProbe{}
+	ctx := WithScanControls(context.Background(), ScanControls{Limiter: limiter})
+
+	var wg sync.WaitGroup
+	counts := make([]int, 3)
+	for i := range counts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			discoverer, processor := newMockedScan(50, 100*time.Microsecond, probe)
+			resources, err := NewAsyncResourceInspector(quietInspectorConfig()).
+				InspectResourcesAsync(ctx, []string{"us-east-1"}, discoverer, processor)
+			assert.NoError(t, err)
+			counts[i] = len(resources)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, []int{50, 50, 50}, counts)
+	assert.LessOrEqual(t, probe.peak, int64(2), "inspectors sharing a limiter must not exceed its capacity")
+}
+
+// benchmarkServices runs ten concurrent inspectors over a mocked 1000-resource discovery,
+// optionally sharing a global concurrency limiter
+func benchmarkServices(b *testing.B, limiter *ConcurrencyLimiter) {
+	b.Helper()
+
+	const services = 10
+	probe := &concurrencyProbe{}
+	discoverer, processor := newMockedScan(1000, 50*time.Microsecond, probe)
+
+	ctx := context.Background()
+	if limiter != nil {
+		ctx = WithScanControls(ctx, ScanControls{Limiter: limiter})
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var wg sync.WaitGroup
+		for i := 0; i < services; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = NewAsyncResourceInspector(quietInspectorConfig()).
+					InspectResourcesAsync(ctx, []string{"us-east-1"}, discoverer, processor)
+			}()
+		}
+		wg.Wait()
+	}
+	b.ReportMetric(float64(probe.peak), "peak-concurrency")
+}
+
+func BenchmarkInspectResourcesAsyncPerInspectorWorkers(b *testing.B) {
+	benchmarkServices(b, nil)
+}
+
+func BenchmarkInspectResourcesAsyncSharedLimiter(b *testing.B) {
+	benchmarkServices(b, NewConcurrencyLimiter(DefaultMaxConcurrency))
+}
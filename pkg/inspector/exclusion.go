@@ -0,0 +1,86 @@
+package inspector
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+)
+
+// ExcludedResource describes a discovered resource that was skipped because it
+// matched one of the exclusion patterns configured for its resource type.
+type ExcludedResource struct {
+	Resource ResourceMetadata `json:"resource"`
+	Pattern  string           `json:"pattern"`
+	Reason   string           `json:"reason,omitempty"`
+}
+
+// exclusionRule pairs a compiled exclusion pattern with its configuration entry
+type exclusionRule struct {
+	pattern *regexp.Regexp
+	source  configuration.ExcludedResource
+}
+
+// ExclusionFilter drops resources whose ID, name or ARN matches any of the
+// configured exclusion patterns for a resource type.
+type ExclusionFilter struct {
+	rules []exclusionRule
+}
+
+// NewExclusionFilter compiles the exclusion patterns of a resource configuration.
+//
+// Patterns are treated as regular expressions, consistent with how the
+// configuration validator checks them. An empty list yields a filter that
+// keeps every resource.
+func NewExclusionFilter(excluded []configuration.ExcludedResource) (*ExclusionFilter, error) {
+	rules := make([]exclusionRule, 0, len(excluded))
+	for _, entry := range excluded {
+		compiled, err := regexp.Compile(entry.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclusion pattern %q: %w", entry.Pattern, err)
+		}
+		rules = append(rules, exclusionRule{pattern: compiled, source: entry})
+	}
+
+	return &ExclusionFilter{rules: rules}, nil
+}
+
+// Match reports whether the resource matches an exclusion pattern, returning
+// the first matching configuration entry.
+func (f *ExclusionFilter) Match(resource ResourceMetadata) (configuration.ExcludedResource, bool) {
+	if f == nil {
+		return configuration.ExcludedResource{}, false
+	}
+
+	candidates := []string{resource.ID, resource.Details.Name, resource.Details.ARN}
+	for _, rule := range f.rules {
+		for _, candidate := range candidates {
+			if candidate != "" && rule.pattern.MatchString(candidate) {
+				return rule.source, true
+			}
+		}
+	}
+
+	return configuration.ExcludedResource{}, false
+}
+
+// Apply splits resources into the ones that should be evaluated and the ones
+// that were excluded.
+func (f *ExclusionFilter) Apply(resources []ResourceMetadata) ([]ResourceMetadata, []ExcludedResource) {
+	kept := make([]ResourceMetadata, 0, len(resources))
+	var excluded []ExcludedResource
+
+	for _, resource := range resources {
+		if entry, ok := f.Match(resource); ok {
+			excluded = append(excluded, ExcludedResource{
+				Resource: resource,
+				Pattern:  entry.Pattern,
+				Reason:   entry.Reason,
+			})
+			continue
+		}
+		kept = append(kept, resource)
+	}
+
+	return kept, excluded
+}
@@ -0,0 +1,64 @@
+package inspector
+
+import (
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExclusionTestResource(id, name, arn string) ResourceMetadata {
+	resource := ResourceMetadata{ID: id, Type: "s3"}
+	resource.Details.Name = name
+	resource.Details.ARN = arn
+	return resource
+}
+
+func TestExclusionFilterApply(t *testing.T) {
+	t.Parallel()
+
+	filter, err := NewExclusionFilter([]configuration.ExcludedResource{
+		{Pattern: "^terraform-state-", Reason: "Terraform state buckets managed separately"},
+		{Pattern: "bastion"},
+	})
+	require.NoError(t, err)
+
+	resources := []ResourceMetadata{
+		newExclusionTestResource("terraform-state-prod", "terraform-state-prod", "arn:aws:s3:::terraform-state-prod"),
+		newExclusionTestResource("i-0123456789", "bastion-host", "arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789"),
+		newExclusionTestResource("app-data", "app-data", "arn:aws:s3:::app-data"),
+	}
+
+	kept, excluded := filter.Apply(resources)
+
+	require.Len(t, kept, 1)
+	assert.Equal(t, "app-data", kept[0].ID)
+
+	require.Len(t, excluded, 2)
+	assert.Equal(t, "terraform-state-prod", excluded[0].Resource.ID)
+	assert.Equal(t, "Terraform state buckets managed separately", excluded[0].Reason)
+	assert.Equal(t, "i-0123456789", excluded[1].Resource.ID, "name matches should exclude the resource")
+	assert.Equal(t, "bastion", excluded[1].Pattern)
+}
+
+func TestExclusionFilterEmpty(t *testing.T) {
+	t.Parallel()
+
+	filter, err := NewExclusionFilter(nil)
+	require.NoError(t, err)
+
+	resources := []ResourceMetadata{newExclusionTestResource("bucket", "bucket", "arn:aws:s3:::bucket")}
+	kept, excluded := filter.Apply(resources)
+
+	assert.Equal(t, resources, kept)
+	assert.Empty(t, excluded)
+}
+
+func TestNewExclusionFilterInvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	filter, err := NewExclusionFilter([]configuration.ExcludedResource{{Pattern: "[invalid"}})
+	assert.Error(t, err)
+	assert.Nil(t, filter)
+}
@@ -0,0 +1,88 @@
+package inspector
+
+import (
+	"context"
+	"maps"
+)
+
+// PreviousResource is the state of a resource recorded by an earlier run
+type PreviousResource struct {
+	ARN          string
+	ResourceID   string
+	ResourceType string
+	AccountID    string
+	Region       string
+	Tags         map[string]string
+
+	// ChangeMarker is the change marker of the resource when it was recorded, see
+	// ResourceMetadata.ChangeMarker
+	ChangeMarker string
+}
+
+// PreviousScan holds the resources recorded by the previous run of an incremental scan.
+//
+// Inspectors whose resources carry a change marker reuse the recorded tags of the resources
+// whose marker is unchanged instead of reading them again. Resources without a marker, or
+// absent from the previous run, are always fully inspected.
+type PreviousScan struct {
+	resources map[string]PreviousResource
+}
+
+// NewPreviousScan creates a PreviousScan from the resources of the previous run, keyed by
+// their ARN, or their ID when they have none
+func NewPreviousScan(resources []PreviousResource) *PreviousScan {
+	previous := &PreviousScan{resources: make(map[string]PreviousResource, len(resources))}
+	for _, resource := range resources {
+		previous.resources[resource.key()] = resource
+	}
+	return previous
+}
+
+// Lookup returns the recorded state of the resource with the given ARN or ID
+func (p *PreviousScan) Lookup(arn string) (PreviousResource, bool) {
+	resource, exists := p.resources[arn]
+	return resource, exists
+}
+
+// Resources returns every resource of the previous run, in no particular order
+func (p *PreviousScan) Resources() []PreviousResource {
+	resources := make([]PreviousResource, 0, len(p.resources))
+	for _, resource := range p.resources {
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+// Len returns the number of resources of the previous run
+func (p *PreviousScan) Len() int {
+	return len(p.resources)
+}
+
+// key returns the key of the resource, its ARN or its ID when it has none
+func (r PreviousResource) key() string {
+	if r.ARN != "" {
+		return r.ARN
+	}
+	return r.ResourceID
+}
+
+// previousTags returns the tags recorded for a resource by the previous run of an
+// incremental scan, when the context carries one and the change marker of the resource is
+// the recorded one. The returned map is a copy the caller may modify.
+func previousTags(ctx context.Context, arn, changeMarker string) (map[string]string, bool) {
+	previous := scanControlsFromContext(ctx).Previous
+	if previous == nil || changeMarker == "" {
+		return nil, false
+	}
+
+	resource, exists := previous.Lookup(arn)
+	if !exists || resource.ChangeMarker != changeMarker {
+		return nil, false
+	}
+
+	tags := maps.Clone(resource.Tags)
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	return tags, true
+}
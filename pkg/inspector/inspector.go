@@ -7,6 +7,7 @@
 
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
 	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
 )
 
 // InspectResult represents the outcome of a resource inspection operation
@@ -38,11 +39,80 @@ type InspectResult struct {
 	// It provides a quick summary of the scan's scope.
 	TotalResources int `json:"total_resources"`
 
+	// ExcludedResources lists the resources skipped because they matched an exclusion pattern.
+	// Excluded resources are not part of Resources nor counted in TotalResources.
+	ExcludedResources []ExcludedResource `json:"excluded_resources,omitempty"`
+
+	// OutOfRegionResources counts the resources left out because they are located outside the
+	// regions they were restricted to, such as S3 buckets outside their bucket_regions.
+	OutOfRegionResources int `json:"out_of_region_resources,omitempty"`
+
+	// Metadata describes how the inspection used the AWS APIs.
+	// It is populated by the InspectorManager once the inspection completes.
+	Metadata ScanMetadata `json:"metadata"`
+
+	// Truncated reports that the inspection stopped early because the max_resources_per_service
+	// or max_api_calls limit of the configuration was hit, so Resources is partial.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Sampling records how the discovered resources were sampled when the scan is sampled,
+	// Resources then only holding the sampled ones. It is populated by the InspectorManager.
+	Sampling *SampleInfo `json:"sampling,omitempty"`
+
 	// Errors is an optional slice of error messages encountered during the inspection process.
 	// If any errors occurred during resource discovery or processing, they will be captured here.
 	Errors []string `json:"errors,omitempty"`
 }
 
+// ScanMetadata summarizes the AWS API usage of an inspection
+type ScanMetadata struct {
+	// APICallsMade is the number of AWS API calls issued while inspecting the resource type
+	APICallsMade int64 `json:"api_calls_made"`
+
+	// RetriesPerformed is the number of attempts retried after a throttling or transient error
+	RetriesPerformed int64 `json:"retries_performed"`
+
+	// RateLimit is the requests-per-second limit applied to the resource type, 0 when unlimited
+	RateLimit float64 `json:"rate_limit,omitempty"`
+
+	// Throttles is the number of attempts AWS throttled while inspecting the resource type
+	Throttles int64 `json:"throttles,omitempty"`
+
+	// MaxConcurrency is the global bound on concurrent operations shared by all inspectors
+	MaxConcurrency int `json:"max_concurrency"`
+
+	// EffectiveConcurrency is the bound on concurrent operations when the inspection ended,
+	// lower than MaxConcurrency while the scan backs off from throttling
+	EffectiveConcurrency int `json:"effective_concurrency"`
+
+	// BatchSize is the batch size used to inspect the resource type
+	BatchSize int `json:"batch_size"`
+
+	// Workers is the number of workers processing the resources of the resource type
+	Workers int `json:"workers"`
+
+	// Cached reports whether the resources were read from the scan cache instead of AWS
+	Cached bool `json:"cached,omitempty"`
+}
+
+// ScanEstimate is the expected size of the inspection of a resource type, measured by
+// discovering its resources without processing them
+type ScanEstimate struct {
+	// ResourcesByRegion is the number of resources discovered in each region
+	ResourcesByRegion map[string]int `json:"resources_by_region" yaml:"resources_by_region"`
+
+	// Resources is the number of resources discovered across regions
+	Resources int `json:"resources" yaml:"resources"`
+
+	// DiscoveryAPICalls is the number of AWS API calls the discovery made
+	DiscoveryAPICalls int64 `json:"discovery_api_calls" yaml:"discovery_api_calls"`
+
+	// EstimatedAPICalls is the expected number of AWS API calls of a full inspection: the
+	// discovery calls plus one call per resource to read its tags. Services reading more
+	// than the tags of a resource make more calls.
+	EstimatedAPICalls int64 `json:"estimated_api_calls" yaml:"estimated_api_calls"`
+}
+
 // Inspector defines the interface for cloud resource inspection operations
 // Inspector defines an interface for cloud resource inspection and retrieval operations.
 // It provides methods to discover and fetch detailed information about cloud resources.
@@ -86,7 +156,7 @@ type Inspector interface {
 //     (e.g., "s3", "ec2", "vpc"). The type is case-insensitive and will be
 //     normalized internally.
 //   - cfg: A TaggyScanConfig containing AWS-specific configuration settings,
-//     including the list of regions to scan.
+//     including the list of regions to scan and the retry policy of API calls.
 //
 // Returns:
 //   - Inspector: An initialized inspector implementation specific to the
@@ -99,6 +169,7 @@ type Inspector interface {
 //   - EC2 (Elastic Compute Cloud)
 //   - VPC (Virtual Private Cloud)
 //   - Route 53 (AWS Route 53)
+//   - Custom resource types added with RegisterInspector
 //
 // Example usage:
 //
@@ -108,29 +179,88 @@ type Inspector interface {
 //	}
 func New(resourceType string, cfg configuration.TaggyScanConfig) (Inspector, error) {
 	// Determine regions to use
+	regions, err := inspectedRegions(resourceType, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Custom inspectors bring their own clients
+	if entry, exists := lookupInspector(resourceType); exists && entry.newAWSInspector == nil {
+		return entry.factory(regions)
+	}
+
+	clientManager, err := NewAWSRegionalClientManager(regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client manager: %w", err)
+	}
+
+	return newInspector(resourceType, regions, clientManager, cfg)
+}
+
+// NewForAccount creates a new Inspector for a specific AWS resource type that scans
+// another AWS account by assuming the role declared for it in the configuration.
+//
+// It behaves like New, except that the inspector's AWS clients are backed by
+// credentials obtained through STS AssumeRole, and every discovered resource is
+// attributed to the configured account ID.
+//
+// Parameters:
+//   - resourceType: The type of AWS resource to inspect (e.g., "s3", "ec2")
+//   - cfg: A TaggyScanConfig containing the list of regions to scan
+//   - account: The account to scan, with the role to assume
+//
+// Returns:
+//   - Inspector: An inspector whose clients operate in the given account
+//   - error: An error if the resource type is unsupported or the clients cannot be configured
+func NewForAccount(resourceType string, cfg configuration.TaggyScanConfig, account configuration.AccountConfig) (Inspector, error) {
+	regions, err := inspectedRegions(resourceType, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Custom inspectors do not scan through the account role
+	if entry, exists := lookupInspector(resourceType); exists && entry.newAWSInspector == nil {
+		return entry.factory(regions)
+	}
+
+	clientManager, err := NewAWSAccountClientManager(regions, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client manager for account %s: %w", account.AccountID, err)
+	}
+
+	return newInspector(resourceType, regions, clientManager, cfg)
+}
+
+// inspectedRegions returns the regions an inspector of the resource type scans: the regions
+// of the configuration, or only the endpoint region of global services so their resources
+// are listed once
+func inspectedRegions(resourceType string, cfg configuration.TaggyScanConfig) ([]string, error) {
 	regions, err := GetEffectiveRegions(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("error getting effective regions: %w", err)
 	}
+	if IsGlobalResourceType(resourceType) {
+		return []string{constants.GlobalServiceRegion}, nil
+	}
+	return regions, nil
+}
+
+// newInspector builds the inspector of a resource type on top of the given client manager,
+// applying the retry policy of the configuration to its AWS clients
+func newInspector(resourceType string, regions []string, clientManager *AWSClientManager, cfg configuration.TaggyScanConfig) (Inspector, error) {
+	retryPolicy, err := NewRetryPolicy(cfg.AWS.Retries)
+	if err != nil {
+		return nil, err
+	}
+	clientManager.SetRetryPolicy(retryPolicy)
+	clientManager.SetEndpointURL(cfg.AWS.EndpointURL)
 
-	switch resourceType {
-	case constants.ResourceTypeS3:
-		return NewS3Inspector(regions)
-	case constants.ResourceTypeEC2:
-		return NewEC2Scanner(regions)
-	case constants.ResourceTypeVPC:
-		return NewVPCInspector(regions)
-	case constants.ResourceTypeCloudWatchLogs:
-		return NewCloudWatchLogsInspector(regions)
-	case constants.ResourceTypeRoute53:
-		return NewRoute53Inspector(regions)
-	case constants.ResourceTypeSNS:
-		return NewSNSInspector(regions)
-	case constants.ResourceTypeRDS:
-		return NewRDSInspector(regions)
-	case constants.ResourceTypeSQS:
-		return NewSQSInspector(regions)
-	default:
+	entry, exists := lookupInspector(resourceType)
+	if !exists {
 		return nil, fmt.Errorf("unsupported resource type: %s", resourceType)
 	}
+	if entry.newAWSInspector == nil {
+		return entry.factory(regions)
+	}
+	return entry.newAWSInspector(regions, clientManager, o11y.DefaultLogger()), nil
 }
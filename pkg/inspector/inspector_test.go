@@ -1,10 +1,15 @@
 package inspector
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestResourceMetadata(t *testing.T) {
@@ -100,6 +105,52 @@ func TestResourceMetadata(t *testing.T) {
 	}
 }
 
+func TestResourceMetadataRawResponse(t *testing.T) {
+	t.Parallel()
+
+	instance := types.Instance{
+		InstanceId:   aws.String("i-0abc"),
+		InstanceType: types.InstanceTypeT3Micro,
+		State:        &types.InstanceState{Name: types.InstanceStateNameRunning},
+		Placement:    &types.Placement{AvailabilityZone: aws.String("us-east-1a")},
+		Tags:         []types.Tag{{Key: aws.String("Owner"), Value: aws.String("platform")}},
+	}
+	for i := 0; i < 20; i++ {
+		instance.BlockDeviceMappings = append(instance.BlockDeviceMappings, types.InstanceBlockDeviceMapping{
+			DeviceName: aws.String(fmt.Sprintf("/dev/sd%c", 'a'+i)),
+			Ebs:        &types.EbsInstanceBlockDevice{VolumeId: aws.String(fmt.Sprintf("vol-%04d", i))},
+		})
+	}
+
+	resource := ResourceMetadata{ID: "i-0abc", Type: "ec2", RawResponse: instance}
+
+	withoutRaw, err := json.Marshal(resource)
+	require.NoError(t, err)
+	assert.NotContains(t, string(withoutRaw), "raw_response")
+
+	resource.IncludeRaw = true
+	withRaw, err := json.Marshal(resource)
+	require.NoError(t, err)
+	assert.Greater(t, len(withRaw), 5*len(withoutRaw), "raw responses dominate the size of the metadata")
+	t.Logf("metadata size: %d bytes without raw response, %d bytes with it", len(withoutRaw), len(withRaw))
+
+	var decoded struct {
+		RawResponse map[string]interface{} `json:"raw_response"`
+	}
+	require.NoError(t, json.Unmarshal(withRaw, &decoded))
+	assert.Equal(t, "i-0abc", decoded.RawResponse["InstanceId"])
+	assert.Len(t, decoded.RawResponse["BlockDeviceMappings"], 20)
+
+	// Responses that cannot be serialized are summarized instead of failing the output
+	resource.RawResponse = make(chan int)
+	withRaw, err = json.Marshal(resource)
+	require.NoError(t, err)
+	assert.Contains(t, string(withRaw), `"type":"chan int"`)
+
+	resource.RawResponse = nil
+	assert.Nil(t, resource.RawResponseMap())
+}
+
 func TestBaseResource(t *testing.T) {
 	t.Parallel()
 
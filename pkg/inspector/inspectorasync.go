@@ -5,6 +5,7 @@
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 )
 
 // AsyncResourceInspector handles asynchronous resource scanning
@@ -45,7 +46,38 @@ func NewAsyncResourceInspector(config InspectorConfig) *AsyncResourceInspector {
 	}
 }
 
-// startResourceDiscovery initiates parallel resource discovery for given regions
+// limiter returns the concurrency limiter shared with other inspectors, preferring the
+// one set in the configuration over the one carried by the context
+func (s *AsyncResourceInspector) limiter(ctx context.Context) *ConcurrencyLimiter {
+	if s.config.Limiter != nil {
+		return s.config.Limiter
+	}
+	return scanControlsFromContext(ctx).Limiter
+}
+
+// resourceQuota hands out the resources a scan may discover across its regions, without
+// limit when max is zero
+type resourceQuota struct {
+	max   int64
+	taken atomic.Int64
+}
+
+// take reserves a resource, reporting false once the quota is used up
+func (q *resourceQuota) take() bool {
+	if q.max <= 0 {
+		return true
+	}
+	return q.taken.Add(1) <= q.max
+}
+
+// full reports whether the quota is used up
+func (q *resourceQuota) full() bool {
+	return q.max > 0 && q.taken.Load() >= q.max
+}
+
+// startResourceDiscovery initiates parallel resource discovery for given regions. Discovery
+// stops once the resource quota or the API call budget of the scan controls is used up,
+// marking the scan as truncated.
 func (s *AsyncResourceInspector) startResourceDiscovery(
 	ctx context.Context,
 	regions []string,
@@ -53,8 +85,10 @@ func (s *AsyncResourceInspector) startResourceDiscovery(
 	resourceChan chan interface{},
 	errorChan chan error,
 	discoveryWg *sync.WaitGroup,
-	processingWg *sync.WaitGroup,
+	quota *resourceQuota,
 ) {
+	controls := scanControlsFromContext(ctx)
+
 	for _, region := range regions {
 		select {
 		case <-ctx.Done():
@@ -66,13 +100,35 @@ func (s *AsyncResourceInspector) startResourceDiscovery(
 			go func(r string) {
 				defer discoveryWg.Done()
 
+				if quota.full() || controls.Budget.Exhausted() {
+					s.config.Logger.Warn("Scan limit reached, skipping resource discovery",
+						"region", r)
+					controls.markTruncated()
+					return
+				}
+
+				limiter := s.limiter(ctx)
+				if err := limiter.Acquire(ctx); err != nil {
+					s.config.Logger.Error("Context cancelled while waiting for a discovery slot",
+						"region", r,
+						"error", err)
+					return
+				}
 				resources, err := discoverer(ctx, r)
+				limiter.Release()
+				// Calls refused once the API call budget is spent truncate the scan without
+				// failing the region
+				if errors.Is(err, ErrAPICallBudgetExhausted) {
+					s.config.Logger.Warn("API call budget spent, stopping resource discovery",
+						"region", r)
+					return
+				}
 				if err != nil {
 					s.config.Logger.Error("Failed to discover resources",
 						"region", r,
 						"error", err)
 					select {
-					case errorChan <- fmt.Errorf("failed to discover resources in region %s: %w", r, err):
+					case errorChan <- &RegionError{Region: r, Err: err}:
 					case <-ctx.Done():
 						s.config.Logger.Error("Context cancelled while sending discovery error",
 							"region", r,
@@ -85,15 +141,28 @@ func (s *AsyncResourceInspector) startResourceDiscovery(
 					"region", r,
 					"count", len(resources))
 
-				processingWg.Add(len(resources))
+				// Estimates only need the number of resources, which are not processed
+				if controls.DiscoveryOnly {
+					if controls.Stats != nil {
+						controls.Stats.addDiscovered(r, len(resources))
+					}
+					return
+				}
 
 				for _, resource := range resources {
+					if !quota.take() {
+						s.config.Logger.Warn("Maximum number of resources reached, stopping resource discovery",
+							"region", r,
+							"max_resources", quota.max)
+						controls.markTruncated()
+						return
+					}
+
 					select {
 					case resourceChan <- resource:
 					case <-ctx.Done():
 						s.config.Logger.Error("Context cancelled while sending resource",
 							"region", r)
-						processingWg.Add(-1)
 						return
 					}
 				}
@@ -108,9 +177,10 @@ func (s *AsyncResourceInspector) startResourceProcessing(
 	resourceChan chan interface{},
 	resultChan chan ResourceMetadata,
 	processor ResourceProcessor,
-	processingWg *sync.WaitGroup,
 ) {
 	workerWg := &sync.WaitGroup{}
+	limiter := s.limiter(ctx)
+	controls := scanControlsFromContext(ctx)
 
 	for i := 0; i < s.config.NumWorkers; i++ {
 		workerWg.Add(1)
@@ -123,8 +193,20 @@ func (s *AsyncResourceInspector) startResourceProcessing(
 						return
 					}
 					func() {
-						defer processingWg.Done()
+						// Resources left once the API call budget is spent are dropped
+						if controls.Budget.Exhausted() {
+							controls.markTruncated()
+							return
+						}
+
+						if err := limiter.Acquire(ctx); err != nil {
+							s.config.Logger.Error("Context cancelled while waiting for a processing slot",
+								"worker", workerID,
+								"error", err)
+							return
+						}
 						metadata, err := processor(ctx, resource)
+						limiter.Release()
 						if err != nil {
 							s.config.Logger.Error("Failed to process resource",
 								"worker", workerID,
@@ -165,57 +247,22 @@ func() {
 	}()
 }
 
-// manageChannelLifecycle handles closing of channels and waiting for goroutines
+// manageChannelLifecycle closes the resource and error channels once every discovery
+// goroutine has returned, as they are their only senders. Closing them any earlier, even on
+// cancellation, could make a discoverer send on a closed channel. The result channel is
+// closed by the workers themselves once they have all returned.
 func (s *AsyncResourceInspector) manageChannelLifecycle(
-	ctx context.Context,
 	resourceChan chan interface{},
-	resultChan chan ResourceMetadata,
 	errorChan chan error,
 	discoveryWg *sync.WaitGroup,
-	processingWg *sync.WaitGroup,
 ) {
-	// Create a channel to coordinate closing
-	done := make(chan struct{})
-	closeOnce := sync.Once{}
-
-	// Close channels in the correct order
 	go func() {
-		defer close(done)
-
-		// First wait for discovery to complete
 		discoveryWg.Wait()
 		s.config.Logger.Info("Resource discovery completed")
 
-		// Then close the resource channel
-		closeOnce.Do(func() {
-			close(resourceChan)
-			s.config.Logger.Info("Resource channel closed")
-		})
-
-		// Wait for processing to complete
-		processingWg.Wait()
-		s.config.Logger.Info("Resource processing completed")
-
-		// Finally close the error channel
+		close(resourceChan)
 		close(errorChan)
-		s.config.Logger.Info("Error channel closed")
 	}()
-
-	// Wait for completion or context cancellation
-	select {
-	case <-done:
-		s.config.Logger.Info("Channel lifecycle management completed normally")
-		return
-	case <-ctx.Done():
-		s.config.Logger.Error("Context cancelled during channel lifecycle management",
-			"error", ctx.Err())
-		// Ensure channels are closed even on cancellation
-		closeOnce.Do(func() {
-			close(resourceChan)
-			s.config.Logger.Info("Resource channel closed on cancellation")
-		})
-		return
-	}
 }
 
 // collectScanResults aggregates processed resources and errors
@@ -266,20 +313,73 @@ func (s *AsyncResourceInspector) collectScanResults(
 				}
 				mu.Unlock()
 			case <-ctx.Done():
-				if ctx.Err() != nil {
-					mu.Lock()
-					scanErrors = append(scanErrors, ctx.Err())
-					mu.Unlock()
-				}
 				return
 			}
 		}
 	}()
 
 	wg.Wait()
+
+	// The error channel may already be closed when the context is cancelled mid-processing
+	if err := ctx.Err(); err != nil {
+		scanErrors = append(scanErrors, err)
+	}
+
 	return results, scanErrors
 }
 
+// sampleDiscovery is the post-discovery hook of sampled scans: it discovers the resources of
+// every region, samples them across the regions and returns a discoverer handing out the
+// sampled resources of each region, or the discovery error of the regions that failed
+func (s *AsyncResourceInspector) sampleDiscovery(
+	ctx context.Context,
+	regions []string,
+	discoverer ResourceDiscoverer,
+	controls ScanControls,
+) ResourceDiscoverer {
+	discovered := make([][]interface{}, len(regions))
+	discoveryErrors := make([]error, len(regions))
+	limiter := s.limiter(ctx)
+
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := limiter.Acquire(ctx); err != nil {
+				discoveryErrors[i] = err
+				return
+			}
+			defer limiter.Release()
+			discovered[i], discoveryErrors[i] = discoverer(ctx, region)
+		}()
+	}
+	wg.Wait()
+
+	sampled := sampleResources(controls.Sample, discovered)
+	total, kept := 0, 0
+	for i := range regions {
+		total += len(discovered[i])
+		kept += len(sampled[i])
+	}
+	if controls.Stats != nil {
+		controls.Stats.addSampled(total, kept)
+	}
+	s.config.Logger.Info(fmt.Sprintf("Sampled %d of %d discovered resources", kept, total),
+		"sample", controls.Sample.String(),
+		"seed", controls.Sample.Seed)
+
+	byRegion := make(map[string]int, len(regions))
+	for i, region := range regions {
+		byRegion[region] = i
+	}
+	return func(_ context.Context, region string) ([]interface{}, error) {
+		i := byRegion[region]
+		return sampled[i], discoveryErrors[i]
+	}
+}
+
 // InspectResourcesAsync performs asynchronous resource scanning using the provided discoverer and processor functions
 // InspectResourcesAsync performs an asynchronous, parallel scanning of resources across multiple regions.
 //
@@ -313,28 +413,30 @@ func (s *AsyncResourceInspector) InspectResourcesAsync(
 	resultChan := make(chan ResourceMetadata, s.config.BatchSize*len(regions))
 	errorChan := make(chan error, len(regions)*2)
 
-	var discoveryWg, processingWg sync.WaitGroup
+	var discoveryWg sync.WaitGroup
+	controls := scanControlsFromContext(ctx)
+	quota := &resourceQuota{max: int64(controls.MaxResources)}
+
+	// Sampled scans discover every region first, only the sampled resources being processed
+	if !controls.Sample.IsZero() && !controls.DiscoveryOnly {
+		discoverer = s.sampleDiscovery(ctx, regions, discoverer, controls)
+	}
 
 	// Start resource discovery
-	s.startResourceDiscovery(ctx, regions, discoverer, resourceChan, errorChan, &discoveryWg, &processingWg)
+	s.startResourceDiscovery(ctx, regions, discoverer, resourceChan, errorChan, &discoveryWg, quota)
 
 	// Start resource processing
-	s.startResourceProcessing(ctx, resourceChan, resultChan, processor, &processingWg)
+	s.startResourceProcessing(ctx, resourceChan, resultChan, processor)
 
 	// Manage channel lifecycle
-	s.manageChannelLifecycle(ctx, resourceChan, resultChan, errorChan, &discoveryWg, &processingWg)
+	s.manageChannelLifecycle(resourceChan, errorChan, &discoveryWg)
 
-	// Collect results and errors
+	// Collect results and errors while the scan runs, returning as soon as the context is
+	// cancelled; in-flight goroutines unwind on their own once their current call returns
 	results, scanErrors := s.collectScanResults(ctx, resultChan, errorChan)
 
 	if len(scanErrors) > 0 {
-		// Create a detailed error message
-		errMsg := fmt.Sprintf("scanning encountered %d errors:\n", len(scanErrors))
-		for i, err := range scanErrors {
-			errMsg += fmt.Sprintf("  %d. %v\n", i+1, err)
-		}
-
-		return results, errors.New(errMsg)
+		return results, scanErrorList(scanErrors)
 	}
 
 	return results, nil
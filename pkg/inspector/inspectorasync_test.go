@@ -0,0 +1,247 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syntheticDiscoverer returns count resources in every region
+func syntheticDiscoverer(count int) ResourceDiscoverer {
+	return func(ctx context.Context, region string) ([]interface{}, error) {
+		resources := make([]interface{}, count)
+		for i := range resources {
+			resources[i] = fmt.Sprintf("%s-%d", region, i)
+		}
+		return resources, nil
+	}
+}
+
+func TestInspectResourcesAsyncReturnsPromptlyOnCancellation(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, processed atomic.Int64
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		select {
+		case <-time.After(50 * time.Millisecond):
+			processed.Add(1)
+			return ResourceMetadata{ID: resource.(string)}, nil
+		case <-ctx.Done():
+			return ResourceMetadata{}, ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	regions := []string{"us-east-1", "eu-west-1", "ap-south-1"}
+	start := time.Now()
+	results, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(ctx, regions, syntheticDiscoverer(500), processor)
+	elapsed := time.Since(start)
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, context.Canceled.Error())
+	assert.Less(t, elapsed, time.Second, "scan must stop shortly after the context is cancelled")
+	assert.Less(t, len(results), len(regions)*500)
+
+	// Every processor call unwinds once the context is cancelled
+	assert.Eventually(t, func() bool { return inFlight.Load() == 0 }, time.Second, 10*time.Millisecond)
+	settled := processed.Load()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, settled, processed.Load(), "no resource must be processed after cancellation")
+}
+
+func TestInspectResourcesAsyncDoesNotWaitForSlowProcessors(t *testing.T) {
+	t.Parallel()
+
+	// A processor ignoring the context must not hold the scan once it is cancelled
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		time.Sleep(time.Second)
+		return ResourceMetadata{ID: resource.(string)}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(ctx, []string{"us-east-1"}, syntheticDiscoverer(100), processor)
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, context.DeadlineExceeded.Error())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestInspectResourcesAsyncCollectsMoreResultsThanBuffered(t *testing.T) {
+	t.Parallel()
+
+	config := quietInspectorConfig()
+	config.BatchSize = 1
+
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		return ResourceMetadata{ID: resource.(string)}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	results, err := NewAsyncResourceInspector(config).
+		InspectResourcesAsync(ctx, []string{"us-east-1", "eu-west-1"}, syntheticDiscoverer(200), processor)
+
+	require.NoError(t, err)
+	assert.Len(t, results, 400)
+}
+
+func TestInspectResourcesAsyncStopsAtMaxResources(t *testing.T) {
+	t.Parallel()
+
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		return ResourceMetadata{ID: resource.(string)}, nil
+	}
+
+	stats := &ScanStats{}
+	ctx := WithScanControls(context.Background(), ScanControls{Stats: stats, MaxResources: 150})
+
+	results, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(ctx, []string{"us-east-1", "eu-west-1"}, syntheticDiscoverer(100), processor)
+
+	require.NoError(t, err)
+	assert.Len(t, results, 150)
+	assert.True(t, stats.Truncated())
+}
+
+func TestInspectResourcesAsyncStopsWhenAPICallBudgetIsSpent(t *testing.T) {
+	t.Parallel()
+
+	// Every discovery makes one API call, spending the whole budget
+	discoverer := func(ctx context.Context, region string) ([]interface{}, error) {
+		if err := scanControlsFromContext(ctx).beforeAPICall(ctx); err != nil {
+			return nil, err
+		}
+		return syntheticDiscoverer(10)(ctx, region)
+	}
+	var processed atomic.Int64
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		processed.Add(1)
+		return ResourceMetadata{ID: resource.(string)}, nil
+	}
+
+	stats := &ScanStats{}
+	budget := NewAPICallBudget(1)
+	ctx := WithScanControls(context.Background(), ScanControls{Stats: stats, Budget: budget})
+
+	results, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(ctx, []string{"us-east-1", "eu-west-1"}, discoverer, processor)
+
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Zero(t, processed.Load(), "no resource must be processed once the budget is spent")
+	assert.True(t, budget.Exhausted())
+	assert.True(t, stats.Truncated())
+}
+
+func TestInspectResourcesAsyncDiscoveryOnly(t *testing.T) {
+	t.Parallel()
+
+	var processed atomic.Int64
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		processed.Add(1)
+		return ResourceMetadata{ID: resource.(string)}, nil
+	}
+
+	stats := &ScanStats{}
+	ctx := WithScanControls(context.Background(), ScanControls{Stats: stats, DiscoveryOnly: true})
+
+	results, err := NewAsyncResourceInspector(quietInspectorConfig()).
+		InspectResourcesAsync(ctx, []string{"us-east-1", "eu-west-1"}, syntheticDiscoverer(25), processor)
+
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Zero(t, processed.Load())
+	assert.Equal(t, map[string]int{"us-east-1": 25, "eu-west-1": 25}, stats.Discovered())
+	assert.False(t, stats.Truncated())
+}
+
+func TestInspectResourcesAsyncSamplesDiscoveredResources(t *testing.T) {
+	t.Parallel()
+
+	var processed atomic.Int64
+	processor := func(ctx context.Context, resource interface{}) (ResourceMetadata, error) {
+		processed.Add(1)
+		return ResourceMetadata{ID: resource.(string)}, nil
+	}
+
+	inspect := func(sample Sample) ([]string, *ScanStats) {
+		stats := &ScanStats{}
+		ctx := WithScanControls(context.Background(), ScanControls{Stats: stats, Sample: sample})
+
+		results, err := NewAsyncResourceInspector(quietInspectorConfig()).
+			InspectResourcesAsync(ctx, []string{"us-east-1", "eu-west-1"}, syntheticDiscoverer(100), processor)
+		require.NoError(t, err)
+
+		ids := make([]string, 0, len(results))
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		slices.Sort(ids)
+		return ids, stats
+	}
+
+	sampled, stats := inspect(Sample{Percent: 10, Seed: 1})
+	assert.Len(t, sampled, 20)
+	assert.Equal(t, int64(20), processed.Load(), "only the sampled resources are processed")
+	assert.Equal(t, &SampleInfo{Discovered: 200, Sampled: 20}, stats.Sampling())
+
+	again, _ := inspect(Sample{Percent: 10, Seed: 1})
+	assert.Equal(t, sampled, again, "the same seed samples the same resources")
+
+	counted, stats := inspect(Sample{Count: 500, Seed: 1})
+	assert.Len(t, counted, 200)
+	assert.Equal(t, &SampleInfo{Discovered: 200, Sampled: 200}, stats.Sampling())
+
+	_, stats = inspect(Sample{})
+	assert.Nil(t, stats.Sampling(), "scans without a sample are not sampled")
+}
+
+func TestInspectResourcesAsyncEmitsJSONLogs(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	config := DefaultInspectorConfig()
+	config.Logger = o11y.NewLoggerWithFormat(&logs, o11y.LogLevelDebug, o11y.LogFormatJSON)
+
+	discoverer, processor := newMockedScan(5, 0, nil)
+	results, err := NewAsyncResourceInspector(config).
+		InspectResourcesAsync(context.Background(), []string{"us-east-1", "eu-west-1"}, discoverer, processor)
+	require.NoError(t, err)
+	require.Len(t, results, 10)
+
+	lines := 0
+	scanner := bufio.NewScanner(&logs)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry), "log line is not JSON: %s", scanner.Text())
+		assert.Contains(t, entry, "time")
+		assert.Contains(t, entry, "level")
+		assert.Contains(t, entry, "msg")
+		lines++
+	}
+	require.NoError(t, scanner.Err())
+
+	// One line per region discovered and per resource processed, at least
+	assert.GreaterOrEqual(t, lines, 12)
+}
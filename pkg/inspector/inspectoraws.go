@@ -6,6 +6,7 @@
 	"sync"
 
 	"github.com/Excoriate/aws-taggy/pkg/cloud"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
 	"github.com/aws/aws-sdk-go-v2/aws"
 )
 
@@ -15,6 +16,14 @@ type ResourceProcessor func(ctx context.Context, resource interface{
 // ResourceDiscoverer is a function type that discovers resources and sends them to a channel
 type ResourceDiscoverer func(ctx context.Context, region string) ([]interface{}, error)
 
+// RegionalResource wraps a discovered resource with the region it was discovered in.
+// Discoverers of regional services return their items wrapped, so that the processor
+// queries each resource with a client of its own region.
+type RegionalResource struct {
+	Region string
+	Item   interface{}
+}
+
 // AWSClient is an interface for AWS service clients
 type AWSClient interface {
 	CreateFromConfig(cfg *aws.Config) interface{}
@@ -29,7 +38,7 @@ type AWSClient interface {
 //
 // Fields:
 //   - mu: A read-write mutex (sync.RWMutex) to provide thread-safe access to the clients map
-//   - clients: A map storing AWS client configurations, keyed by region name
+//   - clients: A map storing AWS client configurations, keyed by region and assumed role
 //
 // The AWSClientManager is designed to support multi-region AWS operations by maintaining
 // a collection of pre-configured AWS client configurations that can be easily retrieved
@@ -38,8 +47,18 @@ type AWSClientManager struct {
 	// mu provides concurrent access control for the clients map
 	mu sync.RWMutex
 
-	// clients stores AWS configurations indexed by region
-	clients map[string]*aws.Config
+	// clients stores AWS configurations indexed by region and assumed role, so clients are
+	// never shared across identities
+	clients map[clientKey]*aws.Config
+
+	// account, when set, is the account scanned by assuming its role
+	account *configuration.AccountConfig
+
+	// retryer, when set, replaces the AWS SDK default retryer of every client
+	retryer func() aws.Retryer
+
+	// endpointURL, when set, replaces the AWS endpoint of every client, e.g. with LocalStack
+	endpointURL string
 }
 
 // NewAWSRegionalClientManager creates a new AWSClientManager with AWS client configurations for specified regions.
@@ -70,23 +89,128 @@ type AWSClientManager struct {
 //	}
 func NewAWSRegionalClientManager(regions []string) (*AWSClientManager, error) {
 	manager := &AWSClientManager{
-		clients: make(map[string]*aws.Config),
+		clients: make(map[clientKey]*aws.Config),
 	}
 
 	// Synchronous client creation for each specified region
+	if err := manager.loadRegions(regions); err != nil {
+		return nil, err
+	}
+
+	return manager, nil
+}
+
+// NewAWSAccountClientManager creates an AWSClientManager whose clients operate in another
+// AWS account, using credentials obtained by assuming the account's role.
+//
+// The role is assumed lazily, on the first API call made with one of the clients, so an
+// unreachable account surfaces as an error of the inspection rather than of the construction.
+//
+// Parameters:
+//   - regions: A slice of AWS region strings to preload configurations for
+//   - account: The account to scan, with the role to assume
+//
+// Returns:
+//   - *AWSClientManager: A manager whose clients use the assumed role credentials
+//   - error: An error if any region's client configuration fails to load
+func NewAWSAccountClientManager(regions []string, account configuration.AccountConfig) (*AWSClientManager, error) {
+	manager := &AWSClientManager{
+		clients: make(map[clientKey]*aws.Config),
+		account: &account,
+	}
+
+	if err := manager.loadRegions(regions); err != nil {
+		return nil, err
+	}
+
+	return manager, nil
+}
+
+// clientKey identifies the client configuration of a region and of the role it assumes,
+// empty for the default credentials
+type clientKey struct {
+	region  string
+	roleARN string
+}
+
+// clientKey returns the key of the client configuration of a region for the identity of the
+// manager
+func (m *AWSClientManager) clientKey(region string) clientKey {
+	key := clientKey{region: region}
+	if m.account != nil {
+		key.roleARN = m.account.RoleARN
+	}
+	return key
+}
+
+// newClientConfig builds the client configuration of a region, assuming the account role if any
+func (m *AWSClientManager) newClientConfig(region string) cloud.AWSClientConfig {
+	if m.account != nil {
+		return cloud.NewAWSAssumeRoleClientConfig(region, m.account.RoleARN, m.account.ExternalID)
+	}
+	return cloud.NewAWSClientConfig(region)
+}
+
+// loadRegions synchronously loads and stores the client configuration of each region
+func (m *AWSClientManager) loadRegions(regions []string) error {
 	for _, region := range regions {
 		// Create AWS client configuration for the current region
-		awsClientConfig := cloud.NewAWSClientConfig(region)
+		awsClientConfig := m.newClientConfig(region)
 		cfg, err := awsClientConfig.LoadConfig(context.Background())
 		if err != nil {
-			return nil, fmt.Errorf("failed to create AWS client for region %s: %w", region, err)
+			return fmt.Errorf("failed to create AWS client for region %s: %w", region, err)
 		}
 
+		m.applyClientOptions(cfg)
+
 		// Store the region-specific AWS configuration
-		manager.clients[region] = cfg
+		m.clients[m.clientKey(region)] = cfg
 	}
 
-	return manager, nil
+	return nil
+}
+
+// applyClientOptions configures the behavior shared by every client built from the configuration
+func (m *AWSClientManager) applyClientOptions(cfg *aws.Config) {
+	// Apply the scan controls carried by the request context to every API call
+	cfg.APIOptions = append(cfg.APIOptions, addScanControlsMiddleware)
+
+	if m.retryer != nil {
+		cfg.Retryer = m.retryer
+	}
+
+	if m.endpointURL != "" {
+		cloud.UseEndpoint(context.Background(), cfg, m.endpointURL)
+	}
+}
+
+// SetRetryPolicy makes every client of the manager retry transient AWS API errors
+// according to the given policy, including clients created for already loaded regions.
+func (m *AWSClientManager) SetRetryPolicy(policy RetryPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.retryer = policy.newRetryer()
+	for _, cfg := range m.clients {
+		cfg.Retryer = m.retryer
+	}
+}
+
+// SetEndpointURL sends the API calls of every client of the manager to a custom endpoint,
+// such as LocalStack or moto, including clients created for already loaded regions.
+// An empty URL keeps the AWS endpoints.
+func (m *AWSClientManager) SetEndpointURL(endpointURL string) {
+	if endpointURL == "" {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.endpointURL = endpointURL
+	for _, cfg := range m.clients {
+		cloud.UseEndpoint(context.Background(), cfg, endpointURL)
+	}
 }
 
 // GetClient retrieves an AWS client for a specific region
@@ -116,19 +240,21 @@ func (m *AWSClientManager) GetClient(region string, creator AWSClient) (interfac
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	cfg, exists := m.clients[region]
+	cfg, exists := m.clients[m.clientKey(region)]
 	if !exists {
 		// If the specific region client doesn't exist, create it
-		awsClientConfig := cloud.NewAWSClientConfig(region)
+		awsClientConfig := m.newClientConfig(region)
 		newCfg, err := awsClientConfig.LoadConfig(context.Background())
 		if err != nil {
 			return nil, fmt.Errorf("failed to create AWS client for region %s: %w", region, err)
 		}
 
+		m.applyClientOptions(newCfg)
+
 		// Store the new client configuration
 		m.mu.RUnlock()
 		m.mu.Lock()
-		m.clients[region] = newCfg
+		m.clients[m.clientKey(region)] = newCfg
 		m.mu.Unlock()
 		m.mu.RLock()
 
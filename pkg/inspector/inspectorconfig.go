@@ -1,6 +1,11 @@
 package inspector
 
-import "github.com/Excoriate/aws-taggy/pkg/o11y"
+import (
+	"context"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+)
 
 // InspectorConfig holds configuration for the scanning process
 // InspectorConfig represents the comprehensive configuration settings for the inspection process.
@@ -22,6 +27,10 @@ type InspectorConfig struct {
 	// BatchSize determines the number of resources processed in a single batch.
 	// Helps in managing memory and processing efficiency during large-scale inspections.
 	BatchSize int
+
+	// Limiter optionally bounds the concurrency of discovery and processing operations.
+	// When nil, the limiter carried by the scan controls of the context is used, if any.
+	Limiter *ConcurrencyLimiter
 }
 
 // DefaultInspectorConfig returns a default scan configuration
@@ -46,3 +55,39 @@ func DefaultInspectorConfig() InspectorConfig {
 		BatchSize:  100,
 	}
 }
+
+// ResolveInspectorConfig returns the inspector configuration of a resource type. Its batch
+// size and number of workers are taken from the resource type configuration, then from the
+// AWS configuration, then from the global configuration, falling back to the defaults of
+// DefaultInspectorConfig.
+func ResolveInspectorConfig(config configuration.TaggyScanConfig, resourceType string) InspectorConfig {
+	resolved := DefaultInspectorConfig()
+	resourceConfig := config.Resources[resourceType]
+
+	for _, batchSize := range []*int{config.Global.BatchSize, config.AWS.BatchSize, resourceConfig.BatchSize} {
+		if batchSize != nil && *batchSize > 0 {
+			resolved.BatchSize = *batchSize
+		}
+	}
+	for _, workers := range []*int{config.Global.Workers, config.AWS.Workers, resourceConfig.Workers} {
+		if workers != nil && *workers > 0 {
+			resolved.NumWorkers = *workers
+		}
+	}
+
+	return resolved
+}
+
+// inspectorConfigFromContext returns the default inspector configuration, with the batch size
+// and number of workers carried by the scan controls of the context when they are set
+func inspectorConfigFromContext(ctx context.Context) InspectorConfig {
+	config := DefaultInspectorConfig()
+	controls := scanControlsFromContext(ctx)
+	if controls.BatchSize > 0 {
+		config.BatchSize = controls.BatchSize
+	}
+	if controls.NumWorkers > 0 {
+		config.NumWorkers = controls.NumWorkers
+	}
+	return config
+}
@@ -0,0 +1,83 @@
+package inspector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestResolveInspectorConfig(t *testing.T) {
+	t.Parallel()
+
+	defaults := DefaultInspectorConfig()
+
+	tests := []struct {
+		name              string
+		config            configuration.TaggyScanConfig
+		expectedBatchSize int
+		expectedWorkers   int
+	}{
+		{
+			name:              "Defaults",
+			expectedBatchSize: defaults.BatchSize,
+			expectedWorkers:   defaults.NumWorkers,
+		},
+		{
+			name: "Global Settings",
+			config: configuration.TaggyScanConfig{
+				Global: configuration.GlobalConfig{BatchSize: intPtr(30), Workers: intPtr(4)},
+			},
+			expectedBatchSize: 30,
+			expectedWorkers:   4,
+		},
+		{
+			name: "AWS Settings Override Global Ones",
+			config: configuration.TaggyScanConfig{
+				Global: configuration.GlobalConfig{BatchSize: intPtr(30), Workers: intPtr(4)},
+				AWS:    configuration.AWSConfig{BatchSize: intPtr(50)},
+			},
+			expectedBatchSize: 50,
+			expectedWorkers:   4,
+		},
+		{
+			name: "Resource Settings Override AWS Ones",
+			config: configuration.TaggyScanConfig{
+				AWS: configuration.AWSConfig{BatchSize: intPtr(50), Workers: intPtr(6)},
+				Resources: map[string]configuration.ResourceConfig{
+					"s3":  {Workers: intPtr(2)},
+					"ec2": {BatchSize: intPtr(5), Workers: intPtr(20)},
+				},
+			},
+			expectedBatchSize: 50,
+			expectedWorkers:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolved := ResolveInspectorConfig(tt.config, "s3")
+			assert.Equal(t, tt.expectedBatchSize, resolved.BatchSize)
+			assert.Equal(t, tt.expectedWorkers, resolved.NumWorkers)
+		})
+	}
+}
+
+func TestInspectorConfigFromContext(t *testing.T) {
+	t.Parallel()
+
+	defaults := DefaultInspectorConfig()
+	assert.Equal(t, defaults.BatchSize, inspectorConfigFromContext(context.Background()).BatchSize)
+
+	ctx := WithScanControls(context.Background(), ScanControls{BatchSize: 7, NumWorkers: 3})
+	config := inspectorConfigFromContext(ctx)
+	assert.Equal(t, 7, config.BatchSize)
+	assert.Equal(t, 3, config.NumWorkers)
+}
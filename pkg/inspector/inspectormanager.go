@@ -4,25 +4,122 @@
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
 	"github.com/Excoriate/aws-taggy/pkg/o11y"
 )
 
+// inspectorTarget binds an inspector to the resource type and account it scans, and to the
+// role it assumes, empty for the default credentials
+type inspectorTarget struct {
+	resourceType string
+	accountID    string
+	roleARN      string
+	inspector    Inspector
+}
+
+// scope describes the resource type and account of the target in log and error messages
+func (t inspectorTarget) scope() string {
+	scope := t.resourceType
+	if IsGlobalResourceType(t.resourceType) {
+		scope = fmt.Sprintf("%s (global)", t.resourceType)
+	}
+	if t.accountID != "" {
+		scope = fmt.Sprintf("%s in account %s", scope, t.accountID)
+	}
+	return scope
+}
+
 // InspectorManager manages scanning operations across multiple resource types
 type InspectorManager struct {
-	inspectors map[string]Inspector
-	config     configuration.TaggyScanConfig
-	results    map[string]*InspectResult
-	logger     *o11y.Logger
-	errors     []string
+	inspectors   map[string]inspectorTarget
+	exclusions   map[string]*ExclusionFilter
+	limiter      *ConcurrencyLimiter
+	rateLimiters map[string]RateLimiter
+	settings     map[string]InspectorConfig
+	config       configuration.TaggyScanConfig
+	results      map[string]*InspectResult
+	logger       *o11y.Logger
+	progress     *o11y.ProgressReporter
+	errors       []string
+	cache        *ScanCache
+	previous     *PreviousScan
+	sample       Sample
+
+	// concurrency caps the workers of every inspector, as set by SetConcurrency, unlimited
+	// when zero
+	concurrency int
+
+	// serviceErrors are the failures of the last Inspect, by resource type and region
+	serviceErrors []ServiceError
+
+	// resultsMu guards the results, errors and bounds of the scans, which Results reads
+	// while Inspect runs
+	resultsMu  sync.RWMutex
+	startedAt  time.Time
+	finishedAt time.Time
+
+	// maxResources caps the resources discovered by each inspector, unlimited when zero, and
+	// budget caps the API calls of every inspector of the run together, unlimited when nil
+	maxResources int
+	budget       *APICallBudget
+
+	// onResult receives the result of every inspector instead of Results when set
+	onResult func(key string, result *InspectResult) error
+
+	// callerAccountID resolves the account of the default credentials, which keys their
+	// cached results
+	callerAccountID func(ctx context.Context, regions []string) (string, error)
 }
 
-// NewInspectorManagerFromConfig creates a new inspector manager based on the configuration
+// ResultKey returns the key under which the results of a resource type are stored.
+// Results of accounts scanned through AssumeRole are keyed by "<account-id>/<resource-type>".
+func ResultKey(accountID, resourceType string) string {
+	if accountID == "" {
+		return resourceType
+	}
+	return fmt.Sprintf("%s/%s", accountID, resourceType)
+}
+
+// ServiceResultKey returns the key under which the results of a resource type scanned in a
+// single account are stored: the account of the role of the resource type when it has one,
+// that of the default credentials otherwise
+func ServiceResultKey(config configuration.TaggyScanConfig, resourceType string) string {
+	if account, hasRole := config.Resources[resourceType].RoleAccount(); hasRole {
+		return ResultKey(account.AccountID, resourceType)
+	}
+	return ResultKey("", resourceType)
+}
+
+// TruncatedResults returns the sorted keys of the results cut short by the
+// max_resources_per_service or max_api_calls limit of the configuration
+func TruncatedResults(results map[string]*InspectResult) []string {
+	var keys []string
+	for key, result := range results {
+		if result.Truncated {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NewInspectorManagerFromConfig creates a new inspector manager based on the configuration.
+// When the configuration declares AWS accounts, one inspector is created per account and
+// resource type, each assuming the account's role, and the account of the default credentials
+// is scanned only when it is one of the declared accounts. Resource types declaring their own
+// role are scanned once with it, in the account of the role, whatever the declared accounts.
 func NewInspectorManagerFromConfig(config configuration.TaggyScanConfig) (*InspectorManager, error) {
 	logger := o11y.DefaultLogger()
-	inspectors := make(map[string]Inspector)
+	inspectors := make(map[string]inspectorTarget)
+	exclusions := make(map[string]*ExclusionFilter)
+	rateLimiters := make(map[string]RateLimiter)
+	settings := make(map[string]InspectorConfig)
 	results := make(map[string]*InspectResult)
 	errors := []string{}
 
@@ -42,63 +139,349 @@ func NewInspectorManagerFromConfig(config configuration.TaggyScanConfig) (*Inspe
 			continue
 		}
 
-		// Create scanner
-		scanner, err := New(resourceType, config)
+		filter, err := NewExclusionFilter(resourceConfig.ExcludedResources)
 		if err != nil {
-			errorMsg := fmt.Sprintf("Failed to create scanner for %s: %v", resourceType, err)
+			errorMsg := fmt.Sprintf("Failed to build exclusion filter for %s: %v", resourceType, err)
 			logger.Error(errorMsg)
 			errors = append(errors, errorMsg)
 			continue
 		}
+		exclusions[resourceType] = filter
+
+		if resourceConfig.RateLimit != nil {
+			rateLimiters[resourceType] = NewIntervalRateLimiter(*resourceConfig.RateLimit)
+		}
+		settings[resourceType] = ResolveInspectorConfig(config, resourceType)
+
+		// Resource types owned by another account, such as the log groups of a logging
+		// account, are scanned with their own role
+		if account, hasRole := resourceConfig.RoleAccount(); hasRole {
+			scanner, err := NewForAccount(resourceType, config, account)
+			if err != nil {
+				errorMsg := fmt.Sprintf("Failed to create scanner for %s with role %s: %v", resourceType, account.RoleARN, err)
+				logger.Error(errorMsg)
+				errors = append(errors, errorMsg)
+				continue
+			}
+
+			inspectors[ResultKey(account.AccountID, resourceType)] = inspectorTarget{
+				resourceType: resourceType,
+				accountID:    account.AccountID,
+				roleARN:      account.RoleARN,
+				inspector:    scanner,
+			}
+			continue
+		}
+
+		// Scan the account of the default credentials when no accounts are declared
+		if len(config.AWS.Accounts) == 0 {
+			scanner, err := New(resourceType, config)
+			if err != nil {
+				errorMsg := fmt.Sprintf("Failed to create scanner for %s: %v", resourceType, err)
+				logger.Error(errorMsg)
+				errors = append(errors, errorMsg)
+				continue
+			}
+
+			inspectors[ResultKey("", resourceType)] = inspectorTarget{
+				resourceType: resourceType,
+				inspector:    scanner,
+			}
+			continue
+		}
+
+		for _, account := range config.AWS.Accounts {
+			scanner, err := NewForAccount(resourceType, config, account)
+			if err != nil {
+				errorMsg := fmt.Sprintf("Failed to create scanner for %s in account %s: %v", resourceType, account.AccountID, err)
+				logger.Error(errorMsg)
+				errors = append(errors, errorMsg)
+				continue
+			}
+
+			inspectors[ResultKey(account.AccountID, resourceType)] = inspectorTarget{
+				resourceType: resourceType,
+				accountID:    account.AccountID,
+				roleARN:      account.RoleARN,
+				inspector:    scanner,
+			}
+		}
+	}
 
-		inspectors[resourceType] = scanner
+	// A single limiter bounds the concurrency of every inspector of the run
+	maxConcurrency := DefaultMaxConcurrency
+	if config.Global.MaxConcurrency != nil {
+		maxConcurrency = *config.Global.MaxConcurrency
+	}
+
+	var budget *APICallBudget
+	if config.AWS.MaxAPICalls != nil {
+		budget = NewAPICallBudget(int64(*config.AWS.MaxAPICalls))
+	}
+	var maxResources int
+	if config.AWS.MaxResourcesPerService != nil {
+		maxResources = *config.AWS.MaxResourcesPerService
+	}
+
+	if len(config.AWS.Accounts) > 0 {
+		logger.Info(fmt.Sprintf("Scanning %d declared AWS accounts; the account of the default credentials is scanned only when listed", len(config.AWS.Accounts)))
 	}
 
 	return &InspectorManager{
-		inspectors: inspectors,
-		config:     config,
-		results:    results,
-		logger:     logger,
-		errors:     errors,
+		inspectors:   inspectors,
+		exclusions:   exclusions,
+		limiter:      newAdaptiveLimiter(maxConcurrency, logger),
+		rateLimiters: rateLimiters,
+		settings:     settings,
+		config:       config,
+		results:      results,
+		logger:       logger,
+		progress:     o11y.DefaultProgress(),
+		errors:       errors,
+		maxResources: maxResources,
+		budget:       budget,
+
+		callerAccountID: func(ctx context.Context, regions []string) (string, error) {
+			return resolveCallerAccountID(ctx, regions, config.AWS.EndpointURL)
+		},
 	}, nil
 }
 
-// Inspect performs scanning for all configured resource types
+// SetRateLimiter installs a rate limiter invoked before every AWS API call made while
+// scanning the given resource type, replacing the one built from its rate_limit setting.
+func (sm *InspectorManager) SetRateLimiter(resourceType string, limiter RateLimiter) {
+	sm.rateLimiters[resourceType] = limiter
+}
+
+// newAdaptiveLimiter creates the limiter shared by the inspectors of a run, which backs off
+// from the throttling of their API calls
+func newAdaptiveLimiter(maxConcurrency int, logger *o11y.Logger) *ConcurrencyLimiter {
+	limiter := NewConcurrencyLimiter(maxConcurrency)
+	limiter.EnableAdaptiveThrottling(DefaultAdaptiveThrottling(), logger)
+	return limiter
+}
+
+// SetConcurrency caps the concurrent operations of the run and the workers of every
+// inspector to concurrency, overriding the max_concurrency and workers settings of the
+// configuration for this run. A non-positive value keeps the configured settings.
+func (sm *InspectorManager) SetConcurrency(concurrency int) {
+	if concurrency <= 0 {
+		return
+	}
+	sm.concurrency = concurrency
+	sm.limiter = newAdaptiveLimiter(concurrency, sm.logger)
+}
+
+// ShareLimits makes the manager share the concurrency limiter and the API call budget of
+// another, so that the scans of both count as a single run
+func (sm *InspectorManager) ShareLimits(other *InspectorManager) {
+	sm.limiter = other.limiter
+	sm.budget = other.budget
+}
+
+// settingsOf returns the inspector configuration resolved for a resource type, its workers
+// capped by SetConcurrency
+func (sm *InspectorManager) settingsOf(resourceType string) InspectorConfig {
+	settings, exists := sm.settings[resourceType]
+	if !exists {
+		settings = ResolveInspectorConfig(sm.config, resourceType)
+	}
+	if sm.concurrency > 0 {
+		settings.NumWorkers = min(settings.NumWorkers, sm.concurrency)
+	}
+	return settings
+}
+
+// SetCache makes Inspect reuse the results cached for the scanned accounts and regions,
+// and cache the results of the resource types it inspects. A nil cache disables caching.
+func (sm *InspectorManager) SetCache(cache *ScanCache) {
+	sm.cache = cache
+}
+
+// SetPreviousScan makes Inspect scan incrementally: inspectors reuse the tags recorded by
+// the previous run for the resources whose change marker is unchanged. A nil previous scan
+// inspects every resource.
+func (sm *InspectorManager) SetPreviousScan(previous *PreviousScan) {
+	sm.previous = previous
+}
+
+// SetSample makes Inspect sample the resources discovered by every inspector, reading the
+// tags of the sampled resources only. Sampled results are partial, they are neither cached
+// nor read from the cache. A zero sample inspects every resource.
+func (sm *InspectorManager) SetSample(sample Sample) {
+	sm.sample = sample
+}
+
+// SetResultHandler makes Inspect hand the result of every inspector to fn as soon as it
+// completes, instead of keeping it for Results. Calls of fn are serialized and receive
+// the key of the result, as in Results. An error of fn is returned by Inspect and stops
+// the results of the remaining inspectors from being handed over.
+func (sm *InspectorManager) SetResultHandler(fn func(key string, result *InspectResult) error) {
+	sm.onResult = fn
+}
+
+// Inspect performs scanning for all configured resource types.
+//
+// Failures of accounts scanned through AssumeRole do not abort the scan: they are
+// recorded, with the account ID, in the errors returned by GetErrors while the
+// remaining accounts keep being scanned. Every failure is also recorded by resource type
+// and region in GetServiceErrors, failures of the default credentials are returned as a
+// *ServiceFailuresError once the other resource types are scanned.
 func (sm *InspectorManager) Inspect(ctx context.Context) error {
 	var wg sync.WaitGroup
 	var mu sync.Mutex
+	var handlerErr error
+	var failures []error
+	failed, processed := 0, 0
 	errChan := make(chan error, len(sm.inspectors))
+
+	sm.resultsMu.Lock()
 	sm.errors = []string{} // Reset errors slice
+	sm.serviceErrors = nil
+	sm.startedAt, sm.finishedAt = time.Now(), time.Time{}
+	sm.resultsMu.Unlock()
+	defer func() {
+		sm.resultsMu.Lock()
+		sortServiceErrors(sm.serviceErrors)
+		sm.finishedAt = time.Now()
+		sm.resultsMu.Unlock()
+	}()
+
+	cacheKeys := sm.cacheKeys(ctx)
 
-	for resourceType, scanner := range sm.inspectors {
+	for key, target := range sm.inspectors {
 		wg.Add(1)
-		go func(rt string, s Inspector) {
+		go func(key string, target inspectorTarget) {
 			defer wg.Done()
 
-			sm.logger.Info(fmt.Sprintf("Scanning resource type: %s", rt))
-
-			result, err := s.Inspect(ctx, sm.config)
-			if err != nil {
-				errorMsg := fmt.Sprintf("Scanning %s failed: %v", rt, err)
-				sm.logger.Error(errorMsg)
+			rt := target.resourceType
+			scope := target.scope()
 
+			sm.logger.Info(fmt.Sprintf("Scanning resource type: %s", scope))
+			sm.progress.Emit(o11y.ProgressEvent{Event: o11y.ProgressServiceStarted, Service: rt, Account: target.accountID})
+			defer func() {
 				mu.Lock()
-				sm.errors = append(sm.errors, errorMsg)
-				errChan <- errors.New(errorMsg)
-				mu.Unlock()
-				return
+				defer mu.Unlock()
+				processed++
+				sm.progress.Emit(o11y.ProgressEvent{Event: o11y.ProgressProcessed, Processed: processed, Total: len(sm.inspectors)})
+			}()
+
+			// Share the global limiter, count the API calls of this inspector and apply the
+			// batch size and workers of its resource type
+			stats := &ScanStats{}
+			rateLimiter := sm.rateLimiters[rt]
+			settings := sm.settingsOf(rt)
+			scanCtx := WithScanControls(ctx, ScanControls{
+				Limiter:     sm.limiter,
+				RateLimiter: rateLimiter,
+				Stats:       stats,
+				BatchSize:   settings.BatchSize,
+				NumWorkers:  settings.NumWorkers,
+				Previous:    sm.previous,
+				Sample:      sm.sample,
+
+				MaxResources: sm.maxResources,
+				Budget:       sm.budget,
+			})
+
+			cacheKey, cacheable := cacheKeys[key]
+			cacheable = cacheable && sm.sample.IsZero()
+			result, cached := sm.loadCached(cacheKey, cacheable)
+			if cached {
+				sm.logger.Info(fmt.Sprintf("Using cached results for %s", scope))
+			} else {
+				var err error
+				result, err = target.inspector.Inspect(scanCtx, sm.config)
+				if err != nil {
+					errorMsg := fmt.Sprintf("Scanning %s failed: %v", scope, err)
+					sm.logger.Error(errorMsg)
+					sm.progress.Emit(o11y.ProgressEvent{Event: o11y.ProgressServiceFailed, Service: rt, Account: target.accountID, Error: err.Error()})
+
+					mu.Lock()
+					failed++
+					if target.accountID == "" {
+						failures = append(failures, errors.New(errorMsg))
+					}
+					mu.Unlock()
+
+					sm.resultsMu.Lock()
+					sm.errors = append(sm.errors, errorMsg)
+					sm.serviceErrors = append(sm.serviceErrors, newServiceErrors(target, err)...)
+					sm.resultsMu.Unlock()
+					return
+				}
+
+				result.Truncated = stats.Truncated()
+				result.Sampling = stats.Sampling()
+				if result.Truncated {
+					sm.logger.Warn(fmt.Sprintf("Results of %s are truncated: the max_resources_per_service or max_api_calls limit was hit", scope))
+				}
+
+				// Results of a cancelled or truncated scan are partial and must not be reused
+				if cacheable && ctx.Err() == nil && !result.Truncated {
+					if err := sm.cache.Store(cacheKey, result); err != nil {
+						sm.logger.Warn(fmt.Sprintf("Failed to cache results for %s: %v", scope, err))
+					}
+				}
+			}
+
+			// Resources that could not be inspected leave the rest of the result usable, they
+			// are reported with the errors of the scan
+			if len(result.Errors) > 0 {
+				sm.resultsMu.Lock()
+				for _, resourceErr := range result.Errors {
+					sm.errors = append(sm.errors, fmt.Sprintf("Scanning %s: %s", scope, resourceErr))
+				}
+				sm.resultsMu.Unlock()
 			}
 
-			// Store results by region for consistent access
+			result.Metadata = ScanMetadata{
+				APICallsMade:     stats.APICalls(),
+				RetriesPerformed: stats.Retries(),
+				Throttles:        stats.Throttles(),
+				MaxConcurrency:   sm.limiter.Capacity(),
+				BatchSize:        settings.BatchSize,
+				Workers:          settings.NumWorkers,
+				Cached:           cached,
+
+				EffectiveConcurrency: sm.limiter.Limit(),
+			}
+			if rated, ok := rateLimiter.(interface{ Rate() float64 }); ok {
+				result.Metadata.RateLimit = rated.Rate()
+			}
+
+			// Drop resources matching the exclusion patterns of this resource type
+			result.Resources, result.ExcludedResources = sm.exclusions[rt].Apply(result.Resources)
+			result.TotalResources = len(result.Resources)
+			if len(result.ExcludedResources) > 0 {
+				sm.logger.Info(fmt.Sprintf("Excluded %d %s resources matching exclusion patterns", len(result.ExcludedResources), scope))
+			}
+			sm.reportDiscovered(target, result)
+
 			mu.Lock()
-			sm.results[result.Region] = result
-			mu.Unlock()
-		}(resourceType, scanner)
+			defer mu.Unlock()
+			if sm.onResult == nil {
+				sm.resultsMu.Lock()
+				sm.results[key] = result
+				sm.resultsMu.Unlock()
+				return
+			}
+			if handlerErr == nil {
+				if handlerErr = sm.onResult(key, result); handlerErr != nil {
+					errChan <- fmt.Errorf("handling the results of %s failed: %w", scope, handlerErr)
+				}
+			}
+		}(key, target)
 	}
 
 	wg.Wait()
 	close(errChan)
 
+	// Inspectors return partial results once the context is done, report the cause instead
+	if ctx.Err() != nil {
+		return fmt.Errorf("scan cancelled: %w", context.Cause(ctx))
+	}
+
 	// Collect and return any errors
 	var errs []error
 	for err := range errChan {
@@ -109,15 +492,256 @@ func (sm *InspectorManager) Inspect(ctx context.Context) error {
 		return errors.Join(errs...)
 	}
 
+	if len(failures) > 0 {
+		return &ServiceFailuresError{Failed: failed, Total: len(sm.inspectors), errs: failures}
+	}
+
 	return nil
 }
 
-// GetResults returns the scanning results for all resource types
+// reportDiscovered emits the progress events of a completed inspector: the resources it
+// discovered in every region, then in total
+func (sm *InspectorManager) reportDiscovered(target inspectorTarget, result *InspectResult) {
+	if sm.progress == nil {
+		return
+	}
+
+	byRegion := make(map[string]int)
+	for _, resource := range result.Resources {
+		byRegion[resource.Region]++
+	}
+	regions := make([]string, 0, len(byRegion))
+	for region := range byRegion {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	for _, region := range regions {
+		sm.progress.Emit(o11y.ProgressEvent{
+			Event:     o11y.ProgressRegionDiscovered,
+			Service:   target.resourceType,
+			Account:   target.accountID,
+			Region:    region,
+			Resources: byRegion[region],
+		})
+	}
+	sm.progress.Emit(o11y.ProgressEvent{
+		Event:     o11y.ProgressServiceCompleted,
+		Service:   target.resourceType,
+		Account:   target.accountID,
+		Resources: result.TotalResources,
+	})
+}
+
+// Estimate measures the size of a scan without processing any resource: every inspector
+// only discovers its resources, with the cheap list and describe calls, and the estimates
+// are keyed by ResultKey like Results. The cache is not used, and inspectors that fail
+// are recorded in GetErrors and left out of the estimates.
+func (sm *InspectorManager) Estimate(ctx context.Context) (map[string]*ScanEstimate, error) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	estimates := make(map[string]*ScanEstimate, len(sm.inspectors))
+	sm.resultsMu.Lock()
+	sm.errors = []string{}
+	sm.resultsMu.Unlock()
+
+	for key, target := range sm.inspectors {
+		wg.Add(1)
+		go func(key string, target inspectorTarget) {
+			defer wg.Done()
+
+			scope := target.scope()
+			sm.logger.Info(fmt.Sprintf("Estimating resource type: %s", scope))
+
+			stats := &ScanStats{}
+			settings := sm.settingsOf(target.resourceType)
+			scanCtx := WithScanControls(ctx, ScanControls{
+				Limiter:       sm.limiter,
+				RateLimiter:   sm.rateLimiters[target.resourceType],
+				Stats:         stats,
+				BatchSize:     settings.BatchSize,
+				NumWorkers:    settings.NumWorkers,
+				DiscoveryOnly: true,
+			})
+
+			if _, err := target.inspector.Inspect(scanCtx, sm.config); err != nil {
+				errorMsg := fmt.Sprintf("Estimating %s failed: %v", scope, err)
+				sm.logger.Error(errorMsg)
+
+				sm.resultsMu.Lock()
+				sm.errors = append(sm.errors, errorMsg)
+				sm.resultsMu.Unlock()
+				return
+			}
+
+			estimate := &ScanEstimate{
+				ResourcesByRegion: stats.Discovered(),
+				DiscoveryAPICalls: stats.APICalls(),
+			}
+			for _, count := range estimate.ResourcesByRegion {
+				estimate.Resources += count
+			}
+			estimate.EstimatedAPICalls = estimate.DiscoveryAPICalls + int64(estimate.Resources)
+
+			mu.Lock()
+			estimates[key] = estimate
+			mu.Unlock()
+		}(key, target)
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("estimate cancelled: %w", context.Cause(ctx))
+	}
+
+	return estimates, nil
+}
+
+// GetResults returns the scanning results, keyed by ResultKey. It is empty when a result
+// handler receives them instead.
+//
+// Deprecated: use Results, whose snapshot also holds the errors and bounds of the scan.
 func (sm *InspectorManager) GetResults() map[string]*InspectResult {
-	return sm.results
+	return sm.Results().ByService
 }
 
 // GetErrors returns the list of error messages encountered during scanning
 func (sm *InspectorManager) GetErrors() []string {
-	return sm.errors
+	sm.resultsMu.RLock()
+	defer sm.resultsMu.RUnlock()
+	return slices.Clone(sm.errors)
+}
+
+// GetServiceErrors returns the failures of the last scan by resource type, account and
+// region
+func (sm *InspectorManager) GetServiceErrors() []ServiceError {
+	return sm.Results().Errors
+}
+
+// Throttles returns the number of attempts of the API calls of the run AWS throttled
+func (sm *InspectorManager) Throttles() int64 {
+	return sm.limiter.Throttles()
+}
+
+// EffectiveConcurrency returns the number of concurrent operations the run currently
+// allows, lower than its max_concurrency while it backs off from throttling
+func (sm *InspectorManager) EffectiveConcurrency() int {
+	return sm.limiter.Limit()
+}
+
+// Identities returns the ARN of the identity every inspector scans with, keyed like the
+// results: the role it assumes, or callerARN for the default credentials, left out when
+// empty. It is nil when no inspector assumes a role, every resource type being scanned with
+// the default credentials.
+func (sm *InspectorManager) Identities(callerARN string) map[string]string {
+	assumesRole := false
+	for _, target := range sm.inspectors {
+		assumesRole = assumesRole || target.roleARN != ""
+	}
+	if !assumesRole {
+		return nil
+	}
+
+	identities := make(map[string]string, len(sm.inspectors))
+	for key, target := range sm.inspectors {
+		switch {
+		case target.roleARN != "":
+			identities[key] = target.roleARN
+		case callerARN != "":
+			identities[key] = callerARN
+		}
+	}
+	return identities
+}
+
+// CallerIdentity returns the account and principal of the default credentials, which scan
+// every resource type apart from those of the configured accounts and those with their own
+// role
+func (sm *InspectorManager) CallerIdentity(ctx context.Context) (CallerIdentity, error) {
+	regions, err := GetEffectiveRegions(sm.config)
+	if err != nil {
+		return CallerIdentity{}, fmt.Errorf("failed to determine regions: %w", err)
+	}
+	return ResolveCallerIdentity(ctx, regions, sm.config.AWS.EndpointURL)
+}
+
+// cacheKeys returns the cache key of every inspector, keyed like the results. It is empty
+// when caching is disabled, and leaves out the inspectors whose account cannot be resolved,
+// which are then always scanned.
+func (sm *InspectorManager) cacheKeys(ctx context.Context) map[string]ScanCacheKey {
+	keys := make(map[string]ScanCacheKey)
+	if sm.cache == nil {
+		return keys
+	}
+
+	regions, err := GetEffectiveRegions(sm.config)
+	if err != nil {
+		sm.logger.Warn(fmt.Sprintf("Scan cache disabled, failed to determine regions: %v", err))
+		return keys
+	}
+
+	var callerAccountID string
+	var callerErr error
+	resolved := false
+
+	for key, target := range sm.inspectors {
+		accountID := target.accountID
+		if accountID == "" {
+			// The default credentials may point at another account than the cached one
+			if !resolved {
+				callerAccountID, callerErr = sm.callerAccountID(ctx, regions)
+				resolved = true
+				if callerErr != nil {
+					sm.logger.Warn(fmt.Sprintf("Scan cache disabled for the default credentials: %v", callerErr))
+				}
+			}
+			if callerErr != nil {
+				continue
+			}
+			accountID = callerAccountID
+		}
+
+		// Global services are scanned once whatever the regions, changing them keeps their cache
+		keyRegions := regions
+		if IsGlobalResourceType(target.resourceType) {
+			keyRegions = []string{constants.GlobalRegion}
+		}
+
+		keys[key] = ScanCacheKey{
+			AccountID:     accountID,
+			ResourceType:  target.resourceType,
+			Regions:       keyRegions,
+			BucketRegions: sm.config.Resources[target.resourceType].BucketRegions,
+		}
+	}
+
+	return keys
+}
+
+// loadCached returns the cached result of key when the inspector is cacheable and the
+// entry can be reused
+func (sm *InspectorManager) loadCached(key ScanCacheKey, cacheable bool) (*InspectResult, bool) {
+	if !cacheable {
+		return nil, false
+	}
+
+	result, ok := sm.cache.Load(key)
+	if !ok {
+		return nil, false
+	}
+
+	return result, true
+}
+
+// resolveCallerAccountID looks up the account of the default credentials through STS, at
+// the custom endpoint if any
+func resolveCallerAccountID(ctx context.Context, regions []string, endpointURL string) (string, error) {
+	clientManager, err := NewAWSRegionalClientManager(regions)
+	if err != nil {
+		return "", fmt.Errorf("failed to create AWS client manager: %w", err)
+	}
+	clientManager.SetEndpointURL(endpointURL)
+
+	return clientManager.GetAccountID(ctx)
 }
@@ -0,0 +1,358 @@
+package inspector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingInspector fails every scan, like an inspector whose role cannot be assumed
+type failingInspector struct {
+	err error
+}
+
+func (i *failingInspector) Inspect(ctx context.Context, config configuration.TaggyScanConfig) (*InspectResult, error) {
+	return nil, i.err
+}
+
+func (i *failingInspector) Fetch(ctx context.Context, arn string, config configuration.TaggyScanConfig) (*ResourceMetadata, error) {
+	return nil, i.err
+}
+
+func TestInspectorManagerKeepsScanningWhenAnAccountFails(t *testing.T) {
+	t.Parallel()
+
+	filter, err := NewExclusionFilter(nil)
+	require.NoError(t, err)
+
+	healthy := &countingInspector{result: func() *InspectResult { return cachedResult("bucket-a") }}
+	failing := &failingInspector{err: errors.New("operation error STS: AssumeRole, AccessDenied")}
+
+	manager := &InspectorManager{
+		inspectors: map[string]inspectorTarget{
+			ResultKey("111111111111", "s3"): {resourceType: "s3", accountID: "111111111111", inspector: healthy},
+			ResultKey("222222222222", "s3"): {resourceType: "s3", accountID: "222222222222", inspector: failing},
+		},
+		exclusions:   map[string]*ExclusionFilter{"s3": filter},
+		rateLimiters: map[string]RateLimiter{},
+		config: configuration.TaggyScanConfig{
+			AWS: configuration.AWSConfig{Regions: configuration.RegionsConfig{Mode: "specific", List: []string{"us-east-1"}}},
+		},
+		results: map[string]*InspectResult{},
+		logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	require.NoError(t, manager.Inspect(context.Background()))
+
+	results := manager.Results().ByService
+	require.Contains(t, results, ResultKey("111111111111", "s3"))
+	assert.Equal(t, 1, results[ResultKey("111111111111", "s3")].TotalResources)
+	assert.NotContains(t, results, ResultKey("222222222222", "s3"))
+
+	scanErrors := manager.GetErrors()
+	require.Len(t, scanErrors, 1)
+	assert.Contains(t, scanErrors[0], "222222222222")
+	assert.Contains(t, scanErrors[0], "AssumeRole")
+}
+
+func TestInspectorManagerResultsOfRoleScan(t *testing.T) {
+	t.Parallel()
+
+	filter, err := NewExclusionFilter(nil)
+	require.NoError(t, err)
+
+	config := configuration.TaggyScanConfig{
+		AWS: configuration.AWSConfig{Regions: configuration.RegionsConfig{Mode: "specific", List: []string{"us-east-1"}}},
+		Resources: map[string]configuration.ResourceConfig{
+			"s3": {Enabled: true, RoleARN: "arn:aws:iam::333333333333:role/log-reader"},
+		},
+	}
+	account, hasRole := config.Resources["s3"].RoleAccount()
+	require.True(t, hasRole)
+
+	// Keyed as NewInspectorManagerFromConfig keys the inspector of a resource type with a role
+	manager := &InspectorManager{
+		inspectors: map[string]inspectorTarget{
+			ResultKey(account.AccountID, "s3"): {
+				resourceType: "s3",
+				accountID:    account.AccountID,
+				roleARN:      account.RoleARN,
+				inspector:    &countingInspector{result: func() *InspectResult { return cachedResult("bucket-a") }},
+			},
+		},
+		exclusions:   map[string]*ExclusionFilter{"s3": filter},
+		rateLimiters: map[string]RateLimiter{},
+		config:       config,
+		results:      map[string]*InspectResult{},
+		logger:       o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+	require.NoError(t, manager.Inspect(context.Background()))
+
+	key := ServiceResultKey(config, "s3")
+	assert.Equal(t, "333333333333/s3", key)
+	results := manager.Results().ByService
+	require.Contains(t, results, key)
+	assert.Equal(t, 1, results[key].TotalResources)
+	assert.NotContains(t, results, ResultKey("", "s3"))
+
+	assert.Equal(t, "s3", ServiceResultKey(configuration.TaggyScanConfig{}, "s3"), "scans without role use the default credentials")
+}
+
+func TestInspectorManagerShareLimits(t *testing.T) {
+	t.Parallel()
+
+	primary := &InspectorManager{limiter: NewConcurrencyLimiter(2), budget: NewAPICallBudget(10)}
+	manager := &InspectorManager{limiter: NewConcurrencyLimiter(8)}
+	manager.ShareLimits(primary)
+
+	assert.Same(t, primary.limiter, manager.limiter)
+	assert.Same(t, primary.budget, manager.budget)
+}
+
+func TestInspectorManagerSetConcurrency(t *testing.T) {
+	t.Parallel()
+
+	filter, err := NewExclusionFilter(nil)
+	require.NoError(t, err)
+
+	logger := o11y.NewLogger(io.Discard, o11y.LogLevelError)
+	manager := &InspectorManager{
+		inspectors: map[string]inspectorTarget{
+			"s3": {resourceType: "s3", inspector: &countingInspector{result: func() *InspectResult { return cachedResult("bucket-a") }}},
+		},
+		exclusions:   map[string]*ExclusionFilter{"s3": filter},
+		limiter:      newAdaptiveLimiter(DefaultMaxConcurrency, logger),
+		rateLimiters: map[string]RateLimiter{},
+		settings: map[string]InspectorConfig{
+			"s3":  {BatchSize: 50, NumWorkers: 10},
+			"ec2": {BatchSize: 50, NumWorkers: 2},
+		},
+		results: map[string]*InspectResult{},
+		logger:  logger,
+	}
+
+	manager.SetConcurrency(0)
+	assert.Equal(t, DefaultMaxConcurrency, manager.limiter.Capacity(), "a zero concurrency keeps the configured settings")
+	assert.Equal(t, 10, manager.settingsOf("s3").NumWorkers)
+
+	manager.SetConcurrency(4)
+	assert.Equal(t, 4, manager.limiter.Capacity())
+	assert.Equal(t, 4, manager.settingsOf("s3").NumWorkers)
+	assert.Equal(t, 2, manager.settingsOf("ec2").NumWorkers, "workers below the concurrency are kept")
+
+	require.NoError(t, manager.Inspect(context.Background()))
+	metadata := manager.Results().ByService["s3"].Metadata
+	assert.Equal(t, 4, metadata.MaxConcurrency)
+	assert.Equal(t, 4, metadata.EffectiveConcurrency)
+	assert.Equal(t, 4, metadata.Workers)
+	assert.Zero(t, metadata.Throttles)
+	assert.Zero(t, manager.Throttles())
+	assert.Equal(t, 4, manager.EffectiveConcurrency())
+}
+
+func TestInspectorManagerReportsServiceErrors(t *testing.T) {
+	t.Parallel()
+
+	filter, err := NewExclusionFilter(nil)
+	require.NoError(t, err)
+
+	healthy := &countingInspector{result: func() *InspectResult {
+		result := cachedResult("bucket-a")
+		result.Errors = []string{"bucket bucket-b: failed to get bucket location: AccessDenied"}
+		return result
+	}}
+	failing := &failingInspector{err: fmt.Errorf("failed to scan RDS resources: %w", scanErrorList{
+		&RegionError{Region: "eu-west-1", Err: errors.New("AccessDenied")},
+		&RegionError{Region: "us-east-1", Err: errors.New("AccessDenied")},
+	})}
+
+	manager := &InspectorManager{
+		inspectors: map[string]inspectorTarget{
+			"s3":  {resourceType: "s3", inspector: healthy},
+			"rds": {resourceType: "rds", inspector: failing},
+		},
+		exclusions:   map[string]*ExclusionFilter{"s3": filter, "rds": filter},
+		rateLimiters: map[string]RateLimiter{},
+		results:      map[string]*InspectResult{},
+		logger:       o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	err = manager.Inspect(context.Background())
+	var failures *ServiceFailuresError
+	require.ErrorAs(t, err, &failures)
+	assert.False(t, failures.AllFailed())
+	assert.Contains(t, err.Error(), "Scanning rds failed")
+
+	assert.Contains(t, manager.Results().ByService, "s3")
+	assert.Equal(t, []ServiceError{
+		{Service: "rds", Region: "eu-west-1", Message: "AccessDenied"},
+		{Service: "rds", Region: "us-east-1", Message: "AccessDenied"},
+	}, manager.GetServiceErrors())
+
+	// The resources an inspector could not inspect are reported with the errors of the scan
+	assert.Contains(t, manager.GetErrors(), "Scanning s3: bucket bucket-b: failed to get bucket location: AccessDenied")
+}
+
+func TestInspectorManagerReportsProgress(t *testing.T) {
+	t.Parallel()
+
+	filter, err := NewExclusionFilter(nil)
+	require.NoError(t, err)
+
+	healthy := &countingInspector{result: func() *InspectResult {
+		return &InspectResult{TotalResources: 3, Resources: []ResourceMetadata{
+			{ID: "i-1", Type: "ec2", Region: "us-east-1"},
+			{ID: "i-2", Type: "ec2", Region: "eu-west-1"},
+			{ID: "i-3", Type: "ec2", Region: "us-east-1"},
+		}}
+	}}
+
+	var buf bytes.Buffer
+	manager := &InspectorManager{
+		inspectors: map[string]inspectorTarget{
+			"ec2": {resourceType: "ec2", inspector: healthy},
+			"rds": {resourceType: "rds", inspector: &failingInspector{err: errors.New("AccessDenied")}},
+		},
+		exclusions:   map[string]*ExclusionFilter{"ec2": filter},
+		rateLimiters: map[string]RateLimiter{},
+		results:      map[string]*InspectResult{},
+		logger:       o11y.NewLogger(io.Discard, o11y.LogLevelError),
+		progress:     o11y.NewProgressReporter(&buf),
+	}
+
+	_ = manager.Inspect(context.Background())
+
+	var ec2Events []o11y.ProgressEvent
+	var processed []int
+	var failed []string
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var event o11y.ProgressEvent
+		require.NoError(t, decoder.Decode(&event))
+		switch {
+		case event.Event == o11y.ProgressProcessed:
+			assert.Equal(t, 2, event.Total)
+			processed = append(processed, event.Processed)
+		case event.Event == o11y.ProgressServiceFailed:
+			failed = append(failed, event.Service+": "+event.Error)
+		case event.Service == "ec2":
+			ec2Events = append(ec2Events, event)
+		}
+	}
+
+	require.Len(t, ec2Events, 4)
+	assert.Equal(t, o11y.ProgressServiceStarted, ec2Events[0].Event)
+	assert.Equal(t, o11y.ProgressEvent{Event: o11y.ProgressRegionDiscovered, Service: "ec2", Region: "eu-west-1", Resources: 1}, withoutTime(ec2Events[1]))
+	assert.Equal(t, o11y.ProgressEvent{Event: o11y.ProgressRegionDiscovered, Service: "ec2", Region: "us-east-1", Resources: 2}, withoutTime(ec2Events[2]))
+	assert.Equal(t, o11y.ProgressEvent{Event: o11y.ProgressServiceCompleted, Service: "ec2", Resources: 3}, withoutTime(ec2Events[3]))
+
+	assert.Equal(t, []string{"rds: AccessDenied"}, failed)
+	assert.Equal(t, []int{1, 2}, processed)
+}
+
+// withoutTime clears the time of a progress event, so it can be compared
+func withoutTime(event o11y.ProgressEvent) o11y.ProgressEvent {
+	event.Time = time.Time{}
+	return event
+}
+
+func TestInspectorManagerIdentities(t *testing.T) {
+	t.Parallel()
+
+	manager := &InspectorManager{
+		inspectors: map[string]inspectorTarget{
+			"s3":  {resourceType: "s3"},
+			"ec2": {resourceType: "ec2"},
+		},
+	}
+	assert.Nil(t, manager.Identities("arn:aws:iam::111111111111:user/auditor"),
+		"scans with the default credentials only are described by the caller identity")
+
+	manager.inspectors[ResultKey("333333333333", "logs")] = inspectorTarget{
+		resourceType: "logs",
+		accountID:    "333333333333",
+		roleARN:      "arn:aws:iam::333333333333:role/aws-taggy-readonly",
+	}
+	assert.Equal(t, map[string]string{
+		"s3":                "arn:aws:iam::111111111111:user/auditor",
+		"ec2":               "arn:aws:iam::111111111111:user/auditor",
+		"333333333333/logs": "arn:aws:iam::333333333333:role/aws-taggy-readonly",
+	}, manager.Identities("arn:aws:iam::111111111111:user/auditor"))
+
+	assert.Equal(t, map[string]string{
+		"333333333333/logs": "arn:aws:iam::333333333333:role/aws-taggy-readonly",
+	}, manager.Identities(""), "the default credentials are left out when unresolved")
+}
+
+func TestInspectorManagerAllServicesFailed(t *testing.T) {
+	t.Parallel()
+
+	manager := &InspectorManager{
+		inspectors: map[string]inspectorTarget{
+			"s3": {resourceType: "s3", inspector: &failingInspector{err: errors.New("no credentials")}},
+		},
+		results: map[string]*InspectResult{},
+		logger:  o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	err := manager.Inspect(context.Background())
+	var failures *ServiceFailuresError
+	require.ErrorAs(t, err, &failures)
+	assert.True(t, failures.AllFailed())
+	assert.Equal(t, []ServiceError{{Service: "s3", Message: "no credentials"}}, manager.GetServiceErrors())
+}
+
+func TestInspectorManagerResultsWhileInspecting(t *testing.T) {
+	t.Parallel()
+
+	filter, err := NewExclusionFilter(nil)
+	require.NoError(t, err)
+
+	inspectors := make(map[string]inspectorTarget)
+	for i := 0; i < 8; i++ {
+		key := fmt.Sprintf("service-%d", i)
+		healthy := &countingInspector{result: func() *InspectResult { return cachedResult(key + "-bucket") }}
+		inspectors[key] = inspectorTarget{resourceType: "s3", inspector: healthy}
+	}
+	manager := &InspectorManager{
+		inspectors:   inspectors,
+		exclusions:   map[string]*ExclusionFilter{"s3": filter},
+		rateLimiters: map[string]RateLimiter{},
+		results:      map[string]*InspectResult{},
+		logger:       o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	// Run with -race: snapshots are read, and modified, while the inspectors store results
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			for _, result := range manager.Results().ByService {
+				result.Resources[0].Tags["Owner"] = "changed"
+				result.TotalResources = 0
+			}
+		}
+	}()
+	require.NoError(t, manager.Inspect(context.Background()))
+	<-done
+
+	aggregate := manager.Results()
+	require.Len(t, aggregate.ByService, len(inspectors))
+	for key, result := range aggregate.ByService {
+		assert.Equal(t, 1, result.TotalResources, key)
+		assert.Equal(t, "platform", result.Resources[0].Tags["Owner"], key)
+	}
+	assert.Empty(t, aggregate.Errors)
+	assert.False(t, aggregate.StartedAt.IsZero())
+	assert.False(t, aggregate.FinishedAt.Before(aggregate.StartedAt))
+}
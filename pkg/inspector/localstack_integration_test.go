@@ -0,0 +1,119 @@
+//go:build integration
+
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The integration tests run against LocalStack or moto, started beforehand, e.g.:
+//
+//	docker run -d -p 4566:4566 localstack/localstack
+//	AWS_TAGGY_ENDPOINT_URL=http://localhost:4566 go test -tags integration ./pkg/inspector/...
+const integrationRegion = "us-east-1"
+
+// integrationConfig returns a configuration scanning the resource type at the endpoint of
+// AWS_TAGGY_ENDPOINT_URL, skipping the test when it is not set
+func integrationConfig(t *testing.T, resourceType string) configuration.TaggyScanConfig {
+	t.Helper()
+
+	endpointURL := os.Getenv(configuration.EndpointURLEnvVar)
+	if endpointURL == "" {
+		t.Skipf("%s is not set, skipping the LocalStack integration test", configuration.EndpointURLEnvVar)
+	}
+
+	return configuration.TaggyScanConfig{
+		AWS: configuration.AWSConfig{
+			Regions:     configuration.RegionsConfig{Mode: "specific", List: []string{integrationRegion}},
+			EndpointURL: endpointURL,
+		},
+		Resources: map[string]configuration.ResourceConfig{
+			resourceType: {Enabled: true},
+		},
+	}
+}
+
+// integrationClients returns a client manager sending its calls to the endpoint of cfg
+func integrationClients(t *testing.T, cfg configuration.TaggyScanConfig) *AWSClientManager {
+	t.Helper()
+
+	clientManager, err := NewAWSRegionalClientManager([]string{integrationRegion})
+	require.NoError(t, err)
+	clientManager.SetEndpointURL(cfg.AWS.EndpointURL)
+	return clientManager
+}
+
+// discoveredTags scans the resource type and returns the tags of the resource with the given ID
+func discoveredTags(t *testing.T, cfg configuration.TaggyScanConfig, resourceType, id string) map[string]string {
+	t.Helper()
+
+	scanner, err := New(resourceType, cfg)
+	require.NoError(t, err)
+
+	result, err := scanner.Inspect(context.Background(), cfg)
+	require.NoError(t, err)
+
+	for _, resource := range result.Resources {
+		if resource.ID == id || resource.Details.Name == id {
+			return resource.Tags
+		}
+	}
+	require.Failf(t, "resource not discovered", "%s %s is missing from %d discovered resources", resourceType, id, len(result.Resources))
+	return nil
+}
+
+func TestLocalStackS3TagsRoundTrip(t *testing.T) {
+	cfg := integrationConfig(t, constants.ResourceTypeS3)
+	client, err := integrationClients(t, cfg).GetS3Client(integrationRegion)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	bucket := fmt.Sprintf("aws-taggy-it-%d", time.Now().UnixNano())
+	tags := map[string]string{"Environment": "test", "Owner": "platform"}
+
+	_, err = client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String(bucket)})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = client.DeleteBucket(context.Background(), &s3.DeleteBucketInput{Bucket: aws.String(bucket)})
+	})
+
+	tagging := &s3types.Tagging{}
+	for key, value := range tags {
+		tagging.TagSet = append(tagging.TagSet, s3types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	_, err = client.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{Bucket: aws.String(bucket), Tagging: tagging})
+	require.NoError(t, err)
+
+	assert.Equal(t, tags, discoveredTags(t, cfg, constants.ResourceTypeS3, bucket))
+}
+
+func TestLocalStackSQSTagsRoundTrip(t *testing.T) {
+	cfg := integrationConfig(t, constants.ResourceTypeSQS)
+	client, err := integrationClients(t, cfg).GetSQSClient(integrationRegion)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	queue := fmt.Sprintf("aws-taggy-it-%d", time.Now().UnixNano())
+	tags := map[string]string{"Environment": "test", "Owner": "platform"}
+
+	created, err := client.CreateQueue(ctx, &sqs.CreateQueueInput{QueueName: aws.String(queue), Tags: tags})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = client.DeleteQueue(context.Background(), &sqs.DeleteQueueInput{QueueUrl: created.QueueUrl})
+	})
+
+	assert.Equal(t, tags, discoveredTags(t, cfg, constants.ResourceTypeSQS, queue))
+}
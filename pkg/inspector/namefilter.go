@@ -0,0 +1,81 @@
+package inspector
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// NameFilter restricts resources to the ones whose ID, name or ARN matches a regular
+// expression, and leaves out the ones whose ID, name or ARN matches another
+type NameFilter struct {
+	// Match keeps the resources with an ID, name or ARN matching it, every resource when nil
+	Match *regexp.Regexp
+
+	// Exclude drops the resources with an ID, name or ARN matching it, none when nil
+	Exclude *regexp.Regexp
+}
+
+// NewNameFilter compiles the match and exclude expressions of a name filter, an empty
+// expression setting no condition
+func NewNameFilter(match, exclude string) (NameFilter, error) {
+	var filter NameFilter
+	var err error
+
+	if match != "" {
+		if filter.Match, err = regexp.Compile(match); err != nil {
+			return NameFilter{}, fmt.Errorf("invalid match expression %q: %w", match, err)
+		}
+	}
+	if exclude != "" {
+		if filter.Exclude, err = regexp.Compile(exclude); err != nil {
+			return NameFilter{}, fmt.Errorf("invalid exclude expression %q: %w", exclude, err)
+		}
+	}
+
+	return filter, nil
+}
+
+// IsZero reports whether the filter keeps every resource
+func (f NameFilter) IsZero() bool {
+	return f.Match == nil && f.Exclude == nil
+}
+
+// Matches reports whether the resource passes the filter
+func (f NameFilter) Matches(resource ResourceMetadata) bool {
+	if f.Match != nil && !matchesIdentifier(f.Match, resource) {
+		return false
+	}
+	return f.Exclude == nil || !matchesIdentifier(f.Exclude, resource)
+}
+
+// Filters describes the conditions of the filter as they are written on the command line
+func (f NameFilter) Filters() []string {
+	var filters []string
+	if f.Match != nil {
+		filters = append(filters, fmt.Sprintf("match %s", f.Match))
+	}
+	if f.Exclude != nil {
+		filters = append(filters, fmt.Sprintf("exclude %s", f.Exclude))
+	}
+	return filters
+}
+
+// matchesIdentifier reports whether the ID, name or ARN of the resource matches the expression
+func matchesIdentifier(expression *regexp.Regexp, resource ResourceMetadata) bool {
+	for _, identifier := range []string{resource.ID, resource.Details.Name, resource.Details.ARN} {
+		if identifier != "" && expression.MatchString(identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByName keeps, in every inspection result, the resources passing the name filter.
+// Excluded resources are filtered alike, and results left without any resource are dropped.
+// With a zero filter, the results are returned as they are.
+func FilterByName(results map[string]*InspectResult, filter NameFilter) map[string]*InspectResult {
+	if filter.IsZero() {
+		return results
+	}
+	return filterResources(results, filter.Matches)
+}
@@ -0,0 +1,83 @@
+package inspector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNameFilter(t *testing.T) {
+	t.Parallel()
+
+	filter, err := NewNameFilter("", "")
+	require.NoError(t, err)
+	assert.True(t, filter.IsZero())
+	assert.Empty(t, filter.Filters())
+
+	filter, err = NewNameFilter("^prod-", "-tmp$")
+	require.NoError(t, err)
+	assert.False(t, filter.IsZero())
+	assert.Equal(t, []string{"match ^prod-", "exclude -tmp$"}, filter.Filters())
+
+	_, err = NewNameFilter("^prod-(", "")
+	assert.ErrorContains(t, err, "invalid match expression")
+
+	_, err = NewNameFilter("", "[")
+	assert.ErrorContains(t, err, "invalid exclude expression")
+}
+
+func TestFilterByName(t *testing.T) {
+	t.Parallel()
+
+	resource := func(id, name, arn string) ResourceMetadata {
+		metadata := ResourceMetadata{ID: id}
+		metadata.Details.Name = name
+		metadata.Details.ARN = arn
+		return metadata
+	}
+
+	results := map[string]*InspectResult{
+		"s3": {
+			Resources: []ResourceMetadata{
+				resource("prod-logs", "prod-logs", "arn:aws:s3:::prod-logs"),
+				resource("prod-logs-tmp", "prod-logs-tmp", "arn:aws:s3:::prod-logs-tmp"),
+				resource("staging-logs", "staging-logs", "arn:aws:s3:::staging-logs"),
+			},
+			TotalResources: 3,
+		},
+		"ec2": {
+			Resources: []ResourceMetadata{
+				resource("i-0abc", "prod-web", "arn:aws:ec2:eu-west-1:123456789012:instance/i-0abc"),
+				resource("i-0def", "batch", "arn:aws:ec2:eu-west-1:123456789012:instance/i-0def"),
+			},
+			TotalResources: 2,
+		},
+		"sqs": {
+			Resources:      []ResourceMetadata{resource("jobs", "jobs", "arn:aws:sqs:eu-west-1:123456789012:jobs")},
+			TotalResources: 1,
+		},
+	}
+
+	filter, err := NewNameFilter("^prod-", "-tmp$")
+	require.NoError(t, err)
+	filtered := FilterByName(results, filter)
+
+	require.Len(t, filtered, 2)
+	require.Len(t, filtered["s3"].Resources, 1)
+	assert.Equal(t, "prod-logs", filtered["s3"].Resources[0].ID)
+	assert.Equal(t, 1, filtered["s3"].TotalResources)
+
+	// The instance is matched through its name
+	require.Len(t, filtered["ec2"].Resources, 1)
+	assert.Equal(t, "i-0abc", filtered["ec2"].Resources[0].ID)
+
+	// The ARN is matched as well
+	filter, err = NewNameFilter("", `:instance/`)
+	require.NoError(t, err)
+	filtered = FilterByName(results, filter)
+	assert.NotContains(t, filtered, "ec2")
+	assert.Len(t, filtered["s3"].Resources, 3)
+
+	assert.Equal(t, results, FilterByName(results, NameFilter{}))
+}
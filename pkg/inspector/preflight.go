@@ -0,0 +1,186 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/aws/smithy-go"
+)
+
+// Prober is implemented by the inspectors able to check the permissions of a scan without
+// running it. No resource is modified by a probe.
+type Prober interface {
+	// Probe issues in a region the cheapest of the read calls the scan makes, then the tag
+	// read of the scan on at most one of the resources it returns. The error of a failed call
+	// is a *ProbeError naming the IAM action the call requires.
+	Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error
+}
+
+// ProbeError is the failure of a call of a probe, along with the IAM action the call requires
+type ProbeError struct {
+	// Action is the IAM action of the call, such as s3:GetBucketTagging
+	Action string
+	Err    error
+}
+
+func (e *ProbeError) Error() string {
+	return fmt.Sprintf("%s failed: %v", e.Action, e.Err)
+}
+
+func (e *ProbeError) Unwrap() error {
+	return e.Err
+}
+
+// probeMaxResults is the page size of the describe calls of the probes, the smallest most
+// EC2 describe calls accept
+const probeMaxResults = 5
+
+// probeCall returns the error of a call of a probe as a ProbeError naming the IAM action of
+// the call, nil when the call succeeded
+func probeCall(action string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ProbeError{Action: action, Err: err}
+}
+
+// ProbeStatus is the outcome of the probe of a resource type in a region
+type ProbeStatus string
+
+const (
+	// ProbeStatusOK reports that every call of the probe succeeded
+	ProbeStatusOK ProbeStatus = "ok"
+
+	// ProbeStatusAccessDenied reports that AWS refused a call for lack of permissions
+	ProbeStatusAccessDenied ProbeStatus = "access_denied"
+
+	// ProbeStatusError reports that a call failed for another reason, such as a region that
+	// is not enabled for the account
+	ProbeStatusError ProbeStatus = "error"
+
+	// ProbeStatusUnsupported reports that the inspector of the resource type cannot be probed,
+	// such as a custom inspector
+	ProbeStatusUnsupported ProbeStatus = "unsupported"
+)
+
+// ProbeResult is the outcome of the probe of a resource type in a region
+type ProbeResult struct {
+	Service   string      `json:"service" yaml:"service"`
+	AccountID string      `json:"account_id,omitempty" yaml:"account_id,omitempty"`
+	Region    string      `json:"region,omitempty" yaml:"region,omitempty"`
+	Status    ProbeStatus `json:"status" yaml:"status"`
+
+	// Action is the IAM action of the failed call, empty when the probe succeeded
+	Action string `json:"action,omitempty" yaml:"action,omitempty"`
+
+	// Message is the error of the failed call
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// accessDeniedCodes are the error codes AWS APIs refuse a call with for lack of permissions
+var accessDeniedCodes = map[string]bool{
+	"AccessDenied":          true,
+	"AccessDeniedException": true,
+	"AuthorizationError":    true,
+	"UnauthorizedOperation": true,
+}
+
+// isAccessDenied reports whether an AWS API refused a call for lack of permissions
+func isAccessDenied(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && accessDeniedCodes[apiErr.ErrorCode()]
+}
+
+// newProbeResult returns the result of the probe of a target in a region from its error
+func newProbeResult(target inspectorTarget, region string, err error) ProbeResult {
+	result := ProbeResult{
+		Service:   target.resourceType,
+		AccountID: target.accountID,
+		Region:    region,
+		Status:    ProbeStatusOK,
+	}
+	if err == nil {
+		return result
+	}
+
+	result.Status = ProbeStatusError
+	if isAccessDenied(err) {
+		result.Status = ProbeStatusAccessDenied
+	}
+	var probeErr *ProbeError
+	if errors.As(err, &probeErr) {
+		result.Action = probeErr.Action
+	}
+	result.Message = err.Error()
+	return result
+}
+
+// Preflight probes the permissions of a scan: the inspector of every resource type issues,
+// in each region it scans, its cheapest read call and the tag read of the scan on at most one
+// resource. Results are ordered by service, account and region, and every probe is reported
+// whether it succeeds or not. Inspectors that cannot be probed are reported as unsupported.
+func (sm *InspectorManager) Preflight(ctx context.Context) ([]ProbeResult, error) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var results []ProbeResult
+
+	for _, target := range sm.inspectors {
+		prober, ok := target.inspector.(Prober)
+		if !ok {
+			mu.Lock()
+			results = append(results, ProbeResult{
+				Service:   target.resourceType,
+				AccountID: target.accountID,
+				Status:    ProbeStatusUnsupported,
+				Message:   "the inspector of the resource type cannot be probed",
+			})
+			mu.Unlock()
+			continue
+		}
+
+		regions, err := inspectedRegions(target.resourceType, sm.config)
+		if err != nil {
+			wg.Wait()
+			return nil, err
+		}
+
+		for _, region := range regions {
+			wg.Add(1)
+			go func(target inspectorTarget, region string) {
+				defer wg.Done()
+
+				err := sm.limiter.Acquire(ctx)
+				if err == nil {
+					err = prober.Probe(ctx, region, sm.config)
+					sm.limiter.Release()
+				}
+
+				mu.Lock()
+				results = append(results, newProbeResult(target, region, err))
+				mu.Unlock()
+			}(target, region)
+		}
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("preflight cancelled: %w", context.Cause(ctx))
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.AccountID != b.AccountID {
+			return a.AccountID < b.AccountID
+		}
+		return a.Region < b.Region
+	})
+	return results, nil
+}
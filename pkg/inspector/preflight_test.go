@@ -0,0 +1,169 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// probingInspector is an inspector whose probe fails in the regions of errs
+type probingInspector struct {
+	failingInspector
+	errs map[string]error
+}
+
+func (i *probingInspector) Probe(ctx context.Context, region string, config configuration.TaggyScanConfig) error {
+	return i.errs[region]
+}
+
+// probeCalls records the IAM actions of the calls of a probe, AWS refusing the denied one
+type probeCalls struct {
+	denied string
+	made   []string
+}
+
+// call records a call of action, returning AccessDenied when action is the denied one
+func (c *probeCalls) call(action string) error {
+	c.made = append(c.made, action)
+	if action == c.denied {
+		return &smithy.GenericAPIError{Code: "AccessDenied", Message: "Access Denied"}
+	}
+	return nil
+}
+
+// assertProbeError checks the error of a probe: nil when no action was denied, otherwise a
+// ProbeError naming the denied action that preflight reports as access denied
+func assertProbeError(t *testing.T, err error, denied string) {
+	t.Helper()
+
+	if denied == "" {
+		require.NoError(t, err)
+		return
+	}
+	var probeErr *ProbeError
+	require.ErrorAs(t, err, &probeErr)
+	assert.Equal(t, denied, probeErr.Action)
+	assert.Equal(t, ProbeStatusAccessDenied, newProbeResult(inspectorTarget{}, "us-east-1", err).Status)
+}
+
+func TestNewProbeResult(t *testing.T) {
+	t.Parallel()
+
+	target := inspectorTarget{resourceType: "s3", accountID: "111111111111"}
+	denied := &smithy.GenericAPIError{Code: "AccessDenied", Message: "Access Denied"}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected ProbeResult
+	}{
+		{
+			name:     "Success",
+			expected: ProbeResult{Service: "s3", AccountID: "111111111111", Region: "us-east-1", Status: ProbeStatusOK},
+		},
+		{
+			name: "Access Denied",
+			err:  probeCall("s3:GetBucketTagging", fmt.Errorf("operation error S3: GetBucketTagging, %w", denied)),
+			expected: ProbeResult{
+				Service:   "s3",
+				AccountID: "111111111111",
+				Region:    "us-east-1",
+				Status:    ProbeStatusAccessDenied,
+				Action:    "s3:GetBucketTagging",
+				Message:   "s3:GetBucketTagging failed: operation error S3: GetBucketTagging, api error AccessDenied: Access Denied",
+			},
+		},
+		{
+			name: "Other Error",
+			err:  probeCall("s3:ListAllMyBuckets", &smithy.GenericAPIError{Code: "InvalidAccessKeyId", Message: "bad key"}),
+			expected: ProbeResult{
+				Service:   "s3",
+				AccountID: "111111111111",
+				Region:    "us-east-1",
+				Status:    ProbeStatusError,
+				Action:    "s3:ListAllMyBuckets",
+				Message:   "s3:ListAllMyBuckets failed: api error InvalidAccessKeyId: bad key",
+			},
+		},
+		{
+			name: "Client Error",
+			err:  errors.New("failed to get S3 client: no credentials"),
+			expected: ProbeResult{
+				Service:   "s3",
+				AccountID: "111111111111",
+				Region:    "us-east-1",
+				Status:    ProbeStatusError,
+				Message:   "failed to get S3 client: no credentials",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, newProbeResult(target, "us-east-1", tt.err))
+		})
+	}
+}
+
+func TestInspectorManagerPreflight(t *testing.T) {
+	t.Parallel()
+
+	denied := probeCall("sqs:ListQueueTags", &smithy.GenericAPIError{Code: "AccessDenied", Message: "not authorized"})
+	manager := &InspectorManager{
+		inspectors: map[string]inspectorTarget{
+			"sqs":        {resourceType: "sqs", inspector: &probingInspector{errs: map[string]error{"eu-west-1": denied}}},
+			"cloudfront": {resourceType: "cloudfront", inspector: &probingInspector{}},
+			"custom":     {resourceType: "custom", inspector: &failingInspector{}},
+		},
+		config: configuration.TaggyScanConfig{
+			AWS: configuration.AWSConfig{Regions: configuration.RegionsConfig{Mode: "specific", List: []string{"us-east-1", "eu-west-1"}}},
+		},
+		logger: o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	results, err := manager.Preflight(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, results, 4)
+	assert.Equal(t, ProbeResult{Service: "cloudfront", Region: "us-east-1", Status: ProbeStatusOK}, results[0],
+		"global resource types are probed once")
+	assert.Equal(t, ProbeStatusUnsupported, results[1].Status)
+	assert.Equal(t, "custom", results[1].Service)
+	assert.Equal(t, ProbeResult{
+		Service: "sqs",
+		Region:  "eu-west-1",
+		Status:  ProbeStatusAccessDenied,
+		Action:  "sqs:ListQueueTags",
+		Message: denied.Error(),
+	}, results[2])
+	assert.Equal(t, ProbeResult{Service: "sqs", Region: "us-east-1", Status: ProbeStatusOK}, results[3])
+}
+
+func TestInspectorManagerPreflightCancelled(t *testing.T) {
+	t.Parallel()
+
+	manager := &InspectorManager{
+		inspectors: map[string]inspectorTarget{
+			"sqs": {resourceType: "sqs", inspector: &probingInspector{}},
+		},
+		config: configuration.TaggyScanConfig{
+			AWS: configuration.AWSConfig{Regions: configuration.RegionsConfig{Mode: "specific", List: []string{"us-east-1"}}},
+		},
+		logger: o11y.NewLogger(io.Discard, o11y.LogLevelError),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := manager.Preflight(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,193 @@
+package inspector
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+)
+
+// InspectorFactory creates the inspector of a resource type scanning the given regions
+type InspectorFactory func(regions []string) (Inspector, error)
+
+// registration is a resource type known to the registry
+type registration struct {
+	factory InspectorFactory
+
+	// newAWSInspector builds a built-in inspector on the AWS clients of the scan, which carry
+	// the credentials of the scanned account and the retry policy and endpoint of the
+	// configuration. It is nil for inspectors registered through RegisterInspector.
+	newAWSInspector func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector
+
+	// global marks the resource types of global services, scanned once per account through
+	// the endpoint of constants.GlobalServiceRegion whatever the configured regions
+	global bool
+}
+
+// registry holds the inspector of every supported resource type
+var registry = struct {
+	sync.RWMutex
+	inspectors map[string]registration
+}{inspectors: make(map[string]registration)}
+
+func init() {
+	registerAWSInspector(constants.ResourceTypeS3, factoryOf(NewS3Inspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &S3Inspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeEC2, factoryOf(NewEC2Inspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &EC2Inspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeVPC, factoryOf(NewVPCInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &VPCInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeCloudWatchLogs, factoryOf(NewCloudWatchLogsInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &CloudWatchLogsInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerGlobalAWSInspector(constants.ResourceTypeRoute53, factoryOf(NewRoute53Inspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &Route53Inspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeSNS, factoryOf(NewSNSInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &SNSInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeRDS, factoryOf(NewRDSInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &RDSInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeSQS, factoryOf(NewSQSInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &SQSInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeEBS, factoryOf(NewEBSInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &EBSInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerGlobalAWSInspector(constants.ResourceTypeCloudfront, factoryOf(NewCloudFrontInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &CloudFrontInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeAPIGateway, factoryOf(NewAPIGatewayInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &APIGatewayInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeElastiCache, factoryOf(NewElastiCacheInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &ElastiCacheInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeCloudWatchAlarms, factoryOf(NewCloudWatchAlarmsInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &CloudWatchAlarmsInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeSecurityGroup, factoryOf(NewSecurityGroupInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &SecurityGroupInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeNATGateway, factoryOf(NewNATGatewayInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &NATGatewayInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeInternetGateway, factoryOf(NewInternetGatewayInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &InternetGatewayInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+	registerAWSInspector(constants.ResourceTypeGeneric, factoryOf(NewGenericInspector),
+		func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector {
+			return &GenericInspector{Regions: regions, ClientManager: clientManager, Logger: logger}
+		})
+}
+
+// RegisterInspector makes a custom resource type, such as an internal CMDB, scannable by New
+// and NewInspectorManagerFromConfig, and accepted under resources in configuration files. It
+// is meant to be called from the init function of the package providing the inspector.
+//
+// The inspector implements Inspect, returning the resources of the type with their tags, and
+// Fetch, returning a single resource. Custom inspectors are created by the factory for the
+// regions of the configuration, whether or not it declares AWS accounts, and are expected to
+// authenticate on their own.
+//
+// RegisterInspector panics when the resource type is empty, already registered (built-in
+// types included) or the factory is nil.
+func RegisterInspector(resourceType string, factory func(regions []string) (Inspector, error)) {
+	if factory == nil {
+		panic(fmt.Sprintf("inspector: nil factory registered for resource type %q", resourceType))
+	}
+	register(resourceType, registration{factory: factory})
+}
+
+// RegisteredResourceTypes returns the resource types that have an inspector, sorted
+func RegisteredResourceTypes() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	resourceTypes := make([]string, 0, len(registry.inspectors))
+	for resourceType := range registry.inspectors {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	slices.Sort(resourceTypes)
+	return resourceTypes
+}
+
+// IsGlobalResourceType reports whether the resource type belongs to a global service, such as
+// CloudFront, whose resources are scanned once per account instead of once per region
+func IsGlobalResourceType(resourceType string) bool {
+	entry, exists := lookupInspector(resourceType)
+	return exists && entry.global
+}
+
+// registerAWSInspector registers a built-in inspector
+func registerAWSInspector(resourceType string, factory InspectorFactory,
+	newAWSInspector func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector) {
+	register(resourceType, registration{factory: factory, newAWSInspector: newAWSInspector})
+}
+
+// registerGlobalAWSInspector registers the built-in inspector of a global service
+func registerGlobalAWSInspector(resourceType string, factory InspectorFactory,
+	newAWSInspector func(regions []string, clientManager *AWSClientManager, logger *o11y.Logger) Inspector) {
+	register(resourceType, registration{factory: factory, newAWSInspector: newAWSInspector, global: true})
+}
+
+// register records a resource type in the registry and in the resource types accepted by
+// the configuration validation
+func register(resourceType string, entry registration) {
+	if resourceType == "" {
+		panic("inspector: empty resource type registered")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, exists := registry.inspectors[resourceType]; exists {
+		panic(fmt.Sprintf("inspector: resource type %q registered twice", resourceType))
+	}
+	registry.inspectors[resourceType] = entry
+	configuration.RegisterResourceType(resourceType)
+}
+
+// lookupInspector returns the registration of a resource type
+func lookupInspector(resourceType string) (registration, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	entry, exists := registry.inspectors[resourceType]
+	return entry, exists
+}
+
+// factoryOf adapts the constructor of a built-in inspector to an InspectorFactory, so a
+// failed construction returns a nil Inspector rather than a typed nil pointer
+func factoryOf[T Inspector](newInspector func(regions []string) (T, error)) InspectorFactory {
+	return func(regions []string) (Inspector, error) {
+		inspector, err := newInspector(regions)
+		if err != nil {
+			return nil, err
+		}
+		return inspector, nil
+	}
+}
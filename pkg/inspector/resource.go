@@ -1,6 +1,8 @@
 package inspector
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/Excoriate/aws-taggy/pkg/constants"
@@ -57,6 +59,23 @@ type ResourceMetadata struct {
 	Tags         map[string]string `json:"tags"`          // Key-value pairs of resource tags
 	DiscoveredAt time.Time         `json:"discovered_at"` // Timestamp when the resource was discovered
 
+	// CreatedAt is the creation time of the resource, such as the launch time of an EC2
+	// instance, zero when the inspector cannot tell it
+	CreatedAt time.Time `json:"created_at"`
+
+	// TagFetchError is the error reading the tags of the resource, whose Tags are then empty
+	// without meaning the resource carries no tags
+	TagFetchError string `json:"tag_fetch_error,omitempty"`
+
+	// ChangeMarker is a provider-supplied indicator of the last change of the resource, such
+	// as the last modified time of a CloudFront distribution, empty when the inspector has no
+	// cheap one. Incremental scans reuse the recorded tags of resources whose marker is unchanged.
+	ChangeMarker string `json:"change_marker,omitempty"`
+
+	// FromSnapshot reports that the tags were taken from the previous run of an incremental
+	// scan rather than read from AWS
+	FromSnapshot bool `json:"from_snapshot,omitempty"`
+
 	// Extended information about the resource
 	Details struct {
 		ARN        string                 `json:"arn,omitempty"`        // Amazon Resource Name or equivalent
@@ -74,7 +93,53 @@ type ResourceMetadata struct {
 
 	// RawResponse stores the complete, unmodified API response
 	// This can be useful for debugging or additional custom processing
-	RawResponse interface{} `json:"raw_response,omitempty"`
+	// It is left out of the serialized metadata unless IncludeRaw is set
+	RawResponse interface{} `json:"-"`
+
+	// IncludeRaw serializes RawResponse, as returned by RawResponseMap, under raw_response
+	IncludeRaw bool `json:"-"`
+}
+
+// MarshalJSON serializes the metadata, with the raw API response when IncludeRaw is set
+func (r ResourceMetadata) MarshalJSON() ([]byte, error) {
+	type metadata ResourceMetadata
+	if !r.IncludeRaw {
+		return json.Marshal(metadata(r))
+	}
+
+	return json.Marshal(struct {
+		metadata
+		RawResponse map[string]interface{} `json:"raw_response,omitempty"`
+	}{metadata(r), r.RawResponseMap()})
+}
+
+// RawResponseMap returns the raw API response as a map, nil without one. The AWS SDK exposes
+// no serializer for the shapes it returns, so the response goes through encoding/json, which
+// keeps the exported fields only and gives a stable output for every SDK type. A response
+// that cannot be serialized, such as one holding a document type, is summarized by its type
+// and the error instead.
+func (r ResourceMetadata) RawResponseMap() map[string]interface{} {
+	if r.RawResponse == nil {
+		return nil
+	}
+
+	var raw interface{}
+	data, err := json.Marshal(r.RawResponse)
+	if err == nil {
+		err = json.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return map[string]interface{}{
+			"type":  fmt.Sprintf("%T", r.RawResponse),
+			"error": err.Error(),
+		}
+	}
+
+	// Responses that are not objects, such as lists, are kept under a single key
+	if fields, ok := raw.(map[string]interface{}); ok {
+		return fields
+	}
+	return map[string]interface{}{"value": raw}
 }
 
 // BaseResource is a fundamental implementation of the Resource interface that provides
@@ -205,3 +270,18 @@ func NewResourceType(resourceType string) Resource {
 		Tags: make(map[string]string),
 	}
 }
+
+// tagFetchError returns the message of an error reading the tags of a resource, empty when
+// they were read
+func tagFetchError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// IsOrphaned tells whether the resource is flagged as used by nothing, see OrphanedProperty
+func (r ResourceMetadata) IsOrphaned() bool {
+	orphaned, _ := r.Details.Properties[OrphanedProperty].(bool)
+	return orphaned
+}
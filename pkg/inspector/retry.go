@@ -0,0 +1,111 @@
+package inspector
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+const (
+	// DefaultRetryMaxAttempts is the number of attempts per AWS API call, including the first one
+	DefaultRetryMaxAttempts = 5
+
+	// DefaultRetryBaseDelay is the delay before the first retry of a failed AWS API call
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+
+	// DefaultRetryMaxDelay caps the delay between two attempts of an AWS API call
+	DefaultRetryMaxDelay = 20 * time.Second
+)
+
+// RetryPolicy describes how AWS API calls failing with transient errors are retried.
+//
+// Only errors the AWS SDK classifies as transient are retried: throttling codes such as
+// Throttling, ThrottlingException or RequestLimitExceeded, 5xx responses, timeouts and
+// connection errors. Authorization and validation errors fail on the first attempt.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per call, including the first one
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry, doubled on each following attempt
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between two attempts
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when the configuration does not set aws.retries
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultRetryMaxAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    DefaultRetryMaxDelay,
+	}
+}
+
+// NewRetryPolicy builds a retry policy from the aws.retries configuration block,
+// falling back to the defaults for every unset field.
+func NewRetryPolicy(cfg *configuration.RetryConfig) (RetryPolicy, error) {
+	policy := DefaultRetryPolicy()
+	if cfg == nil {
+		return policy, nil
+	}
+
+	if cfg.MaxAttempts > 0 {
+		policy.MaxAttempts = cfg.MaxAttempts
+	}
+
+	if cfg.BaseDelay != "" {
+		delay, err := time.ParseDuration(cfg.BaseDelay)
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("invalid retries base_delay %q: %w", cfg.BaseDelay, err)
+		}
+		policy.BaseDelay = delay
+	}
+
+	if cfg.MaxDelay != "" {
+		delay, err := time.ParseDuration(cfg.MaxDelay)
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("invalid retries max_delay %q: %w", cfg.MaxDelay, err)
+		}
+		policy.MaxDelay = delay
+	}
+
+	return policy, nil
+}
+
+// newRetryer returns an AWS SDK retryer factory applying the policy
+func (p RetryPolicy) newRetryer() func() aws.Retryer {
+	return func() aws.Retryer {
+		return retry.NewStandard(func(o *retry.StandardOptions) {
+			o.MaxAttempts = p.MaxAttempts
+			o.MaxBackoff = p.MaxDelay
+			o.Backoff = p
+
+			// A scan issues a large number of calls against the same endpoints; the SDK retry
+			// quota would otherwise stop retrying throttled calls once exhausted.
+			o.RateLimiter = ratelimit.None
+		})
+	}
+}
+
+// BackoffDelay returns the delay before the given retry attempt: an exponential backoff
+// starting at BaseDelay, capped at MaxDelay, with full jitter to spread concurrent retries.
+func (p RetryPolicy) BackoffDelay(attempt int, err error) (time.Duration, error) {
+	delay := p.MaxDelay
+	if attempt > 0 && attempt < 32 {
+		if backoff := p.BaseDelay << (attempt - 1); backoff > 0 && backoff < p.MaxDelay {
+			delay = backoff
+		}
+	}
+
+	if delay <= 0 {
+		return 0, nil
+	}
+
+	return time.Duration(rand.Int63n(int64(delay)) + 1), nil
+}
@@ -0,0 +1,99 @@
+package inspector
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		config   *configuration.RetryConfig
+		expected RetryPolicy
+		wantErr  bool
+	}{
+		{
+			name:     "Defaults When Not Configured",
+			config:   nil,
+			expected: DefaultRetryPolicy(),
+		},
+		{
+			name:   "Overrides Configured Fields",
+			config: &configuration.RetryConfig{MaxAttempts: 8, BaseDelay: "250ms"},
+			expected: RetryPolicy{
+				MaxAttempts: 8,
+				BaseDelay:   250 * time.Millisecond,
+				MaxDelay:    DefaultRetryMaxDelay,
+			},
+		},
+		{
+			name:    "Invalid Max Delay",
+			config:  &configuration.RetryConfig{MaxDelay: "later"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := NewRetryPolicy(tc.config)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, policy)
+		})
+	}
+}
+
+func TestRetryPolicyBackoffDelay(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay, err := policy.BackoffDelay(attempt, nil)
+		require.NoError(t, err)
+
+		ceiling := policy.MaxDelay
+		if exponential := policy.BaseDelay << (attempt - 1); exponential < ceiling {
+			ceiling = exponential
+		}
+		assert.Greater(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, ceiling, "attempt %d", attempt)
+	}
+}
+
+func TestRetryPolicyRetryableErrors(t *testing.T) {
+	t.Parallel()
+
+	retryer := DefaultRetryPolicy().newRetryer()()
+
+	testCases := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{name: "Throttling", err: &smithy.GenericAPIError{Code: "Throttling"}, retryable: true},
+		{name: "Request Limit Exceeded", err: &smithy.GenericAPIError{Code: "RequestLimitExceeded"}, retryable: true},
+		{name: "Access Denied", err: &smithy.GenericAPIError{Code: "AccessDenied"}, retryable: false},
+		{name: "Validation Error", err: &smithy.GenericAPIError{Code: "ValidationError"}, retryable: false},
+		{name: "Plain Error", err: errors.New("boom"), retryable: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.retryable, retryer.IsErrorRetryable(tc.err))
+		})
+	}
+
+	assert.Equal(t, DefaultRetryMaxAttempts, retryer.MaxAttempts())
+}
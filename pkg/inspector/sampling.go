@@ -0,0 +1,97 @@
+package inspector
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Sample selects the resources a sampled scan processes among the ones each inspector
+// discovers. Discovery still enumerates every resource, only the reading and validation of
+// tags is reduced to the sample.
+type Sample struct {
+	// Percent is the share of the discovered resources sampled, in (0, 100], used when
+	// Count is zero
+	Percent float64
+
+	// Count is the number of resources sampled, or every resource when fewer are discovered
+	Count int
+
+	// Seed seeds the random selection: the same seed samples the same resources out of the
+	// same discovery
+	Seed int64
+}
+
+// SampleInfo records how the resources of an inspection result were sampled
+type SampleInfo struct {
+	// Discovered is the number of resources discovered, Sampled the number processed
+	Discovered int `json:"discovered"`
+	Sampled    int `json:"sampled"`
+}
+
+// ParseSample parses the size of a sample, a share of the discovered resources such as 10%
+// or a number of resources such as 500
+func ParseSample(value string, seed int64) (Sample, error) {
+	value = strings.TrimSpace(value)
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		parsed, err := strconv.ParseFloat(percent, 64)
+		if err != nil || parsed <= 0 || parsed > 100 {
+			return Sample{}, fmt.Errorf("invalid sample %q, a share must be greater than 0%% and at most 100%%", value)
+		}
+		return Sample{Percent: parsed, Seed: seed}, nil
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count <= 0 {
+		return Sample{}, fmt.Errorf("invalid sample %q, expected a share such as 10%% or a positive number of resources such as 500", value)
+	}
+	return Sample{Count: count, Seed: seed}, nil
+}
+
+// IsZero reports whether the sample is unset, processing every discovered resource
+func (s Sample) IsZero() bool {
+	return s.Percent == 0 && s.Count == 0
+}
+
+// String returns the size of the sample as parsed by ParseSample
+func (s Sample) String() string {
+	if s.Count > 0 {
+		return strconv.Itoa(s.Count)
+	}
+	return strconv.FormatFloat(s.Percent, 'f', -1, 64) + "%"
+}
+
+// size returns the number of resources sampled out of the discovered ones, at least one
+// when any is discovered
+func (s Sample) size(discovered int) int {
+	if s.Count > 0 {
+		return min(s.Count, discovered)
+	}
+	return min(int(math.Ceil(float64(discovered)*s.Percent/100)), discovered)
+}
+
+// sampleResources samples the resources discovered in every region, listed in region order,
+// across the regions. The sampled resources of every region keep their discovery order.
+func sampleResources[T any](sample Sample, discovered [][]T) [][]T {
+	total := 0
+	for _, resources := range discovered {
+		total += len(resources)
+	}
+
+	picked := rand.New(rand.NewSource(sample.Seed)).Perm(total)[:sample.size(total)]
+	slices.Sort(picked)
+
+	sampled := make([][]T, len(discovered))
+	offset := 0
+	for i, resources := range discovered {
+		for len(picked) > 0 && picked[0] < offset+len(resources) {
+			sampled[i] = append(sampled[i], resources[picked[0]-offset])
+			picked = picked[1:]
+		}
+		offset += len(resources)
+	}
+	return sampled
+}
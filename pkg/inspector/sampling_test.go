@@ -0,0 +1,93 @@
+package inspector
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSample(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    string
+		expected Sample
+		errMsg   string
+	}{
+		{value: "10%", expected: Sample{Percent: 10, Seed: 7}},
+		{value: "0.5%", expected: Sample{Percent: 0.5, Seed: 7}},
+		{value: "100%", expected: Sample{Percent: 100, Seed: 7}},
+		{value: "500", expected: Sample{Count: 500, Seed: 7}},
+		{value: "0%", errMsg: "a share must be greater than 0% and at most 100%"},
+		{value: "150%", errMsg: "a share must be greater than 0% and at most 100%"},
+		{value: "ten%", errMsg: "a share must be greater than 0% and at most 100%"},
+		{value: "0", errMsg: "expected a share such as 10% or a positive number of resources"},
+		{value: "-5", errMsg: "expected a share such as 10% or a positive number of resources"},
+		{value: "half", errMsg: "expected a share such as 10% or a positive number of resources"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			sample, err := ParseSample(tt.value, 7)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sample)
+			assert.Equal(t, tt.value, sample.String())
+			assert.False(t, sample.IsZero())
+		})
+	}
+
+	assert.True(t, Sample{Seed: 7}.IsZero())
+}
+
+func TestSample_Size(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 100, Sample{Percent: 10}.size(1000))
+	assert.Equal(t, 1, Sample{Percent: 10}.size(3), "a share samples at least one resource")
+	assert.Equal(t, 0, Sample{Percent: 10}.size(0))
+	assert.Equal(t, 500, Sample{Count: 500}.size(2000))
+	assert.Equal(t, 20, Sample{Count: 500}.size(20))
+}
+
+func TestSampleResources(t *testing.T) {
+	t.Parallel()
+
+	discovered := make([][]string, 3)
+	for i, region := range []string{"us-east-1", "eu-west-1", "ap-south-1"} {
+		for j := range 40 * (i + 1) {
+			discovered[i] = append(discovered[i], fmt.Sprintf("%s-%03d", region, j))
+		}
+	}
+
+	sample := Sample{Percent: 25, Seed: 42}
+	sampled := sampleResources(sample, discovered)
+	require.Len(t, sampled, 3)
+
+	total := 0
+	for i, resources := range sampled {
+		total += len(resources)
+		assert.IsIncreasing(t, resources, "sampled resources keep their discovery order")
+		for _, resource := range resources {
+			assert.Contains(t, discovered[i], resource)
+		}
+	}
+	assert.Equal(t, 60, total)
+
+	assert.Equal(t, sampled, sampleResources(sample, discovered), "the same seed samples the same resources")
+	assert.NotEqual(t, sampled, sampleResources(Sample{Percent: 25, Seed: 43}, discovered))
+
+	all := sampleResources(Sample{Count: 1000}, discovered)
+	assert.Equal(t, discovered, all)
+
+	assert.Equal(t, [][]string{nil, nil}, sampleResources(sample, [][]string{nil, nil}))
+}
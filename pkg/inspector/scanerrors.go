@@ -0,0 +1,124 @@
+package inspector
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RegionError is the failure of the resource discovery of an inspector in a region
+type RegionError struct {
+	Region string
+	Err    error
+}
+
+func (e *RegionError) Error() string {
+	return fmt.Sprintf("failed to discover resources in region %s: %v", e.Region, e.Err)
+}
+
+func (e *RegionError) Unwrap() error {
+	return e.Err
+}
+
+// scanErrorList aggregates the errors of the regions and resources of an inspector
+type scanErrorList []error
+
+func (l scanErrorList) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "scanning encountered %d errors:\n", len(l))
+	for i, err := range l {
+		fmt.Fprintf(&b, "  %d. %v\n", i+1, err)
+	}
+	return b.String()
+}
+
+func (l scanErrorList) Unwrap() []error {
+	return l
+}
+
+// ServiceError is the failure of a resource type in a region, or in every region when
+// Region is empty, reported next to the results of the services that could be scanned
+type ServiceError struct {
+	Service   string `json:"service" yaml:"service"`
+	AccountID string `json:"account_id,omitempty" yaml:"account_id,omitempty"`
+	Region    string `json:"region,omitempty" yaml:"region,omitempty"`
+	Message   string `json:"message" yaml:"message"`
+}
+
+// newServiceErrors splits the error of an inspector into an error per failed region, or
+// returns a single error without region when the failure is not tied to one
+func newServiceErrors(target inspectorTarget, err error) []ServiceError {
+	var serviceErrors []ServiceError
+	for _, regionErr := range regionErrors(err) {
+		serviceErrors = append(serviceErrors, ServiceError{
+			Service:   target.resourceType,
+			AccountID: target.accountID,
+			Region:    regionErr.Region,
+			Message:   regionErr.Err.Error(),
+		})
+	}
+	if len(serviceErrors) == 0 {
+		serviceErrors = append(serviceErrors, ServiceError{
+			Service:   target.resourceType,
+			AccountID: target.accountID,
+			Message:   err.Error(),
+		})
+	}
+	return serviceErrors
+}
+
+// regionErrors returns every RegionError in the tree of err
+func regionErrors(err error) []*RegionError {
+	switch e := err.(type) {
+	case *RegionError:
+		return []*RegionError{e}
+	case interface{ Unwrap() []error }:
+		var found []*RegionError
+		for _, inner := range e.Unwrap() {
+			found = append(found, regionErrors(inner)...)
+		}
+		return found
+	case interface{ Unwrap() error }:
+		return regionErrors(e.Unwrap())
+	}
+	return nil
+}
+
+// sortServiceErrors orders the errors by service, account and region
+func sortServiceErrors(serviceErrors []ServiceError) {
+	sort.SliceStable(serviceErrors, func(i, j int) bool {
+		a, b := serviceErrors[i], serviceErrors[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.AccountID != b.AccountID {
+			return a.AccountID < b.AccountID
+		}
+		return a.Region < b.Region
+	})
+}
+
+// ServiceFailuresError is returned by InspectorManager.Inspect when resource types of the
+// default credentials could not be scanned. The results of the other resource types are
+// available, callers tolerating partial scans use them along with GetServiceErrors.
+type ServiceFailuresError struct {
+	// Failed and Total count the failed inspectors and every inspector of the scan
+	Failed int
+	Total  int
+
+	errs []error
+}
+
+func (e *ServiceFailuresError) Error() string {
+	return errors.Join(e.errs...).Error()
+}
+
+func (e *ServiceFailuresError) Unwrap() []error {
+	return e.errs
+}
+
+// AllFailed reports that no inspector of the scan succeeded
+func (e *ServiceFailuresError) AllFailed() bool {
+	return e.Failed >= e.Total
+}
@@ -0,0 +1,134 @@
+package inspector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TagSelectorOperator is the comparison a TagSelector applies to the tag it selects
+type TagSelectorOperator string
+
+const (
+	// TagSelectorExists selects resources carrying the tag, whatever its value
+	TagSelectorExists TagSelectorOperator = "exists"
+
+	// TagSelectorEquals selects resources whose tag has the given value
+	TagSelectorEquals TagSelectorOperator = "="
+
+	// TagSelectorNotEquals selects resources whose tag is missing or has another value
+	TagSelectorNotEquals TagSelectorOperator = "!="
+)
+
+// TagSelector restricts resources to the ones whose existing tags match a condition,
+// written as key, key=value or key!=value
+type TagSelector struct {
+	Key      string
+	Value    string
+	Operator TagSelectorOperator
+}
+
+// ParseTagSelector parses a selector written as key, key=value or key!=value
+func ParseTagSelector(selector string) (TagSelector, error) {
+	key, value, operator := selector, "", TagSelectorExists
+	if k, v, found := strings.Cut(selector, "!="); found {
+		key, value, operator = k, v, TagSelectorNotEquals
+	} else if k, v, found := strings.Cut(selector, "="); found {
+		key, value, operator = k, v, TagSelectorEquals
+	}
+
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return TagSelector{}, fmt.Errorf("invalid tag selector %q, expected key, key=value or key!=value", selector)
+	}
+
+	return TagSelector{Key: key, Value: value, Operator: operator}, nil
+}
+
+// ParseTagSelectors parses every selector, failing on the first invalid one
+func ParseTagSelectors(selectors []string) ([]TagSelector, error) {
+	parsed := make([]TagSelector, 0, len(selectors))
+	for _, selector := range selectors {
+		tagSelector, err := ParseTagSelector(selector)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, tagSelector)
+	}
+	return parsed, nil
+}
+
+// Matches reports whether the tags satisfy the selector
+func (s TagSelector) Matches(tags map[string]string) bool {
+	value, exists := tags[s.Key]
+	switch s.Operator {
+	case TagSelectorEquals:
+		return exists && value == s.Value
+	case TagSelectorNotEquals:
+		return !exists || value != s.Value
+	default:
+		return exists
+	}
+}
+
+// String returns the selector as it is written on the command line
+func (s TagSelector) String() string {
+	if s.Operator == TagSelectorExists {
+		return s.Key
+	}
+	return s.Key + string(s.Operator) + s.Value
+}
+
+// MatchesTagSelectors reports whether the tags satisfy every selector
+func MatchesTagSelectors(tags map[string]string, selectors []TagSelector) bool {
+	for _, selector := range selectors {
+		if !selector.Matches(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterByTagSelectors keeps, in every inspection result, the resources whose tags satisfy
+// every selector. Excluded resources are filtered alike, and results left without any
+// resource are dropped. Without selectors, the results are returned as they are.
+func FilterByTagSelectors(results map[string]*InspectResult, selectors []TagSelector) map[string]*InspectResult {
+	if len(selectors) == 0 {
+		return results
+	}
+	return filterResources(results, func(resource ResourceMetadata) bool {
+		return MatchesTagSelectors(resource.Tags, selectors)
+	})
+}
+
+// filterResources keeps, in every inspection result, the resources and excluded resources
+// satisfying keep, dropping the results left without any resource
+func filterResources(results map[string]*InspectResult, keep func(ResourceMetadata) bool) map[string]*InspectResult {
+	filtered := make(map[string]*InspectResult, len(results))
+	for key, result := range results {
+		var resources []ResourceMetadata
+		for _, resource := range result.Resources {
+			if keep(resource) {
+				resources = append(resources, resource)
+			}
+		}
+
+		var excluded []ExcludedResource
+		for _, entry := range result.ExcludedResources {
+			if keep(entry.Resource) {
+				excluded = append(excluded, entry)
+			}
+		}
+
+		if len(resources) == 0 && len(excluded) == 0 {
+			continue
+		}
+
+		selected := *result
+		selected.Resources = resources
+		selected.TotalResources = len(resources)
+		selected.ExcludedResources = excluded
+		filtered[key] = &selected
+	}
+
+	return filtered
+}
@@ -0,0 +1,145 @@
+package inspector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTagSelector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected TagSelector
+		wantErr  bool
+	}{
+		{input: "Team=payments", expected: TagSelector{Key: "Team", Value: "payments", Operator: TagSelectorEquals}},
+		{input: "Environment!=prod", expected: TagSelector{Key: "Environment", Value: "prod", Operator: TagSelectorNotEquals}},
+		{input: "Owner", expected: TagSelector{Key: "Owner", Operator: TagSelectorExists}},
+		{input: "Query=a=b", expected: TagSelector{Key: "Query", Value: "a=b", Operator: TagSelectorEquals}},
+		{input: "Team=", expected: TagSelector{Key: "Team", Value: "", Operator: TagSelectorEquals}},
+		{input: "=payments", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			selector, err := ParseTagSelector(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, selector)
+			assert.Equal(t, tt.input, selector.String())
+		})
+	}
+}
+
+func TestFilterByTagSelectors(t *testing.T) {
+	t.Parallel()
+
+	resource := func(id string, tags map[string]string) ResourceMetadata {
+		return ResourceMetadata{ID: id, Tags: tags}
+	}
+
+	results := map[string]*InspectResult{
+		"s3": {
+			Resources: []ResourceMetadata{
+				resource("payments-prod", map[string]string{"Team": "payments", "Environment": "prod", "Owner": "alice"}),
+				resource("payments-dev", map[string]string{"Team": "payments", "Environment": "dev"}),
+				resource("payments-untagged", map[string]string{"Team": "payments"}),
+				resource("billing-dev", map[string]string{"Team": "billing", "Environment": "dev", "Owner": "bob"}),
+			},
+			TotalResources: 4,
+			ExcludedResources: []ExcludedResource{
+				{Resource: resource("payments-logs", map[string]string{"Team": "payments"}), Pattern: "logs"},
+				{Resource: resource("billing-logs", map[string]string{"Team": "billing"}), Pattern: "logs"},
+			},
+		},
+		"sqs": {
+			Resources:      []ResourceMetadata{resource("billing-queue", map[string]string{"Team": "billing"})},
+			TotalResources: 1,
+		},
+	}
+
+	ids := func(resources []ResourceMetadata) []string {
+		var ids []string
+		for _, r := range resources {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name             string
+		selectors        []string
+		expectedTypes    []string
+		expectedS3       []string
+		expectedExcluded int
+	}{
+		{
+			name:             "No Selectors",
+			expectedTypes:    []string{"s3", "sqs"},
+			expectedS3:       []string{"payments-prod", "payments-dev", "payments-untagged", "billing-dev"},
+			expectedExcluded: 2,
+		},
+		{
+			name:             "Equals",
+			selectors:        []string{"Team=payments"},
+			expectedTypes:    []string{"s3"},
+			expectedS3:       []string{"payments-prod", "payments-dev", "payments-untagged"},
+			expectedExcluded: 1,
+		},
+		{
+			name:             "Every Selector Must Match",
+			selectors:        []string{"Team=payments", "Owner"},
+			expectedTypes:    []string{"s3"},
+			expectedS3:       []string{"payments-prod"},
+			expectedExcluded: 0,
+		},
+		{
+			name:             "Negation Keeps Missing Tags",
+			selectors:        []string{"Team=payments", "Environment!=prod"},
+			expectedTypes:    []string{"s3"},
+			expectedS3:       []string{"payments-dev", "payments-untagged"},
+			expectedExcluded: 1,
+		},
+		{
+			name:      "Nothing Selected",
+			selectors: []string{"Team=marketing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			selectors, err := ParseTagSelectors(tt.selectors)
+			require.NoError(t, err)
+
+			filtered := FilterByTagSelectors(results, selectors)
+
+			var types []string
+			for resourceType := range filtered {
+				types = append(types, resourceType)
+			}
+			assert.ElementsMatch(t, tt.expectedTypes, types)
+
+			if s3, ok := filtered["s3"]; ok {
+				assert.Equal(t, tt.expectedS3, ids(s3.Resources))
+				assert.Equal(t, len(tt.expectedS3), s3.TotalResources)
+				assert.Len(t, s3.ExcludedResources, tt.expectedExcluded)
+			}
+		})
+	}
+
+	// The original results are left untouched
+	assert.Len(t, results["s3"].Resources, 4)
+	assert.Equal(t, 4, results["s3"].TotalResources)
+}
@@ -0,0 +1,338 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// TagChange lists the tags to add or update on a resource and the tag keys to remove from it
+type TagChange struct {
+	Set   map[string]string
+	Unset []string
+}
+
+// ParseTagChange parses the tags to set, written as key=value, and the tag keys to remove.
+// A key cannot be both set and removed.
+func ParseTagChange(set, unset []string) (TagChange, error) {
+	change := TagChange{Set: make(map[string]string, len(set))}
+	for _, assignment := range set {
+		key, value, found := strings.Cut(assignment, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return TagChange{}, fmt.Errorf("invalid tag %q, expected key=value", assignment)
+		}
+		change.Set[key] = value
+	}
+
+	for _, key := range unset {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return TagChange{}, fmt.Errorf("invalid empty tag key to remove")
+		}
+		if _, exists := change.Set[key]; exists {
+			return TagChange{}, fmt.Errorf("tag %s cannot be both set and removed", key)
+		}
+		change.Unset = append(change.Unset, key)
+	}
+
+	return change, nil
+}
+
+// IsEmpty tells whether the change neither sets nor removes any tag
+func (c TagChange) IsEmpty() bool {
+	return len(c.Set) == 0 && len(c.Unset) == 0
+}
+
+// Apply returns the tags resulting from applying the change to tags, leaving tags untouched
+func (c TagChange) Apply(tags map[string]string) map[string]string {
+	result := maps.Clone(tags)
+	if result == nil {
+		result = make(map[string]string)
+	}
+	for _, key := range c.Unset {
+		delete(result, key)
+	}
+	for key, value := range c.Set {
+		result[key] = value
+	}
+	return result
+}
+
+// TagWriter writes tags to a resource through the tagging API of its service
+type TagWriter interface {
+	WriteTags(ctx context.Context, resourceARN string, change TagChange) error
+}
+
+// SupportedTagWriters lists the resource types whose tags can be written
+var SupportedTagWriters = []string{
+	constants.ResourceTypeEC2,
+	constants.ResourceTypeRDS,
+	constants.ResourceTypeS3,
+	constants.ResourceTypeSQS,
+}
+
+// NewTagWriter creates the TagWriter of a resource type. Regions are those buckets are
+// located in, the resources of the other services being written in the region of their ARN.
+// A non-empty endpointURL sends the tagging calls to a custom endpoint, such as LocalStack.
+func NewTagWriter(resourceType string, regions []string, endpointURL string) (TagWriter, error) {
+	if !slices.Contains(SupportedTagWriters, resourceType) {
+		return nil, fmt.Errorf("writing tags of %s resources is not supported, supported resource types are: %s",
+			resourceType, strings.Join(SupportedTagWriters, ", "))
+	}
+
+	clientManager, err := NewAWSRegionalClientManager(regions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client manager: %w", err)
+	}
+	clientManager.SetEndpointURL(endpointURL)
+
+	switch resourceType {
+	case constants.ResourceTypeS3:
+		return &s3TagWriter{regions: regions, clientFor: func(region string) (S3TaggingAPI, error) {
+			client, err := clientManager.GetS3Client(region)
+			if err != nil {
+				return nil, err
+			}
+			return client, nil
+		}}, nil
+	case constants.ResourceTypeEC2:
+		return &ec2TagWriter{clientFor: func(region string) (EC2TaggingAPI, error) {
+			client, err := clientManager.GetEC2Client(region)
+			if err != nil {
+				return nil, err
+			}
+			return client, nil
+		}}, nil
+	case constants.ResourceTypeSQS:
+		return &sqsTagWriter{clientFor: func(region string) (SQSTaggingAPI, error) {
+			client, err := clientManager.GetSQSClient(region)
+			if err != nil {
+				return nil, err
+			}
+			return client, nil
+		}}, nil
+	default:
+		return &rdsTagWriter{clientFor: func(region string) (RDSTaggingAPI, error) {
+			client, err := clientManager.GetRDSClient(region)
+			if err != nil {
+				return nil, err
+			}
+			return client, nil
+		}}, nil
+	}
+}
+
+// S3TaggingAPI is the subset of the S3 client used to write bucket tags
+type S3TaggingAPI interface {
+	S3API
+	PutBucketTagging(ctx context.Context, params *s3.PutBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error)
+	DeleteBucketTagging(ctx context.Context, params *s3.DeleteBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.DeleteBucketTaggingOutput, error)
+}
+
+// s3TagWriter replaces the whole tag set of a bucket, as S3 has no call updating single tags
+type s3TagWriter struct {
+	regions   []string
+	clientFor func(region string) (S3TaggingAPI, error)
+}
+
+func (w *s3TagWriter) WriteTags(ctx context.Context, resourceARN string, change TagChange) error {
+	bucketName, err := ParseS3ARN(resourceARN)
+	if err != nil {
+		return fmt.Errorf("failed to parse S3 ARN: %w", err)
+	}
+
+	bucketRegion, err := locateBucket(ctx, bucketName, w.regions, func(region string) (S3API, error) {
+		return w.clientFor(region)
+	})
+	if err != nil {
+		return err
+	}
+
+	client, err := w.clientFor(bucketRegion)
+	if err != nil {
+		return fmt.Errorf("failed to create S3 client for region %s: %w", bucketRegion, err)
+	}
+
+	// Read the tag set right before replacing it, so tags changed since they were shown survive
+	current := make(map[string]string)
+	output, err := client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: aws.String(bucketName)})
+	if err != nil && !strings.Contains(err.Error(), "NoSuchTagSet") {
+		return fmt.Errorf("failed to get tags of bucket %s: %w", bucketName, err)
+	}
+	if err == nil {
+		for _, tag := range output.TagSet {
+			current[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+
+	tags := change.Apply(current)
+
+	// S3 rejects empty tag sets, removing the last tags deletes the tag set instead
+	if len(tags) == 0 {
+		if _, err := client.DeleteBucketTagging(ctx, &s3.DeleteBucketTaggingInput{Bucket: aws.String(bucketName)}); err != nil {
+			return fmt.Errorf("failed to delete tags of bucket %s: %w", bucketName, err)
+		}
+		return nil
+	}
+
+	tagSet := make([]s3types.Tag, 0, len(tags))
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		tagSet = append(tagSet, s3types.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+
+	if _, err := client.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+		Bucket:  aws.String(bucketName),
+		Tagging: &s3types.Tagging{TagSet: tagSet},
+	}); err != nil {
+		return fmt.Errorf("failed to put tags of bucket %s: %w", bucketName, err)
+	}
+
+	return nil
+}
+
+// EC2TaggingAPI is the subset of the EC2 client used to write instance tags
+type EC2TaggingAPI interface {
+	CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error)
+	DeleteTags(ctx context.Context, params *ec2.DeleteTagsInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error)
+}
+
+type ec2TagWriter struct {
+	clientFor func(region string) (EC2TaggingAPI, error)
+}
+
+func (w *ec2TagWriter) WriteTags(ctx context.Context, resourceARN string, change TagChange) error {
+	instanceID, region, err := ParseEC2ARN(resourceARN)
+	if err != nil {
+		return fmt.Errorf("failed to parse EC2 ARN: %w", err)
+	}
+
+	client, err := w.clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to create EC2 client for region %s: %w", region, err)
+	}
+
+	if len(change.Unset) > 0 {
+		tags := make([]ec2types.Tag, 0, len(change.Unset))
+		for _, key := range change.Unset {
+			tags = append(tags, ec2types.Tag{Key: aws.String(key)})
+		}
+		if _, err := client.DeleteTags(ctx, &ec2.DeleteTagsInput{Resources: []string{instanceID}, Tags: tags}); err != nil {
+			return fmt.Errorf("failed to delete tags of instance %s: %w", instanceID, err)
+		}
+	}
+
+	if len(change.Set) > 0 {
+		tags := make([]ec2types.Tag, 0, len(change.Set))
+		for _, key := range slices.Sorted(maps.Keys(change.Set)) {
+			tags = append(tags, ec2types.Tag{Key: aws.String(key), Value: aws.String(change.Set[key])})
+		}
+		if _, err := client.CreateTags(ctx, &ec2.CreateTagsInput{Resources: []string{instanceID}, Tags: tags}); err != nil {
+			return fmt.Errorf("failed to create tags of instance %s: %w", instanceID, err)
+		}
+	}
+
+	return nil
+}
+
+// SQSTaggingAPI is the subset of the SQS client used to write queue tags
+type SQSTaggingAPI interface {
+	GetQueueUrl(ctx context.Context, params *sqs.GetQueueUrlInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error)
+	TagQueue(ctx context.Context, params *sqs.TagQueueInput, optFns ...func(*sqs.Options)) (*sqs.TagQueueOutput, error)
+	UntagQueue(ctx context.Context, params *sqs.UntagQueueInput, optFns ...func(*sqs.Options)) (*sqs.UntagQueueOutput, error)
+}
+
+type sqsTagWriter struct {
+	clientFor func(region string) (SQSTaggingAPI, error)
+}
+
+func (w *sqsTagWriter) WriteTags(ctx context.Context, resourceARN string, change TagChange) error {
+	queueName, region, err := ParseSQSARN(resourceARN)
+	if err != nil {
+		return fmt.Errorf("failed to parse SQS ARN: %w", err)
+	}
+
+	client, err := w.clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to create SQS client for region %s: %w", region, err)
+	}
+
+	// Queues are tagged through their URL
+	output, err := client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{QueueName: aws.String(queueName)})
+	if err != nil {
+		return fmt.Errorf("failed to get queue URL for queue %s: %w", queueName, err)
+	}
+
+	if len(change.Unset) > 0 {
+		if _, err := client.UntagQueue(ctx, &sqs.UntagQueueInput{QueueUrl: output.QueueUrl, TagKeys: change.Unset}); err != nil {
+			return fmt.Errorf("failed to untag queue %s: %w", queueName, err)
+		}
+	}
+
+	if len(change.Set) > 0 {
+		if _, err := client.TagQueue(ctx, &sqs.TagQueueInput{QueueUrl: output.QueueUrl, Tags: change.Set}); err != nil {
+			return fmt.Errorf("failed to tag queue %s: %w", queueName, err)
+		}
+	}
+
+	return nil
+}
+
+// RDSTaggingAPI is the subset of the RDS client used to write database instance tags
+type RDSTaggingAPI interface {
+	AddTagsToResource(ctx context.Context, params *rds.AddTagsToResourceInput, optFns ...func(*rds.Options)) (*rds.AddTagsToResourceOutput, error)
+	RemoveTagsFromResource(ctx context.Context, params *rds.RemoveTagsFromResourceInput, optFns ...func(*rds.Options)) (*rds.RemoveTagsFromResourceOutput, error)
+}
+
+type rdsTagWriter struct {
+	clientFor func(region string) (RDSTaggingAPI, error)
+}
+
+func (w *rdsTagWriter) WriteTags(ctx context.Context, resourceARN string, change TagChange) error {
+	_, region, err := ParseRDSARN(resourceARN)
+	if err != nil {
+		return fmt.Errorf("failed to parse RDS ARN: %w", err)
+	}
+
+	client, err := w.clientFor(region)
+	if err != nil {
+		return fmt.Errorf("failed to create RDS client for region %s: %w", region, err)
+	}
+
+	// RDS tags resources by their ARN
+	if len(change.Unset) > 0 {
+		if _, err := client.RemoveTagsFromResource(ctx, &rds.RemoveTagsFromResourceInput{
+			ResourceName: aws.String(resourceARN),
+			TagKeys:      change.Unset,
+		}); err != nil {
+			return fmt.Errorf("failed to remove tags of %s: %w", resourceARN, err)
+		}
+	}
+
+	if len(change.Set) > 0 {
+		tags := make([]rdstypes.Tag, 0, len(change.Set))
+		for _, key := range slices.Sorted(maps.Keys(change.Set)) {
+			tags = append(tags, rdstypes.Tag{Key: aws.String(key), Value: aws.String(change.Set[key])})
+		}
+		if _, err := client.AddTagsToResource(ctx, &rds.AddTagsToResourceInput{
+			ResourceName: aws.String(resourceARN),
+			Tags:         tags,
+		}); err != nil {
+			return fmt.Errorf("failed to add tags to %s: %w", resourceARN, err)
+		}
+	}
+
+	return nil
+}
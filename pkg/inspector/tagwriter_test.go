@@ -0,0 +1,234 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockS3TaggingClient holds the tag set of a single bucket, nil when it has none
+type mockS3TaggingClient struct {
+	mockS3Client
+
+	tags    map[string]string
+	deleted bool
+}
+
+func (m *mockS3TaggingClient) GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+	if m.tags == nil {
+		return nil, fmt.Errorf("api error NoSuchTagSet: The TagSet does not exist")
+	}
+	output := &s3.GetBucketTaggingOutput{}
+	for key, value := range m.tags {
+		output.TagSet = append(output.TagSet, s3types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return output, nil
+}
+
+func (m *mockS3TaggingClient) PutBucketTagging(ctx context.Context, params *s3.PutBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+	m.tags = make(map[string]string)
+	for _, tag := range params.Tagging.TagSet {
+		m.tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return &s3.PutBucketTaggingOutput{}, nil
+}
+
+func (m *mockS3TaggingClient) DeleteBucketTagging(ctx context.Context, params *s3.DeleteBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.DeleteBucketTaggingOutput, error) {
+	m.tags = nil
+	m.deleted = true
+	return &s3.DeleteBucketTaggingOutput{}, nil
+}
+
+func TestS3TagWriterReplacesTheWholeTagSet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		tags        map[string]string
+		change      TagChange
+		expected    map[string]string
+		wantDeleted bool
+	}{
+		{
+			name:     "Keeps Untouched Tags",
+			tags:     map[string]string{"Owner": "platform", "Environment": "dev", "Temp": "yes"},
+			change:   TagChange{Set: map[string]string{"Environment": "prod"}, Unset: []string{"Temp"}},
+			expected: map[string]string{"Owner": "platform", "Environment": "prod"},
+		},
+		{
+			name:     "Bucket Without Tags",
+			change:   TagChange{Set: map[string]string{"Owner": "platform"}},
+			expected: map[string]string{"Owner": "platform"},
+		},
+		{
+			name:        "Removing The Last Tag Deletes The Tag Set",
+			tags:        map[string]string{"Temp": "yes"},
+			change:      TagChange{Unset: []string{"Temp"}},
+			wantDeleted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockS3TaggingClient{
+				mockS3Client: mockS3Client{headRegions: map[string]string{"logs": "eu-west-1"}},
+				tags:         tt.tags,
+			}
+			writer := &s3TagWriter{
+				regions:   []string{"eu-west-1"},
+				clientFor: func(region string) (S3TaggingAPI, error) { return client, nil },
+			}
+
+			require.NoError(t, writer.WriteTags(context.Background(), "arn:aws:s3:::logs", tt.change))
+			assert.Equal(t, tt.expected, client.tags)
+			assert.Equal(t, tt.wantDeleted, client.deleted)
+		})
+	}
+}
+
+// mockTaggingCalls records the tagging calls of the EC2, SQS and RDS writers
+type mockTaggingCalls struct {
+	region string
+	set    map[string]string
+	unset  []string
+	target string
+}
+
+func (m *mockTaggingCalls) CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+	m.target = params.Resources[0]
+	m.set = make(map[string]string)
+	for _, tag := range params.Tags {
+		m.set[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+func (m *mockTaggingCalls) DeleteTags(ctx context.Context, params *ec2.DeleteTagsInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error) {
+	for _, tag := range params.Tags {
+		m.unset = append(m.unset, aws.ToString(tag.Key))
+	}
+	return &ec2.DeleteTagsOutput{}, nil
+}
+
+func (m *mockTaggingCalls) GetQueueUrl(ctx context.Context, params *sqs.GetQueueUrlInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error) {
+	return &sqs.GetQueueUrlOutput{QueueUrl: aws.String("https://sqs." + m.region + ".amazonaws.com/123456789012/" + aws.ToString(params.QueueName))}, nil
+}
+
+func (m *mockTaggingCalls) TagQueue(ctx context.Context, params *sqs.TagQueueInput, optFns ...func(*sqs.Options)) (*sqs.TagQueueOutput, error) {
+	m.target = aws.ToString(params.QueueUrl)
+	m.set = params.Tags
+	return &sqs.TagQueueOutput{}, nil
+}
+
+func (m *mockTaggingCalls) UntagQueue(ctx context.Context, params *sqs.UntagQueueInput, optFns ...func(*sqs.Options)) (*sqs.UntagQueueOutput, error) {
+	m.unset = params.TagKeys
+	return &sqs.UntagQueueOutput{}, nil
+}
+
+func (m *mockTaggingCalls) AddTagsToResource(ctx context.Context, params *rds.AddTagsToResourceInput, optFns ...func(*rds.Options)) (*rds.AddTagsToResourceOutput, error) {
+	m.target = aws.ToString(params.ResourceName)
+	m.set = make(map[string]string)
+	for _, tag := range params.Tags {
+		m.set[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return &rds.AddTagsToResourceOutput{}, nil
+}
+
+func (m *mockTaggingCalls) RemoveTagsFromResource(ctx context.Context, params *rds.RemoveTagsFromResourceInput, optFns ...func(*rds.Options)) (*rds.RemoveTagsFromResourceOutput, error) {
+	m.unset = params.TagKeys
+	return &rds.RemoveTagsFromResourceOutput{}, nil
+}
+
+func TestTagWritersUseTheRegionOfTheARN(t *testing.T) {
+	t.Parallel()
+
+	change := TagChange{Set: map[string]string{"Owner": "platform"}, Unset: []string{"Temp"}}
+
+	tests := []struct {
+		name           string
+		arn            string
+		writer         func(clientFor func(region string) *mockTaggingCalls) TagWriter
+		expectedTarget string
+	}{
+		{
+			name: "EC2 Instance",
+			arn:  "arn:aws:ec2:eu-west-1:123456789012:instance/i-0abc",
+			writer: func(clientFor func(region string) *mockTaggingCalls) TagWriter {
+				return &ec2TagWriter{clientFor: func(region string) (EC2TaggingAPI, error) { return clientFor(region), nil }}
+			},
+			expectedTarget: "i-0abc",
+		},
+		{
+			name: "SQS Queue",
+			arn:  "arn:aws:sqs:eu-west-1:123456789012:orders",
+			writer: func(clientFor func(region string) *mockTaggingCalls) TagWriter {
+				return &sqsTagWriter{clientFor: func(region string) (SQSTaggingAPI, error) { return clientFor(region), nil }}
+			},
+			expectedTarget: "https://sqs.eu-west-1.amazonaws.com/123456789012/orders",
+		},
+		{
+			name: "RDS Instance",
+			arn:  "arn:aws:rds:eu-west-1:123456789012:db:orders",
+			writer: func(clientFor func(region string) *mockTaggingCalls) TagWriter {
+				return &rdsTagWriter{clientFor: func(region string) (RDSTaggingAPI, error) { return clientFor(region), nil }}
+			},
+			expectedTarget: "arn:aws:rds:eu-west-1:123456789012:db:orders",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls *mockTaggingCalls
+			writer := tt.writer(func(region string) *mockTaggingCalls {
+				calls = &mockTaggingCalls{region: region}
+				return calls
+			})
+
+			require.NoError(t, writer.WriteTags(context.Background(), tt.arn, change))
+			assert.Equal(t, "eu-west-1", calls.region)
+			assert.Equal(t, tt.expectedTarget, calls.target)
+			assert.Equal(t, change.Set, calls.set)
+			assert.Equal(t, change.Unset, calls.unset)
+		})
+	}
+}
+
+func TestNewTagWriterRejectsUnsupportedResourceTypes(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewTagWriter("route53", []string{"us-east-1"}, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "supported resource types are: ec2, rds, s3, sqs")
+}
+
+func TestParseTagChange(t *testing.T) {
+	t.Parallel()
+
+	change, err := ParseTagChange([]string{"Owner=platform", "Note=a=b", "Empty="}, []string{"Temp"})
+	require.NoError(t, err)
+	assert.Equal(t, TagChange{
+		Set:   map[string]string{"Owner": "platform", "Note": "a=b", "Empty": ""},
+		Unset: []string{"Temp"},
+	}, change)
+	assert.Equal(t, map[string]string{"Owner": "platform", "Note": "a=b", "Empty": "", "Team": "core"},
+		change.Apply(map[string]string{"Owner": "data", "Temp": "yes", "Team": "core"}))
+
+	_, err = ParseTagChange([]string{"Owner"}, nil)
+	assert.ErrorContains(t, err, "expected key=value")
+
+	_, err = ParseTagChange([]string{"Owner=platform"}, []string{"Owner"})
+	assert.ErrorContains(t, err, "cannot be both set and removed")
+}
@@ -2,19 +2,23 @@
 
 import (
 	"fmt"
-	"regexp"
+	"slices"
+	"strings"
 
+	"github.com/Excoriate/aws-taggy/pkg/arn"
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
 	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/Excoriate/aws-taggy/pkg/util"
 )
 
 // GetEffectiveRegions returns the list of regions to scan based on the configuration mode
 func GetEffectiveRegions(cfg configuration.TaggyScanConfig) ([]string, error) {
-	// If mode is 'all', return all valid AWS regions
+	// If mode is 'all', return all valid AWS regions of the commercial partition, GovCloud and
+	// China regions being scanned when listed explicitly
 	if cfg.AWS.Regions.Mode == "all" {
 		regions := make([]string, 0, len(configuration.SupportedAWSRegions))
 		for region, supported := range configuration.SupportedAWSRegions {
-			if supported {
+			if supported && configuration.PartitionOfRegion(region) == constants.PartitionAWS {
 				regions = append(regions, region)
 			}
 		}
@@ -59,28 +63,105 @@ func ExtractRegionFromARNOrDefault(arn string) string {
 	return extractedRegion
 }
 
+// QueryRegions returns the regions to query a single resource from, in order of preference:
+// the given region, else the region of the ARN, else the AWS_REGION and AWS_DEFAULT_REGION
+// environment variables. The default region of the partition of the ARN closes the list, as
+// S3 ARNs carry no region.
+func QueryRegions(resourceARN, region string) []string {
+	var regions []string
+	add := func(candidate string) {
+		if candidate != "" && !slices.Contains(regions, candidate) {
+			regions = append(regions, candidate)
+		}
+	}
+
+	add(region)
+	if len(regions) == 0 {
+		if arnRegion, err := ExtractRegionFromARN(resourceARN); err == nil {
+			add(arnRegion)
+		}
+	}
+	if len(regions) == 0 {
+		if envRegion, err := util.GetAWSRegionEnvVar(); err == nil {
+			add(envRegion)
+		}
+		if envRegion, err := util.GetAWSRegionDefaultEnvVar(); err == nil {
+			add(envRegion)
+		}
+	}
+	partition := constants.PartitionAWS
+	if parsed, err := arn.Parse(resourceARN); err == nil {
+		partition = parsed.Partition
+	}
+	add(configuration.DefaultPartitionRegion(partition))
+
+	return regions
+}
+
 // ExtractRegionFromARN attempts to extract the region from a given AWS ARN
 // It returns an error if the ARN is invalid or the region cannot be extracted
-func ExtractRegionFromARN(arn string) (string, error) {
-	if arn == "" {
+func ExtractRegionFromARN(resourceARN string) (string, error) {
+	if resourceARN == "" {
 		return "", fmt.Errorf("empty ARN provided")
 	}
 
-	// AWS ARN format: arn:aws:service:region:account-id:resource-type/resource-id
-	// Regex pattern to extract region from ARN
-	regionRegex := regexp.MustCompile(`arn:aws:[^:]+:([^:]+):`)
-	matches := regionRegex.FindStringSubmatch(arn)
+	// AWS ARN format: arn:partition:service:region:account-id:resource-type/resource-id, in
+	// any partition (aws, aws-us-gov, aws-cn)
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return "", fmt.Errorf("unable to extract region from ARN: %w", err)
+	}
+	if parsed.Region == "" {
+		return "", fmt.Errorf("unable to extract region from ARN: %s", resourceARN)
+	}
 
-	if len(matches) < 2 {
-		return "", fmt.Errorf("unable to extract region from ARN: %s", arn)
+	// Validate extracted region against supported regions
+	if supported, exists := configuration.SupportedAWSRegions[parsed.Region]; exists && supported {
+		return parsed.Region, nil
 	}
 
-	extractedRegion := matches[1]
+	return "", fmt.Errorf("unsupported region extracted from ARN: %s", resourceARN)
+}
 
-	// Validate extracted region against supported regions
-	if supported, exists := configuration.SupportedAWSRegions[extractedRegion]; exists && supported {
-		return extractedRegion, nil
+// resourceARN builds the ARN of a resource in the partition of the region it lives in, so
+// resources of GovCloud (US) and China regions get aws-us-gov and aws-cn ARNs. The region is
+// left out of the ARN when arnRegion is false, as for S3 buckets and Route 53 hosted zones.
+func resourceARN(service, region string, arnRegion bool, accountID, resource string) string {
+	built := arn.ARN{
+		Partition: configuration.PartitionOfRegion(region),
+		Service:   service,
+		AccountID: accountID,
+		Resource:  resource,
+	}
+	if arnRegion {
+		built.Region = region
+	}
+	return built.String()
+}
+
+// ResourceTypeFromARN infers the resource type of the inspector fetching the resource of an
+// ARN (arn:partition:service:region:account:resource). Services hosting several resource
+// types, such as ec2, are told apart by the resource part of the ARN.
+// It returns an error listing the supported resource types when the ARN designates a
+// resource no dedicated inspector handles.
+func ResourceTypeFromARN(resourceARN string) (string, error) {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return "", fmt.Errorf("invalid ARN %s: %w", resourceARN, err)
+	}
+
+	if resourceType := DedicatedResourceType(resourceARN); resourceType != "" {
+		return resourceType, nil
+	}
+
+	var supported []string
+	for resourceType, enabled := range configuration.SupportedAWSResources {
+		if enabled {
+			supported = append(supported, resourceType)
+		}
 	}
+	slices.Sort(supported)
 
-	return "", fmt.Errorf("unsupported region extracted from ARN: %s", arn)
+	return "", fmt.Errorf("cannot infer the resource type of %s resources from ARN %s, supported resource types are: %s",
+		parsed.Service, resourceARN, strings.Join(supported, ", "))
 }
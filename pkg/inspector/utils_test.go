@@ -0,0 +1,314 @@
+package inspector
+
+import (
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceTypeFromARN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		arn      string
+		expected string
+		errMsg   string
+	}{
+		{
+			name:     "S3 Bucket Without Region Or Account",
+			arn:      "arn:aws:s3:::my-bucket",
+			expected: constants.ResourceTypeS3,
+		},
+		{
+			name:     "EC2 Instance",
+			arn:      "arn:aws:ec2:us-east-1:123456789012:instance/i-0abcd1234efgh5678",
+			expected: constants.ResourceTypeEC2,
+		},
+		{
+			name:     "VPC",
+			arn:      "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0abcd1234",
+			expected: constants.ResourceTypeVPC,
+		},
+		{
+			name:     "SQS Queue",
+			arn:      "arn:aws:sqs:eu-west-1:123456789012:orders",
+			expected: constants.ResourceTypeSQS,
+		},
+		{
+			name:     "CloudWatch Logs Group",
+			arn:      "arn:aws:logs:us-west-2:123456789012:log-group:/aws/lambda/orders:*",
+			expected: constants.ResourceTypeCloudWatchLogs,
+		},
+		{
+			name:     "EBS Volume",
+			arn:      "arn:aws:ec2:us-east-1:123456789012:volume/vol-0abcd1234",
+			expected: constants.ResourceTypeEBS,
+		},
+		{
+			name:   "Unsupported EC2 Resource",
+			arn:    "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-0abcd1234",
+			errMsg: "cannot infer the resource type of ec2 resources",
+		},
+		{
+			name:   "Unsupported Service",
+			arn:    "arn:aws:kinesis:us-east-1:123456789012:stream/orders",
+			errMsg: "supported resource types are: cloudwatchlogs, ebs, ec2, generic",
+		},
+		{
+			name:   "Invalid ARN",
+			arn:    "my-bucket",
+			errMsg: "invalid ARN my-bucket",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resourceType, err := ResourceTypeFromARN(tt.arn)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resourceType)
+		})
+	}
+}
+
+func TestQueryRegions(t *testing.T) {
+	tests := []struct {
+		name     string
+		arn      string
+		region   string
+		env      map[string]string
+		expected []string
+	}{
+		{
+			name:     "Region Flag Wins",
+			arn:      "arn:aws:sqs:eu-west-1:123456789012:orders",
+			region:   "eu-south-1",
+			env:      map[string]string{"AWS_REGION": "us-west-2"},
+			expected: []string{"eu-south-1", "us-east-1"},
+		},
+		{
+			name:     "Region Of The ARN",
+			arn:      "arn:aws:sqs:eu-west-1:123456789012:orders",
+			env:      map[string]string{"AWS_REGION": "us-west-2"},
+			expected: []string{"eu-west-1", "us-east-1"},
+		},
+		{
+			name:     "Environment For ARNs Without Region",
+			arn:      "arn:aws:s3:::my-bucket",
+			env:      map[string]string{"AWS_REGION": "us-west-2", "AWS_DEFAULT_REGION": "eu-central-1"},
+			expected: []string{"us-west-2", "eu-central-1", "us-east-1"},
+		},
+		{
+			name:     "Default Region",
+			arn:      "arn:aws:s3:::my-bucket",
+			expected: []string{"us-east-1"},
+		},
+		{
+			name:     "Region Of A GovCloud ARN",
+			arn:      "arn:aws-us-gov:sqs:us-gov-east-1:123456789012:orders",
+			expected: []string{"us-gov-east-1", "us-gov-west-1"},
+		},
+		{
+			name:     "Default Region Of The China Partition",
+			arn:      "arn:aws-cn:s3:::my-bucket",
+			expected: []string{"cn-north-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_REGION", tt.env["AWS_REGION"])
+			t.Setenv("AWS_DEFAULT_REGION", tt.env["AWS_DEFAULT_REGION"])
+
+			assert.Equal(t, tt.expected, QueryRegions(tt.arn, tt.region))
+		})
+	}
+}
+
+func TestARNPartitions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		region    string
+		partition string
+	}{
+		{region: "eu-west-1", partition: "aws"},
+		{region: "us-gov-west-1", partition: "aws-us-gov"},
+		{region: "cn-north-1", partition: "aws-cn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.partition, func(t *testing.T) {
+			t.Parallel()
+
+			// Log groups
+			logGroup := logGroupARN(tt.region, "123456789012", "/app/web")
+			assert.Equal(t, "arn:"+tt.partition+":logs:"+tt.region+":123456789012:log-group:/app/web", logGroup)
+			name, region, err := ParseCloudWatchLogsARN(logGroup + ":*")
+			require.NoError(t, err)
+			assert.Equal(t, "/app/web", name)
+			assert.Equal(t, tt.region, region)
+
+			// EC2 instances
+			instance := resourceARN("ec2", tt.region, true, "123456789012", "instance/i-0abc")
+			assert.Equal(t, "arn:"+tt.partition+":ec2:"+tt.region+":123456789012:instance/i-0abc", instance)
+			instanceID, region, err := ParseEC2ARN(instance)
+			require.NoError(t, err)
+			assert.Equal(t, "i-0abc", instanceID)
+			assert.Equal(t, tt.region, region)
+			resourceType, err := ResourceTypeFromARN(instance)
+			require.NoError(t, err)
+			assert.Equal(t, constants.ResourceTypeEC2, resourceType)
+
+			extracted, err := ExtractRegionFromARN(instance)
+			require.NoError(t, err)
+			assert.Equal(t, tt.region, extracted)
+
+			// S3 buckets carry no region in their ARN, only the partition of it
+			bucket := resourceARN("s3", tt.region, false, "", "my-bucket")
+			assert.Equal(t, "arn:"+tt.partition+":s3:::my-bucket", bucket)
+			bucketName, err := ParseS3ARN(bucket)
+			require.NoError(t, err)
+			assert.Equal(t, "my-bucket", bucketName)
+			resourceType, err = ResourceTypeFromARN(bucket)
+			require.NoError(t, err)
+			assert.Equal(t, constants.ResourceTypeS3, resourceType)
+		})
+	}
+}
+
+func TestParseARNRejections(t *testing.T) {
+	t.Parallel()
+
+	parseS3 := func(resourceARN string) error { _, err := ParseS3ARN(resourceARN); return err }
+	parseLogs := func(resourceARN string) error { _, _, err := ParseCloudWatchLogsARN(resourceARN); return err }
+	parseSNS := func(resourceARN string) error { _, _, err := ParseSNSARN(resourceARN); return err }
+	parseSQS := func(resourceARN string) error { _, _, err := ParseSQSARN(resourceARN); return err }
+
+	tests := []struct {
+		name    string
+		parse   func(string) error
+		arn     string
+		wantErr bool
+	}{
+		{name: "s3 bucket", parse: parseS3, arn: "arn:aws:s3:::my-bucket"},
+		{name: "s3 bucket with colon", parse: parseS3, arn: "arn:aws:s3:::my:bucket", wantErr: true},
+		{name: "s3 object", parse: parseS3, arn: "arn:aws:s3:::my-bucket/logs/app.log", wantErr: true},
+		{name: "s3 access point", parse: parseS3, arn: "arn:aws:s3:us-east-1:123456789012:accesspoint/reports", wantErr: true},
+		{name: "log group", parse: parseLogs, arn: "arn:aws:logs:us-east-1:123456789012:log-group:/app/web"},
+		{name: "log group with wildcard", parse: parseLogs, arn: "arn:aws:logs:us-east-1:123456789012:log-group:/app/web:*"},
+		{name: "log group with colon", parse: parseLogs, arn: "arn:aws:logs:us-east-1:123456789012:log-group:/app:web", wantErr: true},
+		{name: "log group with colon and wildcard", parse: parseLogs, arn: "arn:aws:logs:us-east-1:123456789012:log-group:/app:web:*", wantErr: true},
+		{name: "log stream", parse: parseLogs, arn: "arn:aws:logs:us-east-1:123456789012:log-group:/app/web:log-stream:i-0abc", wantErr: true},
+		{name: "sns topic", parse: parseSNS, arn: "arn:aws:sns:eu-west-1:123456789012:alerts"},
+		{name: "sns subscription", parse: parseSNS, arn: "arn:aws:sns:eu-west-1:123456789012:alerts:0b7b5e1c-7a1e-4c3b-9f2a-3d4e5f6a7b8c", wantErr: true},
+		{name: "sqs queue", parse: parseSQS, arn: "arn:aws:sqs:eu-west-1:123456789012:orders"},
+		{name: "sqs queue with slash", parse: parseSQS, arn: "arn:aws:sqs:eu-west-1:123456789012:orders/dlq", wantErr: true},
+		{name: "sqs queue with colon", parse: parseSQS, arn: "arn:aws:sqs:eu-west-1:123456789012:orders:dlq", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.parse(tt.arn)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// FuzzParseResourceARNs checks the ARN parsers of the inspectors never panic, whatever the
+// ARN, and that the IDs they extract are never empty
+func FuzzParseResourceARNs(f *testing.F) {
+	for _, seed := range []string{
+		"arn:aws:s3:::my-bucket",
+		"arn:aws:s3:::my:bucket",
+		"arn:aws:ec2:us-east-1:123456789012:instance/i-0abc",
+		"arn:aws:ec2:us-east-1:123456789012:volume/vol-0abc",
+		"arn:aws:ec2:us-east-1:123456789012:natgateway/nat-0abc",
+		"arn:aws:rds:eu-west-1:123456789012:db:orders",
+		"arn:aws:logs:us-west-2:123456789012:log-group:/aws/lambda/orders:*",
+		"arn:aws:cloudwatch:us-east-1:123456789012:alarm:TargetTracking-table/orders:AlarmHigh",
+		"arn:aws:elasticache:us-east-1:123456789012:replicationgroup:sessions",
+		"arn:aws:apigateway:us-east-1::/restapis/a1b2c3",
+		"arn:aws:route53:::hostedzone/Z123",
+		"arn:aws:cloudfront::123456789012:distribution/E2QWRUHEXAMPLE",
+		"arn:aws:sns:eu-west-1:123456789012:alerts",
+		"arn:aws:sqs:eu-west-1:123456789012:orders",
+		"not-an-arn",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, resourceARN string) {
+		ids := make(map[string]string)
+		if id, err := ParseS3ARN(resourceARN); err == nil {
+			ids["s3"] = id
+		}
+		if id, _, err := ParseRDSARN(resourceARN); err == nil {
+			ids["rds"] = id
+		}
+		if id, _, err := ParseCloudWatchLogsARN(resourceARN); err == nil {
+			ids["logs"] = id
+		}
+		if id, _, err := ParseCloudWatchAlarmARN(resourceARN); err == nil {
+			ids["alarm"] = id
+		}
+		if _, id, _, err := ParseEBSARN(resourceARN); err == nil {
+			ids["ebs"] = id
+		}
+		if _, _, id, err := ParseElastiCacheARN(resourceARN); err == nil {
+			ids["elasticache"] = id
+		}
+		if _, _, id, err := ParseAPIGatewayARN(resourceARN); err == nil {
+			ids["apigateway"] = id
+		}
+		if id, err := ParseRoute53ARN(resourceARN); err == nil {
+			ids["route53"] = id
+		}
+		if id, err := ParseCloudFrontARN(resourceARN); err == nil {
+			ids["cloudfront"] = id
+		}
+		if id, _, err := ParseEC2ARN(resourceARN); err == nil {
+			ids["ec2"] = id
+		}
+		if id, _, err := ParseVPCARN(resourceARN); err == nil {
+			ids["vpc"] = id
+		}
+		if id, _, err := ParseNATGatewayARN(resourceARN); err == nil {
+			ids["natgateway"] = id
+		}
+		if id, _, err := ParseInternetGatewayARN(resourceARN); err == nil {
+			ids["internetgateway"] = id
+		}
+		if id, _, err := ParseSecurityGroupARN(resourceARN); err == nil {
+			ids["securitygroup"] = id
+		}
+		if id, _, err := ParseSNSARN(resourceARN); err == nil {
+			ids["sns"] = id
+		}
+		if id, _, err := ParseSQSARN(resourceARN); err == nil {
+			ids["sqs"] = id
+		}
+
+		for parser, id := range ids {
+			if id == "" {
+				t.Fatalf("%s parser extracted an empty ID from %q", parser, resourceARN)
+			}
+		}
+	})
+}
@@ -0,0 +1,144 @@
+// Package manifest records how a compliance run was made, so it can be audited and
+// reproduced: the configuration file and the effective configuration it resolved to, the
+// command line, the aws-taggy build and the identity and regions of the scan.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/version"
+)
+
+// MaskedValue replaces the values of the secret flags recorded in the arguments of a run
+const MaskedValue = "***"
+
+// Manifest describes a compliance run
+type Manifest struct {
+	// Tool is the aws-taggy build of the run
+	Tool version.Info `json:"tool" yaml:"tool"`
+
+	// ConfigFile is the configuration file of the run and ConfigSHA256 the hex encoded
+	// SHA-256 of its content. A replayed run keeps those of the run it replays.
+	ConfigFile   string `json:"config_file" yaml:"config_file"`
+	ConfigSHA256 string `json:"config_sha256" yaml:"config_sha256"`
+
+	// ReplayOf is the manifest the run replayed, see compliance check --replay
+	ReplayOf string `json:"replay_of,omitempty" yaml:"replay_of,omitempty"`
+
+	// Args are the command line arguments of the run, secret values masked
+	Args []string `json:"args" yaml:"args"`
+
+	// AccountID and CallerARN are the identity of the credentials of the scan, empty when it
+	// could not be resolved
+	AccountID string `json:"account_id,omitempty" yaml:"account_id,omitempty"`
+	CallerARN string `json:"caller_arn,omitempty" yaml:"caller_arn,omitempty"`
+
+	// Regions are the regions scanned
+	Regions []string `json:"regions" yaml:"regions"`
+
+	StartedAt  time.Time `json:"started_at" yaml:"started_at"`
+	FinishedAt time.Time `json:"finished_at" yaml:"finished_at"`
+
+	// Config is the effective configuration of the run: the configuration file once
+	// migrated, with the AWS_TAGGY_ environment variables and --set overrides applied and
+	// its tag criteria templates merged
+	Config *configuration.TaggyScanConfig `json:"effective_config" yaml:"effective_config"`
+}
+
+// HashFile returns the hex encoded SHA-256 of the content of a file
+func HashFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// MaskArgs returns the arguments with the values of the given flags, such as
+// --export-password, replaced by MaskedValue, whether written --flag value or --flag=value
+func MaskArgs(args []string, flags ...string) []string {
+	masked := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		for _, flag := range flags {
+			if arg == flag && i+1 < len(args) {
+				masked = append(masked, arg, MaskedValue)
+				i++
+				arg = ""
+				break
+			}
+			if strings.HasPrefix(arg, flag+"=") {
+				arg = flag + "=" + MaskedValue
+				break
+			}
+		}
+		if arg != "" {
+			masked = append(masked, arg)
+		}
+	}
+	return masked
+}
+
+// WriteFile writes the manifest as JSON to path. Manifests are written once: an existing
+// file is never overwritten.
+func (m *Manifest) WriteFile(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run manifest: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("run manifest %s already exists, manifests are never overwritten", path)
+		}
+		return fmt.Errorf("failed to create run manifest %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write run manifest %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close run manifest %s: %w", path, err)
+	}
+	return nil
+}
+
+// Load reads a run manifest, either a file written by WriteFile or the JSON output of a
+// compliance run, which embeds the manifest under its manifest key
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run manifest %s: %w", path, err)
+	}
+
+	var report struct {
+		Manifest *Manifest `json:"manifest"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse run manifest %s: %w", path, err)
+	}
+
+	manifest := report.Manifest
+	if manifest == nil {
+		manifest = &Manifest{}
+		if err := json.Unmarshal(data, manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse run manifest %s: %w", path, err)
+		}
+	}
+	if manifest.Config == nil {
+		return nil, fmt.Errorf("run manifest %s has no effective configuration", path)
+	}
+	return manifest, nil
+}
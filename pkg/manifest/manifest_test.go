@@ -0,0 +1,174 @@
+package manifest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `version: "1.1"
+aws:
+  regions:
+    mode: specific
+    list:
+      - eu-west-1
+global:
+  tag_criteria:
+    minimum_required_tags: 1
+    required_tags:
+      - Owner
+tag_criteria_templates:
+  workload:
+    minimum_required_tags: 2
+    required_tags:
+      - Environment
+      - name: CostCenter
+        severity: critical
+resources:
+  ec2:
+    enabled: true
+    tag_criteria:
+      template: workload
+  s3:
+    enabled: true
+    tag_validation:
+      mode: replace
+      allowed_values:
+        Environment: [sandbox]
+tag_validation:
+  allowed_values:
+    Environment:
+      - production
+      - value: prod
+        deprecated: true
+        replacement: production
+  pattern_rules:
+    CostCenter:
+      pattern: "^CC-[0-9]{4}$"
+      severity: high
+  key_validation:
+    max_length: 128
+`
+
+// newTestManifest loads the test configuration and returns the manifest of a run of it
+func newTestManifest(t *testing.T) *Manifest {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(testConfig), 0o644))
+
+	cfg, err := configuration.NewTaggyScanConfigLoader().LoadConfig(configPath)
+	require.NoError(t, err)
+
+	hash, err := HashFile(configPath)
+	require.NoError(t, err)
+
+	startedAt := time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC)
+	return &Manifest{
+		Tool:         version.Get(),
+		ConfigFile:   configPath,
+		ConfigSHA256: hash,
+		Args:         []string{"compliance", "check", "--config", configPath},
+		AccountID:    "123456789012",
+		CallerARN:    "arn:aws:iam::123456789012:role/audit",
+		Regions:      []string{"eu-west-1"},
+		StartedAt:    startedAt,
+		FinishedAt:   startedAt.Add(time.Minute),
+		Config:       cfg,
+	}
+}
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("version: \"1.1\"\n"), 0o644))
+
+	hash, err := HashFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "286dd462c45bb30232a990370245f5514019215bc5b1ee4063927d5ca25066ad", hash)
+
+	_, err = HashFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestMaskArgs(t *testing.T) {
+	args := []string{
+		"compliance", "check", "--config", "config.yaml",
+		"--export-password", "secret", "--export-username=admin", "--export-password=secret",
+	}
+
+	assert.Equal(t, []string{
+		"compliance", "check", "--config", "config.yaml",
+		"--export-password", MaskedValue, "--export-username=admin", "--export-password=" + MaskedValue,
+	}, MaskArgs(args, "--export-password"))
+	assert.Equal(t, args, MaskArgs(args))
+}
+
+func TestManifest_WriteFileOnce(t *testing.T) {
+	manifest := newTestManifest(t)
+	path := filepath.Join(t.TempDir(), "manifest.json")
+
+	require.NoError(t, manifest.WriteFile(path))
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	err = manifest.WriteFile(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	unchanged, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, written, unchanged)
+}
+
+func TestLoad_ReplaysEffectiveConfig(t *testing.T) {
+	manifest := newTestManifest(t)
+	dir := t.TempDir()
+
+	manifestPath := filepath.Join(dir, "manifest.json")
+	require.NoError(t, manifest.WriteFile(manifestPath))
+
+	// The JSON output of a run embeds its manifest
+	reportPath := filepath.Join(dir, "results.json")
+	report, err := json.Marshal(map[string]any{"summary": map[string]int{"total_resources": 0}, "manifest": manifest})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(reportPath, report, 0o644))
+
+	for _, path := range []string{manifestPath, reportPath} {
+		loaded, err := Load(path)
+		require.NoError(t, err, path)
+
+		assert.Equal(t, manifest.ConfigSHA256, loaded.ConfigSHA256, path)
+		assert.Equal(t, manifest.Args, loaded.Args, path)
+		assert.Equal(t, manifest.Regions, loaded.Regions, path)
+		assert.True(t, manifest.StartedAt.Equal(loaded.StartedAt), path)
+
+		cfg, err := configuration.NewTaggyScanConfigLoader().LoadEffectiveConfig(loaded.Config)
+		require.NoError(t, err, path)
+
+		ec2Criteria := cfg.Resources["ec2"].TagCriteria
+		assert.Equal(t, []string{"Environment", "CostCenter"}, ec2Criteria.RequiredTags, path)
+		assert.Equal(t, configuration.SeverityCritical, ec2Criteria.RequiredTagSeverity("CostCenter"), path)
+		assert.Equal(t, []string{"sandbox"}, cfg.ResourceTagValidation("s3").AllowedValues["Environment"], path)
+		assert.Equal(t, configuration.SeverityHigh, cfg.TagValidation.PatternRuleSeverities["CostCenter"], path)
+
+		replacement, deprecated := cfg.TagValidation.DeprecatedValue("Environment", "prod")
+		assert.True(t, deprecated, path)
+		assert.Equal(t, "production", replacement, path)
+	}
+}
+
+func TestLoad_RequiresEffectiveConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"summary": {"total_resources": 0}}`), 0o644))
+
+	_, err := Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no effective configuration")
+}
@@ -1,8 +1,11 @@
 package o11y
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"strings"
+	"sync"
 
 	"github.com/charmbracelet/log"
 )
@@ -17,10 +20,51 @@
 	LogLevelError
 )
 
+// LogFormat represents how log entries are rendered
+type LogFormat string
+
+const (
+	// LogFormatText renders human oriented lines decorated with emojis
+	LogFormatText LogFormat = "text"
+
+	// LogFormatJSON renders one JSON object per line with the time, level, message and
+	// key-value fields, as expected by log aggregators such as CloudWatch Logs Insights
+	LogFormatJSON LogFormat = "json"
+)
+
+// ParseLogLevel converts a level name (debug, info, warn or error) into a LogLevel
+func ParseLogLevel(level string) (LogLevel, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return LogLevelDebug, nil
+	case "info":
+		return LogLevelInfo, nil
+	case "warn", "warning":
+		return LogLevelWarn, nil
+	case "error":
+		return LogLevelError, nil
+	default:
+		return LogLevelInfo, fmt.Errorf("unknown log level %q, expected one of debug, info, warn or error", level)
+	}
+}
+
+// ParseLogFormat converts a format name (text or json) into a LogFormat
+func ParseLogFormat(format string) (LogFormat, error) {
+	switch LogFormat(strings.ToLower(format)) {
+	case LogFormatText:
+		return LogFormatText, nil
+	case LogFormatJSON:
+		return LogFormatJSON, nil
+	default:
+		return LogFormatText, fmt.Errorf("unknown log format %q, expected text or json", format)
+	}
+}
+
 // Logger provides a structured logging interface with emojis
 type Logger struct {
 	logger *log.Logger
 	level  LogLevel
+	format LogFormat
 }
 
 // LoggerInterface defines the contract for logging methods
@@ -36,8 +80,15 @@ type LoggerInterface interface {
 
 // NewLogger creates a new logger with specified options and emojis
 func NewLogger(output io.Writer, level LogLevel) *Logger {
+	return NewLoggerWithFormat(output, level, LogFormatText)
+}
+
+// NewLoggerWithFormat creates a new logger rendering its entries in the given format.
+// JSON entries carry no emojis, so their messages can be matched as they are written.
+// Entries are written to stderr when output is nil.
+func NewLoggerWithFormat(output io.Writer, level LogLevel, format LogFormat) *Logger {
 	if output == nil {
-		output = os.Stdout
+		output = os.Stderr
 	}
 
 	// Create a new Charmbracelet logger
@@ -59,36 +110,78 @@ func NewLogger(output io.Writer, level LogLevel) *Logger {
 	}
 
 	// Configure emoji and styling
-	charmLogger.SetFormatter(log.TextFormatter)
+	switch format {
+	case LogFormatJSON:
+		charmLogger.SetFormatter(log.JSONFormatter)
+	default:
+		format = LogFormatText
+		charmLogger.SetFormatter(log.TextFormatter)
+	}
 	charmLogger.SetReportTimestamp(true)
 
 	return &Logger{
 		logger: charmLogger,
 		level:  level,
+		format: format,
 	}
 }
 
-// DefaultLogger creates a logger with default settings and emojis
+var (
+	defaultLoggerMu sync.RWMutex
+	defaultLogger   *Logger
+)
+
+// DefaultLogger returns the logger shared by the commands and inspectors. Unless replaced
+// through SetDefaultLogger, it writes text entries with emojis at the info level to stderr,
+// leaving stdout to the output of the commands.
 func DefaultLogger() *Logger {
-	return NewLogger(os.Stdout, LogLevelInfo)
+	defaultLoggerMu.RLock()
+	logger := defaultLogger
+	defaultLoggerMu.RUnlock()
+	if logger != nil {
+		return logger
+	}
+
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	if defaultLogger == nil {
+		defaultLogger = NewLogger(os.Stderr, LogLevelInfo)
+	}
+	return defaultLogger
+}
+
+// SetDefaultLogger replaces the logger returned by DefaultLogger, e.g. with the level and
+// format chosen on the command line. It should be called before any command runs.
+func SetDefaultLogger(logger *Logger) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	defaultLogger = logger
 }
 
 // Debug logs a debug message with 🐞 emoji
 func (l *Logger) Debug(msg string, args ...any) {
-	l.logger.Debug("🐞 "+msg, args...)
+	l.logger.Debug(l.decorate("🐞", msg), args...)
 }
 
 // Info logs an info message with 📝 emoji
 func (l *Logger) Info(msg string, args ...any) {
-	l.logger.Info("ℹ️ "+msg, args...)
+	l.logger.Info(l.decorate("ℹ️", msg), args...)
 }
 
 // Warn logs a warning message with ⚠️ emoji
 func (l *Logger) Warn(msg string, args ...any) {
-	l.logger.Warn("🔔 "+msg, args...)
+	l.logger.Warn(l.decorate("🔔", msg), args...)
 }
 
 // Error logs an error message with 🚨 emoji
 func (l *Logger) Error(msg string, args ...any) {
-	l.logger.Error("🚨 "+msg, args...)
+	l.logger.Error(l.decorate("🚨", msg), args...)
+}
+
+// decorate prefixes text messages with their emoji, leaving JSON messages untouched
+func (l *Logger) decorate(emoji, msg string) string {
+	if l.format == LogFormatJSON {
+		return msg
+	}
+	return emoji + " " + msg
 }
@@ -2,10 +2,12 @@
 
 import (
 	"bytes"
+	"encoding/json"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewLogger(t *testing.T) {
@@ -131,3 +133,70 @@ func TestLoggerMethods(t *testing.T) {
 		})
 	}
 }
+
+func TestNewLoggerWithFormat_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLoggerWithFormat(&buf, LogLevelInfo, LogFormatJSON)
+
+	logger.Debug("hidden message")
+	logger.Warn("test message", "region", "us-east-1", "count", 3)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 1, "debug entries must be filtered at the info level")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "warn", entry["level"])
+	assert.Equal(t, "test message", entry["msg"])
+	assert.Equal(t, "us-east-1", entry["region"])
+	assert.EqualValues(t, 3, entry["count"])
+	assert.Contains(t, entry, "time")
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected LogLevel
+		wantErr  bool
+	}{
+		{input: "debug", expected: LogLevelDebug},
+		{input: "INFO", expected: LogLevelInfo},
+		{input: "warn", expected: LogLevelWarn},
+		{input: "error", expected: LogLevelError},
+		{input: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			level, err := ParseLogLevel(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, level)
+		})
+	}
+}
+
+func TestParseLogFormat(t *testing.T) {
+	format, err := ParseLogFormat("JSON")
+	require.NoError(t, err)
+	assert.Equal(t, LogFormatJSON, format)
+
+	_, err = ParseLogFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestSetDefaultLogger(t *testing.T) {
+	previous := DefaultLogger()
+	t.Cleanup(func() { SetDefaultLogger(previous) })
+
+	assert.Same(t, previous, DefaultLogger(), "the default logger must be shared")
+
+	var buf bytes.Buffer
+	SetDefaultLogger(NewLoggerWithFormat(&buf, LogLevelInfo, LogFormatJSON))
+	DefaultLogger().Info("test message")
+
+	assert.True(t, json.Valid(bytes.TrimSpace(buf.Bytes())), "Expected a JSON entry: %s", buf.String())
+}
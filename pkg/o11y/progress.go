@@ -0,0 +1,92 @@
+package o11y
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Progress events emitted while scanning, in the Event field of a ProgressEvent
+const (
+	// ProgressServiceStarted is emitted when the scan of a resource type starts
+	ProgressServiceStarted = "service_started"
+
+	// ProgressRegionDiscovered is emitted, once the scan of a resource type completes, for
+	// every region its resources were discovered in
+	ProgressRegionDiscovered = "region_discovered"
+
+	// ProgressServiceCompleted is emitted when the scan of a resource type completes, with
+	// the number of resources it discovered
+	ProgressServiceCompleted = "service_completed"
+
+	// ProgressServiceFailed is emitted when the scan of a resource type fails
+	ProgressServiceFailed = "service_failed"
+
+	// ProgressProcessed is emitted every time the scan of a resource type ends, with the
+	// number of resource types processed so far out of the total of the scan
+	ProgressProcessed = "processed"
+)
+
+// ProgressEvent is a machine-readable step of a scan, written as one JSON object per line so
+// wrappers can render their own progress. Zero fields are left out of the line.
+type ProgressEvent struct {
+	Time      time.Time `json:"time"`
+	Event     string    `json:"event"`
+	Service   string    `json:"service,omitempty"`
+	Account   string    `json:"account,omitempty"`
+	Region    string    `json:"region,omitempty"`
+	Resources int       `json:"resources,omitempty"`
+	Processed int       `json:"processed,omitempty"`
+	Total     int       `json:"total,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// ProgressReporter writes progress events as newline-delimited JSON. A nil reporter
+// discards them, so callers do not need to check whether progress is reported.
+type ProgressReporter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewProgressReporter creates a reporter writing progress events to output
+func NewProgressReporter(output io.Writer) *ProgressReporter {
+	return &ProgressReporter{encoder: json.NewEncoder(output)}
+}
+
+// Emit writes a progress event, stamped with the current time unless it has one. Events of
+// concurrent scans are written whole, one per line.
+func (p *ProgressReporter) Emit(event ProgressEvent) {
+	if p == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// Progress is best effort, a wrapper that stopped reading must not fail the scan
+	_ = p.encoder.Encode(event)
+}
+
+var (
+	defaultProgressMu sync.RWMutex
+	defaultProgress   *ProgressReporter
+)
+
+// DefaultProgress returns the reporter of the progress of the commands and inspectors, nil
+// and so discarding every event unless set through SetDefaultProgress
+func DefaultProgress() *ProgressReporter {
+	defaultProgressMu.RLock()
+	defer defaultProgressMu.RUnlock()
+	return defaultProgress
+}
+
+// SetDefaultProgress replaces the reporter returned by DefaultProgress, e.g. with one writing
+// to stderr when asked on the command line. It should be called before any command runs.
+func SetDefaultProgress(progress *ProgressReporter) {
+	defaultProgressMu.Lock()
+	defer defaultProgressMu.Unlock()
+	defaultProgress = progress
+}
@@ -0,0 +1,75 @@
+package o11y
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressReporter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	progress := NewProgressReporter(&buf)
+
+	progress.Emit(ProgressEvent{Event: ProgressServiceStarted, Service: "s3"})
+	progress.Emit(ProgressEvent{Event: ProgressRegionDiscovered, Service: "s3", Region: "eu-west-1", Resources: 12})
+	progress.Emit(ProgressEvent{Event: ProgressProcessed, Processed: 1, Total: 3})
+
+	var events []map[string]interface{}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var event map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event), scanner.Text())
+		events = append(events, event)
+	}
+	require.Len(t, events, 3)
+
+	assert.Equal(t, "service_started", events[0]["event"])
+	assert.Equal(t, "s3", events[0]["service"])
+	assert.Contains(t, events[0], "time")
+	assert.NotContains(t, events[0], "resources", "zero fields are left out")
+
+	assert.Equal(t, "region_discovered", events[1]["event"])
+	assert.Equal(t, "eu-west-1", events[1]["region"])
+	assert.EqualValues(t, 12, events[1]["resources"])
+
+	assert.Equal(t, "processed", events[2]["event"])
+	assert.EqualValues(t, 1, events[2]["processed"])
+	assert.EqualValues(t, 3, events[2]["total"])
+}
+
+func TestProgressReporterConcurrentEvents(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	progress := NewProgressReporter(&buf)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			progress.Emit(ProgressEvent{Event: ProgressServiceCompleted, Service: "ec2", Resources: i})
+		}()
+	}
+	wg.Wait()
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 50)
+	for _, line := range lines {
+		assert.True(t, json.Valid(line), "Expected a JSON event per line: %s", line)
+	}
+}
+
+func TestNilProgressReporter(t *testing.T) {
+	t.Parallel()
+
+	var progress *ProgressReporter
+	assert.NotPanics(t, func() { progress.Emit(ProgressEvent{Event: ProgressServiceStarted}) })
+}
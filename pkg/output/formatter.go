@@ -1,6 +1,7 @@
 package output
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -37,22 +38,20 @@ type YAMLFormatter struct {
 	Pretty bool
 }
 
-// Format formats the data as YAML
+// Format formats the data as YAML, indented by two spaces
 func (f *YAMLFormatter) Format(data interface{}) (string, error) {
-	var bytes []byte
-	var err error
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
 
-	if f.Pretty {
-		bytes, err = yaml.Marshal(data)
-	} else {
-		bytes, err = yaml.Marshal(data)
+	if err := encoder.Encode(data); err != nil {
+		return "", fmt.Errorf("failed to format as YAML: %w", err)
 	}
-
-	if err != nil {
+	if err := encoder.Close(); err != nil {
 		return "", fmt.Errorf("failed to format as YAML: %w", err)
 	}
 
-	return string(bytes), nil
+	return buf.String(), nil
 }
 
 // TableFormatter implements Formatter for table output
@@ -65,6 +65,19 @@ func TestJSONFormatter(t *testing.T) {
 	}
 }
 
+func TestYAMLFormatter(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]interface{}{
+		"service":   "s3",
+		"resources": []map[string]string{{"id": "logs"}},
+	}
+
+	output, err := NewYAMLFormatter(false).Format(data)
+	require.NoError(t, err)
+	assert.Equal(t, "resources:\n  - id: logs\nservice: s3\n", output)
+}
+
 func TestTableFormatter(t *testing.T) {
 	t.Parallel()
 
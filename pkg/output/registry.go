@@ -0,0 +1,102 @@
+package output
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// FormatterOptions tune the formatter built by the factory of a registered format
+type FormatterOptions struct {
+	// Pretty asks for an indented rendering, for formats that have one
+	Pretty bool
+
+	// Headers are the column headers of tabular formats
+	Headers []string
+}
+
+// FormatterFactory creates the formatter of a registered format
+type FormatterFactory func(opts FormatterOptions) Formatter
+
+// registry holds the formatter of every output format, by lowercase name
+var registry = struct {
+	sync.RWMutex
+	factories map[string]FormatterFactory
+}{factories: make(map[string]FormatterFactory)}
+
+func init() {
+	Register("json", func(opts FormatterOptions) Formatter { return NewJSONFormatter(opts.Pretty) })
+	Register("yaml", func(opts FormatterOptions) Formatter { return NewYAMLFormatter(opts.Pretty) })
+	Register("yml", func(opts FormatterOptions) Formatter { return NewYAMLFormatter(opts.Pretty) })
+	Register("table", func(opts FormatterOptions) Formatter { return NewTableFormatter(opts.Headers) })
+}
+
+// Register makes an output format, such as csv, available to every command under a name,
+// matched regardless of case. It is meant to be called from the init function of the package
+// providing the formatter.
+//
+// Register panics when the name is empty, already registered (built-in formats included) or
+// the factory is nil.
+func Register(format string, factory FormatterFactory) {
+	name := normalizeFormat(format)
+	if name == "" {
+		panic("output: empty format registered")
+	}
+	if factory == nil {
+		panic(fmt.Sprintf("output: nil factory registered for format %q", format))
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, exists := registry.factories[name]; exists {
+		panic(fmt.Sprintf("output: format %q registered twice", name))
+	}
+	registry.factories[name] = factory
+}
+
+// New creates the formatter of a registered format with the default options
+func New(format string) (Formatter, error) {
+	return NewWithOptions(format, FormatterOptions{})
+}
+
+// NewWithOptions creates the formatter of a registered format. Unknown formats fail with the
+// list of the registered ones.
+func NewWithOptions(format string, opts FormatterOptions) (Formatter, error) {
+	registry.RLock()
+	factory, exists := registry.factories[normalizeFormat(format)]
+	registry.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("unsupported output format %q, registered formats are: %s", format, strings.Join(Formats(), ", "))
+	}
+	return factory(opts), nil
+}
+
+// IsRegistered reports whether a formatter is registered for the format
+func IsRegistered(format string) bool {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	_, exists := registry.factories[normalizeFormat(format)]
+	return exists
+}
+
+// Formats returns the registered output formats, sorted
+func Formats() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	formats := make([]string, 0, len(registry.factories))
+	for format := range registry.factories {
+		formats = append(formats, format)
+	}
+	slices.Sort(formats)
+	return formats
+}
+
+// normalizeFormat returns the registry name of a format
+func normalizeFormat(format string) string {
+	return strings.ToLower(strings.TrimSpace(format))
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// csvFormatter renders [][]string rows as comma-separated lines
+type csvFormatter struct{}
+
+func (csvFormatter) Format(data interface{}) (string, error) {
+	rows, ok := data.([][]string)
+	if !ok {
+		return "", fmt.Errorf("data must be [][]string for csv formatting")
+	}
+	var out string
+	for _, row := range rows {
+		for i, cell := range row {
+			if i > 0 {
+				out += ","
+			}
+			out += cell
+		}
+		out += "\n"
+	}
+	return out, nil
+}
+
+func TestRegistryBuiltInFormats(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{"json", "yaml", "yml", "table"} {
+		assert.True(t, IsRegistered(format), format)
+	}
+
+	formatter, err := New("JSON")
+	require.NoError(t, err)
+	assert.IsType(t, &JSONFormatter{}, formatter)
+
+	formatter, err = New(" yml ")
+	require.NoError(t, err)
+	assert.IsType(t, &YAMLFormatter{}, formatter)
+
+	formatter, err = NewWithOptions("table", FormatterOptions{Headers: []string{"Key", "Value"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Key", "Value"}, formatter.(*TableFormatter).Headers)
+}
+
+func TestRegistryUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	_, err := New("xml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported output format "xml"`)
+	assert.Contains(t, err.Error(), "json, ")
+	assert.False(t, IsRegistered("xml"))
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-csv", func(opts FormatterOptions) Formatter { return csvFormatter{} })
+
+	assert.Contains(t, Formats(), "test-csv")
+	formatter, err := New("TEST-CSV")
+	require.NoError(t, err)
+
+	rendered, err := formatter.Format([][]string{{"Name", "Age"}, {"John Doe", "30"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Name,Age\nJohn Doe,30\n", rendered)
+
+	assert.Panics(t, func() {
+		Register("Test-CSV", func(opts FormatterOptions) Formatter { return csvFormatter{} })
+	})
+	assert.Panics(t, func() { Register("json", func(opts FormatterOptions) Formatter { return csvFormatter{} }) })
+	assert.Panics(t, func() { Register(" ", func(opts FormatterOptions) Formatter { return csvFormatter{} }) })
+	assert.Panics(t, func() { Register("test-nil", nil) })
+}
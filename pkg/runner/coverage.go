@@ -0,0 +1,70 @@
+package runner
+
+import "sort"
+
+// TagCoverage counts the resources of a type carrying a required tag key, whatever its value.
+// Only resources whose tags were read are counted: excluded resources, resources left out by
+// filters and resources of unknown compliance weigh on neither count.
+type TagCoverage struct {
+	TagKey       string  `json:"tag_key" yaml:"tag_key"`
+	ResourceType string  `json:"resource_type" yaml:"resource_type"`
+	Present      int     `json:"present" yaml:"present"`
+	Missing      int     `json:"missing" yaml:"missing"`
+	Percentage   float64 `json:"percentage" yaml:"percentage"`
+}
+
+// coverageCounter accumulates the tag coverage of the results added to a summary
+type coverageCounter struct {
+	// keys are the tag keys whose coverage is counted
+	keys []string
+
+	// counts are keyed by tag key and resource type
+	counts map[string]map[string]*TagCoverage
+}
+
+// newCoverageCounter creates a coverageCounter of the tag keys
+func newCoverageCounter(keys []string) *coverageCounter {
+	return &coverageCounter{keys: keys, counts: make(map[string]map[string]*TagCoverage, len(keys))}
+}
+
+// add counts the tags of a result of known compliance
+func (c *coverageCounter) add(result *ResourceResult) {
+	for _, key := range c.keys {
+		byType, exists := c.counts[key]
+		if !exists {
+			byType = make(map[string]*TagCoverage)
+			c.counts[key] = byType
+		}
+		coverage, exists := byType[result.ResourceType]
+		if !exists {
+			coverage = &TagCoverage{TagKey: key, ResourceType: result.ResourceType}
+			byType[result.ResourceType] = coverage
+		}
+
+		if _, present := result.ResourceTags[key]; present {
+			coverage.Present++
+		} else {
+			coverage.Missing++
+		}
+	}
+}
+
+// build returns the coverage of every tag key and resource type, ordered by tag key and
+// resource type
+func (c *coverageCounter) build() []TagCoverage {
+	var coverage []TagCoverage
+	for _, byType := range c.counts {
+		for _, entry := range byType {
+			entry.Percentage = float64(entry.Present) / float64(entry.Present+entry.Missing) * 100
+			coverage = append(coverage, *entry)
+		}
+	}
+
+	sort.Slice(coverage, func(i, j int) bool {
+		if coverage[i].TagKey != coverage[j].TagKey {
+			return coverage[i].TagKey < coverage[j].TagKey
+		}
+		return coverage[i].ResourceType < coverage[j].ResourceType
+	})
+	return coverage
+}
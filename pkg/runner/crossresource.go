@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+)
+
+// vpcProperty is the property of the resources inside a VPC holding its ID
+const vpcProperty = "vpc_id"
+
+// crossResourceViolations checks the cross-resource rules against the resources of every
+// inspection result, returning the violations of each resource breaking a rule keyed by the
+// resource in its result. Resources whose tags could not be read are left out, their
+// compliance being unknown. It returns nil without rules.
+func crossResourceViolations(rules []configuration.CrossResourceRule, results map[string]*inspector.InspectResult) map[*inspector.ResourceMetadata][]compliance.Violation {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var checked []*inspector.ResourceMetadata
+	var resources []compliance.ScopedResource
+
+	// Results are walked in key order, so related resources are listed in a stable order
+	for _, key := range slices.Sorted(maps.Keys(results)) {
+		result := results[key]
+		for i := range result.Resources {
+			resource := &result.Resources[i]
+			if resource.TagFetchError != "" {
+				continue
+			}
+
+			vpc, _ := resource.Details.Properties[vpcProperty].(string)
+			checked = append(checked, resource)
+			resources = append(resources, compliance.ScopedResource{
+				Resource:  compliance.ResourceRef{ID: resource.ID, ARN: resource.Details.ARN, Type: resource.Type},
+				Tags:      resource.Tags,
+				AccountID: resource.AccountID,
+				Region:    resource.Region,
+				VPC:       vpc,
+			})
+		}
+	}
+
+	violations := make(map[*inspector.ResourceMetadata][]compliance.Violation)
+	for i, resourceViolations := range compliance.CheckCrossResourceRules(rules, resources) {
+		violations[checked[i]] = resourceViolations
+	}
+	return violations
+}
@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestInstance returns a compliant EC2 instance named name inside the VPC vpc
+func newTestInstance(id, name, vpc string) inspector.ResourceMetadata {
+	instance := newTestResource(id, map[string]string{"Environment": "prod", "Owner": "web", "Name": name})
+	instance.Type = "ec2"
+	instance.Details.ARN = "arn:aws:ec2:us-east-1:123456789012:instance/" + id
+	instance.Details.Properties = map[string]interface{}{"vpc_id": vpc}
+	return instance
+}
+
+func TestRunnerReportCrossResourceRules(t *testing.T) {
+	t.Parallel()
+
+	config := newTestConfig()
+	config.CrossResourceRules = []configuration.CrossResourceRule{{
+		UniqueValue: &configuration.UniqueValueRule{Tag: "Name", Scope: []string{"type", "vpc"}},
+		Severity:    configuration.SeverityHigh,
+	}}
+
+	runner, err := New(config, Options{})
+	require.NoError(t, err)
+
+	unreadable := newTestInstance("i-4", "web", "vpc-a")
+	unreadable.Tags = nil
+	unreadable.TagFetchError = "access denied"
+
+	scan := newTestScan()
+	scan.Results["ec2"] = &inspector.InspectResult{
+		Resources: []inspector.ResourceMetadata{
+			newTestInstance("i-1", "web", "vpc-a"),
+			newTestInstance("i-2", "web", "vpc-a"),
+			newTestInstance("i-3", "web", "vpc-b"),
+			unreadable,
+		},
+		TotalResources: 4,
+	}
+
+	report := mustReport(t, runner, scan)
+	results := make(map[string]*ResourceResult, len(report.ResourceResults))
+	for _, result := range report.ResourceResults {
+		results[result.ResourceID] = result
+	}
+
+	for id, other := range map[string]string{"i-1": "i-2", "i-2": "i-1"} {
+		result := results[id]
+		assert.False(t, result.IsCompliant, id)
+		require.Len(t, result.Violations, 1, id)
+		violation := result.Violations[0]
+		assert.Equal(t, string(compliance.ViolationTypeDuplicateTagValue), violation.Type)
+		assert.Equal(t, "high", violation.Severity)
+		assert.Equal(t, "web", violation.Value)
+		assert.Equal(t, []string{"arn:aws:ec2:us-east-1:123456789012:instance/" + other}, violation.RelatedResources)
+		assert.Equal(t, compliance.MaxComplianceScore-compliance.SeverityHigh.Penalty(), result.Score)
+	}
+	assert.True(t, results["i-3"].IsCompliant, "a name only needs to be unique within its VPC")
+	assert.True(t, results["i-4"].IsUnknown)
+
+	rule := report.Summary.RuleResults["unique_tag_values"]
+	assert.False(t, rule.Passed)
+	assert.Equal(t, 2, rule.Failures)
+	assert.Equal(t, map[string]int{"ec2": 2}, rule.FailuresByResourceType)
+}
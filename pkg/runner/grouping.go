@@ -0,0 +1,159 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// GroupByAccount groups results by the AWS account owning the resources
+	GroupByAccount = "account"
+
+	// GroupByRegion groups results by the region of the resources
+	GroupByRegion = "region"
+
+	// GroupByType groups results by resource type
+	GroupByType = "type"
+
+	// GroupByTagPrefix prefixes the tag whose value groups results, e.g. tag:CostCenter
+	GroupByTagPrefix = "tag:"
+
+	// UntaggedGroup collects the resources missing the tag results are grouped by
+	UntaggedGroup = "(untagged)"
+
+	// TopViolationsLimit is the number of violation types reported for every group
+	TopViolationsLimit = 3
+
+	// unknownGroup collects the resources whose account or region is not known
+	unknownGroup = "unknown"
+)
+
+// ValidateGroupBy checks a grouping dimension: account, region, type or tag:<key>
+func ValidateGroupBy(groupBy string) error {
+	switch groupBy {
+	case GroupByAccount, GroupByRegion, GroupByType:
+		return nil
+	}
+
+	if key, ok := strings.CutPrefix(groupBy, GroupByTagPrefix); ok && key != "" {
+		return nil
+	}
+
+	return fmt.Errorf("unsupported group-by dimension %q, expected one of: %s, %s, %s or %s<key>",
+		groupBy, GroupByAccount, GroupByRegion, GroupByType, GroupByTagPrefix)
+}
+
+// GroupKey returns the key of the group a result belongs to for the grouping dimension
+func GroupKey(result *ResourceResult, groupBy string) string {
+	var key string
+	switch groupBy {
+	case GroupByAccount:
+		key = result.AccountID
+	case GroupByRegion:
+		key = result.Region
+	case GroupByType:
+		key = result.ResourceType
+	default:
+		if tag, ok := strings.CutPrefix(groupBy, GroupByTagPrefix); ok {
+			if value, exists := result.ResourceTags[tag]; exists {
+				return value
+			}
+			return UntaggedGroup
+		}
+	}
+
+	if key == "" {
+		return unknownGroup
+	}
+	return key
+}
+
+// AddToGroup counts a result in the group of its key for the grouping dimension
+func AddToGroup(groups map[string]*GroupSummary, result *ResourceResult, groupBy string) {
+	key := GroupKey(result, groupBy)
+
+	group, exists := groups[key]
+	if !exists {
+		group = &GroupSummary{}
+		groups[key] = group
+	}
+
+	group.TotalResources++
+	if result.IsUnknown {
+		group.UnknownResources++
+		return
+	}
+	if result.IsCompliant {
+		group.CompliantResources++
+		return
+	}
+
+	group.NonCompliantResources++
+	for _, violation := range result.Violations {
+		if group.ViolationTypes == nil {
+			group.ViolationTypes = make(map[string]int)
+		}
+		group.ViolationTypes[violation.Type]++
+	}
+}
+
+// GroupResults aggregates compliance counts by the grouping dimension
+func GroupResults(results []*ResourceResult, groupBy string) map[string]*GroupSummary {
+	groups := make(map[string]*GroupSummary)
+	for _, result := range results {
+		AddToGroup(groups, result, groupBy)
+	}
+	return groups
+}
+
+// ViolationCount is the number of violations of a type
+type ViolationCount struct {
+	Type  string
+	Count int
+}
+
+// TopViolations returns the most frequent violation types of the group, at most limit of
+// them, the most frequent first and ties ordered by type
+func (g *GroupSummary) TopViolations(limit int) []ViolationCount {
+	counts := make([]ViolationCount, 0, len(g.ViolationTypes))
+	for violationType, count := range g.ViolationTypes {
+		counts = append(counts, ViolationCount{Type: violationType, Count: count})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Type < counts[j].Type
+	})
+
+	if len(counts) > limit {
+		counts = counts[:limit]
+	}
+	return counts
+}
+
+// FormatTopViolations renders the most frequent violation types of the group on one line
+func (g *GroupSummary) FormatTopViolations(limit int) string {
+	top := g.TopViolations(limit)
+	if len(top) == 0 {
+		return "-"
+	}
+
+	parts := make([]string, 0, len(top))
+	for _, violation := range top {
+		parts = append(parts, fmt.Sprintf("%s (%d)", violation.Type, violation.Count))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SortedGroupKeys returns the keys of the groups in alphabetical order
+func SortedGroupKeys(groups map[string]*GroupSummary) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
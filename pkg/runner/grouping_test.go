@@ -0,0 +1,161 @@
+package runner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateGroupBy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		groupBy string
+		wantErr bool
+	}{
+		{groupBy: "account"},
+		{groupBy: "region"},
+		{groupBy: "type"},
+		{groupBy: "tag:CostCenter"},
+		{groupBy: "tag:", wantErr: true},
+		{groupBy: "owner", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateGroupBy(tt.groupBy)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGroupResults(t *testing.T) {
+	t.Parallel()
+
+	results := []*ResourceResult{
+		{
+			IsCompliant:  true,
+			ResourceType: "s3",
+			AccountID:    "111111111111",
+			Region:       "us-east-1",
+			ResourceTags: map[string]string{"CostCenter": "finance"},
+		},
+		{
+			ResourceType: "s3",
+			AccountID:    "111111111111",
+			Region:       "eu-west-1",
+			ResourceTags: map[string]string{"CostCenter": "finance"},
+			Violations: []Violation{
+				{Type: "invalid_value"},
+				{Type: "missing_required_tag"},
+			},
+		},
+		{
+			ResourceType: "sqs",
+			Region:       "eu-west-1",
+			ResourceTags: map[string]string{"Owner": "platform"},
+			Violations: []Violation{
+				{Type: "missing_required_tag"},
+			},
+		},
+	}
+
+	tests := []struct {
+		groupBy  string
+		expected map[string][3]int
+	}{
+		{
+			groupBy:  "account",
+			expected: map[string][3]int{"111111111111": {2, 1, 1}, "unknown": {1, 0, 1}},
+		},
+		{
+			groupBy:  "region",
+			expected: map[string][3]int{"us-east-1": {1, 1, 0}, "eu-west-1": {2, 0, 2}},
+		},
+		{
+			groupBy:  "type",
+			expected: map[string][3]int{"s3": {2, 1, 1}, "sqs": {1, 0, 1}},
+		},
+		{
+			groupBy:  "tag:CostCenter",
+			expected: map[string][3]int{"finance": {2, 1, 1}, UntaggedGroup: {1, 0, 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			t.Parallel()
+
+			groups := GroupResults(results, tt.groupBy)
+			require.Len(t, groups, len(tt.expected))
+			for key, counts := range tt.expected {
+				group, ok := groups[key]
+				require.True(t, ok, "missing group %s", key)
+				assert.Equal(t, counts[0], group.TotalResources, key)
+				assert.Equal(t, counts[1], group.CompliantResources, key)
+				assert.Equal(t, counts[2], group.NonCompliantResources, key)
+			}
+		})
+	}
+
+	t.Run("Violation Types", func(t *testing.T) {
+		t.Parallel()
+
+		groups := GroupResults(results, "region")
+		assert.Nil(t, groups["us-east-1"].ViolationTypes)
+		assert.Equal(t, map[string]int{"invalid_value": 1, "missing_required_tag": 2}, groups["eu-west-1"].ViolationTypes)
+	})
+}
+
+func TestGroupSummaryTopViolations(t *testing.T) {
+	t.Parallel()
+
+	group := &GroupSummary{ViolationTypes: map[string]int{
+		"case_mismatch":        1,
+		"invalid_value":        3,
+		"invalid_format":       3,
+		"missing_required_tag": 5,
+	}}
+
+	assert.Equal(t, []ViolationCount{
+		{Type: "missing_required_tag", Count: 5},
+		{Type: "invalid_format", Count: 3},
+		{Type: "invalid_value", Count: 3},
+	}, group.TopViolations(3))
+	assert.Equal(t, "missing_required_tag (5), invalid_format (3), invalid_value (3)", group.FormatTopViolations(3))
+	assert.Equal(t, "-", (&GroupSummary{}).FormatTopViolations(3))
+}
+
+func TestSummaryNestsGroups(t *testing.T) {
+	t.Parallel()
+
+	summary := Summary{
+		TotalResources: 1,
+		GroupBy:        "tag:Team",
+		Groups: map[string]*GroupSummary{
+			"payments": {TotalResources: 1, NonCompliantResources: 1, ViolationTypes: map[string]int{"missing_required_tag": 1}},
+		},
+	}
+
+	data, err := json.Marshal(summary)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "tag:Team", decoded["group_by"])
+
+	groups, ok := decoded["groups"].(map[string]interface{})
+	require.True(t, ok)
+	payments, ok := groups["payments"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, float64(1), payments["non_compliant_resources"])
+	assert.Equal(t, map[string]interface{}{"missing_required_tag": float64(1)}, payments["violation_types"])
+}
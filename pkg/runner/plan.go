@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/Excoriate/aws-taggy/pkg/tfplan"
+)
+
+// PlanScan returns the scan result of the resources of a Terraform plan or state, so their
+// tags are validated by Report and Stream like scanned resources, without calling AWS.
+// Resources are identified by their Terraform address. Those whose resource type is not
+// enabled in the configuration are skipped along with the ones the plan skips.
+func (r *Runner) PlanScan(plan *tfplan.Plan) *ScanResult {
+	scan := &ScanResult{
+		Results: make(map[string]*inspector.InspectResult),
+		Skipped: plan.Skipped,
+	}
+
+	for _, resource := range plan.Resources {
+		if !r.config.Resources[resource.Type].Enabled {
+			scan.Skipped = append(scan.Skipped, tfplan.SkippedResource{
+				Address:       resource.Address,
+				TerraformType: resource.TerraformType,
+				Reason:        fmt.Sprintf("resource type %s is not enabled in the configuration", resource.Type),
+			})
+			continue
+		}
+
+		result, ok := scan.Results[resource.Type]
+		if !ok {
+			result = &inspector.InspectResult{}
+			scan.Results[resource.Type] = result
+		}
+
+		metadata := inspector.ResourceMetadata{
+			ID:       resource.Address,
+			Type:     resource.Type,
+			Provider: "aws",
+			Tags:     resource.Tags,
+		}
+		metadata.Details.Name = resource.Address
+		result.Resources = append(result.Resources, metadata)
+		result.TotalResources++
+	}
+
+	return scan
+}
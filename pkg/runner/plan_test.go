@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/tfplan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner_PlanScan(t *testing.T) {
+	t.Parallel()
+
+	cfg := newTestConfig()
+	cfg.Resources = map[string]configuration.ResourceConfig{
+		"s3":  {Enabled: true},
+		"ec2": {Enabled: false},
+	}
+	runner, err := New(cfg, Options{})
+	require.NoError(t, err)
+
+	plan := &tfplan.Plan{
+		Kind: tfplan.KindPlan,
+		Resources: []tfplan.Resource{
+			{Address: "aws_s3_bucket.logs", TerraformType: "aws_s3_bucket", Type: "s3", Action: "create", Tags: map[string]string{"Environment": "prod", "Owner": "platform"}},
+			{Address: "aws_s3_bucket.scratch", TerraformType: "aws_s3_bucket", Type: "s3", Action: "update", Tags: map[string]string{}},
+			{Address: "aws_instance.web", TerraformType: "aws_instance", Type: "ec2", Action: "create", Tags: map[string]string{}},
+		},
+		Skipped: []tfplan.SkippedResource{
+			{Address: "aws_iam_role.deploy", TerraformType: "aws_iam_role", Reason: tfplan.SkipReasonUnmapped},
+		},
+	}
+
+	scan := runner.PlanScan(plan)
+	require.Contains(t, scan.Results, "s3")
+	assert.NotContains(t, scan.Results, "ec2")
+	assert.Equal(t, 2, scan.Results["s3"].TotalResources)
+	assert.Equal(t, []tfplan.SkippedResource{
+		{Address: "aws_iam_role.deploy", TerraformType: "aws_iam_role", Reason: tfplan.SkipReasonUnmapped},
+		{Address: "aws_instance.web", TerraformType: "aws_instance", Reason: "resource type ec2 is not enabled in the configuration"},
+	}, scan.Skipped)
+
+	report := mustReport(t, runner, scan)
+	assert.Equal(t, 2, report.Summary.TotalResources)
+	assert.Equal(t, 1, report.Summary.CompliantResources)
+	assert.Equal(t, 1, report.Summary.NonCompliantResources)
+	assert.Equal(t, scan.Skipped, report.Skipped)
+
+	results := make(map[string]bool)
+	for _, result := range report.ResourceResults {
+		results[result.ResourceID] = result.IsCompliant
+	}
+	assert.Equal(t, map[string]bool{"aws_s3_bucket.logs": true, "aws_s3_bucket.scratch": false}, results)
+}
@@ -0,0 +1,190 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	cmdbResourceType    = "acme-cmdb"
+	offlineResourceType = "acme-offline"
+)
+
+// cmdbInspector lists the servers of a fake CMDB, standing in for an inspector provided
+// outside aws-taggy
+type cmdbInspector struct {
+	regions []string
+}
+
+func init() {
+	inspector.RegisterInspector(cmdbResourceType, func(regions []string) (inspector.Inspector, error) {
+		return &cmdbInspector{regions: regions}, nil
+	})
+	inspector.RegisterInspector(offlineResourceType, func([]string) (inspector.Inspector, error) {
+		return offlineInspector{}, nil
+	})
+}
+
+// offlineInspector stands in for an inspector provided outside aws-taggy whose backend
+// cannot be reached
+type offlineInspector struct{}
+
+func (offlineInspector) Inspect(_ context.Context, _ configuration.TaggyScanConfig) (*inspector.InspectResult, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (offlineInspector) Fetch(_ context.Context, arn string, _ configuration.TaggyScanConfig) (*inspector.ResourceMetadata, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (c *cmdbInspector) Inspect(_ context.Context, _ configuration.TaggyScanConfig) (*inspector.InspectResult, error) {
+	servers := map[string]map[string]string{
+		"web-01":   {"Environment": "prod", "Owner": "web"},
+		"batch-07": {"Environment": "prod"},
+	}
+
+	result := &inspector.InspectResult{Region: c.regions[0]}
+	for id, tags := range servers {
+		resource := inspector.ResourceMetadata{ID: id, Type: cmdbResourceType, Region: c.regions[0], Tags: tags}
+		resource.Details.ARN = "cmdb:server/" + id
+		result.Resources = append(result.Resources, resource)
+	}
+	result.TotalResources = len(result.Resources)
+	return result, nil
+}
+
+func (c *cmdbInspector) Fetch(_ context.Context, arn string, _ configuration.TaggyScanConfig) (*inspector.ResourceMetadata, error) {
+	return nil, fmt.Errorf("fetching %s is not supported", arn)
+}
+
+func TestRunnerRunsRegisteredInspectors(t *testing.T) {
+	t.Parallel()
+
+	assert.Contains(t, inspector.RegisteredResourceTypes(), cmdbResourceType)
+	assert.Panics(t, func() {
+		inspector.RegisterInspector(cmdbResourceType, func([]string) (inspector.Inspector, error) { return &cmdbInspector{}, nil })
+	})
+
+	config := newTestConfig()
+	config.AWS.Regions = configuration.RegionsConfig{Mode: "specific", List: []string{"eu-west-1"}}
+	config.Resources = map[string]configuration.ResourceConfig{
+		cmdbResourceType: {Enabled: true},
+	}
+
+	runner, err := New(config, Options{})
+	require.NoError(t, err)
+
+	report, err := runner.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.Summary.TotalResources)
+	assert.Equal(t, 1, report.Summary.CompliantResources)
+	assert.Equal(t, 1, report.Summary.NonCompliantResources)
+	for _, result := range report.ResourceResults {
+		assert.Equal(t, cmdbResourceType, result.ResourceType)
+		assert.Equal(t, "eu-west-1", result.Region)
+		assert.Equal(t, result.ResourceID == "web-01", result.IsCompliant, result.ResourceID)
+	}
+}
+
+func TestRunnerStreamScan(t *testing.T) {
+	t.Parallel()
+
+	config := newTestConfig()
+	config.AWS.Regions = configuration.RegionsConfig{Mode: "specific", List: []string{"eu-west-1"}}
+	config.Resources = map[string]configuration.ResourceConfig{
+		cmdbResourceType: {Enabled: true},
+	}
+
+	runner, err := New(config, Options{Resource: "batch-07"})
+	require.NoError(t, err)
+
+	var streamed []*ResourceResult
+	scan, summary, err := runner.StreamScan(context.Background(), func(result *ResourceResult) error {
+		streamed = append(streamed, result)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Results are handed over as the inspectors complete, none is kept in the scan
+	assert.Empty(t, scan.Results)
+	require.Len(t, streamed, 1)
+	assert.Equal(t, "batch-07", streamed[0].ResourceID)
+	assert.False(t, streamed[0].IsCompliant)
+	assert.Equal(t, 1, summary.TotalResources)
+	assert.Equal(t, 1, summary.NonCompliantResources)
+	assert.Equal(t, 1, summary.RuleResults["required_tags"].Failures)
+
+	_, _, err = runner.StreamScan(context.Background(), func(*ResourceResult) error {
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestRunnerScanFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		resourceTypes []string
+		strictScan    bool
+		expectedErr   bool
+	}{
+		{
+			name:          "Partial Failure",
+			resourceTypes: []string{cmdbResourceType, offlineResourceType},
+		},
+		{
+			name:          "Total Failure",
+			resourceTypes: []string{offlineResourceType},
+			expectedErr:   true,
+		},
+		{
+			name:          "Partial Failure With Strict Scan",
+			resourceTypes: []string{cmdbResourceType, offlineResourceType},
+			strictScan:    true,
+			expectedErr:   true,
+		},
+		{
+			name:          "Strict Scan Without Failure",
+			resourceTypes: []string{cmdbResourceType},
+			strictScan:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := newTestConfig()
+			config.AWS.Regions = configuration.RegionsConfig{Mode: "specific", List: []string{"eu-west-1"}}
+			config.Resources = map[string]configuration.ResourceConfig{}
+			for _, resourceType := range tt.resourceTypes {
+				config.Resources[resourceType] = configuration.ResourceConfig{Enabled: true}
+			}
+
+			runner, err := New(config, Options{StrictScan: tt.strictScan})
+			require.NoError(t, err)
+
+			report, err := runner.Run(context.Background())
+			if tt.expectedErr {
+				require.ErrorContains(t, err, "connection refused")
+				return
+			}
+			require.NoError(t, err)
+
+			// The resources of the inspectors that succeeded are validated, the failures reported
+			assert.Equal(t, 2, report.Summary.TotalResources)
+			failedOffline := strings.Contains(strings.Join(report.Summary.ScanErrors, "\n"), offlineResourceType)
+			assert.Equal(t, len(tt.resourceTypes) > 1, failedOffline, report.Summary.ScanErrors)
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package runner
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/Excoriate/aws-taggy/pkg/manifest"
+	"github.com/Excoriate/aws-taggy/pkg/tfplan"
+)
+
+// ComplianceReport is the outcome of a compliance run: the result of every validated
+// resource, the results of the compliance rules and the summary of the run
+type ComplianceReport struct {
+	Summary         Summary                `json:"summary" yaml:"summary"`
+	ResourceResults []*ResourceResult      `json:"resource_results" yaml:"resource_results"`
+	ValidationRules map[string]*RuleResult `json:"validation_rules" yaml:"validation_rules"`
+
+	// Errors are the resource types that could not be scanned, whose resources are missing
+	// from the results
+	Errors []inspector.ServiceError `json:"errors,omitempty" yaml:"errors,omitempty"`
+
+	// Skipped are the resources of a Terraform plan or state whose tags were not validated,
+	// such as those of a Terraform type without an aws-taggy resource type
+	Skipped []tfplan.SkippedResource `json:"skipped_resources,omitempty" yaml:"skipped_resources,omitempty"`
+
+	// Manifest records how the run was made, for audits and compliance check --replay
+	Manifest *manifest.Manifest `json:"manifest,omitempty" yaml:"manifest,omitempty"`
+}
+
+// ResourceResult is the tag compliance validation result of a resource
+type ResourceResult struct {
+	IsCompliant     bool              `json:"is_compliant" yaml:"is_compliant"`
+	IsUnknown       bool              `json:"is_unknown,omitempty" yaml:"is_unknown,omitempty"`
+	ResourceTags    map[string]string `json:"resource_tags" yaml:"resource_tags"`
+	Violations      []Violation       `json:"violations,omitempty" yaml:"violations,omitempty"`
+	ComplianceLevel string            `json:"compliance_level,omitempty" yaml:"compliance_level,omitempty"`
+	ResourceID      string            `json:"resource_id" yaml:"resource_id"`
+	ResourceType    string            `json:"resource_type" yaml:"resource_type"`
+	ResourceARN     string            `json:"resource_arn,omitempty" yaml:"resource_arn,omitempty"`
+	AccountID       string            `json:"account_id,omitempty" yaml:"account_id,omitempty"`
+	Region          string            `json:"region,omitempty" yaml:"region,omitempty"`
+	Score           float64           `json:"score" yaml:"score"`
+
+	// TagFetchError is the error reading the tags of the resource, whose compliance is then unknown
+	TagFetchError string `json:"tag_fetch_error,omitempty" yaml:"tag_fetch_error,omitempty"`
+
+	// ChangeMarker is the provider-supplied indicator of the last change of the resource, and
+	// FromSnapshot reports that an incremental scan reused the tags of the previous run
+	ChangeMarker string `json:"change_marker,omitempty" yaml:"change_marker,omitempty"`
+	FromSnapshot bool   `json:"from_snapshot,omitempty" yaml:"from_snapshot,omitempty"`
+
+	SuppressedViolations []Violation `json:"suppressed_violations,omitempty" yaml:"suppressed_violations,omitempty"`
+
+	// Warnings are findings left out of the compliance status and score, such as a
+	// deprecated allowed value
+	Warnings []Violation `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+
+	// Fix is the tag change fixing the auto-fixable violations of the resource
+	Fix *compliance.TagFix `json:"fix,omitempty" yaml:"fix,omitempty"`
+
+	// SkippedRules lists the built-in rule categories disabled for the resource type, which
+	// were not evaluated on the resource
+	SkippedRules []string `json:"skipped_rules,omitempty" yaml:"skipped_rules,omitempty"`
+
+	// RawResponse is the API response describing the resource, set with the IncludeRaw option
+	RawResponse map[string]interface{} `json:"raw_response,omitempty" yaml:"raw_response,omitempty"`
+}
+
+// Violation is a tag compliance violation of a resource
+type Violation struct {
+	Type     string `json:"type" yaml:"type"`
+	Message  string `json:"message" yaml:"message"`
+	TagKey   string `json:"tag_key,omitempty" yaml:"tag_key,omitempty"`
+	Value    string `json:"value,omitempty" yaml:"value,omitempty"`
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Note     string `json:"note,omitempty" yaml:"note,omitempty"`
+
+	// Expected is the expectation the tag fails, such as the required pattern, the allowed
+	// values or the limit it exceeds
+	Expected string `json:"expected,omitempty" yaml:"expected,omitempty"`
+
+	// SuggestedValue is a value satisfying the broken rule, such as the closest allowed value
+	SuggestedValue string `json:"suggested_value,omitempty" yaml:"suggested_value,omitempty"`
+
+	// DocURL links to the documentation of the broken rule
+	DocURL string `json:"doc_url,omitempty" yaml:"doc_url,omitempty"`
+
+	// AutoFixable is set when the fix of the resource fixes the violation
+	AutoFixable bool `json:"auto_fixable,omitempty" yaml:"auto_fixable,omitempty"`
+
+	// RelatedResources lists the ARN, or the ID, of the other resources involved in a
+	// violation of a cross-resource rule, such as those sharing a tag value that must be unique
+	RelatedResources []string `json:"related_resources,omitempty" yaml:"related_resources,omitempty"`
+}
+
+// Describe returns the message of the violation followed, when the violation has them, by
+// the failing value and the suggested value, e.g. "... got 'Prod' — did you mean 'production'?"
+func (v Violation) Describe() string {
+	description := v.Message
+	if v.Value != "" {
+		description = fmt.Sprintf("%s, got '%s'", description, v.Value)
+	}
+	if v.SuggestedValue != "" {
+		description = fmt.Sprintf("%s — did you mean '%s'?", description, v.SuggestedValue)
+	}
+	return description
+}
+
+// Summary provides an overview of the results of a compliance run
+type Summary struct {
+	TotalResources        int                      `json:"total_resources" yaml:"total_resources"`
+	CompliantResources    int                      `json:"compliant_resources" yaml:"compliant_resources"`
+	NonCompliantResources int                      `json:"non_compliant_resources" yaml:"non_compliant_resources"`
+	UnknownResources      int                      `json:"unknown_resources" yaml:"unknown_resources"`
+	ExcludedResources     int                      `json:"excluded_resources" yaml:"excluded_resources"`
+	SuppressedViolations  int                      `json:"suppressed_violations" yaml:"suppressed_violations"`
+	Warnings              int                      `json:"warnings" yaml:"warnings"`
+	AutoFixableViolations int                      `json:"auto_fixable_violations" yaml:"auto_fixable_violations"`
+	ManualViolations      int                      `json:"manual_violations" yaml:"manual_violations"`
+	ComplianceScore       float64                  `json:"compliance_score" yaml:"compliance_score"`
+	ComplianceLevels      map[string]int           `json:"compliance_levels,omitempty" yaml:"compliance_levels,omitempty"`
+	Exclusions            []ExcludedResource       `json:"exclusions,omitempty" yaml:"exclusions,omitempty"`
+	GlobalViolations      map[string]int           `json:"global_violations,omitempty" yaml:"global_violations,omitempty"`
+	RuleResults           map[string]*RuleResult   `json:"rule_results,omitempty" yaml:"rule_results,omitempty"`
+	GroupBy               string                   `json:"group_by,omitempty" yaml:"group_by,omitempty"`
+	Groups                map[string]*GroupSummary `json:"groups,omitempty" yaml:"groups,omitempty"`
+	ScanErrors            []string                 `json:"scan_errors,omitempty" yaml:"scan_errors,omitempty"`
+	TruncatedResults      []string                 `json:"truncated_results,omitempty" yaml:"truncated_results,omitempty"`
+	ScanMetadata          *ScanMetadata            `json:"scan_metadata,omitempty" yaml:"scan_metadata,omitempty"`
+	Incremental           *IncrementalSummary      `json:"incremental,omitempty" yaml:"incremental,omitempty"`
+	DeletedResources      []DeletedResource        `json:"deleted_resources,omitempty" yaml:"deleted_resources,omitempty"`
+	Coverage              []TagCoverage            `json:"coverage,omitempty" yaml:"coverage,omitempty"`
+	Sample                *SampleSummary           `json:"sample,omitempty" yaml:"sample,omitempty"`
+}
+
+// Truncated reports whether a scan limit cut the results of the run short, in which case
+// the counters only cover part of the resources
+func (s Summary) Truncated() bool {
+	return len(s.TruncatedResults) > 0
+}
+
+// ScoredResources returns the number of resources of known compliance, over which the
+// compliance score is averaged
+func (s Summary) ScoredResources() int {
+	return s.TotalResources - s.UnknownResources
+}
+
+// Badge returns the compliance badge of the run, unknown when no resource was scored
+func (s Summary) Badge(thresholds configuration.BadgeThresholds) compliance.Badge {
+	return compliance.NewBadge(s.ComplianceScore, s.ScoredResources(), thresholds)
+}
+
+// BelowComplianceLevel returns the number of resources of known compliance meeting neither
+// the minimum compliance level nor a stricter one
+func (s Summary) BelowComplianceLevel(minimum compliance.ComplianceLevel) int {
+	below := 0
+	for level, count := range s.ComplianceLevels {
+		if !compliance.ComplianceLevel(level).Meets(minimum) {
+			below += count
+		}
+	}
+	return below
+}
+
+// FixPlan returns the fix plan entries of the results with an auto-fixable violation,
+// leaving out the resources without an ARN, which a tag change cannot address
+func FixPlan(results []*ResourceResult) []compliance.FixPlanEntry {
+	var entries []compliance.FixPlanEntry
+	for _, result := range results {
+		if entry, ok := result.FixPlanEntry(); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// FixPlanEntry returns the fix plan entry of the resource, false when it has no fix or no ARN
+func (r *ResourceResult) FixPlanEntry() (compliance.FixPlanEntry, bool) {
+	if r.Fix == nil || r.ResourceARN == "" {
+		return compliance.FixPlanEntry{}, false
+	}
+	return compliance.FixPlanEntry{
+		ARN:     r.ResourceARN,
+		Service: r.ResourceType,
+		Set:     r.Fix.Set,
+		Unset:   r.Fix.Unset,
+	}, true
+}
+
+// IncrementalSummary counts how the resources of an incremental scan were inspected
+type IncrementalSummary struct {
+	// FromSnapshot is the number of resources whose tags were reused from the previous run
+	FromSnapshot int `json:"from_snapshot" yaml:"from_snapshot"`
+
+	// Inspected is the number of resources whose tags were read from AWS
+	Inspected int `json:"inspected" yaml:"inspected"`
+
+	// Deleted is the number of resources of the previous run no longer discovered
+	Deleted int `json:"deleted" yaml:"deleted"`
+}
+
+// DeletedResource is a resource recorded by the previous run of an incremental scan that
+// the scan did not discover again
+type DeletedResource struct {
+	ResourceID   string `json:"resource_id" yaml:"resource_id"`
+	ResourceType string `json:"resource_type" yaml:"resource_type"`
+	ResourceARN  string `json:"resource_arn,omitempty" yaml:"resource_arn,omitempty"`
+	AccountID    string `json:"account_id,omitempty" yaml:"account_id,omitempty"`
+	Region       string `json:"region,omitempty" yaml:"region,omitempty"`
+}
+
+// ScanMetadata records how the checked resources were selected, so a check can be reproduced,
+// the AWS identity they were scanned with and how AWS throttled the scan
+type ScanMetadata struct {
+	AccountID   string   `json:"account_id,omitempty" yaml:"account_id,omitempty"`
+	CallerARN   string   `json:"caller_arn,omitempty" yaml:"caller_arn,omitempty"`
+	TagFilters  []string `json:"tag_filters,omitempty" yaml:"tag_filters,omitempty"`
+	NameFilters []string `json:"name_filters,omitempty" yaml:"name_filters,omitempty"`
+
+	// Identities are the ARN of the identity that scanned every resource type, keyed like
+	// the results, when any resource type was scanned by assuming a role
+	Identities map[string]string `json:"identities,omitempty" yaml:"identities,omitempty"`
+
+	// Throttles is the number of attempts of AWS API calls throttled during the scan, set
+	// when any attempt was, and EffectiveConcurrency the number of concurrent operations the
+	// scan of AWS ended with, lower than the configured one after backing off from throttling
+	Throttles            int64 `json:"throttles,omitempty" yaml:"throttles,omitempty"`
+	EffectiveConcurrency int   `json:"effective_concurrency,omitempty" yaml:"effective_concurrency,omitempty"`
+}
+
+// GroupSummary provides compliance counts for the resources sharing a grouping key
+type GroupSummary struct {
+	TotalResources        int            `json:"total_resources" yaml:"total_resources"`
+	CompliantResources    int            `json:"compliant_resources" yaml:"compliant_resources"`
+	NonCompliantResources int            `json:"non_compliant_resources" yaml:"non_compliant_resources"`
+	UnknownResources      int            `json:"unknown_resources,omitempty" yaml:"unknown_resources,omitempty"`
+	ViolationTypes        map[string]int `json:"violation_types,omitempty" yaml:"violation_types,omitempty"`
+}
+
+// ExcludedResource represents a resource skipped by a configured exclusion pattern
+type ExcludedResource struct {
+	ResourceID   string `json:"resource_id" yaml:"resource_id"`
+	ResourceType string `json:"resource_type" yaml:"resource_type"`
+	Pattern      string `json:"pattern" yaml:"pattern"`
+	Reason       string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// RuleResult represents the result of a specific compliance rule
+type RuleResult struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+
+	// Passed reports that no resource the rule was evaluated on failed it. A rule skipped on
+	// every resource was evaluated on none and did not pass.
+	Passed   bool `json:"passed" yaml:"passed"`
+	Failures int  `json:"failures" yaml:"failures"`
+
+	// FailuresByResourceType counts the failures of the rule by resource type
+	FailuresByResourceType map[string]int `json:"failures_by_resource_type,omitempty" yaml:"failures_by_resource_type,omitempty"`
+
+	// Skipped counts the resources the rule was not evaluated on, their resource type
+	// disabling it with disabled_rules, which are neither passes nor failures.
+	// SkippedByResourceType counts them by resource type.
+	Skipped               int            `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+	SkippedByResourceType map[string]int `json:"skipped_by_resource_type,omitempty" yaml:"skipped_by_resource_type,omitempty"`
+
+	// ExampleResources are the ARNs, or the IDs of the resources without one, of up to
+	// Options.RuleExamples resources failing the rule: the first ones in alphabetical order,
+	// so runs over the same resources list the same examples
+	ExampleResources []string `json:"example_resources,omitempty" yaml:"example_resources,omitempty"`
+}
+
+// TopResourceType returns the resource type failing the rule the most and its number of
+// failures, the first in alphabetical order on ties, or an empty string when the rule passed
+func (r *RuleResult) TopResourceType() (string, int) {
+	var top string
+	var failures int
+	for resourceType, count := range r.FailuresByResourceType {
+		if count > failures || (count == failures && resourceType < top) {
+			top, failures = resourceType, count
+		}
+	}
+	return top, failures
+}
+
+// IsSkipped reports whether the rule was skipped on every resource it applied to, having
+// been evaluated on none of them
+func (r *RuleResult) IsSkipped() bool {
+	return !r.Passed && r.Failures == 0 && r.Skipped > 0
+}
+
+// addExample records a resource failing the rule among its examples, keeping the first
+// limit resources in alphabetical order
+func (r *RuleResult) addExample(resource string, limit int) {
+	i, found := slices.BinarySearch(r.ExampleResources, resource)
+	if found || i >= limit {
+		return
+	}
+	r.ExampleResources = slices.Insert(r.ExampleResources, i, resource)
+	if len(r.ExampleResources) > limit {
+		r.ExampleResources = r.ExampleResources[:limit]
+	}
+}
@@ -0,0 +1,1054 @@
+// Package runner checks the tag compliance of AWS resources: it scans the resources enabled
+// in a configuration, validates their tags and summarizes the results. It is the engine of
+// the compliance check command, and lets Go programs get the same results without the CLI.
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/Excoriate/aws-taggy/pkg/o11y"
+	"github.com/Excoriate/aws-taggy/pkg/tfplan"
+)
+
+// Options tune a compliance run, the zero value checks every resource enabled in the configuration
+type Options struct {
+	// Cache reuses the scan results it holds, nil always scans AWS
+	Cache *inspector.ScanCache
+
+	// Resource restricts the run to the resources with this ID, ARN or name
+	Resource string
+
+	// TagSelectors restrict the run to the resources whose tags match every selector
+	TagSelectors []inspector.TagSelector
+
+	// AgeFilter restricts the run to the resources created in its window
+	AgeFilter inspector.AgeFilter
+
+	// NameFilter restricts the run to the resources whose ID, name or ARN match its expressions
+	NameFilter inspector.NameFilter
+
+	// Suppressions accept violations, which then do not count against compliance
+	Suppressions *compliance.Suppressions
+
+	// GroupBy aggregates the summary by account, region, type or the value of a tag (tag:<key>)
+	GroupBy string
+
+	// IncludeRaw adds the raw API response of every resource to its result
+	IncludeRaw bool
+
+	// TreatUnreadableAsNonCompliant validates the resources whose tags could not be read as
+	// untagged resources, instead of reporting their compliance as unknown
+	TreatUnreadableAsNonCompliant bool
+
+	// ValidationWorkers is the number of goroutines validating the scanned resources,
+	// GOMAXPROCS when zero
+	ValidationWorkers int
+
+	// Concurrency caps the concurrent AWS operations of the scan and the workers of every
+	// inspector, overriding the max_concurrency and workers settings of the configuration,
+	// which apply when zero
+	Concurrency int
+
+	// Previous makes the scan incremental: the tags recorded by the previous run are reused
+	// for the resources whose change marker is unchanged, and the resources of the previous
+	// run that are no longer discovered are reported as deleted. Nil scans every resource.
+	Previous *inspector.PreviousScan
+
+	// StrictScan fails the scan when any resource type cannot be scanned, instead of only
+	// when every resource type fails
+	StrictScan bool
+
+	// RuleExamples is the number of resources failing each rule listed in its result,
+	// DefaultRuleExamples when zero and none when negative
+	RuleExamples int
+
+	// Sample validates a random sample of the resources discovered by every inspector,
+	// the summary extrapolating its counters to every discovered resource. Sampled scans
+	// bypass the cache and cannot be incremental. The zero value validates every resource.
+	Sample inspector.Sample
+}
+
+// DefaultRuleExamples is the number of resources failing each rule listed in its result
+// when the options leave it unset
+const DefaultRuleExamples = 5
+
+// ruleExamples returns the number of resources failing each rule listed in its result
+func (o Options) ruleExamples() int {
+	switch {
+	case o.RuleExamples == 0:
+		return DefaultRuleExamples
+	case o.RuleExamples < 0:
+		return 0
+	}
+	return o.RuleExamples
+}
+
+// ScanResult holds the resources scanned for a compliance run
+type ScanResult struct {
+	// Results of the inspection, by resource type
+	Results map[string]*inspector.InspectResult
+
+	// Errors of the accounts that could not be scanned
+	Errors []string
+
+	// ServiceErrors are the resource types that could not be scanned, by account and region
+	ServiceErrors []inspector.ServiceError
+
+	// Identity is the account and principal of the credentials of the scan, nil when it could
+	// not be resolved
+	Identity *inspector.CallerIdentity
+
+	// Identities are the ARN of the identity that scanned every resource type, keyed like the
+	// results, nil when every resource type was scanned with the default credentials
+	Identities map[string]string
+
+	// Incremental reports that the scan reused the previous run of the options, whose
+	// resources no longer discovered are listed in Deleted
+	Incremental bool
+	Deleted     []DeletedResource
+
+	// Truncated lists the keys of the results cut short by the max_resources_per_service or
+	// max_api_calls limit of the configuration, as returned by inspector.ResultKey
+	Truncated []string
+
+	// Skipped are the resources of a Terraform plan or state whose tags are not validated,
+	// see PlanScan
+	Skipped []tfplan.SkippedResource
+
+	// Sampling counts the resources discovered and sampled by a sampled scan, before the
+	// filters of the options, nil when the scan is not sampled
+	Sampling *inspector.SampleInfo
+
+	// Throttles is the number of attempts of AWS API calls throttled during the scan, and
+	// EffectiveConcurrency the number of concurrent operations the scan ended with
+	Throttles            int64
+	EffectiveConcurrency int
+}
+
+// Runner scans the resources enabled in a configuration and validates their tags
+type Runner struct {
+	config    *configuration.TaggyScanConfig
+	options   Options
+	validator *compliance.TagValidator
+}
+
+// New creates a Runner for a loaded and validated configuration
+func New(config *configuration.TaggyScanConfig, options Options) (*Runner, error) {
+	if config == nil {
+		return nil, fmt.Errorf("configuration cannot be nil")
+	}
+
+	if options.GroupBy != "" {
+		if err := ValidateGroupBy(options.GroupBy); err != nil {
+			return nil, err
+		}
+	}
+
+	// Resources left out of a sample would be reported as deleted
+	if !options.Sample.IsZero() && options.Previous != nil {
+		return nil, fmt.Errorf("a sampled scan cannot be incremental")
+	}
+
+	validator := compliance.NewTagValidator(config)
+	validator.SetSuppressions(options.Suppressions)
+	validator.SetTreatUnreadableAsNonCompliant(options.TreatUnreadableAsNonCompliant)
+	validator.SetBatchWorkers(options.ValidationWorkers)
+
+	return &Runner{
+		config:    config,
+		options:   options,
+		validator: validator,
+	}, nil
+}
+
+// Run checks the tag compliance of every resource enabled in the configuration
+func Run(ctx context.Context, config *configuration.TaggyScanConfig) (*ComplianceReport, error) {
+	runner, err := New(config, Options{})
+	if err != nil {
+		return nil, err
+	}
+	return runner.Run(ctx)
+}
+
+// Run scans the resources and returns the result of each of them along with the summary.
+// The scan is bounded by the context, use context.WithTimeout to limit its duration.
+func (r *Runner) Run(ctx context.Context) (*ComplianceReport, error) {
+	scan, err := r.Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.Report(ctx, scan)
+}
+
+// Scan scans the resources enabled in the configuration, keeping the ones selected by the
+// resource and tag filters of the options. Accounts and resource types that cannot be
+// scanned are reported in the errors of the result without failing the scan, unless every
+// resource type fails or the options make the scan strict.
+func (r *Runner) Scan(ctx context.Context) (*ScanResult, error) {
+	logger := o11y.DefaultLogger()
+
+	inspectorMgr, identity, err := r.newInspectorManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("🔍 Scanning AWS resources...")
+	if err := r.inspect(ctx, inspectorMgr); err != nil {
+		return nil, err
+	}
+
+	scanErrors := inspectorMgr.GetErrors()
+	for _, scanErr := range scanErrors {
+		logger.Warn(scanErr)
+	}
+
+	aggregate := inspectorMgr.Results()
+	results := aggregate.ByService
+
+	// Deletions are told from the whole discovery, before the filters narrow it down
+	discovered := newDiscovery(identity)
+	for key, result := range results {
+		discovered.add(key, result)
+	}
+	truncated := inspector.TruncatedResults(results)
+	deleted := r.deletedResources(discovered)
+	sampling := sampledResources(results)
+
+	if r.options.Resource != "" {
+		logger.Info(fmt.Sprintf("🔍 Filtering resources matching: %s", r.options.Resource))
+		results = FilterByResource(results, r.options.Resource)
+		if len(results) == 0 {
+			return nil, fmt.Errorf("no resources found matching the resource filter: %s", r.options.Resource)
+		}
+
+		var matched int
+		for _, result := range results {
+			matched += len(result.Resources)
+		}
+		logger.Info(fmt.Sprintf("✅ Found %d resources matching the filter", matched))
+	}
+
+	// Only the resources selected by their tags are validated and counted in the summary
+	if len(r.options.TagSelectors) > 0 {
+		results = inspector.FilterByTagSelectors(results, r.options.TagSelectors)
+		logger.Info(fmt.Sprintf("🏷️  Checking resources matching the tag filters: %s", strings.Join(newScanMetadata(r.options, nil, nil).TagFilters, ", ")))
+	}
+
+	// Resources created outside the age window are left out before validation
+	if !r.options.AgeFilter.IsZero() {
+		results = inspector.FilterByAge(results, r.options.AgeFilter)
+		logger.Info("📅 Checking resources matching the creation time filters")
+	}
+
+	// Resources left out by their ID, name or ARN are not counted in the summary either
+	if !r.options.NameFilter.IsZero() {
+		results = inspector.FilterByName(results, r.options.NameFilter)
+		logger.Info(fmt.Sprintf("🔎 Checking resources matching the name filters: %s", strings.Join(r.options.NameFilter.Filters(), ", ")))
+	}
+
+	return &ScanResult{
+		Results:       results,
+		Errors:        scanErrors,
+		ServiceErrors: aggregate.Errors,
+		Identity:      identity,
+		Identities:    inspectorMgr.Identities(callerARN(identity)),
+		Incremental:   r.options.Previous != nil,
+		Deleted:       deleted,
+		Truncated:     truncated,
+		Sampling:      sampling,
+
+		Throttles:            inspectorMgr.Throttles(),
+		EffectiveConcurrency: inspectorMgr.EffectiveConcurrency(),
+	}, nil
+}
+
+// inspect runs the inspectors of the manager, tolerating the resource types that fail
+// while others succeed unless the scan is strict
+func (r *Runner) inspect(ctx context.Context, inspectorMgr *inspector.InspectorManager) error {
+	err := inspectorMgr.Inspect(ctx)
+	if err == nil {
+		return nil
+	}
+
+	var failures *inspector.ServiceFailuresError
+	if errors.As(err, &failures) && !failures.AllFailed() && !r.options.StrictScan {
+		return nil
+	}
+	return fmt.Errorf("failed to scan AWS resources: %w. Check AWS credentials, permissions, and network connectivity", err)
+}
+
+// StreamScan scans the resources like Scan, validating the resources of every inspector as
+// soon as it completes and handing each result to fn. The resources of an inspector, raw
+// responses included, are dropped once validated, so memory usage does not grow with the
+// number of resources. The returned scan holds the errors, identity and deleted resources
+// of the run but no resources, and the summary is built from the streamed results.
+func (r *Runner) StreamScan(ctx context.Context, fn func(*ResourceResult) error) (*ScanResult, Summary, error) {
+	logger := o11y.DefaultLogger()
+
+	inspectorMgr, identity, err := r.newInspectorManager(ctx)
+	if err != nil {
+		return nil, Summary{}, err
+	}
+
+	if len(r.config.CrossResourceRules) > 0 {
+		logger.Warn("cross_resource_rules are not checked when streaming, every resource would have to be kept until the scan completes")
+	}
+
+	builder := newSummaryBuilder(r.options, len(r.config.ComplianceLevels) > 0, r.config.RequiredTagKeys())
+	discovered := newDiscovery(identity)
+	var truncated []string
+	var sampling *inspector.SampleInfo
+	inspectorMgr.SetResultHandler(func(key string, result *inspector.InspectResult) error {
+		discovered.add(key, result)
+		if result.Truncated {
+			truncated = append(truncated, key)
+		}
+		if result.Sampling != nil {
+			sampling = addSampling(sampling, result.Sampling)
+		}
+		for _, selected := range r.selectResults(map[string]*inspector.InspectResult{key: result}) {
+			builder.addExclusions(selected)
+			builder.setSampling(selected.Sampling)
+			if err := r.validateResources(ctx, selected.Resources, nil, builder, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	logger.Info("🔍 Scanning and validating AWS resources...")
+	if err := r.inspect(ctx, inspectorMgr); err != nil {
+		return nil, Summary{}, err
+	}
+
+	scanErrors := inspectorMgr.GetErrors()
+	for _, scanErr := range scanErrors {
+		logger.Warn(scanErr)
+	}
+
+	if r.options.Resource != "" && builder.summary.TotalResources == 0 && len(builder.exclusions) == 0 {
+		return nil, Summary{}, fmt.Errorf("no resources found matching the resource filter: %s", r.options.Resource)
+	}
+
+	slices.Sort(truncated)
+	scan := &ScanResult{
+		Results:       map[string]*inspector.InspectResult{},
+		Errors:        scanErrors,
+		ServiceErrors: inspectorMgr.GetServiceErrors(),
+		Identity:      identity,
+		Identities:    inspectorMgr.Identities(callerARN(identity)),
+		Incremental:   r.options.Previous != nil,
+		Deleted:       r.deletedResources(discovered),
+		Truncated:     truncated,
+		Sampling:      sampling,
+
+		Throttles:            inspectorMgr.Throttles(),
+		EffectiveConcurrency: inspectorMgr.EffectiveConcurrency(),
+	}
+	return scan, builder.build(scan, r.options), nil
+}
+
+// Estimate measures the size of a scan of the resources enabled in the configuration by
+// discovering them without reading their tags, keyed by inspector.ResultKey. Accounts
+// that cannot be estimated are reported in the returned errors.
+func (r *Runner) Estimate(ctx context.Context) (map[string]*inspector.ScanEstimate, []string, error) {
+	inspectorMgr, _, err := r.newInspectorManager(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	o11y.DefaultLogger().Info("📏 Estimating the size of the scan...")
+	estimates, err := inspectorMgr.Estimate(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to estimate the scan of AWS resources: %w", err)
+	}
+
+	return estimates, inspectorMgr.GetErrors(), nil
+}
+
+// Preflight checks the permissions a scan of the resources enabled in the configuration
+// needs, probing every resource type in each of its regions with its cheapest read calls.
+// No resource is modified.
+func (r *Runner) Preflight(ctx context.Context) ([]inspector.ProbeResult, error) {
+	inspectorMgr, _, err := r.newInspectorManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o11y.DefaultLogger().Info("🩺 Probing the permissions of the scan...")
+	results, err := inspectorMgr.Preflight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe the permissions of the scan: %w", err)
+	}
+	return results, nil
+}
+
+// newInspectorManager creates the inspector manager of the run and resolves the identity of
+// the scan, which is nil when it cannot be resolved
+func (r *Runner) newInspectorManager(ctx context.Context) (*inspector.InspectorManager, *inspector.CallerIdentity, error) {
+	logger := o11y.DefaultLogger()
+
+	inspectorMgr, err := inspector.NewInspectorManagerFromConfig(*r.config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create scanner manager from configuration: %w. Verify the AWS configuration and region settings", err)
+	}
+	inspectorMgr.SetCache(r.options.Cache)
+	inspectorMgr.SetPreviousScan(r.options.Previous)
+	inspectorMgr.SetSample(r.options.Sample)
+	inspectorMgr.SetConcurrency(r.options.Concurrency)
+
+	// Reports record the identity they were produced with, a scan can go on without it
+	var identity *inspector.CallerIdentity
+	if callerIdentity, err := inspectorMgr.CallerIdentity(ctx); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to resolve the AWS identity of the scan: %v", err))
+	} else {
+		identity = &callerIdentity
+		logger.Info(fmt.Sprintf("🔐 Scanning as %s (account %s)", identity.ARN, identity.AccountID))
+	}
+
+	return inspectorMgr, identity, nil
+}
+
+// callerARN returns the principal of the identity of a scan, empty when it could not be
+// resolved
+func callerARN(identity *inspector.CallerIdentity) string {
+	if identity == nil {
+		return ""
+	}
+	return identity.ARN
+}
+
+// selectResults keeps the resources selected by the resource, tag, age and name filters of the
+// options
+func (r *Runner) selectResults(results map[string]*inspector.InspectResult) map[string]*inspector.InspectResult {
+	if r.options.Resource != "" {
+		results = FilterByResource(results, r.options.Resource)
+	}
+	if len(r.options.TagSelectors) > 0 {
+		results = inspector.FilterByTagSelectors(results, r.options.TagSelectors)
+	}
+	results = inspector.FilterByAge(results, r.options.AgeFilter)
+	return inspector.FilterByName(results, r.options.NameFilter)
+}
+
+// discovery indexes the accounts and resource types a scan covered and the resources it
+// discovered, excluded ones included, to tell which resources of the previous run are gone
+type discovery struct {
+	identity   *inspector.CallerIdentity
+	scanned    map[string]bool
+	discovered map[string]bool
+}
+
+// newDiscovery creates an empty discovery of a scan run with the given identity
+func newDiscovery(identity *inspector.CallerIdentity) *discovery {
+	return &discovery{
+		identity:   identity,
+		scanned:    make(map[string]bool),
+		discovered: make(map[string]bool),
+	}
+}
+
+// add records the result stored under key, a key as returned by inspector.ResultKey
+func (d *discovery) add(key string, result *inspector.InspectResult) {
+	accountID, resourceType, found := strings.Cut(key, "/")
+	if !found {
+		// Results of the default credentials are keyed by resource type only
+		resourceType, accountID = key, ""
+		if d.identity != nil {
+			accountID = d.identity.AccountID
+		}
+	}
+	// A truncated result does not hold every resource, missing ones are not deleted
+	if accountID != "" && !result.Truncated {
+		d.scanned[inspector.ResultKey(accountID, resourceType)] = true
+	}
+
+	for _, resource := range result.Resources {
+		d.discovered[resourceKey(resource)] = true
+	}
+	for _, excluded := range result.ExcludedResources {
+		d.discovered[resourceKey(excluded.Resource)] = true
+	}
+}
+
+// deletedResources returns the resources of the previous run that the scan did not discover
+// again, none when the scan is not incremental. Only the resources of the accounts, resource
+// types and regions the scan covered successfully are considered, so narrowing the
+// configuration or a failed inspection does not report resources as deleted. Resources of
+// the default credentials are only considered when their account is known.
+func (r *Runner) deletedResources(discovered *discovery) []DeletedResource {
+	if r.options.Previous == nil {
+		return nil
+	}
+
+	regions, err := inspector.GetEffectiveRegions(*r.config)
+	if err != nil {
+		return nil
+	}
+
+	var deleted []DeletedResource
+	for _, resource := range r.options.Previous.Resources() {
+		if resource.AccountID == "" || !discovered.scanned[inspector.ResultKey(resource.AccountID, resource.ResourceType)] {
+			continue
+		}
+		if !inspector.IsGlobalResourceType(resource.ResourceType) && !slices.Contains(regions, resource.Region) {
+			continue
+		}
+
+		key := resource.ARN
+		if key == "" {
+			key = resource.ResourceID
+		}
+		if discovered.discovered[key] {
+			continue
+		}
+
+		deleted = append(deleted, DeletedResource{
+			ResourceID:   resource.ResourceID,
+			ResourceType: resource.ResourceType,
+			ResourceARN:  resource.ARN,
+			AccountID:    resource.AccountID,
+			Region:       resource.Region,
+		})
+	}
+
+	slices.SortFunc(deleted, func(a, b DeletedResource) int {
+		return strings.Compare(a.ResourceARN+a.ResourceID, b.ResourceARN+b.ResourceID)
+	})
+
+	o11y.DefaultLogger().Info(fmt.Sprintf("♻️  Incremental scan against %d resource(s) of the previous run, %d no longer discovered",
+		r.options.Previous.Len(), len(deleted)))
+	return deleted
+}
+
+// resourceKey returns the key of a resource in the previous run, its ARN or its ID when it
+// has none
+func resourceKey(resource inspector.ResourceMetadata) string {
+	if resource.Details.ARN != "" {
+		return resource.Details.ARN
+	}
+	return resource.ID
+}
+
+// validationChunkSize is the number of resources Stream validates at once, bounding the
+// results held in memory while the workers validate
+const validationChunkSize = 1000
+
+// Validate validates the tags of a resource, leaving out the violations accepted by the
+// suppressions of the options. Resources whose tags could not be read are of unknown
+// compliance, unless the options treat them as non-compliant.
+func (r *Runner) Validate(resource inspector.ResourceMetadata) *ResourceResult {
+	ref := compliance.ResourceRef{ID: resource.ID, ARN: resource.Details.ARN, Type: resource.Type}
+
+	var validationResult *compliance.ComplianceResult
+	if resource.TagFetchError != "" {
+		validationResult = r.validator.ValidateUnreadable(ref, resource.TagFetchError)
+	} else {
+		validationResult = r.validator.ValidateResource(ref, resource.Tags)
+	}
+
+	return r.newResult(resource, validationResult)
+}
+
+// Stream validates every scanned resource and hands its result to fn in scan order.
+// Resources are validated in chunks across the validation workers of the options, and only
+// the results of a chunk are retained, so memory usage does not grow with the number of
+// resources. The summary of the run is returned once every resource is validated, or the
+// first error of fn or of the context. The cross-resource rules of the configuration are
+// checked against every scanned resource before the resources are validated.
+func (r *Runner) Stream(ctx context.Context, scan *ScanResult, fn func(*ResourceResult) error) (Summary, error) {
+	builder := newSummaryBuilder(r.options, len(r.config.ComplianceLevels) > 0, r.config.RequiredTagKeys())
+	crossResource := crossResourceViolations(r.config.CrossResourceRules, scan.Results)
+
+	for _, result := range scan.Results {
+		builder.addExclusions(result)
+		builder.setSampling(result.Sampling)
+		if err := r.validateResources(ctx, result.Resources, crossResource, builder, fn); err != nil {
+			return Summary{}, err
+		}
+	}
+
+	return builder.build(scan, r.options), nil
+}
+
+// validateResources validates resources in chunks of validationChunkSize across the
+// validation workers, adding the violations of the cross-resource rules found for them, and
+// records each result in the summary before handing it to fn
+func (r *Runner) validateResources(ctx context.Context, resources []inspector.ResourceMetadata, crossResource map[*inspector.ResourceMetadata][]compliance.Violation, builder *summaryBuilder, fn func(*ResourceResult) error) error {
+	for start := 0; start < len(resources); start += validationChunkSize {
+		chunk := resources[start:min(start+validationChunkSize, len(resources))]
+
+		batch := make([]compliance.ResourceTags, len(chunk))
+		for i, resource := range chunk {
+			batch[i] = compliance.ResourceTags{
+				Resource:         compliance.ResourceRef{ID: resource.ID, ARN: resource.Details.ARN, Type: resource.Type},
+				Tags:             resource.Tags,
+				UnreadableReason: resource.TagFetchError,
+			}
+		}
+
+		validationResults, err := r.validator.ValidateResourcesBatch(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for i, resource := range chunk {
+			if violations := crossResource[&chunk[i]]; len(violations) > 0 {
+				r.validator.AddCrossResourceViolations(batch[i].Resource, validationResults[i], violations)
+			}
+			resourceResult := r.newResult(resource, validationResults[i])
+			builder.add(resourceResult)
+			if err := fn(resourceResult); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Report validates every scanned resource and returns all the results along with the summary
+func (r *Runner) Report(ctx context.Context, scan *ScanResult) (*ComplianceReport, error) {
+	var results []*ResourceResult
+	summary, err := r.Stream(ctx, scan, func(result *ResourceResult) error {
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ComplianceReport{
+		Summary:         summary,
+		ResourceResults: results,
+		ValidationRules: summary.RuleResults,
+		Errors:          scan.ServiceErrors,
+		Skipped:         scan.Skipped,
+	}, nil
+}
+
+// newResult builds the result of a validated resource, with its raw response when the
+// options include it
+func (r *Runner) newResult(resource inspector.ResourceMetadata, validationResult *compliance.ComplianceResult) *ResourceResult {
+	result := newResourceResult(resource, validationResult)
+	if r.options.IncludeRaw {
+		result.RawResponse = resource.RawResponseMap()
+	}
+	return result
+}
+
+// FilterByResource keeps the resources, excluded ones included, whose ID, ARN or name is the
+// given one, dropping the results left without any resource
+func FilterByResource(results map[string]*inspector.InspectResult, resource string) map[string]*inspector.InspectResult {
+	matches := func(metadata inspector.ResourceMetadata) bool {
+		return metadata.ID == resource ||
+			metadata.Details.ARN == resource ||
+			metadata.Details.Name == resource
+	}
+
+	filtered := make(map[string]*inspector.InspectResult)
+	for resourceType, result := range results {
+		resources := make([]inspector.ResourceMetadata, 0)
+		for _, metadata := range result.Resources {
+			if matches(metadata) {
+				resources = append(resources, metadata)
+			}
+		}
+
+		var excluded []inspector.ExcludedResource
+		for _, entry := range result.ExcludedResources {
+			if matches(entry.Resource) {
+				excluded = append(excluded, entry)
+			}
+		}
+
+		if len(resources) == 0 && len(excluded) == 0 {
+			continue
+		}
+
+		selected := *result
+		selected.Resources = resources
+		selected.TotalResources = len(resources)
+		selected.ExcludedResources = excluded
+		filtered[resourceType] = &selected
+	}
+
+	return filtered
+}
+
+// summaryBuilder accumulates the counters of the summary as results are produced
+type summaryBuilder struct {
+	summary Summary
+
+	// scoreTotal is the sum of the scores added so far, averaged by build over the scored
+	// resources, which leave out those of unknown compliance
+	scoreTotal float64
+	scored     int
+
+	// fromSnapshot counts the results whose tags an incremental scan reused
+	fromSnapshot int
+
+	// exclusions lists the resources skipped by exclusion patterns in the added results
+	exclusions []ExcludedResource
+
+	// coverage counts the resources carrying each required tag key
+	coverage *coverageCounter
+
+	// ruleExamples is the number of resources failing each rule listed in its result
+	ruleExamples int
+
+	// sampleWeight is the number of discovered resources each result added stands for, the
+	// ratio of discovered to sampled resources of its inspection result, and estimates the
+	// counters extrapolated to every discovered resource with it
+	sampleWeight float64
+	estimates    sampleEstimates
+}
+
+// newSummaryBuilder creates a summaryBuilder grouping results by the GroupBy dimension of the
+// options, when set, counting the resources at each compliance level when the configuration
+// defines levels and the coverage of the required tag keys
+func newSummaryBuilder(options Options, levels bool, requiredKeys []string) *summaryBuilder {
+	builder := &summaryBuilder{
+		summary: Summary{
+			GlobalViolations: make(map[string]int),
+			RuleResults:      newRuleResults(),
+		},
+		coverage:     newCoverageCounter(requiredKeys),
+		ruleExamples: options.ruleExamples(),
+		sampleWeight: 1,
+	}
+	if groupBy := options.GroupBy; groupBy != "" {
+		builder.summary.GroupBy = groupBy
+		builder.summary.Groups = make(map[string]*GroupSummary)
+	}
+	if levels {
+		builder.summary.ComplianceLevels = make(map[string]int)
+	}
+	return builder
+}
+
+// setSampling weighs the results added next by the sampling of their inspection result,
+// one discovered resource each when it is not sampled
+func (b *summaryBuilder) setSampling(sampling *inspector.SampleInfo) {
+	b.sampleWeight = 1
+	if sampling != nil && sampling.Sampled > 0 {
+		b.sampleWeight = float64(sampling.Discovered) / float64(sampling.Sampled)
+	}
+}
+
+// add records a result in the summary counters
+func (b *summaryBuilder) add(result *ResourceResult) {
+	b.summary.TotalResources++
+	b.estimates.add(result, b.sampleWeight)
+	b.summary.SuppressedViolations += len(result.SuppressedViolations)
+	b.summary.Warnings += len(result.Warnings)
+	if result.FromSnapshot {
+		b.fromSnapshot++
+	}
+	if b.summary.Groups != nil {
+		AddToGroup(b.summary.Groups, result, b.summary.GroupBy)
+	}
+
+	if result.IsUnknown {
+		b.summary.UnknownResources++
+		return
+	}
+
+	b.scoreTotal += result.Score
+	b.scored++
+	b.coverage.add(result)
+	if b.summary.ComplianceLevels != nil {
+		level := result.ComplianceLevel
+		if level == "" {
+			level = string(compliance.ComplianceLevelNone)
+		}
+		b.summary.ComplianceLevels[level]++
+	}
+	recordRuleFailures(b.summary.RuleResults, result, b.ruleExamples)
+	recordRuleSkips(b.summary.RuleResults, result)
+
+	if result.IsCompliant {
+		b.summary.CompliantResources++
+		return
+	}
+
+	b.summary.NonCompliantResources++
+	for _, violation := range result.Violations {
+		b.summary.GlobalViolations[violation.Type]++
+		if violation.AutoFixable {
+			b.summary.AutoFixableViolations++
+		} else {
+			b.summary.ManualViolations++
+		}
+	}
+}
+
+// addExclusions records the resources of an inspection result skipped by exclusion patterns
+func (b *summaryBuilder) addExclusions(result *inspector.InspectResult) {
+	for _, excluded := range result.ExcludedResources {
+		b.exclusions = append(b.exclusions, ExcludedResource{
+			ResourceID:   excluded.Resource.ID,
+			ResourceType: excluded.Resource.Type,
+			Pattern:      excluded.Pattern,
+			Reason:       excluded.Reason,
+		})
+	}
+}
+
+// build completes the summary with the average score and the details of the scan
+func (b *summaryBuilder) build(scan *ScanResult, options Options) Summary {
+	summary := b.summary
+	summary.ComplianceScore = compliance.MaxComplianceScore
+	if b.scored > 0 {
+		summary.ComplianceScore = b.scoreTotal / float64(b.scored)
+	}
+
+	summary.ScanErrors = scan.Errors
+	summary.TruncatedResults = scan.Truncated
+	summary.ScanMetadata = newScanMetadata(options, scan.Identity, scan.Identities)
+	if scan.EffectiveConcurrency > 0 || scan.Throttles > 0 {
+		if summary.ScanMetadata == nil {
+			summary.ScanMetadata = &ScanMetadata{}
+		}
+		summary.ScanMetadata.Throttles = scan.Throttles
+		summary.ScanMetadata.EffectiveConcurrency = scan.EffectiveConcurrency
+	}
+	summary.Exclusions = b.exclusions
+	summary.ExcludedResources = len(summary.Exclusions)
+
+	// A rule skipped on every scored resource was evaluated on none, which is not a pass
+	for _, rule := range summary.RuleResults {
+		if rule.Skipped > 0 && rule.Skipped == b.scored {
+			rule.Passed = false
+		}
+	}
+	summary.Coverage = b.coverage.build()
+
+	if !options.Sample.IsZero() {
+		summary.Sample = newSampleSummary(options.Sample, scan.Sampling, b.estimates)
+	}
+
+	if scan.Incremental {
+		summary.Incremental = &IncrementalSummary{
+			FromSnapshot: b.fromSnapshot,
+			Inspected:    summary.TotalResources - b.fromSnapshot,
+			Deleted:      len(scan.Deleted),
+		}
+		summary.DeletedResources = scan.Deleted
+	}
+	return summary
+}
+
+// newResourceResult converts the validation result of a resource into its report representation
+func newResourceResult(resource inspector.ResourceMetadata, validationResult *compliance.ComplianceResult) *ResourceResult {
+	result := &ResourceResult{
+		IsCompliant:     validationResult.IsCompliant,
+		IsUnknown:       validationResult.IsUnknown,
+		ResourceTags:    validationResult.ResourceTags,
+		ComplianceLevel: string(validationResult.ComplianceLevel),
+		ResourceID:      resource.ID,
+		ResourceType:    resource.Type,
+		ResourceARN:     resource.Details.ARN,
+		AccountID:       resource.AccountID,
+		Region:          resource.Region,
+		Score:           validationResult.Score,
+		TagFetchError:   resource.TagFetchError,
+		ChangeMarker:    resource.ChangeMarker,
+		FromSnapshot:    resource.FromSnapshot,
+		Fix:             validationResult.Fix,
+		SkippedRules:    validationResult.SkippedRules,
+	}
+
+	for _, v := range validationResult.Violations {
+		result.Violations = append(result.Violations, newViolation(v))
+	}
+	for _, v := range validationResult.SuppressedViolations {
+		result.SuppressedViolations = append(result.SuppressedViolations, newViolation(v))
+	}
+	for _, v := range validationResult.Warnings {
+		result.Warnings = append(result.Warnings, newViolation(v))
+	}
+
+	return result
+}
+
+// newViolation converts a violation into its report representation
+func newViolation(v compliance.Violation) Violation {
+	return Violation{
+		Type:     string(v.Type),
+		Message:  v.Message,
+		TagKey:   v.TagKey,
+		Value:    v.Value,
+		Severity: string(v.Severity),
+		Note:     v.Note,
+		Expected: v.Expected,
+
+		SuggestedValue:   v.SuggestedValue,
+		DocURL:           v.DocURL,
+		AutoFixable:      v.AutoFixable,
+		RelatedResources: v.RelatedResources,
+	}
+}
+
+// newScanMetadata records the identities of the scan and the tag selectors and name filters
+// restricting the checked resources, nil without any
+func newScanMetadata(options Options, identity *inspector.CallerIdentity, identities map[string]string) *ScanMetadata {
+	if len(options.TagSelectors) == 0 && options.NameFilter.IsZero() && identity == nil && len(identities) == 0 {
+		return nil
+	}
+
+	metadata := &ScanMetadata{}
+	if identity != nil {
+		metadata.AccountID = identity.AccountID
+		metadata.CallerARN = identity.ARN
+	}
+	metadata.Identities = identities
+	for _, selector := range options.TagSelectors {
+		metadata.TagFilters = append(metadata.TagFilters, selector.String())
+	}
+	metadata.NameFilters = options.NameFilter.Filters()
+	return metadata
+}
+
+// newRuleResults returns the rule results of the planned checks, all passing
+func newRuleResults() map[string]*RuleResult {
+	return map[string]*RuleResult{
+		"required_tags": {
+			Name:        "Required Tags",
+			Description: "Validates that all required tags are present",
+			Passed:      true,
+		},
+		"tag_format": {
+			Name:        "Tag Value Format",
+			Description: "Ensures tag values match specified formats and patterns",
+			Passed:      true,
+		},
+		"allowed_values": {
+			Name:        "Allowed Values",
+			Description: "Verifies tag values are within allowed sets",
+			Passed:      true,
+		},
+		"case_sensitivity": {
+			Name:        "Case Sensitivity",
+			Description: "Checks if tag keys and values follow case requirements",
+			Passed:      true,
+		},
+		"forbidden_tags": {
+			Name:        "Forbidden Tags",
+			Description: "Verifies that no forbidden tag is present",
+			Passed:      true,
+		},
+		"max_tags": {
+			Name:        "Maximum Tags",
+			Description: "Checks that resources do not carry more tags than allowed",
+			Passed:      true,
+		},
+		"specific_tags": {
+			Name:        "Specific Tags",
+			Description: "Verifies that specific tags carry their exact required values",
+			Passed:      true,
+		},
+		"duplicate_key_different_case": {
+			Name:        "Duplicate Keys Differing In Case",
+			Description: "Checks that no two tag keys of a resource are equal ignoring case",
+			Passed:      true,
+		},
+		"aws_tag_limits": {
+			Name:        "AWS Tag Limits",
+			Description: "Checks tag lengths and characters against the limits AWS enforces",
+			Passed:      true,
+		},
+		"key_validation": {
+			Name:        "Key Validation",
+			Description: "Checks tag keys against the allowed prefixes, suffixes and maximum length",
+			Passed:      true,
+		},
+		"unique_tag_values": {
+			Name:        "Unique Tag Values",
+			Description: "Checks that no two resources within the scope of a cross-resource rule share a tag value",
+			Passed:      true,
+		},
+	}
+}
+
+// recordRuleFailures updates rule results based on the violation types of a result, counting
+// the failures of every rule by resource type and listing the resource among the examples of
+// the rules it fails
+func recordRuleFailures(ruleResults map[string]*RuleResult, result *ResourceResult, examples int) {
+	resource := result.ResourceARN
+	if resource == "" {
+		resource = result.ResourceID
+	}
+
+	for _, v := range result.Violations {
+		var rule string
+		switch compliance.ViolationType(v.Type) {
+		case compliance.ViolationTypeMissingTags:
+			rule = "required_tags"
+		case compliance.ViolationTypePatternViolation:
+			rule = "tag_format"
+		case compliance.ViolationTypeInvalidValue:
+			rule = "allowed_values"
+		case compliance.ViolationTypeCaseViolation:
+			rule = "case_sensitivity"
+		case compliance.ViolationTypeForbiddenTag:
+			rule = "forbidden_tags"
+		case compliance.ViolationTypeTooManyTags:
+			rule = "max_tags"
+		case compliance.ViolationTypeSpecificTagMismatch:
+			rule = "specific_tags"
+		case compliance.ViolationTypeDuplicateKeyDifferentCase:
+			rule = "duplicate_key_different_case"
+		case compliance.ViolationTypeAWSTagLimit:
+			rule = "aws_tag_limits"
+		case compliance.ViolationTypeInvalidKeyPrefix, compliance.ViolationTypeInvalidKeySuffix, compliance.ViolationTypeKeyTooLong:
+			rule = "key_validation"
+		case compliance.ViolationTypeDuplicateTagValue:
+			rule = "unique_tag_values"
+		default:
+			continue
+		}
+
+		ruleResult := ruleResults[rule]
+		ruleResult.Passed = false
+		ruleResult.Failures++
+		if ruleResult.FailuresByResourceType == nil {
+			ruleResult.FailuresByResourceType = make(map[string]int)
+		}
+		ruleResult.FailuresByResourceType[result.ResourceType]++
+		ruleResult.addExample(resource, examples)
+	}
+}
+
+// skippedRuleResults maps the built-in rule categories of disabled_rules to the rule results
+// they leave out. Categories without a rule result, such as key_format, are only listed in the
+// skipped rules of the resources.
+var skippedRuleResults = map[string][]string{
+	configuration.RuleRequiredTags:  {"required_tags"},
+	configuration.RuleAllowedValues: {"allowed_values"},
+	configuration.RulePatternRules:  {"tag_format"},
+	configuration.RuleCaseRules:     {"case_sensitivity"},
+	configuration.RuleKeyValidation: {"key_validation", "duplicate_key_different_case"},
+	configuration.RuleMaxTags:       {"max_tags"},
+}
+
+// recordRuleSkips counts the resource among the skips of the rules its resource type disables,
+// so that a resource a rule was not evaluated on does not count as passing it
+func recordRuleSkips(ruleResults map[string]*RuleResult, result *ResourceResult) {
+	for _, skipped := range result.SkippedRules {
+		for _, rule := range skippedRuleResults[skipped] {
+			ruleResult := ruleResults[rule]
+			ruleResult.Skipped++
+			if ruleResult.SkippedByResourceType == nil {
+				ruleResult.SkippedByResourceType = make(map[string]int)
+			}
+			ruleResult.SkippedByResourceType[result.ResourceType]++
+		}
+	}
+}
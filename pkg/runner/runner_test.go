@@ -0,0 +1,738 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites the golden files of the tests with their actual output
+var update = flag.Bool("update", false, "update the golden files")
+
+func newTestConfig() *configuration.TaggyScanConfig {
+	return &configuration.TaggyScanConfig{
+		Global: configuration.GlobalConfig{
+			Enabled: true,
+			TagCriteria: configuration.TagCriteria{
+				RequiredTags: []string{"Environment", "Owner"},
+			},
+		},
+	}
+}
+
+func newTestResource(id string, tags map[string]string) inspector.ResourceMetadata {
+	resource := inspector.ResourceMetadata{
+		ID:        id,
+		Type:      "s3",
+		AccountID: "123456789012",
+		Region:    "us-east-1",
+		Tags:      tags,
+	}
+	resource.Details.ARN = "arn:aws:s3:::" + id
+	resource.Details.Name = id
+	return resource
+}
+
+func newTestScan() *ScanResult {
+	return &ScanResult{
+		Results: map[string]*inspector.InspectResult{
+			"s3": {
+				Resources: []inspector.ResourceMetadata{
+					newTestResource("payments", map[string]string{"Environment": "prod", "Owner": "payments"}),
+					newTestResource("legacy-logs", map[string]string{"Environment": "prod"}),
+					newTestResource("scratch", map[string]string{}),
+				},
+				TotalResources: 3,
+				ExcludedResources: []inspector.ExcludedResource{
+					{Resource: newTestResource("terraform-state", nil), Pattern: "terraform-.*"},
+				},
+			},
+		},
+		Errors: []string{"account 210987654321: access denied"},
+	}
+}
+
+// mustReport validates every resource of the scan, failing the test on error
+func mustReport(t *testing.T, runner *Runner, scan *ScanResult) *ComplianceReport {
+	t.Helper()
+
+	report, err := runner.Report(context.Background(), scan)
+	require.NoError(t, err)
+	return report
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(nil, Options{})
+	assert.Error(t, err)
+
+	_, err = New(newTestConfig(), Options{GroupBy: "owner"})
+	assert.Error(t, err)
+
+	runner, err := New(newTestConfig(), Options{GroupBy: "tag:Owner"})
+	require.NoError(t, err)
+	assert.NotNil(t, runner)
+}
+
+func TestRunnerReport(t *testing.T) {
+	t.Parallel()
+
+	runner, err := New(newTestConfig(), Options{GroupBy: GroupByType})
+	require.NoError(t, err)
+
+	report := mustReport(t, runner, newTestScan())
+	require.Len(t, report.ResourceResults, 3)
+
+	byID := make(map[string]*ResourceResult)
+	for _, result := range report.ResourceResults {
+		byID[result.ResourceID] = result
+	}
+	assert.True(t, byID["payments"].IsCompliant)
+	assert.False(t, byID["legacy-logs"].IsCompliant)
+	assert.Equal(t, "arn:aws:s3:::legacy-logs", byID["legacy-logs"].ResourceARN)
+	assert.Equal(t, "us-east-1", byID["legacy-logs"].Region)
+	require.Len(t, byID["legacy-logs"].Violations, 1)
+	assert.Equal(t, string(compliance.ViolationTypeMissingTags), byID["legacy-logs"].Violations[0].Type)
+
+	summary := report.Summary
+	assert.Equal(t, 3, summary.TotalResources)
+	assert.Equal(t, 1, summary.CompliantResources)
+	assert.Equal(t, 2, summary.NonCompliantResources)
+	assert.Equal(t, 1, summary.ExcludedResources)
+	assert.Equal(t, 2, summary.GlobalViolations[string(compliance.ViolationTypeMissingTags)])
+	assert.Less(t, summary.ComplianceScore, compliance.MaxComplianceScore)
+	assert.Equal(t, []string{"account 210987654321: access denied"}, summary.ScanErrors)
+	assert.Equal(t, 3, summary.Groups["s3"].TotalResources)
+	assert.Equal(t, summary.RuleResults, report.ValidationRules)
+}
+
+func TestRunnerReportRuleFailures(t *testing.T) {
+	t.Parallel()
+
+	config := newTestConfig()
+	config.TagValidation.AllowedValues = map[string][]string{"environment": {"production", "staging"}}
+
+	runner, err := New(config, Options{})
+	require.NoError(t, err)
+
+	rules := mustReport(t, runner, newTestScan()).Summary.RuleResults
+
+	// legacy-logs and scratch miss tags, payments and legacy-logs carry an invalid environment
+	assert.False(t, rules["required_tags"].Passed)
+	assert.Equal(t, 2, rules["required_tags"].Failures)
+	assert.False(t, rules["allowed_values"].Passed)
+	assert.Equal(t, 2, rules["allowed_values"].Failures)
+	assert.True(t, rules["tag_format"].Passed)
+	assert.Zero(t, rules["tag_format"].Failures)
+
+	assert.Equal(t, map[string]int{"s3": 2}, rules["required_tags"].FailuresByResourceType)
+	assert.Equal(t, []string{"arn:aws:s3:::legacy-logs", "arn:aws:s3:::scratch"}, rules["required_tags"].ExampleResources)
+	assert.Equal(t, []string{"arn:aws:s3:::legacy-logs", "arn:aws:s3:::payments"}, rules["allowed_values"].ExampleResources)
+	assert.Empty(t, rules["tag_format"].FailuresByResourceType)
+	assert.Empty(t, rules["tag_format"].ExampleResources)
+}
+
+// newMixedTestScan returns the resources of newTestScan with a noncompliant EC2 instance
+func newMixedTestScan() *ScanResult {
+	instance := newTestResource("i-0abc", map[string]string{"Environment": "dev", "Owner": "ops"})
+	instance.Type = "ec2"
+	instance.Details.ARN = "arn:aws:ec2:us-east-1:123456789012:instance/i-0abc"
+
+	scan := newTestScan()
+	scan.Results["ec2"] = &inspector.InspectResult{Resources: []inspector.ResourceMetadata{instance}, TotalResources: 1}
+	return scan
+}
+
+func TestRunnerReportRuleExamples(t *testing.T) {
+	t.Parallel()
+
+	config := newTestConfig()
+	config.TagValidation.AllowedValues = map[string][]string{"environment": {"production", "staging"}}
+
+	tests := []struct {
+		name     string
+		examples int
+		expected []string
+	}{
+		{
+			name:     "Default",
+			expected: []string{"arn:aws:ec2:us-east-1:123456789012:instance/i-0abc", "arn:aws:s3:::legacy-logs", "arn:aws:s3:::payments"},
+		},
+		{
+			name:     "Limited",
+			examples: 1,
+			expected: []string{"arn:aws:ec2:us-east-1:123456789012:instance/i-0abc"},
+		},
+		{
+			name:     "Disabled",
+			examples: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			runner, err := New(config, Options{RuleExamples: tt.examples})
+			require.NoError(t, err)
+
+			rule := mustReport(t, runner, newMixedTestScan()).Summary.RuleResults["allowed_values"]
+			assert.Equal(t, 3, rule.Failures)
+			assert.Equal(t, map[string]int{"ec2": 1, "s3": 2}, rule.FailuresByResourceType)
+			assert.Equal(t, tt.expected, rule.ExampleResources)
+
+			resourceType, failures := rule.TopResourceType()
+			assert.Equal(t, "s3", resourceType)
+			assert.Equal(t, 2, failures)
+		})
+	}
+}
+
+func TestRunnerReportDisabledRules(t *testing.T) {
+	t.Parallel()
+
+	config := newTestConfig()
+	config.Resources = map[string]configuration.ResourceConfig{
+		"s3": {
+			Enabled:       true,
+			DisabledRules: []string{configuration.RuleMaxTags, configuration.RuleRequiredTags},
+		},
+	}
+
+	runner, err := New(config, Options{})
+	require.NoError(t, err)
+
+	report := mustReport(t, runner, newTestScan())
+	require.Len(t, report.ResourceResults, 3)
+	for _, result := range report.ResourceResults {
+		assert.True(t, result.IsCompliant, result.ResourceID)
+		assert.Equal(t, []string{configuration.RuleRequiredTags, configuration.RuleMaxTags}, result.SkippedRules, result.ResourceID)
+	}
+
+	required := report.Summary.RuleResults["required_tags"]
+	assert.False(t, required.Passed, "a rule skipped on every resource does not pass")
+	assert.True(t, required.IsSkipped())
+	assert.Zero(t, required.Failures)
+	assert.Equal(t, 3, required.Skipped)
+	assert.Equal(t, map[string]int{"s3": 3}, required.SkippedByResourceType)
+	assert.True(t, report.Summary.RuleResults["allowed_values"].Passed)
+
+	// Resources of other types are still evaluated
+	instance := newTestResource("i-0abc", map[string]string{"Environment": "prod"})
+	instance.Type = "ec2"
+	scan := newTestScan()
+	scan.Results["ec2"] = &inspector.InspectResult{Resources: []inspector.ResourceMetadata{instance}, TotalResources: 1}
+
+	report = mustReport(t, runner, scan)
+	required = report.Summary.RuleResults["required_tags"]
+	assert.False(t, required.Passed)
+	assert.False(t, required.IsSkipped())
+	assert.Equal(t, 1, required.Failures)
+	assert.Equal(t, 3, required.Skipped)
+}
+
+// TestRunnerReportRuleResultsGolden pins the JSON structure of the rule results, which
+// downstream consumers of the JSON output read. Run with -update to rewrite it.
+func TestRunnerReportRuleResultsGolden(t *testing.T) {
+	t.Parallel()
+
+	config := newTestConfig()
+	config.TagValidation.AllowedValues = map[string][]string{"environment": {"production", "staging"}}
+
+	runner, err := New(config, Options{RuleExamples: 2})
+	require.NoError(t, err)
+
+	report := mustReport(t, runner, newMixedTestScan())
+	actual, err := json.MarshalIndent(map[string]any{"validation_rules": report.ValidationRules}, "", "  ")
+	require.NoError(t, err)
+	actual = append(actual, '\n')
+
+	golden := filepath.Join("testdata", "rule_results.golden.json")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, actual, 0o644))
+	}
+
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestRuleResult_TopResourceType(t *testing.T) {
+	t.Parallel()
+
+	passed := &RuleResult{Passed: true}
+	resourceType, failures := passed.TopResourceType()
+	assert.Empty(t, resourceType)
+	assert.Zero(t, failures)
+
+	tied := &RuleResult{FailuresByResourceType: map[string]int{"sqs": 4, "ec2": 4, "s3": 1}}
+	resourceType, failures = tied.TopResourceType()
+	assert.Equal(t, "ec2", resourceType)
+	assert.Equal(t, 4, failures)
+}
+
+func TestRunnerReportComplianceLevels(t *testing.T) {
+	t.Parallel()
+
+	config := newTestConfig()
+	config.ComplianceLevels = map[string]configuration.ComplianceLevel{
+		"standard": {RequiredTags: []string{"Environment"}},
+		"high":     {RequiredTags: []string{"Owner"}, Extends: "standard"},
+	}
+
+	runner, err := New(config, Options{})
+	require.NoError(t, err)
+
+	report := mustReport(t, runner, newTestScan())
+	byID := make(map[string]*ResourceResult)
+	for _, result := range report.ResourceResults {
+		byID[result.ResourceID] = result
+	}
+	assert.Equal(t, "high", byID["payments"].ComplianceLevel)
+	assert.Equal(t, "standard", byID["legacy-logs"].ComplianceLevel)
+	assert.Empty(t, byID["scratch"].ComplianceLevel)
+
+	summary := report.Summary
+	assert.Equal(t, map[string]int{"high": 1, "standard": 1, "none": 1}, summary.ComplianceLevels)
+	assert.Equal(t, 1, summary.BelowComplianceLevel(compliance.ComplianceLevelStandard))
+	assert.Equal(t, 2, summary.BelowComplianceLevel(compliance.ComplianceLevelHigh))
+}
+
+func TestRunnerReportIdentity(t *testing.T) {
+	t.Parallel()
+
+	runner, err := New(newTestConfig(), Options{})
+	require.NoError(t, err)
+
+	assert.Nil(t, mustReport(t, runner, newTestScan()).Summary.ScanMetadata)
+
+	scan := newTestScan()
+	scan.Identity = &inspector.CallerIdentity{AccountID: "123456789012", ARN: "arn:aws:sts::123456789012:assumed-role/audit/aws-taggy"}
+	metadata := mustReport(t, runner, scan).Summary.ScanMetadata
+	require.NotNil(t, metadata)
+	assert.Equal(t, "123456789012", metadata.AccountID)
+	assert.Equal(t, "arn:aws:sts::123456789012:assumed-role/audit/aws-taggy", metadata.CallerARN)
+	assert.Empty(t, metadata.TagFilters)
+}
+
+func TestRunnerReportNameFilters(t *testing.T) {
+	t.Parallel()
+
+	nameFilter, err := inspector.NewNameFilter("^prod-", "-tmp$")
+	require.NoError(t, err)
+	runner, err := New(newTestConfig(), Options{NameFilter: nameFilter})
+	require.NoError(t, err)
+
+	metadata := mustReport(t, runner, newTestScan()).Summary.ScanMetadata
+	require.NotNil(t, metadata)
+	assert.Equal(t, []string{"match ^prod-", "exclude -tmp$"}, metadata.NameFilters)
+	assert.Empty(t, metadata.AccountID)
+}
+
+func TestRunnerReportIdentities(t *testing.T) {
+	t.Parallel()
+
+	runner, err := New(newTestConfig(), Options{})
+	require.NoError(t, err)
+
+	assert.Nil(t, mustReport(t, runner, newTestScan()).Summary.ScanMetadata)
+
+	scan := newTestScan()
+	scan.Identities = map[string]string{
+		"s3":                "arn:aws:iam::111111111111:user/auditor",
+		"333333333333/logs": "arn:aws:iam::333333333333:role/aws-taggy-readonly",
+	}
+	metadata := mustReport(t, runner, scan).Summary.ScanMetadata
+	require.NotNil(t, metadata)
+	assert.Equal(t, scan.Identities, metadata.Identities)
+}
+
+func TestRunnerReportThrottles(t *testing.T) {
+	t.Parallel()
+
+	runner, err := New(newTestConfig(), Options{Concurrency: 8})
+	require.NoError(t, err)
+
+	// A scan without throttling records the concurrency it ran with, and no throttles
+	scan := newTestScan()
+	scan.EffectiveConcurrency = 8
+	summary := mustReport(t, runner, scan).Summary
+	require.NotNil(t, summary.ScanMetadata)
+	assert.Equal(t, 8, summary.ScanMetadata.EffectiveConcurrency)
+	assert.Zero(t, summary.ScanMetadata.Throttles)
+
+	data, err := json.Marshal(summary)
+	require.NoError(t, err)
+	var decoded struct {
+		ScanMetadata map[string]any `json:"scan_metadata"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, map[string]any{"effective_concurrency": float64(8)}, decoded.ScanMetadata)
+
+	scan.Throttles = 12
+	scan.EffectiveConcurrency = 2
+	metadata := mustReport(t, runner, scan).Summary.ScanMetadata
+	require.NotNil(t, metadata)
+	assert.Equal(t, int64(12), metadata.Throttles)
+	assert.Equal(t, 2, metadata.EffectiveConcurrency)
+}
+
+func TestRunnerReportTruncated(t *testing.T) {
+	t.Parallel()
+
+	runner, err := New(newTestConfig(), Options{})
+	require.NoError(t, err)
+
+	assert.False(t, mustReport(t, runner, newTestScan()).Summary.Truncated())
+
+	scan := newTestScan()
+	scan.Truncated = []string{"s3"}
+	summary := mustReport(t, runner, scan).Summary
+	assert.True(t, summary.Truncated())
+	assert.Equal(t, []string{"s3"}, summary.TruncatedResults)
+}
+
+func TestRunnerReportFixPlan(t *testing.T) {
+	t.Parallel()
+
+	config := newTestConfig()
+	config.TagValidation.CaseRules = map[string]configuration.CaseRule{
+		"environment": {Case: configuration.CaseLowercase},
+	}
+	runner, err := New(config, Options{})
+	require.NoError(t, err)
+
+	scan := &ScanResult{
+		Results: map[string]*inspector.InspectResult{
+			"s3": {
+				Resources: []inspector.ResourceMetadata{
+					newTestResource("payments", map[string]string{"environment": "Prod", "Owner": "payments"}),
+					newTestResource("legacy-logs", map[string]string{"environment": "prod"}),
+				},
+				TotalResources: 2,
+			},
+		},
+	}
+	report := mustReport(t, runner, scan)
+
+	assert.Equal(t, 1, report.Summary.AutoFixableViolations)
+	assert.Equal(t, 1, report.Summary.ManualViolations)
+	assert.Equal(t, []compliance.FixPlanEntry{{
+		ARN:     "arn:aws:s3:::payments",
+		Service: "s3",
+		Set:     map[string]string{"environment": "prod"},
+	}}, FixPlan(report.ResourceResults))
+}
+
+func TestRunnerReportSuppressions(t *testing.T) {
+	t.Parallel()
+
+	suppressions, err := compliance.NewSuppressions([]compliance.Suppression{
+		{Resource: `legacy-.*`, ViolationType: string(compliance.ViolationTypeMissingTags), Reason: "Legacy buckets"},
+	})
+	require.NoError(t, err)
+
+	runner, err := New(newTestConfig(), Options{Suppressions: suppressions})
+	require.NoError(t, err)
+
+	summary := mustReport(t, runner, newTestScan()).Summary
+	assert.Equal(t, 2, summary.CompliantResources)
+	assert.Equal(t, 1, summary.NonCompliantResources)
+	assert.Equal(t, 1, summary.SuppressedViolations)
+}
+
+func TestRunnerReportUnreadableTags(t *testing.T) {
+	t.Parallel()
+
+	scan := newTestScan()
+	unreadable := newTestResource("locked", nil)
+	unreadable.TagFetchError = "AccessDenied: not authorized to perform s3:GetBucketTagging"
+	scan.Results["s3"].Resources = append(scan.Results["s3"].Resources, unreadable)
+
+	runner, err := New(newTestConfig(), Options{})
+	require.NoError(t, err)
+
+	report := mustReport(t, runner, scan)
+	summary := report.Summary
+	assert.Equal(t, 4, summary.TotalResources)
+	assert.Equal(t, 1, summary.UnknownResources)
+	assert.Equal(t, 1, summary.CompliantResources)
+	assert.Equal(t, 2, summary.NonCompliantResources)
+	assert.Equal(t, mustReport(t, runner, newTestScan()).Summary.ComplianceScore, summary.ComplianceScore)
+
+	var locked *ResourceResult
+	for _, result := range report.ResourceResults {
+		if result.ResourceID == "locked" {
+			locked = result
+		}
+	}
+	require.NotNil(t, locked)
+	assert.True(t, locked.IsUnknown)
+	assert.Equal(t, unreadable.TagFetchError, locked.TagFetchError)
+
+	strict, err := New(newTestConfig(), Options{TreatUnreadableAsNonCompliant: true})
+	require.NoError(t, err)
+
+	summary = mustReport(t, strict, scan).Summary
+	assert.Equal(t, 0, summary.UnknownResources)
+	assert.Equal(t, 3, summary.NonCompliantResources)
+	assert.Equal(t, 1, summary.GlobalViolations[string(compliance.ViolationTypeTagsUnreadable)])
+}
+
+func TestRunnerReportCoverage(t *testing.T) {
+	t.Parallel()
+
+	config := newTestConfig()
+	config.Resources = map[string]configuration.ResourceConfig{
+		"ec2": {Enabled: true, TagCriteria: configuration.TagCriteria{RequiredTags: []string{"Backup"}}},
+	}
+
+	scan := newTestScan()
+	scan.Results["s3"].Resources = append(scan.Results["s3"].Resources, inspector.ResourceMetadata{
+		ID: "locked", Type: "s3", TagFetchError: "AccessDenied",
+	})
+	instance := newTestResource("i-0abc", map[string]string{"Owner": "web", "Backup": "daily"})
+	instance.Type = "ec2"
+	scan.Results["ec2"] = &inspector.InspectResult{Resources: []inspector.ResourceMetadata{instance}, TotalResources: 1}
+
+	runner, err := New(config, Options{})
+	require.NoError(t, err)
+
+	// The excluded terraform-state bucket and the unreadable locked bucket are not counted
+	coverage := mustReport(t, runner, scan).Summary.Coverage
+	expected := []struct {
+		key, resourceType string
+		present, missing  int
+		percentage        float64
+	}{
+		{"Backup", "ec2", 1, 0, 100},
+		{"Backup", "s3", 0, 3, 0},
+		{"Environment", "ec2", 0, 1, 0},
+		{"Environment", "s3", 2, 1, 66.67},
+		{"Owner", "ec2", 1, 0, 100},
+		{"Owner", "s3", 1, 2, 33.33},
+	}
+	require.Len(t, coverage, len(expected))
+	for i, want := range expected {
+		assert.Equal(t, want.key, coverage[i].TagKey)
+		assert.Equal(t, want.resourceType, coverage[i].ResourceType)
+		assert.Equal(t, want.present, coverage[i].Present, "%s on %s", want.key, want.resourceType)
+		assert.Equal(t, want.missing, coverage[i].Missing, "%s on %s", want.key, want.resourceType)
+		assert.InDelta(t, want.percentage, coverage[i].Percentage, 0.01)
+	}
+
+	// Only the selected resources are counted
+	selected, err := New(config, Options{Resource: "payments"})
+	require.NoError(t, err)
+	filtered := &ScanResult{Results: selected.selectResults(scan.Results)}
+	for _, entry := range mustReport(t, selected, filtered).Summary.Coverage {
+		assert.Equal(t, "s3", entry.ResourceType)
+		assert.Equal(t, 1, entry.Present+entry.Missing)
+	}
+}
+
+func TestRunnerReportWarnings(t *testing.T) {
+	t.Parallel()
+
+	config := newTestConfig()
+	config.TagValidation.AllowedValues = map[string][]string{"Environment": {"production", "staging", "prod"}}
+	config.TagValidation.DeprecatedValues = map[string]map[string]string{"Environment": {"prod": "production"}}
+
+	runner, err := New(config, Options{})
+	require.NoError(t, err)
+
+	report := mustReport(t, runner, newTestScan())
+	assert.Equal(t, 2, report.Summary.Warnings, "payments and legacy-logs carry the deprecated prod")
+	assert.Equal(t, 1, report.Summary.CompliantResources)
+
+	for _, result := range report.ResourceResults {
+		if result.ResourceID == "payments" {
+			assert.True(t, result.IsCompliant)
+			require.Len(t, result.Warnings, 1)
+			assert.Equal(t, string(compliance.ViolationTypeDeprecatedValue), result.Warnings[0].Type)
+			assert.Equal(t, "production", result.Warnings[0].SuggestedValue)
+		}
+	}
+}
+
+func TestSummaryBadge(t *testing.T) {
+	t.Parallel()
+
+	summary := Summary{TotalResources: 4, CompliantResources: 2, NonCompliantResources: 1, UnknownResources: 1, ComplianceScore: 87.5}
+	assert.Equal(t, 3, summary.ScoredResources())
+	assert.Equal(t, "87%", summary.Badge(configuration.DefaultBadgeThresholds).Message)
+
+	// Without resources of known compliance the score defaults to 100, the badge is unknown
+	unknown := Summary{TotalResources: 1, UnknownResources: 1, ComplianceScore: compliance.MaxComplianceScore}
+	badge := unknown.Badge(configuration.DefaultBadgeThresholds)
+	assert.Equal(t, "unknown", badge.Message)
+	assert.Equal(t, compliance.BadgeColorUnknown, badge.Color)
+}
+
+func TestRunnerStream(t *testing.T) {
+	t.Parallel()
+
+	runner, err := New(newTestConfig(), Options{})
+	require.NoError(t, err)
+
+	var streamed []string
+	summary, err := runner.Stream(context.Background(), newTestScan(), func(result *ResourceResult) error {
+		streamed = append(streamed, result.ResourceID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"payments", "legacy-logs", "scratch"}, streamed)
+	assert.Equal(t, 3, summary.TotalResources)
+
+	errWrite := errors.New("disk full")
+	_, err = runner.Stream(context.Background(), newTestScan(), func(*ResourceResult) error { return errWrite })
+	assert.ErrorIs(t, err, errWrite)
+}
+
+func TestRunnerStreamOrder(t *testing.T) {
+	t.Parallel()
+
+	// Enough resources for several validation chunks, streamed in scan order
+	var resources []inspector.ResourceMetadata
+	var expected []string
+	for i := range 2*validationChunkSize + 10 {
+		id := fmt.Sprintf("bucket-%05d", i)
+		resources = append(resources, newTestResource(id, map[string]string{"Environment": "prod"}))
+		expected = append(expected, id)
+	}
+	scan := &ScanResult{Results: map[string]*inspector.InspectResult{"s3": {Resources: resources}}}
+
+	runner, err := New(newTestConfig(), Options{ValidationWorkers: 4})
+	require.NoError(t, err)
+
+	var streamed []string
+	summary, err := runner.Stream(context.Background(), scan, func(result *ResourceResult) error {
+		streamed = append(streamed, result.ResourceID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, expected, streamed)
+	assert.Equal(t, len(expected), summary.NonCompliantResources)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = runner.Report(ctx, scan)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRunnerValidateIncludeRaw(t *testing.T) {
+	t.Parallel()
+
+	resource := newTestResource("payments", map[string]string{"Environment": "prod", "Owner": "payments"})
+	resource.RawResponse = struct{ Name string }{Name: "payments"}
+
+	runner, err := New(newTestConfig(), Options{})
+	require.NoError(t, err)
+	assert.Nil(t, runner.Validate(resource).RawResponse)
+
+	runner, err = New(newTestConfig(), Options{IncludeRaw: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"Name": "payments"}, runner.Validate(resource).RawResponse)
+}
+
+func TestFilterByResource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		resource         string
+		expectedIDs      []string
+		expectedExcluded int
+	}{
+		{name: "By ID", resource: "payments", expectedIDs: []string{"payments"}},
+		{name: "By ARN", resource: "arn:aws:s3:::scratch", expectedIDs: []string{"scratch"}},
+		{name: "Excluded Resource", resource: "terraform-state", expectedIDs: []string{}, expectedExcluded: 1},
+		{name: "No Match", resource: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			filtered := FilterByResource(newTestScan().Results, tt.resource)
+			if tt.expectedIDs == nil {
+				assert.Empty(t, filtered)
+				return
+			}
+
+			require.Contains(t, filtered, "s3")
+			var ids []string
+			for _, resource := range filtered["s3"].Resources {
+				ids = append(ids, resource.ID)
+			}
+			assert.ElementsMatch(t, tt.expectedIDs, ids)
+			assert.Equal(t, len(tt.expectedIDs), filtered["s3"].TotalResources)
+			assert.Len(t, filtered["s3"].ExcludedResources, tt.expectedExcluded)
+		})
+	}
+}
+
+func TestRunnerIncrementalScan(t *testing.T) {
+	t.Parallel()
+
+	previousBucket := func(id, accountID, region string) inspector.PreviousResource {
+		return inspector.PreviousResource{
+			ARN:          "arn:aws:s3:::" + id,
+			ResourceID:   id,
+			ResourceType: "s3",
+			AccountID:    accountID,
+			Region:       region,
+		}
+	}
+	previous := inspector.NewPreviousScan([]inspector.PreviousResource{
+		previousBucket("payments", "123456789012", "us-east-1"),
+		previousBucket("terraform-state", "123456789012", "us-east-1"),
+		previousBucket("retired", "123456789012", "us-east-1"),
+		previousBucket("other-region", "123456789012", "eu-west-1"),
+		previousBucket("other-account", "210987654321", "us-east-1"),
+		{ARN: "arn:aws:ec2:us-east-1:123456789012:instance/i-0abc", ResourceID: "i-0abc", ResourceType: "ec2", AccountID: "123456789012", Region: "us-east-1"},
+	})
+
+	runner, err := New(newTestConfig(), Options{Previous: previous})
+	require.NoError(t, err)
+
+	scan := newTestScan()
+	scan.Results["s3"].Resources[0].FromSnapshot = true
+	scan.Incremental = true
+	discover := func(identity *inspector.CallerIdentity) *discovery {
+		discovered := newDiscovery(identity)
+		for key, result := range scan.Results {
+			discovered.add(key, result)
+		}
+		return discovered
+	}
+	scan.Deleted = runner.deletedResources(discover(&inspector.CallerIdentity{AccountID: "123456789012"}))
+
+	require.Len(t, scan.Deleted, 1)
+	assert.Equal(t, DeletedResource{
+		ResourceID:   "retired",
+		ResourceType: "s3",
+		ResourceARN:  "arn:aws:s3:::retired",
+		AccountID:    "123456789012",
+		Region:       "us-east-1",
+	}, scan.Deleted[0])
+
+	// Without the account of the default credentials nothing can be told deleted
+	assert.Empty(t, runner.deletedResources(discover(nil)))
+
+	summary := mustReport(t, runner, scan).Summary
+	assert.Equal(t, &IncrementalSummary{FromSnapshot: 1, Inspected: 2, Deleted: 1}, summary.Incremental)
+	assert.Equal(t, scan.Deleted, summary.DeletedResources)
+}
@@ -0,0 +1,89 @@
+package runner
+
+import (
+	"math"
+
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+)
+
+// SampleSummary describes a sampled run, whose counters only cover the sampled resources,
+// and extrapolates them to every discovered resource. Estimates scale the counters of every
+// inspection result by its ratio of discovered to sampled resources, they are no
+// statistical bounds.
+type SampleSummary struct {
+	// Sample is the size of the sample of every inspection result, a share such as 10% or a
+	// number of resources such as 500, and Seed the seed of the random selection
+	Sample string `json:"sample" yaml:"sample"`
+	Seed   int64  `json:"seed" yaml:"seed"`
+
+	// DiscoveredResources is the number of resources discovered, SampledResources the
+	// number sampled out of them, both before exclusions and filters
+	DiscoveredResources int `json:"discovered_resources" yaml:"discovered_resources"`
+	SampledResources    int `json:"sampled_resources" yaml:"sampled_resources"`
+
+	EstimatedTotalResources        int `json:"estimated_total_resources" yaml:"estimated_total_resources"`
+	EstimatedCompliantResources    int `json:"estimated_compliant_resources" yaml:"estimated_compliant_resources"`
+	EstimatedNonCompliantResources int `json:"estimated_non_compliant_resources" yaml:"estimated_non_compliant_resources"`
+	EstimatedUnknownResources      int `json:"estimated_unknown_resources" yaml:"estimated_unknown_resources"`
+}
+
+// sampleEstimates accumulates the counters of a sampled run, each result standing for the
+// discovered resources of its weight
+type sampleEstimates struct {
+	total, compliant, nonCompliant, unknown float64
+}
+
+// add records a result standing for weight discovered resources
+func (e *sampleEstimates) add(result *ResourceResult, weight float64) {
+	e.total += weight
+	switch {
+	case result.IsUnknown:
+		e.unknown += weight
+	case result.IsCompliant:
+		e.compliant += weight
+	default:
+		e.nonCompliant += weight
+	}
+}
+
+// newSampleSummary returns the summary of a run of the sample, its estimates rounded to
+// whole resources
+func newSampleSummary(sample inspector.Sample, sampling *inspector.SampleInfo, estimates sampleEstimates) *SampleSummary {
+	summary := &SampleSummary{
+		Sample: sample.String(),
+		Seed:   sample.Seed,
+
+		EstimatedTotalResources:        int(math.Round(estimates.total)),
+		EstimatedCompliantResources:    int(math.Round(estimates.compliant)),
+		EstimatedNonCompliantResources: int(math.Round(estimates.nonCompliant)),
+		EstimatedUnknownResources:      int(math.Round(estimates.unknown)),
+	}
+	if sampling != nil {
+		summary.DiscoveredResources = sampling.Discovered
+		summary.SampledResources = sampling.Sampled
+	}
+	return summary
+}
+
+// sampledResources returns the resources discovered and sampled by the sampled inspection
+// results, nil when none is sampled
+func sampledResources(results map[string]*inspector.InspectResult) *inspector.SampleInfo {
+	var sampling *inspector.SampleInfo
+	for _, result := range results {
+		if result.Sampling != nil {
+			sampling = addSampling(sampling, result.Sampling)
+		}
+	}
+	return sampling
+}
+
+// addSampling adds the resources discovered and sampled by an inspection result to the
+// totals, which are allocated when nil
+func addSampling(totals, sampling *inspector.SampleInfo) *inspector.SampleInfo {
+	if totals == nil {
+		totals = &inspector.SampleInfo{}
+	}
+	totals.Discovered += sampling.Discovered
+	totals.Sampled += sampling.Sampled
+	return totals
+}
@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/inspector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunnerReportSampleEstimates(t *testing.T) {
+	t.Parallel()
+
+	runner, err := New(newTestConfig(), Options{Sample: inspector.Sample{Percent: 10, Seed: 3}})
+	require.NoError(t, err)
+
+	// The three buckets are sampled out of 30, an unsampled instance stands for itself
+	scan := newTestScan()
+	scan.Results["s3"].Sampling = &inspector.SampleInfo{Discovered: 30, Sampled: 3}
+	scan.Results["ec2"] = &inspector.InspectResult{
+		Resources:      []inspector.ResourceMetadata{{ID: "i-0abc", Type: "ec2", Tags: map[string]string{"Environment": "prod", "Owner": "web"}}},
+		TotalResources: 1,
+	}
+	scan.Sampling = &inspector.SampleInfo{Discovered: 30, Sampled: 3}
+
+	summary := mustReport(t, runner, scan).Summary
+	assert.Equal(t, 4, summary.TotalResources, "the counters only cover the sampled resources")
+	assert.Equal(t, &SampleSummary{
+		Sample:                         "10%",
+		Seed:                           3,
+		DiscoveredResources:            30,
+		SampledResources:               3,
+		EstimatedTotalResources:        31,
+		EstimatedCompliantResources:    11,
+		EstimatedNonCompliantResources: 20,
+	}, summary.Sample)
+}
+
+func TestRunnerReportWithoutSample(t *testing.T) {
+	t.Parallel()
+
+	runner, err := New(newTestConfig(), Options{})
+	require.NoError(t, err)
+
+	assert.Nil(t, mustReport(t, runner, newTestScan()).Summary.Sample)
+}
+
+func TestNew_RejectsIncrementalSample(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(newTestConfig(), Options{
+		Sample:   inspector.Sample{Count: 100},
+		Previous: inspector.NewPreviousScan(nil),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a sampled scan cannot be incremental")
+}
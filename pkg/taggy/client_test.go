@@ -71,7 +71,7 @@ func TestNewTaggyClient(t *testing.T) {
 	require.NotNil(t, client)
 
 	assert.NotNil(t, client.Config())
-	assert.Equal(t, "1.0", client.Config().Version)
+	assert.Equal(t, "1.1", client.Config().Version)
 	assert.Equal(t, "specific", client.Config().AWS.Regions.Mode)
 	assert.Equal(t, 1, len(client.Config().AWS.Regions.List))
 	assert.Equal(t, "us-west-2", client.Config().AWS.Regions.List[0])
@@ -0,0 +1,186 @@
+package tfgen
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Style determines the Terraform construct holding the generated tags
+type Style string
+
+const (
+	// StyleResource wraps the tags in an example resource block
+	StyleResource Style = "resource"
+
+	// StyleLocals emits the tags as a common_tags local value
+	StyleLocals Style = "locals"
+
+	// StyleVariable emits the tags as the default of a tags variable
+	StyleVariable Style = "variable"
+)
+
+const (
+	// commonTagsLocal is the name of the local value holding the common tags
+	commonTagsLocal = "common_tags"
+
+	// tagsVariable is the name of the variable holding the common tags
+	tagsVariable = "tags"
+)
+
+// GenerateOptions controls the shape of the generated Terraform code
+type GenerateOptions struct {
+	// Style is the Terraform construct holding the tags, defaults to StyleResource
+	Style Style
+
+	// MergeExpression splits the tags between the common tags of the compliance level and
+	// the additions of the resource type, combined with merge(local.common_tags, { ... })
+	MergeExpression bool
+}
+
+// ParseStyle converts a style name into a Style
+func ParseStyle(name string) (Style, error) {
+	switch style := Style(name); style {
+	case StyleResource, StyleLocals, StyleVariable:
+		return style, nil
+	default:
+		return "", fmt.Errorf("unsupported style %q, expected one of: %s, %s, %s", name, StyleLocals, StyleResource, StyleVariable)
+	}
+}
+
+// GenerateTagsWithOptions generates Terraform HCL tags for a specific resource type using
+// the given output style
+func (g *TagGenerator) GenerateTagsWithOptions(resourceType string, opts GenerateOptions) (*hclwrite.File, error) {
+	// Retrieve resource-specific configuration
+	resourceConfig, exists := g.config.Resources[resourceType]
+	if !exists {
+		return nil, fmt.Errorf("no configuration found for resource type: %s", resourceType)
+	}
+
+	style := opts.Style
+	if style == "" {
+		style = StyleResource
+	}
+	if _, err := ParseStyle(string(style)); err != nil {
+		return nil, err
+	}
+
+	commonTags, err := g.generateCommonTags(resourceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate tags for %s: %w", resourceType, err)
+	}
+
+	resourceTags := make(map[string]string)
+	if err := g.applyResourceTagCriteria(resourceTags, resourceConfig); err != nil {
+		return nil, fmt.Errorf("failed to generate tags for %s: %w", resourceType, err)
+	}
+
+	// Never emit tags that a scan of the same configuration would report as non-compliant
+	if err := g.VerifyResource(resourceType, mergeTags(commonTags, resourceTags)); err != nil {
+		return nil, fmt.Errorf("generated tags for %s are not compliant: %w", resourceType, err)
+	}
+
+	file := hclwrite.NewFile()
+	body := file.Body()
+
+	// Add file header as a comment
+	body.AppendUnstructuredTokens(hclwrite.Tokens{
+		{
+			Type:  hclsyntax.TokenComment,
+			Bytes: []byte(g.generateFileHeader(resourceType)),
+		},
+	})
+
+	if !opts.MergeExpression {
+		tags := mergeTags(commonTags, resourceTags)
+
+		switch style {
+		case StyleLocals:
+			body.AppendNewBlock("locals", nil).Body().SetAttributeValue(commonTagsLocal, tagsValue(tags))
+		case StyleVariable:
+			appendTagsVariable(body, tags)
+		default:
+			body.AppendNewBlock("resource", []string{resourceType, "example"}).Body().
+				SetAttributeValue("tags", tagsValue(tags))
+		}
+
+		return file, nil
+	}
+
+	// Only keep the resource tags adding to or overriding the common tags
+	for key, value := range resourceTags {
+		if common, exists := commonTags[key]; exists && common == value {
+			delete(resourceTags, key)
+		}
+	}
+
+	switch style {
+	case StyleLocals:
+		locals := body.AppendNewBlock("locals", nil).Body()
+		locals.SetAttributeValue(commonTagsLocal, tagsValue(commonTags))
+		locals.SetAttributeRaw(resourceType+"_tags", mergeTokens(hcl.Traversal{
+			hcl.TraverseRoot{Name: "local"},
+			hcl.TraverseAttr{Name: commonTagsLocal},
+		}, resourceTags))
+	case StyleVariable:
+		appendTagsVariable(body, commonTags)
+		body.AppendNewline()
+		body.AppendNewBlock("locals", nil).Body().SetAttributeRaw(resourceType+"_tags", mergeTokens(hcl.Traversal{
+			hcl.TraverseRoot{Name: "var"},
+			hcl.TraverseAttr{Name: tagsVariable},
+		}, resourceTags))
+	default:
+		body.AppendNewBlock("locals", nil).Body().SetAttributeValue(commonTagsLocal, tagsValue(commonTags))
+		body.AppendNewline()
+		body.AppendNewBlock("resource", []string{resourceType, "example"}).Body().
+			SetAttributeRaw("tags", mergeTokens(hcl.Traversal{
+				hcl.TraverseRoot{Name: "local"},
+				hcl.TraverseAttr{Name: commonTagsLocal},
+			}, resourceTags))
+	}
+
+	return file, nil
+}
+
+// appendTagsVariable appends a tags variable defaulting to the given tags
+func appendTagsVariable(body *hclwrite.Body, tags map[string]string) {
+	variable := body.AppendNewBlock("variable", []string{tagsVariable}).Body()
+	variable.SetAttributeValue("description", cty.StringVal("Tags applied to every resource"))
+	variable.SetAttributeRaw("type", hclwrite.TokensForFunctionCall("map", hclwrite.TokensForIdentifier("string")))
+	variable.SetAttributeValue("default", tagsValue(tags))
+}
+
+// mergeTokens builds a merge(<base>, { ... }) expression adding the given tags to a base map
+func mergeTokens(base hcl.Traversal, tags map[string]string) hclwrite.Tokens {
+	return hclwrite.TokensForFunctionCall("merge",
+		hclwrite.TokensForTraversal(base),
+		hclwrite.TokensForValue(tagsValue(tags)),
+	)
+}
+
+// tagsValue converts tags into a map value, which may be empty
+func tagsValue(tags map[string]string) cty.Value {
+	if len(tags) == 0 {
+		return cty.MapValEmpty(cty.String)
+	}
+
+	values := make(map[string]cty.Value, len(tags))
+	for key, value := range tags {
+		values[key] = cty.StringVal(value)
+	}
+	return cty.MapVal(values)
+}
+
+// mergeTags returns the union of the given tag sets, later sets overriding earlier ones
+func mergeTags(tagSets ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, tags := range tagSets {
+		for key, value := range tags {
+			merged[key] = value
+		}
+	}
+	return merged
+}
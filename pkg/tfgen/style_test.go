@@ -0,0 +1,84 @@
+package tfgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateTagsWithOptions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		opts     GenerateOptions
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "resource style is the default",
+			opts:     GenerateOptions{},
+			contains: []string{`resource "s3" "example"`, "tags = {"},
+			excludes: []string{"locals {", "merge("},
+		},
+		{
+			name:     "locals style",
+			opts:     GenerateOptions{Style: StyleLocals},
+			contains: []string{"locals {", "common_tags = {", "environment"},
+			excludes: []string{"resource ", "merge("},
+		},
+		{
+			name:     "variable style",
+			opts:     GenerateOptions{Style: StyleVariable},
+			contains: []string{`variable "tags"`, "type        = map(string)", "default     = {"},
+			excludes: []string{"merge("},
+		},
+		{
+			name:     "locals style with merge expression",
+			opts:     GenerateOptions{Style: StyleLocals, MergeExpression: true},
+			contains: []string{"common_tags = {", "s3_tags     = merge(local.common_tags, {"},
+		},
+		{
+			name:     "variable style with merge expression",
+			opts:     GenerateOptions{Style: StyleVariable, MergeExpression: true},
+			contains: []string{`variable "tags"`, "s3_tags = merge(var.tags, {"},
+		},
+		{
+			name:     "resource style with merge expression",
+			opts:     GenerateOptions{Style: StyleResource, MergeExpression: true},
+			contains: []string{"common_tags = {", "tags = merge(local.common_tags, {"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			generator, err := NewTagGenerator(createVerifyTestConfig())
+			require.NoError(t, err)
+
+			file, err := generator.GenerateTagsWithOptions("s3", tc.opts)
+			require.NoError(t, err)
+
+			output := string(file.Bytes())
+			for _, expected := range tc.contains {
+				assert.Contains(t, output, expected)
+			}
+			for _, unexpected := range tc.excludes {
+				assert.NotContains(t, output, unexpected)
+			}
+		})
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	t.Parallel()
+
+	style, err := ParseStyle("variable")
+	require.NoError(t, err)
+	assert.Equal(t, StyleVariable, style)
+
+	_, err = ParseStyle("module")
+	assert.Error(t, err)
+}
@@ -7,9 +7,7 @@
 	"time"
 
 	"github.com/Excoriate/aws-taggy/pkg/configuration"
-	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
-	"github.com/zclconf/go-cty/cty"
 )
 
 // TagGenerator is responsible for generating Terraform HCL tags
@@ -25,46 +23,29 @@ func NewTagGenerator(config *configuration.TaggyScanConfig) (*TagGenerator, erro
 	return &TagGenerator{config: config}, nil
 }
 
-// GenerateTags generates Terraform HCL tags for a specific resource type
+// GenerateTags generates Terraform HCL tags for a specific resource type, wrapped in an example resource block
 func (g *TagGenerator) GenerateTags(resourceType string) (*hclwrite.File, error) {
-	// Retrieve resource-specific configuration
-	resourceConfig, exists := g.config.Resources[resourceType]
-	if !exists {
-		return nil, fmt.Errorf("no configuration found for resource type: %s", resourceType)
-	}
-
-	// Create a new HCL file
-	file := hclwrite.NewFile()
-
-	// Add file header as a comment
-	file.Body().AppendUnstructuredTokens(hclwrite.Tokens{
-		{
-			Type:  hclsyntax.TokenComment,
-			Bytes: []byte(g.generateFileHeader(resourceType)),
-		},
-	})
+	return g.GenerateTagsWithOptions(resourceType, GenerateOptions{Style: StyleResource})
+}
 
-	// Generate tags based on resource configuration
-	tags, err := g.generateComplianceTags(resourceConfig)
+// generateComplianceTags creates tags that comply with the configuration
+func (g *TagGenerator) generateComplianceTags(resourceConfig configuration.ResourceConfig) (map[string]string, error) {
+	tags, err := g.generateCommonTags(resourceConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate tags for %s: %w", resourceType, err)
+		return nil, err
 	}
 
-	// Add tags to the file
-	block := file.Body().AppendNewBlock("resource", []string{resourceType, "example"})
-
-	// Convert tags to cty.Value
-	tagsMap := make(map[string]cty.Value)
-	for k, v := range tags {
-		tagsMap[k] = cty.StringVal(v)
+	// Apply resource-specific tag criteria
+	if err := g.applyResourceTagCriteria(tags, resourceConfig); err != nil {
+		return nil, err
 	}
-	block.Body().SetAttributeValue("tags", cty.MapVal(tagsMap))
 
-	return file, nil
+	return tags, nil
 }
 
-// generateComplianceTags creates tags that comply with the configuration
-func (g *TagGenerator) generateComplianceTags(resourceConfig configuration.ResourceConfig) (map[string]string, error) {
+// generateCommonTags creates the tags required by the compliance level of a resource type,
+// along with the global specific tags
+func (g *TagGenerator) generateCommonTags(resourceConfig configuration.ResourceConfig) (map[string]string, error) {
 	tags := make(map[string]string)
 
 	// Determine compliance level
@@ -73,15 +54,22 @@ func (g *TagGenerator) generateComplianceTags(resourceConfig configuration.Resou
 		complianceLevel = g.config.Global.TagCriteria.ComplianceLevel
 	}
 
-	// Generate tags based on compliance level
-	complianceLevelConfig, exists := g.config.ComplianceLevels[complianceLevel]
-	if !exists {
+	// Generate tags based on compliance level, including the tags of the levels it extends
+	if _, exists := g.config.ComplianceLevels[complianceLevel]; !exists {
 		return nil, fmt.Errorf("unknown compliance level: %s", complianceLevel)
 	}
+	complianceLevelConfig, err := g.config.ResolveComplianceLevel(complianceLevel)
+	if err != nil {
+		return nil, err
+	}
 
 	// Add required tags from compliance level
 	for _, requiredTag := range complianceLevelConfig.RequiredTags {
-		tags[requiredTag] = g.generateTagValue(requiredTag)
+		value, err := g.generateTagValue(requiredTag)
+		if err != nil {
+			return nil, err
+		}
+		tags[requiredTag] = value
 	}
 
 	// Add specific tags from compliance level
@@ -89,9 +77,9 @@ func (g *TagGenerator) generateComplianceTags(resourceConfig configuration.Resou
 		tags[key] = value
 	}
 
-	// Apply resource-specific tag criteria
-	if err := g.applyResourceTagCriteria(tags, resourceConfig); err != nil {
-		return nil, err
+	// Add the specific tags every resource must carry
+	for key, value := range g.config.Global.TagCriteria.SpecificTags {
+		tags[key] = value
 	}
 
 	return tags, nil
@@ -104,7 +92,11 @@ func (g *TagGenerator) applyResourceTagCriteria(tags map[string]string, resource
 	// Add resource-specific required tags
 	for _, requiredTag := range resourceCriteria.RequiredTags {
 		// Override or add to existing tags
-		tags[requiredTag] = g.generateTagValue(requiredTag)
+		value, err := g.generateTagValue(requiredTag)
+		if err != nil {
+			return err
+		}
+		tags[requiredTag] = value
 	}
 
 	// Apply specific tags
@@ -116,30 +108,36 @@ func (g *TagGenerator) applyResourceTagCriteria(tags map[string]string, resource
 }
 
 // generateTagValue creates a tag value based on configuration
-func (g *TagGenerator) generateTagValue(tagName string) string {
+func (g *TagGenerator) generateTagValue(tagName string) (string, error) {
 	// Priority:
 	// 1. Specific tag values from compliance levels
-	// 2. Allowed values
-	// 3. Pattern rules
-	// 4. Default generation
+	// 2. Templates from tag_defaults
+	// 3. Allowed values
+	// 4. Pattern rules
+	// 5. Default generation
 
 	// Check compliance levels for specific tags
 	for _, levelConfig := range g.config.ComplianceLevels {
 		if value, exists := levelConfig.SpecificTags[tagName]; exists {
-			return g.applyTagConstraints(tagName, value)
+			return g.applyTagConstraints(tagName, value), nil
 		}
 	}
 
+	// Render the configured default template
+	if text, exists := g.config.TagDefaults[tagName]; exists {
+		return g.renderTagDefault(tagName, text)
+	}
+
 	// Use allowed values if defined
 	if allowedValues, exists := g.config.TagValidation.AllowedValues[tagName]; exists {
 		if len(allowedValues) > 0 {
-			return g.applyTagConstraints(tagName, allowedValues[0])
+			return g.applyTagConstraints(tagName, allowedValues[0]), nil
 		}
 	}
 
 	// Use pattern rules if defined
 	if pattern, exists := g.config.TagValidation.PatternRules[tagName]; exists {
-		return g.generateValueForPattern(tagName, pattern)
+		return g.generateValueForPattern(tagName, pattern), nil
 	}
 
 	// Fallback to generic default with specific handling
@@ -154,7 +152,7 @@ func (g *TagGenerator) generateTagValue(tagName string) string {
 	default:
 		defaultValue = fmt.Sprintf("default-%s", strings.ToLower(tagName))
 	}
-	return g.applyTagConstraints(tagName, defaultValue)
+	return g.applyTagConstraints(tagName, defaultValue), nil
 }
 
 // generateValueForPattern creates a value matching a specific pattern
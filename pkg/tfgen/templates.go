@@ -0,0 +1,68 @@
+package tfgen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+)
+
+// templateFuncs is the function map available to tag_defaults templates
+var templateFuncs = template.FuncMap{
+	"env": os.Getenv,
+	"now": func(layout string) string {
+		return time.Now().Format(layout)
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// renderTagDefault evaluates the tag_defaults template of a tag and applies the tag
+// constraints to the result. The final value must satisfy the validation rules of the
+// configuration, so a template can never produce a non-compliant tag.
+func (g *TagGenerator) renderTagDefault(tagName, text string) (string, error) {
+	tmpl, err := template.New(tagName).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid tag_defaults template for %s: %w", tagName, err)
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, nil); err != nil {
+		return "", fmt.Errorf("failed to render tag_defaults template for %s: %w", tagName, err)
+	}
+
+	value := g.applyTagConstraints(tagName, rendered.String())
+	if err := g.validateTagValue(tagName, value); err != nil {
+		return "", fmt.Errorf("tag_defaults template for %s produced %q: %w", tagName, value, err)
+	}
+
+	return value, nil
+}
+
+// validateTagValue checks a generated value with the tag validator of the compliance scan, so
+// a template is held to exactly the rules a scan of the tagged resource would apply
+func (g *TagGenerator) validateTagValue(tagName, value string) error {
+	if value == "" {
+		return fmt.Errorf("value is empty")
+	}
+
+	result := compliance.NewTagValidator(g.config).ValidateTags(map[string]string{tagName: value})
+
+	// Only the violations of the rendered value matter: the others are about the tag key,
+	// which the template does not produce, or about the rest of the tag set
+	var messages []string
+	for _, violation := range result.Violations {
+		if violation.TagKey == tagName && violation.Value == value {
+			messages = append(messages, violation.Message)
+		}
+	}
+	if len(messages) > 0 {
+		return fmt.Errorf("%s", strings.Join(messages, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package tfgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTemplateTestConfig(tagDefaults map[string]string) *configuration.TaggyScanConfig {
+	return &configuration.TaggyScanConfig{
+		Global: configuration.GlobalConfig{
+			TagCriteria: configuration.TagCriteria{ComplianceLevel: "standard"},
+		},
+		Resources: map[string]configuration.ResourceConfig{
+			"s3": {
+				TagCriteria: configuration.TagCriteria{RequiredTags: []string{"Owner", "CreatedAt"}},
+			},
+		},
+		ComplianceLevels: map[string]configuration.ComplianceLevel{
+			"standard": {RequiredTags: []string{"Environment"}},
+		},
+		TagValidation: configuration.TagValidation{
+			AllowedValues: map[string][]string{
+				"Environment": {"production", "staging"},
+			},
+			PatternRules: map[string]string{
+				"Owner": `^[a-z0-9._-]+@company\.com$`,
+			},
+			CaseRules: map[string]configuration.CaseRule{
+				"Owner": {Case: configuration.CaseLowercase},
+			},
+		},
+		TagDefaults: tagDefaults,
+	}
+}
+
+func TestGenerateComplianceTags_TagDefaults(t *testing.T) {
+	t.Setenv("USER", "Jane.Doe")
+
+	tagDefaults := map[string]string{
+		"Owner":       `{{ env "USER" }}@company.com`,
+		"CreatedAt":   `{{ now "2006-01-02" }}`,
+		"Environment": `{{ lower "STAGING" }}`,
+	}
+	generator, err := NewTagGenerator(createTemplateTestConfig(tagDefaults))
+	require.NoError(t, err)
+
+	tags, err := generator.generateComplianceTags(generator.config.Resources["s3"])
+	require.NoError(t, err)
+
+	// The case rule lowercases the rendered owner before the pattern rule is checked
+	assert.Equal(t, "jane.doe@company.com", tags["Owner"])
+	assert.Equal(t, time.Now().Format("2006-01-02"), tags["CreatedAt"])
+	assert.Equal(t, "staging", tags["Environment"])
+}
+
+func TestGenerateTags_TagDefaultsErrors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		tagDefaults map[string]string
+	}{
+		{
+			name:        "Value violating pattern rule",
+			tagDefaults: map[string]string{"Owner": `{{ upper "nobody" }}`},
+		},
+		{
+			name:        "Value outside allowed values",
+			tagDefaults: map[string]string{"Environment": "sandbox"},
+		},
+		{
+			name:        "Invalid template",
+			tagDefaults: map[string]string{"CreatedAt": `{{ now "2006-01-02" `},
+		},
+		{
+			name:        "Unknown function",
+			tagDefaults: map[string]string{"CreatedAt": `{{ today }}`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			generator, err := NewTagGenerator(createTemplateTestConfig(tc.tagDefaults))
+			require.NoError(t, err)
+
+			_, err = generator.GenerateTags("s3")
+			assert.Error(t, err)
+		})
+	}
+}
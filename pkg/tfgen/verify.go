@@ -0,0 +1,53 @@
+package tfgen
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Excoriate/aws-taggy/pkg/compliance"
+)
+
+// Verify checks a set of tags against the compliance rules of the configuration, the same
+// way a scan would. The returned error lists every violation, which makes it usable for
+// hand-written tag maps as well as for generated ones.
+func (g *TagGenerator) Verify(tags map[string]string) error {
+	return g.VerifyResource("", tags)
+}
+
+// VerifyResource checks a set of tags like Verify, also applying the tag criteria of the
+// resource type. Tags AWS would refuse to write are reported even when the configuration
+// does not enforce the AWS tag limits.
+func (g *TagGenerator) VerifyResource(resourceType string, tags map[string]string) error {
+	ref := compliance.ResourceRef{Type: resourceType}
+	result := compliance.NewTagValidator(g.config).ValidateResource(ref, tags)
+
+	violations := make([]compliance.Violation, len(result.Violations))
+	copy(violations, result.Violations)
+	for _, violation := range compliance.CheckAWSTagLimits(tags) {
+		if !slices.ContainsFunc(violations, func(v compliance.Violation) bool {
+			return v.Type == violation.Type && v.TagKey == violation.TagKey
+		}) {
+			violations = append(violations, violation)
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].TagKey != violations[j].TagKey {
+			return violations[i].TagKey < violations[j].TagKey
+		}
+		return violations[i].Message < violations[j].Message
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d tag compliance violation(s):", len(violations))
+	for _, violation := range violations {
+		fmt.Fprintf(&b, "\n  - [%s] %s", violation.Type, violation.Message)
+	}
+
+	return fmt.Errorf("%s", b.String())
+}
@@ -0,0 +1,132 @@
+package tfgen
+
+import (
+	"testing"
+
+	"github.com/Excoriate/aws-taggy/pkg/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createVerifyTestConfig returns a configuration whose generated tags satisfy its own rules
+func createVerifyTestConfig() *configuration.TaggyScanConfig {
+	return &configuration.TaggyScanConfig{
+		Global: configuration.GlobalConfig{
+			TagCriteria: configuration.TagCriteria{
+				ComplianceLevel: "standard",
+				RequiredTags:    []string{"environment", "owner"},
+			},
+		},
+		Resources: map[string]configuration.ResourceConfig{
+			"s3": {
+				TagCriteria: configuration.TagCriteria{RequiredTags: []string{"owner", "data-classification"}},
+			},
+		},
+		ComplianceLevels: map[string]configuration.ComplianceLevel{
+			"standard": {
+				RequiredTags: []string{"environment"},
+				SpecificTags: map[string]string{"managed-by": "terraform"},
+			},
+		},
+		TagValidation: configuration.TagValidation{
+			AllowedValues: map[string][]string{
+				"environment": {"production", "staging"},
+			},
+			PatternRules: map[string]string{
+				"owner": `^[a-z0-9._-]+@company\.com$`,
+			},
+			CaseRules: map[string]configuration.CaseRule{
+				"environment": {Case: configuration.CaseLowercase},
+			},
+			KeyFormatRules: []configuration.KeyFormatRule{
+				{Pattern: `^[a-z][a-z0-9-]*$`, Message: "Tag keys must be lowercase"},
+			},
+			ProhibitedTags: []string{"aws:"},
+		},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		tags       map[string]string
+		violations []string
+	}{
+		{
+			name: "Compliant tags",
+			tags: map[string]string{"environment": "production", "owner": "team@company.com"},
+		},
+		{
+			name:       "Missing required tag",
+			tags:       map[string]string{"environment": "production"},
+			violations: []string{"[missing_tags] Missing required tags: [owner]"},
+		},
+		{
+			name: "Several violations",
+			tags: map[string]string{"environment": "sandbox", "owner": "nobody", "Team": "platform"},
+			violations: []string{
+				"[invalid_value] Tag value for 'environment' must be one of: [production staging]",
+				`[pattern_violation] Tag value for 'owner' does not match required pattern ^[a-z0-9._-]+@company\.com$`,
+				"[invalid_key_format] Tag key 'Team': Tag keys must be lowercase",
+			},
+		},
+	}
+
+	generator, err := NewTagGenerator(createVerifyTestConfig())
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := generator.Verify(tc.tags)
+			if len(tc.violations) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			for _, violation := range tc.violations {
+				assert.Contains(t, err.Error(), violation)
+			}
+		})
+	}
+}
+
+func TestGenerateTags_RejectsNonCompliantTags(t *testing.T) {
+	t.Parallel()
+
+	config := createVerifyTestConfig()
+	// The fallback value of a tag without rules cannot satisfy this pattern
+	config.TagValidation.PatternRules["data-classification"] = `^DC-[0-9]{3}$`
+
+	generator, err := NewTagGenerator(config)
+	require.NoError(t, err)
+
+	_, err = generator.GenerateTags("s3")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "generated tags for s3 are not compliant")
+	assert.Contains(t, err.Error(), "Tag value for 'data-classification' does not match required pattern")
+}
+
+func TestVerify_AWSTagLimits(t *testing.T) {
+	t.Parallel()
+
+	config := createVerifyTestConfig()
+	config.TagValidation.EnforceAWSLimits = new(bool)
+
+	generator, err := NewTagGenerator(config)
+	require.NoError(t, err)
+
+	err = generator.Verify(map[string]string{
+		"environment": "production",
+		"owner":       "team@company.com",
+		"aws:team":    "platform",
+		"cost-center": "cc#1234",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "[aws_tag_limit_violation] Tag 'aws:team' would be rejected by AWS: key starts with the reserved aws: prefix")
+	assert.Contains(t, err.Error(), "[aws_tag_limit_violation] Tag 'cost-center' would be rejected by AWS: value contains characters AWS does not allow: \"#\"")
+}
@@ -0,0 +1,366 @@
+// Package tfplan reads the AWS resources of a Terraform plan or state and their tags, so
+// their tag compliance is checked before they are applied, without AWS credentials. It
+// reads the output of terraform show -json, for a plan or a state, and terraform.tfstate
+// files.
+package tfplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/Excoriate/aws-taggy/pkg/constants"
+)
+
+// Kind is the kind of document a Plan was read from
+type Kind string
+
+const (
+	// KindPlan is the output of terraform show -json for a saved plan
+	KindPlan Kind = "plan"
+
+	// KindState is the output of terraform show -json for the state, or a state file
+	KindState Kind = "state"
+)
+
+// Reasons resources are skipped
+const (
+	// SkipReasonUnmapped is the reason of the resources whose Terraform type has no
+	// aws-taggy resource type
+	SkipReasonUnmapped = "no aws-taggy resource type for this Terraform type"
+
+	// SkipReasonTagsUnknown is the reason of the resources whose tags are all computed
+	// during the apply
+	SkipReasonTagsUnknown = "tags known after apply"
+)
+
+// Tag attributes of the AWS provider: tags_all holds the tags of the resource merged with
+// the default_tags of the provider, tags the tags of the resource only
+const (
+	attributeTagsAll = "tags_all"
+	attributeTags    = "tags"
+)
+
+// managedMode is the mode of resources, data sources being of the data mode
+const managedMode = "managed"
+
+// ResourceTypes maps the Terraform resource types to the aws-taggy resource types
+var ResourceTypes = map[string]string{
+	"aws_s3_bucket":                     constants.ResourceTypeS3,
+	"aws_instance":                      constants.ResourceTypeEC2,
+	"aws_vpc":                           constants.ResourceTypeVPC,
+	"aws_default_vpc":                   constants.ResourceTypeVPC,
+	"aws_cloudwatch_log_group":          constants.ResourceTypeCloudWatchLogs,
+	"aws_db_instance":                   constants.ResourceTypeRDS,
+	"aws_lambda_function":               constants.ResourceTypeLambda,
+	"aws_eks_cluster":                   constants.ResourceTypeEKS,
+	"aws_ecr_repository":                constants.ResourceTypeECR,
+	"aws_cloudfront_distribution":       constants.ResourceTypeCloudfront,
+	"aws_route53_zone":                  constants.ResourceTypeRoute53,
+	"aws_sns_topic":                     constants.ResourceTypeSNS,
+	"aws_sqs_queue":                     constants.ResourceTypeSQS,
+	"aws_ebs_volume":                    constants.ResourceTypeEBS,
+	"aws_ebs_snapshot":                  constants.ResourceTypeEBS,
+	"aws_api_gateway_rest_api":          constants.ResourceTypeAPIGateway,
+	"aws_apigatewayv2_api":              constants.ResourceTypeAPIGateway,
+	"aws_elasticache_cluster":           constants.ResourceTypeElastiCache,
+	"aws_elasticache_replication_group": constants.ResourceTypeElastiCache,
+	"aws_security_group":                constants.ResourceTypeSecurityGroup,
+	"aws_default_security_group":        constants.ResourceTypeSecurityGroup,
+	"aws_cloudwatch_metric_alarm":       constants.ResourceTypeCloudWatchAlarms,
+	"aws_cloudwatch_composite_alarm":    constants.ResourceTypeCloudWatchAlarms,
+	"aws_nat_gateway":                   constants.ResourceTypeNATGateway,
+	"aws_internet_gateway":              constants.ResourceTypeInternetGateway,
+}
+
+// Resource is a resource of a plan or state whose Terraform type maps to an aws-taggy type
+type Resource struct {
+	// Address is the Terraform address of the resource, e.g. module.network.aws_vpc.main[0]
+	Address string `json:"address" yaml:"address"`
+
+	// TerraformType is the Terraform type of the resource, e.g. aws_s3_bucket, and Type its
+	// aws-taggy resource type, e.g. s3
+	TerraformType string `json:"terraform_type" yaml:"terraform_type"`
+	Type          string `json:"type" yaml:"type"`
+
+	// Action is the planned action of the resource, create, update, replace or no-op, empty
+	// for resources read from a state
+	Action string `json:"action,omitempty" yaml:"action,omitempty"`
+
+	// Tags are the tags of the resource, the default tags of the provider included when the
+	// plan or state knows them
+	Tags map[string]string `json:"tags" yaml:"tags"`
+
+	// UnknownTags are the keys of the tags whose values are computed during the apply,
+	// missing from Tags
+	UnknownTags []string `json:"unknown_tags,omitempty" yaml:"unknown_tags,omitempty"`
+}
+
+// SkippedResource is a resource of a plan or state whose tags are not checked
+type SkippedResource struct {
+	Address       string `json:"address" yaml:"address"`
+	TerraformType string `json:"terraform_type" yaml:"terraform_type"`
+	Reason        string `json:"reason" yaml:"reason"`
+}
+
+// Plan holds the resources of a plan or state
+type Plan struct {
+	Kind Kind
+
+	// Resources are the resources whose tags are checked, in the order of the document
+	Resources []Resource
+
+	// Skipped are the resources whose tags are not checked. Data sources and resources
+	// planned for deletion are left out of both lists.
+	Skipped []SkippedResource
+}
+
+// document holds the top level fields telling plans and states apart
+type document struct {
+	// FormatVersion is set by terraform show -json, Version by state files
+	FormatVersion string `json:"format_version"`
+	Version       int    `json:"version"`
+
+	// Plans
+	PlannedValues   json.RawMessage  `json:"planned_values"`
+	ResourceChanges []resourceChange `json:"resource_changes"`
+
+	// Outputs of terraform show -json for the state
+	Values *stateValues `json:"values"`
+
+	// State files
+	Resources []stateFileResource `json:"resources"`
+}
+
+type resourceChange struct {
+	Address string `json:"address"`
+	Mode    string `json:"mode"`
+	Type    string `json:"type"`
+	Deposed string `json:"deposed"`
+	Change  struct {
+		Actions      []string       `json:"actions"`
+		After        map[string]any `json:"after"`
+		AfterUnknown map[string]any `json:"after_unknown"`
+	} `json:"change"`
+}
+
+type stateValues struct {
+	RootModule stateModule `json:"root_module"`
+}
+
+type stateModule struct {
+	Resources    []stateResource `json:"resources"`
+	ChildModules []stateModule   `json:"child_modules"`
+}
+
+type stateResource struct {
+	Address string         `json:"address"`
+	Mode    string         `json:"mode"`
+	Type    string         `json:"type"`
+	Values  map[string]any `json:"values"`
+}
+
+type stateFileResource struct {
+	Module    string `json:"module"`
+	Mode      string `json:"mode"`
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	Instances []struct {
+		IndexKey   any            `json:"index_key"`
+		Attributes map[string]any `json:"attributes"`
+	} `json:"instances"`
+}
+
+// Load reads the plan or state of a file, see Parse
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Terraform plan %s: %w", path, err)
+	}
+
+	plan, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Terraform plan %s: %w", path, err)
+	}
+	return plan, nil
+}
+
+// Parse reads the resources of a plan or state: the output of terraform show -json for a
+// saved plan or for the state, or a version 4 state file. The resources of a plan are
+// read as they will be once it is applied.
+func Parse(data []byte) (*Plan, error) {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	switch {
+	case doc.Version != 0:
+		if doc.Version != 4 {
+			return nil, fmt.Errorf("unsupported state file version %d, only version 4 state files are supported", doc.Version)
+		}
+		return parseStateFile(doc.Resources), nil
+	case doc.FormatVersion == "":
+		return nil, fmt.Errorf("not a Terraform plan or state, expected the output of terraform show -json or a state file")
+	case doc.PlannedValues != nil || doc.ResourceChanges != nil:
+		return parsePlan(doc.ResourceChanges), nil
+	default:
+		plan := &Plan{Kind: KindState}
+		if doc.Values != nil {
+			plan.addModule(doc.Values.RootModule)
+		}
+		return plan, nil
+	}
+}
+
+// parsePlan reads the resources of the resource changes of a plan
+func parsePlan(changes []resourceChange) *Plan {
+	plan := &Plan{Kind: KindPlan}
+	for _, change := range changes {
+		action := plannedAction(change.Change.Actions)
+		if change.Mode != managedMode || change.Deposed != "" || action == "" {
+			continue
+		}
+		plan.add(change.Address, change.Type, action, change.Change.After, change.Change.AfterUnknown)
+	}
+	return plan
+}
+
+// addModule reads the resources of a module of the output of terraform show -json for the
+// state, then those of its child modules
+func (p *Plan) addModule(module stateModule) {
+	for _, resource := range module.Resources {
+		if resource.Mode == managedMode {
+			p.add(resource.Address, resource.Type, "", resource.Values, nil)
+		}
+	}
+	for _, child := range module.ChildModules {
+		p.addModule(child)
+	}
+}
+
+// parseStateFile reads the resource instances of a state file
+func parseStateFile(resources []stateFileResource) *Plan {
+	plan := &Plan{Kind: KindState}
+	for _, resource := range resources {
+		if resource.Mode != managedMode {
+			continue
+		}
+
+		address := resource.Type + "." + resource.Name
+		if resource.Module != "" {
+			address = resource.Module + "." + address
+		}
+		for _, instance := range resource.Instances {
+			plan.add(address+indexSuffix(instance.IndexKey), resource.Type, "", instance.Attributes, nil)
+		}
+	}
+	return plan
+}
+
+// add adds a resource of the plan or state, or skips it when its type is unmapped or its
+// tags are unknown
+func (p *Plan) add(address, terraformType, action string, values, unknown map[string]any) {
+	resourceType, mapped := ResourceTypes[terraformType]
+	if !mapped {
+		p.skip(address, terraformType, SkipReasonUnmapped)
+		return
+	}
+
+	tags, unknownTags, known := resourceTags(values, unknown)
+	if !known {
+		p.skip(address, terraformType, SkipReasonTagsUnknown)
+		return
+	}
+
+	p.Resources = append(p.Resources, Resource{
+		Address:       address,
+		TerraformType: terraformType,
+		Type:          resourceType,
+		Action:        action,
+		Tags:          tags,
+		UnknownTags:   unknownTags,
+	})
+}
+
+func (p *Plan) skip(address, terraformType, reason string) {
+	p.Skipped = append(p.Skipped, SkippedResource{Address: address, TerraformType: terraformType, Reason: reason})
+}
+
+// resourceTags returns the tags of the attribute values of a resource, from tags_all when
+// known and from tags otherwise, with the sorted keys of the tags whose values are
+// unknown. It reports false when neither attribute is known.
+func resourceTags(values, unknown map[string]any) (map[string]string, []string, bool) {
+	for _, attribute := range []string{attributeTagsAll, attributeTags} {
+		if unknown[attribute] == true {
+			continue
+		}
+
+		tagValues, ok := values[attribute].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		tags := make(map[string]string, len(tagValues))
+		for key, value := range tagValues {
+			switch value := value.(type) {
+			case string:
+				tags[key] = value
+			case nil:
+				// Unknown values are null in the planned values
+				continue
+			default:
+				tags[key] = fmt.Sprint(value)
+			}
+		}
+
+		var unknownTags []string
+		if unknownValues, ok := unknown[attribute].(map[string]any); ok {
+			for key, isUnknown := range unknownValues {
+				if isUnknown == true {
+					unknownTags = append(unknownTags, key)
+				}
+			}
+			slices.Sort(unknownTags)
+		}
+		return tags, unknownTags, true
+	}
+
+	// A resource without tags has null tags, which are known
+	if unknown[attributeTagsAll] == true || unknown[attributeTags] == true {
+		return nil, nil, false
+	}
+	return map[string]string{}, nil, true
+}
+
+// plannedAction returns the action of the actions of a resource change, empty for the
+// resources the plan deletes, forgets or reads
+func plannedAction(actions []string) string {
+	switch {
+	case slices.Equal(actions, []string{"create"}):
+		return "create"
+	case slices.Equal(actions, []string{"update"}):
+		return "update"
+	case slices.Equal(actions, []string{"no-op"}):
+		return "no-op"
+	case slices.Equal(actions, []string{"delete", "create"}), slices.Equal(actions, []string{"create", "delete"}):
+		return "replace"
+	default:
+		return ""
+	}
+}
+
+// indexSuffix returns the address suffix of the index key of a resource instance of a state
+// file: [0] for count, ["key"] for for_each and nothing for single instances
+func indexSuffix(indexKey any) string {
+	switch key := indexKey.(type) {
+	case float64:
+		return "[" + strconv.FormatFloat(key, 'f', -1, 64) + "]"
+	case string:
+		return "[" + strconv.Quote(key) + "]"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,160 @@
+package tfplan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Plan(t *testing.T) {
+	plan, err := Load(filepath.Join("testdata", "plan.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, KindPlan, plan.Kind)
+	assert.Equal(t, []Resource{
+		{
+			Address:       "aws_s3_bucket.logs",
+			TerraformType: "aws_s3_bucket",
+			Type:          "s3",
+			Action:        "create",
+			Tags:          map[string]string{"Owner": "platform", "Environment": "production"},
+		},
+		{
+			Address:       "module.app.aws_instance.web[0]",
+			TerraformType: "aws_instance",
+			Type:          "ec2",
+			Action:        "replace",
+			Tags:          map[string]string{"Owner": "web"},
+			UnknownTags:   []string{"Name"},
+		},
+		{
+			Address:       "aws_sqs_queue.jobs",
+			TerraformType: "aws_sqs_queue",
+			Type:          "sqs",
+			Action:        "no-op",
+			Tags:          map[string]string{},
+		},
+	}, plan.Resources)
+
+	// The deleted database and the data source are left out of both lists
+	assert.Equal(t, []SkippedResource{
+		{Address: "aws_iam_role.deploy", TerraformType: "aws_iam_role", Reason: SkipReasonUnmapped},
+		{Address: "aws_sns_topic.alerts", TerraformType: "aws_sns_topic", Reason: SkipReasonTagsUnknown},
+	}, plan.Skipped)
+}
+
+func TestLoad_State(t *testing.T) {
+	plan, err := Load(filepath.Join("testdata", "state.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, KindState, plan.Kind)
+	assert.Equal(t, []Resource{
+		{
+			Address:       "aws_vpc.main",
+			TerraformType: "aws_vpc",
+			Type:          "vpc",
+			Tags:          map[string]string{"Name": "main", "Owner": "network"},
+		},
+		{
+			Address:       `module.queues.aws_sqs_queue.jobs["orders"]`,
+			TerraformType: "aws_sqs_queue",
+			Type:          "sqs",
+			Tags:          map[string]string{"Owner": "orders"},
+		},
+	}, plan.Resources)
+	assert.Equal(t, []SkippedResource{
+		{Address: "module.queues.random_id.suffix", TerraformType: "random_id", Reason: SkipReasonUnmapped},
+	}, plan.Skipped)
+}
+
+func TestLoad_StateFile(t *testing.T) {
+	plan, err := Load(filepath.Join("testdata", "terraform.tfstate"))
+	require.NoError(t, err)
+
+	assert.Equal(t, KindState, plan.Kind)
+	assert.Equal(t, []Resource{
+		{
+			Address:       "aws_cloudwatch_log_group.app",
+			TerraformType: "aws_cloudwatch_log_group",
+			Type:          "cloudwatchlogs",
+			Tags:          map[string]string{"Owner": "web", "Environment": "staging"},
+		},
+		{
+			Address:       "module.compute.aws_instance.worker[0]",
+			TerraformType: "aws_instance",
+			Type:          "ec2",
+			Tags:          map[string]string{"Owner": "batch"},
+		},
+		{
+			Address:       "module.compute.aws_instance.worker[1]",
+			TerraformType: "aws_instance",
+			Type:          "ec2",
+			Tags:          map[string]string{},
+		},
+	}, plan.Resources)
+	assert.Empty(t, plan.Skipped)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		kind   Kind
+		errMsg string
+	}{
+		{
+			name: "Empty Plan",
+			data: `{"format_version": "1.2", "planned_values": {"root_module": {}}}`,
+			kind: KindPlan,
+		},
+		{
+			name: "Empty State",
+			data: `{"format_version": "1.0"}`,
+			kind: KindState,
+		},
+		{
+			name: "Empty State File",
+			data: `{"version": 4, "resources": []}`,
+			kind: KindState,
+		},
+		{
+			name:   "Legacy State File",
+			data:   `{"version": 3, "modules": []}`,
+			errMsg: "unsupported state file version 3",
+		},
+		{
+			name:   "Not A Plan",
+			data:   `{"resources": []}`,
+			errMsg: "not a Terraform plan or state",
+		},
+		{
+			name:   "Invalid JSON",
+			data:   `resource "aws_s3_bucket" "logs" {}`,
+			errMsg: "invalid JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := Parse([]byte(tt.data))
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.kind, plan.Kind)
+			assert.Empty(t, plan.Resources)
+			assert.Empty(t, plan.Skipped)
+		})
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "plan.json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read Terraform plan")
+}
@@ -2,6 +2,7 @@
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 )
 
@@ -36,3 +37,22 @@ func ResolveAbsolutePath(configPath string) (string, error) {
 
 	return absPath, nil
 }
+
+// ExpandHome replaces a leading "~" in path with the home directory of the current user
+func ExpandHome(path string) (string, error) {
+	if path != "~" && !hasHomePrefix(path) {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory for %s: %w", path, err)
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
+
+// hasHomePrefix reports whether path starts with "~/"
+func hasHomePrefix(path string) bool {
+	return len(path) > 1 && path[0] == '~' && (path[1] == '/' || path[1] == filepath.Separator)
+}
@@ -80,3 +80,28 @@ func TestResolveAbsolutePath(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory: %v", err)
+	}
+
+	testCases := map[string]string{
+		"~":                   home,
+		"~/.aws-taggy/cache":  filepath.Join(home, ".aws-taggy/cache"),
+		"/tmp/state.db":       "/tmp/state.db",
+		"~other/state.db":     "~other/state.db",
+		"relative/~/state.db": "relative/~/state.db",
+	}
+
+	for input, expected := range testCases {
+		got, err := ExpandHome(input)
+		if err != nil {
+			t.Fatalf("ExpandHome(%q) failed: %v", input, err)
+		}
+		if got != expected {
+			t.Errorf("ExpandHome(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
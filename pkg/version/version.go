@@ -0,0 +1,30 @@
+// Package version holds the version of the aws-taggy build, set through ldflags at build
+// time, e.g. -X github.com/Excoriate/aws-taggy/pkg/version.Version=v1.2.0
+package version
+
+import "fmt"
+
+// Version, Commit and Date identify the build, set through ldflags by the release
+var (
+	Version = "dev"
+	Commit  = "none"
+	Date    = "unknown"
+)
+
+// Info is the version of the running aws-taggy build
+type Info struct {
+	Version string `json:"version" yaml:"version"`
+	Commit  string `json:"commit" yaml:"commit"`
+	Date    string `json:"date" yaml:"date"`
+}
+
+// Get returns the version of the running build
+func Get() Info {
+	return Info{Version: Version, Commit: Commit, Date: Date}
+}
+
+// String returns the version followed by the commit and the build date,
+// e.g. "v1.2.0 (commit 3b3eb6c, built 2025-01-31T10:00:00Z)"
+func (i Info) String() string {
+	return fmt.Sprintf("%s (commit %s, built %s)", i.Version, i.Commit, i.Date)
+}
@@ -0,0 +1,16 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	t.Cleanup(func() { Version, Commit, Date = "dev", "none", "unknown" })
+	Version, Commit, Date = "v1.2.0", "3b3eb6c", "2025-01-31T10:00:00Z"
+
+	info := Get()
+	assert.Equal(t, Info{Version: "v1.2.0", Commit: "3b3eb6c", Date: "2025-01-31T10:00:00Z"}, info)
+	assert.Equal(t, "v1.2.0 (commit 3b3eb6c, built 2025-01-31T10:00:00Z)", info.String())
+}